@@ -39,6 +39,8 @@ func init() {
 // Adds the list of known types to Scheme.
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
+		&AuditEvent{},
+		&AuditEventList{},
 		&Environment{},
 		&EnvironmentList{},
 		&EnvironmentRoleBinding{},
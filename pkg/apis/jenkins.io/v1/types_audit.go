@@ -0,0 +1,72 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+
+// AuditEvent records a change to the Environments, roles or settings of a team along with who made it and why.
+// AuditEvents are only ever created so they form an append only trail of the changes
+type AuditEvent struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec AuditEventSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuditEventList is a list of AuditEvent resources
+type AuditEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []AuditEvent `json:"items"`
+}
+
+// AuditEventSpec the details of an audited change
+type AuditEventSpec struct {
+	// Action the kind of change such as create, update, delete, promote or approve
+	Action AuditActionType `json:"action,omitempty" protobuf:"bytes,1,opt,name=action"`
+	// Kind the kind of resource which was changed such as Environment, EnvironmentRoleBinding or TeamSettings
+	Kind string `json:"kind,omitempty" protobuf:"bytes,2,opt,name=kind"`
+	// Name the name of the resource which was changed
+	Name string `json:"name,omitempty" protobuf:"bytes,3,opt,name=name"`
+	// Actor the user or service account which made the change
+	Actor string `json:"actor,omitempty" protobuf:"bytes,4,opt,name=actor"`
+	// Source the command or controller which made the change such as 'jx promote'
+	Source    string       `json:"source,omitempty" protobuf:"bytes,5,opt,name=source"`
+	Timestamp *metav1.Time `json:"timestamp,omitempty" protobuf:"bytes,6,opt,name=timestamp"`
+	Reason    string       `json:"reason,omitempty" protobuf:"bytes,7,opt,name=reason"`
+	// Before the YAML of the resource before the change
+	Before string `json:"before,omitempty" protobuf:"bytes,8,opt,name=before"`
+	// After the YAML of the resource after the change
+	After          string `json:"after,omitempty" protobuf:"bytes,9,opt,name=after"`
+	Environment    string `json:"environment,omitempty" protobuf:"bytes,10,opt,name=environment"`
+	Application    string `json:"application,omitempty" protobuf:"bytes,11,opt,name=application"`
+	Version        string `json:"version,omitempty" protobuf:"bytes,12,opt,name=version"`
+	PullRequestURL string `json:"pullRequestURL,omitempty" protobuf:"bytes,13,opt,name=pullRequestURL"`
+}
+
+// AuditActionType the kind of change recorded by an AuditEvent
+type AuditActionType string
+
+const (
+	// AuditActionCreate a resource was created
+	AuditActionCreate AuditActionType = "create"
+	// AuditActionUpdate a resource was modified
+	AuditActionUpdate AuditActionType = "update"
+	// AuditActionDelete a resource was deleted
+	AuditActionDelete AuditActionType = "delete"
+	// AuditActionPromote a version of an application was promoted to an Environment
+	AuditActionPromote AuditActionType = "promote"
+	// AuditActionApprove a promotion Pull Request was approved and merged
+	AuditActionApprove AuditActionType = "approve"
+)
@@ -132,24 +132,37 @@ type EnvironmentRepository struct {
 
 // TeamSettings the default settings for a team
 type TeamSettings struct {
-	UseGitOPs           bool                 `json:"useGitOps,omitempty" protobuf:"bytes,1,opt,name=useGitOps"`
-	AskOnCreate         bool                 `json:"askOnCreate,omitempty" protobuf:"bytes,2,opt,name=askOnCreate"`
-	BranchPatterns      string               `json:"branchPatterns,omitempty" protobuf:"bytes,3,opt,name=branchPatterns"`
-	ForkBranchPatterns  string               `json:"forkBranchPatterns,omitempty" protobuf:"bytes,4,opt,name=forkBranchPatterns"`
-	QuickstartLocations []QuickStartLocation `json:"quickstartLocations,omitempty" protobuf:"bytes,5,opt,name=quickstartLocations"`
-	BuildPackURL        string               `json:"buildPackUrl,omitempty" protobuf:"bytes,6,opt,name=buildPackUrl"`
-	BuildPackRef        string               `json:"buildPackRef,omitempty" protobuf:"bytes,7,opt,name=buildPackRef"`
-	HelmBinary          string               `json:"helmBinary,omitempty" protobuf:"bytes,8,opt,name=helmBinary"`
-	PostPreviewJobs     []batchv1.Job        `json:"postPreviewJobs,omitempty" protobuf:"bytes,9,opt,name=postPreviewJobs"`
-	PromotionEngine     PromotionEngineType  `json:"promotionEngine,omitempty" protobuf:"bytes,10,opt,name=promotionEngine"`
-	NoTiller            bool                 `json:"noTiller,omitempty" protobuf:"bytes,11,opt,name=noTiller"`
-	HelmTemplate        bool                 `json:"helmTemplate,omitempty" protobuf:"bytes,12,opt,name=helmTemplate"`
-	GitServer           string               `json:"gitServer,omitempty" protobuf:"bytes,13,opt,name=gitServer" command:"gitserver" commandUsage:"Default git server for new repositories"`
-	Organisation        string               `json:"organisation,omitempty" protobuf:"bytes,14,opt,name=organisation" command:"organisation" commandUsage:"Default git organisation for new repositories"`
-	PipelineUsername    string               `json:"pipelineUsername,omitempty" protobuf:"bytes,15,opt,name=pipelineUsername" command:"pipelineusername" commandUsage:"User used by pipeline. Is given write permission on new repositories."`
-	DockerRegistryOrg   string               `json:"dockerRegistryOrg,omitempty" protobuf:"bytes,16,opt,name=dockerRegistryOrg" command:"dockerregistryorg" commandUsage:"Docker registry organisation used for new projects in Jenkins X."`
-	GitPrivate          bool                 `json:"gitPrivate,omitempty" protobuf:"bytes,17,opt,name=gitPrivate" command:"gitprivate" commandUsage:"Are new repositories private by default"`
-	KubeProvider        string               `json:"kubeProvider,omitempty" protobuf:"bytes,18,opt,name=kubeProvider"`
+	UseGitOPs           bool                    `json:"useGitOps,omitempty" protobuf:"bytes,1,opt,name=useGitOps"`
+	AskOnCreate         bool                    `json:"askOnCreate,omitempty" protobuf:"bytes,2,opt,name=askOnCreate"`
+	BranchPatterns      string                  `json:"branchPatterns,omitempty" protobuf:"bytes,3,opt,name=branchPatterns"`
+	ForkBranchPatterns  string                  `json:"forkBranchPatterns,omitempty" protobuf:"bytes,4,opt,name=forkBranchPatterns"`
+	QuickstartLocations []QuickStartLocation    `json:"quickstartLocations,omitempty" protobuf:"bytes,5,opt,name=quickstartLocations"`
+	BuildPackURL        string                  `json:"buildPackUrl,omitempty" protobuf:"bytes,6,opt,name=buildPackUrl"`
+	BuildPackRef        string                  `json:"buildPackRef,omitempty" protobuf:"bytes,7,opt,name=buildPackRef"`
+	HelmBinary          string                  `json:"helmBinary,omitempty" protobuf:"bytes,8,opt,name=helmBinary"`
+	PostPreviewJobs     []batchv1.Job           `json:"postPreviewJobs,omitempty" protobuf:"bytes,9,opt,name=postPreviewJobs"`
+	PromotionEngine     PromotionEngineType     `json:"promotionEngine,omitempty" protobuf:"bytes,10,opt,name=promotionEngine"`
+	NoTiller            bool                    `json:"noTiller,omitempty" protobuf:"bytes,11,opt,name=noTiller"`
+	HelmTemplate        bool                    `json:"helmTemplate,omitempty" protobuf:"bytes,12,opt,name=helmTemplate"`
+	GitServer           string                  `json:"gitServer,omitempty" protobuf:"bytes,13,opt,name=gitServer" command:"gitserver" commandUsage:"Default git server for new repositories"`
+	Organisation        string                  `json:"organisation,omitempty" protobuf:"bytes,14,opt,name=organisation" command:"organisation" commandUsage:"Default git organisation for new repositories"`
+	PipelineUsername    string                  `json:"pipelineUsername,omitempty" protobuf:"bytes,15,opt,name=pipelineUsername" command:"pipelineusername" commandUsage:"User used by pipeline. Is given write permission on new repositories."`
+	DockerRegistryOrg   string                  `json:"dockerRegistryOrg,omitempty" protobuf:"bytes,16,opt,name=dockerRegistryOrg" command:"dockerregistryorg" commandUsage:"Docker registry organisation used for new projects in Jenkins X."`
+	GitPrivate          bool                    `json:"gitPrivate,omitempty" protobuf:"bytes,17,opt,name=gitPrivate" command:"gitprivate" commandUsage:"Are new repositories private by default"`
+	KubeProvider        string                  `json:"kubeProvider,omitempty" protobuf:"bytes,18,opt,name=kubeProvider"`
+	BranchProtection    *BranchProtectionPolicy `json:"branchProtection,omitempty" protobuf:"bytes,19,opt,name=branchProtection"`
+	HelmReleaseStorage  string                  `json:"helmReleaseStorage,omitempty" protobuf:"bytes,20,opt,name=helmReleaseStorage" command:"helmreleasestorage" commandUsage:"Where helm template mode stores the release history: configmaps or secrets"`
+}
+
+// BranchProtectionPolicy the protection applied to the default branch of the git repositories created or imported by the team
+type BranchProtectionPolicy struct {
+	Branch                   string   `json:"branch,omitempty" protobuf:"bytes,1,opt,name=branch"`
+	RequiredStatusContexts   []string `json:"requiredStatusContexts,omitempty" protobuf:"bytes,2,opt,name=requiredStatusContexts"`
+	RequiredApprovingReviews int      `json:"requiredApprovingReviews,omitempty" protobuf:"bytes,3,opt,name=requiredApprovingReviews"`
+	DismissStaleReviews      bool     `json:"dismissStaleReviews,omitempty" protobuf:"bytes,4,opt,name=dismissStaleReviews"`
+	EnforceAdmins            bool     `json:"enforceAdmins,omitempty" protobuf:"bytes,5,opt,name=enforceAdmins"`
+	AllowForcePushes         bool     `json:"allowForcePushes,omitempty" protobuf:"bytes,6,opt,name=allowForcePushes"`
+	RequireUpToDate          bool     `json:"requireUpToDate,omitempty" protobuf:"bytes,7,opt,name=requireUpToDate"`
 }
 
 // QuickStartLocation
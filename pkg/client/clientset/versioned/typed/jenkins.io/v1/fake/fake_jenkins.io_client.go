@@ -12,6 +12,10 @@ type FakeJenkinsV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeJenkinsV1) AuditEvents(namespace string) v1.AuditEventInterface {
+	return &FakeAuditEvents{c, namespace}
+}
+
 func (c *FakeJenkinsV1) Environments(namespace string) v1.EnvironmentInterface {
 	return &FakeEnvironments{c, namespace}
 }
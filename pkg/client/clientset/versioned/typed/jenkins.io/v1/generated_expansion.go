@@ -2,6 +2,8 @@
 
 package v1
 
+type AuditEventExpansion interface{}
+
 type EnvironmentExpansion interface{}
 
 type EnvironmentRoleBindingExpansion interface{}
@@ -11,6 +11,7 @@ import (
 
 type JenkinsV1Interface interface {
 	RESTClient() rest.Interface
+	AuditEventsGetter
 	EnvironmentsGetter
 	EnvironmentRoleBindingsGetter
 	ExtensionsGetter
@@ -27,6 +28,10 @@ type JenkinsV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *JenkinsV1Client) AuditEvents(namespace string) AuditEventInterface {
+	return newAuditEvents(c, namespace)
+}
+
 func (c *JenkinsV1Client) Environments(namespace string) EnvironmentInterface {
 	return newEnvironments(c, namespace)
 }
@@ -8,6 +8,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// AuditEvents returns a AuditEventInformer.
+	AuditEvents() AuditEventInformer
 	// Environments returns a EnvironmentInformer.
 	Environments() EnvironmentInformer
 	// EnvironmentRoleBindings returns a EnvironmentRoleBindingInformer.
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// AuditEvents returns a AuditEventInformer.
+func (v *version) AuditEvents() AuditEventInformer {
+	return &auditEventInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Environments returns a EnvironmentInformer.
 func (v *version) Environments() EnvironmentInformer {
 	return &environmentInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -2,6 +2,14 @@
 
 package v1
 
+// AuditEventListerExpansion allows custom methods to be added to
+// AuditEventLister.
+type AuditEventListerExpansion interface{}
+
+// AuditEventNamespaceListerExpansion allows custom methods to be added to
+// AuditEventNamespaceLister.
+type AuditEventNamespaceListerExpansion interface{}
+
 // EnvironmentListerExpansion allows custom methods to be added to
 // EnvironmentLister.
 type EnvironmentListerExpansion interface{}
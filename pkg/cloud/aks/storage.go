@@ -0,0 +1,70 @@
+package aks
+
+import (
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// CreateResourceGroup creates the resource group in the location if it does not already exist
+func CreateResourceGroup(resourceGroup string, location string) error {
+	output, err := runAz("group", "create", "--name", resourceGroup, "--location", location)
+	if err != nil {
+		log.Infof("Error creating resource group: %s, %s\n", output, err)
+		return err
+	}
+	return nil
+}
+
+// StorageAccountExists returns true if the storage account exists in the current subscription
+func StorageAccountExists(account string) (bool, error) {
+	output, err := runAz("storage", "account", "list", "--query", "[].name", "--output", "tsv")
+	if err != nil {
+		log.Infof("Error checking storage account exists: %s, %s\n", output, err)
+		return false, err
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == account {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateStorageAccount creates a storage account with a blob container in the resource group
+func CreateStorageAccount(account string, container string, resourceGroup string, location string) error {
+	output, err := runAz("storage", "account", "create", "--name", account, "--resource-group", resourceGroup, "--location", location, "--sku", "Standard_LRS", "--kind", "StorageV2")
+	if err != nil {
+		log.Infof("Error creating storage account: %s, %s\n", output, err)
+		return err
+	}
+	key, err := GetStorageAccountKey(account, resourceGroup)
+	if err != nil {
+		return err
+	}
+	output, err = runAz("storage", "container", "create", "--name", container, "--account-name", account, "--account-key", key)
+	if err != nil {
+		log.Infof("Error creating storage container: %s, %s\n", output, err)
+		return err
+	}
+	return nil
+}
+
+// GetStorageAccountKey returns the first access key of the storage account
+func GetStorageAccountKey(account string, resourceGroup string) (string, error) {
+	output, err := runAz("storage", "account", "keys", "list", "--account-name", account, "--resource-group", resourceGroup, "--query", "[0].value", "--output", "tsv")
+	if err != nil {
+		log.Infof("Error getting storage account key: %s, %s\n", output, err)
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+func runAz(args ...string) (string, error) {
+	cmd := util.Command{
+		Name: "az",
+		Args: args,
+	}
+	return cmd.RunWithoutRetry()
+}
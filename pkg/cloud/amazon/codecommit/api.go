@@ -0,0 +1,375 @@
+package codecommit
+
+const (
+	// RepositoryDoesNotExist the error code returned for missing repositories
+	RepositoryDoesNotExist = "RepositoryDoesNotExistException"
+	// ApprovalRuleTemplateNameAlreadyExists the error code returned when creating an existing approval rule template
+	ApprovalRuleTemplateNameAlreadyExists = "ApprovalRuleTemplateNameAlreadyExistsException"
+	// ApprovalRuleTemplateDoesNotExist the error code returned for missing approval rule templates
+	ApprovalRuleTemplateDoesNotExist = "ApprovalRuleTemplateDoesNotExistException"
+
+	// PullRequestStatusOpen the status of open pull requests
+	PullRequestStatusOpen = "OPEN"
+	// PullRequestStatusClosed the status of closed or merged pull requests
+	PullRequestStatusClosed = "CLOSED"
+
+	// ApprovalStateApprove approves a pull request
+	ApprovalStateApprove = "APPROVE"
+	// ApprovalStateRevoke revokes the approval of a pull request
+	ApprovalStateRevoke = "REVOKE"
+
+	// ApprovalRuleVersion the version of the approval rule content format
+	ApprovalRuleVersion = "2018-11-08"
+)
+
+// RepositoryMetadata describes a repository
+type RepositoryMetadata struct {
+	AccountID      string     `json:"accountId,omitempty"`
+	RepositoryID   string     `json:"repositoryId,omitempty"`
+	RepositoryName string     `json:"repositoryName,omitempty"`
+	Description    string     `json:"repositoryDescription,omitempty"`
+	DefaultBranch  string     `json:"defaultBranch,omitempty"`
+	CloneURLHTTP   string     `json:"cloneUrlHttp,omitempty"`
+	CloneURLSSH    string     `json:"cloneUrlSsh,omitempty"`
+	Arn            string     `json:"Arn,omitempty"`
+	CreationDate   *Timestamp `json:"creationDate,omitempty"`
+}
+
+// RepositoryNameID the name and ID of a repository returned when listing repositories
+type RepositoryNameID struct {
+	RepositoryName string `json:"repositoryName,omitempty"`
+	RepositoryID   string `json:"repositoryId,omitempty"`
+}
+
+// PullRequestTarget the source and destination of a pull request
+type PullRequestTarget struct {
+	RepositoryName       string         `json:"repositoryName,omitempty"`
+	SourceReference      string         `json:"sourceReference,omitempty"`
+	DestinationReference string         `json:"destinationReference,omitempty"`
+	SourceCommit         string         `json:"sourceCommit,omitempty"`
+	DestinationCommit    string         `json:"destinationCommit,omitempty"`
+	MergeBase            string         `json:"mergeBase,omitempty"`
+	MergeMetadata        *MergeMetadata `json:"mergeMetadata,omitempty"`
+}
+
+// MergeMetadata describes the merge of a pull request
+type MergeMetadata struct {
+	IsMerged      bool   `json:"isMerged,omitempty"`
+	MergedBy      string `json:"mergedBy,omitempty"`
+	MergeCommitID string `json:"mergeCommitId,omitempty"`
+	MergeOption   string `json:"mergeOption,omitempty"`
+}
+
+// ApprovalRule an approval rule of a pull request
+type ApprovalRule struct {
+	ApprovalRuleID      string `json:"approvalRuleId,omitempty"`
+	ApprovalRuleName    string `json:"approvalRuleName,omitempty"`
+	ApprovalRuleContent string `json:"approvalRuleContent,omitempty"`
+}
+
+// PullRequest a CodeCommit pull request
+type PullRequest struct {
+	PullRequestID      string              `json:"pullRequestId,omitempty"`
+	Title              string              `json:"title,omitempty"`
+	Description        string              `json:"description,omitempty"`
+	PullRequestStatus  string              `json:"pullRequestStatus,omitempty"`
+	AuthorArn          string              `json:"authorArn,omitempty"`
+	CreationDate       *Timestamp          `json:"creationDate,omitempty"`
+	LastActivityDate   *Timestamp          `json:"lastActivityDate,omitempty"`
+	RevisionID         string              `json:"revisionId,omitempty"`
+	PullRequestTargets []PullRequestTarget `json:"pullRequestTargets,omitempty"`
+	ApprovalRules      []ApprovalRule      `json:"approvalRules,omitempty"`
+}
+
+// Evaluation the result of evaluating the approval rules of a pull request
+type Evaluation struct {
+	Approved                  bool     `json:"approved"`
+	Overridden                bool     `json:"overridden"`
+	ApprovalRulesSatisfied    []string `json:"approvalRulesSatisfied,omitempty"`
+	ApprovalRulesNotSatisfied []string `json:"approvalRulesNotSatisfied,omitempty"`
+}
+
+// UserInfo the author or committer of a commit
+type UserInfo struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Date  string `json:"date,omitempty"`
+}
+
+// Commit a CodeCommit commit
+type Commit struct {
+	CommitID  string    `json:"commitId,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Parents   []string  `json:"parents,omitempty"`
+	Author    *UserInfo `json:"author,omitempty"`
+	Committer *UserInfo `json:"committer,omitempty"`
+}
+
+// RepositoryTrigger publishes the events of a repository to an SNS topic or Lambda function
+type RepositoryTrigger struct {
+	Name           string   `json:"name"`
+	DestinationArn string   `json:"destinationArn"`
+	CustomData     string   `json:"customData,omitempty"`
+	Branches       []string `json:"branches"`
+	Events         []string `json:"events"`
+}
+
+// ApprovalRuleTemplate an approval rule template which adds approval rules to the pull requests of the repositories
+// it is associated with
+type ApprovalRuleTemplate struct {
+	ApprovalRuleTemplateName    string `json:"approvalRuleTemplateName,omitempty"`
+	ApprovalRuleTemplateContent string `json:"approvalRuleTemplateContent,omitempty"`
+	RuleContentSha256           string `json:"ruleContentSha256,omitempty"`
+}
+
+// ListRepositories returns the names of all the repositories
+func (c *Client) ListRepositories() ([]RepositoryNameID, error) {
+	answer := []RepositoryNameID{}
+	nextToken := ""
+	for {
+		input := map[string]string{}
+		if nextToken != "" {
+			input["nextToken"] = nextToken
+		}
+		output := struct {
+			Repositories []RepositoryNameID `json:"repositories"`
+			NextToken    string             `json:"nextToken"`
+		}{}
+		err := c.call("ListRepositories", input, &output)
+		if err != nil {
+			return nil, err
+		}
+		answer = append(answer, output.Repositories...)
+		nextToken = output.NextToken
+		if nextToken == "" {
+			return answer, nil
+		}
+	}
+}
+
+// GetRepository returns the repository with the name
+func (c *Client) GetRepository(name string) (*RepositoryMetadata, error) {
+	return c.repositoryCall("GetRepository", map[string]string{"repositoryName": name})
+}
+
+// CreateRepository creates a repository
+func (c *Client) CreateRepository(name string, description string) (*RepositoryMetadata, error) {
+	input := map[string]string{"repositoryName": name}
+	if description != "" {
+		input["repositoryDescription"] = description
+	}
+	return c.repositoryCall("CreateRepository", input)
+}
+
+// DeleteRepository deletes the repository
+func (c *Client) DeleteRepository(name string) error {
+	return c.call("DeleteRepository", map[string]string{"repositoryName": name}, nil)
+}
+
+// UpdateRepositoryName renames the repository
+func (c *Client) UpdateRepositoryName(oldName string, newName string) error {
+	return c.call("UpdateRepositoryName", map[string]string{"oldName": oldName, "newName": newName}, nil)
+}
+
+func (c *Client) repositoryCall(operation string, input interface{}) (*RepositoryMetadata, error) {
+	output := struct {
+		RepositoryMetadata *RepositoryMetadata `json:"repositoryMetadata"`
+	}{}
+	err := c.call(operation, input, &output)
+	if err != nil {
+		return nil, err
+	}
+	if output.RepositoryMetadata == nil {
+		output.RepositoryMetadata = &RepositoryMetadata{}
+	}
+	return output.RepositoryMetadata, nil
+}
+
+// CreatePullRequest creates a pull request merging the source reference into the destination reference
+func (c *Client) CreatePullRequest(title string, description string, target PullRequestTarget) (*PullRequest, error) {
+	input := map[string]interface{}{
+		"title":   title,
+		"targets": []PullRequestTarget{target},
+	}
+	if description != "" {
+		input["description"] = description
+	}
+	return c.pullRequestCall("CreatePullRequest", input)
+}
+
+// GetPullRequest returns the pull request with the ID
+func (c *Client) GetPullRequest(id string) (*PullRequest, error) {
+	return c.pullRequestCall("GetPullRequest", map[string]string{"pullRequestId": id})
+}
+
+func (c *Client) pullRequestCall(operation string, input interface{}) (*PullRequest, error) {
+	output := struct {
+		PullRequest *PullRequest `json:"pullRequest"`
+	}{}
+	err := c.call(operation, input, &output)
+	if err != nil {
+		return nil, err
+	}
+	if output.PullRequest == nil {
+		output.PullRequest = &PullRequest{}
+	}
+	return output.PullRequest, nil
+}
+
+// MergePullRequestByThreeWay merges the pull request with a merge commit failing if the source branch has moved
+// on from sourceCommitID
+func (c *Client) MergePullRequestByThreeWay(id string, repository string, sourceCommitID string, message string) (*PullRequest, error) {
+	input := map[string]string{
+		"pullRequestId":  id,
+		"repositoryName": repository,
+	}
+	if sourceCommitID != "" {
+		input["sourceCommitId"] = sourceCommitID
+	}
+	if message != "" {
+		input["commitMessage"] = message
+	}
+	return c.pullRequestCall("MergePullRequestByThreeWay", input)
+}
+
+// PostCommentForPullRequest adds a comment to the pull request
+func (c *Client) PostCommentForPullRequest(id string, repository string, beforeCommitID string, afterCommitID string, content string) error {
+	input := map[string]string{
+		"pullRequestId":  id,
+		"repositoryName": repository,
+		"beforeCommitId": beforeCommitID,
+		"afterCommitId":  afterCommitID,
+		"content":        content,
+	}
+	return c.call("PostCommentForPullRequest", input, nil)
+}
+
+// CreatePullRequestApprovalRule adds an approval rule to the pull request
+func (c *Client) CreatePullRequestApprovalRule(id string, name string, content string) error {
+	input := map[string]string{
+		"pullRequestId":       id,
+		"approvalRuleName":    name,
+		"approvalRuleContent": content,
+	}
+	return c.call("CreatePullRequestApprovalRule", input, nil)
+}
+
+// UpdatePullRequestApprovalState approves or revokes the approval of the revision of the pull request
+func (c *Client) UpdatePullRequestApprovalState(id string, revisionID string, state string) error {
+	input := map[string]string{
+		"pullRequestId": id,
+		"revisionId":    revisionID,
+		"approvalState": state,
+	}
+	return c.call("UpdatePullRequestApprovalState", input, nil)
+}
+
+// EvaluatePullRequestApprovalRules returns whether the revision of the pull request satisfies its approval rules
+func (c *Client) EvaluatePullRequestApprovalRules(id string, revisionID string) (*Evaluation, error) {
+	input := map[string]string{
+		"pullRequestId": id,
+		"revisionId":    revisionID,
+	}
+	output := struct {
+		Evaluation *Evaluation `json:"evaluation"`
+	}{}
+	err := c.call("EvaluatePullRequestApprovalRules", input, &output)
+	if err != nil {
+		return nil, err
+	}
+	if output.Evaluation == nil {
+		output.Evaluation = &Evaluation{}
+	}
+	return output.Evaluation, nil
+}
+
+// GetCommit returns the commit of the repository
+func (c *Client) GetCommit(repository string, commitID string) (*Commit, error) {
+	input := map[string]string{
+		"repositoryName": repository,
+		"commitId":       commitID,
+	}
+	output := struct {
+		Commit *Commit `json:"commit"`
+	}{}
+	err := c.call("GetCommit", input, &output)
+	if err != nil {
+		return nil, err
+	}
+	if output.Commit == nil {
+		output.Commit = &Commit{CommitID: commitID}
+	}
+	return output.Commit, nil
+}
+
+// GetRepositoryTriggers returns the triggers of the repository
+func (c *Client) GetRepositoryTriggers(repository string) ([]RepositoryTrigger, error) {
+	output := struct {
+		Triggers []RepositoryTrigger `json:"triggers"`
+	}{}
+	err := c.call("GetRepositoryTriggers", map[string]string{"repositoryName": repository}, &output)
+	return output.Triggers, err
+}
+
+// PutRepositoryTriggers replaces all the triggers of the repository
+func (c *Client) PutRepositoryTriggers(repository string, triggers []RepositoryTrigger) error {
+	if triggers == nil {
+		triggers = []RepositoryTrigger{}
+	}
+	input := map[string]interface{}{
+		"repositoryName": repository,
+		"triggers":       triggers,
+	}
+	return c.call("PutRepositoryTriggers", input, nil)
+}
+
+// GetApprovalRuleTemplate returns the approval rule template with the name
+func (c *Client) GetApprovalRuleTemplate(name string) (*ApprovalRuleTemplate, error) {
+	return c.approvalRuleTemplateCall("GetApprovalRuleTemplate", map[string]string{"approvalRuleTemplateName": name})
+}
+
+// CreateApprovalRuleTemplate creates an approval rule template
+func (c *Client) CreateApprovalRuleTemplate(name string, content string, description string) (*ApprovalRuleTemplate, error) {
+	input := map[string]string{
+		"approvalRuleTemplateName":    name,
+		"approvalRuleTemplateContent": content,
+	}
+	if description != "" {
+		input["approvalRuleTemplateDescription"] = description
+	}
+	return c.approvalRuleTemplateCall("CreateApprovalRuleTemplate", input)
+}
+
+// UpdateApprovalRuleTemplateContent replaces the content of the approval rule template
+func (c *Client) UpdateApprovalRuleTemplateContent(name string, content string, existingSha256 string) (*ApprovalRuleTemplate, error) {
+	input := map[string]string{
+		"approvalRuleTemplateName":  name,
+		"newRuleContent":            content,
+		"existingRuleContentSha256": existingSha256,
+	}
+	return c.approvalRuleTemplateCall("UpdateApprovalRuleTemplateContent", input)
+}
+
+func (c *Client) approvalRuleTemplateCall(operation string, input interface{}) (*ApprovalRuleTemplate, error) {
+	output := struct {
+		ApprovalRuleTemplate *ApprovalRuleTemplate `json:"approvalRuleTemplate"`
+	}{}
+	err := c.call(operation, input, &output)
+	if err != nil {
+		return nil, err
+	}
+	if output.ApprovalRuleTemplate == nil {
+		output.ApprovalRuleTemplate = &ApprovalRuleTemplate{}
+	}
+	return output.ApprovalRuleTemplate, nil
+}
+
+// AssociateApprovalRuleTemplateWithRepository applies the approval rule template to the new pull requests of the
+// repository
+func (c *Client) AssociateApprovalRuleTemplateWithRepository(name string, repository string) error {
+	input := map[string]string{
+		"approvalRuleTemplateName": name,
+		"repositoryName":           repository,
+	}
+	return c.call("AssociateApprovalRuleTemplateWithRepository", input, nil)
+}
@@ -0,0 +1,187 @@
+// Package codecommit is a client of the AWS CodeCommit API and of the SNS topics CodeCommit repository triggers
+// publish to.
+//
+// Only the operations the CodeCommit git provider needs are implemented, so the requests and responses are plain
+// structs encoded with encoding/json and encoding/xml rather than generated SDK shapes. The clients still use the
+// AWS SDK for credentials, endpoints, signing and retries so they work with any session from amazon.NewAwsSession.
+package codecommit
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/private/protocol/jsonrpc"
+	"github.com/aws/aws-sdk-go/private/protocol/query"
+)
+
+const (
+	// ServiceName the name of the CodeCommit service used to resolve its endpoint and sign requests
+	ServiceName = "codecommit"
+	// NotificationsServiceName the name of the SNS service used to resolve its endpoint and sign requests
+	NotificationsServiceName = "sns"
+
+	codeCommitTargetPrefix = "CodeCommit_20150413"
+	snsAPIVersion          = "2010-03-31"
+)
+
+// Client invokes the AWS CodeCommit API
+type Client struct {
+	*client.Client
+}
+
+// Notifications invokes the AWS SNS API
+type Notifications struct {
+	*client.Client
+}
+
+// Timestamp is a time which CodeCommit encodes as seconds since the epoch
+type Timestamp struct {
+	time.Time
+}
+
+// UnmarshalJSON parses the seconds since the epoch
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	err := json.Unmarshal(data, &seconds)
+	if err != nil {
+		return err
+	}
+	t.Time = time.Unix(0, int64(seconds*float64(time.Second))).UTC()
+	return nil
+}
+
+// New creates a CodeCommit client from a session such as the one returned by amazon.NewAwsSession
+func New(p client.ConfigProvider, cfgs ...*aws.Config) *Client {
+	c := p.ClientConfig(ServiceName, cfgs...)
+	svc := &Client{
+		Client: client.New(
+			*c.Config,
+			metadata.ClientInfo{
+				ServiceName:   ServiceName,
+				SigningName:   c.SigningName,
+				SigningRegion: c.SigningRegion,
+				Endpoint:      c.Endpoint,
+				APIVersion:    "2015-04-13",
+				JSONVersion:   "1.1",
+				TargetPrefix:  codeCommitTargetPrefix,
+			},
+			c.Handlers,
+		),
+	}
+	svc.Handlers.Sign.PushBackNamed(v4.SignRequestHandler)
+	svc.Handlers.Build.PushBack(buildJSON)
+	svc.Handlers.Unmarshal.PushBack(unmarshalJSON)
+	svc.Handlers.UnmarshalMeta.PushBackNamed(jsonrpc.UnmarshalMetaHandler)
+	svc.Handlers.UnmarshalError.PushBackNamed(jsonrpc.UnmarshalErrorHandler)
+	return svc
+}
+
+// NewNotifications creates an SNS client from a session such as the one returned by amazon.NewAwsSession
+func NewNotifications(p client.ConfigProvider, cfgs ...*aws.Config) *Notifications {
+	c := p.ClientConfig(NotificationsServiceName, cfgs...)
+	svc := &Notifications{
+		Client: client.New(
+			*c.Config,
+			metadata.ClientInfo{
+				ServiceName:   NotificationsServiceName,
+				SigningName:   c.SigningName,
+				SigningRegion: c.SigningRegion,
+				Endpoint:      c.Endpoint,
+				APIVersion:    snsAPIVersion,
+			},
+			c.Handlers,
+		),
+	}
+	svc.Handlers.Sign.PushBackNamed(v4.SignRequestHandler)
+	svc.Handlers.Build.PushBack(buildQuery)
+	svc.Handlers.Unmarshal.PushBack(unmarshalXML)
+	svc.Handlers.UnmarshalMeta.PushBackNamed(query.UnmarshalMetaHandler)
+	svc.Handlers.UnmarshalError.PushBackNamed(query.UnmarshalErrorHandler)
+	return svc
+}
+
+// call invokes the CodeCommit operation decoding the response into output if it is not nil
+func (c *Client) call(operation string, input interface{}, output interface{}) error {
+	op := &request.Operation{
+		Name:       operation,
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+	// the SDK expects the parameters to be a pointer
+	return c.NewRequest(op, &input, output).Send()
+}
+
+// call invokes the SNS action decoding the XML response into output if it is not nil
+func (n *Notifications) call(action string, params url.Values, output interface{}) error {
+	op := &request.Operation{
+		Name:       action,
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+	return n.NewRequest(op, &params, output).Send()
+}
+
+func buildJSON(r *request.Request) {
+	body, err := json.Marshal(r.Params)
+	if err != nil {
+		r.Error = awserr.New("SerializationError", "failed encoding JSON RPC request", err)
+		return
+	}
+	r.SetBufferBody(body)
+	r.HTTPRequest.Header.Set("X-Amz-Target", r.ClientInfo.TargetPrefix+"."+r.Operation.Name)
+	r.HTTPRequest.Header.Set("Content-Type", "application/x-amz-json-"+r.ClientInfo.JSONVersion)
+}
+
+func unmarshalJSON(r *request.Request) {
+	defer r.HTTPResponse.Body.Close()
+	if r.Data == nil {
+		return
+	}
+	err := json.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+	if err != nil && err != io.EOF {
+		r.Error = awserr.New("SerializationError", "failed decoding JSON RPC response", err)
+	}
+}
+
+func buildQuery(r *request.Request) {
+	params := url.Values{}
+	if values, ok := r.Params.(*url.Values); ok {
+		for k, v := range *values {
+			params[k] = v
+		}
+	}
+	params.Set("Action", r.Operation.Name)
+	params.Set("Version", r.ClientInfo.APIVersion)
+	r.HTTPRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	r.SetBufferBody([]byte(params.Encode()))
+}
+
+func unmarshalXML(r *request.Request) {
+	defer r.HTTPResponse.Body.Close()
+	if r.Data == nil {
+		return
+	}
+	err := xml.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+	if err != nil && err != io.EOF {
+		r.Error = awserr.New("SerializationError", "failed decoding query response", err)
+	}
+}
+
+// IsErrorCode returns true if the error was returned by AWS with the given code such as
+// RepositoryDoesNotExistException
+func IsErrorCode(err error, code string) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code() == code || strings.HasSuffix(awsErr.Code(), "#"+code)
+	}
+	return false
+}
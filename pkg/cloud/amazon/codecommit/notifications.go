@@ -0,0 +1,75 @@
+package codecommit
+
+import (
+	"net/url"
+)
+
+// PendingConfirmation the ARN of subscriptions which the endpoint has not confirmed yet
+const PendingConfirmation = "PendingConfirmation"
+
+// Subscription an SNS subscription delivering the messages of a topic to an endpoint
+type Subscription struct {
+	SubscriptionArn string `xml:"SubscriptionArn"`
+	TopicArn        string `xml:"TopicArn"`
+	Protocol        string `xml:"Protocol"`
+	Endpoint        string `xml:"Endpoint"`
+}
+
+// CreateTopic creates the topic, or returns the ARN of the topic if it already exists
+func (n *Notifications) CreateTopic(name string) (string, error) {
+	params := url.Values{}
+	params.Set("Name", name)
+	output := struct {
+		TopicArn string `xml:"CreateTopicResult>TopicArn"`
+	}{}
+	err := n.call("CreateTopic", params, &output)
+	return output.TopicArn, err
+}
+
+// Subscribe delivers the messages of the topic to the endpoint. HTTP and HTTPS endpoints have to confirm the
+// subscription before messages are delivered to them
+func (n *Notifications) Subscribe(topicArn string, protocol string, endpoint string) (string, error) {
+	params := url.Values{}
+	params.Set("TopicArn", topicArn)
+	params.Set("Protocol", protocol)
+	params.Set("Endpoint", endpoint)
+	params.Set("ReturnSubscriptionArn", "true")
+	output := struct {
+		SubscriptionArn string `xml:"SubscribeResult>SubscriptionArn"`
+	}{}
+	err := n.call("Subscribe", params, &output)
+	return output.SubscriptionArn, err
+}
+
+// ListSubscriptionsByTopic returns the subscriptions of the topic
+func (n *Notifications) ListSubscriptionsByTopic(topicArn string) ([]Subscription, error) {
+	answer := []Subscription{}
+	nextToken := ""
+	for {
+		params := url.Values{}
+		params.Set("TopicArn", topicArn)
+		if nextToken != "" {
+			params.Set("NextToken", nextToken)
+		}
+		output := struct {
+			Subscriptions []Subscription `xml:"ListSubscriptionsByTopicResult>Subscriptions>member"`
+			NextToken     string         `xml:"ListSubscriptionsByTopicResult>NextToken"`
+		}{}
+		err := n.call("ListSubscriptionsByTopic", params, &output)
+		if err != nil {
+			return nil, err
+		}
+		answer = append(answer, output.Subscriptions...)
+		nextToken = output.NextToken
+		if nextToken == "" {
+			return answer, nil
+		}
+	}
+}
+
+// Unsubscribe deletes the subscription
+func (n *Notifications) Unsubscribe(subscriptionArn string) error {
+	params := url.Values{}
+	params.Set("SubscriptionArn", subscriptionArn)
+	return n.call("Unsubscribe", params, nil)
+}
@@ -0,0 +1,52 @@
+package amazon
+
+import (
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// DynamoDBTableExists returns true if the DynamoDB table exists in the region
+func DynamoDBTableExists(tableName string, profile string, region string) (bool, error) {
+	output, err := runAws(profile, region, "dynamodb", "list-tables", "--query", "TableNames", "--output", "text")
+	if err != nil {
+		log.Infof("Error checking DynamoDB table exists: %s, %s\n", output, err)
+		return false, err
+	}
+	for _, name := range strings.Fields(output) {
+		if name == tableName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateTerraformLockTable creates a DynamoDB table with the LockID key used by Terraform to lock the state stored
+// in S3
+func CreateTerraformLockTable(tableName string, profile string, region string) error {
+	output, err := runAws(profile, region, "dynamodb", "create-table",
+		"--table-name", tableName,
+		"--attribute-definitions", "AttributeName=LockID,AttributeType=S",
+		"--key-schema", "AttributeName=LockID,KeyType=HASH",
+		"--billing-mode", "PAY_PER_REQUEST")
+	if err != nil {
+		log.Infof("Error creating DynamoDB table: %s, %s\n", output, err)
+		return err
+	}
+	return nil
+}
+
+func runAws(profile string, region string, args ...string) (string, error) {
+	if profile != "" {
+		args = append(args, "--profile", profile)
+	}
+	if region != "" {
+		args = append(args, "--region", region)
+	}
+	cmd := util.Command{
+		Name: "aws",
+		Args: args,
+	}
+	return cmd.RunWithoutRetry()
+}
@@ -1,10 +1,36 @@
 package amazon
 
 import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
+var s3BucketNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// ValidateS3BucketName returns an error if the name does not follow the naming rules of S3 buckets which are 3 to 63
+// lower case letters, numbers, dots and hyphens starting and ending with a letter or number
+func ValidateS3BucketName(bucketName string) error {
+	if !s3BucketNameRegex.MatchString(bucketName) {
+		return fmt.Errorf("invalid S3 bucket name %s: it must be 3 to 63 lower case letters, numbers, dots and hyphens starting and ending with a letter or number", bucketName)
+	}
+	if strings.Contains(bucketName, "..") || strings.Contains(bucketName, ".-") || strings.Contains(bucketName, "-.") {
+		return fmt.Errorf("invalid S3 bucket name %s: a dot must not be next to another dot or a hyphen", bucketName)
+	}
+	if net.ParseIP(bucketName) != nil {
+		return fmt.Errorf("invalid S3 bucket name %s: it must not be an IP address", bucketName)
+	}
+	if strings.HasPrefix(bucketName, "xn--") {
+		return fmt.Errorf("invalid S3 bucket name %s: it must not start with xn--", bucketName)
+	}
+	return nil
+}
+
 // CreateS3Bucket creates a new S3 bucket in the default region with the given bucket name
 // returning the location string
 func CreateS3Bucket(bucketName string, profile string, region string) (string, error) {
@@ -27,3 +53,22 @@ func CreateS3Bucket(bucketName string, profile string, region string) (string, e
 	}
 	return location, err
 }
+
+// S3BucketExists returns true if the S3 bucket with the given name exists and can be accessed
+func S3BucketExists(bucketName string, profile string, region string) (bool, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return false, err
+	}
+	svc := s3.New(sess)
+	_, err = svc.HeadBucket(&s3.HeadBucketInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && (aerr.Code() == s3.ErrCodeNoSuchBucket || aerr.Code() == "NotFound") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
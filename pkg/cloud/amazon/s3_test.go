@@ -0,0 +1,20 @@
+package amazon_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateS3BucketName(t *testing.T) {
+	t.Parallel()
+	for _, name := range []string{"123456789012-acme-terraform-state", "my.bucket", "abc"} {
+		assert.NoError(t, amazon.ValidateS3BucketName(name), name)
+	}
+	for _, name := range []string{"", "ab", strings.Repeat("a", 64), "123456789012-Acme-terraform-state", "acme_corp-terraform-state",
+		"-acme", "acme-", "my..bucket", "my.-bucket", "192.168.1.1", "xn--acme"} {
+		assert.Error(t, amazon.ValidateS3BucketName(name), name)
+	}
+}
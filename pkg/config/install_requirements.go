@@ -0,0 +1,224 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/util"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// RequirementsConfigFileName is the default name of the file describing an installation of Jenkins X
+	RequirementsConfigFileName = "jx-requirements.yml"
+
+	// HelmModeTiller uses helm with a tiller running in the cluster
+	HelmModeTiller = "tiller"
+	// HelmModeLocalTiller uses helm with a tiller running locally rather than in the cluster
+	HelmModeLocalTiller = "local-tiller"
+	// HelmModeTemplate uses 'helm template' and 'kubectl apply' so that no tiller is used
+	HelmModeTemplate = "template"
+	// HelmModeHelm3 uses helm 3 which does not use tiller
+	HelmModeHelm3 = "helm3"
+
+	// WebhookProw uses Prow to handle webhooks and promotion
+	WebhookProw = "prow"
+	// WebhookJenkins uses Jenkins to handle webhooks and promotion
+	WebhookJenkins = "jenkins"
+)
+
+var (
+	// HelmModeValues the supported helm modes
+	HelmModeValues = []string{HelmModeTiller, HelmModeLocalTiller, HelmModeTemplate, HelmModeHelm3}
+
+	// WebhookValues the supported webhook engines
+	WebhookValues = []string{WebhookProw, WebhookJenkins}
+
+	// ExposerValues the supported exposecontroller strategies
+	ExposerValues = []string{"Ingress", "Route", "NodePort", "LoadBalancer"}
+
+	// PathModeValues the supported exposecontroller path modes
+	PathModeValues = []string{"", "path"}
+
+	environmentNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+)
+
+// InstallRequirements describes an installation of Jenkins X so that it can be reproduced without any flags or
+// prompts
+type InstallRequirements struct {
+	Cluster   ClusterRequirements   `yaml:"cluster"`
+	GitServer GitServerRequirements `yaml:"gitServer,omitempty"`
+	// Environments are the permanent environments to create. If omitted the default staging and production
+	// environments are created. An empty list creates no environments
+	Environments []EnvironmentRequirements `yaml:"environments"`
+	// EnvironmentPrefix is used to name the environment git repositories 'environment-$prefix-$envName'
+	EnvironmentPrefix string              `yaml:"environmentPrefix,omitempty"`
+	Ingress           IngressRequirements `yaml:"ingress,omitempty"`
+	// Helm is how helm charts are installed: tiller, local-tiller, template or helm3. Defaults to tiller
+	Helm string `yaml:"helm,omitempty"`
+	// Webhook is the engine handling webhooks and promotion: prow or jenkins. Defaults to jenkins
+	Webhook string `yaml:"webhook,omitempty"`
+	// Addons are the names of the addons to install such as 'gitea' or 'prometheus'
+	Addons []string `yaml:"addons,omitempty"`
+	// Version pins the version of the platform. If blank the version of the cloud environments is used
+	Version string `yaml:"version,omitempty"`
+}
+
+// ClusterRequirements describes the Kubernetes cluster Jenkins X is installed into
+type ClusterRequirements struct {
+	// Provider is the Kubernetes provider such as gke, eks or minikube
+	Provider string `yaml:"provider"`
+	// Namespace is the namespace the platform is installed into. Defaults to jx
+	Namespace string `yaml:"namespace,omitempty"`
+	// DockerRegistry is the host or host:port of the Docker registry. Defaults to the provider's registry
+	DockerRegistry string `yaml:"dockerRegistry,omitempty"`
+}
+
+// GitServerRequirements describes the git server used for the environment repositories and pipelines. The API token
+// is never stored in the file; it is taken from $JX_GIT_TOKEN, --git-api-token or the local git auth configuration
+type GitServerRequirements struct {
+	URL      string `yaml:"url,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	// Owner is the user or organisation the environment repositories are created in
+	Owner   string `yaml:"owner,omitempty"`
+	Private bool   `yaml:"private,omitempty"`
+}
+
+// EnvironmentRequirements describes a permanent environment
+type EnvironmentRequirements struct {
+	Name  string `yaml:"name"`
+	Label string `yaml:"label,omitempty"`
+	// PromotionStrategy is one of Auto, Manual or Never. Defaults to Auto
+	PromotionStrategy string `yaml:"promotionStrategy,omitempty"`
+}
+
+// IngressRequirements describes how services are exposed
+type IngressRequirements struct {
+	Domain string `yaml:"domain,omitempty"`
+	// TLS enables automatic TLS with https ingress rules rather than http
+	TLS bool `yaml:"tls,omitempty"`
+	// Exposer is the exposecontroller strategy such as Ingress or Route. Defaults to Ingress
+	Exposer string `yaml:"exposer,omitempty"`
+	// PathMode is blank to expose services as sub domains or 'path' to use paths within the domain
+	PathMode string `yaml:"pathMode,omitempty"`
+}
+
+// DefaultEnvironments returns the staging and production environments created when none are specified
+func DefaultEnvironments() []EnvironmentRequirements {
+	return []EnvironmentRequirements{
+		{
+			Name:              "staging",
+			Label:             "Staging",
+			PromotionStrategy: string(v1.PromotionStrategyTypeAutomatic),
+		},
+		{
+			Name:              "production",
+			Label:             "Production",
+			PromotionStrategy: string(v1.PromotionStrategyTypeManual),
+		},
+	}
+}
+
+// LoadInstallRequirements loads and validates the requirements from the given file
+func LoadInstallRequirements(fileName string) (*InstallRequirements, error) {
+	exists, err := util.FileExists(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("requirements file %s does not exist", fileName)
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load file %s due to %s", fileName, err)
+	}
+	requirements := &InstallRequirements{}
+	err = yaml.UnmarshalStrict(data, requirements)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal YAML file %s due to %s", fileName, err)
+	}
+	err = requirements.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid requirements file %s: %s", fileName, err)
+	}
+	return requirements, nil
+}
+
+// SaveConfig saves the requirements to the given file
+func (r *InstallRequirements) SaveConfig(fileName string) error {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, data, util.DefaultWritePermissions)
+}
+
+// Validate returns an error describing every invalid value of the requirements
+func (r *InstallRequirements) Validate() error {
+	problems := []string{}
+	if r.Cluster.Provider == "" {
+		problems = append(problems, "cluster.provider is required")
+	}
+	if r.Cluster.Namespace != "" && !environmentNameRegex.MatchString(r.Cluster.Namespace) {
+		problems = append(problems, fmt.Sprintf("cluster.namespace %s is not a valid Kubernetes namespace", r.Cluster.Namespace))
+	}
+	if r.GitServer.URL != "" {
+		u, err := url.Parse(r.GitServer.URL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			problems = append(problems, fmt.Sprintf("gitServer.url %s is not an http or https URL", r.GitServer.URL))
+		}
+	}
+	names := map[string]bool{}
+	for i, env := range r.Environments {
+		switch {
+		case env.Name == "":
+			problems = append(problems, fmt.Sprintf("environments[%d].name is required", i))
+		case env.Name == "dev":
+			problems = append(problems, fmt.Sprintf("environments[%d].name dev is reserved for the development environment", i))
+		case !environmentNameRegex.MatchString(env.Name):
+			problems = append(problems, fmt.Sprintf("environments[%d].name %s must be lower case letters, digits and dashes", i, env.Name))
+		case names[env.Name]:
+			problems = append(problems, fmt.Sprintf("environments[%d].name %s is used by more than one environment", i, env.Name))
+		}
+		names[env.Name] = true
+		if env.PromotionStrategy != "" && util.StringArrayIndex(v1.PromotionStrategyTypeValues, env.PromotionStrategy) < 0 {
+			problems = append(problems, fmt.Sprintf("environments[%d].promotionStrategy %s must be one of %s", i, env.PromotionStrategy, strings.Join(v1.PromotionStrategyTypeValues, ", ")))
+		}
+	}
+	if r.EnvironmentPrefix != "" && !environmentNameRegex.MatchString(r.EnvironmentPrefix) {
+		problems = append(problems, fmt.Sprintf("environmentPrefix %s must be lower case letters, digits and dashes", r.EnvironmentPrefix))
+	}
+	if r.Ingress.Domain != "" && strings.ContainsAny(r.Ingress.Domain, "/: ") {
+		problems = append(problems, fmt.Sprintf("ingress.domain %s must be a host name without a scheme or port", r.Ingress.Domain))
+	}
+	if r.Ingress.TLS && r.Ingress.Domain == "" {
+		problems = append(problems, "ingress.domain is required when ingress.tls is enabled")
+	}
+	if r.Ingress.Exposer != "" && util.StringArrayIndex(ExposerValues, r.Ingress.Exposer) < 0 {
+		problems = append(problems, fmt.Sprintf("ingress.exposer %s must be one of %s", r.Ingress.Exposer, strings.Join(ExposerValues, ", ")))
+	}
+	if util.StringArrayIndex(PathModeValues, r.Ingress.PathMode) < 0 {
+		problems = append(problems, fmt.Sprintf("ingress.pathMode %s must be blank or path", r.Ingress.PathMode))
+	}
+	if r.Helm != "" && util.StringArrayIndex(HelmModeValues, r.Helm) < 0 {
+		problems = append(problems, fmt.Sprintf("helm %s must be one of %s", r.Helm, strings.Join(HelmModeValues, ", ")))
+	}
+	if r.Webhook != "" && util.StringArrayIndex(WebhookValues, r.Webhook) < 0 {
+		problems = append(problems, fmt.Sprintf("webhook %s must be one of %s", r.Webhook, strings.Join(WebhookValues, ", ")))
+	}
+	addons := map[string]bool{}
+	for _, addon := range r.Addons {
+		if addons[addon] {
+			problems = append(problems, fmt.Sprintf("addon %s is listed more than once", addon))
+		}
+		addons[addon] = true
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadInstallRequirements(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-install-requirements")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, config.RequirementsConfigFileName)
+	err = ioutil.WriteFile(fileName, []byte(`cluster:
+  provider: gke
+  namespace: cheese
+gitServer:
+  url: https://github.com
+  username: jenkins-x-bot
+  owner: acme
+environments:
+- name: staging
+- name: production
+  promotionStrategy: Manual
+environmentPrefix: acme
+ingress:
+  domain: acme.io
+  tls: true
+helm: template
+webhook: prow
+addons:
+- gitea
+`), 0644)
+	require.NoError(t, err)
+
+	requirements, err := config.LoadInstallRequirements(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "gke", requirements.Cluster.Provider)
+	assert.Equal(t, "cheese", requirements.Cluster.Namespace)
+	assert.Equal(t, "acme", requirements.GitServer.Owner)
+	require.Len(t, requirements.Environments, 2)
+	assert.Equal(t, "Manual", requirements.Environments[1].PromotionStrategy)
+	assert.True(t, requirements.Ingress.TLS)
+	assert.Equal(t, config.HelmModeTemplate, requirements.Helm)
+	assert.Equal(t, config.WebhookProw, requirements.Webhook)
+	assert.Equal(t, []string{"gitea"}, requirements.Addons)
+
+	savedFileName := filepath.Join(dir, "saved.yml")
+	require.NoError(t, requirements.SaveConfig(savedFileName))
+	saved, err := config.LoadInstallRequirements(savedFileName)
+	require.NoError(t, err)
+	assert.Equal(t, requirements, saved)
+}
+
+func TestLoadInstallRequirementsEnvironments(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-install-requirements-environments")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	defaults := filepath.Join(dir, "defaults.yml")
+	require.NoError(t, ioutil.WriteFile(defaults, []byte("cluster:\n  provider: minikube\n"), 0644))
+	requirements, err := config.LoadInstallRequirements(defaults)
+	require.NoError(t, err)
+	assert.Nil(t, requirements.Environments)
+
+	none := filepath.Join(dir, "none.yml")
+	require.NoError(t, ioutil.WriteFile(none, []byte("cluster:\n  provider: minikube\nenvironments: []\n"), 0644))
+	requirements, err = config.LoadInstallRequirements(none)
+	require.NoError(t, err)
+	assert.NotNil(t, requirements.Environments)
+	assert.Empty(t, requirements.Environments)
+}
+
+func TestLoadInstallRequirementsUnknownField(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-install-requirements-unknown")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, config.RequirementsConfigFileName)
+	require.NoError(t, ioutil.WriteFile(fileName, []byte("cluster:\n  provider: gke\n  zone: europe-west1-b\n"), 0644))
+	_, err = config.LoadInstallRequirements(fileName)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "zone")
+
+	_, err = config.LoadInstallRequirements(filepath.Join(dir, "missing.yml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not exist")
+}
+
+func TestValidateInstallRequirements(t *testing.T) {
+	t.Parallel()
+	requirements := &config.InstallRequirements{
+		Cluster: config.ClusterRequirements{
+			Namespace: "Jenkins_X",
+		},
+		GitServer: config.GitServerRequirements{
+			URL: "github.com",
+		},
+		Environments: []config.EnvironmentRequirements{
+			{Name: "staging"},
+			{Name: "staging", PromotionStrategy: "Sometimes"},
+			{Name: "dev"},
+			{Name: ""},
+		},
+		Ingress: config.IngressRequirements{
+			TLS:      true,
+			PathMode: "subdomain",
+		},
+		Helm:    "helm2",
+		Webhook: "gitlab",
+		Addons:  []string{"gitea", "gitea"},
+	}
+
+	err := requirements.Validate()
+	require.Error(t, err)
+	message := err.Error()
+	assert.Contains(t, message, "cluster.provider is required")
+	assert.Contains(t, message, "cluster.namespace Jenkins_X is not a valid Kubernetes namespace")
+	assert.Contains(t, message, "gitServer.url github.com is not an http or https URL")
+	assert.Contains(t, message, "environments[1].name staging is used by more than one environment")
+	assert.Contains(t, message, "environments[1].promotionStrategy Sometimes must be one of Auto, Manual, Never")
+	assert.Contains(t, message, "environments[2].name dev is reserved for the development environment")
+	assert.Contains(t, message, "environments[3].name is required")
+	assert.Contains(t, message, "ingress.domain is required when ingress.tls is enabled")
+	assert.Contains(t, message, "ingress.pathMode subdomain must be blank or path")
+	assert.Contains(t, message, "helm helm2 must be one of tiller, local-tiller, template, helm3")
+	assert.Contains(t, message, "webhook gitlab must be one of prow, jenkins")
+	assert.Contains(t, message, "addon gitea is listed more than once")
+
+	valid := &config.InstallRequirements{
+		Cluster:      config.ClusterRequirements{Provider: "eks"},
+		Environments: config.DefaultEnvironments(),
+	}
+	assert.NoError(t, valid.Validate())
+}
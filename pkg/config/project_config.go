@@ -24,6 +24,7 @@ type ProjectConfig struct {
 	PreviewEnvironments *PreviewEnvironmentConfig `yaml:"previewEnvironments,omitempty"`
 	IssueTracker        *IssueTrackerConfig       `yaml:"issueTracker,omitempty"`
 	Chat                *ChatConfig               `yaml:"chat,omitempty"`
+	Changelog           *ChangelogConfig          `yaml:"changelog,omitempty"`
 	Wiki                *WikiConfig               `yaml:"wiki,omitempty"`
 	Addons              []*AddonConfig            `yaml:"addons,omitempty"`
 	BuildPack           string                    `yaml:"buildPack,omitempty"`
@@ -35,6 +36,93 @@ type ProjectConfig struct {
 type PreviewEnvironmentConfig struct {
 	Disabled         bool `yaml:"disabled,omitempty"`
 	MaximumInstances int  `yaml:"maximumInstances,omitempty"`
+
+	// Dependencies are the other charts deployed into each preview environment before the application
+	Dependencies []*PreviewDependency `yaml:"dependencies,omitempty"`
+}
+
+// PreviewDependency is a chart deployed into a preview environment along with the application
+type PreviewDependency struct {
+	// Name is the name of the chart
+	Name string `yaml:"name"`
+	// Alias is used to name the release if the same chart is used more than once
+	Alias string `yaml:"alias,omitempty"`
+	// Repository is the URL of the chart repository. Defaults to the team's chart repository
+	Repository string `yaml:"repository,omitempty"`
+	// Version pins the version of the chart. If blank the version currently deployed in the Environment is used
+	Version string `yaml:"version,omitempty"`
+	// Environment is the name of the environment used to find the version if none is pinned. Defaults to staging
+	Environment string `yaml:"environment,omitempty"`
+	// Values are helm values to set when installing the chart such as 'postgresql.persistence.enabled=false'
+	Values []string `yaml:"values,omitempty"`
+	// Seed is an optional Job used to populate the dependency, such as a database, after its deployed
+	Seed *PreviewSeed `yaml:"seed,omitempty"`
+}
+
+// PreviewSeed describes a Job which seeds a preview dependency with data such as from a database snapshot
+type PreviewSeed struct {
+	Image   string   `yaml:"image"`
+	Command []string `yaml:"command,omitempty"`
+	Args    []string `yaml:"args,omitempty"`
+	// Snapshot is the location of the snapshot to restore which is passed to the Job as $JX_SEED_SNAPSHOT
+	Snapshot string `yaml:"snapshot,omitempty"`
+	// Env are the environment variables of the Job which use the same YAML layout as Kubernetes
+	Env []*PreviewSeedEnvVar `yaml:"env,omitempty"`
+}
+
+// PreviewSeedEnvVar is an environment variable of a seed Job whose value is either given or taken from a Secret
+// or ConfigMap
+type PreviewSeedEnvVar struct {
+	Name      string                   `yaml:"name"`
+	Value     string                   `yaml:"value,omitempty"`
+	ValueFrom *PreviewSeedEnvVarSource `yaml:"valueFrom,omitempty"`
+}
+
+// PreviewSeedEnvVarSource is the Secret or ConfigMap key an environment variable of a seed Job is taken from
+type PreviewSeedEnvVarSource struct {
+	SecretKeyRef    *PreviewSeedKeySelector `yaml:"secretKeyRef,omitempty"`
+	ConfigMapKeyRef *PreviewSeedKeySelector `yaml:"configMapKeyRef,omitempty"`
+}
+
+// PreviewSeedKeySelector selects a key of a Secret or ConfigMap
+type PreviewSeedKeySelector struct {
+	Name     string `yaml:"name"`
+	Key      string `yaml:"key"`
+	Optional *bool  `yaml:"optional,omitempty"`
+}
+
+// ToEnvVar returns the Kubernetes environment variable
+func (e *PreviewSeedEnvVar) ToEnvVar() corev1.EnvVar {
+	envVar := corev1.EnvVar{
+		Name:  e.Name,
+		Value: e.Value,
+	}
+	if e.ValueFrom != nil {
+		envVar.ValueFrom = &corev1.EnvVarSource{}
+		if ref := e.ValueFrom.SecretKeyRef; ref != nil {
+			envVar.ValueFrom.SecretKeyRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: ref.Name},
+				Key:                  ref.Key,
+				Optional:             ref.Optional,
+			}
+		}
+		if ref := e.ValueFrom.ConfigMapKeyRef; ref != nil {
+			envVar.ValueFrom.ConfigMapKeyRef = &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: ref.Name},
+				Key:                  ref.Key,
+				Optional:             ref.Optional,
+			}
+		}
+	}
+	return envVar
+}
+
+// ReleaseName returns the name of the dependency used for its release
+func (d *PreviewDependency) ReleaseName() string {
+	if d.Alias != "" {
+		return d.Alias
+	}
+	return d.Name
 }
 
 type IssueTrackerConfig struct {
@@ -56,6 +144,29 @@ type ChatConfig struct {
 	UserChannel      string `yaml:"userChannel,omitempty"`
 }
 
+// ChangelogConfig configures how the changelog is generated by 'jx step changelog'
+type ChangelogConfig struct {
+	// Format is the built in output format: markdown, html, asciidoc or json
+	Format string `yaml:"format,omitempty"`
+	// Template is the file name of a go template used to render the changelog
+	Template string `yaml:"template,omitempty"`
+	// File is the name of a markdown file in the repository, such as CHANGELOG.md, to add each release to
+	File string `yaml:"file,omitempty"`
+	// Paths only includes commits which change files within these paths relative to the root of the repository
+	Paths []string `yaml:"paths,omitempty"`
+	// Groups are the rules used to group commits in the changelog; defaults to the Conventional Commit types
+	Groups []*ChangelogGroupConfig `yaml:"groups,omitempty"`
+}
+
+// ChangelogGroupConfig defines a group of commits in the changelog
+type ChangelogGroupConfig struct {
+	Title string `yaml:"title,omitempty"`
+	// Kinds are the Conventional Commit types included in this group such as feat or fix
+	Kinds []string `yaml:"kinds,omitempty"`
+	// Pattern is a regular expression matched against the commit message
+	Pattern string `yaml:"pattern,omitempty"`
+}
+
 type AddonConfig struct {
 	Name    string `yaml:"name,omitempty"`
 	Version string `yaml:"version,omitempty"`
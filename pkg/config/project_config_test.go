@@ -10,6 +10,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProjectConfigMarshal(t *testing.T) {
@@ -47,3 +48,36 @@ func TestProjectConfigMarshal(t *testing.T) {
 	assert.True(t, projectConfig.Builds[0].ExcludePodTemplateEnv)
 	assert.True(t, projectConfig.Builds[0].ExcludePodTemplateVolumes)
 }
+
+func TestPreviewSeedEnvUsesKubernetesLayout(t *testing.T) {
+	t.Parallel()
+	data := `previewEnvironments:
+  dependencies:
+  - name: postgresql
+    seed:
+      image: postgres:10
+      env:
+      - name: PGHOST
+        value: postgresql
+      - name: PGPASSWORD
+        valueFrom:
+          secretKeyRef:
+            name: postgresql
+            key: postgres-password
+`
+	projectConfig := &config.ProjectConfig{}
+	err := yaml.Unmarshal([]byte(data), projectConfig)
+	require.NoError(t, err)
+	require.Len(t, projectConfig.PreviewEnvironments.Dependencies, 1)
+	seed := projectConfig.PreviewEnvironments.Dependencies[0].Seed
+	require.NotNil(t, seed)
+	require.Len(t, seed.Env, 2)
+
+	assert.Equal(t, corev1.EnvVar{Name: "PGHOST", Value: "postgresql"}, seed.Env[0].ToEnvVar())
+	password := seed.Env[1].ToEnvVar()
+	require.NotNil(t, password.ValueFrom)
+	require.NotNil(t, password.ValueFrom.SecretKeyRef)
+	assert.Equal(t, "postgresql", password.ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "postgres-password", password.ValueFrom.SecretKeyRef.Key)
+	assert.Nil(t, password.ValueFrom.ConfigMapKeyRef)
+}
@@ -0,0 +1,312 @@
+package draft
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Azure/draft/pkg/linguist"
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DetectFileName the name of the file in a build pack which declares the rules used to detect it
+	DetectFileName = "detect.yaml"
+)
+
+// PackDetectionRules the rules used to detect whether a build pack should be used for a project
+type PackDetectionRules struct {
+	Rules []DetectionRule `json:"rules,omitempty"`
+}
+
+// DetectionRule scores a build pack if a file matching the pattern exists in the project and contains all the text
+type DetectionRule struct {
+	// File the glob pattern of the file relative to the project directory such as pom.xml or *.csproj
+	File string `json:"file"`
+	// Contains the text which the file must contain for the rule to match
+	Contains []string `json:"contains,omitempty"`
+	// Score the score added to the pack if the rule matches. The standard packs score 40 to 50 for their build files
+	// so rules which check the contents of a build file should score more than that
+	Score int `json:"score"`
+}
+
+// PackScore the score of a build pack for a project with the reasons for the score
+type PackScore struct {
+	Name  string `json:"name"`
+	Dir   string `json:"dir"`
+	Score int    `json:"score"`
+	// Language the percentage of the source code in the language the pack is named after. It only breaks ties
+	// between packs with the same score
+	Language float64  `json:"language,omitempty"`
+	Reasons  []string `json:"reasons,omitempty"`
+}
+
+// Deployable a directory of a project which is built and deployed with a build pack
+type Deployable struct {
+	// Dir the directory relative to the project directory
+	Dir        string       `json:"dir"`
+	Pack       *PackScore   `json:"pack"`
+	Candidates []*PackScore `json:"candidates,omitempty"`
+}
+
+// PackDetector scores the build packs in a packs directory against the files of a project
+type PackDetector struct {
+	PacksDir string
+	Packs    map[string]*PackDetectionRules
+
+	// DisableLanguages disables breaking ties between packs on the dominant language of the source code
+	DisableLanguages bool
+}
+
+// DefaultDetectionRules the rules used for the standard build packs which do not have a detect.yaml
+var DefaultDetectionRules = map[string][]DetectionRule{
+	"appserver":  {{File: "pom.xml", Contains: []string{"<groupId>org.apache.tomcat"}, Score: 60}},
+	"csharp":     {{File: "*.csproj", Score: 50}, {File: "*.sln", Score: 40}},
+	"cwp":        {{File: "packager-config.yml", Score: 60}},
+	"go":         {{File: "go.mod", Score: 50}, {File: "Gopkg.toml", Score: 40}, {File: "glide.yaml", Score: 40}},
+	"gradle":     {{File: "build.gradle", Score: 50}, {File: "build.gradle.kts", Score: 50}, {File: "settings.gradle", Score: 10}},
+	"javascript": {{File: "package.json", Score: 40}},
+	"jenkins":    {{File: "plugins.txt", Score: 55}},
+	"liberty":    {{File: "pom.xml", Contains: []string{"<packaging>war</packaging>", "org.eclipse.microprofile"}, Score: 70}},
+	"maven":      {{File: "pom.xml", Score: 50}},
+	"php":        {{File: "composer.json", Score: 50}},
+	"python":     {{File: "requirements.txt", Score: 40}, {File: "setup.py", Score: 40}, {File: "Pipfile", Score: 40}},
+	"ruby":       {{File: "Gemfile", Score: 50}},
+	"rust":       {{File: "Cargo.toml", Score: 50}},
+	"scala":      {{File: "build.sbt", Score: 50}},
+	"swift":      {{File: "Package.swift", Score: 50}},
+	"typescript": {{File: "tsconfig.json", Score: 45}},
+	"docker":     {{File: "Dockerfile", Score: 20}},
+}
+
+// ignoredDeployableDirs the directories which are never searched for deployables
+var ignoredDeployableDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"build":        true,
+	"charts":       true,
+	"dist":         true,
+}
+
+// NewPackDetector loads the detection rules of the build packs in the packs directory
+func NewPackDetector(packsDir string) (*PackDetector, error) {
+	files, err := ioutil.ReadDir(packsDir)
+	if err != nil {
+		return nil, fmt.Errorf("there was an error reading %s: %v", packsDir, err)
+	}
+	detector := &PackDetector{
+		PacksDir: packsDir,
+		Packs:    map[string]*PackDetectionRules{},
+	}
+	for _, file := range files {
+		if !file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		name := file.Name()
+		rules, err := LoadPackDetectionRules(filepath.Join(packsDir, name))
+		if err != nil {
+			return nil, err
+		}
+		if rules == nil {
+			rules = &PackDetectionRules{
+				Rules: DefaultDetectionRules[name],
+			}
+		}
+		detector.Packs[name] = rules
+	}
+	return detector, nil
+}
+
+// LoadPackDetectionRules loads the detect.yaml of the build pack returning nil if it has none
+func LoadPackDetectionRules(packDir string) (*PackDetectionRules, error) {
+	fileName := filepath.Join(packDir, DetectFileName)
+	exists, err := util.FileExists(fileName)
+	if err != nil || !exists {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load file %s", fileName)
+	}
+	rules := &PackDetectionRules{}
+	err = yaml.Unmarshal(data, rules)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal YAML file %s", fileName)
+	}
+	for _, rule := range rules.Rules {
+		if rule.File == "" {
+			return nil, fmt.Errorf("detection rule without a file in %s", fileName)
+		}
+		if _, err := filepath.Match(rule.File, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid file pattern %s in %s", rule.File, fileName)
+		}
+	}
+	return rules, nil
+}
+
+// Detect scores the build packs for the project in the directory returning the packs which match with the highest
+// score first. Packs with the same score are ordered by how much of the source code is in their language
+func (d *PackDetector) Detect(dir string) ([]*PackScore, error) {
+	scores, err := d.scoreRules(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !d.DisableLanguages {
+		d.scoreLanguages(dir, scores)
+	}
+	answer := []*PackScore{}
+	for _, score := range scores {
+		if score.Score > 0 || score.Language > 0 {
+			answer = append(answer, score)
+		}
+	}
+	sortPackScores(answer)
+	return answer, nil
+}
+
+// sortPackScores sorts the scores with the highest first using the language and then the name to break ties
+func sortPackScores(scores []*PackScore) {
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		if scores[i].Language != scores[j].Language {
+			return scores[i].Language > scores[j].Language
+		}
+		return scores[i].Name < scores[j].Name
+	})
+}
+
+func (d *PackDetector) scoreRules(dir string) (map[string]*PackScore, error) {
+	scores := map[string]*PackScore{}
+	for name, rules := range d.Packs {
+		score := &PackScore{
+			Name: name,
+			Dir:  filepath.Join(d.PacksDir, name),
+		}
+		scores[name] = score
+		for _, rule := range rules.Rules {
+			reason, err := rule.matches(dir)
+			if err != nil {
+				return nil, err
+			}
+			if reason != "" {
+				score.Score += rule.Score
+				score.Reasons = append(score.Reasons, fmt.Sprintf("%s (+%d)", reason, rule.Score))
+			}
+		}
+	}
+	return scores, nil
+}
+
+// scoreLanguages records how much of the source code is in the language of each pack. The language does not add to
+// the score so that a build file always outweighs the language of the source
+func (d *PackDetector) scoreLanguages(dir string, scores map[string]*PackScore) {
+	langs, err := linguist.ProcessDir(dir)
+	if err != nil {
+		return
+	}
+	for _, lang := range langs {
+		detectedLang := linguist.Alias(lang)
+		score := scores[strings.ToLower(detectedLang.Language)]
+		if score != nil && detectedLang.Percent > 0 {
+			score.Language += detectedLang.Percent
+			score.Reasons = append(score.Reasons, fmt.Sprintf("%.0f%% of the source is %s", detectedLang.Percent, detectedLang.Language))
+		}
+	}
+}
+
+// matches returns the reason the rule matches the files in the directory or a blank string if it does not match
+func (r *DetectionRule) matches(dir string) (string, error) {
+	fileNames, err := filepath.Glob(filepath.Join(dir, r.File))
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid file pattern %s", r.File)
+	}
+	for _, fileName := range fileNames {
+		info, err := os.Stat(fileName)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		name := filepath.Base(fileName)
+		if len(r.Contains) == 0 {
+			return "found " + name, nil
+		}
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to load file %s", fileName)
+		}
+		text := string(data)
+		matched := true
+		for _, contains := range r.Contains {
+			if !strings.Contains(text, contains) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return fmt.Sprintf("%s contains %s", name, strings.Join(r.Contains, " and ")), nil
+		}
+	}
+	return "", nil
+}
+
+// DetectDeployables finds the directories of the project up to the given depth which have the build files of a
+// build pack. The sub directories of a deployable which match the same build pack, such as the modules of a maven
+// project, are part of that deployable
+func (d *PackDetector) DetectDeployables(dir string, depth int) ([]*Deployable, error) {
+	answer := []*Deployable{}
+	err := d.detectDeployables(dir, ".", depth, "", &answer)
+	return answer, err
+}
+
+func (d *PackDetector) detectDeployables(dir string, rel string, depth int, parentPack string, answer *[]*Deployable) error {
+	path := filepath.Join(dir, rel)
+	scores, err := d.scoreRules(path)
+	if err != nil {
+		return err
+	}
+	candidates := []*PackScore{}
+	for _, score := range scores {
+		if score.Score > 0 {
+			candidates = append(candidates, score)
+		}
+	}
+	sortPackScores(candidates)
+	if len(candidates) > 0 && candidates[0].Name != parentPack {
+		*answer = append(*answer, &Deployable{
+			Dir:        filepath.ToSlash(rel),
+			Pack:       candidates[0],
+			Candidates: candidates,
+		})
+		parentPack = candidates[0].Name
+	}
+	if depth <= 0 {
+		return nil
+	}
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		name := file.Name()
+		if !file.IsDir() || strings.HasPrefix(name, ".") || ignoredDeployableDirs[name] {
+			continue
+		}
+		err = d.detectDeployables(dir, filepath.Join(rel, name), depth-1, parentPack, answer)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Explain returns a description of why the pack was chosen
+func (s *PackScore) Explain() string {
+	return fmt.Sprintf("%s scored %d: %s", s.Name, s.Score, strings.Join(s.Reasons, ", "))
+}
@@ -0,0 +1,165 @@
+package draft_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/draft"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for name, text := range files {
+		fileName := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(fileName), util.DefaultWritePermissions))
+		require.NoError(t, ioutil.WriteFile(fileName, []byte(text), util.DefaultWritePermissions))
+	}
+}
+
+func createTestPackDetector(t *testing.T) (*draft.PackDetector, string) {
+	dir, err := ioutil.TempDir("", "test-pack-detection")
+	require.NoError(t, err)
+	packsDir := filepath.Join(dir, "packs")
+	for _, name := range []string{"appserver", "go", "gradle", "javascript", "liberty", "maven", "python", "rust", "spring-boot"} {
+		writeTestFiles(t, packsDir, map[string]string{name + "/Dockerfile": "FROM scratch\n"})
+	}
+	// a pack which declares its own rules
+	writeTestFiles(t, packsDir, map[string]string{"spring-boot/" + draft.DetectFileName: `rules:
+- file: build.gradle
+  contains: [org.springframework.boot]
+  score: 70
+- file: pom.xml
+  contains: [spring-boot-starter]
+  score: 70
+`})
+	detector, err := draft.NewPackDetector(packsDir)
+	require.NoError(t, err)
+	detector.DisableLanguages = true
+	return detector, dir
+}
+
+func detectedPack(t *testing.T, detector *draft.PackDetector, dir string) string {
+	scores, err := detector.Detect(dir)
+	require.NoError(t, err)
+	if len(scores) == 0 {
+		return ""
+	}
+	return scores[0].Name
+}
+
+func TestPackDetection(t *testing.T) {
+	t.Parallel()
+	detector, dir := createTestPackDetector(t)
+	defer os.RemoveAll(dir)
+
+	tests := map[string]map[string]string{
+		"maven": {
+			"pom.xml":        "<project><modules><module>api</module></modules></project>",
+			"api/pom.xml":    "<project></project>",
+			"api/src/A.java": "class A {}",
+		},
+		"spring-boot": {
+			"build.gradle":    "plugins { id 'org.springframework.boot' version '2.1.0' }",
+			"settings.gradle": "",
+		},
+		"gradle": {
+			"build.gradle.kts": "",
+		},
+		"liberty": {
+			"pom.xml": "<packaging>war</packaging><groupId>org.eclipse.microprofile</groupId>",
+		},
+		"appserver": {
+			"pom.xml": "<groupId>org.apache.tomcat</groupId>",
+		},
+		"rust": {
+			"Cargo.toml": "[package]",
+		},
+		"": {
+			"README.md": "nothing to build",
+		},
+	}
+	for expected, files := range tests {
+		projectDir := filepath.Join(dir, "project-"+expected)
+		writeTestFiles(t, projectDir, files)
+		assert.Equal(t, expected, detectedPack(t, detector, projectDir), "project with files %v", files)
+	}
+
+	scores, err := detector.Detect(filepath.Join(dir, "project-spring-boot"))
+	require.NoError(t, err)
+	require.Len(t, scores, 2)
+	assert.Equal(t, "spring-boot scored 70: build.gradle contains org.springframework.boot (+70)", scores[0].Explain())
+	assert.Equal(t, "gradle scored 60: found build.gradle (+50), found settings.gradle (+10)", scores[1].Explain())
+}
+
+func TestPackDetectionLanguageBreaksTies(t *testing.T) {
+	t.Parallel()
+	detector, dir := createTestPackDetector(t)
+	defer os.RemoveAll(dir)
+	detector.DisableLanguages = false
+
+	// a maven project with a javascript front end built from the same pom.xml
+	projectDir := filepath.Join(dir, "project-maven-javascript")
+	files := map[string]string{
+		"pom.xml":                       "<project></project>",
+		"package.json":                  "{}",
+		"src/main/java/App.java":        "class App {}\n",
+		"src/main/resources/app.js":     "function a() {}\n",
+		"src/main/resources/more.js":    "function b() {}\n",
+		"src/main/resources/another.js": "function c() {}\n",
+	}
+	writeTestFiles(t, projectDir, files)
+	scores, err := detector.Detect(projectDir)
+	require.NoError(t, err)
+	require.True(t, len(scores) >= 2)
+	assert.Equal(t, "maven", scores[0].Name, "the pom.xml should outweigh the javascript source")
+	assert.Equal(t, 50, scores[0].Score)
+	assert.Equal(t, "javascript", scores[1].Name)
+	assert.Equal(t, 40, scores[1].Score)
+	assert.True(t, scores[1].Language > scores[0].Language)
+
+	// packs with the same score are chosen by the language of the source
+	projectDir = filepath.Join(dir, "project-python-javascript")
+	writeTestFiles(t, projectDir, map[string]string{
+		"package.json":     "{}",
+		"requirements.txt": "flask\n",
+		"app.py":           "def main():\n    pass\n",
+		"server.py":        "def serve():\n    pass\n",
+	})
+	assert.Equal(t, "python", detectedPack(t, detector, projectDir))
+
+	detector.DisableLanguages = true
+	assert.Equal(t, "javascript", detectedPack(t, detector, projectDir))
+}
+
+func TestDetectDeployables(t *testing.T) {
+	t.Parallel()
+	detector, dir := createTestPackDetector(t)
+	defer os.RemoveAll(dir)
+
+	projectDir := filepath.Join(dir, "shop")
+	writeTestFiles(t, projectDir, map[string]string{
+		"go.mod":                               "module shop",
+		"main.go":                              "package main",
+		"frontend/package.json":                "{}",
+		"frontend/node_modules/a/package.json": "{}",
+		"services/orders/pom.xml":              "<project></project>",
+		"services/orders/api/pom.xml":          "<project></project>",
+		"docs/index.md":                        "# docs",
+	})
+	deployables, err := detector.DetectDeployables(projectDir, 3)
+	require.NoError(t, err)
+
+	actual := map[string]string{}
+	for _, deployable := range deployables {
+		actual[deployable.Dir] = deployable.Pack.Name
+	}
+	assert.Equal(t, map[string]string{
+		".":               "go",
+		"frontend":        "javascript",
+		"services/orders": "maven",
+	}, actual)
+}
@@ -0,0 +1,1208 @@
+package gits
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// AzureDevOpsAPIVersion the version of the Azure DevOps REST API used
+	AzureDevOpsAPIVersion = "5.0"
+
+	// DefaultAzureDevOpsWorkItemType the type of the work items created for issues
+	DefaultAzureDevOpsWorkItemType = "Issue"
+
+	azureJSONPatchContentType = "application/json-patch+json"
+
+	azureDevOpsHost             = "dev.azure.com"
+	azureDevOpsSSHHost          = "ssh.dev.azure.com"
+	azureVisualStudioHostSuffix = ".visualstudio.com"
+)
+
+// AzureDevOpsProvider implements GitProvider interface for Azure DevOps Repos.
+//
+// Azure DevOps projects are used as the organisations of the repositories and work items are used as issues.
+// The Azure DevOps organisation is taken from the path of the server URL, e.g. https://dev.azure.com/myorg,
+// or from the host of legacy server URLs, e.g. https://myorg.visualstudio.com
+type AzureDevOpsProvider struct {
+	Client       *http.Client
+	BaseURL      string
+	Organisation string
+	Username     string
+	WorkItemType string
+
+	Server auth.AuthServer
+	User   auth.UserAuth
+	Git    Gitter
+}
+
+// AzureDevOpsError is returned when the Azure DevOps REST API responds with an error status
+type AzureDevOpsError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *AzureDevOpsError) Error() string {
+	return fmt.Sprintf("Azure DevOps API returned status %d: %s", e.StatusCode, e.Message)
+}
+
+type azureProject struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type azureProjects struct {
+	Count int            `json:"count"`
+	Value []azureProject `json:"value"`
+}
+
+type azureRepository struct {
+	ID               string           `json:"id,omitempty"`
+	Name             string           `json:"name,omitempty"`
+	URL              string           `json:"url,omitempty"`
+	RemoteURL        string           `json:"remoteUrl,omitempty"`
+	SSHURL           string           `json:"sshUrl,omitempty"`
+	WebURL           string           `json:"webUrl,omitempty"`
+	DefaultBranch    string           `json:"defaultBranch,omitempty"`
+	Project          *azureProject    `json:"project,omitempty"`
+	ParentRepository *azureRepository `json:"parentRepository,omitempty"`
+}
+
+type azureRepositories struct {
+	Count int               `json:"count"`
+	Value []azureRepository `json:"value"`
+}
+
+type azureIdentity struct {
+	ID          string `json:"id,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	UniqueName  string `json:"uniqueName,omitempty"`
+	URL         string `json:"url,omitempty"`
+	ImageURL    string `json:"imageUrl,omitempty"`
+}
+
+type azureCommitRef struct {
+	CommitID string `json:"commitId,omitempty"`
+}
+
+type azurePullRequest struct {
+	PullRequestID         int              `json:"pullRequestId,omitempty"`
+	Status                string           `json:"status,omitempty"`
+	CreatedBy             *azureIdentity   `json:"createdBy,omitempty"`
+	CreationDate          *time.Time       `json:"creationDate,omitempty"`
+	ClosedDate            *time.Time       `json:"closedDate,omitempty"`
+	Title                 string           `json:"title,omitempty"`
+	Description           string           `json:"description,omitempty"`
+	SourceRefName         string           `json:"sourceRefName,omitempty"`
+	TargetRefName         string           `json:"targetRefName,omitempty"`
+	MergeStatus           string           `json:"mergeStatus,omitempty"`
+	LastMergeSourceCommit *azureCommitRef  `json:"lastMergeSourceCommit,omitempty"`
+	LastMergeCommit       *azureCommitRef  `json:"lastMergeCommit,omitempty"`
+	Repository            *azureRepository `json:"repository,omitempty"`
+	URL                   string           `json:"url,omitempty"`
+}
+
+type azureGitUserDate struct {
+	Name  string     `json:"name,omitempty"`
+	Email string     `json:"email,omitempty"`
+	Date  *time.Time `json:"date,omitempty"`
+}
+
+type azureCommit struct {
+	CommitID  string            `json:"commitId,omitempty"`
+	Author    *azureGitUserDate `json:"author,omitempty"`
+	Committer *azureGitUserDate `json:"committer,omitempty"`
+	Comment   string            `json:"comment,omitempty"`
+	URL       string            `json:"url,omitempty"`
+}
+
+type azureCommits struct {
+	Count int           `json:"count"`
+	Value []azureCommit `json:"value"`
+}
+
+type azureStatusContext struct {
+	Name  string `json:"name,omitempty"`
+	Genre string `json:"genre,omitempty"`
+}
+
+type azureCommitStatus struct {
+	ID          int                 `json:"id,omitempty"`
+	State       string              `json:"state,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Context     *azureStatusContext `json:"context,omitempty"`
+	TargetURL   string              `json:"targetUrl,omitempty"`
+	URL         string              `json:"url,omitempty"`
+}
+
+type azureCommitStatuses struct {
+	Count int                 `json:"count"`
+	Value []azureCommitStatus `json:"value"`
+}
+
+type azureSubscription struct {
+	ID               string            `json:"id,omitempty"`
+	PublisherID      string            `json:"publisherId,omitempty"`
+	EventType        string            `json:"eventType,omitempty"`
+	ResourceVersion  string            `json:"resourceVersion,omitempty"`
+	ConsumerID       string            `json:"consumerId,omitempty"`
+	ConsumerActionID string            `json:"consumerActionId,omitempty"`
+	PublisherInputs  map[string]string `json:"publisherInputs,omitempty"`
+	ConsumerInputs   map[string]string `json:"consumerInputs,omitempty"`
+}
+
+type azureSubscriptions struct {
+	Count int                 `json:"count"`
+	Value []azureSubscription `json:"value"`
+}
+
+type azureLink struct {
+	Href string `json:"href,omitempty"`
+}
+
+type azureWorkItem struct {
+	ID     int                    `json:"id,omitempty"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	Links  map[string]azureLink   `json:"_links,omitempty"`
+	URL    string                 `json:"url,omitempty"`
+}
+
+type azureWorkItems struct {
+	Count int             `json:"count"`
+	Value []azureWorkItem `json:"value"`
+}
+
+type azureWorkItemRef struct {
+	ID  int    `json:"id"`
+	URL string `json:"url,omitempty"`
+}
+
+type azureWiqlResult struct {
+	WorkItems []azureWorkItemRef `json:"workItems"`
+}
+
+type azurePatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// azureWebHookEvents the service hook events which trigger pipelines
+var azureWebHookEvents = []string{"git.push", "git.pullrequest.created", "git.pullrequest.updated"}
+
+// NewAzureDevOpsProvider creates a new git provider for Azure DevOps Repos
+func NewAzureDevOpsProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter) (GitProvider, error) {
+	baseURL := strings.TrimSuffix(server.URL, "/")
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Azure DevOps server URL %s: %s", server.URL, err)
+	}
+	organisation := strings.Trim(u.Path, "/")
+	if organisation == "" && strings.HasSuffix(u.Hostname(), azureVisualStudioHostSuffix) {
+		organisation = strings.TrimSuffix(u.Hostname(), azureVisualStudioHostSuffix)
+	}
+	if organisation == "" || strings.Contains(organisation, "/") {
+		return nil, fmt.Errorf("The Azure DevOps server URL %s must include the organisation, e.g. https://dev.azure.com/myorg", server.URL)
+	}
+	provider := AzureDevOpsProvider{
+		Client:       http.DefaultClient,
+		BaseURL:      baseURL,
+		Organisation: organisation,
+		Username:     user.Username,
+		WorkItemType: DefaultAzureDevOpsWorkItemType,
+		Server:       *server,
+		User:         *user,
+		Git:          git,
+	}
+	return &provider, nil
+}
+
+// IsAzureDevOpsHost returns true if the given host or URL belongs to Azure DevOps Services
+func IsAzureDevOpsHost(gitURL string) bool {
+	host := gitURL
+	u, err := url.Parse(gitURL)
+	if err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	return host == azureDevOpsHost || host == azureDevOpsSSHHost || strings.HasSuffix(host, azureVisualStudioHostSuffix)
+}
+
+// AzureDevOpsServerURL returns the server URL of the Azure DevOps organisation for the given host.
+// The organisation is part of the server URL on dev.azure.com so that each organisation has its own auth server
+func AzureDevOpsServerURL(host string, organisation string) string {
+	u, err := url.Parse(host)
+	if err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	if strings.HasSuffix(host, azureVisualStudioHostSuffix) || organisation == "" {
+		if host == azureDevOpsSSHHost {
+			host = azureDevOpsHost
+		}
+		return "https://" + host
+	}
+	return util.UrlJoin(AzureDevOpsURL, organisation)
+}
+
+// request invokes the REST API at the given path relative to the organisation URL, decoding the response into result
+func (p *AzureDevOpsProvider) request(method string, path string, params url.Values, contentType string, body interface{}, result interface{}) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	if params.Get("api-version") == "" {
+		params.Set("api-version", AzureDevOpsAPIVersion)
+	}
+	u := util.UrlJoin(p.BaseURL, path) + "?" + params.Encode()
+
+	var reqBody *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, u, reqBody)
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(p.Username, p.User.ApiToken)
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &AzureDevOpsError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	if result != nil && len(data) > 0 {
+		err = json.Unmarshal(data, result)
+		if err != nil {
+			return fmt.Errorf("Failed to parse the response of %s %s: %s", method, path, err)
+		}
+	}
+	return nil
+}
+
+func isAzureNotFound(err error) bool {
+	azureErr, ok := err.(*AzureDevOpsError)
+	return ok && azureErr.StatusCode == http.StatusNotFound
+}
+
+func azureRepositoryToGitRepository(repo *azureRepository) *GitRepository {
+	return &GitRepository{
+		Name:             repo.Name,
+		AllowMergeCommit: true,
+		HTMLURL:          repo.WebURL,
+		CloneURL:         repo.RemoteURL,
+		SSHURL:           repo.SSHURL,
+		Fork:             repo.ParentRepository != nil,
+	}
+}
+
+func (p *AzureDevOpsProvider) getProject(project string) (*azureProject, error) {
+	answer := &azureProject{}
+	err := p.request(http.MethodGet, util.UrlJoin("_apis/projects", url.PathEscape(project)), nil, "", nil, answer)
+	return answer, err
+}
+
+func (p *AzureDevOpsProvider) getRepository(project string, name string) (*azureRepository, error) {
+	answer := &azureRepository{}
+	err := p.request(http.MethodGet, p.repositoryPath(project, name), nil, "", nil, answer)
+	return answer, err
+}
+
+// repositoryPath returns the API path of the repository. If the project is blank or the Azure DevOps organisation,
+// such as when the owner of a parsed git URL is passed, the repository is looked up by name across the organisation
+func (p *AzureDevOpsProvider) repositoryPath(project string, repo string, paths ...string) string {
+	elements := []string{}
+	if project != "" && project != p.Organisation {
+		elements = append(elements, url.PathEscape(project))
+	}
+	elements = append(elements, "_apis/git/repositories", url.PathEscape(repo))
+	return util.UrlJoin(append(elements, paths...)...)
+}
+
+// projectOf returns the Azure DevOps project of the repository falling back to the given owner
+func projectOf(info *GitRepositoryInfo, owner string) string {
+	if info == nil {
+		return owner
+	}
+	if info.Project != "" {
+		return info.Project
+	}
+	if owner != "" {
+		return owner
+	}
+	return info.Organisation
+}
+
+func (p *AzureDevOpsProvider) ListOrganisations() ([]GitOrganisation, error) {
+	projects := azureProjects{}
+	err := p.request(http.MethodGet, "_apis/projects", nil, "", nil, &projects)
+	if err != nil {
+		return nil, err
+	}
+	answer := []GitOrganisation{}
+	for _, project := range projects.Value {
+		answer = append(answer, GitOrganisation{Login: project.Name})
+	}
+	return answer, nil
+}
+
+func (p *AzureDevOpsProvider) ListRepositories(org string) ([]*GitRepository, error) {
+	repos := azureRepositories{}
+	err := p.request(http.MethodGet, util.UrlJoin(url.PathEscape(org), "_apis/git/repositories"), nil, "", nil, &repos)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitRepository{}
+	for i := range repos.Value {
+		answer = append(answer, azureRepositoryToGitRepository(&repos.Value[i]))
+	}
+	return answer, nil
+}
+
+func (p *AzureDevOpsProvider) CreateRepository(org string, name string, private bool) (*GitRepository, error) {
+	project, err := p.getProject(org)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find Azure DevOps project %s: %s", org, err)
+	}
+	body := &azureRepository{
+		Name:    name,
+		Project: &azureProject{ID: project.ID},
+	}
+	repo := &azureRepository{}
+	err = p.request(http.MethodPost, util.UrlJoin(url.PathEscape(org), "_apis/git/repositories"), nil, "", body, repo)
+	if err != nil {
+		return nil, err
+	}
+	return azureRepositoryToGitRepository(repo), nil
+}
+
+func (p *AzureDevOpsProvider) GetRepository(org string, name string) (*GitRepository, error) {
+	repo, err := p.getRepository(org, name)
+	if err != nil {
+		return nil, err
+	}
+	return azureRepositoryToGitRepository(repo), nil
+}
+
+func (p *AzureDevOpsProvider) DeleteRepository(org string, name string) error {
+	repo, err := p.getRepository(org, name)
+	if err != nil {
+		return err
+	}
+	return p.request(http.MethodDelete, p.repositoryPath(org, repo.ID), nil, "", nil, nil)
+}
+
+func (p *AzureDevOpsProvider) ForkRepository(originalOrg string, name string, destinationOrg string) (*GitRepository, error) {
+	if destinationOrg == "" {
+		destinationOrg = originalOrg
+	}
+	repo, err := p.getRepository(originalOrg, name)
+	if err != nil {
+		return nil, err
+	}
+	project, err := p.getProject(destinationOrg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find Azure DevOps project %s: %s", destinationOrg, err)
+	}
+	forkName := name
+	if destinationOrg == originalOrg {
+		forkName = name + "-fork"
+	}
+	body := &azureRepository{
+		Name:    forkName,
+		Project: &azureProject{ID: project.ID},
+		ParentRepository: &azureRepository{
+			ID:      repo.ID,
+			Project: repo.Project,
+		},
+	}
+	fork := &azureRepository{}
+	err = p.request(http.MethodPost, util.UrlJoin(url.PathEscape(destinationOrg), "_apis/git/repositories"), nil, "", body, fork)
+	if err != nil {
+		return nil, err
+	}
+	return azureRepositoryToGitRepository(fork), nil
+}
+
+func (p *AzureDevOpsProvider) RenameRepository(org string, name string, newName string) (*GitRepository, error) {
+	repo, err := p.getRepository(org, name)
+	if err != nil {
+		return nil, err
+	}
+	renamed := &azureRepository{}
+	err = p.request(http.MethodPatch, p.repositoryPath(org, repo.ID), nil, "", &azureRepository{Name: newName}, renamed)
+	if err != nil {
+		return nil, err
+	}
+	return azureRepositoryToGitRepository(renamed), nil
+}
+
+func (p *AzureDevOpsProvider) ValidateRepositoryName(org string, name string) error {
+	_, err := p.getRepository(org, name)
+	if err == nil {
+		return fmt.Errorf("Repository %s already exists", p.Git.RepoName(org, name))
+	}
+	if isAzureNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func azureBranchRef(branch string) string {
+	if strings.HasPrefix(branch, "refs/") {
+		return branch
+	}
+	return "refs/heads/" + branch
+}
+
+func (p *AzureDevOpsProvider) pullRequestURL(org string, repo string, number int) string {
+	return util.UrlJoin(p.BaseURL, url.PathEscape(org), "_git", url.PathEscape(repo), "pullrequest", strconv.Itoa(number))
+}
+
+func (p *AzureDevOpsProvider) toGitPullRequest(org string, repo string, pr *azurePullRequest) *GitPullRequest {
+	if pr.Repository != nil && pr.Repository.Project != nil && pr.Repository.Project.Name != "" {
+		org = pr.Repository.Project.Name
+	}
+	number := pr.PullRequestID
+	state := "open"
+	merged := pr.Status == "completed"
+	if pr.Status != "active" {
+		state = "closed"
+	}
+	mergeable := pr.MergeStatus == "succeeded"
+	headRef := strings.TrimPrefix(pr.SourceRefName, "refs/heads/")
+	answer := &GitPullRequest{
+		URL:       p.pullRequestURL(org, repo, number),
+		Owner:     org,
+		Repo:      repo,
+		Number:    &number,
+		State:     &state,
+		Merged:    &merged,
+		Mergeable: &mergeable,
+		HeadRef:   &headRef,
+		ClosedAt:  pr.ClosedDate,
+		Title:     pr.Title,
+		Body:      pr.Description,
+	}
+	if merged {
+		answer.MergedAt = pr.ClosedDate
+	}
+	if pr.CreatedBy != nil {
+		answer.Author = &GitUser{
+			Login:     pr.CreatedBy.UniqueName,
+			Name:      pr.CreatedBy.DisplayName,
+			URL:       pr.CreatedBy.URL,
+			AvatarURL: pr.CreatedBy.ImageURL,
+		}
+	}
+	if pr.LastMergeSourceCommit != nil {
+		answer.LastCommitSha = pr.LastMergeSourceCommit.CommitID
+	}
+	if pr.LastMergeCommit != nil && pr.LastMergeCommit.CommitID != "" {
+		answer.MergeCommitSHA = &pr.LastMergeCommit.CommitID
+	}
+	return answer
+}
+
+func (p *AzureDevOpsProvider) getPullRequest(org string, repo string, number int) (*azurePullRequest, error) {
+	answer := &azurePullRequest{}
+	err := p.request(http.MethodGet, p.repositoryPath(org, repo, "pullrequests", strconv.Itoa(number)), nil, "", nil, answer)
+	return answer, err
+}
+
+func (p *AzureDevOpsProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
+	org := projectOf(data.GitRepositoryInfo, "")
+	repo := data.GitRepositoryInfo.Name
+	base := data.Base
+	if base == "" {
+		base = "master"
+	}
+	body := &azurePullRequest{
+		Title:         data.Title,
+		Description:   data.Body,
+		SourceRefName: azureBranchRef(data.Head),
+		TargetRefName: azureBranchRef(base),
+	}
+	pr := &azurePullRequest{}
+	err := p.request(http.MethodPost, p.repositoryPath(org, repo, "pullrequests"), nil, "", body, pr)
+	if err != nil {
+		return nil, err
+	}
+	return p.toGitPullRequest(org, repo, pr), nil
+}
+
+func (p *AzureDevOpsProvider) UpdatePullRequestStatus(pr *GitPullRequest) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	result, err := p.getPullRequest(pr.Owner, pr.Repo, *pr.Number)
+	if err != nil {
+		return err
+	}
+	updated := p.toGitPullRequest(pr.Owner, pr.Repo, result)
+	pr.State = updated.State
+	pr.Merged = updated.Merged
+	pr.Mergeable = updated.Mergeable
+	pr.MergeCommitSHA = updated.MergeCommitSHA
+	pr.LastCommitSha = updated.LastCommitSha
+	pr.ClosedAt = updated.ClosedAt
+	pr.MergedAt = updated.MergedAt
+	if pr.Author == nil {
+		pr.Author = updated.Author
+	}
+	return nil
+}
+
+func (p *AzureDevOpsProvider) GetPullRequest(owner string, repo *GitRepositoryInfo, number int) (*GitPullRequest, error) {
+	owner = projectOf(repo, owner)
+	pr, err := p.getPullRequest(owner, repo.Name, number)
+	if err != nil {
+		return nil, err
+	}
+	return p.toGitPullRequest(owner, repo.Name, pr), nil
+}
+
+func (p *AzureDevOpsProvider) GetPullRequestCommits(owner string, repo *GitRepositoryInfo, number int) ([]*GitCommit, error) {
+	owner = projectOf(repo, owner)
+	commits := azureCommits{}
+	err := p.request(http.MethodGet, p.repositoryPath(owner, repo.Name, "pullrequests", strconv.Itoa(number), "commits"), nil, "", nil, &commits)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitCommit{}
+	for _, commit := range commits.Value {
+		c := &GitCommit{
+			SHA:     commit.CommitID,
+			Message: commit.Comment,
+			URL:     commit.URL,
+		}
+		if commit.Author != nil {
+			c.Author = &GitUser{
+				Name:  commit.Author.Name,
+				Email: commit.Author.Email,
+			}
+		}
+		if commit.Committer != nil {
+			c.Committer = &GitUser{
+				Name:  commit.Committer.Name,
+				Email: commit.Committer.Email,
+			}
+		}
+		answer = append(answer, c)
+	}
+	return answer, nil
+}
+
+func (p *AzureDevOpsProvider) PullRequestLastCommitStatus(pr *GitPullRequest) (string, error) {
+	ref := pr.LastCommitSha
+	if ref == "" {
+		return "", fmt.Errorf("Missing String for LastCommitSha %#v", pr)
+	}
+	statuses, err := p.ListCommitStatus(pr.Owner, pr.Repo, ref)
+	if err != nil {
+		return "", err
+	}
+	for _, status := range statuses {
+		if status.State != "" {
+			return status.State, nil
+		}
+	}
+	return "", fmt.Errorf("Could not find a status for repository %s/%s with ref %s", pr.Owner, pr.Repo, ref)
+}
+
+// azureStatusState converts the state of an Azure DevOps commit status into the state used by the other providers
+func azureStatusState(state string) string {
+	switch state {
+	case "succeeded", "notApplicable":
+		return "success"
+	case "failed":
+		return "failure"
+	case "error":
+		return "error"
+	default:
+		return "pending"
+	}
+}
+
+func (p *AzureDevOpsProvider) ListCommitStatus(org string, repo string, sha string) ([]*GitRepoStatus, error) {
+	statuses := azureCommitStatuses{}
+	err := p.request(http.MethodGet, p.repositoryPath(org, repo, "commits", sha, "statuses"), nil, "", nil, &statuses)
+	if err != nil {
+		return nil, fmt.Errorf("Could not find a status for repository %s/%s with ref %s: %s", org, repo, sha, err)
+	}
+	answer := []*GitRepoStatus{}
+	for _, s := range statuses.Value {
+		status := &GitRepoStatus{
+			ID:          strconv.Itoa(s.ID),
+			URL:         s.URL,
+			TargetURL:   s.TargetURL,
+			State:       azureStatusState(s.State),
+			Description: s.Description,
+		}
+		if s.Context != nil {
+			status.Context = s.Context.Name
+			if s.Context.Genre != "" {
+				status.Context = s.Context.Genre + "/" + s.Context.Name
+			}
+		}
+		answer = append(answer, status)
+	}
+	return answer, nil
+}
+
+// CreateCommitStatus creates a status on the given commit. The genre of the status is the prefix of the context
+// before the last '/' if there is one
+func (p *AzureDevOpsProvider) CreateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	context := &azureStatusContext{
+		Name:  status.Context,
+		Genre: "jenkins-x",
+	}
+	if i := strings.LastIndex(status.Context, "/"); i > 0 {
+		context.Genre = status.Context[:i]
+		context.Name = status.Context[i+1:]
+	}
+	if context.Name == "" {
+		context.Name = "jenkins-x"
+	}
+	body := &azureCommitStatus{
+		State:       azureDevOpsStatusState(status.State),
+		Description: status.Description,
+		TargetURL:   status.TargetURL,
+		Context:     context,
+	}
+	result := &azureCommitStatus{}
+	err := p.request(http.MethodPost, p.repositoryPath(org, repo, "commits", sha, "statuses"), nil, "", body, result)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepoStatus{
+		ID:          strconv.Itoa(result.ID),
+		Context:     context.Genre + "/" + context.Name,
+		URL:         result.URL,
+		TargetURL:   result.TargetURL,
+		State:       azureStatusState(result.State),
+		Description: result.Description,
+	}, nil
+}
+
+// azureDevOpsStatusState converts the state used by the other providers into the state of an Azure DevOps commit status
+func azureDevOpsStatusState(state string) string {
+	switch state {
+	case "success":
+		return "succeeded"
+	case "failure":
+		return "failed"
+	case "error":
+		return "error"
+	default:
+		return "pending"
+	}
+}
+
+// MergePullRequest completes the pull request
+func (p *AzureDevOpsProvider) MergePullRequest(pr *GitPullRequest, message string) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	current, err := p.getPullRequest(pr.Owner, pr.Repo, *pr.Number)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"status":                "completed",
+		"lastMergeSourceCommit": current.LastMergeSourceCommit,
+		"completionOptions": map[string]interface{}{
+			"mergeCommitMessage": message,
+			"deleteSourceBranch": false,
+		},
+	}
+	return p.request(http.MethodPatch, p.repositoryPath(pr.Owner, pr.Repo, "pullrequests", strconv.Itoa(*pr.Number)), nil, "", body, nil)
+}
+
+// CreateWebHook creates the service hook subscriptions which post the push and pull request events of the
+// repository to the webhook URL
+func (p *AzureDevOpsProvider) CreateWebHook(data *GitWebHookArguments) error {
+	org := data.Owner
+	if data.Repo != nil && (data.Repo.Project != "" || data.Repo.Organisation != "") {
+		org = projectOf(data.Repo, "")
+	}
+	repo, err := p.getRepository(org, data.Repo.Name)
+	if err != nil {
+		return err
+	}
+	if repo.Project == nil || repo.Project.ID == "" {
+		return fmt.Errorf("No project found for Azure DevOps repository %s/%s", org, data.Repo.Name)
+	}
+	consumerInputs := map[string]string{
+		"url": data.URL,
+	}
+	if data.Secret != "" {
+		consumerInputs["basicAuthUsername"] = "jenkins-x"
+		consumerInputs["basicAuthPassword"] = data.Secret
+	}
+	for _, event := range azureWebHookEvents {
+		subscription := &azureSubscription{
+			PublisherID:      "tfs",
+			EventType:        event,
+			ResourceVersion:  "1.0",
+			ConsumerID:       "webHooks",
+			ConsumerActionID: "httpRequest",
+			PublisherInputs: map[string]string{
+				"projectId":  repo.Project.ID,
+				"repository": repo.ID,
+			},
+			ConsumerInputs: consumerInputs,
+		}
+		err = p.request(http.MethodPost, "_apis/hooks/subscriptions", nil, "", subscription, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to create the %s service hook for repository %s/%s: %s", event, org, data.Repo.Name, err)
+		}
+	}
+	return nil
+}
+
+// ListWebHooks returns the webhooks of the repository. As a webhook is made of a service hook subscription per
+// event the subscriptions posting to the same URL are combined into one webhook with a comma separated ID
+func (p *AzureDevOpsProvider) ListWebHooks(org string, name string) ([]*GitWebHookArguments, error) {
+	repo, err := p.getRepository(org, name)
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("publisherId", "tfs")
+	params.Set("consumerId", "webHooks")
+	subscriptions := azureSubscriptions{}
+	err = p.request(http.MethodGet, "_apis/hooks/subscriptions", params, "", nil, &subscriptions)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitWebHookArguments{}
+	hooks := map[string]*GitWebHookArguments{}
+	for _, subscription := range subscriptions.Value {
+		if subscription.PublisherInputs["repository"] != repo.ID {
+			continue
+		}
+		hookURL := subscription.ConsumerInputs["url"]
+		hook := hooks[hookURL]
+		if hook == nil {
+			hook = &GitWebHookArguments{
+				ID:    subscription.ID,
+				Owner: org,
+				Repo:  &GitRepositoryInfo{Organisation: org, Name: name},
+				URL:   hookURL,
+			}
+			hooks[hookURL] = hook
+			answer = append(answer, hook)
+		} else {
+			hook.ID += "," + subscription.ID
+		}
+	}
+	return answer, nil
+}
+
+// UpdateWebHook updates the URL and secret of all the service hook subscriptions of the webhook
+func (p *AzureDevOpsProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	for _, id := range strings.Split(data.ID, ",") {
+		subscription := &azureSubscription{}
+		path := util.UrlJoin("_apis/hooks/subscriptions", id)
+		err := p.request(http.MethodGet, path, nil, "", nil, subscription)
+		if err != nil {
+			return err
+		}
+		consumerInputs := map[string]string{
+			"url": data.URL,
+		}
+		if data.Secret != "" {
+			consumerInputs["basicAuthUsername"] = "jenkins-x"
+			consumerInputs["basicAuthPassword"] = data.Secret
+		}
+		subscription.ConsumerInputs = consumerInputs
+		err = p.request(http.MethodPut, path, nil, "", subscription, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to update the service hook %s: %s", id, err)
+		}
+	}
+	return nil
+}
+
+// DeleteWebHook deletes all the service hook subscriptions of the webhook
+func (p *AzureDevOpsProvider) DeleteWebHook(org string, repo string, id string) error {
+	for _, subscriptionID := range strings.Split(id, ",") {
+		err := p.request(http.MethodDelete, util.UrlJoin("_apis/hooks/subscriptions", subscriptionID), nil, "", nil, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to delete the service hook %s: %s", subscriptionID, err)
+		}
+	}
+	return nil
+}
+
+func (p *AzureDevOpsProvider) workItemURL(project string, id int) string {
+	return util.UrlJoin(p.BaseURL, url.PathEscape(project), "_workitems/edit", strconv.Itoa(id))
+}
+
+func azureField(fields map[string]interface{}, name string) string {
+	switch v := fields[name].(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		// identity fields are objects in newer API versions
+		if s, ok := v["uniqueName"].(string); ok {
+			return s
+		}
+		if s, ok := v["displayName"].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+func azureDateField(fields map[string]interface{}, name string) *time.Time {
+	text := azureField(fields, name)
+	if text == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func (p *AzureDevOpsProvider) toGitIssue(project string, item *azureWorkItem) *GitIssue {
+	number := item.ID
+	state := "open"
+	switch azureField(item.Fields, "System.State") {
+	case "Closed", "Done", "Removed", "Resolved":
+		state = "closed"
+	}
+	issueURL := p.workItemURL(project, number)
+	if link, ok := item.Links["html"]; ok && link.Href != "" {
+		issueURL = link.Href
+	}
+	answer := &GitIssue{
+		URL:       issueURL,
+		Owner:     project,
+		Number:    &number,
+		Key:       strconv.Itoa(number),
+		Title:     azureField(item.Fields, "System.Title"),
+		Body:      azureField(item.Fields, "System.Description"),
+		State:     &state,
+		CreatedAt: azureDateField(item.Fields, "System.CreatedDate"),
+		UpdatedAt: azureDateField(item.Fields, "System.ChangedDate"),
+		ClosedAt:  azureDateField(item.Fields, "Microsoft.VSTS.Common.ClosedDate"),
+	}
+	if login := azureField(item.Fields, "System.CreatedBy"); login != "" {
+		answer.User = &GitUser{Login: login}
+	}
+	if login := azureField(item.Fields, "Microsoft.VSTS.Common.ClosedBy"); login != "" {
+		answer.ClosedBy = &GitUser{Login: login}
+	}
+	if login := azureField(item.Fields, "System.AssignedTo"); login != "" {
+		answer.Assignees = []GitUser{{Login: login}}
+	}
+	if tags := azureField(item.Fields, "System.Tags"); tags != "" {
+		for _, tag := range strings.Split(tags, ";") {
+			tag = strings.TrimSpace(tag)
+			if tag != "" {
+				answer.Labels = append(answer.Labels, GitLabel{Name: tag})
+			}
+		}
+	}
+	return answer
+}
+
+// GetWorkItem returns the work item of the given id in the project as an issue
+func (p *AzureDevOpsProvider) GetWorkItem(project string, id int) (*GitIssue, error) {
+	item := &azureWorkItem{}
+	err := p.request(http.MethodGet, util.UrlJoin(url.PathEscape(project), "_apis/wit/workitems", strconv.Itoa(id)), nil, "", nil, item)
+	if err != nil {
+		return nil, err
+	}
+	return p.toGitIssue(project, item), nil
+}
+
+// QueryWorkItems returns the work items in the project matching the WIQL conditions
+func (p *AzureDevOpsProvider) QueryWorkItems(project string, conditions string) ([]*GitIssue, error) {
+	wiql := "SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project"
+	if conditions != "" {
+		wiql += " AND " + conditions
+	}
+	wiql += " ORDER BY [System.ChangedDate] DESC"
+	result := azureWiqlResult{}
+	err := p.request(http.MethodPost, util.UrlJoin(url.PathEscape(project), "_apis/wit/wiql"), nil, "", map[string]string{"query": wiql}, &result)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitIssue{}
+	if len(result.WorkItems) == 0 {
+		return answer, nil
+	}
+	ids := []string{}
+	for _, ref := range result.WorkItems {
+		ids = append(ids, strconv.Itoa(ref.ID))
+	}
+	// the work items API returns at most 200 work items per request
+	for len(ids) > 0 {
+		batch := ids
+		if len(batch) > 200 {
+			batch = ids[:200]
+		}
+		ids = ids[len(batch):]
+		items := azureWorkItems{}
+		params := url.Values{}
+		params.Set("ids", strings.Join(batch, ","))
+		err = p.request(http.MethodGet, util.UrlJoin(url.PathEscape(project), "_apis/wit/workitems"), params, "", nil, &items)
+		if err != nil {
+			return answer, err
+		}
+		for i := range items.Value {
+			answer = append(answer, p.toGitIssue(project, &items.Value[i]))
+		}
+	}
+	return answer, nil
+}
+
+// SearchWorkItems returns the open work items in the project whose title contains the query
+func (p *AzureDevOpsProvider) SearchWorkItems(project string, query string) ([]*GitIssue, error) {
+	conditions := "[System.State] NOT IN ('Closed', 'Done', 'Removed', 'Resolved')"
+	if query != "" {
+		conditions += " AND [System.Title] CONTAINS '" + strings.Replace(query, "'", "''", -1) + "'"
+	}
+	return p.QueryWorkItems(project, conditions)
+}
+
+// CreateWorkItem creates a work item in the project for the issue
+func (p *AzureDevOpsProvider) CreateWorkItem(project string, issue *GitIssue) (*GitIssue, error) {
+	operations := []azurePatchOperation{
+		{Op: "add", Path: "/fields/System.Title", Value: issue.Title},
+	}
+	if issue.Body != "" {
+		operations = append(operations, azurePatchOperation{Op: "add", Path: "/fields/System.Description", Value: issue.Body})
+	}
+	if len(issue.Labels) > 0 {
+		tags := []string{}
+		for _, label := range issue.Labels {
+			tags = append(tags, label.Name)
+		}
+		operations = append(operations, azurePatchOperation{Op: "add", Path: "/fields/System.Tags", Value: strings.Join(tags, "; ")})
+	}
+	workItemType := p.WorkItemType
+	if workItemType == "" {
+		workItemType = DefaultAzureDevOpsWorkItemType
+	}
+	item := &azureWorkItem{}
+	err := p.request(http.MethodPost, util.UrlJoin(url.PathEscape(project), "_apis/wit/workitems", "$"+url.PathEscape(workItemType)), nil, azureJSONPatchContentType, operations, item)
+	if err != nil {
+		return nil, err
+	}
+	return p.toGitIssue(project, item), nil
+}
+
+// CreateWorkItemComment adds a comment to the discussion of the work item
+func (p *AzureDevOpsProvider) CreateWorkItemComment(project string, id int, comment string) error {
+	operations := []azurePatchOperation{
+		{Op: "add", Path: "/fields/System.History", Value: comment},
+	}
+	return p.request(http.MethodPatch, util.UrlJoin(url.PathEscape(project), "_apis/wit/workitems", strconv.Itoa(id)), nil, azureJSONPatchContentType, operations, nil)
+}
+
+// WorkItemURL returns the URL of the work item in the project
+func (p *AzureDevOpsProvider) WorkItemURL(project string, id int) string {
+	return p.workItemURL(project, id)
+}
+
+func (p *AzureDevOpsProvider) GetIssue(org string, name string, number int) (*GitIssue, error) {
+	issue, err := p.GetWorkItem(org, number)
+	if issue != nil {
+		issue.Repo = name
+	}
+	return issue, err
+}
+
+func (p *AzureDevOpsProvider) IssueURL(org string, name string, number int, isPull bool) string {
+	if isPull {
+		return p.pullRequestURL(org, name, number)
+	}
+	return p.workItemURL(org, number)
+}
+
+func (p *AzureDevOpsProvider) SearchIssues(org string, name string, query string) ([]*GitIssue, error) {
+	return p.SearchWorkItems(org, query)
+}
+
+func (p *AzureDevOpsProvider) SearchIssuesClosedSince(org string, name string, t time.Time) ([]*GitIssue, error) {
+	conditions := "[System.State] IN ('Closed', 'Done', 'Removed', 'Resolved') AND [System.ChangedDate] >= '" + t.UTC().Format("2006-01-02") + "'"
+	issues, err := p.QueryWorkItems(org, conditions)
+	if err != nil {
+		return issues, err
+	}
+	return FilterIssuesClosedSince(issues, t), nil
+}
+
+func (p *AzureDevOpsProvider) CreateIssue(owner string, repo string, issue *GitIssue) (*GitIssue, error) {
+	answer, err := p.CreateWorkItem(owner, issue)
+	if answer != nil {
+		answer.Repo = repo
+	}
+	return answer, err
+}
+
+func (p *AzureDevOpsProvider) HasIssues() bool {
+	return true
+}
+
+// AddPRComment adds the comment as a new thread on the pull request
+func (p *AzureDevOpsProvider) AddPRComment(pr *GitPullRequest, comment string) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	thread := map[string]interface{}{
+		"comments": []map[string]interface{}{
+			{
+				"parentCommentId": 0,
+				"content":         comment,
+				"commentType":     1,
+			},
+		},
+		"status": 1,
+	}
+	return p.request(http.MethodPost, p.repositoryPath(pr.Owner, pr.Repo, "pullrequests", strconv.Itoa(*pr.Number), "threads"), nil, "", thread, nil)
+}
+
+func (p *AzureDevOpsProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
+	return p.CreateWorkItemComment(owner, number, comment)
+}
+
+func (p *AzureDevOpsProvider) UpdateRelease(owner string, repo string, tag string, releaseInfo *GitRelease) error {
+	log.Warn("Azure DevOps Repos doesn't support releases")
+	return nil
+}
+
+func (p *AzureDevOpsProvider) ListReleases(org string, name string) ([]*GitRelease, error) {
+	answer := []*GitRelease{}
+	log.Warn("Azure DevOps Repos doesn't support releases")
+	return answer, nil
+}
+
+func (p *AzureDevOpsProvider) ProtectBranch(org string, repo string, protection *GitBranchProtection) error {
+	log.Warn("Azure DevOps does not support branch protection at this moment")
+	return nil
+}
+
+func (p *AzureDevOpsProvider) IsGitHub() bool {
+	return false
+}
+
+func (p *AzureDevOpsProvider) IsGitea() bool {
+	return false
+}
+
+func (p *AzureDevOpsProvider) IsBitbucketCloud() bool {
+	return false
+}
+
+func (p *AzureDevOpsProvider) IsBitbucketServer() bool {
+	return false
+}
+
+func (p *AzureDevOpsProvider) IsGerrit() bool {
+	return false
+}
+
+func (p *AzureDevOpsProvider) Kind() string {
+	return KindAzureDevOps
+}
+
+// JenkinsWebHookPath uses the generic git plugin endpoint as Jenkins has no Azure DevOps specific webhook
+func (p *AzureDevOpsProvider) JenkinsWebHookPath(gitURL string, secret string) string {
+	return "/git/notifyCommit?url=" + url.QueryEscape(gitURL)
+}
+
+func (p *AzureDevOpsProvider) Label() string {
+	return p.Server.Label()
+}
+
+func (p *AzureDevOpsProvider) ServerURL() string {
+	return p.Server.URL
+}
+
+func (p *AzureDevOpsProvider) BranchArchiveURL(org string, name string, branch string) string {
+	return util.UrlJoin(p.BaseURL, p.repositoryPath(org, name, "items")) +
+		"?path=/&versionDescriptor.version=" + url.QueryEscape(branch) + "&$format=zip&download=true&api-version=" + AzureDevOpsAPIVersion
+}
+
+func (p *AzureDevOpsProvider) CurrentUsername() string {
+	return p.Username
+}
+
+func (p *AzureDevOpsProvider) UserAuth() auth.UserAuth {
+	return p.User
+}
+
+func (p *AzureDevOpsProvider) UserInfo(username string) *GitUser {
+	return &GitUser{
+		Login: username,
+		Email: username,
+	}
+}
+
+func (p *AzureDevOpsProvider) AddCollaborator(user string, organisation string, repo string) error {
+	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Azure DevOps. Please add user: %v to the project %s.\n", user, organisation)
+	return nil
+}
+
+func (p *AzureDevOpsProvider) ListCollaborators(organisation string, repo string) ([]*GitCollaborator, error) {
+	log.Warn("Listing the collaborators of a repository is currently not implemented for Azure DevOps")
+	return []*GitCollaborator{}, nil
+}
+
+func (p *AzureDevOpsProvider) AddOrganisationMember(organisation string, user string) error {
+	log.Infof("Adding members to a project is currently not implemented for Azure DevOps. Please add user: %v to the project %s.\n", user, organisation)
+	return nil
+}
+
+func (p *AzureDevOpsProvider) RemoveOrganisationMember(organisation string, user string) error {
+	log.Warnf("Removing members from a project is currently not implemented for Azure DevOps. Please remove user: %v from the project %s.\n", user, organisation)
+	return nil
+}
+
+func (p *AzureDevOpsProvider) ListInvitations() ([]*GitInvitation, error) {
+	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Azure DevOps.\n")
+	return []*GitInvitation{}, nil
+}
+
+func (p *AzureDevOpsProvider) AcceptInvitation(ID string) error {
+	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Azure DevOps.\n")
+	return nil
+}
+
+// AzureDevOpsAccessTokenURL returns the URL to create personal access tokens
+func AzureDevOpsAccessTokenURL(url string) string {
+	return util.UrlJoin(url, "/_usersSettings/tokens")
+}
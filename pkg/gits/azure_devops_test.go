@@ -0,0 +1,439 @@
+package gits_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	azureRepoID     = "5febef5a-833d-4e14-b9c0-14cb638f91e6"
+	azureCommitSha  = "b60280bc6e62e2f880f1b63c1e24987664d3bda3"
+	azureRepoPath   = "/myorg/test-project/_apis/git/repositories/test-repo"
+	azureRepoIDPath = "/myorg/test-project/_apis/git/repositories/" + azureRepoID
+)
+
+type AzureDevOpsProviderTestSuite struct {
+	suite.Suite
+	mux      *http.ServeMux
+	server   *httptest.Server
+	provider *gits.AzureDevOpsProvider
+
+	lock     sync.Mutex
+	requests map[string]string
+}
+
+var azureDevOpsRouter = util.Router{
+	"/myorg/_apis/projects": util.MethodMap{
+		"GET": "projects.json",
+	},
+	"/myorg/_apis/projects/test-project": util.MethodMap{
+		"GET": "projects.test-project.json",
+	},
+	"/myorg/_apis/projects/fork-project": util.MethodMap{
+		"GET": "projects.fork-project.json",
+	},
+	"/myorg/test-project/_apis/git/repositories": util.MethodMap{
+		"GET":  "repos.json",
+		"POST": "repos.new-repo.json",
+	},
+	"/myorg/fork-project/_apis/git/repositories": util.MethodMap{
+		"POST": "repos.test-fork.json",
+	},
+	azureRepoPath: util.MethodMap{
+		"GET": "repos.test-repo.json",
+	},
+	"/myorg/_apis/git/repositories/test-repo": util.MethodMap{
+		"GET": "repos.test-repo.json",
+	},
+	azureRepoIDPath: util.MethodMap{
+		"DELETE": "empty.json",
+		"PATCH":  "repos.test-repo-renamed.json",
+	},
+	azureRepoPath + "/pullrequests": util.MethodMap{
+		"POST": "pullrequests.created.json",
+	},
+	azureRepoPath + "/pullrequests/1": util.MethodMap{
+		"GET":   "pullrequests.1.json",
+		"PATCH": "pullrequests.2.json",
+	},
+	azureRepoPath + "/pullrequests/2": util.MethodMap{
+		"GET": "pullrequests.2.json",
+	},
+	azureRepoPath + "/pullrequests/1/commits": util.MethodMap{
+		"GET": "pullrequests.1.commits.json",
+	},
+	azureRepoPath + "/pullrequests/1/threads": util.MethodMap{
+		"POST": "threads.json",
+	},
+	azureRepoPath + "/commits/" + azureCommitSha + "/statuses": util.MethodMap{
+		"GET": "statuses.json",
+	},
+	"/myorg/_apis/hooks/subscriptions": util.MethodMap{
+		"POST": "subscriptions.json",
+	},
+	"/myorg/test-project/_apis/wit/wiql": util.MethodMap{
+		"POST": "wiql.json",
+	},
+	"/myorg/test-project/_apis/wit/workitems": util.MethodMap{
+		"GET": "workitems.json",
+	},
+	"/myorg/test-project/_apis/wit/workitems/1": util.MethodMap{
+		"GET":   "workitems.1.json",
+		"PATCH": "workitems.1.json",
+	},
+	"/myorg/test-project/_apis/wit/workitems/$Issue": util.MethodMap{
+		"POST": "workitems.1.json",
+	},
+}
+
+func (suite *AzureDevOpsProviderTestSuite) SetupSuite() {
+	suite.mux = http.NewServeMux()
+	suite.requests = map[string]string{}
+
+	for path, methodMap := range azureDevOpsRouter {
+		suite.mux.HandleFunc(path, util.GetMockAPIResponseFromFile("test_data/azure_devops", methodMap))
+	}
+
+	// record the request bodies so that the tests can verify what was sent
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		suite.lock.Lock()
+		suite.requests[r.Method+" "+r.URL.Path] = string(body)
+		suite.lock.Unlock()
+		suite.Require().Equal(gits.AzureDevOpsAPIVersion, r.URL.Query().Get("api-version"))
+		suite.mux.ServeHTTP(w, r)
+	}))
+	suite.Require().NotNil(suite.server)
+
+	as := auth.AuthServer{
+		URL:         suite.server.URL + "/myorg",
+		Name:        "Test Azure DevOps Server",
+		Kind:        gits.KindAzureDevOps,
+		CurrentUser: "test-user",
+	}
+	ua := auth.UserAuth{
+		Username: "test-user",
+		ApiToken: "0123456789abdef",
+	}
+
+	git := gits.NewGitCLI()
+	p, err := gits.CreateProvider(&as, &ua, git)
+	suite.Require().Nil(err)
+	suite.Require().NotNil(p)
+
+	var ok bool
+	suite.provider, ok = p.(*gits.AzureDevOpsProvider)
+	suite.Require().True(ok)
+	suite.Require().Equal(as.URL, suite.provider.BaseURL)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) requestBody(method string, path string) string {
+	suite.lock.Lock()
+	defer suite.lock.Unlock()
+	return suite.requests[method+" "+path]
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestListOrganisations() {
+	orgs, err := suite.provider.ListOrganisations()
+
+	suite.Require().Nil(err)
+	suite.Require().Len(orgs, 2)
+	suite.Require().Equal("test-project", orgs[0].Login)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestListRepositories() {
+	repos, err := suite.provider.ListRepositories("test-project")
+
+	suite.Require().Nil(err)
+	suite.Require().Len(repos, 2)
+	suite.Require().Equal("test-repo", repos[0].Name)
+	suite.Require().Equal("https://myorg@dev.azure.com/myorg/test-project/_git/test-repo", repos[0].CloneURL)
+	suite.Require().Equal("https://dev.azure.com/myorg/test-project/_git/test-repo", repos[0].HTMLURL)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestGetRepository() {
+	repo, err := suite.provider.GetRepository("test-project", "test-repo")
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("test-repo", repo.Name)
+	suite.Require().False(repo.Fork)
+
+	// the owner of a parsed git URL is the organisation so the repository is looked up across the organisation
+	repo, err = suite.provider.GetRepository("myorg", "test-repo")
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("test-repo", repo.Name)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestCreateRepository() {
+	repo, err := suite.provider.CreateRepository("test-project", "new-repo", true)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("new-repo", repo.Name)
+	suite.Require().JSONEq(`{"name":"new-repo","project":{"id":"eb6e4656-77fc-42a1-9181-4c6d8e9da5d1"}}`,
+		suite.requestBody("POST", "/myorg/test-project/_apis/git/repositories"))
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestDeleteRepository() {
+	err := suite.provider.DeleteRepository("test-project", "test-repo")
+
+	suite.Require().Nil(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestForkRepository() {
+	fork, err := suite.provider.ForkRepository("test-project", "test-repo", "fork-project")
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("test-repo", fork.Name)
+	suite.Require().True(fork.Fork)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestRenameRepository() {
+	repo, err := suite.provider.RenameRepository("test-project", "test-repo", "test-repo-renamed")
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("test-repo-renamed", repo.Name)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestValidateRepositoryName() {
+	err := suite.provider.ValidateRepositoryName("test-project", "test-repo")
+	suite.Require().NotNil(err)
+
+	err = suite.provider.ValidateRepositoryName("test-project", "foo-repo")
+	suite.Require().Nil(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestCreatePullRequest() {
+	args := gits.GitPullRequestArguments{
+		GitRepositoryInfo: &gits.GitRepositoryInfo{Name: "test-repo", Organisation: "myorg", Project: "test-project"},
+		Head:              "feature",
+		Base:              "master",
+		Title:             "Test Pull Request",
+	}
+
+	pr, err := suite.provider.CreatePullRequest(&args)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(1, *pr.Number)
+	suite.Require().Equal("open", *pr.State)
+	suite.Require().Equal("feature", *pr.HeadRef)
+	suite.Require().Equal(azureCommitSha, pr.LastCommitSha)
+	suite.Require().Equal("test-user@example.com", pr.Author.Login)
+	suite.Require().Equal(suite.provider.BaseURL+"/test-project/_git/test-repo/pullrequest/1", pr.URL)
+	suite.Require().JSONEq(`{"title":"Test Pull Request","sourceRefName":"refs/heads/feature","targetRefName":"refs/heads/master"}`,
+		suite.requestBody("POST", azureRepoPath+"/pullrequests"))
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestUpdatePullRequestStatus() {
+	number := 2
+	pr := &gits.GitPullRequest{
+		Owner:  "test-project",
+		Repo:   "test-repo",
+		Number: &number,
+	}
+
+	err := suite.provider.UpdatePullRequestStatus(pr)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("closed", *pr.State)
+	suite.Require().True(*pr.Merged)
+	suite.Require().True(pr.IsClosed())
+	suite.Require().Equal("8f5c7cd3e5b3b1d2f0a1e9c8b7a6f5e4d3c2b1a0", *pr.MergeCommitSHA)
+	suite.Require().Equal(time.Date(2018, 11, 21, 8, 0, 0, 0, time.UTC), pr.MergedAt.UTC())
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestGetPullRequest() {
+	pr, err := suite.provider.GetPullRequest("test-project", &gits.GitRepositoryInfo{Name: "test-repo"}, 1)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(1, *pr.Number)
+	suite.Require().True(*pr.Mergeable)
+	suite.Require().False(*pr.Merged)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestGetPullRequestCommits() {
+	commits, err := suite.provider.GetPullRequestCommits("test-project", &gits.GitRepositoryInfo{Name: "test-repo"}, 1)
+
+	suite.Require().Nil(err)
+	suite.Require().Len(commits, 2)
+	suite.Require().Equal(azureCommitSha, commits[0].SHA)
+	suite.Require().Equal("test-user@example.com", commits[0].Author.Email)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestListCommitStatus() {
+	statuses, err := suite.provider.ListCommitStatus("test-project", "test-repo", azureCommitSha)
+
+	suite.Require().Nil(err)
+	suite.Require().Len(statuses, 2)
+	suite.Require().Equal("success", statuses[0].State)
+	suite.Require().Equal("jenkins-x/build", statuses[0].Context)
+	suite.Require().Equal("pending", statuses[1].State)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestPullRequestLastCommitStatus() {
+	pr := &gits.GitPullRequest{
+		Owner:         "test-project",
+		Repo:          "test-repo",
+		LastCommitSha: azureCommitSha,
+	}
+
+	status, err := suite.provider.PullRequestLastCommitStatus(pr)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("success", status)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestMergePullRequest() {
+	number := 1
+	pr := &gits.GitPullRequest{
+		Owner:  "test-project",
+		Repo:   "test-repo",
+		Number: &number,
+	}
+
+	err := suite.provider.MergePullRequest(pr, "Merging from unit tests")
+
+	suite.Require().Nil(err)
+	suite.Require().JSONEq(`{"status":"completed","lastMergeSourceCommit":{"commitId":"`+azureCommitSha+`"},"completionOptions":{"mergeCommitMessage":"Merging from unit tests","deleteSourceBranch":false}}`,
+		suite.requestBody("PATCH", azureRepoPath+"/pullrequests/1"))
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestAddPRComment() {
+	number := 1
+	pr := &gits.GitPullRequest{
+		Owner:  "test-project",
+		Repo:   "test-repo",
+		Number: &number,
+	}
+
+	err := suite.provider.AddPRComment(pr, "Looks good")
+
+	suite.Require().Nil(err)
+	suite.Require().Contains(suite.requestBody("POST", azureRepoPath+"/pullrequests/1/threads"), `"content":"Looks good"`)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestCreateWebHook() {
+	data := &gits.GitWebHookArguments{
+		Repo:   &gits.GitRepositoryInfo{Name: "test-repo", Organisation: "myorg", Project: "test-project"},
+		URL:    "https://hook.example.com/hook",
+		Secret: "shhh",
+	}
+
+	err := suite.provider.CreateWebHook(data)
+
+	suite.Require().Nil(err)
+	body := suite.requestBody("POST", "/myorg/_apis/hooks/subscriptions")
+	suite.Require().Contains(body, `"repository":"`+azureRepoID+`"`)
+	suite.Require().Contains(body, `"basicAuthPassword":"shhh"`)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestGetIssue() {
+	issue, err := suite.provider.GetIssue("test-project", "test-repo", 1)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(1, *issue.Number)
+	suite.Require().Equal("This is a test issue", issue.Title)
+	suite.Require().Equal("open", *issue.State)
+	suite.Require().Equal("test-user@example.com", issue.User.Login)
+	suite.Require().Equal("other-user@example.com", issue.Assignees[0].Login)
+	suite.Require().Equal([]gits.GitLabel{{Name: "bug"}, {Name: "ui"}}, issue.Labels)
+	suite.Require().Equal("https://dev.azure.com/myorg/test-project/_workitems/edit/1", issue.URL)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestSearchIssues() {
+	issues, err := suite.provider.SearchIssues("test-project", "test-repo", "test")
+
+	suite.Require().Nil(err)
+	suite.Require().Len(issues, 2)
+	suite.Require().Contains(suite.requestBody("POST", "/myorg/test-project/_apis/wit/wiql"), "[System.Title] CONTAINS 'test'")
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestSearchIssuesClosedSince() {
+	issues, err := suite.provider.SearchIssuesClosedSince("test-project", "test-repo", time.Date(2018, 11, 21, 0, 0, 0, 0, time.UTC))
+
+	suite.Require().Nil(err)
+	suite.Require().Len(issues, 1)
+	suite.Require().Equal(2, *issues[0].Number)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestCreateIssue() {
+	issue, err := suite.provider.CreateIssue("test-project", "test-repo", &gits.GitIssue{
+		Title: "This is a test issue",
+	})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(1, *issue.Number)
+	suite.Require().JSONEq(`[{"op":"add","path":"/fields/System.Title","value":"This is a test issue"}]`,
+		suite.requestBody("POST", "/myorg/test-project/_apis/wit/workitems/$Issue"))
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestCreateIssueComment() {
+	err := suite.provider.CreateIssueComment("test-project", "test-repo", 1, "A comment")
+
+	suite.Require().Nil(err)
+	suite.Require().JSONEq(`[{"op":"add","path":"/fields/System.History","value":"A comment"}]`,
+		suite.requestBody("PATCH", "/myorg/test-project/_apis/wit/workitems/1"))
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
+
+func TestNewAzureDevOpsProviderRequiresOrganisation(t *testing.T) {
+	t.Parallel()
+	ua := &auth.UserAuth{Username: "test-user", ApiToken: "0123456789abdef"}
+	for _, u := range []string{"https://dev.azure.com", "https://dev.azure.com/", "https://dev.azure.com/myorg/myproject"} {
+		_, err := gits.NewAzureDevOpsProvider(&auth.AuthServer{URL: u}, ua, nil)
+		if assert.Error(t, err, "server URL %s", u) {
+			assert.Contains(t, err.Error(), "must include the organisation")
+		}
+	}
+	p, err := gits.NewAzureDevOpsProvider(&auth.AuthServer{URL: "https://dev.azure.com/myorg/"}, ua, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "myorg", p.(*gits.AzureDevOpsProvider).Organisation)
+}
+
+func TestNewAzureDevOpsProviderFromCloneURL(t *testing.T) {
+	t.Parallel()
+	ua := &auth.UserAuth{Username: "test-user", ApiToken: "0123456789abdef"}
+	testCases := map[string][]string{
+		"https://dev.azure.com/myorg/myproject/_git/foo":       {"https://dev.azure.com/myorg", "myorg"},
+		"https://myorg@dev.azure.com/myorg/myproject/_git/foo": {"https://dev.azure.com/myorg", "myorg"},
+		"git@ssh.dev.azure.com:v3/myorg/myproject/foo":         {"https://dev.azure.com/myorg", "myorg"},
+		"https://myorg.visualstudio.com/myproject/_git/foo":    {"https://myorg.visualstudio.com", "myorg"},
+	}
+	for cloneURL, expected := range testCases {
+		info, err := gits.ParseGitURL(cloneURL)
+		require.NoError(t, err)
+		serverURL := info.HostURLWithoutUser()
+		assert.Equal(t, expected[0], serverURL, "server URL for %s", cloneURL)
+		assert.Equal(t, expected[0], info.HostURL(), "host URL for %s", cloneURL)
+		assert.Equal(t, gits.KindAzureDevOps, gits.SaasGitKind(serverURL), "git kind for %s", cloneURL)
+
+		p, err := gits.CreateProvider(&auth.AuthServer{URL: serverURL, Kind: gits.SaasGitKind(serverURL)}, ua, nil)
+		require.NoError(t, err, "provider for %s", cloneURL)
+		if assert.IsType(t, &gits.AzureDevOpsProvider{}, p) {
+			assert.Equal(t, expected[1], p.(*gits.AzureDevOpsProvider).Organisation, "organisation for %s", cloneURL)
+			assert.Equal(t, expected[0], p.(*gits.AzureDevOpsProvider).BaseURL, "base URL for %s", cloneURL)
+		}
+	}
+}
+
+func TestAzureDevOpsProviderTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping AzureDevOpsProviderTestSuite in short mode")
+	} else {
+		suite.Run(t, new(AzureDevOpsProviderTestSuite))
+	}
+}
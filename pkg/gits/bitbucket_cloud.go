@@ -1,18 +1,23 @@
 package gits
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/pkg/util"
 
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/pkg/errors"
 	"github.com/wbrefvem/go-bitbucket"
 )
 
@@ -21,6 +26,8 @@ type BitbucketCloudProvider struct {
 	Client   *bitbucket.APIClient
 	Username string
 	Context  context.Context
+	// BasePath the URL of the Bitbucket Cloud API for the requests the client does not support
+	BasePath string
 
 	Server auth.AuthServer
 	User   auth.UserAuth
@@ -53,6 +60,7 @@ func NewBitbucketCloudProvider(server *auth.AuthServer, user *auth.UserAuth, git
 
 	cfg := bitbucket.NewConfiguration()
 	provider.Client = bitbucket.NewAPIClient(cfg)
+	provider.BasePath = cfg.BasePath
 
 	return &provider, nil
 }
@@ -404,6 +412,7 @@ func (b *BitbucketCloudProvider) UpdatePullRequestStatus(pr *GitPullRequest) err
 	if bitbucketPR.State == "MERGED" {
 		merged := true
 		pr.Merged = &merged
+		pr.MergedBy = bitbucketCloudMergedBy(&bitbucketPR)
 	}
 
 	commits, _, err := b.Client.PullrequestsApi.RepositoriesUsernameRepoSlugPullrequestsPullRequestIdCommitsGet(
@@ -460,14 +469,34 @@ func (p *BitbucketCloudProvider) GetPullRequest(owner string, repoInfo *GitRepos
 		}
 	}
 
-	return &GitPullRequest{
+	answer := &GitPullRequest{
 		URL:    pr.Links.Html.Href,
 		Owner:  strings.Split(pr.Destination.Repository.FullName, "/")[0],
 		Repo:   pr.Destination.Repository.Name,
 		Number: &number,
 		State:  &pr.State,
 		Author: author,
-	}, nil
+	}
+	if pr.State == "MERGED" {
+		merged := true
+		answer.Merged = &merged
+		answer.MergedBy = bitbucketCloudMergedBy(&pr)
+		if pr.MergeCommit != nil {
+			answer.MergeCommitSHA = &pr.MergeCommit.Hash
+		}
+	}
+	return answer, nil
+}
+
+// bitbucketCloudMergedBy returns the user who merged the Pull Request which is the user who closed it
+func bitbucketCloudMergedBy(pr *bitbucket.Pullrequest) *GitUser {
+	if pr.ClosedBy == nil || pr.ClosedBy.Username == "" {
+		return nil
+	}
+	return &GitUser{
+		Login: pr.ClosedBy.Username,
+		Name:  pr.ClosedBy.DisplayName,
+	}
 }
 
 func (b *BitbucketCloudProvider) GetPullRequestCommits(owner string, repository *GitRepositoryInfo, number int) ([]*GitCommit, error) {
@@ -639,6 +668,54 @@ func (b *BitbucketCloudProvider) ListCommitStatus(org string, repo string, sha s
 	return statuses, nil
 }
 
+// CreateCommitStatus creates a build status on the given commit
+func (b *BitbucketCloudProvider) CreateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	key := status.Context
+	if key == "" {
+		key = "jenkins-x"
+	}
+	commitStatus := bitbucket.Commitstatus{
+		Type_:       "build",
+		Key:         key,
+		Name:        key,
+		Url:         status.TargetURL,
+		State:       bitbucketBuildState(status.State),
+		Description: status.Description,
+	}
+	result, _, err := b.Client.CommitstatusesApi.RepositoriesUsernameRepoSlugCommitNodeStatusesBuildPost(
+		b.Context,
+		org,
+		repo,
+		sha,
+		map[string]interface{}{"body": commitStatus},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepoStatus{
+		ID:          result.Key,
+		Context:     result.Key,
+		URL:         status.TargetURL,
+		State:       stateMap[result.State],
+		TargetURL:   result.Url,
+		Description: result.Description,
+	}, nil
+}
+
+// bitbucketBuildState converts the state of a status into a Bitbucket build state
+func bitbucketBuildState(state string) string {
+	switch state {
+	case "success":
+		return "SUCCESSFUL"
+	case "error", "failure":
+		return "FAILED"
+	case "stopped":
+		return "STOPPED"
+	default:
+		return "INPROGRESS"
+	}
+}
+
 func (b *BitbucketCloudProvider) MergePullRequest(pr *GitPullRequest, message string) error {
 
 	options := map[string]interface{}{
@@ -690,6 +767,37 @@ func (b *BitbucketCloudProvider) CreateWebHook(data *GitWebHookArguments) error
 	return nil
 }
 
+func (b *BitbucketCloudProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error) {
+	hooks, _, err := b.Client.RepositoriesApi.RepositoriesUsernameRepoSlugHooksGet(b.Context, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitWebHookArguments{}
+	for _, hook := range hooks.Values {
+		answer = append(answer, &GitWebHookArguments{
+			ID:    hook.Uuid,
+			Owner: owner,
+			Repo:  &GitRepositoryInfo{Organisation: owner, Name: repo},
+			URL:   hook.Url,
+		})
+	}
+	return answer, nil
+}
+
+func (b *BitbucketCloudProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	// the REST client cannot send the body of a webhook update so lets replace the webhook instead
+	err := b.DeleteWebHook(data.Repo.Organisation, data.Repo.Name, data.ID)
+	if err != nil {
+		return err
+	}
+	return b.CreateWebHook(data)
+}
+
+func (b *BitbucketCloudProvider) DeleteWebHook(owner string, repo string, id string) error {
+	_, err := b.Client.RepositoriesApi.RepositoriesUsernameRepoSlugHooksUidDelete(b.Context, owner, repo, id)
+	return err
+}
+
 func BitbucketIssueToGitIssue(bIssue bitbucket.Issue) *GitIssue {
 	id := int(bIssue.Id)
 	ownerAndRepo := strings.Split(bIssue.Repository.FullName, "/")
@@ -829,6 +937,11 @@ func (b *BitbucketCloudProvider) HasIssues() bool {
 	return true
 }
 
+func (b *BitbucketCloudProvider) ProtectBranch(org string, repo string, protection *GitBranchProtection) error {
+	log.Warn("Bitbucket Cloud does not support branch protection at this moment")
+	return nil
+}
+
 func (b *BitbucketCloudProvider) IsGitHub() bool {
 	return false
 }
@@ -904,19 +1017,60 @@ func (p *BitbucketCloudProvider) ListReleases(org string, name string) ([]*GitRe
 	return answer, nil
 }
 
+// AddCollaborator grants the user write access to the repository
 func (b *BitbucketCloudProvider) AddCollaborator(user string, organisation string, repo string) error {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for bitbucket. Please add user: %v as a collaborator to this project.\n", user)
+	account, _, err := b.Client.UsersApi.UsersUsernameGet(b.Context, user)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the bitbucket user %s", user)
+	}
+	body, err := json.Marshal(map[string]string{"permission": "write"})
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/repositories/%s/%s/permissions-config/users/%s", b.BasePath, url.PathEscape(organisation),
+		url.PathEscape(repo), url.PathEscape(account.Uuid))
+	req, err := http.NewRequest(http.MethodPut, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(b.User.Username, b.User.ApiToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to add user %s as a collaborator to %s/%s", user, organisation, repo)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to add user %s as a collaborator to %s/%s: status %s: %s", user, organisation, repo,
+			resp.Status, string(data))
+	}
+	return nil
+}
+
+func (b *BitbucketCloudProvider) ListCollaborators(organisation string, repo string) ([]*GitCollaborator, error) {
+	log.Warn("Listing the collaborators of a repository is currently not implemented for bitbucket")
+	return []*GitCollaborator{}, nil
+}
+
+func (b *BitbucketCloudProvider) AddOrganisationMember(organisation string, user string) error {
+	log.Infof("Adding members to a team is currently not implemented for bitbucket. Please add user: %v to the team %s.\n", user, organisation)
 	return nil
 }
 
-func (b *BitbucketCloudProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
+func (b *BitbucketCloudProvider) RemoveOrganisationMember(organisation string, user string) error {
+	log.Warnf("Removing members from a team is currently not implemented for bitbucket. Please remove user: %v from the team %s.\n", user, organisation)
+	return nil
+}
+
+func (b *BitbucketCloudProvider) ListInvitations() ([]*GitInvitation, error) {
 	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for bitbucket.\n")
-	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+	return []*GitInvitation{}, nil
 }
 
-func (b *BitbucketCloudProvider) AcceptInvitation(ID int64) (*github.Response, error) {
+func (b *BitbucketCloudProvider) AcceptInvitation(ID string) error {
 	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for bitbucket.\n")
-	return &github.Response{}, nil
+	return nil
 }
 
 func BitBucketCloudAccessTokenURL(url string, username string) string {
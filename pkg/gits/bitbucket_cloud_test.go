@@ -48,6 +48,9 @@ var bitbucketRouter = util.Router{
 	"/repositories/test-org/test-repo/pullrequests/4/": util.MethodMap{
 		"GET": "pullrequests.test-org.test-repo-closed.json",
 	},
+	"/repositories/test-user/test-repo/pullrequests/1/": util.MethodMap{
+		"GET": "pullrequests.test-repo.merged.json",
+	},
 	"/repositories/test-user/test-repo/pullrequests/1/commits": util.MethodMap{
 		"GET": "pullrequests.test-user.test-repo.1.json",
 	},
@@ -60,6 +63,9 @@ var bitbucketRouter = util.Router{
 	"/repositories/test-user/test-repo/commit/5c8afc5/statuses": util.MethodMap{
 		"GET": "repos.test-repo.statuses.json",
 	},
+	"/repositories/test-user/test-repo/commit/5c8afc5/statuses/build": util.MethodMap{
+		"POST": "repos.test-repo.statuses.build.json",
+	},
 	"/repositories/test-user/test-repo/commit/7793466f879b83f1bdd8f3fc3f761bc3cb61bc41": util.MethodMap{
 		"GET": "repos.test-user.test-repo.commits.7793466f879b83f1bdd8f3fc3f761bc3cb61bc41.json",
 	},
@@ -79,6 +85,9 @@ var bitbucketRouter = util.Router{
 	"/repositories/test-user/test-repo/issues/1": util.MethodMap{
 		"GET": "issues.test-repo.issue-1.json",
 	},
+	"/repositories/test-user/test-repo/permissions-config/users/{ba1sfef6-722c-4636-a861-f55ee300cwq8}": util.MethodMap{
+		"PUT": "repos.test-repo.permissions.test-user.json",
+	},
 	"/users/test-user": util.MethodMap{
 		"GET": "users.test-user.json",
 	},
@@ -120,6 +129,7 @@ func (suite *BitbucketCloudProviderTestSuite) SetupSuite() {
 	cfg.BasePath = suite.server.URL
 
 	suite.provider.Client = bitbucket.NewAPIClient(cfg)
+	suite.provider.BasePath = suite.server.URL
 }
 
 func (suite *BitbucketCloudProviderTestSuite) TestListRepositories() {
@@ -288,6 +298,20 @@ func (suite *BitbucketCloudProviderTestSuite) TestGetPullRequest() {
 	suite.Require().Equal(*pr.Number, 3)
 }
 
+func (suite *BitbucketCloudProviderTestSuite) TestGetMergedPullRequest() {
+
+	pr, err := suite.provider.GetPullRequest(
+		"test-user",
+		&gits.GitRepositoryInfo{Name: "test-repo"},
+		1,
+	)
+
+	suite.Require().Nil(err)
+	suite.Require().True(*pr.Merged)
+	suite.Require().NotNil(pr.MergedBy)
+	suite.Require().Equal("test-user", pr.MergedBy.Login)
+}
+
 func (suite *BitbucketCloudProviderTestSuite) TestPullRequestCommits() {
 	commits, err := suite.provider.GetPullRequestCommits("test-user", &gits.GitRepositoryInfo{Name: "test-repo"}, 1)
 
@@ -334,6 +358,21 @@ func (suite *BitbucketCloudProviderTestSuite) TestListCommitStatus() {
 	suite.testStatuses(statuses, err)
 }
 
+func (suite *BitbucketCloudProviderTestSuite) TestCreateCommitStatus() {
+	status, err := suite.provider.CreateCommitStatus("test-user", "test-repo", "5c8afc5", &gits.GitRepoStatus{
+		State:       "success",
+		Context:     "cve-scan",
+		Description: "No vulnerabilities found",
+		TargetURL:   "https://reports.example.com/5c8afc5",
+	})
+
+	suite.Require().Nil(err)
+	suite.Require().NotNil(status)
+	suite.Require().Equal("cve-scan", status.Context)
+	suite.Require().Equal("success", status.State)
+	suite.Require().Equal("https://reports.example.com/5c8afc5", status.TargetURL)
+}
+
 func (suite *BitbucketCloudProviderTestSuite) TestMergePullRequest() {
 
 	id := 1
@@ -411,20 +450,21 @@ func (suite *BitbucketCloudProviderTestSuite) TestUserInfo() {
 }
 
 func (suite *BitbucketCloudProviderTestSuite) TestAddCollaborator() {
-	err := suite.provider.AddCollaborator("derek", orgName, "repo")
+	err := suite.provider.AddCollaborator("test-user", "test-user", "test-repo")
 	suite.Require().Nil(err)
+
+	err = suite.provider.AddCollaborator("derek", orgName, "repo")
+	suite.Require().NotNil(err)
 }
 
 func (suite *BitbucketCloudProviderTestSuite) TestListInvitations() {
-	invites, res, err := suite.provider.ListInvitations()
+	invites, err := suite.provider.ListInvitations()
 	suite.Require().NotNil(invites)
-	suite.Require().NotNil(res)
 	suite.Require().Nil(err)
 }
 
 func (suite *BitbucketCloudProviderTestSuite) TestAcceptInvitations() {
-	res, err := suite.provider.AcceptInvitation(1)
-	suite.Require().NotNil(res)
+	err := suite.provider.AcceptInvitation("1")
 	suite.Require().Nil(err)
 }
 
@@ -1,14 +1,17 @@
 package gits
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/google/go-github/github"
 	"github.com/mitchellh/mapstructure"
 
 	bitbucket "github.com/gfleury/go-bitbucket-v1"
@@ -430,6 +433,32 @@ func getMergeCommitSHAFromPRActivity(prActivity map[string]interface{}) *string
 	return &commitSHA
 }
 
+// getMergedByFromPRActivity returns the user who merged the Pull Request from its activity or nil if not known
+func getMergedByFromPRActivity(prActivity map[string]interface{}) *GitUser {
+	var activity []map[string]interface{}
+	var user map[string]interface{}
+
+	mapstructure.Decode(prActivity["values"], &activity)
+	for _, a := range activity {
+		if a["action"] != "MERGED" {
+			continue
+		}
+		mapstructure.Decode(a["user"], &user)
+		login, _ := user["slug"].(string)
+		if login == "" {
+			return nil
+		}
+		name, _ := user["displayName"].(string)
+		email, _ := user["emailAddress"].(string)
+		return &GitUser{
+			Login: login,
+			Name:  name,
+			Email: email,
+		}
+	}
+	return nil
+}
+
 func getLastCommitSHAFromPRCommits(prCommits map[string]interface{}) string {
 	return getLastCommitFromPRCommits(prCommits).ID
 }
@@ -473,6 +502,7 @@ func (b *BitbucketServerProvider) UpdatePullRequestStatus(pr *GitPullRequest) er
 
 		mapstructure.Decode(apiResponse.Values, &prActivity)
 		pr.MergeCommitSHA = getMergeCommitSHAFromPRActivity(prActivity)
+		pr.MergedBy = getMergedByFromPRActivity(prActivity)
 	}
 	diffURL := bitbucketPR.Links.Self[0].Href + "/diff"
 	pr.DiffURL = &diffURL
@@ -507,14 +537,27 @@ func (b *BitbucketServerProvider) GetPullRequest(owner string, repo *GitReposito
 		Email: bPR.Author.User.Email,
 	}
 
-	return &GitPullRequest{
+	answer := &GitPullRequest{
 		URL:    bPR.Links.Self[0].Href,
 		Owner:  bPR.Author.User.Name,
 		Repo:   bPR.ToRef.Repository.Name,
 		Number: &bPR.ID,
 		State:  &bPR.State,
 		Author: author,
-	}, nil
+	}
+	if bPR.State == "MERGED" {
+		var prActivity map[string]interface{}
+		merged := true
+		answer.Merged = &merged
+		apiResponse, err := b.Client.DefaultApi.GetPullRequestActivity(repo.Project, repo.Name, number)
+		if err != nil {
+			return answer, err
+		}
+		mapstructure.Decode(apiResponse.Values, &prActivity)
+		answer.MergeCommitSHA = getMergeCommitSHAFromPRActivity(prActivity)
+		answer.MergedBy = getMergedByFromPRActivity(prActivity)
+	}
+	return answer, nil
 }
 
 func convertBitBucketCommitToGitCommit(bCommit *bitbucket.Commit, repo *GitRepositoryInfo) *GitCommit {
@@ -632,6 +675,148 @@ func convertBitBucketBuildStatusToGitStatus(buildStatus *bitbucket.BuildStatus)
 	}
 }
 
+// CreateCommitStatus creates a build status on the given commit
+func (b *BitbucketServerProvider) CreateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	key := status.Context
+	if key == "" {
+		key = "jenkins-x"
+	}
+	buildStatus := bitbucket.BuildStatus{
+		State:       bitbucketBuildState(status.State),
+		Key:         key,
+		Name:        key,
+		Url:         status.TargetURL,
+		Description: status.Description,
+	}
+	body := map[string]string{
+		"state":       buildStatus.State,
+		"key":         buildStatus.Key,
+		"name":        buildStatus.Name,
+		"url":         buildStatus.Url,
+		"description": buildStatus.Description,
+	}
+	// the REST client has no operation to create build statuses so lets invoke the build status API directly
+	err := b.doRestRequest(http.MethodPost, "build-status/1.0/commits/"+sha, body, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the build status of commit %s: %s", sha, err)
+	}
+	answer := convertBitBucketBuildStatusToGitStatus(&buildStatus)
+	answer.Context = key
+	answer.State = status.State
+	return answer, nil
+}
+
+// bitbucketServerRestrictions a page of the branch restrictions of a repository
+type bitbucketServerRestrictions struct {
+	Values []struct {
+		ID      int    `json:"id"`
+		Type    string `json:"type"`
+		Matcher struct {
+			ID string `json:"id"`
+		} `json:"matcher"`
+	} `json:"values"`
+}
+
+func (b *BitbucketServerProvider) ProtectBranch(org string, repo string, protection *GitBranchProtection) error {
+	ref := "refs/heads/" + protection.Branch
+	matcher := map[string]interface{}{
+		"id":   ref,
+		"type": map[string]string{"id": "BRANCH"},
+	}
+	restrictions := []string{"no-deletes"}
+	if !protection.AllowForcePushes {
+		restrictions = append(restrictions, "fast-forward-only")
+	}
+	if protection.RequiredApprovingReviews > 0 || len(protection.RequiredStatusContexts) > 0 {
+		restrictions = append(restrictions, "pull-request-only")
+	}
+
+	// the REST client has no operations for branch permissions or merge checks so lets invoke the REST API directly
+	restrictionsPath := fmt.Sprintf("branch-permissions/2.0/projects/%s/repos/%s/restrictions", org, repo)
+	existing := bitbucketServerRestrictions{}
+	err := b.doRestRequest(http.MethodGet, restrictionsPath+"?limit=1000&matcherType=BRANCH&matcherId="+url.QueryEscape(ref), nil, &existing)
+	if err != nil {
+		return fmt.Errorf("Failed to list the restrictions of branch %s: %s", protection.Branch, err)
+	}
+	existingIDs := map[string]int{}
+	for _, restriction := range existing.Values {
+		if restriction.Matcher.ID == ref {
+			existingIDs[restriction.Type] = restriction.ID
+		}
+	}
+
+	log.Infof("Protecting Bitbucket Server branch %s of %s/%s\n", protection.Branch, org, repo)
+	for _, restriction := range restrictions {
+		if _, ok := existingIDs[restriction]; ok {
+			continue
+		}
+		err := b.doRestRequest(http.MethodPost, restrictionsPath, map[string]interface{}{
+			"type":    restriction,
+			"matcher": matcher,
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to add the %s restriction to branch %s: %s", restriction, protection.Branch, err)
+		}
+	}
+	if id, ok := existingIDs["fast-forward-only"]; ok && protection.AllowForcePushes {
+		err := b.doRestRequest(http.MethodDelete, fmt.Sprintf("%s/%d", restrictionsPath, id), nil, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to remove the fast-forward-only restriction of branch %s: %s", protection.Branch, err)
+		}
+	}
+
+	// Bitbucket Server cannot require specific status contexts so lets require the same number of successful builds
+	mergeChecks := map[string]int{}
+	if protection.RequiredApprovingReviews > 0 {
+		mergeChecks["com.atlassian.bitbucket.server.bitbucket-bundled:requiredApprovers"] = protection.RequiredApprovingReviews
+	}
+	if len(protection.RequiredStatusContexts) > 0 {
+		mergeChecks["com.atlassian.bitbucket.server.bitbucket-build:requiredBuildsMergeCheck"] = len(protection.RequiredStatusContexts)
+	}
+	for key, count := range mergeChecks {
+		err := b.doRestRequest(http.MethodPut, fmt.Sprintf("api/1.0/projects/%s/repos/%s/settings/hooks/%s/enabled", org, repo, key), map[string]int{
+			"requiredCount": count,
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to enable the merge check %s: %s", key, err)
+		}
+	}
+	return nil
+}
+
+// doRestRequest invokes the REST API of the server for operations which are not supported by the REST client
+// decoding the response into the result if it is not nil
+func (b *BitbucketServerProvider) doRestRequest(method string, path string, body interface{}, result interface{}) error {
+	requestBody := []byte{}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		requestBody = data
+	}
+	u := util.UrlJoin(b.Server.URL, "rest", path)
+	req, err := http.NewRequest(method, u, bytes.NewReader(requestBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+b.User.ApiToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%s %s failed: %s %s", method, u, resp.Status, string(data))
+	}
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
+
 func (b *BitbucketServerProvider) MergePullRequest(pr *GitPullRequest, message string) error {
 	var currentPR bitbucket.PullRequest
 	projectKey, repo := parseBitBucketServerURL(pr.URL)
@@ -665,27 +850,77 @@ func (b *BitbucketServerProvider) MergePullRequest(pr *GitPullRequest, message s
 func (b *BitbucketServerProvider) CreateWebHook(data *GitWebHookArguments) error {
 	projectKey, repo := parseBitBucketServerURL(data.Repo.URL)
 
-	var options = map[string]interface{}{
+	requestBody, err := json.Marshal(bitbucketServerWebHook(data))
+	if err != nil {
+		return err
+	}
+
+	_, err = b.Client.DefaultApi.CreateWebhook(projectKey, repo, requestBody, []string{"application/json"})
+
+	return err
+}
+
+// bitbucketServerWebHook returns the webhook request body for the given arguments
+func bitbucketServerWebHook(data *GitWebHookArguments) map[string]interface{} {
+	options := map[string]interface{}{
 		"url":    data.URL,
 		"name":   "Jenkins X Web Hook",
 		"active": true,
 		"events": []string{"repo:refs_changed", "repo:modified", "repo:forked", "repo:comment:added", "repo:comment:edited", "repo:comment:deleted", "pr:opened", "pr:reviewer:approved", "pr:reviewer:unapproved", "pr:reviewer:needs_work", "pr:merged", "pr:declined", "pr:deleted", "pr:comment:added", "pr:comment:edited", "pr:comment:deleted"},
 	}
-
 	if data.Secret != "" {
 		options["configuration"] = map[string]interface{}{
 			"secret": data.Secret,
 		}
 	}
+	return options
+}
 
-	requestBody, err := json.Marshal(options)
-	if err != nil {
-		return err
+type bitbucketServerWebHooksPage struct {
+	Size          int  `json:"size"`
+	Start         int  `json:"start"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+	Values        []struct {
+		ID  int    `json:"id"`
+		URL string `json:"url"`
+	} `json:"values"`
+}
+
+func (b *BitbucketServerProvider) ListWebHooks(org string, repo string) ([]*GitWebHookArguments, error) {
+	answer := []*GitWebHookArguments{}
+	start := 0
+	for {
+		page := bitbucketServerWebHooksPage{}
+		path := fmt.Sprintf("api/1.0/projects/%s/repos/%s/webhooks?start=%d", org, repo, start)
+		err := b.doRestRequest(http.MethodGet, path, nil, &page)
+		if err != nil {
+			return answer, err
+		}
+		for _, hook := range page.Values {
+			answer = append(answer, &GitWebHookArguments{
+				ID:    strconv.Itoa(hook.ID),
+				Owner: org,
+				Repo:  &GitRepositoryInfo{Organisation: org, Name: repo},
+				URL:   hook.URL,
+			})
+		}
+		if page.IsLastPage || len(page.Values) == 0 {
+			break
+		}
+		start = page.NextPageStart
 	}
+	return answer, nil
+}
 
-	_, err = b.Client.DefaultApi.CreateWebhook(projectKey, repo, requestBody, []string{"application/json"})
+func (b *BitbucketServerProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	path := fmt.Sprintf("api/1.0/projects/%s/repos/%s/webhooks/%s", data.Owner, data.Repo.Name, data.ID)
+	return b.doRestRequest(http.MethodPut, path, bitbucketServerWebHook(data), nil)
+}
 
-	return err
+func (b *BitbucketServerProvider) DeleteWebHook(org string, repo string, id string) error {
+	path := fmt.Sprintf("api/1.0/projects/%s/repos/%s/webhooks/%s", org, repo, id)
+	return b.doRestRequest(http.MethodDelete, path, nil, nil)
 }
 
 func (b *BitbucketServerProvider) SearchIssues(org string, name string, query string) ([]*GitIssue, error) {
@@ -822,18 +1057,73 @@ func (b *BitbucketServerProvider) ListReleases(org string, name string) ([]*GitR
 }
 
 func (b *BitbucketServerProvider) AddCollaborator(user string, organisation string, repo string) error {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for bitbucket. Please add user: %v as a collaborator to this project.\n", user)
-	return nil
+	log.Infof("Automatically granting the pipeline user: %v write permission on the repository.\n", user)
+	// Bitbucket Server grants permissions directly so there is no invitation to accept
+	path := fmt.Sprintf("api/1.0/projects/%s/repos/%s/permissions/users?name=%s&permission=REPO_WRITE",
+		organisation, repo, url.QueryEscape(user))
+	return b.doRestRequest(http.MethodPut, path, nil, nil)
+}
+
+var bitbucketServerPermissions = map[string]string{
+	"REPO_READ":  GitPermissionRead,
+	"REPO_WRITE": GitPermissionWrite,
+	"REPO_ADMIN": GitPermissionAdmin,
 }
 
-func (b *BitbucketServerProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for bitbucket.\n")
-	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+type bitbucketServerUserPermissionsPage struct {
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+	Values        []struct {
+		User struct {
+			Name string `json:"name"`
+		} `json:"user"`
+		Permission string `json:"permission"`
+	} `json:"values"`
 }
 
-func (b *BitbucketServerProvider) AcceptInvitation(ID int64) (*github.Response, error) {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for bitbucket.\n")
-	return &github.Response{}, nil
+func (b *BitbucketServerProvider) ListCollaborators(organisation string, repo string) ([]*GitCollaborator, error) {
+	answer := []*GitCollaborator{}
+	start := 0
+	for {
+		page := bitbucketServerUserPermissionsPage{}
+		path := fmt.Sprintf("api/1.0/projects/%s/repos/%s/permissions/users?start=%d", organisation, repo, start)
+		err := b.doRestRequest(http.MethodGet, path, nil, &page)
+		if err != nil {
+			return answer, err
+		}
+		for _, value := range page.Values {
+			answer = append(answer, &GitCollaborator{
+				Login:      value.User.Name,
+				Permission: bitbucketServerPermissions[value.Permission],
+			})
+		}
+		if page.IsLastPage || len(page.Values) == 0 {
+			break
+		}
+		start = page.NextPageStart
+	}
+	return answer, nil
+}
+
+func (b *BitbucketServerProvider) AddOrganisationMember(organisation string, user string) error {
+	// Bitbucket Server projects have no members so lets grant write permission on all the repositories of the project
+	path := fmt.Sprintf("api/1.0/projects/%s/permissions/users?name=%s&permission=PROJECT_WRITE",
+		organisation, url.QueryEscape(user))
+	return b.doRestRequest(http.MethodPut, path, nil, nil)
+}
+
+func (b *BitbucketServerProvider) RemoveOrganisationMember(organisation string, user string) error {
+	path := fmt.Sprintf("api/1.0/projects/%s/permissions/users?name=%s", organisation, url.QueryEscape(user))
+	return b.doRestRequest(http.MethodDelete, path, nil, nil)
+}
+
+func (b *BitbucketServerProvider) ListInvitations() ([]*GitInvitation, error) {
+	// Bitbucket Server grants permissions without an invitation
+	return []*GitInvitation{}, nil
+}
+
+func (b *BitbucketServerProvider) AcceptInvitation(ID string) error {
+	return nil
 }
 
 func BitBucketServerAccessTokenURL(url string) string {
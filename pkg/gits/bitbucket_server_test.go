@@ -2,6 +2,7 @@ package gits_test
 
 import (
 	"context"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,6 +11,8 @@ import (
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -56,13 +59,33 @@ var bitbucketServerRouter = util.Router{
 		"POST": "pr-merge-success.json",
 	},
 	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/webhooks": util.MethodMap{
+		"GET":  "webhooks.json",
 		"POST": "webhook.json",
 	},
+	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/webhooks/14": util.MethodMap{
+		"PUT":    "webhook.json",
+		"DELETE": "webhook.json",
+	},
+	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/permissions/users": util.MethodMap{
+		"GET": "user-permissions.json",
+		"PUT": "user-permissions.json",
+	},
 	"/rest/api/1.0/users/test-user": util.MethodMap{
 		"GET": "user.json",
 	},
 	"/rest/build-status/1.0/commits/d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c": util.MethodMap{
-		"GET": "build-statuses.json",
+		"GET":  "build-statuses.json",
+		"POST": "build-status.json",
+	},
+	"/rest/branch-permissions/2.0/projects/TEST-ORG/repos/test-repo/restrictions": util.MethodMap{
+		"GET":  "branch-restrictions.json",
+		"POST": "branch-restriction.json",
+	},
+	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/settings/hooks/com.atlassian.bitbucket.server.bitbucket-bundled:requiredApprovers/enabled": util.MethodMap{
+		"PUT": "repo-hook-settings.json",
+	},
+	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/settings/hooks/com.atlassian.bitbucket.server.bitbucket-build:requiredBuildsMergeCheck/enabled": util.MethodMap{
+		"PUT": "repo-hook-settings.json",
 	},
 }
 
@@ -103,6 +126,7 @@ func (suite *BitbucketServerProviderTestSuite) SetupSuite() {
 
 	apiKeyAuthContext := context.WithValue(ctx, bitbucket.ContextAccessToken, ua.ApiToken)
 	suite.provider.Client = bitbucket.NewAPIClient(apiKeyAuthContext, cfg)
+	suite.provider.Server.URL = suite.server.URL
 }
 
 func (suite *BitbucketServerProviderTestSuite) TestGetRepository() {
@@ -266,6 +290,60 @@ func (suite *BitbucketServerProviderTestSuite) TestListCommitStatuses() {
 	}
 }
 
+func (suite *BitbucketServerProviderTestSuite) TestCreateCommitStatus() {
+	status, err := suite.provider.CreateCommitStatus("TEST-ORG", "test-repo", "d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c", &gits.GitRepoStatus{
+		State:       "success",
+		Context:     "cve-scan",
+		Description: "No vulnerabilities found",
+		TargetURL:   "https://reports.example.com/d6f24ee",
+	})
+	suite.Require().Nil(err)
+	suite.Require().NotNil(status)
+	suite.Require().Equal("cve-scan", status.Context)
+	suite.Require().Equal("success", status.State)
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestProtectBranch() {
+	err := suite.provider.ProtectBranch("TEST-ORG", "test-repo", &gits.GitBranchProtection{
+		Branch:                   "master",
+		RequiredStatusContexts:   []string{"continuous-integration/jenkins/pr-merge"},
+		RequiredApprovingReviews: 1,
+	})
+	suite.Require().Nil(err)
+}
+
+func TestBitbucketServerProtectBranchOnlyAddsMissingRestrictions(t *testing.T) {
+	t.Parallel()
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodGet {
+			data, err := ioutil.ReadFile("test_data/bitbucket_server/branch-restrictions.json")
+			require.NoError(t, err)
+			w.Write(data)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	provider := &gits.BitbucketServerProvider{
+		Server: auth.AuthServer{URL: server.URL},
+		User:   auth.UserAuth{Username: "test-user", ApiToken: "0123456789abdef"},
+	}
+	restrictions := "/rest/branch-permissions/2.0/projects/TEST-ORG/repos/test-repo/restrictions"
+
+	err := provider.ProtectBranch("TEST-ORG", "test-repo", &gits.GitBranchProtection{Branch: "master"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET " + restrictions, "POST " + restrictions}, requests, "only the missing no-deletes restriction should be added")
+
+	requests = []string{}
+	err = provider.ProtectBranch("TEST-ORG", "test-repo", &gits.GitBranchProtection{Branch: "master", AllowForcePushes: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET " + restrictions, "POST " + restrictions, "DELETE " + restrictions + "/1"}, requests,
+		"the fast-forward-only restriction should be removed to allow force pushes")
+}
+
 func (suite *BitbucketServerProviderTestSuite) TestMergePullRequest() {
 
 	id := 1
@@ -291,6 +369,37 @@ func (suite *BitbucketServerProviderTestSuite) TestCreateWebHook() {
 	suite.Require().Nil(err)
 }
 
+func (suite *BitbucketServerProviderTestSuite) TestListWebHooks() {
+
+	hooks, err := suite.provider.ListWebHooks("TEST-ORG", "test-repo")
+
+	suite.Require().Nil(err)
+	suite.Require().Len(hooks, 1)
+	suite.Require().Equal("14", hooks[0].ID)
+	suite.Require().Equal("https://docs.atlassian.com/jira/REST/schema/rest-webhook#", hooks[0].URL)
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestUpdateWebHook() {
+
+	data := &gits.GitWebHookArguments{
+		ID:     "14",
+		Owner:  "TEST-ORG",
+		Repo:   &gits.GitRepositoryInfo{Organisation: "TEST-ORG", Name: "test-repo"},
+		URL:    "https://my-jenkins.example.com/bitbucket-webhook/",
+		Secret: "someSecret",
+	}
+	err := suite.provider.UpdateWebHook(data)
+
+	suite.Require().Nil(err)
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestDeleteWebHook() {
+
+	err := suite.provider.DeleteWebHook("TEST-ORG", "test-repo", "14")
+
+	suite.Require().Nil(err)
+}
+
 func (suite *BitbucketServerProviderTestSuite) TestUserInfo() {
 
 	userInfo := suite.provider.UserInfo("test-user")
@@ -304,20 +413,26 @@ func (suite *BitbucketServerProviderTestSuite) TestUserInfo() {
 }
 
 func (suite *BitbucketServerProviderTestSuite) TestAddCollaborator() {
-	err := suite.provider.AddCollaborator("derek", orgname, "repo")
+	err := suite.provider.AddCollaborator("derek", "TEST-ORG", "test-repo")
+	suite.Require().Nil(err)
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestListCollaborators() {
+	collaborators, err := suite.provider.ListCollaborators("TEST-ORG", "test-repo")
 	suite.Require().Nil(err)
+	suite.Require().Len(collaborators, 2)
+	suite.Require().Equal(gits.GitCollaborator{Login: "test-user", Permission: gits.GitPermissionAdmin}, *collaborators[0])
+	suite.Require().Equal(gits.GitCollaborator{Login: "derek", Permission: gits.GitPermissionWrite}, *collaborators[1])
 }
 
 func (suite *BitbucketServerProviderTestSuite) TestListInvitations() {
-	invites, res, err := suite.provider.ListInvitations()
+	invites, err := suite.provider.ListInvitations()
 	suite.Require().NotNil(invites)
-	suite.Require().NotNil(res)
 	suite.Require().Nil(err)
 }
 
 func (suite *BitbucketServerProviderTestSuite) TestAcceptInvitations() {
-	res, err := suite.provider.AcceptInvitation(1)
-	suite.Require().NotNil(res)
+	err := suite.provider.AcceptInvitation("1")
 	suite.Require().Nil(err)
 }
 
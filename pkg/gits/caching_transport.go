@@ -0,0 +1,149 @@
+package gits
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gregjones/httpcache"
+	"github.com/gregjones/httpcache/diskcache"
+	"github.com/jenkins-x/jx/pkg/log"
+)
+
+const (
+	defaultMaxRateLimitWait = 5 * time.Minute
+	maxRateLimitRetries     = 3
+)
+
+// NewCachingTransport wraps the HTTP transport so that GET responses are cached in the directory, or in memory if it is
+// blank, and are revalidated on every request with conditional requests using their ETag or Last-Modified headers.
+// Requests rejected by the rate limit of the git server are retried once the limit resets if that is within maxWait
+func NewCachingTransport(transport http.RoundTripper, cacheDir string, maxWait time.Duration, metrics *GitProviderMetrics) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if maxWait <= 0 {
+		maxWait = defaultMaxRateLimitWait
+	}
+	if metrics == nil {
+		metrics = NewGitProviderMetrics()
+	}
+	var cache httpcache.Cache = httpcache.NewMemoryCache()
+	if cacheDir != "" {
+		cache = diskcache.New(cacheDir)
+	}
+	return &revalidatingTransport{
+		transport: &httpcache.Transport{
+			Transport: &rateLimitTransport{
+				transport: transport,
+				maxWait:   maxWait,
+				metrics:   metrics,
+			},
+			Cache:               cache,
+			MarkCachedResponses: true,
+		},
+	}
+}
+
+// revalidatingTransport makes the HTTP cache revalidate cached responses rather than trusting the max-age of the
+// git server so that changes such as merged Pull Requests are seen straight away
+type revalidatingTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *revalidatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet && req.Header.Get("Cache-Control") == "" {
+		req2 := new(http.Request)
+		*req2 = *req
+		req2.Header = make(http.Header, len(req.Header)+1)
+		for k, v := range req.Header {
+			req2.Header[k] = append([]string{}, v...)
+		}
+		req2.Header.Set("Cache-Control", "max-age=0")
+		req = req2
+	}
+	return t.transport.RoundTrip(req)
+}
+
+// rateLimitTransport records the API usage and retries requests rejected by the rate limit of the git server
+type rateLimitTransport struct {
+	transport http.RoundTripper
+	maxWait   time.Duration
+	metrics   *GitProviderMetrics
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for i := 0; ; i++ {
+		atomic.AddInt64(&t.metrics.Requests, 1)
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		if resp.StatusCode == http.StatusNotModified {
+			atomic.AddInt64(&t.metrics.NotModified, 1)
+		}
+		if remaining := rateLimitHeader(resp, "Remaining"); remaining != "" {
+			value, err := strconv.ParseInt(remaining, 10, 64)
+			if err == nil {
+				atomic.StoreInt64(&t.metrics.RateLimitRemaining, value)
+			}
+		}
+		wait, limited := rateLimitWait(resp, time.Now())
+		if !limited {
+			return resp, nil
+		}
+		atomic.AddInt64(&t.metrics.RateLimited, 1)
+		if i >= maxRateLimitRetries || wait > t.maxWait || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		log.Warnf("Rate limited by the git server on %s %s so retrying in %s\n", req.Method, req.URL.Path, wait.String())
+		resp.Body.Close()
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		time.Sleep(wait)
+	}
+}
+
+// rateLimitHeader returns the rate limit header with the given suffix using the GitHub or GitLab naming
+func rateLimitHeader(resp *http.Response, suffix string) string {
+	value := resp.Header.Get("X-RateLimit-" + suffix)
+	if value == "" {
+		value = resp.Header.Get("RateLimit-" + suffix)
+	}
+	return value
+}
+
+// rateLimitWait returns how long to wait before retrying if the response was rejected by the rate limit of the git server
+func rateLimitWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	retryAfter := resp.Header.Get("Retry-After")
+	if retryAfter != "" {
+		seconds, err := strconv.Atoi(retryAfter)
+		if err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+	if rateLimitHeader(resp, "Remaining") == "0" {
+		reset, err := strconv.ParseInt(rateLimitHeader(resp, "Reset"), 10, 64)
+		if err != nil {
+			return time.Minute, true
+		}
+		wait := time.Unix(reset, 0).Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return time.Second, true
+	}
+	return 0, false
+}
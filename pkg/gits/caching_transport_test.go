@@ -0,0 +1,71 @@
+package gits_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachingTransportRevalidatesWithETag(t *testing.T) {
+	t.Parallel()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Cache-Control", "private, max-age=60")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(5000-requests))
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"merged": false}`))
+	}))
+	defer server.Close()
+
+	metrics := gits.NewGitProviderMetrics()
+	client := &http.Client{Transport: gits.NewCachingTransport(nil, "", time.Minute, metrics)}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL + "/repos/myorg/myrepo/pulls/1")
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `{"merged": false}`, string(body))
+	}
+	assert.Equal(t, 2, requests, "cached responses should be revalidated")
+	assert.Equal(t, int64(1), metrics.NotModified)
+	assert.Equal(t, int64(4998), metrics.RateLimitRemaining)
+}
+
+func TestCachingTransportRetriesWhenRateLimited(t *testing.T) {
+	t.Parallel()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	metrics := gits.NewGitProviderMetrics()
+	client := &http.Client{Transport: gits.NewCachingTransport(nil, "", time.Minute, metrics)}
+	resp, err := client.Get(server.URL + "/user")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(1), metrics.RateLimited)
+	assert.Equal(t, int64(2), metrics.Requests)
+}
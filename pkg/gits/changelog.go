@@ -0,0 +1,363 @@
+package gits
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// ChangelogFormatMarkdown renders the changelog as markdown
+	ChangelogFormatMarkdown = "markdown"
+	// ChangelogFormatHTML renders the changelog as HTML
+	ChangelogFormatHTML = "html"
+	// ChangelogFormatAsciiDoc renders the changelog as AsciiDoc
+	ChangelogFormatAsciiDoc = "asciidoc"
+	// ChangelogFormatJSON renders the changelog data as JSON
+	ChangelogFormatJSON = "json"
+
+	otherChangesTitle = "Other Changes"
+)
+
+// ChangelogFormats the built in changelog formats
+var ChangelogFormats = []string{ChangelogFormatMarkdown, ChangelogFormatHTML, ChangelogFormatAsciiDoc, ChangelogFormatJSON}
+
+// CommitGroupRule defines how commits are grouped in a changelog. A commit belongs to the first rule
+// which either lists the commit's conventional commit kind or whose pattern matches the commit message
+type CommitGroupRule struct {
+	Title   string
+	Kinds   []string
+	Pattern string
+
+	regex *regexp.Regexp
+}
+
+// ChangelogCommit a commit in a changelog along with its parsed conventional commit information
+type ChangelogCommit struct {
+	Title  string             `json:"title"`
+	Info   *CommitInfo        `json:"info"`
+	Commit *v1.CommitSummary  `json:"commit"`
+	Issues []*v1.IssueSummary `json:"issues,omitempty"`
+}
+
+// ChangelogGroup a titled group of commits in a changelog
+type ChangelogGroup struct {
+	Title   string             `json:"title"`
+	Order   int                `json:"order"`
+	Commits []*ChangelogCommit `json:"commits"`
+}
+
+// ChangelogData is the data model used to render a changelog via a go template
+type ChangelogData struct {
+	Release      *v1.ReleaseSpec    `json:"release"`
+	GitInfo      *GitRepositoryInfo `json:"gitInfo,omitempty"`
+	Groups       []*ChangelogGroup  `json:"groups"`
+	Issues       []v1.IssueSummary  `json:"issues,omitempty"`
+	PullRequests []v1.IssueSummary  `json:"pullRequests,omitempty"`
+}
+
+// IsEmpty returns true if there are no commits, issues or pull requests in the changelog
+func (d *ChangelogData) IsEmpty() bool {
+	return len(d.Groups) == 0 && len(d.Issues) == 0 && len(d.PullRequests) == 0
+}
+
+// NewChangelogData creates the changelog data model for the given release grouping the commits
+// using the given rules. If no rules are specified the Conventional Commit types are used
+func NewChangelogData(releaseSpec *v1.ReleaseSpec, gitInfo *GitRepositoryInfo, rules []*CommitGroupRule) (*ChangelogData, error) {
+	for _, rule := range rules {
+		if rule.Pattern != "" && rule.regex == nil {
+			regex, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid changelog group pattern %s for group %s: %s", rule.Pattern, rule.Title, err)
+			}
+			rule.regex = regex
+		}
+	}
+	issueMap := map[string]*v1.IssueSummary{}
+	for i := range releaseSpec.Issues {
+		issue := &releaseSpec.Issues[i]
+		issueMap[issue.ID] = issue
+	}
+	for i := range releaseSpec.PullRequests {
+		pr := &releaseSpec.PullRequests[i]
+		issueMap[pr.ID] = pr
+	}
+
+	groupMap := map[string]*ChangelogGroup{}
+	for i := range releaseSpec.Commits {
+		cs := &releaseSpec.Commits[i]
+		if cs.Message == "" {
+			continue
+		}
+		ci := ParseCommit(cs.Message)
+		title, order := commitGroupFor(ci, cs.Message, rules)
+		group := groupMap[title]
+		if group == nil {
+			group = &ChangelogGroup{
+				Title: title,
+				Order: order,
+			}
+			groupMap[title] = group
+		}
+		commit := &ChangelogCommit{
+			Title:  strings.Split(strings.TrimSpace(ci.Message), "\n")[0],
+			Info:   ci,
+			Commit: cs,
+		}
+		for _, id := range cs.IssueIDs {
+			issue := issueMap[id]
+			if issue != nil {
+				commit.Issues = append(commit.Issues, issue)
+			}
+		}
+		group.Commits = append(group.Commits, commit)
+	}
+
+	answer := &ChangelogData{
+		Release:      releaseSpec,
+		GitInfo:      gitInfo,
+		Groups:       []*ChangelogGroup{},
+		Issues:       releaseSpec.Issues,
+		PullRequests: releaseSpec.PullRequests,
+	}
+	for _, group := range groupMap {
+		answer.Groups = append(answer.Groups, group)
+	}
+	sort.SliceStable(answer.Groups, func(i, j int) bool {
+		return answer.Groups[i].Order < answer.Groups[j].Order
+	})
+	if len(answer.Groups) > 1 {
+		for _, group := range answer.Groups {
+			if group.Title == "" {
+				group.Title = otherChangesTitle
+			}
+		}
+	}
+	return answer, nil
+}
+
+func commitGroupFor(ci *CommitInfo, message string, rules []*CommitGroupRule) (string, int) {
+	if len(rules) == 0 {
+		group := ConventionalCommitTypeToTitle(ci.Kind)
+		return group.Title, group.Order
+	}
+	kind := strings.ToLower(ci.Kind)
+	for i, rule := range rules {
+		for _, k := range rule.Kinds {
+			if strings.ToLower(k) == kind && kind != "" {
+				return rule.Title, i
+			}
+		}
+		if rule.regex != nil && rule.regex.MatchString(message) {
+			return rule.Title, i
+		}
+	}
+	return otherChangesTitle, len(rules)
+}
+
+// RenderChangelog renders the changelog data using the given go template text. If no template text is
+// specified then the built in template for the given format is used. HTML is rendered via html/template so
+// that the commit messages, issue titles and user names are escaped
+func RenderChangelog(format string, templateText string, data *ChangelogData) (string, error) {
+	if format == ChangelogFormatHTML {
+		if templateText == "" {
+			return renderHTMLTemplate(ChangelogFormatHTML, htmlChangelogTemplate, data)
+		}
+		return renderHTMLTemplate("changelog", templateText, data)
+	}
+	if templateText != "" {
+		return renderTextTemplate("changelog", templateText, data)
+	}
+	switch format {
+	case "", ChangelogFormatMarkdown:
+		return renderTextTemplate(ChangelogFormatMarkdown, markdownChangelogTemplate, data)
+	case ChangelogFormatAsciiDoc:
+		return renderTextTemplate(ChangelogFormatAsciiDoc, asciiDocChangelogTemplate, data)
+	case ChangelogFormatJSON:
+		output, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(output) + "\n", nil
+	default:
+		return "", util.InvalidArg(format, ChangelogFormats)
+	}
+}
+
+func renderTextTemplate(name string, templateText string, data *ChangelogData) (string, error) {
+	tmpl, err := template.New(name).Funcs(template.FuncMap(changelogFuncMap(data))).Parse(templateText)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse changelog template %s: %s", name, err)
+	}
+	var buffer bytes.Buffer
+	writer := bufio.NewWriter(&buffer)
+	err = tmpl.Execute(writer, data)
+	writer.Flush()
+	return buffer.String(), err
+}
+
+func renderHTMLTemplate(name string, templateText string, data *ChangelogData) (string, error) {
+	tmpl, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap(changelogFuncMap(data))).Parse(templateText)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse changelog template %s: %s", name, err)
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, data)
+	return buffer.String(), err
+}
+
+func changelogFuncMap(data *ChangelogData) map[string]interface{} {
+	return map[string]interface{}{
+		"shortSha": func(sha string) string {
+			if len(sha) > 7 {
+				return sha[0:7]
+			}
+			return sha
+		},
+		"userURL": func(user *v1.UserDetails) string {
+			if user == nil {
+				return ""
+			}
+			if user.URL == "" && user.Login != "" && data.GitInfo != nil {
+				return util.UrlJoin(data.GitInfo.HostURL(), user.Login)
+			}
+			return user.URL
+		},
+		"userName": func(user *v1.UserDetails) string {
+			if user == nil {
+				return ""
+			}
+			if user.Login != "" {
+				return user.Login
+			}
+			return user.Name
+		},
+		"issueLabel": issueLabel,
+	}
+}
+
+// UpdateChangelogFile adds the markdown changelog for the given version to the top of the given file,
+// replacing any previous section for the same version. Headings in the markdown are nested under the
+// version heading
+func UpdateChangelogFile(fileName string, version string, markdown string) error {
+	exists, err := util.FileExists(fileName)
+	if err != nil {
+		return err
+	}
+	text := "# Changelog\n"
+	if exists {
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return fmt.Errorf("Failed to load file %s due to %s", fileName, err)
+		}
+		text = string(data)
+	}
+	heading := "## " + version
+
+	lines := []string{heading, ""}
+	for _, line := range strings.Split(strings.TrimSpace(markdown), "\n") {
+		if strings.HasPrefix(line, "#") {
+			line = "#" + line
+		}
+		lines = append(lines, line)
+	}
+	section := strings.Join(lines, "\n") + "\n"
+
+	// lets remove any previous section for this version
+	existing := strings.Split(text, "\n")
+	remaining := []string{}
+	insertAt := -1
+	skip := false
+	for _, line := range existing {
+		if strings.HasPrefix(line, "## ") {
+			skip = strings.TrimSpace(line) == heading
+			if insertAt < 0 {
+				insertAt = len(remaining)
+			}
+		}
+		if !skip {
+			remaining = append(remaining, line)
+		}
+	}
+	if insertAt < 0 {
+		insertAt = len(remaining)
+		for i, line := range remaining {
+			if strings.HasPrefix(line, "# ") {
+				insertAt = i + 1
+				break
+			}
+			if strings.TrimSpace(line) != "" {
+				insertAt = i
+				break
+			}
+		}
+	}
+	before := strings.TrimRight(strings.Join(remaining[0:insertAt], "\n"), "\n")
+	after := strings.TrimLeft(strings.Join(remaining[insertAt:], "\n"), "\n")
+	answer := section
+	if before != "" {
+		answer = before + "\n\n" + answer
+	}
+	if after != "" {
+		answer += "\n" + after
+	}
+	return ioutil.WriteFile(fileName, []byte(answer), util.DefaultWritePermissions)
+}
+
+const markdownChangelogTemplate = `{{- if not .IsEmpty -}}
+## Changes
+{{ range .Groups }}
+{{ if .Title }}### {{ .Title }}
+
+{{ end }}{{ range .Commits }}* {{ if .Info.Feature }}{{ .Info.Feature }}: {{ end }}{{ .Title }}{{ with .Commit.Author }}{{ if userURL . }} ([{{ userName . }}]({{ userURL . }})){{ else if userName . }} ({{ userName . }}){{ end }}{{ end }}{{ range .Issues }} [{{ issueLabel . }}]({{ .URL }}){{ end }}
+{{ end }}{{ end }}{{ if .Issues }}
+### Issues
+
+{{ range .Issues }}* [{{ issueLabel . }}]({{ .URL }}) {{ .Title }}
+{{ end }}{{ end }}{{ if .PullRequests }}
+### Pull Requests
+
+{{ range .PullRequests }}* [{{ issueLabel . }}]({{ .URL }}) {{ .Title }}
+{{ end }}{{ end }}{{ end }}`
+
+const asciiDocChangelogTemplate = `{{- if not .IsEmpty -}}
+== Changes
+{{ range .Groups }}
+{{ if .Title }}=== {{ .Title }}
+
+{{ end }}{{ range .Commits }}* {{ if .Info.Feature }}{{ .Info.Feature }}: {{ end }}{{ .Title }}{{ with .Commit.Author }}{{ if userURL . }} ({{ userURL . }}[{{ userName . }}]){{ else if userName . }} ({{ userName . }}){{ end }}{{ end }}{{ range .Issues }} {{ .URL }}[{{ issueLabel . }}]{{ end }}
+{{ end }}{{ end }}{{ if .Issues }}
+=== Issues
+
+{{ range .Issues }}* {{ .URL }}[{{ issueLabel . }}] {{ .Title }}
+{{ end }}{{ end }}{{ if .PullRequests }}
+=== Pull Requests
+
+{{ range .PullRequests }}* {{ .URL }}[{{ issueLabel . }}] {{ .Title }}
+{{ end }}{{ end }}{{ end }}`
+
+const htmlChangelogTemplate = `{{- if not .IsEmpty -}}
+<h2>Changes</h2>
+{{ range .Groups }}{{ if .Title }}<h3>{{ .Title }}</h3>
+{{ end }}<ul>
+{{ range .Commits }}<li>{{ if .Info.Feature }}{{ .Info.Feature }}: {{ end }}{{ .Title }}{{ with .Commit.Author }}{{ if userURL . }} (<a href="{{ userURL . }}">{{ userName . }}</a>){{ else if userName . }} ({{ userName . }}){{ end }}{{ end }}{{ range .Issues }} <a href="{{ .URL }}">{{ issueLabel . }}</a>{{ end }}</li>
+{{ end }}</ul>
+{{ end }}{{ if .Issues }}<h3>Issues</h3>
+<ul>
+{{ range .Issues }}<li><a href="{{ .URL }}">{{ issueLabel . }}</a> {{ .Title }}</li>
+{{ end }}</ul>
+{{ end }}{{ if .PullRequests }}<h3>Pull Requests</h3>
+<ul>
+{{ range .PullRequests }}<li><a href="{{ .URL }}">{{ issueLabel . }}</a> {{ .Title }}</li>
+{{ end }}</ul>
+{{ end }}{{ end }}`
@@ -0,0 +1,194 @@
+package gits_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestReleaseSpec() *v1.ReleaseSpec {
+	return &v1.ReleaseSpec{
+		Version: "1.2.3",
+		Commits: []v1.CommitSummary{
+			{
+				Message:  "fix: some commit 1\nfixes #123",
+				SHA:      "1234567890",
+				IssueIDs: []string{"123"},
+				Author: &v1.UserDetails{
+					Name:  "James Strachan",
+					Login: "jstrachan",
+				},
+			},
+			{
+				Message: "feat:(cheese) some commit 2",
+				SHA:     "4567890123",
+				Author: &v1.UserDetails{
+					Name:  "James Rawlings",
+					Login: "rawlingsj",
+				},
+			},
+			{
+				Message: "upgrade CVE-2018-1234 dependency",
+				SHA:     "7890123456",
+				Author: &v1.UserDetails{
+					Name: "Someone Else",
+				},
+			},
+		},
+		Issues: []v1.IssueSummary{
+			{
+				ID:    "123",
+				URL:   "https://github.com/jstrachan/foo/issues/123",
+				Title: "something broke",
+			},
+		},
+	}
+}
+
+func createTestGitInfo() *gits.GitRepositoryInfo {
+	return &gits.GitRepositoryInfo{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+}
+
+func TestChangelogDataConventionalCommitGroups(t *testing.T) {
+	t.Parallel()
+	data, err := gits.NewChangelogData(createTestReleaseSpec(), createTestGitInfo(), nil)
+	require.NoError(t, err)
+
+	require.Len(t, data.Groups, 3)
+	assert.Equal(t, "New Features", data.Groups[0].Title)
+	assert.Equal(t, "Bug Fixes", data.Groups[1].Title)
+	assert.Equal(t, "Other Changes", data.Groups[2].Title)
+
+	fix := data.Groups[1].Commits[0]
+	assert.Equal(t, "some commit 1", fix.Title)
+	require.Len(t, fix.Issues, 1)
+	assert.Equal(t, "something broke", fix.Issues[0].Title)
+	assert.Equal(t, "cheese", data.Groups[0].Commits[0].Info.Feature)
+}
+
+func TestChangelogDataCustomGroups(t *testing.T) {
+	t.Parallel()
+	rules := []*gits.CommitGroupRule{
+		{
+			Title:   "Security",
+			Pattern: "CVE-\\d+",
+		},
+		{
+			Title: "Changes",
+			Kinds: []string{"feat", "fix"},
+		},
+	}
+	data, err := gits.NewChangelogData(createTestReleaseSpec(), createTestGitInfo(), rules)
+	require.NoError(t, err)
+
+	require.Len(t, data.Groups, 2)
+	assert.Equal(t, "Security", data.Groups[0].Title)
+	assert.Len(t, data.Groups[0].Commits, 1)
+	assert.Equal(t, "Changes", data.Groups[1].Title)
+	assert.Len(t, data.Groups[1].Commits, 2)
+
+	_, err = gits.NewChangelogData(createTestReleaseSpec(), createTestGitInfo(), []*gits.CommitGroupRule{{Title: "Bad", Pattern: "("}})
+	assert.Error(t, err)
+}
+
+func TestRenderChangelogFormats(t *testing.T) {
+	t.Parallel()
+	rules := []*gits.CommitGroupRule{
+		{
+			Title: "Features",
+			Kinds: []string{"feat"},
+		},
+	}
+	data, err := gits.NewChangelogData(createTestReleaseSpec(), createTestGitInfo(), rules)
+	require.NoError(t, err)
+
+	markdown, err := gits.RenderChangelog(gits.ChangelogFormatMarkdown, "", data)
+	require.NoError(t, err)
+	expectedMarkdown := `## Changes
+
+### Features
+
+* cheese: some commit 2 ([rawlingsj](https://github.com/rawlingsj))
+
+### Other Changes
+
+* some commit 1 ([jstrachan](https://github.com/jstrachan)) [#123](https://github.com/jstrachan/foo/issues/123)
+* upgrade CVE-2018-1234 dependency (Someone Else)
+
+### Issues
+
+* [#123](https://github.com/jstrachan/foo/issues/123) something broke
+`
+	assert.Equal(t, expectedMarkdown, markdown)
+
+	html, err := gits.RenderChangelog(gits.ChangelogFormatHTML, "", data)
+	require.NoError(t, err)
+	assert.Contains(t, html, "<h3>Features</h3>")
+	assert.Contains(t, html, `<a href="https://github.com/rawlingsj">rawlingsj</a>`)
+
+	asciidoc, err := gits.RenderChangelog(gits.ChangelogFormatAsciiDoc, "", data)
+	require.NoError(t, err)
+	assert.Contains(t, asciidoc, "=== Features")
+	assert.Contains(t, asciidoc, "https://github.com/jstrachan/foo/issues/123[#123] something broke")
+
+	text, err := gits.RenderChangelog(gits.ChangelogFormatJSON, "", data)
+	require.NoError(t, err)
+	copy := &gits.ChangelogData{}
+	err = json.Unmarshal([]byte(text), copy)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", copy.Release.Version)
+	assert.Len(t, copy.Groups, 2)
+
+	custom, err := gits.RenderChangelog("", `{{ .Release.Version }}:{{ range .Groups }} {{ .Title }}={{ len .Commits }}{{ end }}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3: Features=1 Other Changes=2", custom)
+
+	customHTML, err := gits.RenderChangelog(gits.ChangelogFormatHTML, `<p>{{ .Release.Version }} {{ "<script>" }}</p>`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>1.2.3 &lt;script&gt;</p>", customHTML)
+
+	_, err = gits.RenderChangelog("pdf", "", data)
+	assert.Error(t, err)
+}
+
+func TestUpdateChangelogFile(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-changelog-file")
+	require.NoError(t, err)
+	fileName := filepath.Join(dir, "CHANGELOG.md")
+
+	err = gits.UpdateChangelogFile(fileName, "1.0.0", "## Changes\n\n* first\n")
+	require.NoError(t, err)
+	err = gits.UpdateChangelogFile(fileName, "1.0.1", "## Changes\n\n* second\n")
+	require.NoError(t, err)
+	err = gits.UpdateChangelogFile(fileName, "1.0.1", "## Changes\n\n* second again\n")
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(fileName)
+	require.NoError(t, err)
+	expected := `# Changelog
+
+## 1.0.1
+
+### Changes
+
+* second again
+
+## 1.0.0
+
+### Changes
+
+* first
+`
+	assert.Equal(t, expected, string(data))
+}
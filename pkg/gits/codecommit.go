@@ -0,0 +1,713 @@
+package gits
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/jenkins-x/jx/pkg/cloud/amazon/codecommit"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// CodeCommitWebHookTrigger the name of the repository trigger which publishes the repository events to the
+	// SNS topic the webhooks subscribe to
+	CodeCommitWebHookTrigger = "jenkins-x"
+	// CodeCommitTopicPrefix the prefix of the names of the SNS topics the webhook triggers publish to
+	CodeCommitTopicPrefix = "jx-codecommit-"
+
+	// maxCodeCommitPullRequestCommits the number of commits GetPullRequestCommits walks back through
+	maxCodeCommitPullRequestCommits = 250
+)
+
+var (
+	codeCommitHostRegex      = regexp.MustCompile(`^git-codecommit\.([a-z0-9-]+)\.amazonaws\.com(\.cn)?$`)
+	codeCommitTopicNameRegex = regexp.MustCompile("[^a-zA-Z0-9_-]")
+)
+
+// CodeCommitProvider implements GitProvider interface for AWS CodeCommit.
+//
+// CodeCommit repositories belong to an AWS account and region rather than an organisation so the region is used as
+// the organisation of the repositories. The API is invoked with the AWS credentials of the session while the user
+// auth holds the HTTPS git credentials used to clone and push.
+//
+// CodeCommit has no webhooks. Instead a repository trigger publishes the events of the repository to an SNS topic
+// and each webhook is an HTTP subscription to the topic.
+type CodeCommitProvider struct {
+	CodeCommit    *codecommit.Client
+	Notifications *codecommit.Notifications
+	Region        string
+	Username      string
+
+	Server auth.AuthServer
+	User   auth.UserAuth
+	Git    Gitter
+}
+
+// NewCodeCommitProvider creates a new git provider for AWS CodeCommit using the AWS session of the region of the
+// server URL, e.g. https://git-codecommit.us-east-1.amazonaws.com
+func NewCodeCommitProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter) (GitProvider, error) {
+	region := CodeCommitRegion(server.URL)
+	sess, err := amazon.NewAwsSession("", region)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the AWS session for CodeCommit: %s", err)
+	}
+	if region == "" && sess.Config.Region != nil {
+		region = *sess.Config.Region
+	}
+	return NewCodeCommitProviderWithSession(sess, region, server, user, git), nil
+}
+
+// NewCodeCommitProviderWithSession creates a new git provider for AWS CodeCommit using the given AWS session
+func NewCodeCommitProviderWithSession(sess client.ConfigProvider, region string, server *auth.AuthServer, user *auth.UserAuth, git Gitter) *CodeCommitProvider {
+	return &CodeCommitProvider{
+		CodeCommit:    codecommit.New(sess),
+		Notifications: codecommit.NewNotifications(sess),
+		Region:        region,
+		Username:      user.Username,
+		Server:        *server,
+		User:          *user,
+		Git:           git,
+	}
+}
+
+// CodeCommitRegion returns the AWS region of a CodeCommit server or git URL or "" if it is not a CodeCommit URL
+func CodeCommitRegion(gitURL string) string {
+	host := gitURL
+	u, err := url.Parse(gitURL)
+	if err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	groups := codeCommitHostRegex.FindStringSubmatch(host)
+	if len(groups) < 2 {
+		return ""
+	}
+	return groups[1]
+}
+
+func (p *CodeCommitProvider) consoleURL(paths ...string) string {
+	elements := append([]string{fmt.Sprintf("https://%s.console.aws.amazon.com/codesuite/codecommit/repositories", p.Region)}, paths...)
+	return util.UrlJoin(elements...) + "?region=" + p.Region
+}
+
+func (p *CodeCommitProvider) cloneURL(name string) string {
+	return fmt.Sprintf("https://git-codecommit.%s.amazonaws.com/v1/repos/%s", p.Region, name)
+}
+
+func (p *CodeCommitProvider) toGitRepository(repo *codecommit.RepositoryMetadata) *GitRepository {
+	cloneURL := repo.CloneURLHTTP
+	if cloneURL == "" {
+		cloneURL = p.cloneURL(repo.RepositoryName)
+	}
+	return &GitRepository{
+		Name:             repo.RepositoryName,
+		AllowMergeCommit: true,
+		HTMLURL:          p.consoleURL(repo.RepositoryName, "browse"),
+		CloneURL:         cloneURL,
+		SSHURL:           repo.CloneURLSSH,
+	}
+}
+
+func (p *CodeCommitProvider) ListOrganisations() ([]GitOrganisation, error) {
+	return []GitOrganisation{{Login: p.Region}}, nil
+}
+
+func (p *CodeCommitProvider) ListRepositories(org string) ([]*GitRepository, error) {
+	repos, err := p.CodeCommit.ListRepositories()
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitRepository{}
+	for _, repo := range repos {
+		answer = append(answer, p.toGitRepository(&codecommit.RepositoryMetadata{RepositoryName: repo.RepositoryName}))
+	}
+	return answer, nil
+}
+
+// CreateRepository creates a repository. CodeCommit repositories are always private
+func (p *CodeCommitProvider) CreateRepository(org string, name string, private bool) (*GitRepository, error) {
+	repo, err := p.CodeCommit.CreateRepository(name, "")
+	if err != nil {
+		return nil, err
+	}
+	return p.toGitRepository(repo), nil
+}
+
+func (p *CodeCommitProvider) GetRepository(org string, name string) (*GitRepository, error) {
+	repo, err := p.CodeCommit.GetRepository(name)
+	if err != nil {
+		return nil, err
+	}
+	return p.toGitRepository(repo), nil
+}
+
+func (p *CodeCommitProvider) DeleteRepository(org string, name string) error {
+	return p.CodeCommit.DeleteRepository(name)
+}
+
+func (p *CodeCommitProvider) ForkRepository(originalOrg string, name string, destinationOrg string) (*GitRepository, error) {
+	return nil, fmt.Errorf("CodeCommit does not support forking repositories")
+}
+
+func (p *CodeCommitProvider) RenameRepository(org string, name string, newName string) (*GitRepository, error) {
+	err := p.CodeCommit.UpdateRepositoryName(name, newName)
+	if err != nil {
+		return nil, err
+	}
+	return p.GetRepository(org, newName)
+}
+
+func (p *CodeCommitProvider) ValidateRepositoryName(org string, name string) error {
+	_, err := p.CodeCommit.GetRepository(name)
+	if err == nil {
+		return fmt.Errorf("Repository %s already exists", name)
+	}
+	if codecommit.IsErrorCode(err, codecommit.RepositoryDoesNotExist) {
+		return nil
+	}
+	return err
+}
+
+func codeCommitBranchRef(branch string) string {
+	if strings.HasPrefix(branch, "refs/") {
+		return branch
+	}
+	return "refs/heads/" + branch
+}
+
+func (p *CodeCommitProvider) toGitPullRequest(org string, pr *codecommit.PullRequest) *GitPullRequest {
+	number, _ := strconv.Atoi(pr.PullRequestID)
+	state := "open"
+	if pr.PullRequestStatus == codecommit.PullRequestStatusClosed {
+		state = "closed"
+	}
+	merged := false
+	answer := &GitPullRequest{
+		Owner:  org,
+		Number: &number,
+		State:  &state,
+		Merged: &merged,
+		Title:  pr.Title,
+		Body:   pr.Description,
+	}
+	if pr.AuthorArn != "" {
+		login := pr.AuthorArn[strings.LastIndex(pr.AuthorArn, "/")+1:]
+		answer.Author = &GitUser{
+			Login: login,
+			Name:  login,
+		}
+	}
+	if state == "closed" && pr.LastActivityDate != nil {
+		closedAt := pr.LastActivityDate.Time
+		answer.ClosedAt = &closedAt
+	}
+	if len(pr.PullRequestTargets) > 0 {
+		target := pr.PullRequestTargets[0]
+		headRef := strings.TrimPrefix(target.SourceReference, "refs/heads/")
+		answer.Repo = target.RepositoryName
+		answer.HeadRef = &headRef
+		answer.LastCommitSha = target.SourceCommit
+		if target.MergeMetadata != nil && target.MergeMetadata.IsMerged {
+			merged = true
+			answer.MergedAt = answer.ClosedAt
+			if target.MergeMetadata.MergeCommitID != "" {
+				answer.MergeCommitSHA = &target.MergeMetadata.MergeCommitID
+			}
+		}
+	}
+	answer.URL = p.consoleURL(answer.Repo, "pull-requests", pr.PullRequestID, "details")
+	return answer
+}
+
+func (p *CodeCommitProvider) getPullRequest(pr *GitPullRequest) (*codecommit.PullRequest, error) {
+	if pr.Number == nil {
+		return nil, fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	return p.CodeCommit.GetPullRequest(strconv.Itoa(*pr.Number))
+}
+
+func (p *CodeCommitProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
+	base := data.Base
+	if base == "" {
+		base = "master"
+	}
+	target := codecommit.PullRequestTarget{
+		RepositoryName:       data.GitRepositoryInfo.Name,
+		SourceReference:      codeCommitBranchRef(data.Head),
+		DestinationReference: codeCommitBranchRef(base),
+	}
+	pr, err := p.CodeCommit.CreatePullRequest(data.Title, data.Body, target)
+	if err != nil {
+		return nil, err
+	}
+	return p.toGitPullRequest(data.GitRepositoryInfo.Organisation, pr), nil
+}
+
+func (p *CodeCommitProvider) UpdatePullRequestStatus(pr *GitPullRequest) error {
+	result, err := p.getPullRequest(pr)
+	if err != nil {
+		return err
+	}
+	updated := p.toGitPullRequest(pr.Owner, result)
+	pr.State = updated.State
+	pr.Merged = updated.Merged
+	pr.MergeCommitSHA = updated.MergeCommitSHA
+	pr.LastCommitSha = updated.LastCommitSha
+	pr.ClosedAt = updated.ClosedAt
+	pr.MergedAt = updated.MergedAt
+	if pr.Author == nil {
+		pr.Author = updated.Author
+	}
+	return nil
+}
+
+func (p *CodeCommitProvider) GetPullRequest(owner string, repo *GitRepositoryInfo, number int) (*GitPullRequest, error) {
+	pr, err := p.CodeCommit.GetPullRequest(strconv.Itoa(number))
+	if err != nil {
+		return nil, err
+	}
+	return p.toGitPullRequest(owner, pr), nil
+}
+
+// GetPullRequestCommits returns the commits of the source branch since the merge base of the pull request, following
+// the first parent of merge commits
+func (p *CodeCommitProvider) GetPullRequestCommits(owner string, repo *GitRepositoryInfo, number int) ([]*GitCommit, error) {
+	pr, err := p.CodeCommit.GetPullRequest(strconv.Itoa(number))
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitCommit{}
+	if len(pr.PullRequestTargets) == 0 {
+		return answer, nil
+	}
+	target := pr.PullRequestTargets[0]
+	sha := target.SourceCommit
+	for i := 0; sha != "" && sha != target.MergeBase && i < maxCodeCommitPullRequestCommits; i++ {
+		commit, err := p.CodeCommit.GetCommit(target.RepositoryName, sha)
+		if err != nil {
+			return nil, err
+		}
+		c := &GitCommit{
+			SHA:     sha,
+			Message: commit.Message,
+			URL:     p.consoleURL(target.RepositoryName, "commit", sha),
+		}
+		if commit.Author != nil {
+			c.Author = &GitUser{
+				Name:  commit.Author.Name,
+				Email: commit.Author.Email,
+			}
+		}
+		if commit.Committer != nil {
+			c.Committer = &GitUser{
+				Name:  commit.Committer.Name,
+				Email: commit.Committer.Email,
+			}
+		}
+		answer = append(answer, c)
+		sha = ""
+		if len(commit.Parents) > 0 {
+			sha = commit.Parents[0]
+		}
+	}
+	return answer, nil
+}
+
+func (p *CodeCommitProvider) PullRequestLastCommitStatus(pr *GitPullRequest) (string, error) {
+	return "", fmt.Errorf("CodeCommit does not support commit statuses so there is no status for repository %s/%s with ref %s", pr.Owner, pr.Repo, pr.LastCommitSha)
+}
+
+func (p *CodeCommitProvider) ListCommitStatus(org string, repo string, sha string) ([]*GitRepoStatus, error) {
+	log.Warn("CodeCommit does not support commit statuses")
+	return []*GitRepoStatus{}, nil
+}
+
+func (p *CodeCommitProvider) CreateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	log.Warn("CodeCommit does not support commit statuses")
+	return status, nil
+}
+
+// MergePullRequest merges the pull request with a merge commit once its approval rules are satisfied
+func (p *CodeCommitProvider) MergePullRequest(pr *GitPullRequest, message string) error {
+	current, err := p.getPullRequest(pr)
+	if err != nil {
+		return err
+	}
+	if len(current.PullRequestTargets) == 0 {
+		return fmt.Errorf("No target found for CodeCommit pull request %s", current.PullRequestID)
+	}
+	if len(current.ApprovalRules) > 0 {
+		evaluation, err := p.CodeCommit.EvaluatePullRequestApprovalRules(current.PullRequestID, current.RevisionID)
+		if err != nil {
+			return err
+		}
+		if !evaluation.Approved && !evaluation.Overridden {
+			return fmt.Errorf("Pull request %s cannot be merged as its approval rules are not satisfied: %s",
+				current.PullRequestID, strings.Join(evaluation.ApprovalRulesNotSatisfied, ", "))
+		}
+	}
+	target := current.PullRequestTargets[0]
+	_, err = p.CodeCommit.MergePullRequestByThreeWay(current.PullRequestID, target.RepositoryName, target.SourceCommit, message)
+	return err
+}
+
+// codeCommitApprovalRuleContent returns the content of an approval rule needing the number of approvals from the
+// approvers, or anyone in the account if there are none, for pull requests into the branches if there are any
+func codeCommitApprovalRuleContent(approvals int, approvers []string, branches ...string) (string, error) {
+	statement := map[string]interface{}{
+		"Type":                    "Approvers",
+		"NumberOfApprovalsNeeded": approvals,
+	}
+	if len(approvers) > 0 {
+		statement["ApprovalPoolMembers"] = approvers
+	}
+	content := map[string]interface{}{
+		"Version":    codecommit.ApprovalRuleVersion,
+		"Statements": []interface{}{statement},
+	}
+	if len(branches) > 0 {
+		refs := []string{}
+		for _, branch := range branches {
+			refs = append(refs, codeCommitBranchRef(branch))
+		}
+		content["DestinationReferences"] = refs
+	}
+	data, err := json.Marshal(content)
+	return string(data), err
+}
+
+// CreatePullRequestApprovalRule adds an approval rule to the pull request needing the number of approvals from the
+// approvers, which are IAM ARNs and may use wildcards, or from anyone in the account if there are none
+func (p *CodeCommitProvider) CreatePullRequestApprovalRule(pr *GitPullRequest, name string, approvals int, approvers []string) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	content, err := codeCommitApprovalRuleContent(approvals, approvers)
+	if err != nil {
+		return err
+	}
+	return p.CodeCommit.CreatePullRequestApprovalRule(strconv.Itoa(*pr.Number), name, content)
+}
+
+// ApprovePullRequest approves the current revision of the pull request as the AWS user of the session
+func (p *CodeCommitProvider) ApprovePullRequest(pr *GitPullRequest) error {
+	current, err := p.getPullRequest(pr)
+	if err != nil {
+		return err
+	}
+	return p.CodeCommit.UpdatePullRequestApprovalState(current.PullRequestID, current.RevisionID, codecommit.ApprovalStateApprove)
+}
+
+// ProtectBranch requires approvals before pull requests into the branch can merge using an approval rule template
+// associated with the repository. CodeCommit cannot require commit statuses or block pushes to the branch
+func (p *CodeCommitProvider) ProtectBranch(org string, repo string, protection *GitBranchProtection) error {
+	if len(protection.RequiredStatusContexts) > 0 {
+		log.Warnf("CodeCommit does not support required status checks so they are not applied to %s\n", repo)
+	}
+	if protection.RequiredApprovingReviews <= 0 {
+		log.Warnf("CodeCommit can only protect branches by requiring approvals so the branch %s of %s is not protected\n", protection.Branch, repo)
+		return nil
+	}
+	branch := protection.Branch
+	if branch == "" {
+		branch = "master"
+	}
+	content, err := codeCommitApprovalRuleContent(protection.RequiredApprovingReviews, nil, branch)
+	if err != nil {
+		return err
+	}
+	name := "jx-" + repo + "-" + branch
+	_, err = p.CodeCommit.CreateApprovalRuleTemplate(name, content, fmt.Sprintf("Approvals required to merge into %s of %s", branch, repo))
+	if codecommit.IsErrorCode(err, codecommit.ApprovalRuleTemplateNameAlreadyExists) {
+		var template *codecommit.ApprovalRuleTemplate
+		template, err = p.CodeCommit.GetApprovalRuleTemplate(name)
+		if err != nil {
+			return err
+		}
+		if template.ApprovalRuleTemplateContent != content {
+			_, err = p.CodeCommit.UpdateApprovalRuleTemplateContent(name, content, template.RuleContentSha256)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to save the approval rule template %s: %s", name, err)
+	}
+	return p.CodeCommit.AssociateApprovalRuleTemplateWithRepository(name, repo)
+}
+
+// codeCommitTopicName returns the name of the SNS topic the trigger of the repository publishes to
+func codeCommitTopicName(repo string) string {
+	return CodeCommitTopicPrefix + codeCommitTopicNameRegex.ReplaceAllString(repo, "_")
+}
+
+// CreateWebHook publishes the events of the repository to an SNS topic and subscribes the webhook URL to it.
+//
+// SNS only delivers notifications to HTTP endpoints which confirm the subscription and the notifications are not in a
+// format Jenkins or the Prow hook understand, so the URL is the webhook recorder addon which confirms the subscription
+// and forwards the notifications to Jenkins or the Prow hook as push events
+func (p *CodeCommitProvider) CreateWebHook(data *GitWebHookArguments) error {
+	repo := data.Repo.Name
+	topicArn, err := p.Notifications.CreateTopic(codeCommitTopicName(repo))
+	if err != nil {
+		return fmt.Errorf("Failed to create the SNS topic for repository %s: %s", repo, err)
+	}
+	triggers, err := p.CodeCommit.GetRepositoryTriggers(repo)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, trigger := range triggers {
+		if trigger.Name == CodeCommitWebHookTrigger && trigger.DestinationArn == topicArn {
+			found = true
+		}
+	}
+	if !found {
+		updated := []codecommit.RepositoryTrigger{}
+		for _, trigger := range triggers {
+			if trigger.Name != CodeCommitWebHookTrigger {
+				updated = append(updated, trigger)
+			}
+		}
+		updated = append(updated, codecommit.RepositoryTrigger{
+			Name:           CodeCommitWebHookTrigger,
+			DestinationArn: topicArn,
+			Branches:       []string{},
+			Events:         []string{"all"},
+		})
+		err = p.CodeCommit.PutRepositoryTriggers(repo, updated)
+		if err != nil {
+			return fmt.Errorf("Failed to add the trigger to repository %s: %s", repo, err)
+		}
+	}
+
+	subscriptions, err := p.Notifications.ListSubscriptionsByTopic(topicArn)
+	if err != nil {
+		return err
+	}
+	for _, subscription := range subscriptions {
+		if subscription.Endpoint == data.URL {
+			log.Infof("Already subscribed %s to the events of repository %s\n", data.URL, repo)
+			return nil
+		}
+	}
+	u, err := url.Parse(data.URL)
+	if err != nil {
+		return fmt.Errorf("Invalid webhook URL %s: %s", data.URL, err)
+	}
+	_, err = p.Notifications.Subscribe(topicArn, u.Scheme, data.URL)
+	if err != nil {
+		return fmt.Errorf("Failed to subscribe %s to the events of repository %s: %s", data.URL, repo, err)
+	}
+	return nil
+}
+
+// ListWebHooks returns the subscriptions to the SNS topics the triggers of the repository publish to
+func (p *CodeCommitProvider) ListWebHooks(org string, repo string) ([]*GitWebHookArguments, error) {
+	triggers, err := p.CodeCommit.GetRepositoryTriggers(repo)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitWebHookArguments{}
+	for _, trigger := range triggers {
+		if !strings.HasPrefix(trigger.DestinationArn, "arn:aws:sns:") {
+			continue
+		}
+		subscriptions, err := p.Notifications.ListSubscriptionsByTopic(trigger.DestinationArn)
+		if err != nil {
+			return nil, err
+		}
+		for _, subscription := range subscriptions {
+			if subscription.Protocol != "http" && subscription.Protocol != "https" {
+				continue
+			}
+			answer = append(answer, &GitWebHookArguments{
+				ID:    subscription.SubscriptionArn,
+				Owner: org,
+				Repo:  &GitRepositoryInfo{Organisation: org, Name: repo},
+				URL:   subscription.Endpoint,
+			})
+		}
+	}
+	return answer, nil
+}
+
+// UpdateWebHook replaces the subscription of the webhook with one for its new URL
+func (p *CodeCommitProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	err := p.DeleteWebHook(data.Owner, data.Repo.Name, data.ID)
+	if err != nil {
+		return err
+	}
+	return p.CreateWebHook(data)
+}
+
+// DeleteWebHook removes the subscription of the webhook. Subscriptions which are still pending confirmation cannot
+// be removed and expire after three days
+func (p *CodeCommitProvider) DeleteWebHook(org string, repo string, id string) error {
+	if id == "" || id == codecommit.PendingConfirmation {
+		log.Warnf("The SNS subscription for repository %s has not been confirmed so it cannot be removed\n", repo)
+		return nil
+	}
+	err := p.Notifications.Unsubscribe(id)
+	if err != nil {
+		return fmt.Errorf("Failed to remove the SNS subscription %s: %s", id, err)
+	}
+	return nil
+}
+
+func (p *CodeCommitProvider) GetIssue(org string, name string, number int) (*GitIssue, error) {
+	log.Warn("CodeCommit does not support issues")
+	return &GitIssue{}, nil
+}
+
+func (p *CodeCommitProvider) IssueURL(org string, name string, number int, isPull bool) string {
+	if isPull {
+		return p.consoleURL(name, "pull-requests", strconv.Itoa(number), "details")
+	}
+	return ""
+}
+
+func (p *CodeCommitProvider) SearchIssues(org string, name string, query string) ([]*GitIssue, error) {
+	log.Warn("CodeCommit does not support issues")
+	return []*GitIssue{}, nil
+}
+
+func (p *CodeCommitProvider) SearchIssuesClosedSince(org string, name string, t time.Time) ([]*GitIssue, error) {
+	issues, err := p.SearchIssues(org, name, "")
+	if err != nil {
+		return issues, err
+	}
+	return FilterIssuesClosedSince(issues, t), nil
+}
+
+func (p *CodeCommitProvider) CreateIssue(owner string, repo string, issue *GitIssue) (*GitIssue, error) {
+	log.Warn("CodeCommit does not support issues")
+	return &GitIssue{}, nil
+}
+
+func (p *CodeCommitProvider) HasIssues() bool {
+	return false
+}
+
+// AddPRComment adds a comment on the latest changes of the pull request
+func (p *CodeCommitProvider) AddPRComment(pr *GitPullRequest, comment string) error {
+	current, err := p.getPullRequest(pr)
+	if err != nil {
+		return err
+	}
+	if len(current.PullRequestTargets) == 0 {
+		return fmt.Errorf("No target found for CodeCommit pull request %s", current.PullRequestID)
+	}
+	target := current.PullRequestTargets[0]
+	return p.CodeCommit.PostCommentForPullRequest(current.PullRequestID, target.RepositoryName, target.DestinationCommit, target.SourceCommit, comment)
+}
+
+// CreateIssueComment comments on the pull request with the number as CodeCommit has no issues
+func (p *CodeCommitProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
+	return p.AddPRComment(&GitPullRequest{Owner: owner, Repo: repo, Number: &number}, comment)
+}
+
+func (p *CodeCommitProvider) UpdateRelease(owner string, repo string, tag string, releaseInfo *GitRelease) error {
+	log.Warn("CodeCommit doesn't support releases")
+	return nil
+}
+
+func (p *CodeCommitProvider) ListReleases(org string, name string) ([]*GitRelease, error) {
+	log.Warn("CodeCommit doesn't support releases")
+	return []*GitRelease{}, nil
+}
+
+func (p *CodeCommitProvider) IsGitHub() bool {
+	return false
+}
+
+func (p *CodeCommitProvider) IsGitea() bool {
+	return false
+}
+
+func (p *CodeCommitProvider) IsBitbucketCloud() bool {
+	return false
+}
+
+func (p *CodeCommitProvider) IsBitbucketServer() bool {
+	return false
+}
+
+func (p *CodeCommitProvider) IsGerrit() bool {
+	return false
+}
+
+func (p *CodeCommitProvider) Kind() string {
+	return KindCodeCommit
+}
+
+// JenkinsWebHookPath uses the generic git plugin endpoint as Jenkins has no CodeCommit specific webhook
+func (p *CodeCommitProvider) JenkinsWebHookPath(gitURL string, secret string) string {
+	return "/git/notifyCommit?url=" + url.QueryEscape(gitURL)
+}
+
+func (p *CodeCommitProvider) Label() string {
+	return p.Server.Label()
+}
+
+func (p *CodeCommitProvider) ServerURL() string {
+	return p.Server.URL
+}
+
+// BranchArchiveURL returns "" as CodeCommit has no archive downloads
+func (p *CodeCommitProvider) BranchArchiveURL(org string, name string, branch string) string {
+	return ""
+}
+
+func (p *CodeCommitProvider) CurrentUsername() string {
+	return p.Username
+}
+
+func (p *CodeCommitProvider) UserAuth() auth.UserAuth {
+	return p.User
+}
+
+func (p *CodeCommitProvider) UserInfo(username string) *GitUser {
+	return &GitUser{
+		Login: username,
+	}
+}
+
+func (p *CodeCommitProvider) AddCollaborator(user string, organisation string, repo string) error {
+	log.Infof("CodeCommit access is granted with IAM policies. Please give the IAM user %s access to the repository %s.\n", user, repo)
+	return nil
+}
+
+func (p *CodeCommitProvider) ListCollaborators(organisation string, repo string) ([]*GitCollaborator, error) {
+	log.Warn("CodeCommit access is granted with IAM policies so repositories have no collaborators")
+	return []*GitCollaborator{}, nil
+}
+
+func (p *CodeCommitProvider) AddOrganisationMember(organisation string, user string) error {
+	log.Infof("CodeCommit access is granted with IAM policies. Please give the IAM user %s access to the repositories of %s.\n", user, organisation)
+	return nil
+}
+
+func (p *CodeCommitProvider) RemoveOrganisationMember(organisation string, user string) error {
+	log.Warnf("CodeCommit access is granted with IAM policies. Please remove the access of the IAM user %s to the repositories of %s.\n", user, organisation)
+	return nil
+}
+
+func (p *CodeCommitProvider) ListInvitations() ([]*GitInvitation, error) {
+	return []*GitInvitation{}, nil
+}
+
+func (p *CodeCommitProvider) AcceptInvitation(ID string) error {
+	return nil
+}
+
+// CodeCommitAccessTokenURL returns the URL to create the HTTPS git credentials of IAM users
+func CodeCommitAccessTokenURL(url string) string {
+	return "https://console.aws.amazon.com/iam/home#/security_credentials"
+}
@@ -0,0 +1,255 @@
+package gits_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/suite"
+)
+
+type CodeCommitProviderTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	provider *gits.CodeCommitProvider
+
+	lock     sync.Mutex
+	requests map[string]string
+}
+
+// ServeHTTP stubs the CodeCommit and SNS APIs returning the test_data/codecommit file named after the operation
+func (suite *CodeCommitProviderTestSuite) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	suite.Require().NotEmpty(r.Header.Get("Authorization"), "the request should be signed")
+
+	operation := ""
+	extension := ".json"
+	key := ""
+	if target := r.Header.Get("X-Amz-Target"); target != "" {
+		suite.Require().True(strings.HasPrefix(target, "CodeCommit_20150413."))
+		operation = strings.TrimPrefix(target, "CodeCommit_20150413.")
+		input := map[string]interface{}{}
+		suite.Require().Nil(json.Unmarshal(body, &input))
+		if input["repositoryName"] == "new-repo" && operation == "GetRepository" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"__type":"RepositoryDoesNotExistException","message":"new-repo does not exist"}`)
+			return
+		}
+		if commitID, ok := input["commitId"].(string); ok {
+			key = "." + commitID
+		}
+	} else {
+		values, err := url.ParseQuery(string(body))
+		suite.Require().Nil(err)
+		operation = values.Get("Action")
+		extension = ".xml"
+	}
+	suite.lock.Lock()
+	suite.requests[operation] = string(body)
+	suite.lock.Unlock()
+
+	data, err := ioutil.ReadFile(filepath.Join("test_data/codecommit", operation+key+extension))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"__type":"InvalidOperation","message":"no stub for %s"}`, operation)
+		return
+	}
+	w.Write(data)
+}
+
+func (suite *CodeCommitProviderTestSuite) SetupSuite() {
+	suite.requests = map[string]string{}
+	suite.server = httptest.NewServer(suite)
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(suite.server.URL),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	suite.Require().Nil(err)
+
+	as := &auth.AuthServer{
+		URL:  "https://git-codecommit.us-east-1.amazonaws.com",
+		Name: "Test CodeCommit",
+		Kind: gits.KindCodeCommit,
+	}
+	ua := &auth.UserAuth{
+		Username: "test-user-at-123456789012",
+		ApiToken: "secret",
+	}
+	suite.provider = gits.NewCodeCommitProviderWithSession(sess, gits.CodeCommitRegion(as.URL), as, ua, gits.NewGitCLI())
+	suite.Require().Equal("us-east-1", suite.provider.Region)
+}
+
+func (suite *CodeCommitProviderTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
+
+func (suite *CodeCommitProviderTestSuite) requestBody(operation string) string {
+	suite.lock.Lock()
+	defer suite.lock.Unlock()
+	return suite.requests[operation]
+}
+
+func (suite *CodeCommitProviderTestSuite) TestListOrganisations() {
+	orgs, err := suite.provider.ListOrganisations()
+
+	suite.Require().Nil(err)
+	suite.Require().Len(orgs, 1)
+	suite.Require().Equal("us-east-1", orgs[0].Login)
+}
+
+func (suite *CodeCommitProviderTestSuite) TestListRepositories() {
+	repos, err := suite.provider.ListRepositories("us-east-1")
+
+	suite.Require().Nil(err)
+	suite.Require().Len(repos, 2)
+	suite.Require().Equal("test-repo", repos[0].Name)
+	suite.Require().Equal("https://git-codecommit.us-east-1.amazonaws.com/v1/repos/test-repo", repos[0].CloneURL)
+}
+
+func (suite *CodeCommitProviderTestSuite) TestGetRepository() {
+	repo, err := suite.provider.GetRepository("us-east-1", "test-repo")
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("test-repo", repo.Name)
+	suite.Require().Equal("ssh://git-codecommit.us-east-1.amazonaws.com/v1/repos/test-repo", repo.SSHURL)
+	suite.Require().Equal("https://us-east-1.console.aws.amazon.com/codesuite/codecommit/repositories/test-repo/browse?region=us-east-1", repo.HTMLURL)
+}
+
+func (suite *CodeCommitProviderTestSuite) TestCreateRepository() {
+	repo, err := suite.provider.CreateRepository("us-east-1", "new-repo", true)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("new-repo", repo.Name)
+	suite.Require().JSONEq(`{"repositoryName":"new-repo"}`, suite.requestBody("CreateRepository"))
+}
+
+func (suite *CodeCommitProviderTestSuite) TestValidateRepositoryName() {
+	err := suite.provider.ValidateRepositoryName("us-east-1", "test-repo")
+	suite.Require().NotNil(err)
+
+	err = suite.provider.ValidateRepositoryName("us-east-1", "new-repo")
+	suite.Require().Nil(err)
+}
+
+func (suite *CodeCommitProviderTestSuite) TestCreatePullRequest() {
+	pr, err := suite.provider.CreatePullRequest(&gits.GitPullRequestArguments{
+		GitRepositoryInfo: &gits.GitRepositoryInfo{Organisation: "us-east-1", Name: "test-repo"},
+		Title:             "Add a feature",
+		Head:              "feature",
+		Base:              "master",
+	})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(2, *pr.Number)
+	suite.Require().Equal("test-repo", pr.Repo)
+	suite.Require().Equal("feature", *pr.HeadRef)
+	suite.Require().Equal("test-user", pr.Author.Login)
+	suite.Require().JSONEq(`{"title":"Add a feature","targets":[{"repositoryName":"test-repo","sourceReference":"refs/heads/feature","destinationReference":"refs/heads/master"}]}`,
+		suite.requestBody("CreatePullRequest"))
+}
+
+func (suite *CodeCommitProviderTestSuite) TestGetPullRequest() {
+	pr, err := suite.provider.GetPullRequest("us-east-1", &gits.GitRepositoryInfo{Name: "test-repo"}, 1)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("open", *pr.State)
+	suite.Require().False(*pr.Merged)
+	suite.Require().Equal("c5709475EXAMPLE", pr.LastCommitSha)
+	suite.Require().Equal("https://us-east-1.console.aws.amazon.com/codesuite/codecommit/repositories/test-repo/pull-requests/1/details?region=us-east-1", pr.URL)
+}
+
+func (suite *CodeCommitProviderTestSuite) TestGetPullRequestCommits() {
+	commits, err := suite.provider.GetPullRequestCommits("us-east-1", &gits.GitRepositoryInfo{Name: "test-repo"}, 1)
+
+	suite.Require().Nil(err)
+	suite.Require().Len(commits, 2)
+	suite.Require().Equal("c5709475EXAMPLE", commits[0].SHA)
+	suite.Require().Equal("test-user@example.com", commits[0].Author.Email)
+	suite.Require().Equal("b1c2d3e4EXAMPLE", commits[1].SHA)
+}
+
+func (suite *CodeCommitProviderTestSuite) TestMergePullRequest() {
+	number := 1
+	err := suite.provider.MergePullRequest(&gits.GitPullRequest{Number: &number}, "Merged")
+
+	suite.Require().Nil(err)
+	suite.Require().JSONEq(`{"pullRequestId":"1","revisionId":"9f29d167-e1a3-4ca3-b2b5-0a4c5EXAMPLE"}`,
+		suite.requestBody("EvaluatePullRequestApprovalRules"))
+	suite.Require().JSONEq(`{"pullRequestId":"1","repositoryName":"test-repo","sourceCommitId":"c5709475EXAMPLE","commitMessage":"Merged"}`,
+		suite.requestBody("MergePullRequestByThreeWay"))
+}
+
+func (suite *CodeCommitProviderTestSuite) TestAddPRComment() {
+	number := 1
+	err := suite.provider.AddPRComment(&gits.GitPullRequest{Number: &number}, "LGTM")
+
+	suite.Require().Nil(err)
+	suite.Require().JSONEq(`{"pullRequestId":"1","repositoryName":"test-repo","beforeCommitId":"317f8570EXAMPLE","afterCommitId":"c5709475EXAMPLE","content":"LGTM"}`,
+		suite.requestBody("PostCommentForPullRequest"))
+}
+
+func (suite *CodeCommitProviderTestSuite) TestProtectBranch() {
+	err := suite.provider.ProtectBranch("us-east-1", "test-repo", &gits.GitBranchProtection{
+		Branch:                   "master",
+		RequiredApprovingReviews: 2,
+	})
+
+	suite.Require().Nil(err)
+	input := map[string]string{}
+	suite.Require().Nil(json.Unmarshal([]byte(suite.requestBody("CreateApprovalRuleTemplate")), &input))
+	suite.Require().Equal("jx-test-repo-master", input["approvalRuleTemplateName"])
+	suite.Require().JSONEq(`{"Version":"2018-11-08","DestinationReferences":["refs/heads/master"],"Statements":[{"Type":"Approvers","NumberOfApprovalsNeeded":2}]}`,
+		input["approvalRuleTemplateContent"])
+	suite.Require().JSONEq(`{"approvalRuleTemplateName":"jx-test-repo-master","repositoryName":"test-repo"}`,
+		suite.requestBody("AssociateApprovalRuleTemplateWithRepository"))
+}
+
+func (suite *CodeCommitProviderTestSuite) TestCreateWebHook() {
+	err := suite.provider.CreateWebHook(&gits.GitWebHookArguments{
+		Repo: &gits.GitRepositoryInfo{Organisation: "us-east-1", Name: "test-repo"},
+		URL:  "https://hook.example.com/hook",
+	})
+
+	suite.Require().Nil(err)
+	suite.Require().Contains(suite.requestBody("CreateTopic"), "Name=jx-codecommit-test-repo")
+	suite.Require().JSONEq(`{"repositoryName":"test-repo","triggers":[
+		{"name":"notify-team","destinationArn":"arn:aws:sns:us-east-1:123456789012:team-notifications","branches":["master"],"events":["createReference"]},
+		{"name":"jenkins-x","destinationArn":"arn:aws:sns:us-east-1:123456789012:jx-codecommit-test-repo","branches":[],"events":["all"]}]}`,
+		suite.requestBody("PutRepositoryTriggers"))
+	values, err := url.ParseQuery(suite.requestBody("Subscribe"))
+	suite.Require().Nil(err)
+	suite.Require().Equal("https", values.Get("Protocol"))
+	suite.Require().Equal("https://hook.example.com/hook", values.Get("Endpoint"))
+}
+
+func (suite *CodeCommitProviderTestSuite) TestListWebHooks() {
+	hooks, err := suite.provider.ListWebHooks("us-east-1", "test-repo")
+
+	suite.Require().Nil(err)
+	suite.Require().Len(hooks, 1)
+	suite.Require().Equal("https://jenkins.example.com/git/notifyCommit", hooks[0].URL)
+	suite.Require().Equal("arn:aws:sns:us-east-1:123456789012:team-notifications:80289ba6-0fd4-4079-afb4-ce8c8260f0ca", hooks[0].ID)
+}
+
+func TestCodeCommitProviderTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CodeCommitProviderTestSuite in short mode")
+	} else {
+		suite.Run(t, new(CodeCommitProviderTestSuite))
+	}
+}
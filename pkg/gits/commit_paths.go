@@ -0,0 +1,102 @@
+package gits
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// FilterCommitsByPaths returns the commits which change any file within the given paths. If no paths are specified
+// all the commits are returned
+func FilterCommitsByPaths(commits []object.Commit, paths []string) ([]object.Commit, error) {
+	if len(paths) == 0 {
+		return commits, nil
+	}
+	answer := []object.Commit{}
+	for _, commit := range commits {
+		c := commit
+		touched, err := CommitTouchesPaths(&c, paths)
+		if err != nil {
+			return answer, err
+		}
+		if touched {
+			answer = append(answer, commit)
+		}
+	}
+	return answer, nil
+}
+
+// CommitTouchesPaths returns true if the given commit changes any file within the given paths compared to its first parent.
+// Paths are relative to the root of the repository and can be files or directories
+func CommitTouchesPaths(commit *object.Commit, paths []string) (bool, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return false, fmt.Errorf("Failed to find the tree of commit %s: %s", commit.Hash.String(), err)
+	}
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return false, fmt.Errorf("Failed to find the parent of commit %s: %s", commit.Hash.String(), err)
+		}
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return false, fmt.Errorf("Failed to find the tree of commit %s: %s", parent.Hash.String(), err)
+		}
+	}
+	for _, p := range paths {
+		if treePathHash(tree, p) != treePathHash(parentTree, p) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// treePathHash returns the hash of the file or directory at the given path or the zero hash if it does not exist
+func treePathHash(tree *object.Tree, p string) plumbing.Hash {
+	if tree == nil {
+		return plumbing.ZeroHash
+	}
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if p == "" {
+		return tree.Hash
+	}
+	entry, err := tree.FindEntry(p)
+	if err != nil || entry == nil {
+		return plumbing.ZeroHash
+	}
+	return entry.Hash
+}
+
+// GetFileContentsAtRevision returns the contents of the file at the given path relative to the root of the git repository
+// in the given directory at the given revision. Returns nil if the file does not exist at that revision
+func GetFileContentsAtRevision(gitDir string, revision string, fileName string) ([]byte, error) {
+	repo, err := git.PlainOpen(gitDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open git repository %s: %s", gitDir, err)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to resolve revision %s in %s: %s", revision, gitDir, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find commit %s in %s: %s", hash.String(), gitDir, err)
+	}
+	file, err := commit.File(strings.TrimPrefix(path.Clean("/"+fileName), "/"))
+	if err == object.ErrFileNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	text, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(text), nil
+}
@@ -0,0 +1,70 @@
+package gits_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestFilterCommitsByPaths(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-commit-paths")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	commitFile := func(fileName string, text string) *object.Commit {
+		path := filepath.Join(dir, fileName)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(text), 0644))
+		_, err := worktree.Add(fileName)
+		require.NoError(t, err)
+		hash, err := worktree.Commit("change "+fileName, &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		commit, err := repo.CommitObject(hash)
+		require.NoError(t, err)
+		return commit
+	}
+
+	first := commitFile("services/foo/main.go", "package main")
+	second := commitFile("services/bar/main.go", "package main")
+	third := commitFile("services/foo/README.md", "# foo")
+	commits := []object.Commit{*first, *second, *third}
+
+	filtered, err := gits.FilterCommitsByPaths(commits, []string{"services/foo"})
+	require.NoError(t, err)
+	require.Len(t, filtered, 2)
+	assert.Equal(t, first.Hash, filtered[0].Hash)
+	assert.Equal(t, third.Hash, filtered[1].Hash)
+
+	filtered, err = gits.FilterCommitsByPaths(commits, []string{"/services/bar/"})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, second.Hash, filtered[0].Hash)
+
+	filtered, err = gits.FilterCommitsByPaths(commits, nil)
+	require.NoError(t, err)
+	assert.Len(t, filtered, 3)
+
+	data, err := gits.GetFileContentsAtRevision(dir, first.Hash.String(), "services/foo/main.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package main", string(data))
+
+	data, err = gits.GetFileContentsAtRevision(dir, first.Hash.String(), "services/foo/README.md")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+}
@@ -206,6 +206,11 @@ func describeIssue(info *GitRepositoryInfo, issue *v1.IssueSummary) string {
 }
 
 func describeIssueShort(info *GitRepositoryInfo, issue *v1.IssueSummary) string {
+	return "[" + issueLabel(issue) + "](" + issue.URL + ") "
+}
+
+// issueLabel returns the issue ID prefixed with a hash for numeric ids
+func issueLabel(issue *v1.IssueSummary) string {
 	prefix := ""
 	id := issue.ID
 	if len(id) > 0 {
@@ -215,7 +220,7 @@ func describeIssueShort(info *GitRepositoryInfo, issue *v1.IssueSummary) string
 			prefix = "#"
 		}
 	}
-	return "[" + prefix + issue.ID + "](" + issue.URL + ") "
+	return prefix + issue.ID
 }
 
 func describeUser(info *GitRepositoryInfo, user *v1.UserDetails) string {
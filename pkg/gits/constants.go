@@ -1,16 +1,19 @@
 package gits
 
 const (
+	KindAzureDevOps     = "azuredevops"
 	KindBitBucketCloud  = "bitbucketcloud"
 	KindBitBucketServer = "bitbucketserver"
+	KindCodeCommit      = "codecommit"
 	KindGitea           = "gitea"
 	KindGitlab          = "gitlab"
 	KindGitHub          = "github"
 	KindUnknown         = "unknown"
 
+	AzureDevOpsURL    = "https://dev.azure.com"
 	BitbucketCloudURL = "https://bitbucket.org"
 )
 
 var (
-	KindGits = []string{KindBitBucketCloud, KindBitBucketServer, KindGitea, KindGitHub, KindGitlab}
+	KindGits = []string{KindAzureDevOps, KindBitBucketCloud, KindBitBucketServer, KindCodeCommit, KindGitea, KindGitHub, KindGitlab}
 )
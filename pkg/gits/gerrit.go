@@ -5,7 +5,6 @@ import (
 	"time"
 
 	gerrit "github.com/andygrunwald/go-gerrit"
-	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/log"
 )
@@ -76,6 +75,11 @@ func (p *GerritProvider) ListCommitStatus(org string, repo string, sha string) (
 	return nil, nil
 }
 
+func (p *GerritProvider) CreateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	log.Warn("Gerrit does not support commit statuses at this moment")
+	return status, nil
+}
+
 func (p *GerritProvider) MergePullRequest(pr *GitPullRequest, message string) error {
 	return nil
 }
@@ -84,6 +88,26 @@ func (p *GerritProvider) CreateWebHook(data *GitWebHookArguments) error {
 	return nil
 }
 
+func (p *GerritProvider) ListWebHooks(org string, repo string) ([]*GitWebHookArguments, error) {
+	log.Warn("Gerrit does not support listing webhooks at this moment")
+	return []*GitWebHookArguments{}, nil
+}
+
+func (p *GerritProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	log.Warn("Gerrit does not support updating webhooks at this moment")
+	return nil
+}
+
+func (p *GerritProvider) DeleteWebHook(org string, repo string, id string) error {
+	log.Warn("Gerrit does not support deleting webhooks at this moment")
+	return nil
+}
+
+func (p *GerritProvider) ProtectBranch(org string, repo string, protection *GitBranchProtection) error {
+	log.Warn("Gerrit does not support branch protection at this moment")
+	return nil
+}
+
 func (p *GerritProvider) IsGitHub() bool {
 	return false
 }
@@ -181,12 +205,27 @@ func (p *GerritProvider) AddCollaborator(user string, organisation string, repo
 	return nil
 }
 
-func (p *GerritProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
+func (p *GerritProvider) ListCollaborators(organisation string, repo string) ([]*GitCollaborator, error) {
+	log.Warn("Listing the collaborators of a repository is currently not implemented for gerrit")
+	return []*GitCollaborator{}, nil
+}
+
+func (p *GerritProvider) AddOrganisationMember(organisation string, user string) error {
+	log.Infof("Adding members to an organisation is currently not implemented for gerrit. Please give user: %v access to %s.\n", user, organisation)
+	return nil
+}
+
+func (p *GerritProvider) RemoveOrganisationMember(organisation string, user string) error {
+	log.Warnf("Removing members from an organisation is currently not implemented for gerrit. Please remove the access of user: %v to %s.\n", user, organisation)
+	return nil
+}
+
+func (p *GerritProvider) ListInvitations() ([]*GitInvitation, error) {
 	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for gerrit.\n")
-	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+	return []*GitInvitation{}, nil
 }
 
-func (p *GerritProvider) AcceptInvitation(ID int64) (*github.Response, error) {
+func (p *GerritProvider) AcceptInvitation(ID string) error {
 	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for gerrit.\n")
-	return &github.Response{}, nil
+	return nil
 }
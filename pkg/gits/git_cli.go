@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
 
 	gitcfg "gopkg.in/src-d/go-git.v4/config"
@@ -22,11 +23,23 @@ const (
 )
 
 // GitCLI implements common git actions based on git CLI
-type GitCLI struct{}
+type GitCLI struct {
+	// Signing the key used to sign commits and tags. They are not signed if nil
+	Signing *SigningConfig
+}
 
-// NewGitCLI creates a new GitCLI instance
+// NewGitCLI creates a new GitCLI instance which signs commits and tags if a signing key is configured with the
+// JX_GIT_SIGNING_KEY environment variable. An invalid signing configuration fails the commits and tags
 func NewGitCLI() *GitCLI {
-	return &GitCLI{}
+	signing := SigningConfigFromEnv()
+	if signing != nil {
+		if err := signing.Validate(); err != nil {
+			log.Warnf("Invalid %s so commits and tags will fail: %s\n", SigningFormatEnvVar, err)
+		}
+	}
+	return &GitCLI{
+		Signing: signing,
+	}
 }
 
 // FindGitConfigDir tries to find the `.git` directory either in the current directory or in parent directories
@@ -198,6 +211,11 @@ func (g *GitCLI) Push(dir string) error {
 	return g.gitCmd(dir, "push", "origin", "HEAD")
 }
 
+// PushBranch pushes the local branch into the remote branch of the repository at the given directory
+func (g *GitCLI) PushBranch(dir string, localBranch string, remoteBranch string) error {
+	return g.gitCmd(dir, "push", "origin", localBranch+":refs/heads/"+remoteBranch)
+}
+
 // ForcePushBranch does a force push of the local branch into the remote branch of the repository at the given directory
 func (g *GitCLI) ForcePushBranch(dir string, localBranch string, remoteBranch string) error {
 	return g.gitCmd(dir, "push", "-f", "origin", localBranch+":"+remoteBranch)
@@ -243,12 +261,20 @@ func (g *GitCLI) CommitIfChanges(dir string, message string) error {
 
 // CommitDir commits all changes from the given directory
 func (g *GitCLI) CommitDir(dir string, message string) error {
-	return g.gitCmd(dir, "commit", "-m", message)
+	args, err := g.commitArgs("-m", message)
+	if err != nil {
+		return err
+	}
+	return g.gitCmd(dir, args...)
 }
 
 // AddCommit perform an add and commit of the changes from the repository at the given directory with the given messages
 func (g *GitCLI) AddCommmit(dir string, msg string) error {
-	return g.gitCmd(dir, "commit", "-a", "-m", msg, "--allow-empty")
+	args, err := g.commitArgs("-a", "-m", msg, "--allow-empty")
+	if err != nil {
+		return err
+	}
+	return g.gitCmd(dir, args...)
 }
 
 func (g *GitCLI) gitCmd(dir string, args ...string) error {
@@ -512,7 +538,11 @@ func (g *GitCLI) Tags(dir string) ([]string, error) {
 
 // CreateTag creates a tag with the given name and message in the repository at the given directory
 func (g *GitCLI) CreateTag(dir string, tag string, msg string) error {
-	return g.gitCmd("", "tag", "-fa", tag, "-m", msg)
+	args, err := g.tagArgs(tag, msg)
+	if err != nil {
+		return err
+	}
+	return g.gitCmd(dir, args...)
 }
 
 // PrintCreateRepositoryGenerateAccessToken prints the access token URL of a Git repository
@@ -141,6 +141,10 @@ func (g *GitFake) CreatePushURL(cloneURL string, userAuth *auth.UserAuth) (strin
 	return cloneURL, nil
 }
 
+func (g *GitFake) PushBranch(dir string, localBranch string, remoteBranch string) error {
+	return nil
+}
+
 func (g *GitFake) ForcePushBranch(dir string, localBranch string, remoteBranch string) error {
 	return nil
 }
@@ -56,8 +56,11 @@ func (i *GitRepositoryInfo) HttpsURL() string {
 	return util.UrlJoin(host, i.Organisation, i.Name)
 }
 
-// HostURL returns the URL to the host
+// HostURL returns the URL to the host, which includes the organisation for Azure DevOps
 func (i *GitRepositoryInfo) HostURL() string {
+	if IsAzureDevOpsHost(i.Host) {
+		return AzureDevOpsServerURL(i.Host, i.Organisation)
+	}
 	answer := i.Host
 	if !strings.Contains(answer, ":/") {
 		// lets find the scheme from the URL
@@ -82,6 +85,9 @@ func (i *GitRepositoryInfo) HostURL() string {
 }
 
 func (i *GitRepositoryInfo) HostURLWithoutUser() string {
+	if IsAzureDevOpsHost(i.Host) {
+		return AzureDevOpsServerURL(i.Host, i.Organisation)
+	}
 	u := i.URL
 	if u != "" {
 		u2, err := url.Parse(u)
@@ -135,6 +141,15 @@ func ParseGitURL(text string) (*GitRepositoryInfo, error) {
 		t = strings.TrimSuffix(t, ".git")
 
 		arr := util.RegexpSplit(t, ":|/")
+		// Azure DevOps SSH URLs look like git@ssh.dev.azure.com:v3/myorg/myproject/myrepo
+		if len(arr) >= 5 && arr[1] == "v3" {
+			answer.Scheme = "git"
+			answer.Host = arr[0]
+			answer.Organisation = arr[2]
+			answer.Project = arr[3]
+			answer.Name = arr[4]
+			return &answer, nil
+		}
 		if len(arr) >= 3 {
 			answer.Scheme = "git"
 			answer.Host = arr[0]
@@ -151,6 +166,32 @@ func parsePath(path string, info *GitRepositoryInfo) (*GitRepositoryInfo, error)
 	trimPath = strings.TrimSuffix(trimPath, ".git")
 	arr := strings.Split(trimPath, "/")
 	arrayLength := len(arr)
+	// CodeCommit paths look like /v1/repos/myrepo and the region of the host is used as the organisation
+	if region := CodeCommitRegion(info.Host); region != "" && arrayLength == 4 && arr[1] == "v1" && arr[2] == "repos" {
+		info.Organisation = region
+		info.Project = region
+		info.Name = arr[3]
+		return info, nil
+	}
+	// Azure DevOps paths look like /myorg/myproject/_git/myrepo or /myproject/_git/myrepo on myorg.visualstudio.com.
+	// The project is omitted if it has the same name as the repository
+	for i := 1; i < arrayLength-1; i++ {
+		if arr[i] == "_git" {
+			info.Name = arr[i+1]
+			switch {
+			case i >= 3:
+				info.Organisation = arr[i-2]
+				info.Project = arr[i-1]
+			case strings.HasSuffix(info.Host, ".visualstudio.com"):
+				info.Organisation = strings.Split(info.Host, ".")[0]
+				info.Project = arr[i-1]
+			default:
+				info.Organisation = arr[i-1]
+				info.Project = info.Name
+			}
+			return info, nil
+		}
+	}
 	if arrayLength >= 2 {
 		info.Organisation = arr[arrayLength-2]
 		info.Project = arr[arrayLength-2]
@@ -164,6 +205,12 @@ func parsePath(path string, info *GitRepositoryInfo) (*GitRepositoryInfo, error)
 
 // SaasGitKind returns the kind for SaaS Git providers or "" if the URL could not be deduced
 func SaasGitKind(gitServiceUrl string) string {
+	if CodeCommitRegion(gitServiceUrl) != "" {
+		return KindCodeCommit
+	}
+	if IsAzureDevOpsHost(gitServiceUrl) {
+		return KindAzureDevOps
+	}
 	switch gitServiceUrl {
 	case "http://github.com":
 		return KindGitHub
@@ -50,6 +50,21 @@ func TestParseGitURL(t *testing.T) {
 		{
 			"http://test-user@auth.example.com/scm/bar/foo.git", "auth.example.com", "bar", "foo",
 		},
+		{
+			"https://dev.azure.com/myorg/myproject/_git/foo", "dev.azure.com", "myorg", "foo",
+		},
+		{
+			"https://myorg@dev.azure.com/myorg/myproject/_git/foo", "dev.azure.com", "myorg", "foo",
+		},
+		{
+			"https://git-codecommit.us-east-1.amazonaws.com/v1/repos/foo", "git-codecommit.us-east-1.amazonaws.com", "us-east-1", "foo",
+		},
+		{
+			"ssh://git-codecommit.eu-west-2.amazonaws.com/v1/repos/foo", "git-codecommit.eu-west-2.amazonaws.com", "eu-west-2", "foo",
+		},
+		{
+			"git@ssh.dev.azure.com:v3/myorg/myproject/foo", "ssh.dev.azure.com", "myorg", "foo",
+		},
 	}
 	for _, data := range testCases {
 		info, err := gits.ParseGitURL(data.url)
@@ -60,3 +75,23 @@ func TestParseGitURL(t *testing.T) {
 		assert.Equal(t, data.name, info.Name, "Name does not match for input %s", data.url)
 	}
 }
+
+func TestParseAzureDevOpsGitURL(t *testing.T) {
+	t.Parallel()
+	testCases := map[string][]string{
+		"https://dev.azure.com/myorg/myproject/_git/foo":       {"myorg", "myproject", "foo"},
+		"https://myorg@dev.azure.com/myorg/myproject/_git/foo": {"myorg", "myproject", "foo"},
+		"https://dev.azure.com/myorg/_git/foo":                 {"myorg", "foo", "foo"},
+		"https://myorg.visualstudio.com/myproject/_git/foo":    {"myorg", "myproject", "foo"},
+		"git@ssh.dev.azure.com:v3/myorg/myproject/foo":         {"myorg", "myproject", "foo"},
+	}
+	for text, expected := range testCases {
+		info, err := gits.ParseGitURL(text)
+		assert.NoError(t, err)
+		if assert.NotNil(t, info) {
+			assert.Equal(t, expected[0], info.Organisation, "Organisation does not match for input %s", text)
+			assert.Equal(t, expected[1], info.Project, "Project does not match for input %s", text)
+			assert.Equal(t, expected[2], info.Name, "Name does not match for input %s", text)
+		}
+	}
+}
@@ -1,13 +1,16 @@
 package gits
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"code.gitea.io/sdk/gitea"
-	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
@@ -20,22 +23,39 @@ type GiteaProvider struct {
 	Server auth.AuthServer
 	User   auth.UserAuth
 	Git    Gitter
+
+	// httpClient the HTTP client of the gitea client so that its transport can be wrapped
+	httpClient *http.Client
 }
 
 func NewGiteaProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter) (GitProvider, error) {
 	client := gitea.NewClient(server.URL, user.ApiToken)
+	httpClient := &http.Client{}
+	client.SetHTTPClient(httpClient)
 
 	provider := GiteaProvider{
-		Client:   client,
-		Server:   *server,
-		User:     *user,
-		Username: user.Username,
-		Git:      git,
+		Client:     client,
+		Server:     *server,
+		User:       *user,
+		Username:   user.Username,
+		Git:        git,
+		httpClient: httpClient,
 	}
 
 	return &provider, nil
 }
 
+// wrapTransport wraps the transport of the HTTP client of the gitea client so that any transport it was configured
+// with is kept
+func (p *GiteaProvider) wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) error {
+	if p.httpClient == nil {
+		p.httpClient = &http.Client{}
+		p.Client.SetHTTPClient(p.httpClient)
+	}
+	p.httpClient.Transport = wrap(clientTransport(p.httpClient))
+	return nil
+}
+
 func (p *GiteaProvider) ListOrganisations() ([]GitOrganisation, error) {
 	answer := []GitOrganisation{}
 	orgs, err := p.Client.ListMyOrgs()
@@ -148,6 +168,8 @@ func toGiteaRepo(name string, repo *gitea.Repository) *GitRepository {
 		HTMLURL:          repo.HTMLURL,
 		SSHURL:           repo.SSHURL,
 		Fork:             repo.Fork,
+		Stars:            repo.Stars,
+		Description:      repo.Description,
 	}
 }
 
@@ -188,6 +210,137 @@ func (p *GiteaProvider) ForkRepository(originalOrg string, name string, destinat
 	return toGiteaRepo(name, repo), nil
 }
 
+func (p *GiteaProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error) {
+	hooks, err := p.Client.ListRepoHooks(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitWebHookArguments{}
+	for _, hook := range hooks {
+		answer = append(answer, &GitWebHookArguments{
+			ID:    strconv.FormatInt(hook.ID, 10),
+			Owner: owner,
+			Repo:  &GitRepositoryInfo{Organisation: owner, Name: repo},
+			URL:   hook.Config["url"],
+		})
+	}
+	return answer, nil
+}
+
+func (p *GiteaProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	owner := data.Owner
+	if owner == "" {
+		owner = p.Username
+	}
+	id, err := strconv.ParseInt(data.ID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid webhook ID %s: %s", data.ID, err)
+	}
+	config := map[string]string{
+		"url":          data.URL,
+		"content_type": "json",
+	}
+	if data.Secret != "" {
+		config["secret"] = data.Secret
+	}
+	active := true
+	hook := gitea.EditHookOption{
+		Config: config,
+		Events: []string{"create", "push", "pull_request"},
+		Active: &active,
+	}
+	log.Infof("Updating Gitea webhook %s for %s/%s to url %s\n", data.ID, owner, data.Repo.Name, data.URL)
+	return p.Client.EditRepoHook(owner, data.Repo.Name, id, hook)
+}
+
+func (p *GiteaProvider) DeleteWebHook(owner string, repo string, id string) error {
+	hookID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid webhook ID %s: %s", id, err)
+	}
+	return p.Client.DeleteRepoHook(owner, repo, hookID)
+}
+
+// giteaBranchProtection the branch protection options of the Gitea API
+type giteaBranchProtection struct {
+	BranchName            string   `json:"branch_name,omitempty"`
+	EnablePush            bool     `json:"enable_push"`
+	EnableStatusCheck     bool     `json:"enable_status_check"`
+	StatusCheckContexts   []string `json:"status_check_contexts"`
+	RequiredApprovals     int64    `json:"required_approvals"`
+	DismissStaleApprovals bool     `json:"dismiss_stale_approvals"`
+	EnableForcePush       bool     `json:"enable_force_push"`
+}
+
+func (p *GiteaProvider) ProtectBranch(org string, repo string, protection *GitBranchProtection) error {
+	body := &giteaBranchProtection{
+		EnableForcePush:       protection.AllowForcePushes,
+		EnablePush:            protection.RequiredApprovingReviews == 0 && len(protection.RequiredStatusContexts) == 0,
+		EnableStatusCheck:     len(protection.RequiredStatusContexts) > 0,
+		StatusCheckContexts:   protection.RequiredStatusContexts,
+		RequiredApprovals:     int64(protection.RequiredApprovingReviews),
+		DismissStaleApprovals: protection.DismissStaleReviews,
+	}
+	path := fmt.Sprintf("repos/%s/%s/branch_protections", org, repo)
+
+	// the SDK has no operations for branch protections so lets invoke the REST API directly
+	status, err := p.doRequest(http.MethodGet, path+"/"+protection.Branch, nil, nil)
+	if err != nil && status != http.StatusNotFound {
+		return err
+	}
+	log.Infof("Protecting Gitea branch %s of %s/%s\n", protection.Branch, org, repo)
+	result := &giteaBranchProtection{}
+	if status == http.StatusNotFound {
+		body.BranchName = protection.Branch
+		_, err = p.doRequest(http.MethodPost, path, body, result)
+	} else {
+		_, err = p.doRequest(http.MethodPatch, path+"/"+protection.Branch, body, result)
+	}
+	if err != nil {
+		return err
+	}
+	// older versions of Gitea ignore the force pushes
+	if protection.AllowForcePushes && !result.EnableForcePush {
+		return fmt.Errorf("this Gitea server does not support allowing force pushes to the protected branch %s of %s/%s", protection.Branch, org, repo)
+	}
+	return nil
+}
+
+// doRequest invokes the Gitea REST API returning the status code of the response and decoding the response into
+// the result if it is not nil
+func (p *GiteaProvider) doRequest(method string, path string, body interface{}, result interface{}) (int, error) {
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	u := util.UrlJoin(p.Server.URL, "api/v1", path)
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "token "+p.User.ApiToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := ioutil.ReadAll(resp.Body)
+		return resp.StatusCode, fmt.Errorf("%s %s failed: %s %s", method, u, resp.Status, string(data))
+	}
+	if result != nil {
+		return resp.StatusCode, json.NewDecoder(resp.Body).Decode(result)
+	}
+	return resp.StatusCode, nil
+}
+
 func (p *GiteaProvider) CreateWebHook(data *GitWebHookArguments) error {
 	owner := data.Owner
 	if owner == "" {
@@ -520,6 +673,27 @@ func (p *GiteaProvider) ListCommitStatus(org string, repo string, sha string) ([
 	return answer, nil
 }
 
+// CreateCommitStatus creates a status on the given commit
+func (p *GiteaProvider) CreateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	result, err := p.Client.CreateStatus(org, repo, sha, gitea.CreateStatusOption{
+		State:       gitea.StatusState(status.State),
+		TargetURL:   status.TargetURL,
+		Description: status.Description,
+		Context:     status.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepoStatus{
+		ID:          strconv.FormatInt(result.ID, 10),
+		Context:     result.Context,
+		URL:         result.URL,
+		TargetURL:   result.TargetURL,
+		State:       string(result.State),
+		Description: result.Description,
+	}, nil
+}
+
 func (p *GiteaProvider) RenameRepository(org string, name string, newName string) (*GitRepository, error) {
 	return nil, fmt.Errorf("Rename of repositories is not supported for Gitea")
 }
@@ -661,16 +835,45 @@ func (p *GiteaProvider) UserInfo(username string) *GitUser {
 }
 
 func (p *GiteaProvider) AddCollaborator(user string, organisation string, repo string) error {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Gitea. Please add user: %v as a collaborator to this project.\n", user)
-	return nil
+	log.Infof("Automatically adding the pipeline user: %v as a collaborator.\n", user)
+	// Gitea adds collaborators directly so there is no invitation to accept
+	permission := GitPermissionWrite
+	return p.Client.AddCollaborator(organisation, repo, user, gitea.AddCollaboratorOption{
+		Permission: &permission,
+	})
+}
+
+func (p *GiteaProvider) ListCollaborators(organisation string, repo string) ([]*GitCollaborator, error) {
+	users, err := p.Client.ListCollaborators(organisation, repo)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitCollaborator{}
+	for _, user := range users {
+		// the Gitea API does not return the permission of collaborators
+		answer = append(answer, &GitCollaborator{
+			Login: user.UserName,
+		})
+	}
+	return answer, nil
 }
 
-func (p *GiteaProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Gitea.\n")
-	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+func (p *GiteaProvider) AddOrganisationMember(organisation string, user string) error {
+	// Gitea adds members directly so there is no invitation to accept
+	return p.Client.AddOrgMembership(organisation, user, gitea.AddOrgMembershipOption{
+		Role: "member",
+	})
 }
 
-func (p *GiteaProvider) AcceptInvitation(ID int64) (*github.Response, error) {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Gitea.\n")
-	return &github.Response{}, nil
+func (p *GiteaProvider) RemoveOrganisationMember(organisation string, user string) error {
+	return fmt.Errorf("removing members of an organisation is not supported by the Gitea API. Please remove %s from %s", user, organisation)
+}
+
+func (p *GiteaProvider) ListInvitations() ([]*GitInvitation, error) {
+	// Gitea adds collaborators without an invitation
+	return []*GitInvitation{}, nil
+}
+
+func (p *GiteaProvider) AcceptInvitation(ID string) error {
+	return nil
 }
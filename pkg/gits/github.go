@@ -3,6 +3,7 @@ package gits
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -40,20 +41,36 @@ func NewGitHubProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter)
 		Git:      git,
 	}
 
+	var err error
+	provider.Client, err = newGitHubClient(ctx, server.URL, user.ApiToken, nil)
+	return &provider, err
+}
+
+// newGitHubClient creates a GitHub client for the server optionally wrapping its HTTP transport
+func newGitHubClient(ctx context.Context, serverURL string, token string, wrap func(http.RoundTripper) http.RoundTripper) (*github.Client, error) {
 	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: user.ApiToken},
+		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(ctx, ts)
+	if wrap != nil {
+		tc.Transport = wrap(tc.Transport)
+	}
 
-	var err error
-	u := server.URL
+	u := serverURL
 	if IsGitHubServerURL(u) {
-		provider.Client = github.NewClient(tc)
-	} else {
-		u = GitHubEnterpriseApiEndpointURL(u)
-		provider.Client, err = github.NewEnterpriseClient(u, u, tc)
+		return github.NewClient(tc), nil
 	}
-	return &provider, err
+	u = GitHubEnterpriseApiEndpointURL(u)
+	return github.NewEnterpriseClient(u, u, tc)
+}
+
+func (p *GitHubProvider) wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) error {
+	client, err := newGitHubClient(p.Context, p.Server.URL, p.User.ApiToken, wrap)
+	if err != nil {
+		return err
+	}
+	p.Client = client
+	return nil
 }
 
 func GitHubEnterpriseApiEndpointURL(u string) string {
@@ -246,6 +263,8 @@ func toGitHubRepo(name string, repo *github.Repository) *GitRepository {
 		Fork:             asBool(repo.Fork),
 		Language:         asText(repo.Language),
 		Stars:            asInt(repo.StargazersCount),
+		Description:      asText(repo.Description),
+		Topics:           repo.Topics,
 	}
 }
 
@@ -336,6 +355,118 @@ func (p *GitHubProvider) CreateWebHook(data *GitWebHookArguments) error {
 	return err
 }
 
+func (p *GitHubProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error) {
+	answer := []*GitWebHookArguments{}
+	options := &github.ListOptions{
+		Page:    0,
+		PerPage: pageSize,
+	}
+	for {
+		hooks, _, err := p.Client.Repositories.ListHooks(p.Context, owner, repo, options)
+		if err != nil {
+			return answer, err
+		}
+		for _, hook := range hooks {
+			hookURL, _ := hook.Config["url"].(string)
+			answer = append(answer, &GitWebHookArguments{
+				ID:    strconv.FormatInt(hook.GetID(), 10),
+				Owner: owner,
+				Repo:  &GitRepositoryInfo{Organisation: owner, Name: repo},
+				URL:   hookURL,
+			})
+		}
+		if len(hooks) < pageSize || len(hooks) == 0 {
+			break
+		}
+		options.Page++
+	}
+	return answer, nil
+}
+
+func (p *GitHubProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	owner := data.Owner
+	if owner == "" {
+		owner = p.Username
+	}
+	id, err := strconv.ParseInt(data.ID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid webhook ID %s: %s", data.ID, err)
+	}
+	config := map[string]interface{}{
+		"url":          data.URL,
+		"content_type": "json",
+	}
+	if data.Secret != "" {
+		config["secret"] = data.Secret
+	}
+	hook := &github.Hook{
+		Config: config,
+		Events: []string{"*"},
+	}
+	log.Infof("Updating GitHub webhook %s for %s/%s to url %s\n", data.ID, owner, data.Repo.Name, data.URL)
+	_, _, err = p.Client.Repositories.EditHook(p.Context, owner, data.Repo.Name, id, hook)
+	return err
+}
+
+func (p *GitHubProvider) DeleteWebHook(owner string, repo string, id string) error {
+	hookID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid webhook ID %s: %s", id, err)
+	}
+	_, err = p.Client.Repositories.DeleteHook(p.Context, owner, repo, hookID)
+	return err
+}
+
+// githubProtectionRequest adds the force pushes which the client does not support to the branch protection request
+type githubProtectionRequest struct {
+	*github.ProtectionRequest
+	AllowForcePushes bool `json:"allow_force_pushes"`
+}
+
+// githubProtection the fields of a branch protection which the client does not support
+type githubProtection struct {
+	AllowForcePushes struct {
+		Enabled bool `json:"enabled"`
+	} `json:"allow_force_pushes"`
+}
+
+func (p *GitHubProvider) ProtectBranch(org string, repo string, protection *GitBranchProtection) error {
+	request := &github.ProtectionRequest{
+		EnforceAdmins: protection.EnforceAdmins,
+	}
+	if len(protection.RequiredStatusContexts) > 0 {
+		request.RequiredStatusChecks = &github.RequiredStatusChecks{
+			Strict:   protection.RequireUpToDate,
+			Contexts: protection.RequiredStatusContexts,
+		}
+	}
+	if protection.RequiredApprovingReviews > 0 {
+		request.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{
+			DismissStaleReviews:          protection.DismissStaleReviews,
+			RequiredApprovingReviewCount: protection.RequiredApprovingReviews,
+		}
+	}
+	log.Infof("Protecting GitHub branch %s of %s/%s\n", protection.Branch, org, repo)
+	u := fmt.Sprintf("repos/%s/%s/branches/%s/protection", org, repo, protection.Branch)
+	req, err := p.Client.NewRequest("PUT", u, &githubProtectionRequest{
+		ProtectionRequest: request,
+		AllowForcePushes:  protection.AllowForcePushes,
+	})
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github.luke-cage-preview+json")
+	result := &githubProtection{}
+	_, err = p.Client.Do(p.Context, req, result)
+	if err != nil {
+		return err
+	}
+	if protection.AllowForcePushes && !result.AllowForcePushes.Enabled {
+		return fmt.Errorf("GitHub does not support allowing force pushes to the protected branch %s of %s/%s", protection.Branch, org, repo)
+	}
+	return nil
+}
+
 func (p *GitHubProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
 	owner := data.GitRepositoryInfo.Organisation
 	repo := data.GitRepositoryInfo.Name
@@ -401,6 +532,11 @@ func (p *GitHubProvider) UpdatePullRequestStatus(pr *GitPullRequest) error {
 	if result.MergedAt != nil {
 		pr.MergedAt = result.MergedAt
 	}
+	if result.MergedBy != nil && result.MergedBy.Login != nil {
+		pr.MergedBy = &GitUser{
+			Login: *result.MergedBy.Login,
+		}
+	}
 	if result.State != nil {
 		pr.State = result.State
 	}
@@ -591,6 +727,34 @@ func (p *GitHubProvider) ListCommitStatus(org string, repo string, sha string) (
 	return answer, nil
 }
 
+// CreateCommitStatus creates a status on the given commit
+func (p *GitHubProvider) CreateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	repoStatus := &github.RepoStatus{
+		State: github.String(status.State),
+	}
+	if status.TargetURL != "" {
+		repoStatus.TargetURL = github.String(status.TargetURL)
+	}
+	if status.Description != "" {
+		repoStatus.Description = github.String(status.Description)
+	}
+	if status.Context != "" {
+		repoStatus.Context = github.String(status.Context)
+	}
+	result, _, err := p.Client.Repositories.CreateStatus(p.Context, org, repo, sha, repoStatus)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepoStatus{
+		ID:          strconv.FormatInt(result.GetID(), 10),
+		Context:     result.GetContext(),
+		URL:         result.GetURL(),
+		TargetURL:   result.GetTargetURL(),
+		State:       result.GetState(),
+		Description: result.GetDescription(),
+	}, nil
+}
+
 func notNullInt64(n *int64) int64 {
 	if n != nil {
 		return *n
@@ -908,20 +1072,114 @@ func (p *GitHubProvider) UserInfo(username string) *GitUser {
 
 func (p *GitHubProvider) AddCollaborator(user string, organisation string, repo string) error {
 	log.Infof("Automatically adding the pipeline user: %v as a collaborator.\n", user)
-	_, err := p.Client.Repositories.AddCollaborator(p.Context, organisation, repo, user, &github.RepositoryAddCollaboratorOptions{})
+	_, err := p.Client.Repositories.AddCollaborator(p.Context, organisation, repo, user, &github.RepositoryAddCollaboratorOptions{
+		Permission: "push",
+	})
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (p *GitHubProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
-	return p.Client.Users.ListInvitations(p.Context, &github.ListOptions{})
+func (p *GitHubProvider) ListCollaborators(organisation string, repo string) ([]*GitCollaborator, error) {
+	answer := []*GitCollaborator{}
+	options := &github.ListCollaboratorsOptions{
+		ListOptions: github.ListOptions{
+			Page:    0,
+			PerPage: pageSize,
+		},
+	}
+	for {
+		users, _, err := p.Client.Repositories.ListCollaborators(p.Context, organisation, repo, options)
+		if err != nil {
+			return answer, err
+		}
+		for _, user := range users {
+			answer = append(answer, &GitCollaborator{
+				Login:      user.GetLogin(),
+				Permission: gitHubPermission(user.GetPermissions()),
+			})
+		}
+		if len(users) < pageSize || len(users) == 0 {
+			break
+		}
+		options.Page++
+	}
+	return answer, nil
 }
 
-func (p *GitHubProvider) AcceptInvitation(ID int64) (*github.Response, error) {
+// gitHubPermission returns the highest of the GitHub permissions of a collaborator
+func gitHubPermission(permissions map[string]bool) string {
+	switch {
+	case permissions["admin"]:
+		return GitPermissionAdmin
+	case permissions["push"]:
+		return GitPermissionWrite
+	case permissions["pull"]:
+		return GitPermissionRead
+	}
+	return ""
+}
+
+func (p *GitHubProvider) AddOrganisationMember(organisation string, user string) error {
+	membership, _, err := p.Client.Organizations.GetOrgMembership(p.Context, user, organisation)
+	if err == nil && membership != nil {
+		// lets not demote admins or resend pending invitations
+		return nil
+	}
+	log.Infof("Inviting %s to the GitHub organisation %s\n", user, organisation)
+	_, _, err = p.Client.Organizations.EditOrgMembership(p.Context, user, organisation, &github.Membership{
+		Role: github.String("member"),
+	})
+	return err
+}
+
+func (p *GitHubProvider) RemoveOrganisationMember(organisation string, user string) error {
+	_, err := p.Client.Organizations.RemoveOrgMembership(p.Context, user, organisation)
+	return err
+}
+
+func (p *GitHubProvider) ListInvitations() ([]*GitInvitation, error) {
+	answer := []*GitInvitation{}
+	options := &github.ListOptions{
+		Page:    0,
+		PerPage: pageSize,
+	}
+	for {
+		invites, _, err := p.Client.Users.ListInvitations(p.Context, options)
+		if err != nil {
+			return answer, err
+		}
+		for _, invite := range invites {
+			repo := invite.GetRepo()
+			answer = append(answer, &GitInvitation{
+				ID:           strconv.FormatInt(invite.GetID(), 10),
+				Organisation: repo.GetOwner().GetLogin(),
+				Repository:   repo.GetName(),
+				Inviter:      invite.GetInviter().GetLogin(),
+				Permission:   invite.GetPermissions(),
+				URL:          invite.GetHTMLURL(),
+			})
+		}
+		if len(invites) < pageSize || len(invites) == 0 {
+			break
+		}
+		options.Page++
+	}
+	return answer, nil
+}
+
+func (p *GitHubProvider) AcceptInvitation(ID string) error {
+	id, err := strconv.ParseInt(ID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid invitation ID %s: %s", ID, err)
+	}
+	_, err = p.Client.Users.AcceptInvitation(p.Context, id)
+	if err != nil {
+		return err
+	}
 	log.Infof("Automatically accepted invitation: %v for the pipeline user.\n", ID)
-	return p.Client.Users.AcceptInvitation(p.Context, ID)
+	return nil
 }
 
 func asBool(b *bool) bool {
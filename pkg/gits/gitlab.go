@@ -3,11 +3,11 @@ package gits
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
@@ -22,17 +22,24 @@ type GitlabProvider struct {
 	Server auth.AuthServer
 	User   auth.UserAuth
 	Git    Gitter
+
+	// httpClient the HTTP client of the gitlab client so that its transport can be wrapped
+	httpClient *http.Client
 }
 
 func NewGitlabProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter) (GitProvider, error) {
 	u := server.URL
-	c := gitlab.NewClient(nil, user.ApiToken)
+	// lets not use http.DefaultClient so that the transport of this provider can be wrapped on its own
+	httpClient := &http.Client{}
+	c := gitlab.NewClient(httpClient, user.ApiToken)
 	if !IsGitLabServerURL(u) {
 		if err := c.SetBaseURL(u); err != nil {
 			return nil, err
 		}
 	}
-	return WithGitlabClient(server, user, c, git)
+	provider := newGitlabProvider(server, user, c, git)
+	provider.httpClient = httpClient
+	return provider, nil
 }
 
 func IsGitLabServerURL(u string) bool {
@@ -40,16 +47,29 @@ func IsGitLabServerURL(u string) bool {
 	return u == "" || u == "https://gitlab.com" || u == "http://gitlab.com"
 }
 
+// wrapTransport wraps the transport of the HTTP client of the gitlab client so that the client keeps its base URL and
+// token. The transport of a client injected with WithGitlabClient is left alone as its HTTP client is not known
+func (g *GitlabProvider) wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) error {
+	if g.httpClient == nil {
+		return nil
+	}
+	g.httpClient.Transport = wrap(clientTransport(g.httpClient))
+	return nil
+}
+
 // Used by unit tests to inject a mocked client
 func WithGitlabClient(server *auth.AuthServer, user *auth.UserAuth, client *gitlab.Client, git Gitter) (GitProvider, error) {
-	provider := &GitlabProvider{
+	return newGitlabProvider(server, user, client, git), nil
+}
+
+func newGitlabProvider(server *auth.AuthServer, user *auth.UserAuth, client *gitlab.Client, git Gitter) *GitlabProvider {
+	return &GitlabProvider{
 		Server:   *server,
 		User:     *user,
 		Username: user.Username,
 		Client:   client,
 		Git:      git,
 	}
-	return provider, nil
 }
 
 func (g *GitlabProvider) ListRepositories(org string) ([]*GitRepository, error) {
@@ -85,11 +105,14 @@ func getRepositories(g *gitlab.Client, username string, org string) ([]*gitlab.P
 
 func fromGitlabProject(p *gitlab.Project) *GitRepository {
 	return &GitRepository{
-		Name:     p.Name,
-		HTMLURL:  p.WebURL,
-		SSHURL:   p.SSHURLToRepo,
-		CloneURL: p.HTTPURLToRepo,
-		Fork:     p.ForkedFromProject != nil,
+		Name:        p.Name,
+		HTMLURL:     p.WebURL,
+		SSHURL:      p.SSHURLToRepo,
+		CloneURL:    p.HTTPURLToRepo,
+		Fork:        p.ForkedFromProject != nil,
+		Stars:       p.StarCount,
+		Description: p.Description,
+		Topics:      p.TagList,
 	}
 }
 
@@ -239,6 +262,13 @@ func fromMergeRequest(mr *gitlab.MergeRequest, owner, repo string) *GitPullReque
 	if mr.MergedAt != nil {
 		merged = true
 	}
+	var mergedBy *GitUser
+	if mr.MergedBy.Username != "" {
+		mergedBy = &GitUser{
+			Login: mr.MergedBy.Username,
+			Name:  mr.MergedBy.Name,
+		}
+	}
 	return &GitPullRequest{
 		Author: &GitUser{
 			Login: mr.Author.Username,
@@ -254,6 +284,7 @@ func fromMergeRequest(mr *gitlab.MergeRequest, owner, repo string) *GitPullReque
 		Merged:         &merged,
 		LastCommitSha:  mr.SHA,
 		MergedAt:       mr.MergedAt,
+		MergedBy:       mergedBy,
 		ClosedAt:       mr.ClosedAt,
 	}
 }
@@ -376,6 +407,45 @@ func fromCommitStatus(status *gitlab.CommitStatus) *GitRepoStatus {
 	}
 }
 
+// CreateCommitStatus creates a status on the given commit
+func (g *GitlabProvider) CreateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	pid, err := g.projectId(org, g.Username, repo)
+	if err != nil {
+		return nil, err
+	}
+	opt := &gitlab.SetCommitStatusOptions{
+		State: gitlabBuildState(status.State),
+	}
+	if status.Context != "" {
+		opt.Name = &status.Context
+	}
+	if status.TargetURL != "" {
+		opt.TargetURL = &status.TargetURL
+	}
+	if status.Description != "" {
+		opt.Description = &status.Description
+	}
+	result, _, err := g.Client.Commits.SetCommitStatus(pid, sha, opt)
+	if err != nil {
+		return nil, err
+	}
+	return fromCommitStatus(result), nil
+}
+
+// gitlabBuildState converts the state of a status into the GitLab build state
+func gitlabBuildState(state string) gitlab.BuildStateValue {
+	switch state {
+	case "success":
+		return gitlab.Success
+	case "error", "failure":
+		return gitlab.Failed
+	case "running", "in-progress":
+		return gitlab.Running
+	default:
+		return gitlab.Pending
+	}
+}
+
 func (g *GitlabProvider) MergePullRequest(pr *GitPullRequest, message string) error {
 	pid, err := g.projectId(pr.Owner, g.Username, pr.Repo)
 	if err != nil {
@@ -405,6 +475,133 @@ func (g *GitlabProvider) CreateWebHook(data *GitWebHookArguments) error {
 	return err
 }
 
+func (g *GitlabProvider) ListWebHooks(org string, repo string) ([]*GitWebHookArguments, error) {
+	pid, err := g.projectId(org, g.Username, repo)
+	if err != nil {
+		return nil, err
+	}
+	hooks, _, err := g.Client.Projects.ListProjectHooks(pid, nil)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitWebHookArguments{}
+	for _, hook := range hooks {
+		answer = append(answer, &GitWebHookArguments{
+			ID:    strconv.Itoa(hook.ID),
+			Owner: org,
+			Repo:  &GitRepositoryInfo{Organisation: org, Name: repo},
+			URL:   hook.URL,
+		})
+	}
+	return answer, nil
+}
+
+func (g *GitlabProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	pid, err := g.projectId(data.Owner, g.Username, data.Repo.Name)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(data.ID)
+	if err != nil {
+		return fmt.Errorf("Invalid webhook ID %s: %s", data.ID, err)
+	}
+
+	owner := owner(g.Username, data.Owner)
+	webhookURL := util.UrlJoin(data.URL, owner, data.Repo.Name)
+	opt := &gitlab.EditProjectHookOptions{
+		URL:   &webhookURL,
+		Token: &data.Secret,
+	}
+	_, _, err = g.Client.Projects.EditProjectHook(pid, id, opt)
+	return err
+}
+
+func (g *GitlabProvider) DeleteWebHook(org string, repo string, id string) error {
+	pid, err := g.projectId(org, g.Username, repo)
+	if err != nil {
+		return err
+	}
+	hookID, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("Invalid webhook ID %s: %s", id, err)
+	}
+	_, err = g.Client.Projects.DeleteProjectHook(pid, hookID)
+	return err
+}
+
+// gitlabMergeSettings the project settings which control when merge requests can be merged
+type gitlabMergeSettings struct {
+	OnlyAllowMergeIfPipelineSucceeds *bool `url:"only_allow_merge_if_pipeline_succeeds,omitempty" json:"only_allow_merge_if_pipeline_succeeds,omitempty"`
+	ApprovalsBeforeMerge             *int  `url:"approvals_before_merge,omitempty" json:"approvals_before_merge,omitempty"`
+}
+
+// gitlabProtectBranchOptions adds the force pushes which the client does not support to the protected branch options
+type gitlabProtectBranchOptions struct {
+	Name             *string                  `json:"name,omitempty"`
+	PushAccessLevel  *gitlab.AccessLevelValue `json:"push_access_level,omitempty"`
+	MergeAccessLevel *gitlab.AccessLevelValue `json:"merge_access_level,omitempty"`
+	AllowForcePush   bool                     `json:"allow_force_push"`
+}
+
+// gitlabProtectedBranch the fields of a protected branch which the client does not support
+type gitlabProtectedBranch struct {
+	AllowForcePush bool `json:"allow_force_push"`
+}
+
+func (g *GitlabProvider) ProtectBranch(org string, repo string, protection *GitBranchProtection) error {
+	pid, err := g.projectId(org, g.Username, repo)
+	if err != nil {
+		return err
+	}
+
+	// lets replace any existing protection as GitLab does not support updating protected branches
+	resp, err := g.Client.ProtectedBranches.UnprotectRepositoryBranches(pid, protection.Branch)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return fmt.Errorf("Failed to remove the existing protection of branch %s of %s/%s: %s", protection.Branch, org, repo, err)
+	}
+
+	// maintainers, such as the pipeline user which tags and commits releases, can still push while the reviews and
+	// the pipeline are required to merge
+	pushAccessLevel := gitlab.MasterPermissions
+	mergeAccessLevel := gitlab.DeveloperPermissions
+	log.Infof("Protecting GitLab branch %s of %s/%s\n", protection.Branch, org, repo)
+	req, err := g.Client.NewRequest("POST", "projects/"+pid+"/protected_branches", &gitlabProtectBranchOptions{
+		Name:             &protection.Branch,
+		PushAccessLevel:  &pushAccessLevel,
+		MergeAccessLevel: &mergeAccessLevel,
+		AllowForcePush:   protection.AllowForcePushes,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	protected := &gitlabProtectedBranch{}
+	_, err = g.Client.Do(req, protected)
+	if err != nil {
+		return err
+	}
+	if protection.AllowForcePushes && !protected.AllowForcePush {
+		return fmt.Errorf("this GitLab server does not support allowing force pushes to the protected branch %s of %s/%s", protection.Branch, org, repo)
+	}
+
+	// GitLab cannot require specific status contexts so lets require the pipeline to succeed instead
+	settings := &gitlabMergeSettings{}
+	if len(protection.RequiredStatusContexts) > 0 {
+		settings.OnlyAllowMergeIfPipelineSucceeds = gitlab.Bool(true)
+	}
+	if protection.RequiredApprovingReviews > 0 {
+		settings.ApprovalsBeforeMerge = &protection.RequiredApprovingReviews
+	}
+	if settings.OnlyAllowMergeIfPipelineSucceeds == nil && settings.ApprovalsBeforeMerge == nil {
+		return nil
+	}
+	req, err = g.Client.NewRequest("PUT", "projects/"+pid, settings, nil)
+	if err != nil {
+		return err
+	}
+	_, err = g.Client.Do(req, nil)
+	return err
+}
+
 func (g *GitlabProvider) SearchIssues(org, repo, query string) ([]*GitIssue, error) {
 	opt := &gitlab.ListProjectIssuesOptions{Search: &query}
 	return g.searchIssuesWithOptions(org, repo, opt)
@@ -605,19 +802,122 @@ func (p *GitlabProvider) IssueURL(org string, name string, number int, isPull bo
 	return ""
 }
 
-func (p *GitlabProvider) AddCollaborator(user string, organisation string, repo string) error {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for gitlab. Please add user: %v as a collaborator to this project.\n", user)
-	return nil
+func (g *GitlabProvider) AddCollaborator(user string, organisation string, repo string) error {
+	pid, err := g.projectId(organisation, g.Username, repo)
+	if err != nil {
+		return err
+	}
+	userID, err := g.userID(user)
+	if err != nil {
+		return err
+	}
+	member, _, err := g.Client.ProjectMembers.GetProjectMember(pid, userID)
+	if err == nil && member != nil && member.AccessLevel >= gitlab.DeveloperPermissions {
+		return nil
+	}
+	// GitLab adds members directly so there is no invitation to accept
+	log.Infof("Automatically adding the pipeline user: %v as a member of the project.\n", user)
+	accessLevel := gitlab.DeveloperPermissions
+	if err == nil && member != nil {
+		_, _, err = g.Client.ProjectMembers.EditProjectMember(pid, userID, &gitlab.EditProjectMemberOptions{
+			AccessLevel: &accessLevel,
+		})
+		return err
+	}
+	_, _, err = g.Client.ProjectMembers.AddProjectMember(pid, &gitlab.AddProjectMemberOptions{
+		UserID:      &userID,
+		AccessLevel: &accessLevel,
+	})
+	return err
 }
 
-func (p *GitlabProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for gitlab.\n")
-	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+func (g *GitlabProvider) ListCollaborators(organisation string, repo string) ([]*GitCollaborator, error) {
+	pid, err := g.projectId(organisation, g.Username, repo)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitCollaborator{}
+	opt := &gitlab.ListProjectMembersOptions{
+		ListOptions: gitlab.ListOptions{
+			Page:    1,
+			PerPage: pageSize,
+		},
+	}
+	for {
+		members, resp, err := g.Client.ProjectMembers.ListProjectMembers(pid, opt)
+		if err != nil {
+			return answer, err
+		}
+		for _, member := range members {
+			answer = append(answer, &GitCollaborator{
+				Login:      member.Username,
+				Permission: gitlabPermission(member.AccessLevel),
+			})
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return answer, nil
 }
 
-func (p *GitlabProvider) AcceptInvitation(ID int64) (*github.Response, error) {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for gitlab.\n")
-	return &github.Response{}, nil
+// gitlabPermission converts the access level of a GitLab project member into a git permission
+func gitlabPermission(level gitlab.AccessLevelValue) string {
+	switch {
+	case level >= gitlab.MasterPermissions:
+		return GitPermissionAdmin
+	case level >= gitlab.DeveloperPermissions:
+		return GitPermissionWrite
+	}
+	return GitPermissionRead
+}
+
+func (g *GitlabProvider) AddOrganisationMember(organisation string, user string) error {
+	userID, err := g.userID(user)
+	if err != nil {
+		return err
+	}
+	member, _, err := g.Client.GroupMembers.GetGroupMember(organisation, userID)
+	if err == nil && member != nil {
+		return nil
+	}
+	// GitLab adds members directly so there is no invitation to accept
+	accessLevel := gitlab.DeveloperPermissions
+	_, _, err = g.Client.GroupMembers.AddGroupMember(organisation, &gitlab.AddGroupMemberOptions{
+		UserID:      &userID,
+		AccessLevel: &accessLevel,
+	})
+	return err
+}
+
+func (g *GitlabProvider) RemoveOrganisationMember(organisation string, user string) error {
+	userID, err := g.userID(user)
+	if err != nil {
+		return err
+	}
+	_, err = g.Client.GroupMembers.RemoveGroupMember(organisation, userID)
+	return err
+}
+
+func (g *GitlabProvider) userID(user string) (int, error) {
+	users, _, err := g.Client.Users.ListUsers(&gitlab.ListUsersOptions{Username: &user})
+	if err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, fmt.Errorf("no user found with username %s", user)
+	}
+	return users[0].ID, nil
+}
+
+func (g *GitlabProvider) ListInvitations() ([]*GitInvitation, error) {
+	// GitLab adds project members without an invitation
+	return []*GitInvitation{}, nil
+}
+
+func (g *GitlabProvider) AcceptInvitation(ID string) error {
+	return nil
 }
 
 // GitlabAccessTokenURL returns the URL to click on to generate a personal access token for the Git provider
@@ -1,6 +1,7 @@
 package gits_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"fmt"
@@ -20,6 +21,9 @@ const (
 	gitlabOrgName     = "testorg"
 	gitlabProjectName = "test-project"
 	gitlabProjectID   = "5690870"
+
+	gitlabOrgProjectName = "orgproject"
+	gitlabOrgProjectID   = "5861335"
 )
 
 type GitlabProviderSuite struct {
@@ -97,6 +101,13 @@ func configureGitlabMock(suite *GitlabProviderSuite, mux *http.ServeMux) {
 		fmt.Sprintf("/api/v4/projects/%s", gitlabProjectID): util.MethodMap{
 			"GET": "project.json",
 		},
+		"/api/v4/users": util.MethodMap{
+			"GET": "users.json",
+		},
+		fmt.Sprintf("/api/v4/projects/%s/members", gitlabOrgProjectID): util.MethodMap{
+			"GET":  "members.json",
+			"POST": "member.json",
+		},
 	}
 	for path, methodMap := range gitlabRouter {
 		mux.HandleFunc(path, util.GetMockAPIResponseFromFile("test_data/gitlab", methodMap))
@@ -155,20 +166,49 @@ func (suite *GitlabProviderSuite) TestGetRepository() {
 }
 
 func (suite *GitlabProviderSuite) TestAddCollaborator() {
-	err := suite.provider.AddCollaborator("derek", orgName, "repo")
+	err := suite.provider.AddCollaborator("derek", gitlabOrgName, gitlabOrgProjectName)
 	suite.Require().Nil(err)
 }
 
+func (suite *GitlabProviderSuite) TestListCollaborators() {
+	collaborators, err := suite.provider.ListCollaborators(gitlabOrgName, gitlabOrgProjectName)
+	suite.Require().Nil(err)
+	suite.Require().Len(collaborators, 2)
+	suite.Require().Equal(gits.GitCollaborator{Login: "testperson", Permission: gits.GitPermissionAdmin}, *collaborators[0])
+	suite.Require().Equal(gits.GitCollaborator{Login: "derek", Permission: gits.GitPermissionWrite}, *collaborators[1])
+}
+
+func (suite *GitlabProviderSuite) TestProtectBranch() {
+	var requested map[string]interface{}
+	suite.mux.HandleFunc(fmt.Sprintf("/api/v4/projects/%s/protected_branches/master", gitlabOrgProjectID), func(w http.ResponseWriter, r *http.Request) {
+		suite.Require().Equal(http.MethodDelete, r.Method)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	suite.mux.HandleFunc(fmt.Sprintf("/api/v4/projects/%s/protected_branches", gitlabOrgProjectID), func(w http.ResponseWriter, r *http.Request) {
+		suite.Require().Equal(http.MethodPost, r.Method)
+		requested = map[string]interface{}{}
+		suite.Require().Nil(json.NewDecoder(r.Body).Decode(&requested))
+		// lets be a GitLab server which does not support force pushes
+		w.Write([]byte(`{"name": "master"}`))
+	})
+
+	err := suite.provider.ProtectBranch(gitlabOrgName, gitlabOrgProjectName, &gits.GitBranchProtection{Branch: "master"})
+	suite.Require().Nil(err)
+	suite.Require().Equal(float64(gitlab.MasterPermissions), requested["push_access_level"], "maintainers should still be able to push")
+
+	err = suite.provider.ProtectBranch(gitlabOrgName, gitlabOrgProjectName, &gits.GitBranchProtection{Branch: "master", AllowForcePushes: true})
+	suite.Require().Error(err)
+	suite.Require().Equal(true, requested["allow_force_push"])
+}
+
 func (suite *GitlabProviderSuite) TestListInvitations() {
-	invites, res, err := suite.provider.ListInvitations()
+	invites, err := suite.provider.ListInvitations()
 	suite.Require().NotNil(invites)
-	suite.Require().NotNil(res)
 	suite.Require().Nil(err)
 }
 
 func (suite *GitlabProviderSuite) TestAcceptInvitations() {
-	res, err := suite.provider.AcceptInvitation(1)
-	suite.Require().NotNil(res)
+	err := suite.provider.AcceptInvitation("1")
 	suite.Require().Nil(err)
 }
 
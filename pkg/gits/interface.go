@@ -4,7 +4,6 @@ import (
 	"io"
 	"time"
 
-	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/pkg/auth"
 	gitcfg "gopkg.in/src-d/go-git.v4/config"
 )
@@ -52,10 +51,20 @@ type GitProvider interface {
 
 	ListCommitStatus(org string, repo string, sha string) ([]*GitRepoStatus, error)
 
+	CreateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error)
+
 	MergePullRequest(pr *GitPullRequest, message string) error
 
 	CreateWebHook(data *GitWebHookArguments) error
 
+	ListWebHooks(org string, repo string) ([]*GitWebHookArguments, error)
+
+	UpdateWebHook(data *GitWebHookArguments) error
+
+	DeleteWebHook(org string, repo string, id string) error
+
+	ProtectBranch(org string, repo string, protection *GitBranchProtection) error
+
 	IsGitHub() bool
 
 	IsGitea() bool
@@ -128,11 +137,23 @@ type GitProvider interface {
 	// Returns user info, if possible
 	UserInfo(username string) *GitUser
 
-	AddCollaborator(string, string, string) error
-	// TODO Refactor to remove bespoke types when we implement another provider
-	ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error)
-	// TODO Refactor to remove bespoke types when we implement another provider
-	AcceptInvitation(int64) (*github.Response, error)
+	// AddCollaborator gives the user write access to the repository, inviting them if the git provider requires it
+	AddCollaborator(user string, organisation string, repo string) error
+
+	// ListCollaborators returns the users who have been given access to the repository
+	ListCollaborators(organisation string, repo string) ([]*GitCollaborator, error)
+
+	// ListInvitations returns the pending invitations of the current user
+	ListInvitations() ([]*GitInvitation, error)
+
+	// AcceptInvitation accepts the invitation of the current user with the given ID
+	AcceptInvitation(ID string) error
+
+	// AddOrganisationMember makes the user a member of the organisation, inviting them if the git provider requires it
+	AddOrganisationMember(organisation string, user string) error
+
+	// RemoveOrganisationMember removes the user from the members of the organisation
+	RemoveOrganisationMember(organisation string, user string) error
 }
 
 // Gitter defines common git actions used by Jenkins X via git cli
@@ -158,6 +179,7 @@ type Gitter interface {
 	Init(dir string) error
 	Clone(url string, directory string) error
 	Push(dir string) error
+	PushBranch(dir string, localBranch string, remoteBranch string) error
 	PushMaster(dir string) error
 	PushTag(dir string, tag string) error
 	CreatePushURL(cloneURL string, userAuth *auth.UserAuth) (string, error)
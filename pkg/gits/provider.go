@@ -26,6 +26,8 @@ type GitRepository struct {
 	Language         string
 	Fork             bool
 	Stars            int
+	Description      string
+	Topics           []string
 }
 
 type GitPullRequest struct {
@@ -44,6 +46,7 @@ type GitPullRequest struct {
 	MergeCommitSHA *string
 	ClosedAt       *time.Time
 	MergedAt       *time.Time
+	MergedBy       *GitUser
 	LastCommitSha  string
 	Title          string
 	Body           string
@@ -127,12 +130,63 @@ type GitPullRequestArguments struct {
 }
 
 type GitWebHookArguments struct {
+	// ID identifies an existing webhook when listing or updating webhooks
+	ID     string
 	Owner  string
 	Repo   *GitRepositoryInfo
 	URL    string
 	Secret string
 }
 
+// GitInvitation an invitation for the current user to collaborate on a git repository
+type GitInvitation struct {
+	ID           string
+	Organisation string
+	Repository   string
+	Inviter      string
+	Permission   string
+	URL          string
+}
+
+const (
+	// GitPermissionRead the permission to clone a repository
+	GitPermissionRead = "read"
+	// GitPermissionWrite the permission to push to a repository
+	GitPermissionWrite = "write"
+	// GitPermissionAdmin the permission to administer a repository
+	GitPermissionAdmin = "admin"
+)
+
+// GitCollaborator a user who has been given access to a git repository
+type GitCollaborator struct {
+	Login      string
+	Permission string
+}
+
+// GitBranchProtection the protection rules of a branch
+type GitBranchProtection struct {
+	Branch string
+
+	// RequiredStatusContexts the contexts of the commit statuses which must pass before a Pull Request can merge
+	RequiredStatusContexts []string
+
+	// RequiredApprovingReviews the number of approving reviews needed before a Pull Request can merge
+	RequiredApprovingReviews int
+
+	// DismissStaleReviews dismisses approving reviews when new commits are pushed
+	DismissStaleReviews bool
+
+	// EnforceAdmins applies the protection to administrators too
+	EnforceAdmins bool
+
+	// AllowForcePushes allows force pushes to the branch. Providers which protect branches but cannot allow them
+	// return an error
+	AllowForcePushes bool
+
+	// RequireUpToDate requires branches to be up to date with the protected branch before merging
+	RequireUpToDate bool
+}
+
 // IsClosed returns true if the PullRequest has been closed
 func (pr *GitPullRequest) IsClosed() bool {
 	return pr.ClosedAt != nil
@@ -140,10 +194,14 @@ func (pr *GitPullRequest) IsClosed() bool {
 
 func CreateProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter) (GitProvider, error) {
 	switch server.Kind {
+	case KindAzureDevOps:
+		return NewAzureDevOpsProvider(server, user, git)
 	case KindBitBucketCloud:
 		return NewBitbucketCloudProvider(server, user, git)
 	case KindBitBucketServer:
 		return NewBitbucketServerProvider(server, user, git)
+	case KindCodeCommit:
+		return NewCodeCommitProvider(server, user, git)
 	case KindGitea:
 		return NewGiteaProvider(server, user, git)
 	case KindGitlab:
@@ -171,11 +229,15 @@ func GetHost(gitProvider GitProvider) (string, error) {
 
 func ProviderAccessTokenURL(kind string, url string, username string) string {
 	switch kind {
+	case KindAzureDevOps:
+		return AzureDevOpsAccessTokenURL(url)
 	case KindBitBucketCloud:
 		// TODO pass in the username
 		return BitBucketCloudAccessTokenURL(url, username)
 	case KindBitBucketServer:
 		return BitBucketServerAccessTokenURL(url)
+	case KindCodeCommit:
+		return CodeCommitAccessTokenURL(url)
 	case KindGitea:
 		return GiteaAccessTokenURL(url)
 	case KindGitlab:
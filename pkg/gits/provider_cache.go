@@ -0,0 +1,292 @@
+package gits
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CachingGitProviderOptions configures a CachingGitProvider
+type CachingGitProviderOptions struct {
+	// TTL how long the results of read only calls are cached in memory. Results are not cached in memory if zero
+	TTL time.Duration
+
+	// CacheDir the directory in which HTTP responses are cached so that they can be revalidated with conditional
+	// requests across restarts. Responses are cached in memory if blank
+	CacheDir string
+
+	// MaxRateLimitWait the longest time to wait for the rate limit of the git server to reset before retrying
+	MaxRateLimitWait time.Duration
+
+	// Metrics collects the API usage. One is created if nil
+	Metrics *GitProviderMetrics
+}
+
+// GitProviderMetrics counts the usage of the API of a git provider
+type GitProviderMetrics struct {
+	// Calls the number of read only calls of the git provider
+	Calls int64
+	// CacheHits the number of calls answered from the in-memory cache
+	CacheHits int64
+	// Requests the number of HTTP requests sent to the git server
+	Requests int64
+	// NotModified the number of conditional requests answered from the HTTP cache
+	NotModified int64
+	// RateLimited the number of requests rejected by the rate limit of the git server
+	RateLimited int64
+	// RateLimitRemaining the number of requests left before the rate limit resets or -1 if unknown
+	RateLimitRemaining int64
+}
+
+// NewGitProviderMetrics creates the metrics of a git provider
+func NewGitProviderMetrics() *GitProviderMetrics {
+	return &GitProviderMetrics{
+		RateLimitRemaining: -1,
+	}
+}
+
+func (m *GitProviderMetrics) String() string {
+	return fmt.Sprintf("calls: %d, cache hits: %d, requests: %d, not modified: %d, rate limited: %d, rate limit remaining: %d",
+		atomic.LoadInt64(&m.Calls), atomic.LoadInt64(&m.CacheHits), atomic.LoadInt64(&m.Requests),
+		atomic.LoadInt64(&m.NotModified), atomic.LoadInt64(&m.RateLimited), atomic.LoadInt64(&m.RateLimitRemaining))
+}
+
+// CachingGitProvider decorates a GitProvider caching the results of read only calls in memory.
+// Write calls pass through and invalidate the cached results of the repository they change
+type CachingGitProvider struct {
+	GitProvider
+
+	TTL     time.Duration
+	Metrics *GitProviderMetrics
+
+	lock    sync.Mutex
+	entries map[string]*gitProviderCacheEntry
+}
+
+type gitProviderCacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// transportWrapper is implemented by the git providers whose HTTP transport can be wrapped
+type transportWrapper interface {
+	wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) error
+}
+
+// clientTransport returns the transport of the HTTP client or the default transport which the client uses if it has
+// none
+func clientTransport(client *http.Client) http.RoundTripper {
+	if client.Transport != nil {
+		return client.Transport
+	}
+	return http.DefaultTransport
+}
+
+// NewCachingGitProvider decorates the git provider with an in-memory cache. The HTTP transport of the GitHub, GitLab and
+// Gitea providers is also changed to revalidate cached responses with conditional requests and to back off when rate limited
+func NewCachingGitProvider(provider GitProvider, options CachingGitProviderOptions) (*CachingGitProvider, error) {
+	if caching, ok := provider.(*CachingGitProvider); ok {
+		return caching, nil
+	}
+	metrics := options.Metrics
+	if metrics == nil {
+		metrics = NewGitProviderMetrics()
+	}
+	if wrapper, ok := provider.(transportWrapper); ok {
+		cacheDir := options.CacheDir
+		if cacheDir != "" {
+			// lets not share cached responses between users or servers
+			server := provider.ServerURL()
+			server = strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+			cacheDir = filepath.Join(cacheDir, strings.Replace(server, "/", "_", -1), provider.CurrentUsername())
+		}
+		err := wrapper.wrapTransport(func(transport http.RoundTripper) http.RoundTripper {
+			return NewCachingTransport(transport, cacheDir, options.MaxRateLimitWait, metrics)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &CachingGitProvider{
+		GitProvider: provider,
+		TTL:         options.TTL,
+		Metrics:     metrics,
+		entries:     map[string]*gitProviderCacheEntry{},
+	}, nil
+}
+
+func providerCacheKey(org string, repo string, method string, args ...interface{}) string {
+	key := org + "|" + repo + "|" + method
+	for _, arg := range args {
+		key += fmt.Sprintf("|%v", arg)
+	}
+	return key
+}
+
+// cached returns the cached result for the key or invokes the function caching its result if it succeeds
+func (p *CachingGitProvider) cached(key string, fn func() (interface{}, error)) (interface{}, error) {
+	atomic.AddInt64(&p.Metrics.Calls, 1)
+	if p.TTL <= 0 {
+		return fn()
+	}
+	now := time.Now()
+	p.lock.Lock()
+	entry := p.entries[key]
+	p.lock.Unlock()
+	if entry != nil && now.Before(entry.expires) {
+		atomic.AddInt64(&p.Metrics.CacheHits, 1)
+		return entry.value, nil
+	}
+	value, err := fn()
+	if err != nil {
+		return value, err
+	}
+	p.lock.Lock()
+	p.entries[key] = &gitProviderCacheEntry{value: value, expires: now.Add(p.TTL)}
+	p.lock.Unlock()
+	return value, nil
+}
+
+// Invalidate removes the cached results of the repository
+func (p *CachingGitProvider) Invalidate(org string, repo string) {
+	prefix := org + "|" + repo + "|"
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for key := range p.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(p.entries, key)
+		}
+	}
+}
+
+func (p *CachingGitProvider) GetRepository(org string, name string) (*GitRepository, error) {
+	value, err := p.cached(providerCacheKey(org, name, "GetRepository"), func() (interface{}, error) {
+		return p.GitProvider.GetRepository(org, name)
+	})
+	repo, _ := value.(*GitRepository)
+	if repo != nil {
+		answer := *repo
+		repo = &answer
+	}
+	return repo, err
+}
+
+func (p *CachingGitProvider) GetPullRequest(owner string, repo *GitRepositoryInfo, number int) (*GitPullRequest, error) {
+	value, err := p.cached(providerCacheKey(owner, repo.Name, "GetPullRequest", number), func() (interface{}, error) {
+		return p.GitProvider.GetPullRequest(owner, repo, number)
+	})
+	pr, _ := value.(*GitPullRequest)
+	if pr != nil {
+		answer := *pr
+		pr = &answer
+	}
+	return pr, err
+}
+
+func (p *CachingGitProvider) GetPullRequestCommits(owner string, repo *GitRepositoryInfo, number int) ([]*GitCommit, error) {
+	value, err := p.cached(providerCacheKey(owner, repo.Name, "GetPullRequestCommits", number), func() (interface{}, error) {
+		return p.GitProvider.GetPullRequestCommits(owner, repo, number)
+	})
+	commits, _ := value.([]*GitCommit)
+	return append([]*GitCommit{}, commits...), err
+}
+
+func (p *CachingGitProvider) PullRequestLastCommitStatus(pr *GitPullRequest) (string, error) {
+	if pr.LastCommitSha == "" {
+		return p.GitProvider.PullRequestLastCommitStatus(pr)
+	}
+	value, err := p.cached(providerCacheKey(pr.Owner, pr.Repo, "PullRequestLastCommitStatus", pr.LastCommitSha), func() (interface{}, error) {
+		return p.GitProvider.PullRequestLastCommitStatus(pr)
+	})
+	status, _ := value.(string)
+	return status, err
+}
+
+func (p *CachingGitProvider) ListCommitStatus(org string, repo string, sha string) ([]*GitRepoStatus, error) {
+	value, err := p.cached(providerCacheKey(org, repo, "ListCommitStatus", sha), func() (interface{}, error) {
+		return p.GitProvider.ListCommitStatus(org, repo, sha)
+	})
+	statuses, _ := value.([]*GitRepoStatus)
+	return append([]*GitRepoStatus{}, statuses...), err
+}
+
+func (p *CachingGitProvider) GetIssue(org string, name string, number int) (*GitIssue, error) {
+	value, err := p.cached(providerCacheKey(org, name, "GetIssue", number), func() (interface{}, error) {
+		return p.GitProvider.GetIssue(org, name, number)
+	})
+	issue, _ := value.(*GitIssue)
+	if issue != nil {
+		answer := *issue
+		issue = &answer
+	}
+	return issue, err
+}
+
+func (p *CachingGitProvider) SearchIssues(org string, name string, query string) ([]*GitIssue, error) {
+	value, err := p.cached(providerCacheKey(org, name, "SearchIssues", query), func() (interface{}, error) {
+		return p.GitProvider.SearchIssues(org, name, query)
+	})
+	issues, _ := value.([]*GitIssue)
+	return append([]*GitIssue{}, issues...), err
+}
+
+func (p *CachingGitProvider) ListReleases(org string, name string) ([]*GitRelease, error) {
+	value, err := p.cached(providerCacheKey(org, name, "ListReleases"), func() (interface{}, error) {
+		return p.GitProvider.ListReleases(org, name)
+	})
+	releases, _ := value.([]*GitRelease)
+	return append([]*GitRelease{}, releases...), err
+}
+
+func (p *CachingGitProvider) UserInfo(username string) *GitUser {
+	value, _ := p.cached(providerCacheKey("", "", "UserInfo", username), func() (interface{}, error) {
+		return p.GitProvider.UserInfo(username), nil
+	})
+	user, _ := value.(*GitUser)
+	if user != nil {
+		answer := *user
+		user = &answer
+	}
+	return user
+}
+
+func (p *CachingGitProvider) DeleteRepository(org string, name string) error {
+	defer p.Invalidate(org, name)
+	return p.GitProvider.DeleteRepository(org, name)
+}
+
+func (p *CachingGitProvider) RenameRepository(org string, name string, newName string) (*GitRepository, error) {
+	defer p.Invalidate(org, name)
+	return p.GitProvider.RenameRepository(org, name, newName)
+}
+
+func (p *CachingGitProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
+	if data.GitRepositoryInfo != nil {
+		defer p.Invalidate(data.GitRepositoryInfo.Organisation, data.GitRepositoryInfo.Name)
+	}
+	return p.GitProvider.CreatePullRequest(data)
+}
+
+func (p *CachingGitProvider) MergePullRequest(pr *GitPullRequest, message string) error {
+	defer p.Invalidate(pr.Owner, pr.Repo)
+	return p.GitProvider.MergePullRequest(pr, message)
+}
+
+func (p *CachingGitProvider) CreateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	defer p.Invalidate(org, repo)
+	return p.GitProvider.CreateCommitStatus(org, repo, sha, status)
+}
+
+func (p *CachingGitProvider) CreateIssue(owner string, repo string, issue *GitIssue) (*GitIssue, error) {
+	defer p.Invalidate(owner, repo)
+	return p.GitProvider.CreateIssue(owner, repo, issue)
+}
+
+func (p *CachingGitProvider) UpdateRelease(owner string, repo string, tag string, releaseInfo *GitRelease) error {
+	defer p.Invalidate(owner, repo)
+	return p.GitProvider.UpdateRelease(owner, repo, tag, releaseInfo)
+}
@@ -0,0 +1,107 @@
+package gits_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingProvider counts the calls of GetPullRequest
+type countingProvider struct {
+	*gits.FakeProvider
+	calls int
+}
+
+func (p *countingProvider) GetPullRequest(owner string, repo *gits.GitRepositoryInfo, number int) (*gits.GitPullRequest, error) {
+	p.calls++
+	return p.FakeProvider.GetPullRequest(owner, repo, number)
+}
+
+func createCachedPullRequest(t *testing.T, ttl time.Duration) (*countingProvider, *gits.CachingGitProvider, *gits.GitRepositoryInfo, *gits.GitPullRequest) {
+	fake := &countingProvider{FakeProvider: gits.NewFakeProvider(gits.NewFakeRepository("myorg", "myrepo"))}
+	provider, err := gits.NewCachingGitProvider(fake, gits.CachingGitProviderOptions{TTL: ttl})
+	require.NoError(t, err)
+	gitInfo := &gits.GitRepositoryInfo{Organisation: "myorg", Name: "myrepo"}
+	pr, err := provider.CreatePullRequest(&gits.GitPullRequestArguments{Title: "promote", GitRepositoryInfo: gitInfo})
+	require.NoError(t, err)
+	return fake, provider, gitInfo, pr
+}
+
+func TestCachingGitProviderCachesReads(t *testing.T) {
+	t.Parallel()
+	fake, provider, gitInfo, pr := createCachedPullRequest(t, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		cached, err := provider.GetPullRequest("myorg", gitInfo, *pr.Number)
+		require.NoError(t, err)
+		assert.Equal(t, "promote", cached.Title)
+		cached.Title = "changed by the caller"
+	}
+	assert.Equal(t, 1, fake.calls)
+	assert.Equal(t, int64(3), provider.Metrics.Calls)
+	assert.Equal(t, int64(2), provider.Metrics.CacheHits)
+
+	err := provider.MergePullRequest(pr, "merged")
+	require.NoError(t, err)
+	_, err = provider.GetPullRequest("myorg", gitInfo, *pr.Number)
+	assert.Error(t, err, "merging should invalidate the cached Pull Request")
+	assert.Equal(t, 2, fake.calls)
+}
+
+func TestCachingGitProviderWithoutTTL(t *testing.T) {
+	t.Parallel()
+	fake, provider, gitInfo, pr := createCachedPullRequest(t, 0)
+
+	for i := 0; i < 3; i++ {
+		_, err := provider.GetPullRequest("myorg", gitInfo, *pr.Number)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, fake.calls)
+	assert.Equal(t, int64(0), provider.Metrics.CacheHits)
+}
+
+func TestCachingGitProviderKeepsTheClientOfGitlabAndGitea(t *testing.T) {
+	t.Parallel()
+	tokens := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/api/v4/"):
+			tokens["gitlab"] = r.Header.Get("Private-Token")
+		case strings.HasPrefix(r.URL.Path, "/api/v1/"):
+			tokens["gitea"] = r.Header.Get("Authorization")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		repo := `{"id":1,"name":"myrepo","path":"myrepo","full_name":"myorg/myrepo"}`
+		if strings.HasSuffix(r.URL.Path, "/projects") {
+			repo = "[" + repo + "]"
+		}
+		w.Write([]byte(repo))
+	}))
+	defer server.Close()
+
+	authServer := &auth.AuthServer{URL: server.URL}
+	userAuth := &auth.UserAuth{Username: "me", ApiToken: "secret"}
+	gitlabProvider, err := gits.NewGitlabProvider(authServer, userAuth, nil)
+	require.NoError(t, err)
+	giteaProvider, err := gits.NewGiteaProvider(authServer, userAuth, nil)
+	require.NoError(t, err)
+
+	for _, provider := range []gits.GitProvider{gitlabProvider, giteaProvider} {
+		metrics := gits.NewGitProviderMetrics()
+		caching, err := gits.NewCachingGitProvider(provider, gits.CachingGitProviderOptions{TTL: time.Minute, Metrics: metrics})
+		require.NoError(t, err)
+		repo, err := caching.GetRepository("myorg", "myrepo")
+		require.NoError(t, err, "the %s provider should keep its server URL", provider.Kind())
+		assert.Equal(t, "myrepo", repo.Name)
+		assert.True(t, metrics.Requests > 0, "the requests of the %s provider should go through the caching transport", provider.Kind())
+	}
+	assert.Equal(t, "secret", tokens["gitlab"])
+	assert.Equal(t, "token secret", tokens["gitea"])
+}
@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
@@ -60,6 +59,11 @@ type FakeRepository struct {
 	issueCount         int
 	Releases           map[string]*GitRelease
 	PullRequestCounter int
+	CommitStatuses     map[string][]*GitRepoStatus
+	BranchProtections  map[string]*GitBranchProtection
+	WebHooks           []*GitWebHookArguments
+	webHookCounter     int
+	Collaborators      []*GitCollaborator
 }
 
 type FakeProvider struct {
@@ -70,6 +74,9 @@ type FakeProvider struct {
 	ForkedRepositories map[string][]*FakeRepository
 	Type               FakeProviderType
 	Users              []*GitUser
+	Invitations        []*GitInvitation
+	// Members the logins of the members of each organisation
+	Members map[string][]string
 }
 
 func (f *FakeProvider) ListOrganisations() ([]GitOrganisation, error) {
@@ -291,6 +298,7 @@ func (f *FakeProvider) ListCommitStatus(org string, repoName string, sha string)
 	}
 
 	answer := []*GitRepoStatus{}
+	answer = append(answer, repo.CommitStatuses[sha]...)
 	for _, commit := range repo.Commits {
 		if commit.Commit.SHA == sha {
 			status := &GitRepoStatus{
@@ -305,6 +313,37 @@ func (f *FakeProvider) ListCommitStatus(org string, repoName string, sha string)
 	return answer, nil
 }
 
+func (f *FakeProvider) CreateCommitStatus(org string, repoName string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	repos, ok := f.Repositories[org]
+	if !ok {
+		return nil, fmt.Errorf("no repositories found for '%s'", org)
+	}
+	var repo *FakeRepository
+	for _, r := range repos {
+		if r.GitRepo.Name == repoName {
+			repo = r
+		}
+	}
+
+	if repo == nil {
+		return nil, fmt.Errorf("repository with name '%s' not found", repoName)
+	}
+
+	if repo.CommitStatuses == nil {
+		repo.CommitStatuses = map[string][]*GitRepoStatus{}
+	}
+	answer := *status
+	answer.ID = strconv.Itoa(len(repo.CommitStatuses[sha]) + 1)
+	// the most recent status is listed first
+	repo.CommitStatuses[sha] = append([]*GitRepoStatus{&answer}, repo.CommitStatuses[sha]...)
+	for _, commit := range repo.Commits {
+		if commit.Commit.SHA == sha {
+			commit.Status = CommitStatus(status.State)
+		}
+	}
+	return &answer, nil
+}
+
 func (f *FakeProvider) MergePullRequest(pr *GitPullRequest, message string) error {
 	owner := pr.Owner
 	repos, ok := f.Repositories[owner]
@@ -327,9 +366,87 @@ func (f *FakeProvider) MergePullRequest(pr *GitPullRequest, message string) erro
 }
 
 func (f *FakeProvider) CreateWebHook(data *GitWebHookArguments) error {
+	if data.Repo == nil {
+		return nil
+	}
+	repo, err := f.fakeRepository(data.Repo.Organisation, data.Repo.Name)
+	if err != nil {
+		// lets ignore webhooks for repositories which are not faked
+		return nil
+	}
+	repo.webHookCounter++
+	hook := *data
+	hook.ID = strconv.Itoa(repo.webHookCounter)
+	repo.WebHooks = append(repo.WebHooks, &hook)
 	return nil
 }
 
+func (f *FakeProvider) ListWebHooks(org string, repoName string) ([]*GitWebHookArguments, error) {
+	repo, err := f.fakeRepository(org, repoName)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*GitWebHookArguments{}, repo.WebHooks...), nil
+}
+
+func (f *FakeProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	repo, err := f.fakeRepository(data.Repo.Organisation, data.Repo.Name)
+	if err != nil {
+		return err
+	}
+	for i, hook := range repo.WebHooks {
+		if hook.ID == data.ID {
+			repo.WebHooks[i] = data
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook '%s' not found", data.ID)
+}
+
+func (f *FakeProvider) DeleteWebHook(org string, repoName string, id string) error {
+	repo, err := f.fakeRepository(org, repoName)
+	if err != nil {
+		return err
+	}
+	for i, hook := range repo.WebHooks {
+		if hook.ID == id {
+			repo.WebHooks = append(repo.WebHooks[:i], repo.WebHooks[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook '%s' not found", id)
+}
+
+func (f *FakeProvider) fakeRepository(org string, repoName string) (*FakeRepository, error) {
+	repos, ok := f.Repositories[org]
+	if !ok {
+		return nil, fmt.Errorf("no repositories found for '%s'", org)
+	}
+	for _, repo := range repos {
+		if repo.GitRepo.Name == repoName {
+			return repo, nil
+		}
+	}
+	return nil, fmt.Errorf("repository with name '%s' not found", repoName)
+}
+
+func (f *FakeProvider) ProtectBranch(org string, repoName string, protection *GitBranchProtection) error {
+	repos, ok := f.Repositories[org]
+	if !ok {
+		return fmt.Errorf("no repositories found for '%s'", org)
+	}
+	for _, repo := range repos {
+		if repo.GitRepo.Name == repoName {
+			if repo.BranchProtections == nil {
+				repo.BranchProtections = map[string]*GitBranchProtection{}
+			}
+			repo.BranchProtections[protection.Branch] = protection
+			return nil
+		}
+	}
+	return fmt.Errorf("repository with name '%s' not found", repoName)
+}
+
 func (f *FakeProvider) IsGitHub() bool {
 	return f.Type == GitHub
 }
@@ -561,18 +678,62 @@ func (f *FakeProvider) UserInfo(username string) *GitUser {
 }
 
 func (f *FakeProvider) AddCollaborator(user string, organisation string, repo string) error {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for git fake. Please add user: %v as a collaborator to this project.\n", user)
+	fakeRepo, err := f.fakeRepository(organisation, repo)
+	if err != nil {
+		return err
+	}
+	for _, collaborator := range fakeRepo.Collaborators {
+		if collaborator.Login == user {
+			return nil
+		}
+	}
+	fakeRepo.Collaborators = append(fakeRepo.Collaborators, &GitCollaborator{
+		Login:      user,
+		Permission: GitPermissionWrite,
+	})
 	return nil
 }
 
-func (f *FakeProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for git fake.\n")
-	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+func (f *FakeProvider) ListCollaborators(organisation string, repo string) ([]*GitCollaborator, error) {
+	fakeRepo, err := f.fakeRepository(organisation, repo)
+	if err != nil {
+		return nil, err
+	}
+	return fakeRepo.Collaborators, nil
+}
+
+func (f *FakeProvider) ListInvitations() ([]*GitInvitation, error) {
+	return append([]*GitInvitation{}, f.Invitations...), nil
+}
+
+func (f *FakeProvider) AcceptInvitation(ID string) error {
+	for i, invitation := range f.Invitations {
+		if invitation.ID == ID {
+			f.Invitations = append(f.Invitations[:i], f.Invitations[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("invitation '%s' not found", ID)
 }
 
-func (f *FakeProvider) AcceptInvitation(ID int64) (*github.Response, error) {
-	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for git fake.\n")
-	return &github.Response{}, nil
+func (f *FakeProvider) AddOrganisationMember(organisation string, user string) error {
+	if f.Members == nil {
+		f.Members = map[string][]string{}
+	}
+	if util.StringArrayIndex(f.Members[organisation], user) < 0 {
+		f.Members[organisation] = append(f.Members[organisation], user)
+	}
+	return nil
+}
+
+func (f *FakeProvider) RemoveOrganisationMember(organisation string, user string) error {
+	members := f.Members[organisation]
+	idx := util.StringArrayIndex(members, user)
+	if idx < 0 {
+		return fmt.Errorf("user '%s' is not a member of organisation '%s'", user, organisation)
+	}
+	f.Members[organisation] = append(members[:idx], members[idx+1:]...)
+	return nil
 }
 
 func (r *FakeRepository) String() string {
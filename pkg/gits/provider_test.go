@@ -45,3 +45,63 @@ func Test_getOrganizations(t *testing.T) {
 		})
 	}
 }
+
+func TestFakeProviderCreateCommitStatus(t *testing.T) {
+	t.Parallel()
+	repo := gits.NewFakeRepository("test-user", "test-repo")
+	repo.Commits = append(repo.Commits, &gits.FakeCommit{
+		Commit: &gits.GitCommit{SHA: "5c8afc5", Message: "initial commit"},
+		Status: gits.CommitStatusPending,
+	})
+	provider := gits.NewFakeProvider(repo)
+
+	_, err := provider.CreateCommitStatus("test-user", "test-repo", "5c8afc5", &gits.GitRepoStatus{
+		State:   "pending",
+		Context: "jx/preview",
+	})
+	assert.NoError(t, err)
+	status, err := provider.CreateCommitStatus("test-user", "test-repo", "5c8afc5", &gits.GitRepoStatus{
+		State:     "success",
+		Context:   "jx/preview",
+		TargetURL: "http://preview.example.com",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "2", status.ID)
+
+	statuses, err := provider.ListCommitStatus("test-user", "test-repo", "5c8afc5")
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 3)
+	assert.Equal(t, "success", statuses[0].State)
+	assert.Equal(t, "http://preview.example.com", statuses[0].TargetURL)
+	assert.Equal(t, "pending", statuses[1].State)
+	assert.Equal(t, "success", statuses[2].State, "the status of the commit should be updated")
+
+	_, err = provider.CreateCommitStatus("test-user", "missing-repo", "5c8afc5", &gits.GitRepoStatus{State: "success"})
+	assert.Error(t, err)
+}
+
+func TestFakeProviderAddCollaborator(t *testing.T) {
+	t.Parallel()
+	provider := gits.NewFakeProvider(gits.NewFakeRepository("myorg", "myrepo"))
+
+	for i := 0; i < 2; i++ {
+		err := provider.AddCollaborator("jenkins-x-bot", "myorg", "myrepo")
+		assert.NoError(t, err)
+	}
+	collaborators, err := provider.ListCollaborators("myorg", "myrepo")
+	assert.NoError(t, err)
+	assert.Equal(t, []*gits.GitCollaborator{{Login: "jenkins-x-bot", Permission: gits.GitPermissionWrite}}, collaborators)
+}
+
+func TestFakeProviderOrganisationMembers(t *testing.T) {
+	t.Parallel()
+	provider := gits.NewFakeProvider(gits.NewFakeRepository("myorg", "myrepo"))
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, provider.AddOrganisationMember("myorg", "alice"))
+	}
+	assert.NoError(t, provider.AddOrganisationMember("myorg", "bob"))
+	assert.NoError(t, provider.RemoveOrganisationMember("myorg", "alice"))
+	assert.Equal(t, []string{"bob"}, provider.Members["myorg"])
+	assert.Error(t, provider.RemoveOrganisationMember("myorg", "alice"))
+}
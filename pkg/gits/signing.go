@@ -0,0 +1,201 @@
+package gits
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// SigningKeyEnvVar the environment variable with the key used to sign commits and tags: a GPG key ID or the
+	// path of an SSH key file
+	SigningKeyEnvVar = "JX_GIT_SIGNING_KEY"
+	// SigningFormatEnvVar the environment variable with the format of the signing key. Defaults to gpg
+	SigningFormatEnvVar = "JX_GIT_SIGNING_FORMAT"
+	// AllowedSignersEnvVar the environment variable with the SSH allowed signers file used to verify signatures
+	AllowedSignersEnvVar = "JX_GIT_ALLOWED_SIGNERS"
+
+	// SigningFormatGPG signs with a GPG key
+	SigningFormatGPG = "gpg"
+	// SigningFormatSSH signs with an SSH key. Requires git 2.34 or later
+	SigningFormatSSH = "ssh"
+	// SigningFormatX509 signs with an X.509 certificate using gpgsm
+	SigningFormatX509 = "x509"
+)
+
+// SigningFormats the supported formats of signing keys
+var SigningFormats = []string{SigningFormatGPG, SigningFormatSSH, SigningFormatX509}
+
+// signatureStatuses describes the signature statuses of git log's %G? format
+var signatureStatuses = map[string]string{
+	"G": "good signature",
+	"B": "bad signature",
+	"U": "good signature with unknown validity",
+	"X": "good signature which has expired",
+	"Y": "good signature made by an expired key",
+	"R": "good signature made by a revoked key",
+	"E": "signature cannot be checked",
+	"N": "no signature",
+}
+
+// SigningConfig the key used to sign the commits and tags which jx creates
+type SigningConfig struct {
+	// Format one of SigningFormats
+	Format string
+	// Key the GPG key ID or the path of the SSH key file
+	Key string
+	// AllowedSignersFile the SSH allowed signers file used to verify SSH signatures
+	AllowedSignersFile string
+}
+
+// SigningConfigFromEnv returns the signing configuration of the environment variables or nil if no key is configured
+func SigningConfigFromEnv() *SigningConfig {
+	key := os.Getenv(SigningKeyEnvVar)
+	if key == "" {
+		return nil
+	}
+	return &SigningConfig{
+		Format:             os.Getenv(SigningFormatEnvVar),
+		Key:                key,
+		AllowedSignersFile: os.Getenv(AllowedSignersEnvVar),
+	}
+}
+
+// Validate returns an error if the format is not supported
+func (c *SigningConfig) Validate() error {
+	if c.Format != "" && util.StringArrayIndex(SigningFormats, c.Format) < 0 {
+		return util.InvalidArg(c.Format, SigningFormats)
+	}
+	return nil
+}
+
+// ConfigArgs returns the git configuration arguments, which have to come before the git command, which select the key
+func (c *SigningConfig) ConfigArgs() []string {
+	if c == nil {
+		return []string{}
+	}
+	args := []string{}
+	if c.Format != "" {
+		format := c.Format
+		if format == SigningFormatGPG {
+			format = "openpgp"
+		}
+		args = append(args, "-c", "gpg.format="+format)
+	}
+	if c.Key != "" {
+		args = append(args, "-c", "user.signingkey="+c.Key)
+	}
+	if c.AllowedSignersFile != "" {
+		args = append(args, "-c", "gpg.ssh.allowedSignersFile="+c.AllowedSignersFile)
+	}
+	return args
+}
+
+// commitArgs returns the arguments to create a commit signed if there is a signing configuration
+func (g *GitCLI) commitArgs(args ...string) ([]string, error) {
+	if g.Signing == nil {
+		return append([]string{"commit"}, args...), nil
+	}
+	if err := g.validateSigning(); err != nil {
+		return nil, err
+	}
+	answer := append(g.Signing.ConfigArgs(), "commit", "-S")
+	return append(answer, args...), nil
+}
+
+// tagArgs returns the arguments to create an annotated tag signed if there is a signing configuration
+func (g *GitCLI) tagArgs(tag string, msg string) ([]string, error) {
+	if g.Signing == nil {
+		return []string{"tag", "-fa", tag, "-m", msg}, nil
+	}
+	if err := g.validateSigning(); err != nil {
+		return nil, err
+	}
+	return append(g.Signing.ConfigArgs(), "tag", "-fs", tag, "-m", msg), nil
+}
+
+// validateSigning returns an error if the signing configuration is invalid so that nothing is committed or tagged
+// with a key git cannot use
+func (g *GitCLI) validateSigning() error {
+	err := g.Signing.Validate()
+	if err != nil {
+		return fmt.Errorf("Invalid signing configuration, check the %s environment variable: %s", SigningFormatEnvVar, err)
+	}
+	return nil
+}
+
+// CommitSignature the signature of a commit
+type CommitSignature struct {
+	SHA     string
+	Subject string
+	// Status the %G? status of git log such as G for a good signature or N for no signature
+	Status string
+	Signer string
+	Key    string
+}
+
+// IsValid returns true if the signature is good. Good signatures from keys of unknown validity are only valid if
+// allowUnknown is true as GPG has no trust in keys which have only been imported
+func (s *CommitSignature) IsValid(allowUnknown bool) bool {
+	return s.Status == "G" || (allowUnknown && s.Status == "U")
+}
+
+// Description describes the status of the signature
+func (s *CommitSignature) Description() string {
+	description := signatureStatuses[s.Status]
+	if description == "" {
+		description = "unknown signature status " + s.Status
+	}
+	return description
+}
+
+// CommitSignatures returns the signatures of the commits in the revision range, e.g. v1.0.0..HEAD, of the repository
+// in the directory. The signing configuration, if any, supplies the allowed signers file to verify SSH signatures
+func CommitSignatures(dir string, revisionRange string, config *SigningConfig) ([]*CommitSignature, error) {
+	args := append(config.ConfigArgs(), "log", "--format=%H%x1f%G?%x1f%GS%x1f%GK%x1f%s", revisionRange, "--")
+	cmd := util.Command{
+		Dir:  dir,
+		Name: "git",
+		Args: args,
+	}
+	text, err := cmd.RunWithoutRetry()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list the commits %s: %s", revisionRange, err)
+	}
+	return parseCommitSignatures(text), nil
+}
+
+func parseCommitSignatures(text string) []*CommitSignature {
+	answer := []*CommitSignature{}
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) < 5 {
+			continue
+		}
+		answer = append(answer, &CommitSignature{
+			SHA:     strings.TrimSpace(fields[0]),
+			Status:  fields[1],
+			Signer:  fields[2],
+			Key:     fields[3],
+			Subject: fields[4],
+		})
+	}
+	return answer
+}
+
+// VerifyTag verifies the signature of the tag in the repository in the directory
+func VerifyTag(dir string, tag string, config *SigningConfig) error {
+	args := append(config.ConfigArgs(), "verify-tag", tag)
+	cmd := util.Command{
+		Dir:  dir,
+		Name: "git",
+		Args: args,
+	}
+	_, err := cmd.RunWithoutRetry()
+	if err != nil {
+		return fmt.Errorf("The signature of tag %s could not be verified: %s", tag, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package gits_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningConfigArgs(t *testing.T) {
+	t.Parallel()
+	var config *gits.SigningConfig
+	assert.Empty(t, config.ConfigArgs())
+
+	config = &gits.SigningConfig{Format: gits.SigningFormatGPG, Key: "ABCDEF"}
+	assert.Equal(t, []string{"-c", "gpg.format=openpgp", "-c", "user.signingkey=ABCDEF"}, config.ConfigArgs())
+
+	config = &gits.SigningConfig{Format: gits.SigningFormatSSH, Key: "/keys/id", AllowedSignersFile: "/keys/allowed"}
+	assert.Equal(t, []string{"-c", "gpg.format=ssh", "-c", "user.signingkey=/keys/id", "-c", "gpg.ssh.allowedSignersFile=/keys/allowed"}, config.ConfigArgs())
+
+	assert.NoError(t, config.Validate())
+	config.Format = "pgp"
+	assert.Error(t, config.Validate())
+}
+
+func TestSSHSignedCommitsAndTags(t *testing.T) {
+	for _, command := range []string{"git", "ssh-keygen"} {
+		if _, err := exec.LookPath(command); err != nil {
+			t.Skipf("%s is not installed", command)
+		}
+	}
+	dir, err := ioutil.TempDir("", "test-git-signing-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	keyFile := filepath.Join(dir, "id_ed25519")
+	out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "test", "-f", keyFile).CombinedOutput()
+	require.NoError(t, err, string(out))
+	publicKey, err := ioutil.ReadFile(keyFile + ".pub")
+	require.NoError(t, err)
+	allowedSigners := filepath.Join(dir, "allowed_signers")
+	err = ioutil.WriteFile(allowedSigners, []byte("jx@example.com "+string(publicKey)), 0600)
+	require.NoError(t, err)
+
+	repoDir := filepath.Join(dir, "repo")
+	require.NoError(t, os.MkdirAll(repoDir, 0755))
+	gitter := &gits.GitCLI{}
+	require.NoError(t, gitter.Init(repoDir))
+	for _, args := range [][]string{{"user.name", "jx"}, {"user.email", "jx@example.com"}} {
+		cmd := exec.Command("git", append([]string{"config"}, args...)...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	commit := func(name string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(repoDir, name), []byte(name), 0644))
+		require.NoError(t, gitter.Add(repoDir, name))
+		require.NoError(t, gitter.CommitDir(repoDir, "add "+name))
+	}
+	commit("unsigned.txt")
+	gitter.Signing = &gits.SigningConfig{Format: gits.SigningFormatSSH, Key: keyFile}
+	commit("signed.txt")
+	require.NoError(t, gitter.CreateTag(repoDir, "v1.0.0", "release 1.0.0"))
+
+	verify := &gits.SigningConfig{Format: gits.SigningFormatSSH, AllowedSignersFile: allowedSigners}
+	signatures, err := gits.CommitSignatures(repoDir, "HEAD", verify)
+	require.NoError(t, err)
+	require.Len(t, signatures, 2)
+
+	assert.Equal(t, "add signed.txt", signatures[0].Subject)
+	assert.True(t, signatures[0].IsValid(false), "signature status %s", signatures[0].Status)
+	assert.Equal(t, "jx@example.com", signatures[0].Signer)
+	assert.Equal(t, "add unsigned.txt", signatures[1].Subject)
+	assert.False(t, signatures[1].IsValid(true))
+	assert.Equal(t, "no signature", signatures[1].Description())
+
+	assert.NoError(t, gits.VerifyTag(repoDir, "v1.0.0", verify))
+	err = gits.VerifyTag(repoDir, "v1.0.0", &gits.SigningConfig{Format: gits.SigningFormatSSH})
+	assert.True(t, err != nil && strings.Contains(err.Error(), "v1.0.0"))
+}
+
+func TestInvalidSigningConfigFailsCommitsAndTags(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, err := ioutil.TempDir("", "test-git-signing-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gitter := &gits.GitCLI{}
+	require.NoError(t, gitter.Init(dir))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("hello"), 0644))
+	require.NoError(t, gitter.Add(dir, "README.md"))
+
+	gitter.Signing = &gits.SigningConfig{Format: "pgp", Key: "ABCDEF"}
+	err = gitter.CommitDir(dir, "add README.md")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), gits.SigningFormatEnvVar)
+	assert.Error(t, gitter.AddCommmit(dir, "add README.md"))
+	assert.Error(t, gitter.CreateTag(dir, "v1.0.0", "release 1.0.0"))
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
@@ -401,6 +402,55 @@ func (h *HelmCLI) StatusReleases(ns string) (map[string]string, error) {
 	return statusMap, nil
 }
 
+// ReleaseHistory returns the revisions of the given release
+func (h *HelmCLI) ReleaseHistory(ns string, releaseName string) ([]*ReleaseRevision, error) {
+	output, err := h.runHelmWithOutput("history", releaseName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the history of release '%s'", releaseName)
+	}
+	answer := []*ReleaseRevision{}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return answer, nil
+	}
+	for _, line := range lines[1:] {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 4 {
+			continue
+		}
+		revision, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+		if err != nil {
+			continue
+		}
+		release := &ReleaseRevision{
+			Name:      releaseName,
+			Namespace: ns,
+			Revision:  revision,
+			Status:    strings.TrimSpace(fields[2]),
+			Chart:     strings.TrimSpace(fields[3]),
+		}
+		updated, err := time.Parse(time.ANSIC, strings.TrimSpace(fields[1]))
+		if err == nil {
+			release.Updated = updated
+		}
+		idx := strings.LastIndex(release.Chart, "-")
+		if idx > 0 {
+			release.Version = release.Chart[idx+1:]
+			release.Chart = release.Chart[0:idx]
+		}
+		if len(fields) > 4 {
+			release.Description = strings.TrimSpace(strings.Join(fields[4:], "\t"))
+		}
+		answer = append(answer, release)
+	}
+	return answer, nil
+}
+
+// RollbackRelease rolls back the release to the given revision
+func (h *HelmCLI) RollbackRelease(ns string, releaseName string, revision int) error {
+	return h.runHelm("rollback", releaseName, strconv.Itoa(revision))
+}
+
 // Lint lints the helm chart from the current working directory and returns the warnings in the output
 func (h *HelmCLI) Lint() (string, error) {
 	return h.runHelmWithOutput("lint")
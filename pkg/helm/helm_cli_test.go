@@ -43,6 +43,11 @@ jx-staging                      1               Mon Jul  2 16:21:06 2018
 jxing                           1               Wed Jun  6 14:24:42 2018        DEPLOYED        nginx-ingress-0.20.1            kube-system
 vault-operator                  1               Mon Jun 25 16:09:28 2018        DEPLOYED        vault-operator-0.1.0            jx
 `
+const releaseHistoryOutput = `
+REVISION	UPDATED                 	STATUS    	CHART                      	DESCRIPTION
+1       	Mon Jul  2 16:16:20 2018	SUPERSEDED	jenkins-x-platform-0.0.1655	Install complete
+2       	Tue Jul  3 09:10:11 2018	DEPLOYED  	jenkins-x-platform-0.0.1660	Upgrade complete
+`
 
 func checkArgs(cli *helm.HelmCLI, expectedDir string, expectedName string, exptectedArgs string) error {
 	if cli.Runner.Dir != expectedDir {
@@ -245,6 +250,33 @@ func TestStatusReleases(t *testing.T) {
 	}
 }
 
+func TestReleaseHistory(t *testing.T) {
+	setup(releaseHistoryOutput)
+	expectedArgs := fmt.Sprintf("history %s", releaseName)
+	helm, err := createHelm(expectedArgs)
+	assert.NoError(t, err, "should create helm without any error")
+	history, err := helm.ReleaseHistory(namespace, releaseName)
+	assert.NoError(t, err, "should get the history of a helm chart release without any error")
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, 1, history[0].Revision)
+		assert.Equal(t, "SUPERSEDED", history[0].Status)
+		assert.Equal(t, "jenkins-x-platform", history[0].Chart)
+		assert.Equal(t, "0.0.1655", history[0].Version)
+		assert.Equal(t, 2018, history[0].Updated.Year())
+		assert.Equal(t, 2, history[1].Revision)
+		assert.Equal(t, "Upgrade complete", history[1].Description)
+	}
+}
+
+func TestRollbackRelease(t *testing.T) {
+	setup("")
+	expectedArgs := fmt.Sprintf("rollback %s 2", releaseName)
+	helm, err := createHelm(expectedArgs)
+	assert.NoError(t, err, "should create helm without any error")
+	err = helm.RollbackRelease(namespace, releaseName, 2)
+	assert.NoError(t, err, "should rollback a helm chart release without any error")
+}
+
 func TestLint(t *testing.T) {
 	expectedArgs := "lint"
 	expectedOutput := "test"
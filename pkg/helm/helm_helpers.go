@@ -109,6 +109,57 @@ func (r *Requirements) RemoveApp(app string) bool {
 	return false
 }
 
+// DependencyUpgrade describes a dependency which was added or changed version between two requirements
+type DependencyUpgrade struct {
+	Name        string
+	Alias       string
+	Repository  string
+	FromVersion string
+	ToVersion   string
+}
+
+// DiffRequirements returns the dependencies which have been added or have changed version in the to requirements
+// compared to the from requirements
+func DiffRequirements(from *Requirements, to *Requirements) []*DependencyUpgrade {
+	answer := []*DependencyUpgrade{}
+	if to == nil {
+		return answer
+	}
+	fromVersions := map[string]string{}
+	if from != nil {
+		for _, dep := range from.Dependencies {
+			if dep != nil {
+				fromVersions[dependencyKey(dep)] = dep.Version
+			}
+		}
+	}
+	for _, dep := range to.Dependencies {
+		if dep == nil {
+			continue
+		}
+		fromVersion := fromVersions[dependencyKey(dep)]
+		if fromVersion != dep.Version {
+			answer = append(answer, &DependencyUpgrade{
+				Name:        dep.Name,
+				Alias:       dep.Alias,
+				Repository:  dep.Repository,
+				FromVersion: fromVersion,
+				ToVersion:   dep.Version,
+			})
+		}
+	}
+	return answer
+}
+
+// dependencyKey returns the alias of the dependency falling back to its name so that the same chart can be used
+// more than once under different aliases
+func dependencyKey(dep *Dependency) string {
+	if dep.Alias != "" {
+		return dep.Alias
+	}
+	return dep.Name
+}
+
 // FindRequirementsFileName returns the default requirements.yaml file name
 func FindRequirementsFileName(dir string) (string, error) {
 	names := []string{
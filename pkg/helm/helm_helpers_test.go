@@ -0,0 +1,54 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRequirements(t *testing.T) {
+	t.Parallel()
+	from := &Requirements{
+		Dependencies: []*Dependency{
+			{Name: "expose", Version: "2.3.0"},
+			{Name: "myapp", Version: "1.0.0", Repository: "http://chartmuseum"},
+			{Name: "removed", Version: "0.1.0"},
+		},
+	}
+	to := &Requirements{
+		Dependencies: []*Dependency{
+			{Name: "expose", Version: "2.3.0"},
+			{Name: "myapp", Version: "1.0.3", Repository: "http://chartmuseum"},
+			{Name: "newapp", Version: "0.0.1"},
+		},
+	}
+	upgrades := DiffRequirements(from, to)
+	assert.Equal(t, []*DependencyUpgrade{
+		{Name: "myapp", Repository: "http://chartmuseum", FromVersion: "1.0.0", ToVersion: "1.0.3"},
+		{Name: "newapp", ToVersion: "0.0.1"},
+	}, upgrades)
+
+	assert.Empty(t, DiffRequirements(to, to))
+}
+
+func TestDiffRequirementsUsesAliases(t *testing.T) {
+	t.Parallel()
+	from := &Requirements{
+		Dependencies: []*Dependency{
+			{Name: "postgresql", Alias: "orders-db", Version: "3.0.0"},
+			{Name: "postgresql", Alias: "users-db", Version: "3.0.0"},
+		},
+	}
+	to := &Requirements{
+		Dependencies: []*Dependency{
+			{Name: "postgresql", Alias: "orders-db", Version: "3.0.0"},
+			{Name: "postgresql", Alias: "users-db", Version: "3.1.0"},
+			{Name: "postgresql", Version: "3.1.0"},
+		},
+	}
+	upgrades := DiffRequirements(from, to)
+	assert.Equal(t, []*DependencyUpgrade{
+		{Name: "postgresql", Alias: "users-db", FromVersion: "3.0.0", ToVersion: "3.1.0"},
+		{Name: "postgresql", ToVersion: "3.1.0"},
+	}, upgrades)
+}
@@ -5,6 +5,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,8 +26,9 @@ const (
 	// LabelReleaseChartVersion stores the version of a chart installation in a label
 	LabelReleaseChartVersion = "jenkins.io/version"
 
-	hookFailed    = "hook-failed"
-	hookSucceeded = "hook-succeeded"
+	hookFailed         = "hook-failed"
+	hookSucceeded      = "hook-succeeded"
+	hookBeforeCreation = "before-hook-creation"
 )
 
 // HelmTemplate implements common helm actions but purely as client side operations
@@ -38,10 +41,15 @@ type HelmTemplate struct {
 	Runner          *util.Command
 	KubectlValidate bool
 	KubeClient      kubernetes.Interface
+	Releases        *ReleaseHistoryStore
 }
 
-// NewHelmTemplate creates a new HelmTemplate instance configured to the given client side Helmer
-func NewHelmTemplate(client *HelmCLI, workDir string, kubeClient kubernetes.Interface) *HelmTemplate {
+// NewHelmTemplate creates a new HelmTemplate instance configured to the given client side Helmer. The release
+// history is stored in the given storage which is either configmaps or secrets, defaulting to configmaps if blank
+func NewHelmTemplate(client *HelmCLI, workDir string, kubeClient kubernetes.Interface, storage string) *HelmTemplate {
+	if storage == "" {
+		storage = ReleaseStorageConfigMaps
+	}
 	cli := &HelmTemplate{
 		Client:          client,
 		WorkDir:         workDir,
@@ -50,16 +58,23 @@ func NewHelmTemplate(client *HelmCLI, workDir string, kubeClient kubernetes.Inte
 		CWD:             client.CWD,
 		KubectlValidate: false,
 		KubeClient:      kubeClient,
+		Releases: &ReleaseHistoryStore{
+			KubeClient: kubeClient,
+			Storage:    storage,
+			MaxHistory: DefaultMaxReleaseHistory,
+		},
 	}
 	return cli
 }
 
+// HelmHook represents a resource annotated as a helm hook
 type HelmHook struct {
 	Kind               string
 	Name               string
 	File               string
 	Hooks              []string
 	HookDeletePolicies []string
+	Weight             int
 }
 
 // SetHost is used to point at a locally running tiller
@@ -127,9 +142,21 @@ func (h *HelmTemplate) BuildDependency() error {
 	return h.Client.BuildDependency()
 }
 
-// ListCharts execute the helm list command and returns its output
+// ListCharts returns the releases recorded in the release history in the same format as helm list
 func (h *HelmTemplate) ListCharts() (string, error) {
-	return h.Client.ListCharts()
+	if h.Releases == nil || h.Releases.KubeClient == nil {
+		return h.Client.ListCharts()
+	}
+	releases, err := h.Releases.ListReleases("")
+	if err != nil {
+		return "", err
+	}
+	lines := []string{"NAME\tREVISION\tUPDATED\tSTATUS\tCHART\tNAMESPACE"}
+	for _, r := range releases {
+		lines = append(lines, strings.Join([]string{r.Name, strconv.Itoa(r.Revision), r.Updated.Format(time.ANSIC),
+			r.Status, r.Chart + "-" + r.Version, r.Namespace}, "\t"))
+	}
+	return strings.Join(lines, "\n"), nil
 }
 
 // SearchChartVersions search all version of the given chart
@@ -157,6 +184,12 @@ func (h *HelmTemplate) PackageChart() error {
 	return h.Client.PackageChart()
 }
 
+// Template generates the YAML from the chart template to the given directory
+func (h *HelmTemplate) Template(chart string, releaseName string, ns string, outDir string, upgrade bool,
+	values []string, valueFiles []string) error {
+	return h.Client.Template(chart, releaseName, ns, outDir, upgrade, values, valueFiles)
+}
+
 // Version executes the helm version command and returns its output
 func (h *HelmTemplate) Version(tls bool) (string, error) {
 	return h.Client.VersionWithArgs(tls, "--client")
@@ -167,23 +200,39 @@ func (h *HelmTemplate) Version(tls bool) (string, error) {
 // InstallChart installs a helm chart according with the given flags
 func (h *HelmTemplate) InstallChart(chart string, releaseName string, ns string, version *string, timeout *int,
 	values []string, valueFiles []string) error {
+	return h.deployChart(chart, releaseName, ns, version, false, true, values, valueFiles)
+}
+
+// UpgradeChart upgrades a helm chart according with given helm flags
+func (h *HelmTemplate) UpgradeChart(chart string, releaseName string, ns string, version *string, install bool,
+	timeout *int, force bool, wait bool, values []string, valueFiles []string) error {
+	return h.deployChart(chart, releaseName, ns, version, true, wait, values, valueFiles)
+}
+
+// deployChart generates the YAML for the chart, applies it with kubectl running the helm hooks for the install or
+// upgrade phases, prunes any resources removed since the previous revision then records the new revision
+func (h *HelmTemplate) deployChart(chart string, releaseName string, ns string, version *string, upgrade bool,
+	wait bool, values []string, valueFiles []string) error {
 
 	err := h.clearOutputDir(releaseName)
 	if err != nil {
 		return err
 	}
-	outputDir, _, chartsDir, err := h.getDirectories(releaseName)
+	outputDir, helmHookDir, chartsDir, err := h.getDirectories(releaseName)
+	if err != nil {
+		return err
+	}
 
 	chartDir, err := h.chartNameToFolder(chart, chartsDir)
 	if err != nil {
 		return err
 	}
-	err = h.Client.Template(chartDir, releaseName, ns, outputDir, false, values, valueFiles)
+	err = h.Client.Template(chartDir, releaseName, ns, outputDir, upgrade, values, valueFiles)
 	if err != nil {
 		return err
 	}
 
-	_, versionText, err := h.getChartNameAndVersion(chartDir, version)
+	chartName, versionText, err := h.getChartNameAndVersion(chartDir, version)
 	if err != nil {
 		return err
 	}
@@ -192,89 +241,175 @@ func (h *HelmTemplate) InstallChart(chart string, releaseName string, ns string,
 	if err != nil {
 		return err
 	}
+	manifest, err := joinYamlFiles(outputDir)
+	if err != nil {
+		return err
+	}
+	hooks, err := joinYamlFiles(helmHookDir)
+	if err != nil {
+		return err
+	}
+	previous := h.deployedRevision(ns, releaseName)
+
 	helmPrePhase := "pre-install"
 	helmPostPhase := "post-install"
-	wait := true
+	description := "Install complete"
 	create := true
+	if upgrade {
+		helmPrePhase = "pre-upgrade"
+		helmPostPhase = "post-upgrade"
+		description = "Upgrade complete"
+		create = false
+	}
+	revision := &ReleaseRevision{
+		Name:      releaseName,
+		Namespace: ns,
+		Status:    ReleaseStatusDeployed,
+		Chart:     chartName,
+		Version:   versionText,
+		Values:    releaseValuesText(values, valueFiles),
+		Manifest:  manifest,
+		Hooks:     hooks,
+	}
 
 	err = h.runHooks(helmHooks, helmPrePhase, ns, chart, releaseName, wait, create)
 	if err != nil {
-		return err
+		return h.saveFailedRevision(revision, err)
 	}
 	err = h.kubectlApply(ns, chart, releaseName, wait, create, outputDir)
 	if err != nil {
 		h.deleteHooks(helmHooks, helmPrePhase, hookFailed, ns)
-		return err
+		return h.saveFailedRevision(revision, err)
 	}
 	h.deleteHooks(helmHooks, helmPrePhase, hookSucceeded, ns)
 
 	err = h.runHooks(helmHooks, helmPostPhase, ns, chart, releaseName, wait, create)
 	if err != nil {
 		h.deleteHooks(helmHooks, helmPostPhase, hookFailed, ns)
-		return err
+		return h.saveFailedRevision(revision, err)
 	}
 
 	err = h.deleteHooks(helmHooks, helmPostPhase, hookSucceeded, ns)
 	err2 := h.deleteOldResources(ns, releaseName, versionText, wait)
+	var err3 error
+	if previous != nil {
+		err3 = h.deleteRemovedResources(ns, previous.Manifest, manifest, wait)
+	}
+	revision.Description = description
+	err4 := h.saveRevision(revision)
 
-	return util.CombineErrors(err, err2)
+	return util.CombineErrors(err, err2, err3, err4)
 }
 
-// UpgradeChart upgrades a helm chart according with given helm flags
-func (h *HelmTemplate) UpgradeChart(chart string, releaseName string, ns string, version *string, install bool,
-	timeout *int, force bool, wait bool, values []string, valueFiles []string) error {
-
-	err := h.clearOutputDir(releaseName)
+// RollbackRelease rolls back the release to the given revision by reapplying the manifest of that revision
+func (h *HelmTemplate) RollbackRelease(ns string, releaseName string, revision int) error {
+	if h.Releases == nil {
+		return fmt.Errorf("No release history configured!")
+	}
+	target, err := h.Releases.FindRevision(ns, releaseName, revision)
 	if err != nil {
 		return err
 	}
-	outputDir, _, chartsDir, err := h.getDirectories(releaseName)
+	current := h.deployedRevision(ns, releaseName)
 
-	chartDir, err := h.chartNameToFolder(chart, chartsDir)
+	err = h.clearOutputDir(releaseName)
 	if err != nil {
 		return err
 	}
-	err = h.Client.Template(chartDir, releaseName, ns, outputDir, false, values, valueFiles)
+	outputDir, helmHookDir, _, err := h.getDirectories(releaseName)
 	if err != nil {
 		return err
 	}
-
-	_, versionText, err := h.getChartNameAndVersion(chartDir, version)
+	_, err = writeYamlDocuments(target.Manifest, outputDir)
 	if err != nil {
 		return err
 	}
-
-	helmHooks, err := h.addLabelsToFiles(releaseName, versionText)
+	helmHooks, err := h.loadHooks(target.Hooks, helmHookDir)
 	if err != nil {
 		return err
 	}
 
-	helmPrePhase := "pre-upgrade"
-	helmPostPhase := "post-upgrade"
+	log.Infof("Rolling back release %s to revision %s\n", util.ColorInfo(releaseName), util.ColorInfo(strconv.Itoa(revision)))
+
+	rollback := *target
+	rollback.Status = ReleaseStatusDeployed
+	rollback.Updated = time.Time{}
+	rollback.Description = fmt.Sprintf("Rollback to %d", revision)
+
+	helmPrePhase := "pre-rollback"
+	helmPostPhase := "post-rollback"
+	wait := true
 	create := false
 
-	err = h.runHooks(helmHooks, helmPrePhase, ns, chart, releaseName, wait, create)
+	err = h.runHooks(helmHooks, helmPrePhase, ns, target.Chart, releaseName, wait, create)
 	if err != nil {
-		return err
+		return h.saveFailedRevision(&rollback, err)
 	}
-
-	err = h.kubectlApply(ns, chart, releaseName, wait, create, outputDir)
+	err = h.kubectlApply(ns, target.Chart, releaseName, wait, create, outputDir)
 	if err != nil {
 		h.deleteHooks(helmHooks, helmPrePhase, hookFailed, ns)
-		return err
+		return h.saveFailedRevision(&rollback, err)
 	}
 	h.deleteHooks(helmHooks, helmPrePhase, hookSucceeded, ns)
 
-	err = h.runHooks(helmHooks, helmPostPhase, ns, chart, releaseName, wait, create)
+	err = h.runHooks(helmHooks, helmPostPhase, ns, target.Chart, releaseName, wait, create)
 	if err != nil {
 		h.deleteHooks(helmHooks, helmPostPhase, hookFailed, ns)
-		return err
+		return h.saveFailedRevision(&rollback, err)
 	}
-
 	err = h.deleteHooks(helmHooks, helmPostPhase, hookSucceeded, ns)
-	err2 := h.deleteOldResources(ns, releaseName, versionText, wait)
+	var err2 error
+	if current != nil {
+		err2 = h.deleteRemovedResources(ns, current.Manifest, target.Manifest, wait)
+	}
+	err3 := h.saveRevision(&rollback)
 
-	return util.CombineErrors(err, err2)
+	return util.CombineErrors(err, err2, err3)
+}
+
+// ReleaseHistory returns the revisions of the given release
+func (h *HelmTemplate) ReleaseHistory(ns string, releaseName string) ([]*ReleaseRevision, error) {
+	if h.Releases == nil {
+		return nil, fmt.Errorf("No release history configured!")
+	}
+	return h.Releases.ReleaseHistory(ns, releaseName)
+}
+
+// deployedRevision returns the currently deployed revision of the release or nil if there is none
+func (h *HelmTemplate) deployedRevision(ns string, releaseName string) *ReleaseRevision {
+	if h.Releases == nil || h.Releases.KubeClient == nil {
+		return nil
+	}
+	history, err := h.Releases.ReleaseHistory(ns, releaseName)
+	if err != nil {
+		log.Warnf("Failed to load the history of release %s: %s\n", releaseName, err)
+		return nil
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Status == ReleaseStatusDeployed {
+			return history[i]
+		}
+	}
+	return nil
+}
+
+func (h *HelmTemplate) saveRevision(revision *ReleaseRevision) error {
+	if h.Releases == nil || h.Releases.KubeClient == nil {
+		log.Warnf("Not recording revision of release %s as no KubeClient is configured\n", revision.Name)
+		return nil
+	}
+	return h.Releases.SaveRevision(revision)
+}
+
+// saveFailedRevision records the failed revision of the release and returns the original error
+func (h *HelmTemplate) saveFailedRevision(revision *ReleaseRevision, err error) error {
+	revision.Status = ReleaseStatusFailed
+	revision.Description = err.Error()
+	err2 := h.saveRevision(revision)
+	if err2 != nil {
+		log.Warnf("Failed to record the failed revision of release %s: %s\n", revision.Name, err2)
+	}
+	return err
 }
 
 func (h *HelmTemplate) kubectlApply(ns string, chart string, releaseName string, wait bool, create bool, dir string) error {
@@ -350,18 +485,131 @@ func (h *HelmTemplate) deleteResourcesBySelector(ns string, selector string, wai
 	return nil
 }
 
-// DeleteRelease removes the given release
+// DeleteRelease removes the given release running any pre-delete and post-delete hooks of the deployed revision.
+// If purge is true the release history is removed too
 func (h *HelmTemplate) DeleteRelease(ns string, releaseName string, purge bool) error {
+	latest := h.deployedRevision(ns, releaseName)
+	helmHooks := []*HelmHook{}
+	if latest != nil && latest.Hooks != "" {
+		err := h.clearOutputDir(releaseName)
+		if err != nil {
+			return err
+		}
+		_, helmHookDir, _, err := h.getDirectories(releaseName)
+		if err != nil {
+			return err
+		}
+		helmHooks, err = h.loadHooks(latest.Hooks, helmHookDir)
+		if err != nil {
+			return err
+		}
+	}
+	helmPrePhase := "pre-delete"
+	helmPostPhase := "post-delete"
+	wait := true
+	create := true
+
+	err := h.runHooks(helmHooks, helmPrePhase, ns, "", releaseName, wait, create)
+	if err != nil {
+		h.deleteHooks(helmHooks, helmPrePhase, hookFailed, ns)
+		return err
+	}
+	h.deleteHooks(helmHooks, helmPrePhase, hookSucceeded, ns)
+
 	selector := LabelReleaseName + "=" + releaseName
 
 	log.Infof("Removing release %s using selector: %s\n", util.ColorInfo(releaseName), util.ColorInfo(selector))
 
-	return h.deleteResourcesBySelector(ns, selector, true)
+	err = h.deleteResourcesBySelector(ns, selector, wait)
+	if err != nil {
+		return err
+	}
+	if latest != nil {
+		// lets remove any kinds of resources not included in 'kubectl delete all'
+		err = h.deleteRemovedResources(ns, latest.Manifest, "", wait)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = h.runHooks(helmHooks, helmPostPhase, ns, "", releaseName, wait, create)
+	if err != nil {
+		h.deleteHooks(helmHooks, helmPostPhase, hookFailed, ns)
+		return err
+	}
+	err = h.deleteHooks(helmHooks, helmPostPhase, hookSucceeded, ns)
+
+	var err2 error
+	if h.Releases != nil && h.Releases.KubeClient != nil {
+		if purge {
+			err2 = h.Releases.DeleteHistory(ns, releaseName)
+		} else if latest != nil {
+			latest.Description = "Deletion complete"
+			err2 = h.Releases.UpdateStatus(latest, ReleaseStatusDeleted)
+		}
+	}
+	return util.CombineErrors(err, err2)
+}
+
+// deleteRemovedResources deletes the resources in the old manifest which are no longer in the new manifest
+func (h *HelmTemplate) deleteRemovedResources(ns string, oldManifest string, newManifest string, wait bool) error {
+	resources, err := RemovedResources(oldManifest, newManifest)
+	if err != nil {
+		return err
+	}
+	for _, r := range resources {
+		resourceNs := r.Namespace
+		if resourceNs == "" {
+			resourceNs = ns
+		}
+		log.Infof("Removing %s which is no longer in the release\n", util.ColorInfo(r.String()))
+		args := []string{"delete", r.String(), "--ignore-not-found", "--namespace", resourceNs}
+		if wait {
+			args = append(args, "--wait")
+		}
+		err = h.runKubectl(args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// StatusRelease returns the output of the helm status command for a given release
+// StatusRelease displays the status of the given release
 func (h *HelmTemplate) StatusRelease(ns string, releaseName string) error {
-	// TODO
+	var latest *ReleaseRevision
+	if h.Releases != nil && h.Releases.KubeClient != nil {
+		var err error
+		latest, err = h.Releases.LatestRevision(ns, releaseName)
+		if err != nil {
+			return err
+		}
+	}
+	if latest == nil {
+		// lets fall back to releases deployed before the release history was recorded
+		statusMap, err := h.StatusReleases(ns)
+		if err != nil {
+			return err
+		}
+		status := statusMap[releaseName]
+		if status == "" {
+			return fmt.Errorf("Release %s not found in namespace %s", releaseName, ns)
+		}
+		log.Infof("NAME: %s\nNAMESPACE: %s\nSTATUS: %s\n", releaseName, ns, status)
+		return nil
+	}
+	log.Infof("NAME: %s\nREVISION: %d\nLAST DEPLOYED: %s\nNAMESPACE: %s\nSTATUS: %s\nCHART: %s-%s\n",
+		latest.Name, latest.Revision, latest.Updated.Format(time.ANSIC), latest.Namespace, latest.Status, latest.Chart, latest.Version)
+	resources, err := ManifestResources(latest.Manifest)
+	if err != nil {
+		return err
+	}
+	if len(resources) > 0 {
+		log.Info("\nRESOURCES:\n")
+		for _, r := range resources {
+			log.Infof("%s\n", r.String())
+		}
+	}
 	return nil
 }
 
@@ -380,10 +628,19 @@ func (h *HelmTemplate) StatusReleases(ns string) (map[string]string, error) {
 		if labels != nil {
 			release := labels[LabelReleaseName]
 			if release != "" {
-				statusMap[release] = "DEPLOYED"
+				statusMap[release] = ReleaseStatusDeployed
 			}
 		}
 	}
+	if h.Releases != nil && h.Releases.KubeClient != nil {
+		releases, err := h.Releases.ListReleases(ns)
+		if err != nil {
+			return statusMap, err
+		}
+		for _, r := range releases {
+			statusMap[r.Name] = r.Status
+		}
+	}
 	return statusMap, nil
 }
 
@@ -495,10 +752,7 @@ func addLabelsToChartYaml(dir string, hooksDir string, releaseName string, versi
 					log.Warnf("Failed to move helm hook template %s to %s: %s", path, newPath, err)
 					return err
 				}
-				name := getYamlValueString(&m, "metadata", "name")
-				kind := getYamlValueString(&m, "kind")
-				helmDeletePolicy := getYamlValueString(&m, "metadata", "annotations", "helm.sh/hook-delete-policy")
-				helmHooks = append(helmHooks, NewHelmHook(kind, name, newPath, helmHook, helmDeletePolicy))
+				helmHooks = append(helmHooks, helmHookFromYaml(&m, newPath))
 				return nil
 			}
 			err = setYamlValue(&m, releaseName, "metadata", "labels", LabelReleaseName)
@@ -524,6 +778,49 @@ func addLabelsToChartYaml(dir string, hooksDir string, releaseName string, versi
 	return helmHooks, err
 }
 
+// helmHookFromYaml creates the HelmHook for the given parsed hook resource YAML
+func helmHookFromYaml(m *yaml.MapSlice, file string) *HelmHook {
+	helmHook := getYamlValueString(m, "metadata", "annotations", "helm.sh/hook")
+	name := getYamlValueString(m, "metadata", "name")
+	kind := getYamlValueString(m, "kind")
+	helmDeletePolicy := getYamlValueString(m, "metadata", "annotations", "helm.sh/hook-delete-policy")
+	hook := NewHelmHook(kind, name, file, helmHook, helmDeletePolicy)
+	weight := getYamlValueString(m, "metadata", "annotations", "helm.sh/hook-weight")
+	if weight != "" {
+		w, err := strconv.Atoi(strings.TrimSpace(weight))
+		if err != nil {
+			log.Warnf("Ignoring invalid helm hook weight %s on %s %s\n", weight, kind, name)
+		} else {
+			hook.Weight = w
+		}
+	}
+	return hook
+}
+
+// loadHooks writes the hooks of a recorded release revision into the hooks dir and returns them
+func (h *HelmTemplate) loadHooks(hooksText string, helmHookDir string) ([]*HelmHook, error) {
+	helmHooks := []*HelmHook{}
+	files, err := writeYamlDocuments(hooksText, helmHookDir)
+	if err != nil {
+		return helmHooks, err
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return helmHooks, errors.Wrapf(err, "Failed to load file %s", file)
+		}
+		m := yaml.MapSlice{}
+		err = yaml.Unmarshal(data, &m)
+		if err != nil {
+			return helmHooks, errors.Wrapf(err, "Failed to parse YAML of file %s", file)
+		}
+		if getYamlValueString(&m, "metadata", "annotations", "helm.sh/hook") != "" {
+			helmHooks = append(helmHooks, helmHookFromYaml(&m, file))
+		}
+	}
+	return helmHooks, nil
+}
+
 func getYamlValueString(mapSlice *yaml.MapSlice, keys ...string) string {
 	value := getYamlValue(mapSlice, keys...)
 	answer, ok := value.(string)
@@ -679,6 +976,12 @@ func (h *HelmTemplate) getChartNameAndVersion(chartDir string, version *string)
 func (h *HelmTemplate) runHooks(hooks []*HelmHook, hookPhase string, ns string, chart string, releaseName string, wait bool, create bool) error {
 	matchingHooks := MatchingHooks(hooks, hookPhase, "")
 	for _, hook := range matchingHooks {
+		if util.StringArrayIndex(hook.HookDeletePolicies, hookBeforeCreation) >= 0 {
+			err := h.runKubectl("delete", "-f", hook.File, "--namespace", ns, "--ignore-not-found", "--wait")
+			if err != nil {
+				return err
+			}
+		}
 		err := h.kubectlApplyFile(ns, hookPhase, wait, create, hook.File)
 		if err != nil {
 			return err
@@ -722,6 +1025,7 @@ func NewHelmHook(kind string, name string, file string, hook string, hookDeleteP
 }
 
 // MatchingHooks returns the matching files which have the given hook name and if hookPolicy is not blank the hook policy too
+// ordered by their hook weight
 func MatchingHooks(hooks []*HelmHook, hook string, hookDeletePolicy string) []*HelmHook {
 	answer := []*HelmHook{}
 	for _, h := range hooks {
@@ -730,5 +1034,8 @@ func MatchingHooks(hooks []*HelmHook, hook string, hookDeletePolicy string) []*H
 			answer = append(answer, h)
 		}
 	}
+	sort.SliceStable(answer, func(i, j int) bool {
+		return answer[i].Weight < answer[j].Weight
+	})
 	return answer
 }
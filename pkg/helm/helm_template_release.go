@@ -0,0 +1,459 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ReleaseStorageConfigMaps stores the release history of template mode releases in ConfigMaps
+	ReleaseStorageConfigMaps = "configmaps"
+
+	// ReleaseStorageSecrets stores the release history of template mode releases in Secrets
+	ReleaseStorageSecrets = "secrets"
+
+	// DefaultMaxReleaseHistory the default number of revisions kept for each release
+	DefaultMaxReleaseHistory = 10
+
+	// ReleaseStatusDeployed the release revision is currently deployed
+	ReleaseStatusDeployed = "DEPLOYED"
+
+	// ReleaseStatusSuperseded the release revision has been replaced by a later revision
+	ReleaseStatusSuperseded = "SUPERSEDED"
+
+	// ReleaseStatusFailed the release revision failed to deploy
+	ReleaseStatusFailed = "FAILED"
+
+	// ReleaseStatusDeleted the release has been deleted but its history kept
+	ReleaseStatusDeleted = "DELETED"
+
+	labelReleaseHistoryOwner    = "OWNER"
+	labelReleaseHistoryName     = "NAME"
+	labelReleaseHistoryRevision = "VERSION"
+	labelReleaseHistoryStatus   = "STATUS"
+	releaseHistoryOwner         = "jx-helm-template"
+	releaseHistoryDataKey       = "release"
+)
+
+// ReleaseStorageValues the supported storage of the release history of template mode releases
+var ReleaseStorageValues = []string{ReleaseStorageConfigMaps, ReleaseStorageSecrets}
+
+// ValidateReleaseStorage returns an error if the given release history storage is not blank or one of ReleaseStorageValues
+func ValidateReleaseStorage(storage string) error {
+	if storage != "" && util.StringArrayIndex(ReleaseStorageValues, storage) < 0 {
+		return fmt.Errorf("invalid helm release storage %s, must be one of %s", storage, strings.Join(ReleaseStorageValues, ", "))
+	}
+	return nil
+}
+
+// ReleaseRevision represents a single revision in the history of a helm release
+type ReleaseRevision struct {
+	Name        string    `json:"name"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Revision    int       `json:"revision"`
+	Updated     time.Time `json:"updated,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Chart       string    `json:"chart,omitempty"`
+	Version     string    `json:"version,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Values      string    `json:"values,omitempty"`
+	Manifest    string    `json:"manifest,omitempty"`
+	Hooks       string    `json:"hooks,omitempty"`
+}
+
+// ReleaseHistoryStore stores the revisions of template mode releases in ConfigMaps or Secrets
+type ReleaseHistoryStore struct {
+	KubeClient kubernetes.Interface
+	Storage    string
+	MaxHistory int
+}
+
+// ReleaseHistory returns the revisions of the given release ordered by revision number
+func (s *ReleaseHistoryStore) ReleaseHistory(ns string, releaseName string) ([]*ReleaseRevision, error) {
+	return s.list(ns, labelReleaseHistoryName+"="+releaseName)
+}
+
+// LatestRevision returns the latest revision of the given release or nil if the release has no history
+func (s *ReleaseHistoryStore) LatestRevision(ns string, releaseName string) (*ReleaseRevision, error) {
+	history, err := s.ReleaseHistory(ns, releaseName)
+	if err != nil || len(history) == 0 {
+		return nil, err
+	}
+	return history[len(history)-1], nil
+}
+
+// FindRevision returns the given revision of the release
+func (s *ReleaseHistoryStore) FindRevision(ns string, releaseName string, revision int) (*ReleaseRevision, error) {
+	history, err := s.ReleaseHistory(ns, releaseName)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range history {
+		if r.Revision == revision {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("No revision %d of release %s found in namespace %s", revision, releaseName, ns)
+}
+
+// ListReleases returns the latest revision of every release in the given namespace or all namespaces if blank
+func (s *ReleaseHistoryStore) ListReleases(ns string) ([]*ReleaseRevision, error) {
+	revisions, err := s.list(ns, "")
+	if err != nil {
+		return nil, err
+	}
+	latest := map[string]*ReleaseRevision{}
+	for _, r := range revisions {
+		key := r.Namespace + "/" + r.Name
+		latest[key] = r
+	}
+	answer := []*ReleaseRevision{}
+	for _, r := range latest {
+		answer = append(answer, r)
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		if answer[i].Name == answer[j].Name {
+			return answer[i].Namespace < answer[j].Namespace
+		}
+		return answer[i].Name < answer[j].Name
+	})
+	return answer, nil
+}
+
+// SaveRevision stores the given revision as the next revision of the release. If the revision is deployed any
+// previously deployed revisions are marked as superseded. Revisions beyond MaxHistory are removed
+func (s *ReleaseHistoryStore) SaveRevision(release *ReleaseRevision) error {
+	if release.Name == "" {
+		return fmt.Errorf("No release name specified!")
+	}
+	history, err := s.ReleaseHistory(release.Namespace, release.Name)
+	if err != nil {
+		return err
+	}
+	release.Revision = 1
+	if len(history) > 0 {
+		release.Revision = history[len(history)-1].Revision + 1
+	}
+	if release.Updated.IsZero() {
+		release.Updated = time.Now()
+	}
+	if release.Status == ReleaseStatusDeployed {
+		for _, r := range history {
+			if r.Status == ReleaseStatusDeployed {
+				r.Status = ReleaseStatusSuperseded
+				err = s.write(r, false)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	err = s.write(release, true)
+	if err != nil {
+		return err
+	}
+	history = append(history, release)
+
+	max := s.MaxHistory
+	if max <= 0 {
+		max = DefaultMaxReleaseHistory
+	}
+	for i := 0; i < len(history)-max; i++ {
+		err = s.delete(history[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateStatus updates the status of the given revision
+func (s *ReleaseHistoryStore) UpdateStatus(release *ReleaseRevision, status string) error {
+	release.Status = status
+	return s.write(release, false)
+}
+
+// DeleteHistory removes all of the revisions of the given release
+func (s *ReleaseHistoryStore) DeleteHistory(ns string, releaseName string) error {
+	history, err := s.ReleaseHistory(ns, releaseName)
+	if err != nil {
+		return err
+	}
+	for _, r := range history {
+		err = s.delete(r)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ReleaseHistoryStore) list(ns string, selector string) ([]*ReleaseRevision, error) {
+	if s.KubeClient == nil {
+		return nil, fmt.Errorf("No KubeClient configured!")
+	}
+	selector = strings.TrimSuffix(labelReleaseHistoryOwner+"="+releaseHistoryOwner+","+selector, ",")
+	listOptions := metav1.ListOptions{LabelSelector: selector}
+	datas := [][]byte{}
+	if s.Storage == ReleaseStorageSecrets {
+		list, err := s.KubeClient.CoreV1().Secrets(ns).List(listOptions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to list release Secrets in namespace %s", ns)
+		}
+		for _, item := range list.Items {
+			datas = append(datas, item.Data[releaseHistoryDataKey])
+		}
+	} else {
+		list, err := s.KubeClient.CoreV1().ConfigMaps(ns).List(listOptions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to list release ConfigMaps in namespace %s", ns)
+		}
+		for _, item := range list.Items {
+			datas = append(datas, []byte(item.Data[releaseHistoryDataKey]))
+		}
+	}
+	answer := []*ReleaseRevision{}
+	for _, data := range datas {
+		release := &ReleaseRevision{}
+		err := json.Unmarshal(data, release)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to unmarshal release revision")
+		}
+		answer = append(answer, release)
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].Revision < answer[j].Revision
+	})
+	return answer, nil
+}
+
+func (s *ReleaseHistoryStore) write(release *ReleaseRevision, create bool) error {
+	if s.KubeClient == nil {
+		return fmt.Errorf("No KubeClient configured!")
+	}
+	data, err := json.Marshal(release)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to marshal revision %d of release %s", release.Revision, release.Name)
+	}
+	objectMeta := metav1.ObjectMeta{
+		Name:      releaseRevisionResourceName(release),
+		Namespace: release.Namespace,
+		Labels: map[string]string{
+			labelReleaseHistoryOwner:    releaseHistoryOwner,
+			labelReleaseHistoryName:     release.Name,
+			labelReleaseHistoryRevision: strconv.Itoa(release.Revision),
+			labelReleaseHistoryStatus:   release.Status,
+		},
+	}
+	if s.Storage == ReleaseStorageSecrets {
+		secret := &corev1.Secret{
+			ObjectMeta: objectMeta,
+			Data: map[string][]byte{
+				releaseHistoryDataKey: data,
+			},
+		}
+		secrets := s.KubeClient.CoreV1().Secrets(release.Namespace)
+		if create {
+			_, err = secrets.Create(secret)
+		} else {
+			_, err = secrets.Update(secret)
+		}
+	} else {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: objectMeta,
+			Data: map[string]string{
+				releaseHistoryDataKey: string(data),
+			},
+		}
+		configMaps := s.KubeClient.CoreV1().ConfigMaps(release.Namespace)
+		if create {
+			_, err = configMaps.Create(cm)
+		} else {
+			_, err = configMaps.Update(cm)
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Failed to save revision %d of release %s in namespace %s", release.Revision, release.Name, release.Namespace)
+	}
+	return nil
+}
+
+func (s *ReleaseHistoryStore) delete(release *ReleaseRevision) error {
+	name := releaseRevisionResourceName(release)
+	var err error
+	if s.Storage == ReleaseStorageSecrets {
+		err = s.KubeClient.CoreV1().Secrets(release.Namespace).Delete(name, &metav1.DeleteOptions{})
+	} else {
+		err = s.KubeClient.CoreV1().ConfigMaps(release.Namespace).Delete(name, &metav1.DeleteOptions{})
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Failed to delete revision %d of release %s in namespace %s", release.Revision, release.Name, release.Namespace)
+	}
+	return nil
+}
+
+func releaseRevisionResourceName(release *ReleaseRevision) string {
+	return fmt.Sprintf("%s.v%d", release.Name, release.Revision)
+}
+
+// ManifestResource identifies a kubernetes resource generated by a chart
+type ManifestResource struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+// String returns the kind and name of the resource in kubectl format
+func (r ManifestResource) String() string {
+	return strings.ToLower(r.Kind) + "/" + r.Name
+}
+
+// ManifestResources returns the resources in the given multi document YAML manifest
+func ManifestResources(manifest string) ([]ManifestResource, error) {
+	answer := []ManifestResource{}
+	for _, doc := range SplitYamlDocuments(manifest) {
+		m := yaml.MapSlice{}
+		err := yaml.Unmarshal([]byte(doc), &m)
+		if err != nil {
+			return answer, errors.Wrap(err, "Failed to parse YAML of the release manifest")
+		}
+		kind := getYamlValueString(&m, "kind")
+		name := getYamlValueString(&m, "metadata", "name")
+		if kind == "" || name == "" {
+			continue
+		}
+		answer = append(answer, ManifestResource{
+			Kind:      kind,
+			Name:      name,
+			Namespace: getYamlValueString(&m, "metadata", "namespace"),
+		})
+	}
+	return answer, nil
+}
+
+// RemovedResources returns the resources in the old manifest which are not in the new manifest
+func RemovedResources(oldManifest string, newManifest string) ([]ManifestResource, error) {
+	oldResources, err := ManifestResources(oldManifest)
+	if err != nil {
+		return nil, err
+	}
+	newResources, err := ManifestResources(newManifest)
+	if err != nil {
+		return nil, err
+	}
+	answer := []ManifestResource{}
+	for _, o := range oldResources {
+		found := false
+		for _, n := range newResources {
+			if strings.EqualFold(o.Kind, n.Kind) && o.Name == n.Name && o.Namespace == n.Namespace {
+				found = true
+				break
+			}
+		}
+		if !found {
+			answer = append(answer, o)
+		}
+	}
+	return answer, nil
+}
+
+// SplitYamlDocuments splits the given multi document YAML text into its non empty documents
+func SplitYamlDocuments(text string) []string {
+	answer := []string{}
+	doc := []string{}
+	flush := func() {
+		d := strings.Join(doc, "\n")
+		if strings.TrimSpace(d) != "" {
+			answer = append(answer, d+"\n")
+		}
+		doc = []string{}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "---") {
+			flush()
+			continue
+		}
+		doc = append(doc, line)
+	}
+	flush()
+	return answer
+}
+
+// joinYamlFiles returns a single multi document YAML text of all the YAML files in the given directory
+func joinYamlFiles(dir string) (string, error) {
+	buffer := bytes.Buffer{}
+	err := filepath.Walk(dir, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if f.IsDir() || filepath.Ext(path) != ".yaml" {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to load file %s", path)
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		for _, doc := range SplitYamlDocuments(string(data)) {
+			buffer.WriteString("---\n# Source: " + filepath.ToSlash(relPath) + "\n")
+			buffer.WriteString(doc)
+		}
+		return nil
+	})
+	return buffer.String(), err
+}
+
+// writeYamlDocuments writes each of the documents in the given multi document YAML text to a separate file in the dir
+func writeYamlDocuments(text string, dir string) ([]string, error) {
+	answer := []string{}
+	err := os.MkdirAll(dir, util.DefaultWritePermissions)
+	if err != nil {
+		return answer, err
+	}
+	for i, doc := range SplitYamlDocuments(text) {
+		file := filepath.Join(dir, fmt.Sprintf("resource-%03d.yaml", i+1))
+		err = ioutil.WriteFile(file, []byte(doc), util.DefaultWritePermissions)
+		if err != nil {
+			return answer, errors.Wrapf(err, "Failed to write YAML file %s", file)
+		}
+		answer = append(answer, file)
+	}
+	return answer, nil
+}
+
+// releaseValuesText returns the values used for a release as YAML text
+func releaseValuesText(values []string, valueFiles []string) string {
+	buffer := bytes.Buffer{}
+	for _, valueFile := range valueFiles {
+		data, err := ioutil.ReadFile(valueFile)
+		if err == nil {
+			buffer.WriteString("# Source: " + valueFile + "\n")
+			buffer.WriteString(strings.TrimSuffix(string(data), "\n") + "\n")
+		}
+	}
+	if len(values) > 0 {
+		buffer.WriteString("# Source: --set\n")
+		for _, value := range values {
+			buffer.WriteString(value + "\n")
+		}
+	}
+	return buffer.String()
+}
@@ -0,0 +1,154 @@
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testManifestV1 = `---
+# Source: cheese/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: cheese
+---
+# Source: cheese/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: cheese
+---
+# Source: cheese/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cheese-config
+`
+
+const testManifestV2 = `---
+# Source: cheese/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: cheese
+---
+# Source: cheese/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: cheese
+`
+
+const testHooks = `---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: second
+  annotations:
+    helm.sh/hook: pre-delete
+    helm.sh/hook-weight: "5"
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: first
+  annotations:
+    helm.sh/hook: pre-delete,post-rollback
+    helm.sh/hook-weight: "-1"
+    helm.sh/hook-delete-policy: before-hook-creation
+`
+
+func TestReleaseHistoryStore(t *testing.T) {
+	t.Parallel()
+
+	for _, storage := range []string{ReleaseStorageConfigMaps, ReleaseStorageSecrets} {
+		store := &ReleaseHistoryStore{
+			KubeClient: fake.NewSimpleClientset(),
+			Storage:    storage,
+			MaxHistory: 2,
+		}
+		ns := "jx-staging"
+		for _, version := range []string{"1.0.0", "1.0.1", "1.0.2"} {
+			err := store.SaveRevision(&ReleaseRevision{
+				Name:      "cheese",
+				Namespace: ns,
+				Chart:     "cheese",
+				Version:   version,
+				Status:    ReleaseStatusDeployed,
+				Manifest:  testManifestV1,
+			})
+			require.NoError(t, err, "storage %s", storage)
+		}
+
+		history, err := store.ReleaseHistory(ns, "cheese")
+		require.NoError(t, err)
+		require.Len(t, history, 2, "storage %s", storage)
+		assert.Equal(t, 2, history[0].Revision)
+		assert.Equal(t, ReleaseStatusSuperseded, history[0].Status)
+		assert.Equal(t, 3, history[1].Revision)
+		assert.Equal(t, "1.0.2", history[1].Version)
+		assert.Equal(t, ReleaseStatusDeployed, history[1].Status)
+		assert.Equal(t, testManifestV1, history[1].Manifest)
+
+		releases, err := store.ListReleases("")
+		require.NoError(t, err)
+		require.Len(t, releases, 1)
+		assert.Equal(t, 3, releases[0].Revision)
+
+		_, err = store.FindRevision(ns, "cheese", 1)
+		assert.Error(t, err, "revision 1 should have been pruned")
+
+		err = store.DeleteHistory(ns, "cheese")
+		require.NoError(t, err)
+		history, err = store.ReleaseHistory(ns, "cheese")
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	}
+}
+
+func TestRemovedResources(t *testing.T) {
+	t.Parallel()
+
+	resources, err := ManifestResources(testManifestV1)
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+	assert.Equal(t, "deployment/cheese", resources[0].String())
+
+	removed, err := RemovedResources(testManifestV1, testManifestV2)
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "configmap/cheese-config", removed[0].String())
+
+	removed, err = RemovedResources(testManifestV1, "")
+	require.NoError(t, err)
+	assert.Len(t, removed, 3)
+}
+
+func TestLoadHooksOrderedByWeight(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "test-load-hooks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	h := &HelmTemplate{}
+	hooks, err := h.loadHooks(testHooks, dir)
+	require.NoError(t, err)
+	require.Len(t, hooks, 2)
+
+	matching := MatchingHooks(hooks, "pre-delete", "")
+	require.Len(t, matching, 2)
+	assert.Equal(t, "first", matching[0].Name)
+	assert.Equal(t, -1, matching[0].Weight)
+	assert.Equal(t, "second", matching[1].Name)
+	assert.FileExists(t, matching[1].File)
+
+	matching = MatchingHooks(hooks, "post-rollback", hookBeforeCreation)
+	require.Len(t, matching, 1)
+	assert.Equal(t, "first", matching[0].Name)
+}
@@ -24,7 +24,10 @@ type Helmer interface {
 	FindChart() (string, error)
 	PackageChart() error
 	StatusRelease(ns string, releaseName string) error
+	Template(chart string, releaseName string, ns string, outDir string, upgrade bool, values []string, valueFiles []string) error
 	StatusReleases(ns string) (map[string]string, error)
+	ReleaseHistory(ns string, releaseName string) ([]*ReleaseRevision, error)
+	RollbackRelease(ns string, releaseName string, revision int) error
 	Lint() (string, error)
 	Version(tls bool) (string, error)
 	SearchCharts(filter string) ([]ChartSummary, error)
@@ -0,0 +1,78 @@
+package issues
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// AzureDevOpsIssueProvider uses the work items of an Azure DevOps project as issues
+type AzureDevOpsIssueProvider struct {
+	Provider *gits.AzureDevOpsProvider
+	Project  string
+}
+
+// CreateAzureDevOpsIssueProvider creates an issue provider for the work items of the given Azure DevOps project
+func CreateAzureDevOpsIssueProvider(server *auth.AuthServer, userAuth *auth.UserAuth, project string, git gits.Gitter) (IssueProvider, error) {
+	if project == "" {
+		return nil, fmt.Errorf("No project specified for the Azure DevOps server %s", server.URL)
+	}
+	if userAuth == nil {
+		userAuth = &auth.UserAuth{}
+	}
+	gitProvider, err := gits.NewAzureDevOpsProvider(server, userAuth, git)
+	if err != nil {
+		return nil, err
+	}
+	provider, ok := gitProvider.(*gits.AzureDevOpsProvider)
+	if !ok {
+		return nil, fmt.Errorf("Unexpected git provider %#v for Azure DevOps", gitProvider)
+	}
+	return &AzureDevOpsIssueProvider{
+		Provider: provider,
+		Project:  project,
+	}, nil
+}
+
+func (i *AzureDevOpsIssueProvider) GetIssue(key string) (*gits.GitIssue, error) {
+	n, err := issueKeyToNumber(key)
+	if err != nil {
+		return nil, err
+	}
+	return i.Provider.GetWorkItem(i.Project, n)
+}
+
+func (i *AzureDevOpsIssueProvider) SearchIssues(query string) ([]*gits.GitIssue, error) {
+	return i.Provider.SearchWorkItems(i.Project, query)
+}
+
+func (i *AzureDevOpsIssueProvider) SearchIssuesClosedSince(t time.Time) ([]*gits.GitIssue, error) {
+	return i.Provider.SearchIssuesClosedSince(i.Project, "", t)
+}
+
+func (i *AzureDevOpsIssueProvider) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error) {
+	return i.Provider.CreateWorkItem(i.Project, issue)
+}
+
+func (i *AzureDevOpsIssueProvider) CreateIssueComment(key string, comment string) error {
+	n, err := issueKeyToNumber(key)
+	if err != nil {
+		return err
+	}
+	return i.Provider.CreateWorkItemComment(i.Project, n, comment)
+}
+
+func (i *AzureDevOpsIssueProvider) IssueURL(key string) string {
+	n, err := issueKeyToNumber(key)
+	if err != nil {
+		return ""
+	}
+	return i.Provider.WorkItemURL(i.Project, n)
+}
+
+func (i *AzureDevOpsIssueProvider) HomeURL() string {
+	return util.UrlJoin(i.Provider.BaseURL, i.Project, "_workitems")
+}
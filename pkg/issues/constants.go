@@ -1,12 +1,13 @@
 package issues
 
 const (
-	Bugzilla = "bugzilla"
-	Jira     = "jira"
-	Trello   = "trello"
-	Git      = "git"
+	AzureDevOps = "azuredevops"
+	Bugzilla    = "bugzilla"
+	Jira        = "jira"
+	Trello      = "trello"
+	Git         = "git"
 )
 
 var (
-	IssueTrackerKinds = []string{Bugzilla, Jira, Trello}
+	IssueTrackerKinds = []string{AzureDevOps, Bugzilla, Jira, Trello}
 )
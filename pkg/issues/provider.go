@@ -33,6 +33,8 @@ type IssueProvider interface {
 
 func CreateIssueProvider(kind string, server *auth.AuthServer, userAuth *auth.UserAuth, project string, batchMode bool, git gits.Gitter) (IssueProvider, error) {
 	switch kind {
+	case AzureDevOps:
+		return CreateAzureDevOpsIssueProvider(server, userAuth, project, git)
 	case Jira:
 		return CreateJiraIssueProvider(server, userAuth, project, batchMode, git)
 	default:
@@ -42,6 +44,8 @@ func CreateIssueProvider(kind string, server *auth.AuthServer, userAuth *auth.Us
 
 func ProviderAccessTokenURL(kind string, url string) string {
 	switch kind {
+	case AzureDevOps:
+		return gits.AzureDevOpsAccessTokenURL(url)
 	case Jira:
 		// TODO handle on premise servers too by detecting the URL is at atlassian.com
 		return "https://id.atlassian.com/manage/api-tokens"
@@ -52,9 +56,11 @@ func ProviderAccessTokenURL(kind string, url string) string {
 
 // GetIssueProvider returns the kind of issue provider
 func GetIssueProvider(tracker IssueProvider) string {
-	_, ok := tracker.(*JiraService)
-	if ok {
+	switch tracker.(type) {
+	case *JiraService:
 		return Jira
+	case *AzureDevOpsIssueProvider:
+		return AzureDevOps
 	}
 	return Git
 }
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const defaultProtectedBranch = "master"
+
+// gitBranchProtection converts the branch protection policy of a team into the protection of a git branch
+func gitBranchProtection(policy *v1.BranchProtectionPolicy) *gits.GitBranchProtection {
+	branch := policy.Branch
+	if branch == "" {
+		branch = defaultProtectedBranch
+	}
+	return &gits.GitBranchProtection{
+		Branch:                   branch,
+		RequiredStatusContexts:   append([]string{}, policy.RequiredStatusContexts...),
+		RequiredApprovingReviews: policy.RequiredApprovingReviews,
+		DismissStaleReviews:      policy.DismissStaleReviews,
+		EnforceAdmins:            policy.EnforceAdmins,
+		AllowForcePushes:         policy.AllowForcePushes,
+		RequireUpToDate:          policy.RequireUpToDate,
+	}
+}
+
+// protectBranch applies the branch protection policy of the team to the git repository if the team has one.
+// Failures are only logged so that repositories can still be imported by users who cannot administer them
+func (o *CommonOptions) protectBranch(teamSettings *v1.TeamSettings, gitURL string, gitProvider gits.GitProvider) {
+	if teamSettings == nil || teamSettings.BranchProtection == nil {
+		return
+	}
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		log.Warnf("Failed to parse git URL %s so cannot protect its branch: %s\n", gitURL, err)
+		return
+	}
+	protection := gitBranchProtection(teamSettings.BranchProtection)
+	err = gitProvider.ProtectBranch(gitInfo.Organisation, gitInfo.Name, protection)
+	if err != nil {
+		log.Warnf("Failed to protect branch %s of %s: %s\n", protection.Branch, gitURL, err)
+		return
+	}
+	log.Infof("Protected branch %s of %s\n", util.ColorInfo(protection.Branch), util.ColorInfo(gitURL))
+}
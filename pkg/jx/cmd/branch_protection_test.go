@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtectBranchAppliesTeamPolicy(t *testing.T) {
+	t.Parallel()
+	repo := gits.NewFakeRepository("myorg", "myrepo")
+	provider := gits.NewFakeProvider(repo)
+	o := &CommonOptions{}
+
+	o.protectBranch(&v1.TeamSettings{}, "https://github.com/myorg/myrepo.git", provider)
+	assert.Empty(t, repo.BranchProtections, "no branches should be protected without a policy")
+
+	teamSettings := &v1.TeamSettings{
+		BranchProtection: &v1.BranchProtectionPolicy{
+			RequiredStatusContexts:   []string{"continuous-integration/jenkins/pr-merge"},
+			RequiredApprovingReviews: 2,
+			DismissStaleReviews:      true,
+			RequireUpToDate:          true,
+		},
+	}
+	o.protectBranch(teamSettings, "https://github.com/myorg/myrepo.git", provider)
+
+	protection := repo.BranchProtections["master"]
+	require.NotNil(t, protection, "the master branch should be protected by default")
+	assert.Equal(t, []string{"continuous-integration/jenkins/pr-merge"}, protection.RequiredStatusContexts)
+	assert.Equal(t, 2, protection.RequiredApprovingReviews)
+	assert.True(t, protection.DismissStaleReviews)
+	assert.False(t, protection.AllowForcePushes)
+	assert.True(t, protection.RequireUpToDate)
+}
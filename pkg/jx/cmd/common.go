@@ -61,6 +61,7 @@ type CommonOptions struct {
 	ServiceAccount         string
 	Username               string
 	ExternalJenkinsBaseURL string
+	AuditReason            string
 
 	// common cached clients
 	KubeClientCached    kubernetes.Interface
@@ -265,7 +266,11 @@ func (o *CommonOptions) Helm() helm.Helmer {
 		o.helm = helmCLI
 		if helmTemplate {
 			kubeClient, _, _ := o.KubeClient()
-			o.helm = helm.NewHelmTemplate(helmCLI, "", kubeClient)
+			storage, err := o.TeamHelmReleaseStorage()
+			if err != nil {
+				log.Warnf("%s, using %s\n", err, storage)
+			}
+			o.helm = helm.NewHelmTemplate(helmCLI, "", kubeClient, storage)
 		} else {
 			o.helm = helmCLI
 		}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const optionAuditReason = "reason"
+
+// addAuditFlags adds the flag used to record why a change is being made in the audit trail
+func (o *CommonOptions) addAuditFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.AuditReason, optionAuditReason, "", "", "The reason for the change which is recorded in the audit trail")
+}
+
+// audit records an AuditEvent in the dev namespace. As the change has already been made a failure to record it
+// is only logged
+func (o *CommonOptions) audit(event *v1.AuditEvent) {
+	err := o.createAuditEvent(event)
+	if err != nil {
+		log.Warnf("Failed to record the %s of %s %s in the audit trail: %s\n", event.Spec.Action, event.Spec.Kind, event.Spec.Name, err)
+	}
+}
+
+// auditChange records an AuditEvent for a change to a resource with the YAML of the resource before and after it
+func (o *CommonOptions) auditChange(action v1.AuditActionType, kind string, name string, before interface{}, after interface{}) {
+	event := kube.NewAuditEvent("", action, kind, name)
+	var err error
+	event.Spec.Before, err = kube.ToAuditYAML(before)
+	if err != nil {
+		log.Warnf("Failed to record the state of %s %s before the change: %s\n", kind, name, err)
+	}
+	event.Spec.After, err = kube.ToAuditYAML(after)
+	if err != nil {
+		log.Warnf("Failed to record the state of %s %s after the change: %s\n", kind, name, err)
+	}
+	o.audit(event)
+}
+
+// createAuditEvent creates the event in the dev namespace. The AuditEvent CRD is registered by 'jx install' and
+// 'jx upgrade platform' rather than here
+func (o *CommonOptions) createAuditEvent(event *v1.AuditEvent) error {
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	event.Namespace = ns
+	if event.Spec.Actor == "" {
+		event.Spec.Actor = o.auditActor()
+	}
+	if event.Spec.Reason == "" {
+		event.Spec.Reason = o.AuditReason
+	}
+	if event.Spec.Source == "" {
+		event.Spec.Source = o.auditSource()
+	}
+	_, err = kube.CreateAuditEvent(jxClient, ns, event)
+	if apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "the AuditEvent CRD is not registered, run 'jx upgrade platform' to register it")
+	}
+	return err
+}
+
+// auditActor returns who is making the change. Defaults to $JX_AUDIT_ACTOR, the git user or the operating system
+// user. The user of the kubernetes context is not used as it is only the name of the credentials in the kubeconfig
+func (o *CommonOptions) auditActor() string {
+	actor := os.Getenv("JX_AUDIT_ACTOR")
+	if actor != "" {
+		return actor
+	}
+	email, err := o.Git().Email("")
+	if err == nil && email != "" {
+		return email
+	}
+	name, err := o.Git().Username("")
+	if err == nil && name != "" {
+		return name
+	}
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return "unknown"
+}
+
+// auditPromotionMerge records the approval of a promotion by the merge of its Pull Request in the audit trail. The
+// actor is the user who merged the Pull Request if the git provider reports it
+func (o *CommonOptions) auditPromotionMerge(app string, envName string, version string, prURL string, mergedBy *gits.GitUser, mergedAutomatically bool) {
+	event := kube.NewAuditEvent("", v1.AuditActionApprove, "Application", app)
+	event.Spec.Application = app
+	event.Spec.Environment = envName
+	event.Spec.Version = version
+	event.Spec.PullRequestURL = prURL
+	if mergedBy != nil && mergedBy.Login != "" {
+		event.Spec.Actor = mergedBy.Login
+	}
+	if mergedAutomatically {
+		event.Spec.Reason = "merged automatically as the Pull Request checks passed"
+	} else {
+		event.Spec.Reason = "the Pull Request was merged on the git provider"
+		if event.Spec.Actor == "" {
+			// lets not blame whoever is running jx for a merge made on the git provider
+			event.Spec.Actor = "unknown"
+		}
+	}
+	o.audit(event)
+}
+
+// auditSource returns the command making the change along with the pipeline it is running in
+func (o *CommonOptions) auditSource() string {
+	source := "jx"
+	if o.Cmd != nil {
+		source = o.Cmd.CommandPath()
+	}
+	job := o.getJobName()
+	if job != "" {
+		build := o.getBuildNumber()
+		if build != "" {
+			return fmt.Sprintf("%s in pipeline %s #%s", source, job, build)
+		}
+		return fmt.Sprintf("%s in pipeline %s", source, job)
+	}
+	return source
+}
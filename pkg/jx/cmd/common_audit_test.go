@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	gits_test "github.com/jenkins-x/jx/pkg/gits/mocks"
+	helm_test "github.com/jenkins-x/jx/pkg/helm/mocks"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestAuditChange(t *testing.T) {
+	os.Setenv("JX_AUDIT_ACTOR", "jstrachan")
+	defer os.Unsetenv("JX_AUDIT_ACTOR")
+
+	o := &CommonOptions{}
+	ConfigureTestOptionsWithResources(o, []runtime.Object{}, []runtime.Object{}, gits_test.NewMockGitter(), helm_test.NewMockHelmer())
+	o.AuditReason = "move staging to the new cluster"
+
+	before := &v1.EnvironmentSpec{Label: "Staging", Cluster: "old"}
+	after := &v1.EnvironmentSpec{Label: "Staging", Cluster: "new"}
+	o.auditChange(v1.AuditActionUpdate, "Environment", "staging", before, after)
+
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	require.NoError(t, err)
+	events, err := kube.GetAuditEvents(jxClient, ns, &kube.AuditEventFilter{Kind: "Environment"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	spec := events[0].Spec
+	assert.Equal(t, v1.AuditActionUpdate, spec.Action)
+	assert.Equal(t, "staging", spec.Name)
+	assert.Equal(t, "jstrachan", spec.Actor)
+	assert.Equal(t, "move staging to the new cluster", spec.Reason)
+	assert.Contains(t, spec.Before, "cluster: old")
+	assert.Contains(t, spec.After, "cluster: new")
+	assert.NotNil(t, spec.Timestamp)
+}
+
+func TestAuditPromotionMerge(t *testing.T) {
+	os.Setenv("JX_AUDIT_ACTOR", "jx-bot")
+	defer os.Unsetenv("JX_AUDIT_ACTOR")
+
+	o := &CommonOptions{}
+	ConfigureTestOptionsWithResources(o, []runtime.Object{}, []runtime.Object{}, gits_test.NewMockGitter(), helm_test.NewMockHelmer())
+
+	o.auditPromotionMerge("cheese", "production", "1.0.1", "https://github.com/myorg/env-production/pull/1", &gits.GitUser{Login: "jstrachan"}, false)
+	o.auditPromotionMerge("cheese", "production", "1.0.2", "https://github.com/myorg/env-production/pull/2", nil, false)
+	o.auditPromotionMerge("cheese", "staging", "1.0.2", "https://github.com/myorg/env-staging/pull/3", nil, true)
+
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	require.NoError(t, err)
+	events, err := kube.GetAuditEvents(jxClient, ns, &kube.AuditEventFilter{Kind: "Application"})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	actors := map[string]string{}
+	for _, event := range events {
+		assert.Equal(t, v1.AuditActionApprove, event.Spec.Action)
+		actors[event.Spec.PullRequestURL] = event.Spec.Actor
+	}
+	assert.Equal(t, "jstrachan", actors["https://github.com/myorg/env-production/pull/1"], "the user who merged the Pull Request")
+	assert.Equal(t, "unknown", actors["https://github.com/myorg/env-production/pull/2"], "the merge was not made by jx")
+	assert.Equal(t, "jx-bot", actors["https://github.com/myorg/env-staging/pull/3"], "the merge was made by jx")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
@@ -214,6 +215,7 @@ func (o *CommonOptions) modifyDevEnvironment(jxClient versioned.Interface, ns st
 	if env == nil {
 		return fmt.Errorf("No Development environment found in namespace %s", ns)
 	}
+	before := env.Spec.TeamSettings.DeepCopy()
 	err = fn(env)
 	if err != nil {
 		return errors.Wrap(err, "failed to call the callback function for dev environment")
@@ -223,5 +225,8 @@ func (o *CommonOptions) modifyDevEnvironment(jxClient versioned.Interface, ns st
 		return fmt.Errorf("Failed to update Development environment in namespace %s: %s", ns, err)
 	}
 	log.Infof("Updated the team settings in namespace %s\n", ns)
+	if !reflect.DeepEqual(before, &env.Spec.TeamSettings) {
+		o.auditChange(v1.AuditActionUpdate, "TeamSettings", ns, before, &env.Spec.TeamSettings)
+	}
 	return nil
 }
@@ -233,6 +233,24 @@ func addGitRepoOptionsArguments(cmd *cobra.Command, repositoryOptions *gits.GitR
 	cmd.Flags().BoolVarP(&repositoryOptions.Private, "git-private", "", false, "Create new Git repositories as private")
 }
 
+// webhookEndpoint returns the URL the webhooks of the git provider are registered with. CodeCommit webhooks are SNS
+// subscriptions which neither Prow nor Jenkins can confirm or parse, so they are registered with the webhook recorder
+// which translates them into push events and forwards them to the webhook URL
+func (o *CommonOptions) webhookEndpoint(gitProvider gits.GitProvider, webhookURL string) (string, error) {
+	if gitProvider.Kind() != gits.KindCodeCommit {
+		return webhookURL, nil
+	}
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return "", err
+	}
+	recorderURL, err := kube.GetServiceURLFromName(kubeClient, kube.ServiceWebhookRecorder, ns)
+	if err != nil {
+		return "", fmt.Errorf("CodeCommit webhooks are received by the webhook recorder which forwards them to %s. Install it with 'jx create addon webhook-recorder': %s", webhookURL, err)
+	}
+	return util.UrlJoin(recorderURL, "hook"), nil
+}
+
 func (o *CommonOptions) GitServerKind(gitInfo *gits.GitRepositoryInfo) (string, error) {
 	return o.GitServerHostURLKind(gitInfo.HostURL())
 }
@@ -197,7 +197,10 @@ func (o *CommonOptions) ImportProject(gitURL string, dir string, jenkinsfile str
 	if jenkBaseURL == "" {
 		jenkBaseURL = jenk.BaseURL()
 	}
-	webhookUrl := util.UrlJoin(jenkBaseURL, suffix)
+	webhookUrl, err := o.webhookEndpoint(gitProvider, util.UrlJoin(jenkBaseURL, suffix))
+	if err != nil {
+		return err
+	}
 	webhook := &gits.GitWebHookArguments{
 		Owner: gitInfo.Organisation,
 		Repo:  gitInfo,
@@ -1593,21 +1593,15 @@ func (o *CommonOptions) installProw() error {
 }
 
 func (o *CommonOptions) createWebhookProw(gitURL string, gitProvider gits.GitProvider) error {
-	ns, _, err := kube.GetDevNamespace(o.KubeClientCached, o.currentNamespace)
-	if err != nil {
-		return err
-	}
 	gitInfo, err := gits.ParseGitURL(gitURL)
 	if err != nil {
 		return err
 	}
-	baseURL, err := kube.GetServiceURLFromName(o.KubeClientCached, "hook", ns)
+	webhookUrl, secret, err := o.prowWebhookEndpoint()
 	if err != nil {
 		return err
 	}
-	webhookUrl := util.UrlJoin(baseURL, "hook")
-
-	hmacToken, err := o.KubeClientCached.CoreV1().Secrets(ns).Get("hmac-token", metav1.GetOptions{})
+	webhookUrl, err = o.webhookEndpoint(gitProvider, webhookUrl)
 	if err != nil {
 		return err
 	}
@@ -1615,11 +1609,28 @@ func (o *CommonOptions) createWebhookProw(gitURL string, gitProvider gits.GitPro
 		Owner:  gitInfo.Organisation,
 		Repo:   gitInfo,
 		URL:    webhookUrl,
-		Secret: string(hmacToken.Data["hmac"]),
+		Secret: secret,
 	}
 	return gitProvider.CreateWebHook(webhook)
 }
 
+// prowWebhookEndpoint returns the URL of the prow hook and the HMAC secret webhooks must use
+func (o *CommonOptions) prowWebhookEndpoint() (string, string, error) {
+	ns, _, err := kube.GetDevNamespace(o.KubeClientCached, o.currentNamespace)
+	if err != nil {
+		return "", "", err
+	}
+	baseURL, err := kube.GetServiceURLFromName(o.KubeClientCached, kube.ServiceProwHook, ns)
+	if err != nil {
+		return "", "", err
+	}
+	hmacToken, err := o.KubeClientCached.CoreV1().Secrets(ns).Get(kube.SecretProwHMACToken, metav1.GetOptions{})
+	if err != nil {
+		return "", "", err
+	}
+	return util.UrlJoin(baseURL, "hook"), string(hmacToken.Data[kube.SecretDataHMAC]), nil
+}
+
 func (o *CommonOptions) isProw() (bool, error) {
 	env, err := kube.GetEnvironment(o.jxClient, o.currentNamespace, "dev")
 	if err != nil {
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/quickstarts"
+	"github.com/spf13/cobra"
+)
+
+// QuickstartLoadOptions the options for finding the quickstarts of the team
+type QuickstartLoadOptions struct {
+	GitHubOrganisations []string
+	IgnoreTeam          bool
+	CatalogFile         string
+	CatalogMaxAge       time.Duration
+	Refresh             bool
+}
+
+func (o *QuickstartLoadOptions) addQuickstartLoadFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&o.GitHubOrganisations, "organisations", "g", []string{}, "The GitHub organisations to query for quickstarts")
+	cmd.Flags().StringVarP(&o.CatalogFile, "catalog-file", "", "", "The quickstart catalog JSON file created by 'jx step quickstart index'. Defaults to the catalog of the team")
+	cmd.Flags().DurationVarP(&o.CatalogMaxAge, "catalog-max-age", "", 24*time.Hour, "The age after which the quickstart catalog is ignored and the git providers are queried. 0 never expires")
+	cmd.Flags().BoolVarP(&o.Refresh, "refresh", "", false, "Ignore the quickstart catalog and query the git providers")
+}
+
+// loadQuickstartLocations returns the quickstart locations of the team along with any extra GitHub organisations
+func (o *CommonOptions) loadQuickstartLocations(loadOptions *QuickstartLoadOptions) ([]v1.QuickStartLocation, error) {
+	var locations []v1.QuickStartLocation
+	if !loadOptions.IgnoreTeam {
+		jxClient, ns, err := o.JXClientAndDevNamespace()
+		if err != nil {
+			return nil, err
+		}
+		err = o.registerEnvironmentCRD()
+		if err != nil {
+			return nil, err
+		}
+
+		locations, err = kube.GetQuickstartLocations(jxClient, ns)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// lets add any extra github organisations if they are not already configured
+	for _, org := range loadOptions.GitHubOrganisations {
+		found := false
+		for _, loc := range locations {
+			if loc.GitURL == gits.GitHubURL && loc.Owner == org {
+				found = true
+				break
+			}
+		}
+		if !found {
+			locations = append(locations, v1.QuickStartLocation{
+				GitURL:   gits.GitHubURL,
+				GitKind:  gits.KindGitHub,
+				Owner:    org,
+				Includes: []string{"*"},
+				Excludes: []string{"WIP-*"},
+			})
+		}
+	}
+	return locations, nil
+}
+
+// loadQuickstartCatalog loads the catalog file or the catalog of the team returning nil if there is no catalog,
+// it is stale or a refresh has been requested
+func (o *CommonOptions) loadQuickstartCatalog(loadOptions *QuickstartLoadOptions) (*quickstarts.QuickstartCatalog, error) {
+	if loadOptions.Refresh {
+		return nil, nil
+	}
+	var catalog *quickstarts.QuickstartCatalog
+	var err error
+	if loadOptions.CatalogFile != "" {
+		catalog, err = quickstarts.LoadQuickstartCatalogFile(loadOptions.CatalogFile)
+		if err != nil {
+			return nil, err
+		}
+	} else if !loadOptions.IgnoreTeam {
+		kubeClient, ns, err := o.KubeClientAndDevNamespace()
+		if err != nil {
+			return nil, err
+		}
+		catalog, err = quickstarts.LoadQuickstartCatalogConfigMap(kubeClient, ns)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if catalog != nil && catalog.IsStale(loadOptions.CatalogMaxAge) {
+		log.Warnf("Ignoring the quickstart catalog as it was created %s. Please run: jx step quickstart index\n", catalog.Created.Format(time.RFC3339))
+		return nil, nil
+	}
+	return catalog, nil
+}
+
+// loadQuickstartModel loads the quickstarts of the team from the catalog falling back to querying the git providers
+// of the locations which are not in the catalog
+func (o *CommonOptions) loadQuickstartModel(loadOptions *QuickstartLoadOptions) (*quickstarts.QuickstartModel, error) {
+	locations, err := o.loadQuickstartLocations(loadOptions)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := o.loadQuickstartCatalog(loadOptions)
+	if err != nil {
+		return nil, err
+	}
+	return o.loadQuickstartsFromMap(quickstartLocationMap(locations), catalog)
+}
+
+func quickstartLocationMap(locations []v1.QuickStartLocation) map[string]map[string]v1.QuickStartLocation {
+	gitMap := map[string]map[string]v1.QuickStartLocation{}
+	for _, loc := range locations {
+		m := gitMap[loc.GitURL]
+		if m == nil {
+			m = map[string]v1.QuickStartLocation{}
+			gitMap[loc.GitURL] = m
+		}
+		m[loc.Owner] = loc
+	}
+	return gitMap
+}
+
+func (o *CommonOptions) loadQuickstartsFromMap(gitMap map[string]map[string]v1.QuickStartLocation, catalog *quickstarts.QuickstartCatalog) (*quickstarts.QuickstartModel, error) {
+	model := quickstarts.NewQuickstartModel()
+
+	for gitURL, m := range gitMap {
+		for _, location := range m {
+			kind := location.GitKind
+			if kind == "" {
+				kind = gits.KindGitHub
+			}
+			gitProvider, err := o.gitProviderForGitServerURL(gitURL, kind)
+			if err != nil {
+				return model, err
+			}
+			if catalog != nil && model.LoadCatalogQuickstarts(catalog, gitProvider, location.Owner, location.Includes, location.Excludes) {
+				o.Debugf("Loaded the quickstarts for Git server %s owner %s from the catalog\n", gitProvider.ServerURL(), location.Owner)
+				continue
+			}
+			o.Debugf("Searching for repositories in Git server %s owner %s includes %s excludes %s as user %s \n", gitProvider.ServerURL(), location.Owner, strings.Join(location.Includes, ", "), strings.Join(location.Excludes, ", "), gitProvider.CurrentUsername())
+			err = model.LoadGitQuickstarts(gitProvider, location.Owner, location.Includes, location.Excludes)
+			if err != nil {
+				o.Debugf("Quickstart load error: %s\n", err.Error())
+			}
+		}
+	}
+	return model, nil
+}
@@ -11,6 +11,7 @@ import (
 	"reflect"
 
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/helm"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -94,6 +95,23 @@ func (o *CommonOptions) TeamHelmBin() (string, bool, bool, error) {
 	return helmBin, teamSettings.NoTiller, teamSettings.HelmTemplate, nil
 }
 
+// TeamHelmReleaseStorage returns where helm template mode stores the release history for the current team
+func (o *CommonOptions) TeamHelmReleaseStorage() (string, error) {
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return helm.ReleaseStorageConfigMaps, err
+	}
+	storage := teamSettings.HelmReleaseStorage
+	err = helm.ValidateReleaseStorage(storage)
+	if err != nil {
+		return helm.ReleaseStorageConfigMaps, err
+	}
+	if storage == "" {
+		storage = helm.ReleaseStorageConfigMaps
+	}
+	return storage, nil
+}
+
 // ModifyDevEnvironment modifies the development environment settings
 func (o *CommonOptions) ModifyDevEnvironment(callback func(env *v1.Environment) error) error {
 	apisClient, err := o.CreateApiExtensionsClient()
@@ -122,7 +140,7 @@ func (o *CommonOptions) ModifyDevEnvironment(callback func(env *v1.Environment)
 }
 
 func (o *CommonOptions) registerReleaseCRD() error {
-	apisClient, err := o.Factory.CreateApiExtensionsClient()
+	apisClient, err := o.CreateApiExtensionsClient()
 	if err != nil {
 		return err
 	}
@@ -134,7 +152,7 @@ func (o *CommonOptions) registerReleaseCRD() error {
 }
 
 func (o *CommonOptions) registerTeamCRD() error {
-	apisClient, err := o.Factory.CreateApiExtensionsClient()
+	apisClient, err := o.CreateApiExtensionsClient()
 	if err != nil {
 		return err
 	}
@@ -146,7 +164,7 @@ func (o *CommonOptions) registerTeamCRD() error {
 }
 
 func (o *CommonOptions) registerUserCRD() error {
-	apisClient, err := o.Factory.CreateApiExtensionsClient()
+	apisClient, err := o.CreateApiExtensionsClient()
 	if err != nil {
 		return err
 	}
@@ -158,7 +176,7 @@ func (o *CommonOptions) registerUserCRD() error {
 }
 
 func (o *CommonOptions) registerEnvironmentRoleBindingCRD() error {
-	apisClient, err := o.Factory.CreateApiExtensionsClient()
+	apisClient, err := o.CreateApiExtensionsClient()
 	if err != nil {
 		return err
 	}
@@ -169,8 +187,22 @@ func (o *CommonOptions) registerEnvironmentRoleBindingCRD() error {
 	return nil
 }
 
+// registerAuditEventCRD registers the AuditEvent CRD. It is registered when installing or upgrading the platform so
+// that recording an audit event does not need permission to change the CRDs of the cluster
+func (o *CommonOptions) registerAuditEventCRD() error {
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterAuditEventCRD(apisClient)
+	if err != nil {
+		return errors.Wrap(err, "failed to register the AuditEvent CRD")
+	}
+	return nil
+}
+
 func (o *CommonOptions) registerPipelineActivityCRD() error {
-	apisClient, err := o.Factory.CreateApiExtensionsClient()
+	apisClient, err := o.CreateApiExtensionsClient()
 	if err != nil {
 		return err
 	}
@@ -182,7 +214,7 @@ func (o *CommonOptions) registerPipelineActivityCRD() error {
 }
 
 func (o *CommonOptions) registerWorkflowCRD() error {
-	apisClient, err := o.Factory.CreateApiExtensionsClient()
+	apisClient, err := o.CreateApiExtensionsClient()
 	if err != nil {
 		return err
 	}
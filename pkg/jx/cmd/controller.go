@@ -2,16 +2,32 @@ package cmd
 
 import (
 	"io"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 
+	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
 )
 
+const gitMetricsLogInterval = 10 * time.Minute
+
 // ControllerOptions contains the CLI options
 type ControllerOptions struct {
 	CommonOptions
+
+	NoGitCache  bool
+	GitCacheTTL time.Duration
+	GitCacheDir string
+
+	gitProviderLock   sync.Mutex
+	gitProviders      map[string]gits.GitProvider
+	gitMetrics        *gits.GitProviderMetrics
+	gitMetricsLogTime time.Time
 }
 
 var (
@@ -27,7 +43,7 @@ var (
 // NewCmdController creates the edit command
 func NewCmdController(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
 	options := &ControllerOptions{
-		CommonOptions{
+		CommonOptions: CommonOptions{
 			Factory: f,
 			In:      in,
 			Out:     out,
@@ -50,8 +66,11 @@ func NewCmdController(f Factory, in terminal.FileReader, out terminal.FileWriter
 
 	cmd.AddCommand(NewCmdControllerBackup(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerBuild(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerDrift(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerRole(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerTeam(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerUserSync(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerWebhookRecorder(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerWorkflow(f, in, out, errOut))
 	return cmd
 }
@@ -60,3 +79,78 @@ func NewCmdController(f Factory, in terminal.FileReader, out terminal.FileWriter
 func (o *ControllerOptions) Run() error {
 	return o.Cmd.Help()
 }
+
+// addGitCacheFlags adds the flags which configure the caching of git provider API calls
+func (o *ControllerOptions) addGitCacheFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&o.NoGitCache, "no-git-cache", "", false, "Disables the caching of git provider API calls")
+	cmd.Flags().DurationVarP(&o.GitCacheTTL, "git-cache-ttl", "", 10*time.Second, "How long the results of git provider API calls are cached in memory")
+	cmd.Flags().StringVarP(&o.GitCacheDir, "git-cache-dir", "", "", "The directory to cache git provider HTTP responses in so they can be revalidated with conditional requests. Defaults to caching them in memory")
+}
+
+// cachingGitProviderForURL returns the git provider for the git URL reusing one provider per git server which caches
+// the API calls unless the cache is disabled
+func (o *ControllerOptions) cachingGitProviderForURL(gitURL string) (gits.GitProvider, *gits.GitRepositoryInfo, error) {
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		return nil, gitInfo, err
+	}
+	provider, err := o.cachingGitProvider(gitInfo.HostURL(), func() (gits.GitProvider, error) {
+		provider, _, err := o.createGitProviderForURLWithoutKind(gitURL)
+		return provider, err
+	})
+	return provider, gitInfo, err
+}
+
+// cachingGitProviderForServer returns the git provider for the git server URL reusing one provider per git server
+// which caches the API calls unless the cache is disabled
+func (o *ControllerOptions) cachingGitProviderForServer(serverURL string, gitKind string) (gits.GitProvider, error) {
+	return o.cachingGitProvider(serverURL, func() (gits.GitProvider, error) {
+		return o.gitProviderForGitServerURL(serverURL, gitKind)
+	})
+}
+
+func (o *ControllerOptions) cachingGitProvider(serverURL string, create func() (gits.GitProvider, error)) (gits.GitProvider, error) {
+	if o.NoGitCache {
+		return create()
+	}
+	o.gitProviderLock.Lock()
+	defer o.gitProviderLock.Unlock()
+
+	key := strings.TrimSuffix(serverURL, "/")
+	provider := o.gitProviders[key]
+	if provider != nil {
+		return provider, nil
+	}
+	provider, err := create()
+	if err != nil {
+		return provider, err
+	}
+	if o.gitMetrics == nil {
+		o.gitMetrics = gits.NewGitProviderMetrics()
+	}
+	provider, err = gits.NewCachingGitProvider(provider, gits.CachingGitProviderOptions{
+		TTL:      o.GitCacheTTL,
+		CacheDir: o.GitCacheDir,
+		Metrics:  o.gitMetrics,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if o.gitProviders == nil {
+		o.gitProviders = map[string]gits.GitProvider{}
+	}
+	o.gitProviders[key] = provider
+	return provider, nil
+}
+
+// logGitProviderMetrics periodically logs the usage of the git provider APIs
+func (o *ControllerOptions) logGitProviderMetrics() {
+	o.gitProviderLock.Lock()
+	defer o.gitProviderLock.Unlock()
+
+	if o.gitMetrics == nil || time.Since(o.gitMetricsLogTime) < gitMetricsLogInterval {
+		return
+	}
+	o.gitMetricsLogTime = time.Now()
+	log.Infof("Git provider API usage: %s\n", o.gitMetrics.String())
+}
@@ -208,11 +208,23 @@ func (o *ControllerBackupOptions) onUserChange(obj interface{}, ns string, dir s
 }
 
 func (o *ControllerBackupOptions) writeResourceToBackupFile(obj interface{}, resource string, key string, ns string, dir string) {
-	out, err := yaml.Marshal(obj)
+	err := o.saveResourceToBackupFile(obj, resource, key, ns, dir)
 	if err != nil {
-		log.Errorf("Unable to marshall %s %s\n", resource, err)
+		log.Errorf("%s\n", err)
 		return
 	}
+	err = o.pushDirIfChanges(dir, fmt.Sprintf("Updating %s %s", resource, key))
+	if err != nil {
+		log.Errorf("%s\n", err)
+	}
+}
+
+// saveResourceToBackupFile writes the resource as YAML to the file of its key in the directory of its kind and namespace
+func (o *ControllerBackupOptions) saveResourceToBackupFile(obj interface{}, resource string, key string, ns string, dir string) error {
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("Unable to marshall %s %s", resource, err)
+	}
 
 	o.Debugf("Dumping %s with key %s...\n", util.ColorInfo(resource), util.ColorInfo(key))
 	o.Debugf("%s\n", string(out))
@@ -220,48 +232,43 @@ func (o *ControllerBackupOptions) writeResourceToBackupFile(obj interface{}, res
 	nsDir := path.Join(dir, fmt.Sprintf("%ss", resource), ns)
 	err = os.MkdirAll(nsDir, os.FileMode(0755))
 	if err != nil {
-		log.Errorf("Unable to create directory %s\n", err)
-		return
+		return fmt.Errorf("Unable to create directory %s", err)
 	}
 
 	envFile := path.Join(nsDir, fmt.Sprintf("%s.yaml", key))
 	err = ioutil.WriteFile(envFile, out, 0644)
 	if err != nil {
-		log.Errorf("Unable to write file %s\n", err)
-		return
+		return fmt.Errorf("Unable to write file %s", err)
 	}
-
-	o.commitDirIfChanges(dir, fmt.Sprintf("Updating %s %s", resource, key))
+	return nil
 }
 
-func (o *ControllerBackupOptions) commitDirIfChanges(dir string, message string) {
+// pushDirIfChanges commits any changes in the directory and pushes them to master
+func (o *ControllerBackupOptions) pushDirIfChanges(dir string, message string) error {
 	changes, err := o.Git().HasChanges(dir)
 	if err != nil {
-		log.Errorf("Unable to determine changes %s\n", err)
-		return
+		return fmt.Errorf("Unable to determine changes %s", err)
 	}
 
 	if changes {
 		err = o.Git().Add(dir, "*")
 		if err != nil {
-			log.Errorf("Unable to add files %s\n", err)
-			return
+			return fmt.Errorf("Unable to add files %s", err)
 		}
 
 		err = o.Git().CommitDir(dir, message)
 		if err != nil {
-			log.Errorf("Unable to commit dir %s\n", err)
-			return
+			return fmt.Errorf("Unable to commit dir %s", err)
 		}
 
 		err = o.Git().PushMaster(dir)
 		if err != nil {
-			log.Errorf("Unable to push master %s\n", err)
-			return
+			return fmt.Errorf("Unable to push master %s", err)
 		}
 
 		fmt.Fprintf(o.Out, "Pushed update '%s' Git repository %s\n", util.ColorInfo(message), util.ColorInfo(dir))
 	}
+	return nil
 }
 
 func (o *ControllerBackupOptions) getOrCreateBackupRepository() (string, error) {
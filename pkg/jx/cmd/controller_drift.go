@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"io"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// ControllerDriftOptions are the flags for the commands
+type ControllerDriftOptions struct {
+	ControllerOptions
+
+	Environments []string
+	PollInterval time.Duration
+	CreateIssue  bool
+	Reapply      bool
+}
+
+var (
+	controllerDriftLong = templates.LongDesc(`
+		Runs the drift controller which periodically compares the Environment git repositories with the cluster.
+
+		Any drift is reported in the logs and can optionally be raised as an issue on the Environment git repository
+		or fixed by reapplying the desired state from the git repository.
+`)
+
+	controllerDriftExample = templates.Examples(`
+		# Check all the permanent environments for drift every 10 minutes raising an issue if any is found
+		jx controller drift --issue
+
+		# Check the production environment every hour and restore its desired state
+		jx controller drift --env production --poll 1h --reapply
+	`)
+)
+
+// NewCmdControllerDrift creates a command object for the "controller drift" command
+func NewCmdControllerDrift(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerDriftOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "drift",
+		Short:   "Runs the controller which detects drift between the Environment git repositories and the cluster",
+		Long:    controllerDriftLong,
+		Example: controllerDriftExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&options.Environments, "env", "e", []string{}, "The Environments to check. Defaults to all the permanent Environments")
+	cmd.Flags().DurationVarP(&options.PollInterval, "poll", "", 10*time.Minute, "The interval between checks for drift")
+	cmd.Flags().BoolVarP(&options.CreateIssue, "issue", "", false, "Raises an issue on the Environment git repository if any drift is detected")
+	cmd.Flags().BoolVarP(&options.Reapply, "reapply", "", false, "Reapplies the Environment git repository if any drift is detected")
+
+	options.addCommonFlags(cmd)
+
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerDriftOptions) Run() error {
+	err := o.registerEnvironmentCRD()
+	if err != nil {
+		return err
+	}
+	log.Infof("Checking for Environment drift every %s\n", util.ColorInfo(o.PollInterval.String()))
+	for {
+		err = o.checkDrift()
+		if err != nil {
+			log.Warnf("Failed to check for Environment drift: %s\n", err)
+		}
+		time.Sleep(o.PollInterval)
+	}
+}
+
+func (o *ControllerDriftOptions) checkDrift() error {
+	envs, err := o.driftEnvironments(o.Environments)
+	if err != nil {
+		return err
+	}
+	for _, env := range envs {
+		drift, err := o.detectEnvironmentDrift(env)
+		if err != nil {
+			log.Warnf("Failed to detect drift in Environment %s: %s\n", env.Name, err)
+			continue
+		}
+		if len(drift.Resources) == 0 {
+			log.Infof("No drift in Environment %s\n", util.ColorInfo(env.Name))
+			drift.removeWorkDir()
+			continue
+		}
+		for _, r := range drift.Resources {
+			log.Warnf("Environment %s %s %s is %s: %s\n", env.Name, r.Kind, r.Name, r.Status, r.DriftSummary())
+		}
+		if o.CreateIssue {
+			err = o.createDriftIssue(env, drift)
+			if err != nil {
+				log.Warnf("Failed to raise a drift issue for Environment %s: %s\n", env.Name, err)
+			}
+		}
+		if o.Reapply {
+			err = o.reapplyEnvironment(drift)
+			if err != nil {
+				log.Warnf("Failed to reapply Environment %s: %s\n", env.Name, err)
+			}
+		}
+		drift.removeWorkDir()
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
@@ -16,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
@@ -29,6 +31,10 @@ type ControllerTeamOptions struct {
 	InstallOptions
 
 	GitRepositoryOptions gits.GitRepositoryOptions
+
+	DefaultRole string
+	GitMembers  bool
+	Archive     bool
 }
 
 // NewCmdControllerTeam creates a command object for the generic "get" action, which
@@ -59,8 +65,13 @@ func NewCmdControllerTeam(f Factory, in terminal.FileReader, out terminal.FileWr
 	}
 
 	options.ControllerOptions.addCommonFlags(cmd)
+	options.ControllerOptions.addGitCacheFlags(cmd)
 	options.InstallOptions.addInstallFlags(cmd, true)
 
+	cmd.Flags().StringVarP(&options.DefaultRole, "default-role", "", kube.DefaultTeamMemberRole, "The role given to the members of a team in its environments unless they have been given other roles")
+	cmd.Flags().BoolVarP(&options.Archive, "archive", "", false, "Archives deleted teams with their environments and role bindings to the backup repository of the organisation before removing them")
+	cmd.Flags().BoolVarP(&options.GitMembers, "git-members", "", false, "Adds the members of a team to the git organisation of the team and removes the members it added when they leave the team unless they are in another team of the organisation")
+
 	return cmd
 }
 
@@ -143,7 +154,7 @@ func (o *ControllerTeamOptions) Run() error {
 				o.onTeamChange(newObj, client, jxClient, devNs)
 			},
 			DeleteFunc: func(obj interface{}) {
-				// do nothing, already handled by 'jx delete team'
+				// do nothing, the finalizer keeps the team until its resources have been removed
 			},
 		},
 	)
@@ -164,6 +175,18 @@ func (o *ControllerTeamOptions) onTeamChange(obj interface{}, kubeClient kuberne
 	teamNs := team.Name
 	log.Infof("Adding / Updating Team %s, Namespace %s, Status '%s'\n", util.ColorInfo(teamNs), util.ColorInfo(team.Namespace), util.ColorInfo(team.Status.ProvisionStatus))
 
+	if kube.IsTeamDeleting(team) {
+		if team.DeletionTimestamp != nil && !kube.HasTeamFinalizer(team) {
+			// the team is being removed by someone else
+			return
+		}
+		err := o.deprovisionTeam(team, kubeClient, jxClient, adminNs)
+		if err != nil {
+			log.Errorf("Unable to delete team %s: %s\n", util.ColorInfo(teamNs), err)
+		}
+		return
+	}
+
 	if v1.TeamProvisionStatusNone == team.Status.ProvisionStatus {
 		// update first
 		oc := &o.ControllerOptions
@@ -191,6 +214,7 @@ func (o *ControllerTeamOptions) onTeamChange(obj interface{}, kubeClient kuberne
 		}
 
 		err = oc.ModifyTeam(teamNs, func(team *v1.Team) error {
+			kube.AddTeamFinalizer(team)
 			team.Status.ProvisionStatus = v1.TeamProvisionStatusPending
 			team.Status.Message = "Installing resources"
 			return nil
@@ -326,6 +350,167 @@ func (o *ControllerTeamOptions) onTeamChange(obj interface{}, kubeClient kuberne
 			return
 		}
 	}
+
+	// the update to the Complete status reconciles the members once the roles of the team exist
+	if v1.TeamProvisionStatusComplete == team.Status.ProvisionStatus {
+		if !kube.HasTeamFinalizer(team) {
+			// teams provisioned before the controller used finalizers
+			oc := &o.ControllerOptions
+			oc.SetDevNamespace(adminNs)
+			err := oc.ModifyTeam(teamNs, func(team *v1.Team) error {
+				kube.AddTeamFinalizer(team)
+				return nil
+			})
+			if err != nil {
+				log.Errorf("Unable to add the finalizer to team %s: %s\n", util.ColorInfo(teamNs), err)
+			}
+		}
+		err := o.reconcileTeamMembers(team, kubeClient, jxClient, adminNs)
+		if err != nil {
+			log.Errorf("Unable to reconcile the members of team %s: %s\n", util.ColorInfo(teamNs), err)
+		}
+	}
+}
+
+// reconcileTeamMembers gives the members of the team access to the team's environments and optionally its git
+// organisation, removes the access of members who have left and then records the members on the team
+func (o *ControllerTeamOptions) reconcileTeamMembers(team *v1.Team, kubeClient kubernetes.Interface, jxClient versioned.Interface, adminNs string) error {
+	oc := &o.ControllerOptions
+	oc.SetDevNamespace(adminNs)
+
+	members := append([]string{}, team.Spec.Members...)
+	added, removed, err := kube.ReconcileTeamMembers(kubeClient, jxClient, adminNs, team, o.DefaultRole)
+	if err != nil {
+		return err
+	}
+	gitMembers := kube.TeamGitMembers(team)
+	if o.GitMembers && len(added)+len(removed) > 0 {
+		gitMembers, err = o.reconcileGitMembers(jxClient, adminNs, team, added, removed)
+		if err != nil {
+			// record who was added to the git organisation so far so that they are removed once they leave the team
+			modifyErr := oc.ModifyTeam(team.Name, func(t *v1.Team) error {
+				kube.SetTeamGitMembers(t, gitMembers)
+				return nil
+			})
+			if modifyErr != nil {
+				log.Warnf("Unable to record the git organisation members of team %s: %s\n", team.Name, modifyErr)
+			}
+			return err
+		}
+	}
+
+	updated := team.DeepCopy()
+	kube.SetReconciledTeamMembers(updated, members)
+	kube.SetTeamGitMembers(updated, gitMembers)
+	if updated.Annotations[kube.AnnotationTeamMembers] == team.Annotations[kube.AnnotationTeamMembers] &&
+		updated.Annotations[kube.AnnotationTeamGitMembers] == team.Annotations[kube.AnnotationTeamGitMembers] {
+		return nil
+	}
+	return oc.ModifyTeam(team.Name, func(t *v1.Team) error {
+		kube.SetReconciledTeamMembers(t, members)
+		kube.SetTeamGitMembers(t, gitMembers)
+		return nil
+	})
+}
+
+// reconcileGitMembers adds and removes the members of the git organisation in the team settings of the team and
+// returns the members of the team who have been added to the git organisation by the controller
+func (o *ControllerTeamOptions) reconcileGitMembers(jxClient versioned.Interface, adminNs string, team *v1.Team, added []string, removed []string) ([]string, error) {
+	oc := &o.ControllerOptions
+	gitMembers := kube.TeamGitMembers(team)
+	settings, err := teamGitSettings(jxClient, team.Name)
+	if err != nil {
+		return gitMembers, err
+	}
+	if settings == nil || settings.GitServer == "" || settings.Organisation == "" {
+		log.Warnf("No git server and organisation configured in the team settings of team %s so git organisation members cannot be reconciled\n", team.Name)
+		return gitMembers, nil
+	}
+	oc.BatchMode = true
+	gitKind, err := oc.GitServerHostURLKind(settings.GitServer)
+	if err != nil {
+		return gitMembers, err
+	}
+	provider, err := oc.cachingGitProviderForServer(settings.GitServer, gitKind)
+	if err != nil {
+		return gitMembers, errors.Wrapf(err, "failed to create the git provider for %s", settings.GitServer)
+	}
+	return reconcileGitOrganisationMembers(provider, jxClient, adminNs, team, settings, added, removed)
+}
+
+// reconcileGitOrganisationMembers adds the added members of the team to the git organisation of the team and removes
+// the removed members which were added by the controller unless they are a member of another team using the same
+// git organisation. Returns the members of the team who have been added to the git organisation by the controller
+func reconcileGitOrganisationMembers(provider gits.GitProvider, jxClient versioned.Interface, adminNs string, team *v1.Team, settings *v1.TeamSettings, added []string, removed []string) ([]string, error) {
+	gitMembers := kube.TeamGitMembers(team)
+	for _, user := range added {
+		err := provider.AddOrganisationMember(settings.Organisation, user)
+		if err != nil {
+			return gitMembers, errors.Wrapf(err, "failed to add %s to the git organisation %s", user, settings.Organisation)
+		}
+		if util.StringArrayIndex(gitMembers, user) < 0 {
+			gitMembers = append(gitMembers, user)
+		}
+		log.Infof("Added %s to the git organisation %s\n", util.ColorInfo(user), util.ColorInfo(settings.Organisation))
+	}
+	for _, user := range removed {
+		idx := util.StringArrayIndex(gitMembers, user)
+		if idx < 0 {
+			log.Infof("Not removing %s from the git organisation %s as they were not added to it by the team controller\n", util.ColorInfo(user), util.ColorInfo(settings.Organisation))
+			continue
+		}
+		otherTeam, err := gitOrganisationTeamOfMember(jxClient, adminNs, team.Name, settings, user)
+		if err != nil {
+			return gitMembers, err
+		}
+		if otherTeam != "" {
+			log.Infof("Not removing %s from the git organisation %s as they are a member of team %s\n", util.ColorInfo(user), util.ColorInfo(settings.Organisation), util.ColorInfo(otherTeam))
+		} else {
+			err = provider.RemoveOrganisationMember(settings.Organisation, user)
+			if err != nil {
+				return gitMembers, errors.Wrapf(err, "failed to remove %s from the git organisation %s", user, settings.Organisation)
+			}
+			log.Infof("Removed %s from the git organisation %s\n", util.ColorInfo(user), util.ColorInfo(settings.Organisation))
+		}
+		gitMembers = append(gitMembers[:idx], gitMembers[idx+1:]...)
+	}
+	return gitMembers, nil
+}
+
+// gitOrganisationTeamOfMember returns the name of another team the user is a member of which uses the same git
+// organisation or an empty string if there is none
+func gitOrganisationTeamOfMember(jxClient versioned.Interface, adminNs string, teamName string, settings *v1.TeamSettings, user string) (string, error) {
+	teams, names, err := kube.GetPendingTeams(jxClient, adminNs)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list the teams in namespace %s", adminNs)
+	}
+	for _, name := range names {
+		if name == teamName || util.StringArrayIndex(teams[name].Spec.Members, user) < 0 {
+			continue
+		}
+		otherSettings, err := teamGitSettings(jxClient, name)
+		if err != nil {
+			return "", err
+		}
+		if otherSettings != nil && otherSettings.Organisation == settings.Organisation &&
+			strings.TrimSuffix(otherSettings.GitServer, "/") == strings.TrimSuffix(settings.GitServer, "/") {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// teamGitSettings returns the team settings of the dev environment of the team or nil if the team has no dev
+// environment yet
+func teamGitSettings(jxClient versioned.Interface, teamNs string) (*v1.TeamSettings, error) {
+	env, err := jxClient.JenkinsV1().Environments(teamNs).Get(kube.LabelValueDevEnvironment, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the dev environment of team %s", teamNs)
+	}
+	return &env.Spec.TeamSettings, nil
 }
 
 // LoadProwOAuthConfig returns the OAuth Token for Prow
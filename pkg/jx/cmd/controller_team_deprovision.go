@@ -0,0 +1,414 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/prow"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// teamDeprovisionStep a step of the teardown of a team. Steps have to be safe to run again as the teardown resumes
+// from the step after the last one which completed
+type teamDeprovisionStep struct {
+	name        string
+	description string
+	run         func(team *v1.Team, kubeClient kubernetes.Interface, jxClient versioned.Interface, adminNs string) error
+}
+
+func (o *ControllerTeamOptions) teamDeprovisionSteps() []teamDeprovisionStep {
+	steps := []teamDeprovisionStep{}
+	if o.Archive {
+		steps = append(steps, teamDeprovisionStep{"archive", "archiving the team to the backup repository", o.archiveTeam})
+	}
+	return append(steps,
+		teamDeprovisionStep{"webhooks", "deleting the webhooks of the environment repositories", o.deleteTeamWebhooks},
+		teamDeprovisionStep{"prow", "removing the repositories of the team from prow", o.removeTeamProwConfig},
+		teamDeprovisionStep{"releases", "deleting the helm releases of the team", o.deleteTeamReleases},
+		teamDeprovisionStep{"environments", "deleting the environment namespaces", o.deleteTeamEnvironments},
+		teamDeprovisionStep{"namespace", "deleting the team namespace", o.deleteTeamNamespace},
+	)
+}
+
+// remainingTeamDeprovisionSteps returns the steps which have not completed yet
+func remainingTeamDeprovisionSteps(team *v1.Team, steps []teamDeprovisionStep) []teamDeprovisionStep {
+	last := team.Annotations[kube.AnnotationTeamDeprovisionStep]
+	if last == "" {
+		return steps
+	}
+	for i, step := range steps {
+		if step.name == last {
+			return steps[i+1:]
+		}
+	}
+	return steps
+}
+
+// deprovisionTeam removes the resources of a deleted team step by step, recording the progress on the team so that
+// a restarted controller resumes where it left off, then removes the finalizer so that the team can be removed
+func (o *ControllerTeamOptions) deprovisionTeam(team *v1.Team, kubeClient kubernetes.Interface, jxClient versioned.Interface, adminNs string) error {
+	teamNs := team.Name
+	if teamNs == adminNs {
+		return fmt.Errorf("the team %s is the admin namespace so it cannot be deleted", teamNs)
+	}
+	oc := &o.ControllerOptions
+	oc.SetDevNamespace(adminNs)
+
+	// lets resume from the latest progress as the event may be stale and not recreate a team which has gone
+	teamInterface := jxClient.JenkinsV1().Teams(adminNs)
+	team, err := teamInterface.Get(teamNs, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	steps := o.teamDeprovisionSteps()
+	remaining := remainingTeamDeprovisionSteps(team, steps)
+	for i, step := range remaining {
+		number := len(steps) - len(remaining) + i + 1
+		log.Infof("Team %s: %s (%d/%d)\n", util.ColorInfo(teamNs), step.description, number, len(steps))
+		err := oc.ModifyTeam(teamNs, func(t *v1.Team) error {
+			t.Status.ProvisionStatus = v1.TeamProvisionStatusDeleting
+			t.Status.Message = fmt.Sprintf("Deleting: %s (%d/%d)", step.description, number, len(steps))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = step.run(team, kubeClient, jxClient, adminNs)
+		if err != nil {
+			// lets leave the team deleting so that the teardown is retried when the team is resynced
+			message := fmt.Sprintf("Failed %s: %s", step.description, err)
+			modifyErr := oc.ModifyTeam(teamNs, func(t *v1.Team) error {
+				t.Status.Message = message
+				return nil
+			})
+			return util.CombineErrors(errors.New(message), modifyErr)
+		}
+
+		err = oc.ModifyTeam(teamNs, func(t *v1.Team) error {
+			if t.Annotations == nil {
+				t.Annotations = map[string]string{}
+			}
+			t.Annotations[kube.AnnotationTeamDeprovisionStep] = step.name
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	current, err := teamInterface.Get(teamNs, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if kube.RemoveTeamFinalizer(current) {
+		current.Status.Message = "Deleted"
+		current, err = teamInterface.Update(current)
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove the finalizer of team %s", teamNs)
+		}
+	}
+	// teams marked for deletion by an older 'jx delete team' have not been deleted yet
+	if current.DeletionTimestamp == nil {
+		err = kube.DeleteTeam(jxClient, adminNs, teamNs)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete team %s", teamNs)
+		}
+	}
+	log.Infof("Deleted team %s\n", util.ColorInfo(teamNs))
+	return nil
+}
+
+// archiveTeam saves the team, its environments and role bindings to the backup repository of the organisation
+func (o *ControllerTeamOptions) archiveTeam(team *v1.Team, kubeClient kubernetes.Interface, jxClient versioned.Interface, adminNs string) error {
+	settings, err := o.ControllerOptions.TeamSettings()
+	if err != nil {
+		return err
+	}
+	backup := &ControllerBackupOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: o.ControllerOptions.CommonOptions,
+		},
+		GitRepositoryOptions: o.GitRepositoryOptions,
+		Organisation:         settings.Organisation,
+	}
+	backup.BatchMode = true
+	if backup.GitRepositoryOptions.ServerURL == "" {
+		backup.GitRepositoryOptions.ServerURL = settings.GitServer
+	}
+	if backup.GitRepositoryOptions.Owner == "" {
+		backup.GitRepositoryOptions.Owner = settings.Organisation
+	}
+	dir, err := backup.getOrCreateBackupRepository()
+	if err != nil {
+		return err
+	}
+
+	teamNs := team.Name
+	err = backup.saveResourceToBackupFile(team, "team", teamNs, adminNs, dir)
+	if err != nil {
+		return err
+	}
+	envs, _, err := kube.GetEnvironments(jxClient, teamNs)
+	if err != nil {
+		return err
+	}
+	for name, env := range envs {
+		err = backup.saveResourceToBackupFile(env, "environment", name, teamNs, dir)
+		if err != nil {
+			return err
+		}
+	}
+	bindings, _, err := kube.GetEnvironmentRoles(jxClient, teamNs)
+	if err != nil {
+		return err
+	}
+	for name, binding := range bindings {
+		err = backup.saveResourceToBackupFile(binding, "environmentrolebinding", name, teamNs, dir)
+		if err != nil {
+			return err
+		}
+	}
+	return backup.pushDirIfChanges(dir, fmt.Sprintf("Archiving team %s", teamNs))
+}
+
+// teamEnvironments returns the environments of the team other than its development environment
+func teamEnvironments(jxClient versioned.Interface, teamNs string) ([]*v1.Environment, error) {
+	envs, names, err := kube.GetEnvironments(jxClient, teamNs)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return []*v1.Environment{}, nil
+		}
+		return nil, err
+	}
+	answer := []*v1.Environment{}
+	for _, name := range names {
+		env := envs[name]
+		if env.Spec.Kind != v1.EnvironmentKindTypeDevelopment {
+			answer = append(answer, env)
+		}
+	}
+	return answer, nil
+}
+
+// deleteTeamWebhooks deletes the webhooks of the environment repositories which point at the ingresses of the team
+func (o *ControllerTeamOptions) deleteTeamWebhooks(team *v1.Team, kubeClient kubernetes.Interface, jxClient versioned.Interface, adminNs string) error {
+	teamNs := team.Name
+	ingresses, err := kubeClient.ExtensionsV1beta1().Ingresses(teamNs).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	hosts := []string{}
+	for _, ingress := range ingresses.Items {
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host != "" {
+				hosts = append(hosts, rule.Host)
+			}
+		}
+	}
+	if len(hosts) == 0 {
+		return nil
+	}
+	envs, err := teamEnvironments(jxClient, teamNs)
+	if err != nil {
+		return err
+	}
+	o.ControllerOptions.BatchMode = true
+	for _, env := range envs {
+		gitURL := env.Spec.Source.URL
+		if gitURL == "" {
+			continue
+		}
+		provider, gitInfo, err := o.ControllerOptions.cachingGitProviderForURL(gitURL)
+		if err != nil {
+			return err
+		}
+		hooks, err := provider.ListWebHooks(gitInfo.Organisation, gitInfo.Name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list the webhooks of %s", gitURL)
+		}
+		for _, hook := range hooks {
+			if !webhookTargetsHost(hook.URL, hosts) {
+				continue
+			}
+			err = provider.DeleteWebHook(gitInfo.Organisation, gitInfo.Name, hook.ID)
+			if err != nil {
+				return errors.Wrapf(err, "failed to delete the webhook %s of %s", hook.URL, gitURL)
+			}
+			log.Infof("Deleted the webhook %s of %s\n", util.ColorInfo(hook.URL), util.ColorInfo(gitURL))
+		}
+	}
+	return nil
+}
+
+// webhookTargetsHost returns true if the host name of the webhook URL is one of the given hosts
+func webhookTargetsHost(hookURL string, hosts []string) bool {
+	u, err := url.Parse(hookURL)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	for _, host := range hosts {
+		if strings.EqualFold(u.Hostname(), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeTeamProwConfig removes the repositories of the team from the prow of the admin namespace. The prow config in
+// the team namespace goes with the namespace
+func (o *ControllerTeamOptions) removeTeamProwConfig(team *v1.Team, kubeClient kubernetes.Interface, jxClient versioned.Interface, adminNs string) error {
+	teamNs := team.Name
+	adminRepos, err := prow.GetRepositories(kubeClient, adminNs)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	teamRepos, err := prow.GetRepositories(kubeClient, teamNs)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	envs, err := teamEnvironments(jxClient, teamNs)
+	if err != nil {
+		return err
+	}
+	for _, env := range envs {
+		if env.Spec.Source.URL == "" {
+			continue
+		}
+		gitInfo, err := gits.ParseGitURL(env.Spec.Source.URL)
+		if err != nil {
+			return err
+		}
+		teamRepos = append(teamRepos, gitInfo.Organisation+"/"+gitInfo.Name)
+	}
+	for _, repo := range teamRepos {
+		if util.StringArrayIndex(adminRepos, repo) < 0 {
+			continue
+		}
+		err = prow.RemoveRepository(kubeClient, adminNs, repo)
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove %s from prow", repo)
+		}
+		log.Infof("Removed %s from prow\n", util.ColorInfo(repo))
+	}
+	return nil
+}
+
+// deleteTeamReleases deletes the helm releases installed by the team controller. Helm 2 release names are global so
+// only the releases in the namespaces of the team are deleted, never the same named releases of the admin team
+func (o *ControllerTeamOptions) deleteTeamReleases(team *v1.Team, kubeClient kubernetes.Interface, jxClient versioned.Interface, adminNs string) error {
+	teamNs := team.Name
+	envs, err := teamEnvironments(jxClient, teamNs)
+	if err != nil {
+		return err
+	}
+	releases := map[string]string{
+		"jx-prow":   teamNs,
+		"jenkins-x": teamNs,
+	}
+	for _, env := range envs {
+		releases[teamNs+"-"+env.Name] = env.Spec.Namespace
+	}
+	installed, err := helmReleaseNamespaces(o.ControllerOptions.Helm())
+	if err != nil {
+		return err
+	}
+	for _, release := range util.SortedMapKeys(releases) {
+		ns := releases[release]
+		installedNs, ok := installed[release]
+		if !ok {
+			// already deleted or never installed
+			continue
+		}
+		if installedNs != ns {
+			log.Warnf("Not deleting the helm release %s of namespace %s as it does not belong to team %s\n", release, installedNs, teamNs)
+			continue
+		}
+		err = o.ControllerOptions.Helm().DeleteRelease(ns, release, true)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete the helm release %s", release)
+		}
+		log.Infof("Deleted the helm release %s\n", util.ColorInfo(release))
+	}
+	return nil
+}
+
+// helmReleaseNamespaces returns the namespace of each installed helm release from the output of helm list whose last
+// column is the namespace
+func helmReleaseNamespaces(helmer helm.Helmer) (map[string]string, error) {
+	output, err := helmer.ListCharts()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the installed helm releases")
+	}
+	answer := map[string]string{}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for _, line := range lines[1:] {
+		fields := strings.Split(line, "\t")
+		if len(fields) > 4 {
+			answer[strings.TrimSpace(fields[0])] = strings.TrimSpace(fields[len(fields)-1])
+		}
+	}
+	return answer, nil
+}
+
+// deleteTeamEnvironments deletes the namespaces and then the Environments of the team other than its development environment
+func (o *ControllerTeamOptions) deleteTeamEnvironments(team *v1.Team, kubeClient kubernetes.Interface, jxClient versioned.Interface, adminNs string) error {
+	teamNs := team.Name
+	envs, err := teamEnvironments(jxClient, teamNs)
+	if err != nil {
+		return err
+	}
+	for _, env := range envs {
+		ns := env.Spec.Namespace
+		if ns == "" {
+			ns = teamNs + "-" + env.Name
+		}
+		err = deleteNamespaceIfExists(kubeClient, ns)
+		if err != nil {
+			return err
+		}
+		err = jxClient.JenkinsV1().Environments(teamNs).Delete(env.Name, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete Environment %s", env.Name)
+		}
+	}
+	return nil
+}
+
+// deleteTeamNamespace deletes the namespace of the team
+func (o *ControllerTeamOptions) deleteTeamNamespace(team *v1.Team, kubeClient kubernetes.Interface, jxClient versioned.Interface, adminNs string) error {
+	return deleteNamespaceIfExists(kubeClient, team.Name)
+}
+
+func deleteNamespaceIfExists(kubeClient kubernetes.Interface, ns string) error {
+	err := kubeClient.CoreV1().Namespaces().Delete(ns, &metav1.DeleteOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete the namespace %s", ns)
+	}
+	log.Infof("Deleted the namespace %s\n", util.ColorInfo(ns))
+	return nil
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	gits_test "github.com/jenkins-x/jx/pkg/gits/mocks"
+	helm_test "github.com/jenkins-x/jx/pkg/helm/mocks"
+	"github.com/jenkins-x/jx/pkg/kube"
+	. "github.com/petergtz/pegomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func createDeletingTeamOptions(lastStep string) (*ControllerTeamOptions, *helm_test.MockHelmer) {
+	team := kube.CreateTeam("jx", "myteam", nil)
+	kube.AddTeamFinalizer(team)
+	team.Status.ProvisionStatus = v1.TeamProvisionStatusDeleting
+	if lastStep != "" {
+		team.Annotations = map[string]string{kube.AnnotationTeamDeprovisionStep: lastStep}
+	}
+	staging := &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "staging",
+			Namespace: "myteam",
+		},
+		Spec: v1.EnvironmentSpec{
+			Namespace: "myteam-staging",
+			Kind:      v1.EnvironmentKindTypePermanent,
+		},
+	}
+
+	helmer := helm_test.NewMockHelmer()
+	o := &ControllerTeamOptions{}
+	ConfigureTestOptionsWithResources(&o.ControllerOptions.CommonOptions,
+		[]runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "myteam"}},
+		},
+		[]runtime.Object{team, staging},
+		gits_test.NewMockGitter(),
+		helmer,
+	)
+	return o, helmer
+}
+
+// stubHelmReleases makes helm list the releases given as name and namespace pairs
+func stubHelmReleases(helmer *helm_test.MockHelmer, releases ...string) {
+	lines := []string{"NAME\tREVISION\tUPDATED\tSTATUS\tCHART\tAPP VERSION\tNAMESPACE"}
+	for i := 0; i+1 < len(releases); i += 2 {
+		lines = append(lines, strings.Join([]string{releases[i], "1", "Mon Oct 15 10:00:00 2018", "DEPLOYED", "chart-1.0.0", "", releases[i+1]}, "\t"))
+	}
+	When(helmer.ListCharts()).ThenReturn(strings.Join(lines, "\n"), nil)
+}
+
+func TestDeprovisionTeam(t *testing.T) {
+	RegisterMockTestingT(t)
+	o, helmer := createDeletingTeamOptions("")
+	stubHelmReleases(helmer, "jenkins-x", "myteam", "myteam-staging", "myteam-staging")
+	kubeClient, _, err := o.ControllerOptions.KubeClient()
+	require.NoError(t, err)
+	jxClient, _, err := o.ControllerOptions.JXClient()
+	require.NoError(t, err)
+	team, err := jxClient.JenkinsV1().Teams("jx").Get("myteam", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	err = o.deprovisionTeam(team, kubeClient, jxClient, "jx")
+	require.NoError(t, err)
+
+	helmer.VerifyWasCalledOnce().DeleteRelease("myteam-staging", "myteam-staging", true)
+	helmer.VerifyWasCalledOnce().DeleteRelease("myteam", "jenkins-x", true)
+	helmer.VerifyWasCalled(Never()).DeleteRelease(AnyString(), EqString("jx-prow"), AnyBool())
+	for _, ns := range []string{"myteam", "myteam-staging"} {
+		_, err = kubeClient.CoreV1().Namespaces().Get(ns, metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err), "namespace %s should have been deleted", ns)
+	}
+	_, err = jxClient.JenkinsV1().Environments("myteam").Get("staging", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "the staging Environment should have been deleted")
+	_, err = jxClient.JenkinsV1().Teams("jx").Get("myteam", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "the team should have been deleted")
+	_, err = kubeClient.CoreV1().Namespaces().Get("jx", metav1.GetOptions{})
+	assert.NoError(t, err, "the admin namespace should be kept")
+}
+
+func TestDeprovisionTeamKeepsSameNamedReleasesOfOtherNamespaces(t *testing.T) {
+	RegisterMockTestingT(t)
+	o, helmer := createDeletingTeamOptions("")
+	// the admin team's platform has the same release names as the team's
+	stubHelmReleases(helmer, "jenkins-x", "jx", "jx-prow", "jx", "myteam-staging", "myteam-staging")
+	kubeClient, _, err := o.ControllerOptions.KubeClient()
+	require.NoError(t, err)
+	jxClient, _, err := o.ControllerOptions.JXClient()
+	require.NoError(t, err)
+	team, err := jxClient.JenkinsV1().Teams("jx").Get("myteam", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	err = o.deleteTeamReleases(team, kubeClient, jxClient, "jx")
+	require.NoError(t, err)
+
+	helmer.VerifyWasCalledOnce().DeleteRelease("myteam-staging", "myteam-staging", true)
+	helmer.VerifyWasCalled(Never()).DeleteRelease(AnyString(), EqString("jenkins-x"), AnyBool())
+	helmer.VerifyWasCalled(Never()).DeleteRelease(AnyString(), EqString("jx-prow"), AnyBool())
+}
+
+func TestDeprovisionTeamResumesAfterLastStep(t *testing.T) {
+	RegisterMockTestingT(t)
+	o, helmer := createDeletingTeamOptions("releases")
+	kubeClient, _, err := o.ControllerOptions.KubeClient()
+	require.NoError(t, err)
+	jxClient, _, err := o.ControllerOptions.JXClient()
+	require.NoError(t, err)
+	team, err := jxClient.JenkinsV1().Teams("jx").Get("myteam", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	err = o.deprovisionTeam(team, kubeClient, jxClient, "jx")
+	require.NoError(t, err)
+
+	helmer.VerifyWasCalled(Never()).DeleteRelease(AnyString(), AnyString(), AnyBool())
+	_, err = kubeClient.CoreV1().Namespaces().Get("myteam-staging", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "the environment namespace should have been deleted")
+}
+
+func TestWebhookTargetsHost(t *testing.T) {
+	t.Parallel()
+	hosts := []string{"jenkins.myteam.example.com", "hook.myteam.example.com"}
+	assert.True(t, webhookTargetsHost("http://jenkins.myteam.example.com/github-webhook/", hosts))
+	assert.True(t, webhookTargetsHost("https://hook.myteam.example.com/hook", hosts))
+	assert.False(t, webhookTargetsHost("https://hook.otherteam.example.com/hook", hosts))
+	assert.True(t, webhookTargetsHost("https://HOOK.myteam.example.com:443/hook", hosts))
+	assert.False(t, webhookTargetsHost("https://hook.myteam.example.com.evil.io/hook", hosts))
+	assert.False(t, webhookTargetsHost("https://evil.io/?target=https://hook.myteam.example.com", hosts))
+	assert.False(t, webhookTargetsHost("not a url", hosts))
+}
+
+func TestDeprovisionTeamRefusesAdminNamespace(t *testing.T) {
+	RegisterMockTestingT(t)
+	o, helmer := createDeletingTeamOptions("")
+	kubeClient, _, err := o.ControllerOptions.KubeClient()
+	require.NoError(t, err)
+	jxClient, _, err := o.ControllerOptions.JXClient()
+	require.NoError(t, err)
+	team, err := jxClient.JenkinsV1().Teams("jx").Get("myteam", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	err = o.deprovisionTeam(team, kubeClient, jxClient, "myteam")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "admin namespace")
+
+	helmer.VerifyWasCalled(Never()).DeleteRelease(AnyString(), AnyString(), AnyBool())
+	_, err = kubeClient.CoreV1().Namespaces().Get("myteam", metav1.GetOptions{})
+	assert.NoError(t, err, "the admin namespace should be kept")
+	_, err = jxClient.JenkinsV1().Environments("myteam").Get("staging", metav1.GetOptions{})
+	assert.NoError(t, err, "the staging Environment should be kept")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	versiond_mocks "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func teamDevEnvironment(ns string, organisation string) *v1.Environment {
+	return &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kube.LabelValueDevEnvironment,
+			Namespace: ns,
+		},
+		Spec: v1.EnvironmentSpec{
+			TeamSettings: v1.TeamSettings{
+				GitServer:    "https://github.com",
+				Organisation: organisation,
+			},
+		},
+	}
+}
+
+func TestTeamGitSettingsUsesTheDevEnvironmentOfTheTeam(t *testing.T) {
+	t.Parallel()
+	jxClient := versiond_mocks.NewSimpleClientset(teamDevEnvironment("jx", "admin-org"), teamDevEnvironment("myteam", "myteam-org"))
+
+	settings, err := teamGitSettings(jxClient, "myteam")
+	require.NoError(t, err)
+	require.NotNil(t, settings)
+	assert.Equal(t, "https://github.com", settings.GitServer)
+	assert.Equal(t, "myteam-org", settings.Organisation)
+
+	settings, err = teamGitSettings(jxClient, "otherteam")
+	require.NoError(t, err)
+	assert.Nil(t, settings)
+}
+
+func TestReconcileGitOrganisationMembersOnlyRemovesMembersAddedByTheController(t *testing.T) {
+	t.Parallel()
+	team := func(name string, members ...string) *v1.Team {
+		return &v1.Team{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "jx",
+			},
+			Spec: v1.TeamSpec{
+				Members: members,
+			},
+		}
+	}
+	myTeam := team("myteam", "newbie")
+	kube.SetTeamGitMembers(myTeam, []string{"leaver", "shared"})
+	jxClient := versiond_mocks.NewSimpleClientset(
+		myTeam,
+		team("otherteam", "shared", "bystander"),
+		team("elsewhere", "leaver"),
+		teamDevEnvironment("myteam", "myorg"),
+		teamDevEnvironment("otherteam", "myorg"),
+		teamDevEnvironment("elsewhere", "anotherorg"),
+	)
+	provider := &gits.FakeProvider{
+		Members: map[string][]string{
+			"myorg": {"leaver", "shared", "bystander", "founder"},
+		},
+	}
+	settings := &teamDevEnvironment("myteam", "myorg").Spec.TeamSettings
+
+	gitMembers, err := reconcileGitOrganisationMembers(provider, jxClient, "jx", myTeam, settings,
+		[]string{"newbie"}, []string{"leaver", "shared", "founder"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"newbie"}, gitMembers)
+	assert.Equal(t, []string{"shared", "bystander", "founder", "newbie"}, provider.Members["myorg"],
+		"only the members added by the controller who are not in another team of the organisation should be removed")
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/usersync"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// ControllerUserSyncOptions are the flags for the commands
+type ControllerUserSyncOptions struct {
+	ControllerOptions
+
+	LDAP         usersync.LDAPDirectory
+	SCIM         usersync.SCIMDirectory
+	GroupRoles   []string
+	DeleteUsers  bool
+	AllowEmpty   bool
+	PollInterval time.Duration
+	NoWatch      bool
+}
+
+var (
+	controllerUserSyncLong = templates.LongDesc(`
+		Runs the user sync controller which periodically imports the users of an LDAP server or a SCIM endpoint
+		into Users and grants them the roles of the teams mapped to their groups.
+
+		Groups are mapped to roles with 'group=role' which grants the role in the current team or 'group=team/role'.
+		When a user leaves a group the roles the group granted are removed; roles given to the user with
+		'jx edit userrole' are left alone. An import which finds no users at all fails rather than removing
+		everyone's roles unless --allow-empty-directory is specified.
+
+		The LDAP bind password defaults to $LDAP_BIND_PASSWORD and the SCIM token to $SCIM_TOKEN.
+`)
+
+	controllerUserSyncExample = templates.Examples(`
+		# Import the users of an LDAP server every 10 minutes making the developers group team members
+		jx controller usersync --ldap-url ldap://ldap.example.com --ldap-bind-dn cn=admin,dc=example,dc=com \
+			--ldap-base-dn ou=people,dc=example,dc=com --group-role developers=member --group-role leads=jx/owner
+
+		# Import the users of a SCIM endpoint once
+		jx controller usersync --scim-url https://example.okta.com/scim/v2 --group-role engineering=viewer --no-watch
+	`)
+)
+
+// NewCmdControllerUserSync creates a command object for the "controller usersync" command
+func NewCmdControllerUserSync(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerUserSyncOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "usersync",
+		Short:   "Runs the controller which imports users and their group roles from an LDAP server or SCIM endpoint",
+		Long:    controllerUserSyncLong,
+		Example: controllerUserSyncExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.LDAP.URL, "ldap-url", "", "", "The URL of the LDAP server such as ldap://ldap.example.com or ldaps://ldap.example.com")
+	cmd.Flags().StringVarP(&options.LDAP.BindDN, "ldap-bind-dn", "", "", "The DN to bind to the LDAP server as. Binds anonymously if not specified")
+	cmd.Flags().StringVarP(&options.LDAP.BindPassword, "ldap-bind-password", "", os.Getenv("LDAP_BIND_PASSWORD"), "The password of the bind DN")
+	cmd.Flags().BoolVarP(&options.LDAP.StartTLS, "ldap-start-tls", "", false, "Upgrades ldap:// connections to TLS with StartTLS")
+	cmd.Flags().BoolVarP(&options.LDAP.InsecureSkipVerify, "ldap-insecure-skip-verify", "", false, "Skips verifying the certificate of the LDAP server")
+	cmd.Flags().StringVarP(&options.LDAP.UserBaseDN, "ldap-base-dn", "", "", "The DN to search for users under")
+	cmd.Flags().StringVarP(&options.LDAP.UserFilter, "ldap-user-filter", "", usersync.DefaultLDAPUserFilter, "The filter used to find the users")
+	cmd.Flags().StringVarP(&options.LDAP.LoginAttribute, "ldap-login-attribute", "", usersync.DefaultLDAPLoginAttribute, "The attribute holding the login of a user such as sAMAccountName for Active Directory")
+	cmd.Flags().StringVarP(&options.LDAP.NameAttribute, "ldap-name-attribute", "", usersync.DefaultLDAPNameAttribute, "The attribute holding the name of a user")
+	cmd.Flags().StringVarP(&options.LDAP.EmailAttribute, "ldap-email-attribute", "", usersync.DefaultLDAPEmailAttribute, "The attribute holding the email address of a user")
+	cmd.Flags().StringVarP(&options.LDAP.GroupBaseDN, "ldap-group-base-dn", "", "", "The DN to search for groups under. Defaults to the base DN of the users")
+	cmd.Flags().StringVarP(&options.LDAP.GroupFilter, "ldap-group-filter", "", usersync.DefaultLDAPGroupFilter, "The filter used to find the groups")
+	cmd.Flags().StringVarP(&options.LDAP.GroupNameAttribute, "ldap-group-name-attribute", "", usersync.DefaultLDAPGroupNameAttribute, "The attribute holding the name of a group")
+	cmd.Flags().StringVarP(&options.SCIM.URL, "scim-url", "", "", "The base URL of a SCIM 2.0 endpoint to import the users from")
+	cmd.Flags().StringVarP(&options.SCIM.Token, "scim-token", "", os.Getenv("SCIM_TOKEN"), "The bearer token used to query the SCIM endpoint")
+	cmd.Flags().StringArrayVarP(&options.GroupRoles, "group-role", "g", []string{}, "Grants the members of a group a role with 'group=role' or 'group=team/role'")
+	cmd.Flags().BoolVarP(&options.DeleteUsers, "delete-users", "", false, "Deletes the imported Users which are removed from the directory")
+	cmd.Flags().BoolVarP(&options.AllowEmpty, "allow-empty-directory", "", false, "Allows a directory without any users to remove the roles of all the Users imported from it. Otherwise the import fails as the filter or base DN is probably wrong")
+	cmd.Flags().DurationVarP(&options.PollInterval, "poll", "", 10*time.Minute, "The interval between imports")
+	cmd.Flags().BoolVarP(&options.NoWatch, "no-watch", "n", false, "Imports the users once rather than polling the directory")
+
+	options.addCommonFlags(cmd)
+
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerUserSyncOptions) Run() error {
+	directory, err := o.directory()
+	if err != nil {
+		return err
+	}
+	err = o.registerUserCRD()
+	if err != nil {
+		return err
+	}
+	err = o.registerEnvironmentRoleBindingCRD()
+	if err != nil {
+		return err
+	}
+	if o.NoWatch {
+		return o.syncUsers(directory)
+	}
+	log.Infof("Importing the users of %s every %s\n", util.ColorInfo(directory.Name()), util.ColorInfo(o.PollInterval.String()))
+	for {
+		err = o.syncUsers(directory)
+		if err != nil {
+			log.Warnf("Failed to import the users of %s: %s\n", directory.Name(), err)
+		}
+		time.Sleep(o.PollInterval)
+	}
+}
+
+func (o *ControllerUserSyncOptions) directory() (usersync.Directory, error) {
+	if o.LDAP.URL != "" && o.SCIM.URL != "" {
+		return nil, fmt.Errorf("Specify only one of --ldap-url and --scim-url")
+	}
+	if o.LDAP.URL != "" {
+		if o.LDAP.UserBaseDN == "" {
+			return nil, util.MissingOption("ldap-base-dn")
+		}
+		return &o.LDAP, nil
+	}
+	if o.SCIM.URL != "" {
+		return &o.SCIM, nil
+	}
+	return nil, fmt.Errorf("Specify the directory to import the users from with --ldap-url or --scim-url")
+}
+
+func (o *ControllerUserSyncOptions) syncUsers(directory usersync.Directory) error {
+	kubeClient, devNs, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return err
+	}
+	adminNs, err := kube.GetAdminNamespace(kubeClient, devNs)
+	if err != nil {
+		return err
+	}
+	groupRoles, err := usersync.ParseGroupRoles(o.GroupRoles, devNs)
+	if err != nil {
+		return err
+	}
+	users, err := directory.ListUsers()
+	if err != nil {
+		return err
+	}
+	syncer := &usersync.UserSyncer{
+		KubeClient:     kubeClient,
+		JXClient:       jxClient,
+		AdminNamespace: adminNs,
+		GroupRoles:     groupRoles,
+		DeleteUsers:    o.DeleteUsers,
+		AllowEmpty:     o.AllowEmpty,
+	}
+	result, err := syncer.Sync(directory.Name(), users)
+	if err != nil {
+		return err
+	}
+	log.Infof("Imported %d users from %s: %s created, %s updated, %s deleted and %s with changed roles\n", len(users),
+		util.ColorInfo(directory.Name()), userSyncLoginsInfo(result.Created), userSyncLoginsInfo(result.Updated), userSyncLoginsInfo(result.Deleted),
+		userSyncLoginsInfo(result.RolesChanged))
+	return nil
+}
+
+func userSyncLoginsInfo(logins []string) string {
+	if len(logins) == 0 {
+		return "none"
+	}
+	return util.ColorInfo(strings.Join(logins, ", "))
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/jenkins-x/jx/pkg/webhooks"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ControllerWebhookRecorderOptions are the flags for the commands
+type ControllerWebhookRecorderOptions struct {
+	ControllerOptions
+
+	Port              int
+	Dir               string
+	MaxEvents         int
+	ForwardURL        string
+	ForwardSecret     string
+	ForwardSecretName string
+	EventsToken       string
+	TopicArns         []string
+}
+
+var (
+	controllerWebhookRecorderLong = templates.LongDesc(`
+		Runs the webhook recorder which stores the webhooks it receives from git providers so that they can be inspected
+		and replayed with 'jx step webhook replay'.
+
+		The recorder can sit in front of Jenkins or the Prow hook forwarding each webhook on to it.
+		It is installed into a cluster with 'jx create addon webhook-recorder'.
+
+		The recorded events are listed as JSON on the /events path to the clients which present the events token as a
+		bearer token. Unless the --events-token is given the token is taken from the Secret jx-webhook-recorder which
+		is created with a random token if it does not exist.
+
+		CodeCommit webhooks are SNS subscriptions which the recorder confirms. The SNS notifications of the CodeCommit
+		repository triggers are forwarded as GitHub push events signed with the --forward-secret so that the Prow hook,
+		or the /github-webhook/ endpoint of Jenkins, can handle them. The forward secret can be loaded from the hmac of
+		a Secret, such as the hmac-token Secret of Prow, with --forward-secret-name. SNS messages are only trusted if
+		they are signed by SNS and come from one of the --sns-topic-arn topics.
+`)
+
+	controllerWebhookRecorderExample = templates.Examples(`
+		# Record the webhooks sent to port 8080
+		jx controller webhook-recorder
+
+		# Record the webhooks and forward them to the Prow hook
+		jx controller webhook-recorder --forward-url http://hook/hook
+
+		# Forward the CodeCommit notifications as push events signed with the Prow HMAC token
+		jx controller webhook-recorder --forward-url http://hook/hook --forward-secret-name hmac-token --sns-topic-arn 'arn:aws:sns:us-east-1:123456789012:jx-codecommit-*'
+	`)
+)
+
+// NewCmdControllerWebhookRecorder creates a command object for the "controller webhook-recorder" command
+func NewCmdControllerWebhookRecorder(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerWebhookRecorderOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "webhook-recorder",
+		Short:   "Runs the service which records the webhooks received from git providers",
+		Aliases: []string{"webhook-recorders"},
+		Long:    controllerWebhookRecorderLong,
+		Example: controllerWebhookRecorderExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().IntVarP(&options.Port, "port", "p", 8080, "The port to listen on")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "/tmp/webhooks", "The directory in which the webhooks are stored")
+	cmd.Flags().IntVarP(&options.MaxEvents, "max-events", "m", webhooks.DefaultMaxEvents, "The number of webhooks to keep")
+	cmd.Flags().StringVarP(&options.ForwardURL, "forward-url", "f", "", "The URL of the webhook endpoint to forward the webhooks to")
+	cmd.Flags().StringVarP(&options.ForwardSecret, "forward-secret", "", "", "The webhook secret to sign the push events translated from CodeCommit notifications with")
+	cmd.Flags().StringVarP(&options.ForwardSecretName, "forward-secret-name", "", "", "The name of the Secret whose hmac is the forward secret if no --forward-secret is given, such as the Prow Secret "+kube.SecretProwHMACToken)
+	cmd.Flags().StringArrayVarP(&options.TopicArns, "sns-topic-arn", "", nil, "The SNS topics of the CodeCommit webhooks whose messages are trusted. A topic ending with * matches every topic starting with the rest of it. Can be specified multiple times")
+	cmd.Flags().StringVarP(&options.EventsToken, "events-token", "", "", "The bearer token required to list the recorded events. Defaults to the token in the Secret "+kube.SecretWebhookRecorder)
+
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerWebhookRecorderOptions) Run() error {
+	store, err := webhooks.NewFileStore(o.Dir, o.MaxEvents)
+	if err != nil {
+		return err
+	}
+	token := o.EventsToken
+	forwardSecret := o.ForwardSecret
+	if token == "" || (forwardSecret == "" && o.ForwardSecretName != "") {
+		// the recorder runs in the dev namespace so it only needs access to the secrets of its own namespace
+		kubeClient, ns, err := o.KubeClient()
+		if err != nil {
+			return err
+		}
+		if token == "" {
+			token, err = webhookRecorderEventsToken(kubeClient, ns, true)
+			if err != nil {
+				return err
+			}
+		}
+		if forwardSecret == "" && o.ForwardSecretName != "" {
+			forwardSecret, err = webhookRecorderForwardSecret(kubeClient, ns, o.ForwardSecretName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	recorder := &webhooks.Recorder{
+		Store:         store,
+		ForwardURL:    o.ForwardURL,
+		ForwardSecret: forwardSecret,
+		EventsToken:   token,
+		TopicArns:     o.TopicArns,
+	}
+	address := fmt.Sprintf(":%d", o.Port)
+	log.Infof("Recording webhooks on %s into %s\n", util.ColorInfo(address), util.ColorInfo(o.Dir))
+	if o.ForwardURL != "" {
+		log.Infof("Forwarding webhooks to %s\n", util.ColorInfo(o.ForwardURL))
+	}
+	return http.ListenAndServe(address, recorder)
+}
+
+// webhookRecorderEventsToken returns the token required to list the events of the webhook recorder from its Secret
+// in the namespace optionally creating the Secret with a random token if it does not exist
+func webhookRecorderEventsToken(kubeClient kubernetes.Interface, ns string, create bool) (string, error) {
+	secrets := kubeClient.CoreV1().Secrets(ns)
+	secret, err := secrets.Get(kube.SecretWebhookRecorder, metav1.GetOptions{})
+	if err == nil {
+		token := string(secret.Data[kube.SecretDataToken])
+		if token == "" {
+			return "", fmt.Errorf("no %s in the Secret %s in namespace %s", kube.SecretDataToken, kube.SecretWebhookRecorder, ns)
+		}
+		return token, nil
+	}
+	if !apierrors.IsNotFound(err) || !create {
+		return "", errors.Wrapf(err, "failed to load the events token of the webhook recorder from the Secret %s in namespace %s", kube.SecretWebhookRecorder, ns)
+	}
+	data := make([]byte, 32)
+	_, err = rand.Read(data)
+	if err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(data)
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: kube.SecretWebhookRecorder,
+		},
+		Data: map[string][]byte{
+			kube.SecretDataToken: []byte(token),
+		},
+	}
+	_, err = secrets.Create(secret)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create the Secret %s in namespace %s", kube.SecretWebhookRecorder, ns)
+	}
+	log.Infof("Created the events token of the webhook recorder in the Secret %s\n", util.ColorInfo(kube.SecretWebhookRecorder))
+	return token, nil
+}
+
+// webhookRecorderForwardSecret returns the hmac of the Secret in the namespace which the push events translated from
+// CodeCommit notifications are signed with
+func webhookRecorderForwardSecret(kubeClient kubernetes.Interface, ns string, name string) (string, error) {
+	secret, err := kubeClient.CoreV1().Secrets(ns).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to load the forward secret of the webhook recorder from the Secret %s in namespace %s", name, ns)
+	}
+	hmac := string(secret.Data[kube.SecretDataHMAC])
+	if hmac == "" {
+		return "", fmt.Errorf("no %s in the Secret %s in namespace %s", kube.SecretDataHMAC, name, ns)
+	}
+	return hmac, nil
+}
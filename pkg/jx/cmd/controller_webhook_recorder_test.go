@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWebhookRecorderEventsToken(t *testing.T) {
+	t.Parallel()
+	kubeClient := fake.NewSimpleClientset()
+
+	_, err := webhookRecorderEventsToken(kubeClient, "jx", false)
+	require.Error(t, err, "the token should only be created by the recorder")
+
+	token, err := webhookRecorderEventsToken(kubeClient, "jx", true)
+	require.NoError(t, err)
+	assert.Len(t, token, 64)
+
+	secret, err := kubeClient.CoreV1().Secrets("jx").Get(kube.SecretWebhookRecorder, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, token, string(secret.Data[kube.SecretDataToken]))
+
+	loaded, err := webhookRecorderEventsToken(kubeClient, "jx", false)
+	require.NoError(t, err)
+	assert.Equal(t, token, loaded)
+}
@@ -46,6 +46,7 @@ type ControllerWorkflowOptions struct {
 	PullRequestPollDuration *time.Duration
 	workflowMap             map[string]*v1.Workflow
 	pipelineMap             map[string]*v1.PipelineActivity
+	mergedPullRequests      map[string]bool
 }
 
 // NewCmdControllerWorkflow creates a command object for the generic "get" action, which
@@ -80,6 +81,7 @@ func NewCmdControllerWorkflow(f Factory, in terminal.FileReader, out terminal.Fi
 	cmd.Flags().StringVarP(&options.LocalHelmRepoName, "helm-repo-name", "r", kube.LocalHelmRepoName, "The name of the helm repository that contains the app")
 	cmd.Flags().BoolVarP(&options.NoWatch, "no-watch", "", false, "Disable watch so just performs any delta processes on pending workflows")
 	cmd.Flags().StringVarP(&options.PullRequestPollTime, optionPullRequestPollTime, "", "20s", "Poll time when waiting for a Pull Request to merge")
+	options.addGitCacheFlags(cmd)
 	return cmd
 }
 
@@ -171,6 +173,7 @@ func (o *ControllerWorkflowOptions) Run() error {
 			}
 			//o.pollGitPipelineStatuses(jxClient, ns)
 			o.ReloadAndPollGitPipelineStatuses(jxClient, ns)
+			o.logGitProviderMetrics()
 		}
 	}()
 
@@ -400,7 +403,7 @@ func (o *ControllerWorkflowOptions) createGitProviderForPR(prURL string) (gits.G
 		}
 		return o.FakeGitProvider, gitInfo, nil
 	}
-	answer, gitInfo, err := o.createGitProviderForURLWithoutKind(gitUrl)
+	answer, gitInfo, err := o.cachingGitProviderForURL(gitUrl)
 	if err != nil {
 		return answer, gitInfo, errors.Wrapf(err, "Failed for git URL %s", gitUrl)
 	}
@@ -419,7 +422,7 @@ func (o *ControllerWorkflowOptions) createGitProvider(activity *v1.PipelineActiv
 		}
 		return o.FakeGitProvider, gitInfo, nil
 	}
-	answer, gitInfo, err := o.createGitProviderForURLWithoutKind(gitUrl)
+	answer, gitInfo, err := o.cachingGitProviderForURL(gitUrl)
 	if err != nil {
 		return answer, gitInfo, errors.Wrapf(err, "Failed for git URL %s", gitUrl)
 	}
@@ -521,6 +524,9 @@ func (o *ControllerWorkflowOptions) pollGitStatusforPipeline(activity *v1.Pipeli
 				} else {
 					mergeSha := *pr.MergeCommitSHA
 					mergedPR := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromotePullRequestStep) error {
+						if p.MergeCommitSHA == "" {
+							o.auditPromotionApproval(a, envName, prURL, pr.MergedBy)
+						}
 						kube.CompletePromotionPullRequest(a, s, ps, p)
 						p.MergeCommitSHA = mergeSha
 						return nil
@@ -628,6 +634,11 @@ func (o *ControllerWorkflowOptions) pollGitStatusforPipeline(activity *v1.Pipeli
 							err = gitProvider.MergePullRequest(pr, "jx promote automatically merged promotion PR")
 							if err != nil {
 								log.Warnf("Failed to merge the Pull Request %s due to %s maybe I don't have karma?\n", pr.URL, err)
+							} else {
+								if o.mergedPullRequests == nil {
+									o.mergedPullRequests = map[string]bool{}
+								}
+								o.mergedPullRequests[prURL] = true
 							}
 						}
 					} else if status == "error" || status == "failure" {
@@ -652,6 +663,12 @@ func (o *ControllerWorkflowOptions) pollGitStatusforPipeline(activity *v1.Pipeli
 	}
 }
 
+// auditPromotionApproval records the merge of a promotion Pull Request in the audit trail
+func (o *ControllerWorkflowOptions) auditPromotionApproval(activity *v1.PipelineActivity, envName string, prURL string, mergedBy *gits.GitUser) {
+	o.auditPromotionMerge(activity.RepositoryName(), envName, activity.Spec.Version, prURL, mergedBy, o.mergedPullRequests[prURL])
+	delete(o.mergedPullRequests, prURL)
+}
+
 func (o *ControllerWorkflowOptions) createReleaseInfo(activity *v1.PipelineActivity, env *v1.Environment) *ReleaseInfo {
 	spec := &activity.Spec
 	app := activity.RepositoryName()
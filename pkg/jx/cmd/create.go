@@ -93,6 +93,7 @@ func NewCmdCreate(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	cmd.AddCommand(NewCmdCreateToken(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateTracker(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateUser(f, in, out, errOut))
+	cmd.AddCommand(NewCmdCreateWebhookEvent(f, in, out, errOut))
 	return cmd
 }
 
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const (
+	defaultWebhookRecorderName = "webhook-recorder"
+
+	// webhookRecorderProwForwardURL the in cluster URL of the Prow hook
+	webhookRecorderProwForwardURL = "http://hook/hook"
+	// webhookRecorderJenkinsForwardURL the in cluster URL of the GitHub webhook endpoint of Jenkins
+	webhookRecorderJenkinsForwardURL = "http://jenkins:8080/github-webhook/"
+)
+
+var (
+	createAddonWebhookRecorderLong = templates.LongDesc(`
+		Creates the webhook recorder addon which records the webhooks of the git providers and forwards them to the
+		Prow hook, or to Jenkins if Prow is not used.
+
+		CodeCommit webhooks are registered with the recorder which forwards them as push events signed with the
+		HMAC token of Prow. Only the SNS messages of the --sns-topic-arn topics are trusted which default to the
+		CodeCommit webhook topics of the current AWS account and region.
+`)
+
+	createAddonWebhookRecorderExample = templates.Examples(`
+		# Create the webhook-recorder addon
+		jx create addon webhook-recorder
+
+		# Create the webhook-recorder addon trusting the CodeCommit webhooks of another account
+		jx create addon webhook-recorder --sns-topic-arn 'arn:aws:sns:eu-west-1:123456789012:jx-codecommit-*'
+	`)
+)
+
+// CreateAddonWebhookRecorderOptions the options for the create addon webhook-recorder command
+type CreateAddonWebhookRecorderOptions struct {
+	CreateAddonOptions
+
+	ForwardURL string
+	TopicArns  []string
+}
+
+// NewCmdCreateAddonWebhookRecorder creates a command object for the "create addon webhook-recorder" command
+func NewCmdCreateAddonWebhookRecorder(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &CreateAddonWebhookRecorderOptions{
+		CreateAddonOptions: CreateAddonOptions{
+			CreateOptions: CreateOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "webhook-recorder",
+		Short:   "Create the webhook recorder addon",
+		Aliases: []string{"webhook-recorders"},
+		Long:    createAddonWebhookRecorderLong,
+		Example: createAddonWebhookRecorderExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+	options.addFlags(cmd, kube.DefaultNamespace, defaultWebhookRecorderName)
+
+	cmd.Flags().StringVarP(&options.Version, "version", "v", "", "The version of the webhook recorder chart to use")
+	cmd.Flags().StringVarP(&options.ForwardURL, "forward-url", "f", "", "The URL to forward the webhooks to. Defaults to the Prow hook, or Jenkins if Prow is not used")
+	cmd.Flags().StringArrayVarP(&options.TopicArns, "sns-topic-arn", "", nil, "The SNS topics of the CodeCommit webhooks whose messages are trusted. Defaults to the CodeCommit webhook topics of the current AWS account and region")
+	return cmd
+}
+
+// Run implements the command
+func (o *CreateAddonWebhookRecorderOptions) Run() error {
+	if o.ReleaseName == "" {
+		return util.MissingOption(optionRelease)
+	}
+	err := o.ensureHelm()
+	if err != nil {
+		return errors.Wrap(err, "failed to ensure that helm is present")
+	}
+	setValues, err := o.webhookRecorderValues()
+	if err != nil {
+		return err
+	}
+	if o.SetValues != "" {
+		setValues = append(setValues, strings.Split(o.SetValues, ",")...)
+	}
+	err = o.installChart(o.ReleaseName, kube.ChartWebhookRecorder, o.Version, o.Namespace, o.HelmUpdate, setValues)
+	if err != nil {
+		return fmt.Errorf("Failed to install chart %s: %s", kube.ChartWebhookRecorder, err)
+	}
+	return o.ExposeAddon(defaultWebhookRecorderName)
+}
+
+// webhookRecorderValues returns the chart values which make the recorder forward the webhooks to the Prow hook,
+// signing the push events translated from CodeCommit with the Prow HMAC token, or to Jenkins if Prow is not used
+func (o *CreateAddonWebhookRecorderOptions) webhookRecorderValues() ([]string, error) {
+	_, _, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return nil, err
+	}
+	prow, err := o.isProw()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find the promotion engine of the team")
+	}
+	args := []string{"controller", "webhook-recorder", "--port", "8080"}
+	forwardURL := o.ForwardURL
+	if prow {
+		if forwardURL == "" {
+			forwardURL = webhookRecorderProwForwardURL
+		}
+		args = append(args, "--forward-url", forwardURL, "--forward-secret-name", kube.SecretProwHMACToken)
+	} else {
+		if forwardURL == "" {
+			forwardURL = webhookRecorderJenkinsForwardURL
+		}
+		args = append(args, "--forward-url", forwardURL)
+	}
+	topicArns := o.TopicArns
+	if len(topicArns) == 0 {
+		accountID, region, err := amazon.GetAccountIDAndRegion("", "")
+		if err != nil {
+			log.Warnf("The CodeCommit webhooks will be rejected by the webhook recorder as the AWS account could not be found to trust their SNS topics. Use --sns-topic-arn to trust them: %s\n", err)
+		} else {
+			topicArns = []string{fmt.Sprintf("arn:aws:sns:%s:%s:%s*", region, accountID, gits.CodeCommitTopicPrefix)}
+		}
+	}
+	for _, topicArn := range topicArns {
+		args = append(args, "--sns-topic-arn", topicArn)
+	}
+	log.Infof("Forwarding the webhooks recorded by the webhook recorder to %s\n", util.ColorInfo(forwardURL))
+	return []string{"jx-webhook-recorder.args={" + strings.Join(args, ",") + "}"}, nil
+}
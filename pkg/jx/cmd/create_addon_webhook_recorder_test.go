@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	gits_test "github.com/jenkins-x/jx/pkg/gits/mocks"
+	helm_test "github.com/jenkins-x/jx/pkg/helm/mocks"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const testCodeCommitTopics = "arn:aws:sns:us-east-1:123456789012:jx-codecommit-*"
+
+func createWebhookRecorderAddonOptions(promotionEngine v1.PromotionEngineType) *CreateAddonWebhookRecorderOptions {
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.PromotionEngine = promotionEngine
+	hmacToken := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kube.SecretProwHMACToken,
+			Namespace: "jx",
+		},
+		Data: map[string][]byte{
+			kube.SecretDataHMAC: []byte("my-hmac"),
+		},
+	}
+	o := &CreateAddonWebhookRecorderOptions{
+		TopicArns: []string{testCodeCommitTopics},
+	}
+	ConfigureTestOptionsWithResources(&o.CommonOptions, []runtime.Object{hmacToken}, []runtime.Object{devEnv},
+		gits_test.NewMockGitter(), helm_test.NewMockHelmer())
+	return o
+}
+
+// parseWebhookRecorderCommand parses the recorder command line the chart values of the addon run the recorder with
+func parseWebhookRecorderCommand(t *testing.T, setValues []string) map[string][]string {
+	require.Len(t, setValues, 1)
+	value := setValues[0]
+	prefix := "jx-webhook-recorder.args={"
+	require.True(t, strings.HasPrefix(value, prefix) && strings.HasSuffix(value, "}"), "unexpected value %s", value)
+	args := strings.Split(strings.TrimSuffix(strings.TrimPrefix(value, prefix), "}"), ",")
+	require.Equal(t, []string{"controller", "webhook-recorder"}, args[:2])
+
+	cmd := NewCmdControllerWebhookRecorder(nil, nil, nil, nil)
+	require.NoError(t, cmd.Flags().Parse(args[2:]))
+	flags := map[string][]string{}
+	for _, name := range []string{"forward-url", "forward-secret", "forward-secret-name"} {
+		flag, err := cmd.Flags().GetString(name)
+		require.NoError(t, err)
+		flags[name] = []string{flag}
+	}
+	topics, err := cmd.Flags().GetStringArray("sns-topic-arn")
+	require.NoError(t, err)
+	flags["sns-topic-arn"] = topics
+	return flags
+}
+
+func TestCreateAddonWebhookRecorderForwardsToProw(t *testing.T) {
+	t.Parallel()
+	o := createWebhookRecorderAddonOptions(v1.PromotionEngineProw)
+
+	setValues, err := o.webhookRecorderValues()
+	require.NoError(t, err)
+	flags := parseWebhookRecorderCommand(t, setValues)
+
+	assert.Equal(t, []string{"http://hook/hook"}, flags["forward-url"])
+	assert.Equal(t, []string{""}, flags["forward-secret"], "the HMAC token should not be in the chart values")
+	assert.Equal(t, []string{testCodeCommitTopics}, flags["sns-topic-arn"])
+
+	forwardSecret, err := webhookRecorderForwardSecret(o.KubeClientCached, "jx", flags["forward-secret-name"][0])
+	require.NoError(t, err)
+	assert.Equal(t, "my-hmac", forwardSecret)
+}
+
+func TestCreateAddonWebhookRecorderForwardsToJenkins(t *testing.T) {
+	t.Parallel()
+	o := createWebhookRecorderAddonOptions(v1.PromotionEngineJenkins)
+
+	setValues, err := o.webhookRecorderValues()
+	require.NoError(t, err)
+	flags := parseWebhookRecorderCommand(t, setValues)
+
+	assert.Equal(t, []string{"http://jenkins:8080/github-webhook/"}, flags["forward-url"])
+	assert.Equal(t, []string{""}, flags["forward-secret-name"])
+	assert.Equal(t, []string{testCodeCommitTopics}, flags["sns-topic-arn"])
+}
@@ -171,6 +171,21 @@ func (o *CreateClusterOptions) initAndInstall(provider string) error {
 	return nil
 }
 
+// validateRequirementsFile validates the requirements file, if there is one, before the cluster is created so that
+// the installation does not fail once the cluster exists
+func (o *CreateClusterOptions) validateRequirementsFile(provider string) error {
+	if o.SkipInstallation || o.InstallOptions.Flags.RequirementsFile == "" {
+		return nil
+	}
+	o.InstallOptions.Flags.Provider = provider
+	err := o.InstallOptions.loadRequirementsFile()
+	if err != nil {
+		return err
+	}
+	o.BatchMode = true
+	return nil
+}
+
 func (o *CreateClusterOptions) Run() error {
 	return o.Cmd.Help()
 }
@@ -132,13 +132,17 @@ func NewCmdCreateClusterAKS(f Factory, in terminal.FileReader, out terminal.File
 }
 
 func (o *CreateClusterAKSOptions) Run() error {
+	err := o.validateRequirementsFile(AKS)
+	if err != nil {
+		return err
+	}
 
 	var deps []string
 	d := binaryShouldBeInstalled("az")
 	if d != "" {
 		deps = append(deps, d)
 	}
-	err := o.installMissingDependencies(deps)
+	err = o.installMissingDependencies(deps)
 	if err != nil {
 		log.Errorf("%v\nPlease fix the error or install manually then try again", err)
 		os.Exit(-1)
@@ -108,13 +108,17 @@ func NewCmdCreateClusterAWS(f Factory, in terminal.FileReader, out terminal.File
 
 // Run runs the command
 func (o *CreateClusterAWSOptions) Run() error {
+	err := o.validateRequirementsFile(AWS)
+	if err != nil {
+		return err
+	}
 	surveyOpts := survey.WithStdio(o.In, o.Out, o.Err)
 	var deps []string
 	d := binaryShouldBeInstalled("kops")
 	if d != "" {
 		deps = append(deps, d)
 	}
-	err := o.installMissingDependencies(deps)
+	err = o.installMissingDependencies(deps)
 	if err != nil {
 		log.Errorf("%v\nPlease fix the error or install manually then try again", err)
 		os.Exit(-1)
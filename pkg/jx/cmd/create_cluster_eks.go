@@ -91,6 +91,10 @@ func NewCmdCreateClusterEKS(f Factory, in terminal.FileReader, out terminal.File
 
 // Run runs the command
 func (o *CreateClusterEKSOptions) Run() error {
+	err := o.validateRequirementsFile(EKS)
+	if err != nil {
+		return err
+	}
 	var deps []string
 	/*
 		d := binaryShouldBeInstalled("aws")
@@ -106,7 +110,7 @@ func (o *CreateClusterEKSOptions) Run() error {
 	if d != "" {
 		deps = append(deps, d)
 	}
-	err := o.installMissingDependencies(deps)
+	err = o.installMissingDependencies(deps)
 	if err != nil {
 		log.Errorf("%v\nPlease fix the error or install manually then try again", err)
 		os.Exit(-1)
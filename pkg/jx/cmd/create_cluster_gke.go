@@ -117,7 +117,11 @@ func NewCmdCreateClusterGKE(f Factory, in terminal.FileReader, out terminal.File
 }
 
 func (o *CreateClusterGKEOptions) Run() error {
-	err := o.installRequirements(GKE)
+	err := o.validateRequirementsFile(GKE)
+	if err != nil {
+		return err
+	}
+	err = o.installRequirements(GKE)
 	if err != nil {
 		return err
 	}
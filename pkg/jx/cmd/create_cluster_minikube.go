@@ -88,13 +88,17 @@ func NewCmdCreateClusterMinikube(f Factory, in terminal.FileReader, out terminal
 }
 
 func (o *CreateClusterMinikubeOptions) Run() error {
+	err := o.validateRequirementsFile(MINIKUBE)
+	if err != nil {
+		return err
+	}
 	var deps []string
 	d := binaryShouldBeInstalled("minikube")
 	if d != "" {
 		deps = append(deps, d)
 	}
 
-	err := o.installMissingDependencies(deps)
+	err = o.installMissingDependencies(deps)
 	if err != nil {
 		log.Errorf("error installing missing dependencies %v, please fix or install manually then try again", err)
 		os.Exit(-1)
@@ -80,6 +80,10 @@ func NewCmdCreateClusterMinishift(f Factory, in terminal.FileReader, out termina
 }
 
 func (o *CreateClusterMinishiftOptions) Run() error {
+	err := o.validateRequirementsFile(MINISHIFT)
+	if err != nil {
+		return err
+	}
 	var deps []string
 	d := binaryShouldBeInstalled("minishift")
 	if d != "" {
@@ -90,7 +94,7 @@ func (o *CreateClusterMinishiftOptions) Run() error {
 		deps = append(deps, d)
 	}
 
-	err := o.installMissingDependencies(deps)
+	err = o.installMissingDependencies(deps)
 	if err != nil {
 		log.Errorf("error installing missing dependencies %v, please fix or install manually then try again", err)
 		os.Exit(-1)
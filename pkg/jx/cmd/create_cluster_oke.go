@@ -124,7 +124,11 @@ func NewCmdCreateClusterOKE(f Factory, in terminal.FileReader, out terminal.File
 }
 
 func (o *CreateClusterOKEOptions) Run() error {
-	err := o.installRequirements(OKE)
+	err := o.validateRequirementsFile(OKE)
+	if err != nil {
+		return err
+	}
+	err = o.installRequirements(OKE)
 	if err != nil {
 		return err
 	}
@@ -109,6 +109,7 @@ func NewCmdCreateEnv(f Factory, in terminal.FileReader, out terminal.FileWriter,
 	options.HelmValuesConfig.AddExposeControllerValues(cmd, false)
 
 	options.addCommonFlags(cmd)
+	options.addAuditFlags(cmd)
 
 	return cmd
 }
@@ -176,6 +177,7 @@ func (o *CreateEnvOptions) Run() error {
 	}
 
 	log.Infof("Created environment %s\n", util.ColorInfo(env.Name))
+	o.auditChange(v1.AuditActionCreate, "Environment", env.Name, nil, &env.Spec)
 
 	err = kube.EnsureEnvironmentNamespaceSetup(kubeClient, jxClient, &env, ns)
 	if err != nil {
@@ -211,6 +213,7 @@ func (o *CreateEnvOptions) Run() error {
 			}
 			gitProvider = p
 		}
+		o.protectBranch(&devEnv.Spec.TeamSettings, gitURL, gitProvider)
 		if o.Prow {
 			config := authConfigSvc.Config()
 			u := gitInfo.HostURL()
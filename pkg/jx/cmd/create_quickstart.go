@@ -11,9 +11,9 @@ import (
 
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/gits"
-	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/quickstarts"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 
@@ -47,18 +47,27 @@ var (
 		jx create quickstart
 
 		jx create quickstart -f http
+
+		# Search the quickstarts of the team by language, description and tags
+		jx create quickstart -f "rest api" -t spring
+
+		# Create a project from a quickstart specifying the values of the parameters in its quickstart.yaml
+		jx create quickstart -f spring-boot-rest --set packageName=com.acme.demo --set database=postgres
 	`)
 )
 
 // CreateQuickstartOptions the options for the create spring command
 type CreateQuickstartOptions struct {
 	CreateProjectOptions
+	QuickstartLoadOptions
+
+	Filter      quickstarts.QuickstartFilter
+	GitProvider gits.GitProvider
+	GitHost     string
+	Parameters  []string
 
-	GitHubOrganisations []string
-	Filter              quickstarts.QuickstartFilter
-	GitProvider         gits.GitProvider
-	GitHost             string
-	IgnoreTeam          bool
+	// downloadedQuickstarts the directories the quickstarts have been downloaded to indexed by their ID
+	downloadedQuickstarts map[string]string
 }
 
 // NewCmdCreateQuickstart creates a command object for the "create" command
@@ -91,7 +100,7 @@ func NewCmdCreateQuickstart(f Factory, in terminal.FileReader, out terminal.File
 	}
 	options.addCreateAppFlags(cmd)
 
-	cmd.Flags().StringArrayVarP(&options.GitHubOrganisations, "organisations", "g", []string{}, "The GitHub organisations to query for quickstarts")
+	options.addQuickstartLoadFlags(cmd)
 	cmd.Flags().StringArrayVarP(&options.Filter.Tags, "tag", "t", []string{}, "The tags on the quickstarts to filter")
 	cmd.Flags().StringVarP(&options.Filter.Owner, "owner", "", "", "The owner to filter on")
 	cmd.Flags().StringVarP(&options.Filter.Language, "language", "l", "", "The language to filter on")
@@ -99,68 +108,21 @@ func NewCmdCreateQuickstart(f Factory, in terminal.FileReader, out terminal.File
 	cmd.Flags().StringVarP(&options.GitHost, "git-host", "", "", "The Git server host if not using GitHub when pushing created project")
 	cmd.Flags().StringVarP(&options.Filter.Text, "filter", "f", "", "The text filter")
 	cmd.Flags().StringVarP(&options.Filter.ProjectName, "project-name", "p", "", "The project name (for use with -b batch mode)")
+	cmd.Flags().StringArrayVarP(&options.Parameters, "set", "", []string{}, "The values of the quickstart parameters in the form name=value")
 	return cmd
 }
 
 // Run implements the generic Create command
 func (o *CreateQuickstartOptions) Run() error {
-	authConfigSvc, err := o.CreateGitAuthConfigService()
+	model, err := o.loadQuickstartModel(&o.QuickstartLoadOptions)
 	if err != nil {
-		return err
-	}
-	config := authConfigSvc.Config()
-
-	var locations []v1.QuickStartLocation
-	if !o.IgnoreTeam {
-		jxClient, ns, err := o.JXClientAndDevNamespace()
-		if err != nil {
-			return err
-		}
-		err = o.registerEnvironmentCRD()
-		if err != nil {
-			return err
-		}
-
-		locations, err = kube.GetQuickstartLocations(jxClient, ns)
-		if err != nil {
-			return err
-		}
-	}
-
-	// lets add any extra github organisations if they are not already configured
-	for _, org := range o.GitHubOrganisations {
-		found := false
-		for _, loc := range locations {
-			if loc.GitURL == gits.GitHubURL && loc.Owner == org {
-				found = true
-				break
-			}
-		}
-		if !found {
-			locations = append(locations, v1.QuickStartLocation{
-				GitURL:   gits.GitHubURL,
-				GitKind:  gits.KindGitHub,
-				Owner:    org,
-				Includes: []string{"*"},
-				Excludes: []string{"WIP-*"},
-			})
-		}
-	}
-
-	gitMap := map[string]map[string]v1.QuickStartLocation{}
-	for _, loc := range locations {
-		m := gitMap[loc.GitURL]
-		if m == nil {
-			m = map[string]v1.QuickStartLocation{}
-			gitMap[loc.GitURL] = m
-		}
-		m[loc.Owner] = loc
+		return fmt.Errorf("failed to load quickstarts: %s", err)
 	}
-
-	model, err := o.LoadQuickstartsFromMap(config, gitMap)
+	o.Filter.Parameters, err = quickstarts.ParseParameterValues(o.Parameters)
 	if err != nil {
-		return fmt.Errorf("failed to load quickstarts: %s", err)
+		return err
 	}
+	model.LoadManifest = o.loadQuickstartManifest
 	q, err := model.CreateSurvey(&o.Filter, o.BatchMode, o.In, o.Out, o.Err)
 	if err != nil {
 		return err
@@ -225,15 +187,62 @@ func (o *CreateQuickstartOptions) Run() error {
 func (o *CreateQuickstartOptions) createQuickstart(f *quickstarts.QuickstartForm, dir string) (string, error) {
 	q := f.Quickstart
 	answer := filepath.Join(dir, f.Name)
+	tmpDir, err := o.downloadQuickstart(q)
+	if err != nil {
+		return answer, err
+	}
+	if q.Manifest != nil {
+		err = q.Manifest.Render(tmpDir, q.Manifest.TemplateData(f.Name, f.Parameters))
+		if err != nil {
+			return answer, errors.Wrapf(err, "failed to render quickstart %s", q.ID)
+		}
+	}
+	err = util.RenameDir(tmpDir, answer, false)
+	if err != nil {
+		return answer, fmt.Errorf("failed to rename temp dir %s to %s: %s", tmpDir, answer, err)
+	}
+	log.Infof("Generated quickstart at %s\n", answer)
+	return answer, nil
+}
+
+// loadQuickstartManifest downloads the quickstart and loads its manifest if it has one
+func (o *CreateQuickstartOptions) loadQuickstartManifest(q *quickstarts.Quickstart) (*quickstarts.QuickstartManifest, error) {
+	dir, err := o.downloadQuickstart(q)
+	if err != nil {
+		return nil, err
+	}
+	return quickstarts.LoadQuickstartManifest(dir)
+}
+
+// downloadQuickstart downloads and unzips the source of the quickstart into a temporary directory. The quickstart
+// is only downloaded once
+func (o *CreateQuickstartOptions) downloadQuickstart(q *quickstarts.Quickstart) (string, error) {
+	if dir := o.downloadedQuickstarts[q.ID]; dir != "" {
+		return dir, nil
+	}
+	dir, err := o.downloadQuickstartSource(q)
+	if err != nil {
+		return "", err
+	}
+	if o.downloadedQuickstarts == nil {
+		o.downloadedQuickstarts = map[string]string{}
+	}
+	o.downloadedQuickstarts[q.ID] = dir
+	return dir, nil
+}
+
+// downloadQuickstartSource downloads and unzips the source of the quickstart into a directory inside a new
+// temporary directory
+func (o *CommonOptions) downloadQuickstartSource(q *quickstarts.Quickstart) (string, error) {
 	u := q.DownloadZipURL
 	if u == "" {
-		return answer, fmt.Errorf("quickstart %s does not have a download zip URL", q.ID)
+		return "", fmt.Errorf("quickstart %s does not have a download zip URL", q.ID)
 	}
 	client := http.Client{}
 
 	req, err := http.NewRequest(http.MethodGet, u, strings.NewReader(""))
 	if err != nil {
-		return answer, err
+		return "", err
 	}
 	userAuth := q.GitProvider.UserAuth()
 	token := userAuth.ApiToken
@@ -244,40 +253,35 @@ func (o *CreateQuickstartOptions) createQuickstart(f *quickstarts.QuickstartForm
 	}
 	res, err := client.Do(req)
 	if err != nil {
-		return answer, err
+		return "", err
 	}
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return answer, err
+		return "", err
 	}
 
-	zipFile := filepath.Join(dir, "source.zip")
-	err = ioutil.WriteFile(zipFile, body, util.DefaultWritePermissions)
+	tmpDir, err := ioutil.TempDir("", "jx-source-")
 	if err != nil {
-		return answer, fmt.Errorf("failed to download file %s due to %s", zipFile, err)
+		return "", fmt.Errorf("failed to create temporary directory: %s", err)
 	}
-	tmpDir, err := ioutil.TempDir("", "jx-source-")
+	zipFile := filepath.Join(tmpDir, "source.zip")
+	err = ioutil.WriteFile(zipFile, body, util.DefaultWritePermissions)
 	if err != nil {
-		return answer, fmt.Errorf("failed to create temporary directory: %s", err)
+		return "", fmt.Errorf("failed to download file %s due to %s", zipFile, err)
 	}
 	err = util.Unzip(zipFile, tmpDir)
 	if err != nil {
-		return answer, fmt.Errorf("failed to unzip new project file %s due to %s", zipFile, err)
+		return "", fmt.Errorf("failed to unzip new project file %s due to %s", zipFile, err)
 	}
 	err = os.Remove(zipFile)
 	if err != nil {
-		return answer, err
+		return "", err
 	}
 	tmpDir, err = findFirstDirectory(tmpDir)
 	if err != nil {
-		return answer, fmt.Errorf("failed to find a directory inside the source download: %s", err)
-	}
-	err = util.RenameDir(tmpDir, answer, false)
-	if err != nil {
-		return answer, fmt.Errorf("failed to rename temp dir %s to %s: %s", tmpDir, answer, err)
+		return "", fmt.Errorf("failed to find a directory inside the source download: %s", err)
 	}
-	log.Infof("Generated quickstart at %s\n", answer)
-	return answer, nil
+	return tmpDir, nil
 }
 
 func findFirstDirectory(dir string) (string, error) {
@@ -295,24 +299,5 @@ func findFirstDirectory(dir string) (string, error) {
 
 // LoadQuickstartsFromMap Load all quickstarts
 func (o *CreateQuickstartOptions) LoadQuickstartsFromMap(config *auth.AuthConfig, gitMap map[string]map[string]v1.QuickStartLocation) (*quickstarts.QuickstartModel, error) {
-	model := quickstarts.NewQuickstartModel()
-
-	for gitURL, m := range gitMap {
-		for _, location := range m {
-			kind := location.GitKind
-			if kind == "" {
-				kind = gits.KindGitHub
-			}
-			gitProvider, err := o.gitProviderForGitServerURL(gitURL, kind)
-			if err != nil {
-				return model, err
-			}
-			o.Debugf("Searching for repositories in Git server %s owner %s includes %s excludes %s as user %s \n", gitProvider.ServerURL(), location.Owner, strings.Join(location.Includes, ", "), strings.Join(location.Excludes, ", "), gitProvider.CurrentUsername())
-			err = model.LoadGithubQuickstarts(gitProvider, location.Owner, location.Includes, location.Excludes)
-			if err != nil {
-				o.Debugf("Quickstart load error: %s\n", err.Error())
-			}
-		}
-	}
-	return model, nil
+	return o.loadQuickstartsFromMap(gitMap, nil)
 }
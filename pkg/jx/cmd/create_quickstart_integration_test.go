@@ -22,7 +22,9 @@ func TestCreateQuickstartProjects(t *testing.T) {
 	appName := "myvets"
 
 	o := &cmd.CreateQuickstartOptions{
-		GitHubOrganisations: []string{"petclinic-gcp"},
+		QuickstartLoadOptions: cmd.QuickstartLoadOptions{
+			GitHubOrganisations: []string{"petclinic-gcp"},
+		},
 		Filter: quickstarts.QuickstartFilter{
 			Text:        "petclinic-gcp/spring-petclinic-vets-service",
 			ProjectName: appName,
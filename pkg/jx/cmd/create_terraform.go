@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"crypto/sha256"
 	"io"
+	"io/ioutil"
 	"strings"
 
 	"fmt"
@@ -21,8 +23,11 @@ import (
 	"path"
 
 	"github.com/Pallinder/go-randomdata"
+	"github.com/jenkins-x/jx/pkg/cloud/aks"
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
 	"github.com/jenkins-x/jx/pkg/cloud/gke"
 	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jenkins"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
@@ -42,6 +47,7 @@ type Cluster interface {
 	SetProvider(string) string
 	Context() string
 	CreateTfVarsFile(path string) error
+	ParseTfVarsFile(path string)
 }
 
 // GKECluster implements Cluster interface for GKE
@@ -202,6 +208,186 @@ func (g *GKECluster) ParseTfVarsFile(path string) {
 	g.AutoUpgrade = b
 }
 
+// EKSCluster implements Cluster interface for EKS
+type EKSCluster struct {
+	name          string
+	provider      string
+	Organisation  string
+	Region        string
+	Profile       string
+	MachineType   string
+	MinNumOfNodes string
+	MaxNumOfNodes string
+	DiskSize      string
+}
+
+// Name Get name
+func (e EKSCluster) Name() string {
+	return e.name
+}
+
+// SetName Sets the name
+func (e *EKSCluster) SetName(name string) string {
+	e.name = name
+	return e.name
+}
+
+// ClusterName get cluster name
+func (e EKSCluster) ClusterName() string {
+	return fmt.Sprintf("%s-%s", e.Organisation, e.name)
+}
+
+// Provider get provider
+func (e EKSCluster) Provider() string {
+	return e.provider
+}
+
+// SetProvider Set the provider
+func (e *EKSCluster) SetProvider(provider string) string {
+	e.provider = provider
+	return e.provider
+}
+
+// Context Get the context which is used as the alias of the cluster in the kube config
+func (e EKSCluster) Context() string {
+	return fmt.Sprintf("%s_%s_%s", e.provider, e.Region, e.ClusterName())
+}
+
+// CreateTfVarsFile create vars
+func (e EKSCluster) CreateTfVarsFile(path string) error {
+	values := []string{
+		"cluster_name", e.ClusterName(),
+		"organisation", e.Organisation,
+		"provider", e.provider,
+		"aws_region", e.Region,
+		"min_node_count", e.MinNumOfNodes,
+		"max_node_count", e.MaxNumOfNodes,
+		"node_machine_type", e.MachineType,
+		"node_disk_size", e.DiskSize,
+	}
+	if e.Profile != "" {
+		values = append(values, "aws_profile", e.Profile)
+	}
+	return writeTfVarsFile(path, values...)
+}
+
+// ParseTfVarsFile Parse vars file
+func (e *EKSCluster) ParseTfVarsFile(path string) {
+	e.Organisation, _ = terraform.ReadValueFromFile(path, "organisation")
+	e.provider, _ = terraform.ReadValueFromFile(path, "provider")
+	e.Region, _ = terraform.ReadValueFromFile(path, "aws_region")
+	e.Profile, _ = terraform.ReadValueFromFile(path, "aws_profile")
+	e.MinNumOfNodes, _ = terraform.ReadValueFromFile(path, "min_node_count")
+	e.MaxNumOfNodes, _ = terraform.ReadValueFromFile(path, "max_node_count")
+	e.MachineType, _ = terraform.ReadValueFromFile(path, "node_machine_type")
+	e.DiskSize, _ = terraform.ReadValueFromFile(path, "node_disk_size")
+}
+
+// AKSCluster implements Cluster interface for AKS
+type AKSCluster struct {
+	name          string
+	provider      string
+	Organisation  string
+	ResourceGroup string
+	Location      string
+	NodeVMSize    string
+	MinNumOfNodes string
+	MaxNumOfNodes string
+	DiskSize      string
+}
+
+// Name Get name
+func (a AKSCluster) Name() string {
+	return a.name
+}
+
+// SetName Sets the name
+func (a *AKSCluster) SetName(name string) string {
+	a.name = name
+	return a.name
+}
+
+// ClusterName get cluster name
+func (a AKSCluster) ClusterName() string {
+	return fmt.Sprintf("%s-%s", a.Organisation, a.name)
+}
+
+// Provider get provider
+func (a AKSCluster) Provider() string {
+	return a.provider
+}
+
+// SetProvider Set the provider
+func (a *AKSCluster) SetProvider(provider string) string {
+	a.provider = provider
+	return a.provider
+}
+
+// Context Get the context which is named after the cluster by az aks get-credentials
+func (a AKSCluster) Context() string {
+	return a.ClusterName()
+}
+
+// CreateTfVarsFile create vars
+func (a AKSCluster) CreateTfVarsFile(path string) error {
+	return writeTfVarsFile(path,
+		"cluster_name", a.ClusterName(),
+		"organisation", a.Organisation,
+		"provider", a.provider,
+		"resource_group", a.ResourceGroup,
+		"location", a.Location,
+		"min_node_count", a.MinNumOfNodes,
+		"max_node_count", a.MaxNumOfNodes,
+		"node_vm_size", a.NodeVMSize,
+		"node_disk_size", a.DiskSize,
+	)
+}
+
+// ParseTfVarsFile Parse vars file
+func (a *AKSCluster) ParseTfVarsFile(path string) {
+	a.Organisation, _ = terraform.ReadValueFromFile(path, "organisation")
+	a.provider, _ = terraform.ReadValueFromFile(path, "provider")
+	a.ResourceGroup, _ = terraform.ReadValueFromFile(path, "resource_group")
+	a.Location, _ = terraform.ReadValueFromFile(path, "location")
+	a.MinNumOfNodes, _ = terraform.ReadValueFromFile(path, "min_node_count")
+	a.MaxNumOfNodes, _ = terraform.ReadValueFromFile(path, "max_node_count")
+	a.NodeVMSize, _ = terraform.ReadValueFromFile(path, "node_vm_size")
+	a.DiskSize, _ = terraform.ReadValueFromFile(path, "node_disk_size")
+}
+
+// writeTfVarsFile writes who created the cluster and then the key value pairs to the vars file
+func writeTfVarsFile(path string, keyValues ...string) error {
+	user, err := os_user.Current()
+	var username string
+	if err != nil {
+		username = "unknown"
+	} else {
+		username = sanitizeLabel(user.Username)
+	}
+	keyValues = append([]string{"created_by", username, "created_timestamp", time.Now().Format("20060102150405")}, keyValues...)
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		err = terraform.WriteKeyValueToFileIfNotExists(path, keyValues[i], keyValues[i+1])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newTerraformCluster creates the Cluster for the Kubernetes provider
+func newTerraformCluster(name string, provider string) (Cluster, error) {
+	switch provider {
+	case GKE:
+		return &GKECluster{name: name, provider: provider}, nil
+	case EKS:
+		return &EKSCluster{name: name, provider: provider}, nil
+	case AKS:
+		return &AKSCluster{name: name, provider: provider}, nil
+	default:
+		return nil, fmt.Errorf("invalid cluster provider type %s, must be one of %v", provider, validTerraformClusterProviders)
+	}
+}
+
 // Flags for a cluster
 type Flags struct {
 	Cluster                     []string
@@ -220,7 +406,21 @@ type Flags struct {
 	GKEAutoRepair               bool
 	GKEAutoUpgrade              bool
 	GKEServiceAccount           string
+	EKSRegion                   string
+	EKSProfile                  string
+	EKSMachineType              string
+	EKSMinNumOfNodes            string
+	EKSMaxNumOfNodes            string
+	EKSDiskSize                 string
+	AKSResourceGroup            string
+	AKSLocation                 string
+	AKSNodeVMSize               string
+	AKSMinNumOfNodes            string
+	AKSMaxNumOfNodes            string
+	AKSDiskSize                 string
 	LocalOrganisationRepository string
+	StateBucket                 string
+	GitOps                      bool
 }
 
 // CreateTerraformOptions the options for the create spring command
@@ -230,10 +430,13 @@ type CreateTerraformOptions struct {
 	Flags                Flags
 	Clusters             []Cluster
 	GitRepositoryOptions gits.GitRepositoryOptions
+
+	// ApplySavedPlans applies the plan saved in the terraform directory of a cluster rather than creating a new plan
+	ApplySavedPlans bool
 }
 
 var (
-	validTerraformClusterProviders = []string{"gke"}
+	validTerraformClusterProviders = []string{GKE, EKS, AKS}
 
 	createTerraformExample = templates.Examples(`
 		jx create terraform
@@ -241,16 +444,75 @@ var (
 		# to specify the clusters via flags
 		jx create terraform -c dev=gke -c stage=gke -c prod=gke
 
+		# to create the clusters on EKS or AKS
+		jx create terraform -c dev=eks -c prod=eks --eks-region us-west-2
+		jx create terraform -c dev=aks -c prod=aks --aks-location westeurope
+
+		# to review the plan on a Pull Request of the organisation repository and apply it once merged
+		jx create terraform -c dev=gke -c prod=gke --gitops
+
 `)
 	validTerraformVersions = "0.11.0"
 
 	gkeBucketConfiguration = `terraform {
   required_version = ">= %s"
   backend "gcs" {
-    bucket      = "%s-%s-terraform-state"
+    bucket      = "%s"
     prefix      = "%s"
   }
 }`
+
+	eksBucketConfiguration = `terraform {
+  required_version = ">= %s"
+  backend "s3" {
+    bucket         = "%s"
+    key            = "%s/terraform.tfstate"
+    region         = "%s"
+    dynamodb_table = "%s"
+    encrypt        = true
+  }
+}`
+
+	aksStorageConfiguration = `terraform {
+  required_version = ">= %s"
+  backend "azurerm" {
+    resource_group_name  = "%s"
+    storage_account_name = "%s"
+    container_name       = "%s"
+    key                  = "%s.terraform.tfstate"
+  }
+}`
+
+	// organisationJenkinsfile the pipeline of an organisation repository created with --gitops which comments the
+	// plans of the changed clusters on Pull Requests and applies the reviewed plans once they are merged
+	organisationJenkinsfile = `pipeline {
+  agent {
+    label "jenkins-terraform"
+  }
+  stages {
+    stage('Plan the changed clusters') {
+      when {
+        branch 'PR-*'
+      }
+      steps {
+        container('terraform') {
+          sh "jx step terraform plan"
+        }
+      }
+    }
+    stage('Apply the reviewed plans') {
+      when {
+        branch 'master'
+      }
+      steps {
+        container('terraform') {
+          sh "jx step terraform apply"
+        }
+      }
+    }
+  }
+}
+`
 )
 
 const (
@@ -258,8 +520,16 @@ const (
 	Clusters = "clusters"
 	// Terraform constant
 	Terraform = "terraform"
+	// TerraformPlanFile the name of the file in the terraform directory of a cluster which the plan is saved to
+	TerraformPlanFile = "jx.tfplan"
 	// TerraformTemplatesGKE constant
 	TerraformTemplatesGKE = "https://github.com/jenkins-x/terraform-jx-templates-gke.git"
+	// TerraformTemplatesEKS constant
+	TerraformTemplatesEKS = "https://github.com/jenkins-x/terraform-jx-templates-eks.git"
+	// TerraformTemplatesAKS constant
+	TerraformTemplatesAKS = "https://github.com/jenkins-x/terraform-jx-templates-aks.git"
+
+	aksTerraformStateContainer = "terraform-state"
 )
 
 // NewCmdCreateTerraform creates a command object for the "create" command
@@ -306,6 +576,8 @@ func (options *CreateTerraformOptions) addFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&options.Flags.IgnoreTerraformWarnings, "ignore-terraform-warnings", "", false, "Ignore any warnings about the Terraform plan being potentially destructive")
 	cmd.Flags().StringVarP(&options.Flags.JxEnvironment, "jx-environment", "", "dev", "The cluster name to install jx inside")
 	cmd.Flags().StringVarP(&options.Flags.LocalOrganisationRepository, "local-organisation-repository", "", "", "Rather than cloning from a remote Git server, the local directory to use for the organisational folder")
+	cmd.Flags().StringVarP(&options.Flags.StateBucket, "state-bucket", "", "", "The GCS or S3 bucket, or the Azure storage account, which stores the Terraform state. Defaults to one named after the organisation")
+	cmd.Flags().BoolVarP(&options.Flags.GitOps, "gitops", "", false, "Create a Pull Request on the organisation repository with the cluster changes rather than applying them. The repository gets a pipeline and is imported into the current team so that the plan is commented on the Pull Request by 'jx step terraform plan' and applied after merge by 'jx step terraform apply'")
 
 	// GKE specific overrides
 	cmd.Flags().StringVarP(&options.Flags.GKEDiskSize, "gke-disk-size", "", "100", "Size in GB for node VM boot disks. Defaults to 100GB")
@@ -317,6 +589,21 @@ func (options *CreateTerraformOptions) addFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&options.Flags.GKEProjectID, "gke-project-id", "", "", "Google Project ID to create cluster in")
 	cmd.Flags().StringVarP(&options.Flags.GKEZone, "gke-zone", "", "", "The compute zone (e.g. us-central1-a) for the cluster")
 
+	// EKS specific overrides
+	cmd.Flags().StringVarP(&options.Flags.EKSRegion, "eks-region", "", "", "The AWS region to create the cluster in. Defaults to the region of the AWS profile")
+	cmd.Flags().StringVarP(&options.Flags.EKSProfile, "eks-profile", "", "", "The AWS profile used to create the cluster")
+	cmd.Flags().StringVarP(&options.Flags.EKSMachineType, "eks-machine-type", "", "", "The EC2 instance type to use for nodes")
+	cmd.Flags().StringVarP(&options.Flags.EKSMinNumOfNodes, "eks-min-num-nodes", "", "", "The minimum number of nodes in the cluster")
+	cmd.Flags().StringVarP(&options.Flags.EKSMaxNumOfNodes, "eks-max-num-nodes", "", "", "The maximum number of nodes in the cluster")
+	cmd.Flags().StringVarP(&options.Flags.EKSDiskSize, "eks-disk-size", "", "100", "Size in GB for node volumes. Defaults to 100GB")
+
+	// AKS specific overrides
+	cmd.Flags().StringVarP(&options.Flags.AKSResourceGroup, "aks-resource-group", "", "", "The Azure resource group of the cluster. Defaults to one named after the cluster")
+	cmd.Flags().StringVarP(&options.Flags.AKSLocation, "aks-location", "", "", "The Azure location (e.g. westeurope) for the cluster")
+	cmd.Flags().StringVarP(&options.Flags.AKSNodeVMSize, "aks-node-vm-size", "", "", "The size of the Azure virtual machines to use for nodes")
+	cmd.Flags().StringVarP(&options.Flags.AKSMinNumOfNodes, "aks-min-num-nodes", "", "", "The minimum number of nodes in the cluster")
+	cmd.Flags().StringVarP(&options.Flags.AKSMaxNumOfNodes, "aks-max-num-nodes", "", "", "The maximum number of nodes in the cluster")
+	cmd.Flags().StringVarP(&options.Flags.AKSDiskSize, "aks-disk-size", "", "100", "Size in GB for node OS disks. Defaults to 100GB")
 }
 
 func stringInValidProviders(a string) bool {
@@ -331,10 +618,6 @@ func stringInValidProviders(a string) bool {
 // Run implements this command
 func (options *CreateTerraformOptions) Run() error {
 	options.InstallOptions.Flags.Prow = true
-	err := options.installRequirements(GKE, "terraform", options.InstallOptions.InitOptions.HelmBinary())
-	if err != nil {
-		return err
-	}
 
 	if len(options.Flags.Cluster) >= 1 {
 		err := options.ValidateClusterDetails()
@@ -350,6 +633,11 @@ func (options *CreateTerraformOptions) Run() error {
 		}
 	}
 
+	err := options.installRequirements("", terraformClusterDependencies(options.Clusters, options.InstallOptions.InitOptions.HelmBinary())...)
+	if err != nil {
+		return err
+	}
+
 	err = options.createOrganisationGitRepo()
 	if err != nil {
 		return err
@@ -357,6 +645,22 @@ func (options *CreateTerraformOptions) Run() error {
 	return nil
 }
 
+// terraformClusterDependencies returns the binaries required to create the clusters with Terraform
+func terraformClusterDependencies(clusters []Cluster, helmBinary string) []string {
+	deps := []string{"terraform", helmBinary}
+	for _, c := range clusters {
+		switch c.Provider() {
+		case GKE:
+			deps = append(deps, "gcloud")
+		case EKS:
+			deps = append(deps, "aws", "heptio-authenticator-aws")
+		case AKS:
+			deps = append(deps, "az")
+		}
+	}
+	return deps
+}
+
 // ClusterDetailsWizard cluster details wizard
 func (options *CreateTerraformOptions) ClusterDetailsWizard() error {
 	surveyOpts := survey.WithStdio(options.In, options.Out, options.Err)
@@ -425,7 +729,10 @@ func (options *CreateTerraformOptions) ClusterDetailsWizard() error {
 				jxEnvironment = name
 			}
 		}
-		c := &GKECluster{name: name, provider: provider}
+		c, err := newTerraformCluster(name, provider)
+		if err != nil {
+			return err
+		}
 
 		options.Clusters = append(options.Clusters, c)
 	}
@@ -446,7 +753,10 @@ func (options *CreateTerraformOptions) ValidateClusterDetails() error {
 			return fmt.Errorf("invalid cluster provider type %s, must be one of %v", p, validTerraformClusterProviders)
 		}
 
-		c := &GKECluster{name: pair[0], provider: pair[1]}
+		c, err := newTerraformCluster(pair[0], pair[1])
+		if err != nil {
+			return err
+		}
 		options.Clusters = append(options.Clusters, c)
 	}
 	return nil
@@ -570,6 +880,14 @@ func (options *CreateTerraformOptions) createOrganisationGitRepo() error {
 		return err
 	}
 
+	if options.Flags.GitOps {
+		err = options.writeOrganisationJenkinsfile(dir)
+		if err != nil {
+			return err
+		}
+		return options.createClustersPullRequest(dir)
+	}
+
 	changes, err := options.commitClusters(dir)
 	if err != nil {
 		return err
@@ -628,22 +946,45 @@ func (options *CreateTerraformOptions) CreateOrganisationFolderStructure(dir str
 
 			switch c.Provider() {
 			case "gke":
-				options.Git().Clone(TerraformTemplatesGKE, path)
+				err = options.Git().Clone(TerraformTemplatesGKE, path)
+				if err != nil {
+					return nil, fmt.Errorf("failed to clone the Terraform templates %s into %s: %v", TerraformTemplatesGKE, path, err)
+				}
 				g := c.(*GKECluster)
 				//g := &GKECluster{}
 
-				err := options.configureGKECluster(g, path)
+				err = options.configureGKECluster(g, path)
 				if err != nil {
 					return nil, err
 				}
 				clusterDefinitions = append(clusterDefinitions, g)
 
 			case "aks":
-				// TODO add aks terraform templates URL
-				return nil, fmt.Errorf("creating an AKS cluster via Terraform is not currently supported")
+				err = options.Git().Clone(TerraformTemplatesAKS, path)
+				if err != nil {
+					return nil, fmt.Errorf("failed to clone the Terraform templates %s into %s: %v", TerraformTemplatesAKS, path, err)
+				}
+				a := c.(*AKSCluster)
+
+				err = options.configureAKSCluster(a, path)
+				if err != nil {
+					return nil, err
+				}
+				clusterDefinitions = append(clusterDefinitions, a)
+
 			case "eks":
-				// TODO add eks terraform templates URL
-				return nil, fmt.Errorf("creating an EKS cluster via Terraform is not currently supported")
+				err = options.Git().Clone(TerraformTemplatesEKS, path)
+				if err != nil {
+					return nil, fmt.Errorf("failed to clone the Terraform templates %s into %s: %v", TerraformTemplatesEKS, path, err)
+				}
+				e := c.(*EKSCluster)
+
+				err = options.configureEKSCluster(e, path)
+				if err != nil {
+					return nil, err
+				}
+				clusterDefinitions = append(clusterDefinitions, e)
+
 			default:
 				return nil, fmt.Errorf("unknown Kubernetes provider type %s must be one of %v", c.Provider(), validTerraformClusterProviders)
 			}
@@ -653,25 +994,11 @@ func (options *CreateTerraformOptions) CreateOrganisationFolderStructure(dir str
 			// if the directory already exists, try to load its config
 			options.Debugf("cluster %s already exists, loading...", c.Name())
 
-			switch c.Provider() {
-			case "gke":
-				//g := &GKECluster{}
-				g := c.(*GKECluster)
-				terraformVars := filepath.Join(path, "terraform.tfvars")
-				fmt.Fprintf(options.Out, "loading config from %s\n", util.ColorInfo(terraformVars))
-
-				g.ParseTfVarsFile(terraformVars)
-				clusterDefinitions = append(clusterDefinitions, g)
+			terraformVars := filepath.Join(path, "terraform.tfvars")
+			fmt.Fprintf(options.Out, "loading config from %s\n", util.ColorInfo(terraformVars))
 
-			case "aks":
-				// TODO add aks terraform templates URL
-				return nil, fmt.Errorf("creating an AKS cluster via Terraform is not currently supported")
-			case "eks":
-				// TODO add eks terraform templates URL
-				return nil, fmt.Errorf("creating an EKS cluster via Terraform is not currently supported")
-			default:
-				return nil, fmt.Errorf("unknown Kubernetes provider type %s must be one of %v", c.Provider(), validTerraformClusterProviders)
-			}
+			c.ParseTfVarsFile(terraformVars)
+			clusterDefinitions = append(clusterDefinitions, c)
 		}
 	}
 
@@ -681,22 +1008,31 @@ func (options *CreateTerraformOptions) CreateOrganisationFolderStructure(dir str
 func (options *CreateTerraformOptions) createClusters(dir string, clusterDefinitions []Cluster) error {
 	fmt.Printf("Creating/Updating %v clusters\n", util.ColorInfo(len(clusterDefinitions)))
 	for _, c := range clusterDefinitions {
-		switch v := c.(type) {
-		case *GKECluster:
-			path := filepath.Join(dir, Clusters, v.Name(), Terraform)
-			fmt.Fprintf(options.Out, "\n\nCreating/Updating cluster %s\n", util.ColorInfo(c.Name()))
-			err := options.applyTerraformGKE(v, path)
-			if err != nil {
-				return err
-			}
-		default:
-			return fmt.Errorf("unknown Kubernetes provider type, must be one of %v, got %s", validTerraformClusterProviders, v)
+		path := filepath.Join(dir, Clusters, c.Name(), Terraform)
+		fmt.Fprintf(options.Out, "\n\nCreating/Updating cluster %s\n", util.ColorInfo(c.Name()))
+		err := options.applyTerraformCluster(c, path)
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// applyTerraformCluster applies the Terraform plan in the path to the cluster
+func (options *CreateTerraformOptions) applyTerraformCluster(c Cluster, path string) error {
+	switch v := c.(type) {
+	case *GKECluster:
+		return options.applyTerraformGKE(v, path)
+	case *EKSCluster:
+		return options.applyTerraformEKS(v, path)
+	case *AKSCluster:
+		return options.applyTerraformAKS(v, path)
+	default:
+		return fmt.Errorf("unknown Kubernetes provider type, must be one of %v, got %s", validTerraformClusterProviders, c.Provider())
+	}
+}
+
 func (options *CreateTerraformOptions) findDevCluster(clusterDefinitions []Cluster) (Cluster, error) {
 	for _, c := range clusterDefinitions {
 		if c.Name() == options.Flags.JxEnvironment {
@@ -715,7 +1051,7 @@ func (options *CreateTerraformOptions) writeGitIgnoreFile(dir string) error {
 		}
 		defer file.Close()
 
-		_, err = file.WriteString("**/*.key.json\n.terraform\n**/*.tfstate\njx\n")
+		_, err = file.WriteString("**/*.key.json\n.terraform\n**/*.tfstate\n**/*.tfplan\njx\n")
 		if err != nil {
 			return err
 		}
@@ -723,6 +1059,34 @@ func (options *CreateTerraformOptions) writeGitIgnoreFile(dir string) error {
 	return nil
 }
 
+// writeOrganisationJenkinsfile writes the pipeline which plans and applies the clusters of the organisation
+// repository unless the repository already has one
+func (options *CreateTerraformOptions) writeOrganisationJenkinsfile(dir string) error {
+	jenkinsfile := filepath.Join(dir, jenkins.DefaultJenkinsfile)
+	exists, err := util.FileExists(jenkinsfile)
+	if err != nil || exists {
+		return err
+	}
+	return ioutil.WriteFile(jenkinsfile, []byte(organisationJenkinsfile), DefaultWritePermissions)
+}
+
+// importOrganisationRepository imports the organisation repository into the current team so that its pipeline runs
+// 'jx step terraform plan' on Pull Requests and 'jx step terraform apply' once they are merged
+func (options *CreateTerraformOptions) importOrganisationRepository(dir string, gitInfo *gits.GitRepositoryInfo) error {
+	importOptions := &ImportOptions{
+		CommonOptions: options.CommonOptions,
+		Dir:           dir,
+		RepoURL:       gitInfo.URL,
+		Organisation:  gitInfo.Organisation,
+		Repository:    gitInfo.Name,
+		AppName:       gitInfo.Name,
+		DisableDraft:  true,
+		Jenkinsfile:   jenkins.DefaultJenkinsfile,
+	}
+	importOptions.BatchMode = true
+	return importOptions.Run()
+}
+
 func (options *CreateTerraformOptions) commitClusters(dir string) (bool, error) {
 	err := options.Git().Add(dir, "*")
 	if err != nil {
@@ -738,6 +1102,65 @@ func (options *CreateTerraformOptions) commitClusters(dir string) (bool, error)
 	return false, nil
 }
 
+// createClustersPullRequest creates a Pull Request with the cluster changes on the organisation repository so that the
+// plan can be reviewed before it is applied by the pipeline of the repository
+func (options *CreateTerraformOptions) createClustersPullRequest(dir string) error {
+	branch := fmt.Sprintf("clusters-%s", time.Now().Format("20060102150405"))
+	err := options.Git().CreateBranch(dir, branch)
+	if err != nil {
+		return err
+	}
+	err = options.Git().Checkout(dir, branch)
+	if err != nil {
+		return err
+	}
+	changes, err := options.commitClusters(dir)
+	if err != nil {
+		return err
+	}
+	if !changes {
+		fmt.Fprintf(options.Out, "No changes to the clusters of the organisation\n")
+		return options.Git().Checkout(dir, "master")
+	}
+	err = options.Git().ForcePushBranch(dir, branch, branch)
+	if err != nil {
+		return err
+	}
+
+	gitInfo, err := options.Git().Info(dir)
+	if err != nil {
+		return err
+	}
+	provider, err := options.gitProviderForURL(gitInfo.URL, "user name to create the Pull Request")
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for _, c := range options.Clusters {
+		names = append(names, c.Name())
+	}
+	pr, err := provider.CreatePullRequest(&gits.GitPullRequestArguments{
+		GitRepositoryInfo: gitInfo,
+		Title:             fmt.Sprintf("Add organisation clusters %s", strings.Join(names, ", ")),
+		Body:              "The Terraform plan of the clusters is commented by `jx step terraform plan` and applied by `jx step terraform apply` once this Pull Request is merged",
+		Base:              "master",
+		Head:              branch,
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("Created Pull Request: %s\n", util.ColorInfo(pr.URL))
+
+	err = options.importOrganisationRepository(dir, gitInfo)
+	if err != nil {
+		log.Warnf("Failed to import the organisation repository so the plans will not be commented or applied: %s\n", err)
+		log.Warnf("Import it into a team of an existing Jenkins X installation with: %s\n", util.ColorInfo("jx import --url "+gitInfo.URL+" --no-draft"))
+	} else {
+		log.Infof("The clusters are created when the Pull Request is merged, then you can install Jenkins X with: %s\n", util.ColorInfo("jx install"))
+	}
+	return options.Git().Checkout(dir, "master")
+}
+
 func (options *CreateTerraformOptions) configureGKECluster(g *GKECluster, path string) error {
 	surveyOpts := survey.WithStdio(options.In, options.Out, options.Err)
 	g.DiskSize = options.Flags.GKEDiskSize
@@ -853,9 +1276,18 @@ func (options *CreateTerraformOptions) configureGKECluster(g *GKECluster, path s
 		return err
 	}
 
-	storageBucket := fmt.Sprintf(gkeBucketConfiguration, validTerraformVersions, g.ProjectID, options.Flags.OrganisationName, g.Name())
+	bucketName := options.Flags.StateBucket
+	if bucketName == "" {
+		bucketName = fmt.Sprintf("%s-%s-terraform-state", g.ProjectID, g.Organisation)
+	}
+	storageBucket := fmt.Sprintf(gkeBucketConfiguration, validTerraformVersions, bucketName, g.Name())
 	options.Debugf("Using bucket configuration %s", storageBucket)
 
+	return writeTerraformBackendFile(path, storageBucket)
+}
+
+// writeTerraformBackendFile writes the configuration of where the Terraform state is stored unless it already exists
+func writeTerraformBackendFile(path string, configuration string) error {
 	terraformTf := filepath.Join(path, "terraform.tf")
 	// file exists
 	if _, err := os.Stat(terraformTf); os.IsNotExist(err) {
@@ -865,7 +1297,7 @@ func (options *CreateTerraformOptions) configureGKECluster(g *GKECluster, path s
 		}
 		defer file.Close()
 
-		_, err = file.WriteString(storageBucket)
+		_, err = file.WriteString(configuration)
 		if err != nil {
 			return err
 		}
@@ -876,40 +1308,253 @@ func (options *CreateTerraformOptions) configureGKECluster(g *GKECluster, path s
 	return nil
 }
 
-func (options *CreateTerraformOptions) applyTerraformGKE(g *GKECluster, path string) error {
+func (options *CreateTerraformOptions) configureEKSCluster(e *EKSCluster, path string) error {
 	surveyOpts := survey.WithStdio(options.In, options.Out, options.Err)
-	if g.ProjectID == "" {
-		return errors.New("Unable to apply terraform, projectId has not been set")
+	e.Region = options.Flags.EKSRegion
+	e.Profile = options.Flags.EKSProfile
+	e.MachineType = options.Flags.EKSMachineType
+	e.MinNumOfNodes = options.Flags.EKSMinNumOfNodes
+	e.MaxNumOfNodes = options.Flags.EKSMaxNumOfNodes
+	e.DiskSize = options.Flags.EKSDiskSize
+	e.Organisation = options.Flags.OrganisationName
+
+	if e.Region == "" {
+		defaultRegion, err := amazon.ResolveRegion(e.Profile, "")
+		if err != nil {
+			return err
+		}
+		prompt := &survey.Input{
+			Message: "AWS Region:",
+			Default: defaultRegion,
+			Help:    "The AWS region (e.g. us-west-2) which supports EKS to create the cluster in",
+		}
+
+		err = survey.AskOne(prompt, &e.Region, survey.Required, surveyOpts)
+		if err != nil {
+			return err
+		}
 	}
 
-	log.Info("Applying Terraform changes\n")
+	if e.MachineType == "" {
+		prompt := &survey.Input{
+			Message: "AWS EC2 Instance Type:",
+			Default: "m5.large",
+			Help:    "We recommend a minimum of m5.large for Jenkins X,  a table of instance types can be found here https://aws.amazon.com/ec2/instance-types/",
+		}
+
+		err := survey.AskOne(prompt, &e.MachineType, survey.Required, surveyOpts)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := options.askNodeCounts(&e.MinNumOfNodes, &e.MaxNumOfNodes)
+	if err != nil {
+		return err
+	}
 
 	terraformVars := filepath.Join(path, "terraform.tfvars")
+	err = e.CreateTfVarsFile(terraformVars)
+	if err != nil {
+		return err
+	}
+
+	bucketName := options.Flags.StateBucket
+	if bucketName == "" {
+		accountID, _, err := amazon.GetAccountIDAndRegion(e.Profile, e.Region)
+		if err != nil {
+			return err
+		}
+		bucketName = strings.ToLower(fmt.Sprintf("%s-%s-terraform-state", accountID, e.Organisation))
+	}
+	err = amazon.ValidateS3BucketName(bucketName)
+	if err != nil {
+		return fmt.Errorf("%v, use --state-bucket to specify a valid bucket", err)
+	}
+	storageBucket := fmt.Sprintf(eksBucketConfiguration, validTerraformVersions, bucketName, e.Name(), e.Region, bucketName+"-lock")
+	options.Debugf("Using bucket configuration %s", storageBucket)
+
+	return writeTerraformBackendFile(path, storageBucket)
+}
+
+func (options *CreateTerraformOptions) configureAKSCluster(a *AKSCluster, path string) error {
+	surveyOpts := survey.WithStdio(options.In, options.Out, options.Err)
+	a.ResourceGroup = options.Flags.AKSResourceGroup
+	a.Location = options.Flags.AKSLocation
+	a.NodeVMSize = options.Flags.AKSNodeVMSize
+	a.MinNumOfNodes = options.Flags.AKSMinNumOfNodes
+	a.MaxNumOfNodes = options.Flags.AKSMaxNumOfNodes
+	a.DiskSize = options.Flags.AKSDiskSize
+	a.Organisation = options.Flags.OrganisationName
+
+	if a.ResourceGroup == "" {
+		a.ResourceGroup = fmt.Sprintf("jx-%s", a.ClusterName())
+	}
+
+	if a.Location == "" {
+		prompts := &survey.Select{
+			Message:  "Azure Location:",
+			Options:  aks.GetResourceGroupLocation(),
+			PageSize: 10,
+			Help:     "The Azure location (e.g. westeurope) for the cluster",
+		}
+
+		err := survey.AskOne(prompts, &a.Location, nil, surveyOpts)
+		if err != nil {
+			return err
+		}
+	}
+
+	if a.NodeVMSize == "" {
+		prompts := &survey.Select{
+			Message:  "Azure Virtual Machine Size:",
+			Options:  aks.GetSizes(),
+			Help:     "We recommend a minimum of Standard_D2s_v3 for Jenkins X,  a table of sizes can be found here https://docs.microsoft.com/en-us/azure/virtual-machines/linux/sizes",
+			PageSize: 10,
+			Default:  "Standard_D2s_v3",
+		}
+
+		err := survey.AskOne(prompts, &a.NodeVMSize, nil, surveyOpts)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := options.askNodeCounts(&a.MinNumOfNodes, &a.MaxNumOfNodes)
+	if err != nil {
+		return err
+	}
+
+	terraformVars := filepath.Join(path, "terraform.tfvars")
+	err = a.CreateTfVarsFile(terraformVars)
+	if err != nil {
+		return err
+	}
+
+	resourceGroup, account := aksStateStorage(a.Organisation)
+	if options.Flags.StateBucket != "" {
+		account = options.Flags.StateBucket
+	}
+	storage := fmt.Sprintf(aksStorageConfiguration, validTerraformVersions, resourceGroup, account, aksTerraformStateContainer, a.Name())
+	options.Debugf("Using storage configuration %s", storage)
+
+	return writeTerraformBackendFile(path, storage)
+}
+
+// askNodeCounts asks for the minimum and maximum number of nodes of a cluster if they have not been specified
+func (options *CreateTerraformOptions) askNodeCounts(minNumOfNodes *string, maxNumOfNodes *string) error {
+	surveyOpts := survey.WithStdio(options.In, options.Out, options.Err)
+	if *minNumOfNodes == "" {
+		prompt := &survey.Input{
+			Message: "Minimum number of Nodes",
+			Default: "3",
+			Help:    "We recommend a minimum of 3 for Jenkins X,  the minimum number of nodes in the cluster",
+		}
+
+		err := survey.AskOne(prompt, minNumOfNodes, nil, surveyOpts)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *maxNumOfNodes == "" {
+		prompt := &survey.Input{
+			Message: "Maximum number of Nodes",
+			Default: "5",
+			Help:    "We recommend at least 5 for Jenkins X,  the maximum number of nodes in the cluster",
+		}
+
+		err := survey.AskOne(prompt, maxNumOfNodes, nil, surveyOpts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aksStateStorage returns the resource group and storage account which store the Terraform state of the organisation.
+// Storage account names must be at most 24 lower case letters and numbers so long names are truncated and suffixed
+// with a hash of the full name to keep the accounts of different organisations apart
+func aksStateStorage(organisation string) (string, string) {
+	account := "jx"
+	for _, r := range strings.ToLower(organisation) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			account += string(r)
+		}
+	}
+	account += "tfstate"
+	if len(account) > 24 {
+		hash := fmt.Sprintf("%x", sha256.Sum256([]byte(account)))
+		account = account[:16] + hash[:8]
+	}
+	return fmt.Sprintf("jx-%s-terraform-state", organisation), account
+}
+
+func (options *CreateTerraformOptions) applyTerraformGKE(g *GKECluster, path string) error {
+	log.Info("Applying Terraform changes\n")
+
+	serviceAccountPath, err := options.prepareTerraformGKE(g, path)
+	if err != nil {
+		return err
+	}
+
+	applied, err := options.initPlanAndApplyTerraform(path, serviceAccountPath)
+	if err != nil || !applied {
+		return err
+	}
+
+	output, err := options.getCommandOutput("", "gcloud", "container", "clusters", "get-credentials", g.ClusterName(), "--zone", g.Zone, "--project", g.ProjectID)
+	if err != nil {
+		return err
+	}
+	log.Info(output)
+	return nil
+}
+
+// prepareTerraformCluster logs in and creates the storage of the Terraform state of the cluster so that its plan
+// can be initialised, returning the service account used by GKE plans
+func (options *CreateTerraformOptions) prepareTerraformCluster(c Cluster, path string) (string, error) {
+	switch v := c.(type) {
+	case *GKECluster:
+		return options.prepareTerraformGKE(v, path)
+	case *EKSCluster:
+		return "", options.prepareTerraformEKS(v, path)
+	case *AKSCluster:
+		return "", options.prepareTerraformAKS(v, path)
+	default:
+		return "", fmt.Errorf("unknown Kubernetes provider type, must be one of %v, got %s", validTerraformClusterProviders, c.Provider())
+	}
+}
+
+func (options *CreateTerraformOptions) prepareTerraformGKE(g *GKECluster, path string) (string, error) {
+	if g.ProjectID == "" {
+		return "", errors.New("Unable to apply terraform, projectId has not been set")
+	}
 
 	if g.ServiceAccount == "" {
 		if options.Flags.GKEServiceAccount != "" {
 			g.ServiceAccount = options.Flags.GKEServiceAccount
 			err := gke.Login(g.ServiceAccount, false)
 			if err != nil {
-				return err
+				return "", err
 			}
 
 			options.Debugf("attempting to enable apis")
 			err = gke.EnableApis(g.ProjectID, "iam", "compute", "container")
 			if err != nil {
-				return err
+				return "", err
 			}
 		}
 	}
 
 	var serviceAccountPath string
 	if g.ServiceAccount == "" {
-		serviceAccountName := fmt.Sprintf("jx-%s-%s", options.Flags.OrganisationName, g.Name())
+		serviceAccountName := fmt.Sprintf("jx-%s-%s", g.Organisation, g.Name())
 		fmt.Fprintf(options.Out, "No GCP service account provided, creating %s\n", util.ColorInfo(serviceAccountName))
 
 		_, err := gke.GetOrCreateServiceAccount(serviceAccountName, g.ProjectID, filepath.Dir(path))
 		if err != nil {
-			return err
+			return "", err
 		}
 		serviceAccountPath = filepath.Join(filepath.Dir(path), fmt.Sprintf("%s.key.json", serviceAccountName))
 		fmt.Fprintf(options.Out, "Created GCP service account: %s\n", util.ColorInfo(serviceAccountPath))
@@ -919,31 +1564,159 @@ func (options *CreateTerraformOptions) applyTerraformGKE(g *GKECluster, path str
 	}
 
 	// create the bucket
-	bucketName := fmt.Sprintf("%s-%s-terraform-state", g.ProjectID, options.Flags.OrganisationName)
+	bucketName, err := readTerraformBackendValue(path, "bucket")
+	if err != nil {
+		return "", err
+	}
 	exists, err := gke.BucketExists(g.ProjectID, bucketName)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	if !exists {
 		err = gke.CreateBucket(g.ProjectID, bucketName, g.Region())
 		if err != nil {
-			return err
+			return "", err
 		}
 		fmt.Fprintf(options.Out, "Created GCS bucket: %s in region %s\n", util.ColorInfo(bucketName), util.ColorInfo(g.Region()))
 	}
+	return serviceAccountPath, nil
+}
 
-	err = terraform.Init(path, serviceAccountPath)
+func (options *CreateTerraformOptions) prepareTerraformEKS(e *EKSCluster, path string) error {
+	if e.Region == "" {
+		return errors.New("Unable to apply terraform, the AWS region has not been set")
+	}
+
+	if e.Profile != "" {
+		os.Setenv("AWS_PROFILE", e.Profile)
+	}
+
+	// create the bucket
+	bucketName, err := readTerraformBackendValue(path, "bucket")
+	if err != nil {
+		return err
+	}
+	exists, err := amazon.S3BucketExists(bucketName, e.Profile, e.Region)
 	if err != nil {
 		return err
 	}
 
-	plan, err := terraform.Plan(path, terraformVars, serviceAccountPath)
+	if !exists {
+		_, err = amazon.CreateS3Bucket(bucketName, e.Profile, e.Region)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(options.Out, "Created S3 bucket: %s in region %s\n", util.ColorInfo(bucketName), util.ColorInfo(e.Region))
+	}
+
+	// create the table which locks the state
+	tableName, err := readTerraformBackendValue(path, "dynamodb_table")
+	if err != nil {
+		return err
+	}
+	exists, err = amazon.DynamoDBTableExists(tableName, e.Profile, e.Region)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		err = amazon.CreateTerraformLockTable(tableName, e.Profile, e.Region)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(options.Out, "Created DynamoDB lock table: %s in region %s\n", util.ColorInfo(tableName), util.ColorInfo(e.Region))
+	}
+	return nil
+}
+
+func (options *CreateTerraformOptions) prepareTerraformAKS(a *AKSCluster, path string) error {
+	if a.Location == "" {
+		return errors.New("Unable to apply terraform, the Azure location has not been set")
+	}
+
+	// create the storage account
+	resourceGroup, err := readTerraformBackendValue(path, "resource_group_name")
+	if err != nil {
+		return err
+	}
+	account, err := readTerraformBackendValue(path, "storage_account_name")
+	if err != nil {
+		return err
+	}
+	container, err := readTerraformBackendValue(path, "container_name")
+	if err != nil {
+		return err
+	}
+	exists, err := aks.StorageAccountExists(account)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(options.Out, plan)
+	if !exists {
+		err = aks.CreateResourceGroup(resourceGroup, a.Location)
+		if err != nil {
+			return err
+		}
+		err = aks.CreateStorageAccount(account, container, resourceGroup, a.Location)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(options.Out, "Created Azure storage account: %s in location %s\n", util.ColorInfo(account), util.ColorInfo(a.Location))
+	}
+
+	key, err := aks.GetStorageAccountKey(account, resourceGroup)
+	if err != nil {
+		return err
+	}
+	os.Setenv("ARM_ACCESS_KEY", key)
+	return nil
+}
+
+// readTerraformBackendValue reads a setting of the backend which stores the Terraform state of the cluster
+func readTerraformBackendValue(path string, key string) (string, error) {
+	terraformTf := filepath.Join(path, "terraform.tf")
+	value, err := terraform.ReadValueFromFile(terraformTf, key)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return "", fmt.Errorf("no %s in the backend configuration of %s", key, terraformTf)
+	}
+	return value, nil
+}
+
+// initPlanAndApplyTerraform shows the Terraform plan in the path and applies it once confirmed returning true if
+// the plan was applied. The plan is saved so that exactly the plan which was shown is applied
+func (options *CreateTerraformOptions) initPlanAndApplyTerraform(path string, serviceAccountPath string) (bool, error) {
+	surveyOpts := survey.WithStdio(options.In, options.Out, options.Err)
+	terraformVars := filepath.Join(path, "terraform.tfvars")
+	planFile := filepath.Join(path, TerraformPlanFile)
+
+	err := terraform.Init(path, serviceAccountPath)
+	if err != nil {
+		return false, err
+	}
+
+	var plan string
+	savedPlan := false
+	if options.ApplySavedPlans {
+		savedPlan, err = util.FileExists(planFile)
+		if err != nil {
+			return false, err
+		}
+	}
+	if savedPlan {
+		plan, err = terraform.ShowPlan(planFile)
+	} else {
+		plan, err = terraform.Plan(path, terraformVars, serviceAccountPath, planFile)
+	}
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(planFile)
+
+	fmt.Fprintf(options.Out, "%s", plan)
 
 	if !options.BatchMode {
 		confirm := false
@@ -954,7 +1727,7 @@ func (options *CreateTerraformOptions) applyTerraformGKE(g *GKECluster, path str
 
 		if !confirm {
 			// exit at this point
-			return nil
+			return false, nil
 		}
 	}
 
@@ -962,26 +1735,67 @@ func (options *CreateTerraformOptions) applyTerraformGKE(g *GKECluster, path str
 		if strings.Contains(plan, "forces new resource") {
 			fmt.Fprintf(options.Out, "%s\n", util.ColorError("It looks like this plan is destructive, aborting."))
 			fmt.Fprintf(options.Out, "Use --ignore-terraform-warnings to override\n")
-			return errors.New("aborting destructive plan")
+			return false, errors.New("aborting destructive plan")
 		}
 	}
 
-	if !options.Flags.SkipTerraformApply {
-		log.Info("Applying plan...\n")
+	if options.Flags.SkipTerraformApply {
+		fmt.Fprintf(options.Out, "Skipping Terraform apply\n")
+		return false, nil
+	}
 
-		err = terraform.Apply(path, terraformVars, serviceAccountPath, options.Out, options.Err)
-		if err != nil {
-			return err
-		}
+	log.Info("Applying plan...\n")
 
-		output, err := options.getCommandOutput("", "gcloud", "container", "clusters", "get-credentials", g.ClusterName(), "--zone", g.Zone, "--project", g.ProjectID)
-		if err != nil {
-			return err
-		}
-		log.Info(output)
-	} else {
-		fmt.Fprintf(options.Out, "Skipping Terraform apply\n")
+	err = terraform.ApplyPlan(planFile, options.Out, options.Err)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (options *CreateTerraformOptions) applyTerraformEKS(e *EKSCluster, path string) error {
+	log.Info("Applying Terraform changes\n")
+
+	err := options.prepareTerraformEKS(e, path)
+	if err != nil {
+		return err
+	}
+
+	applied, err := options.initPlanAndApplyTerraform(path, "")
+	if err != nil || !applied {
+		return err
+	}
+
+	args := []string{"eks", "update-kubeconfig", "--name", e.ClusterName(), "--region", e.Region, "--alias", e.Context()}
+	if e.Profile != "" {
+		args = append(args, "--profile", e.Profile)
+	}
+	output, err := options.getCommandOutput("", "aws", args...)
+	if err != nil {
+		return err
+	}
+	log.Info(output)
+	return nil
+}
+
+func (options *CreateTerraformOptions) applyTerraformAKS(a *AKSCluster, path string) error {
+	log.Info("Applying Terraform changes\n")
+
+	err := options.prepareTerraformAKS(a, path)
+	if err != nil {
+		return err
+	}
+
+	applied, err := options.initPlanAndApplyTerraform(path, "")
+	if err != nil || !applied {
+		return err
+	}
+
+	output, err := options.getCommandOutput("", "az", "aks", "get-credentials", "--resource-group", a.ResourceGroup, "--name", a.ClusterName(), "--overwrite-existing")
+	if err != nil {
+		return err
 	}
+	log.Info(output)
 	return nil
 }
 
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAKSStateStorage(t *testing.T) {
+	t.Parallel()
+	resourceGroup, account := aksStateStorage("Acme")
+	assert.Equal(t, "jx-Acme-terraform-state", resourceGroup)
+	assert.Equal(t, "jxacmetfstate", account)
+
+	_, long1 := aksStateStorage("a-very-long-organisation-one")
+	_, long2 := aksStateStorage("a-very-long-organisation-two")
+	assert.Len(t, long1, 24)
+	assert.Len(t, long2, 24)
+	assert.True(t, strings.HasPrefix(long1, "jxaverylongorgan"))
+	assert.NotEqual(t, long1, long2)
+}
+
+func TestWriteOrganisationJenkinsfile(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-organisation-jenkinsfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	options := &CreateTerraformOptions{}
+	err = options.writeOrganisationJenkinsfile(dir)
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "Jenkinsfile"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "jx step terraform plan")
+	assert.Contains(t, string(data), "jx step terraform apply")
+
+	// an existing pipeline is kept
+	err = ioutil.WriteFile(filepath.Join(dir, "Jenkinsfile"), []byte("custom"), DefaultWritePermissions)
+	require.NoError(t, err)
+	err = options.writeOrganisationJenkinsfile(dir)
+	require.NoError(t, err)
+	data, err = ioutil.ReadFile(filepath.Join(dir, "Jenkinsfile"))
+	require.NoError(t, err)
+	assert.Equal(t, "custom", string(data))
+}
@@ -1,10 +1,14 @@
 package cmd_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/jenkins-x/jx/pkg/jx/cmd"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSetName(t *testing.T) {
@@ -33,8 +37,79 @@ func TestValidateClusterDetails(t *testing.T) {
 func TestValidateClusterDetailsFail(t *testing.T) {
 	t.Parallel()
 	o := cmd.CreateTerraformOptions{
-		Flags: cmd.Flags{Cluster: []string{"foo=gke", "bar=aks"}},
+		Flags: cmd.Flags{Cluster: []string{"foo=gke", "bar=minikube"}},
 	}
 	err := o.ValidateClusterDetails()
 	assert.Error(t, err)
 }
+
+func TestValidateClusterDetailsProviders(t *testing.T) {
+	t.Parallel()
+	o := cmd.CreateTerraformOptions{
+		Flags: cmd.Flags{Cluster: []string{"dev=gke", "staging=eks", "prod=aks"}},
+	}
+	err := o.ValidateClusterDetails()
+	require.NoError(t, err)
+	require.Len(t, o.Clusters, 3)
+	assert.IsType(t, &cmd.GKECluster{}, o.Clusters[0])
+	assert.IsType(t, &cmd.EKSCluster{}, o.Clusters[1])
+	assert.IsType(t, &cmd.AKSCluster{}, o.Clusters[2])
+	assert.Equal(t, "staging", o.Clusters[1].Name())
+	assert.Equal(t, "aks", o.Clusters[2].Provider())
+}
+
+func TestEKSClusterTfVarsFile(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-terraform-eks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := &cmd.EKSCluster{
+		Organisation:  "acme",
+		Region:        "eu-west-1",
+		MachineType:   "m5.large",
+		MinNumOfNodes: "3",
+		MaxNumOfNodes: "5",
+		DiskSize:      "100",
+	}
+	c.SetName("dev")
+	c.SetProvider("eks")
+	assert.Equal(t, "acme-dev", c.ClusterName())
+	assert.Equal(t, "eks_eu-west-1_acme-dev", c.Context())
+
+	path := filepath.Join(dir, "terraform.tfvars")
+	require.NoError(t, c.CreateTfVarsFile(path))
+
+	parsed := &cmd.EKSCluster{}
+	parsed.SetName("dev")
+	parsed.ParseTfVarsFile(path)
+	assert.Equal(t, c, parsed)
+}
+
+func TestAKSClusterTfVarsFile(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-terraform-aks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := &cmd.AKSCluster{
+		Organisation:  "acme",
+		ResourceGroup: "jx-acme-prod",
+		Location:      "westeurope",
+		NodeVMSize:    "Standard_D2s_v3",
+		MinNumOfNodes: "3",
+		MaxNumOfNodes: "5",
+		DiskSize:      "100",
+	}
+	c.SetName("prod")
+	c.SetProvider("aks")
+	assert.Equal(t, "acme-prod", c.Context())
+
+	path := filepath.Join(dir, "terraform.tfvars")
+	require.NoError(t, c.CreateTfVarsFile(path))
+
+	parsed := &cmd.AKSCluster{}
+	parsed.SetName("prod")
+	parsed.ParseTfVarsFile(path)
+	assert.Equal(t, c, parsed)
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/jenkins-x/jx/pkg/webhooks"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+var (
+	createWebhookEventLong = templates.LongDesc(`
+		Synthesises a webhook of a git provider and sends it to the Prow hook or the Jenkins webhook endpoint.
+
+		Use this to trigger pipelines without pushing to the git provider or when the git provider cannot reach the cluster.
+		The event types are: ` + strings.Join(webhooks.EventTypes, ", ") + `
+`)
+
+	createWebhookEventExample = templates.Examples(`
+		# Send a push of the current commit of the current directory
+		jx create webhook-event push
+
+		# Send a Pull Request event
+		jx create webhook-event pull-request --pr 12 --branch my-feature
+
+		# Send a comment on a Pull Request to a local endpoint
+		jx create webhook-event comment --pr 12 --comment "/test this" --target-url http://localhost:8888/hook --secret mysecret
+
+		# Print the payload rather than sending it
+		jx create webhook-event push --dry-run
+	`)
+)
+
+// CreateWebhookEventOptions the options for the create webhook-event command
+type CreateWebhookEventOptions struct {
+	CreateOptions
+	WebhookTargetOptions
+
+	Dir        string
+	GitURL     string
+	Kind       string
+	Branch     string
+	BaseBranch string
+	Sha        string
+	Number     int
+	Title      string
+	Comment    string
+	Sender     string
+	DryRun     bool
+}
+
+// NewCmdCreateWebhookEvent creates a command object for the "create webhook-event" command
+func NewCmdCreateWebhookEvent(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &CreateWebhookEventOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "webhook-event [" + strings.Join(webhooks.EventTypes, "|") + "]",
+		Short:   "Sends a synthesised git provider webhook to the Prow hook or Jenkins",
+		Long:    createWebhookEventLong,
+		Example: createWebhookEventExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Dir, "dir", "", "", "The source directory used to detect the Git repository. Defaults to the current directory")
+	cmd.Flags().StringVarP(&options.GitURL, "git-url", "u", "", "The git URL of the repository. Defaults to the Git repository of the current directory")
+	cmd.Flags().StringVarP(&options.Kind, "kind", "k", "", "The kind of git provider to synthesise the webhook of. Defaults to the kind of the git server of the repository")
+	cmd.Flags().StringVarP(&options.Branch, "branch", "b", "", "The branch pushed to or the source branch of the Pull Request. Defaults to the current branch")
+	cmd.Flags().StringVarP(&options.BaseBranch, "base-branch", "", "master", "The target branch of the Pull Request")
+	cmd.Flags().StringVarP(&options.Sha, "sha", "", "", "The commit SHA. Defaults to the HEAD of the current directory")
+	cmd.Flags().IntVarP(&options.Number, "pr", "", 0, "The Pull Request number")
+	cmd.Flags().StringVarP(&options.Title, optionTitle, "t", "", "The title of the Pull Request or commit message")
+	cmd.Flags().StringVarP(&options.Comment, "comment", "c", "/test this", "The comment on the Pull Request")
+	cmd.Flags().StringVarP(&options.Sender, "sender", "", "", "The user name sending the webhook. Defaults to the owner of the repository")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "Prints the webhook rather than sending it")
+	options.WebhookTargetOptions.addFlags(cmd)
+
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *CreateWebhookEventOptions) Run() error {
+	if len(o.Args) == 0 {
+		return fmt.Errorf("Missing the event type argument. Valid types are %s", strings.Join(webhooks.EventTypes, ", "))
+	}
+	eventType := o.Args[0]
+	if util.StringArrayIndex(webhooks.EventTypes, eventType) < 0 {
+		return util.InvalidArg(eventType, webhooks.EventTypes)
+	}
+
+	var gitInfo *gits.GitRepositoryInfo
+	var err error
+	if o.GitURL != "" {
+		gitInfo, err = gits.ParseGitURL(o.GitURL)
+	} else {
+		gitInfo, err = o.FindGitInfo(o.Dir)
+	}
+	if err != nil {
+		return err
+	}
+	if o.GitServer == "" {
+		o.GitServer = gitInfo.HostURLWithoutUser()
+	}
+
+	kind := o.Kind
+	if kind == "" {
+		if gitInfo.IsGitHub() {
+			kind = gits.KindGitHub
+		} else {
+			kind, err = o.GitServerKind(gitInfo)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if o.GitURL == "" {
+		if o.Branch == "" {
+			o.Branch, err = o.Git().Branch(o.Dir)
+			if err != nil {
+				return err
+			}
+		}
+		if o.Sha == "" {
+			o.Sha, err = o.getCommandOutput(o.Dir, "git", "rev-parse", "HEAD")
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	event, err := o.synthesiseEvent(kind, eventType, gitInfo)
+	if err != nil {
+		return err
+	}
+	if o.DryRun {
+		for name, value := range event.Headers {
+			log.Infof("%s: %s\n", name, value)
+		}
+		log.Infof("\n%s\n", event.Body)
+		return nil
+	}
+	return o.sendWebhookEvent(&o.WebhookTargetOptions, event)
+}
+
+func (o *CreateWebhookEventOptions) synthesiseEvent(kind string, eventType string, gitInfo *gits.GitRepositoryInfo) (*webhooks.Event, error) {
+	return webhooks.Synthesise(&webhooks.EventArguments{
+		Kind:       kind,
+		Type:       eventType,
+		Repository: gitInfo,
+		Branch:     o.Branch,
+		BaseBranch: o.BaseBranch,
+		Sha:        o.Sha,
+		Number:     o.Number,
+		Title:      o.Title,
+		Comment:    o.Comment,
+		Sender:     o.Sender,
+	})
+}
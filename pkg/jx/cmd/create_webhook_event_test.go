@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWebhookEventSynthesisesPullRequest(t *testing.T) {
+	t.Parallel()
+	gitInfo, err := gits.ParseGitURL("https://github.com/jstrachan/myapp.git")
+	require.NoError(t, err)
+
+	o := &CreateWebhookEventOptions{
+		Branch:     "my-feature",
+		BaseBranch: "master",
+		Sha:        "abc123",
+		Number:     12,
+	}
+	event, err := o.synthesiseEvent(gits.KindGitHub, webhooks.PullRequestEvent, gitInfo)
+	require.NoError(t, err)
+
+	assert.Equal(t, "pull_request", event.Type)
+	assert.Equal(t, "jstrachan/myapp", event.Repository)
+	assert.Equal(t, "pull_request", event.Headers["X-GitHub-Event"])
+
+	payload := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(event.Body), &payload))
+	assert.Equal(t, float64(12), payload["number"])
+}
+
+func TestWebhookTargetUsesTargetURL(t *testing.T) {
+	t.Parallel()
+	o := &CommonOptions{}
+	target := &WebhookTargetOptions{
+		TargetURL: "http://localhost:8888/hook",
+		Secret:    "mysecret",
+	}
+	url, secret, err := o.webhookTarget(target, "jstrachan/myapp")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8888/hook", url)
+	assert.Equal(t, "mysecret", secret)
+}
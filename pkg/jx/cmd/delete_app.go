@@ -14,6 +14,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/prow"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 
@@ -22,7 +23,7 @@ import (
 
 var (
 	deleteAppLong = templates.LongDesc(`
-		Deletes one or more Applications from Jenkins
+		Deletes one or more Applications from Jenkins or, if the team uses Prow, removes them from Prow
 
 		Note that this command does not remove the underlying Git Repositories. 
 
@@ -97,24 +98,47 @@ func NewCmdDeleteApp(f Factory, in terminal.FileReader, out terminal.FileWriter,
 func (o *DeleteAppOptions) Run() error {
 	args := o.Args
 
-	jenk, err := o.JenkinsClient()
+	_, _, err := o.KubeClient()
 	if err != nil {
 		return err
 	}
-
-	jobs, err := jenkins.LoadAllJenkinsJobs(jenk)
+	_, _, err = o.JXClient()
+	if err != nil {
+		return err
+	}
+	isProw, err := o.isProw()
 	if err != nil {
 		return err
 	}
 
+	var jenk gojenkins.JenkinsClient
 	names := []string{}
 	m := map[string]*gojenkins.Job{}
+	engine := "Jenkins"
+
+	if isProw {
+		engine = "Prow"
+		names, err = prow.GetRepositories(o.KubeClientCached, o.currentNamespace)
+		if err != nil {
+			return err
+		}
+	} else {
+		jenk, err = o.JenkinsClient()
+		if err != nil {
+			return err
+		}
+
+		jobs, err := jenkins.LoadAllJenkinsJobs(jenk)
+		if err != nil {
+			return err
+		}
 
-	for _, j := range jobs {
-		if jenkins.IsMultiBranchProject(j) {
-			name := j.FullName
-			names = append(names, name)
-			m[name] = j
+		for _, j := range jobs {
+			if jenkins.IsMultiBranchProject(j) {
+				name := j.FullName
+				names = append(names, name)
+				m[name] = j
+			}
 		}
 	}
 
@@ -134,16 +158,16 @@ func (o *DeleteAppOptions) Run() error {
 	}
 
 	if len(names) == 0 {
-		return fmt.Errorf("There are no Apps in Jenkins")
+		return fmt.Errorf("There are no Apps in %s", engine)
 	}
 
 	if len(args) == 0 {
-		args, err = util.SelectNamesWithFilter(names, "Pick Applications to remove from Jenkins:", o.SelectAll, o.SelectFilter, o.In, o.Out, o.Err)
+		args, err = util.SelectNamesWithFilter(names, "Pick Applications to remove from "+engine+":", o.SelectAll, o.SelectFilter, o.In, o.Out, o.Err)
 		if err != nil {
 			return err
 		}
 		if len(args) == 0 {
-			return fmt.Errorf("No application was picked to be removed from Jenkins")
+			return fmt.Errorf("No application was picked to be removed from %s", engine)
 		}
 	} else {
 		for _, arg := range args {
@@ -155,24 +179,35 @@ func (o *DeleteAppOptions) Run() error {
 	deleteMessage := strings.Join(args, ", ")
 
 	if !o.BatchMode {
-		if !util.Confirm("You are about to delete these Applications from Jenkins: "+deleteMessage, false, "The list of Applications names to be deleted from Jenkins", o.In, o.Out, o.Err) {
+		if !util.Confirm("You are about to delete these Applications from "+engine+": "+deleteMessage, false, "The list of Applications names to be deleted from "+engine, o.In, o.Out, o.Err) {
 			return nil
 		}
 	}
 	for _, name := range args {
-		job := m[name]
-		if job != nil {
-			err = o.deleteApp(jenk, name, job)
-			if err != nil {
-				return err
+		repo := name
+		if isProw {
+			err = o.deleteApp(name, func() error {
+				return prow.RemoveRepository(o.KubeClientCached, o.currentNamespace, repo)
+			})
+		} else {
+			job := m[name]
+			if job == nil {
+				continue
 			}
+			err = o.deleteApp(name, func() error {
+				return jenk.DeleteJob(*job)
+			})
+		}
+		if err != nil {
+			return err
 		}
 	}
 	log.Infof("Deleted Applications %s\n", util.ColorInfo(deleteMessage))
 	return nil
 }
 
-func (o *DeleteAppOptions) deleteApp(jenkinsClient gojenkins.JenkinsClient, name string, job *gojenkins.Job) error {
+// deleteApp removes the app from the permanent environments then removes its pipeline with the given function
+func (o *DeleteAppOptions) deleteApp(name string, deletePipeline func() error) error {
 	apisClient, err := o.Factory.CreateApiExtensionsClient()
 	if err != nil {
 		return err
@@ -208,7 +243,7 @@ func (o *DeleteAppOptions) deleteApp(jenkinsClient gojenkins.JenkinsClient, name
 	}
 
 	// lets try delete the job from each environment first
-	return jenkinsClient.DeleteJob(*job)
+	return deletePipeline()
 }
 
 func (o *DeleteAppOptions) appNameFromJenkinsJobName(name string) string {
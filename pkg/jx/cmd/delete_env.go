@@ -60,6 +60,7 @@ func NewCmdDeleteEnv(f Factory, in terminal.FileReader, out terminal.FileWriter,
 	//addDeleteFlags(cmd, &options.CreateOptions)
 
 	cmd.Flags().BoolVarP(&options.DeleteNamespace, "namespace", "n", false, "Delete the namespace for the Environment too?")
+	options.addAuditFlags(cmd)
 	return cmd
 }
 
@@ -123,6 +124,7 @@ func (o *DeleteEnvOptions) deleteEnviroment(jxClient versioned.Interface, ns str
 	log.Infof("Deleted environment %s\n", util.ColorInfo(name))
 
 	env := envMap[name]
+	o.auditChange(v1.AuditActionDelete, "Environment", name, &env.Spec, nil)
 	envNs := env.Spec.Namespace
 	if envNs == "" {
 		return fmt.Errorf("No namespace for environment %s", name)
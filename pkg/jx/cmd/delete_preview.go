@@ -10,6 +10,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // DeletePreviewOptions are the flags for delete commands
@@ -99,6 +100,17 @@ func (o *DeletePreviewOptions) Run() error {
 
 func (o *DeletePreviewOptions) deletePreview(name string) error {
 	log.Infof("Deleting preview environment: %s\n", util.ColorInfo(name))
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	env, err := jxClient.JenkinsV1().Environments(devNs).Get(name, metav1.GetOptions{})
+	if err == nil {
+		err = o.deletePreviewDependencies(env)
+		if err != nil {
+			return err
+		}
+	}
 	deleteOptions := &DeleteEnvOptions{
 		CommonOptions:   o.CommonOptions,
 		DeleteNamespace: true,
@@ -164,6 +164,7 @@ func (o *DeleteRepoOptions) Run() error {
 		owner = username
 	}
 	info := util.ColorInfo
+	deleted := []string{}
 	for _, name := range names {
 		err = provider.DeleteRepository(owner, name)
 		if err != nil {
@@ -171,6 +172,13 @@ func (o *DeleteRepoOptions) Run() error {
 			log.Warnf("%s\n", err)
 		} else {
 			log.Infof("Deleted repository %s/%s\n", info(owner), info(name))
+			deleted = append(deleted, owner+"/"+name)
+		}
+	}
+	if len(deleted) > 0 {
+		err = o.removeRepositoriesFromProw(deleted...)
+		if err != nil {
+			log.Warnf("Could not remove the deleted repositories from Prow: %s\n", err)
 		}
 	}
 	return nil
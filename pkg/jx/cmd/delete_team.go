@@ -147,6 +147,25 @@ func (o *DeleteTeamOptions) deleteTeam(name string) error {
 		return err
 	}
 
+	team, err := jxClient.JenkinsV1().Teams(ns).Get(name, metav1.GetOptions{})
+	if err == nil && kube.HasTeamFinalizer(team) {
+		// the team controller removes the resources of the team before the team is removed
+		err = o.ModifyTeam(name, func(team *v1.Team) error {
+			team.Status.ProvisionStatus = v1.TeamProvisionStatusDeleting
+			team.Status.Message = "Waiting for the team controller to delete the resources"
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		err = kube.DeleteTeam(jxClient, ns, name)
+		if err != nil {
+			return err
+		}
+		log.Infof("The team controller is deleting the resources of team %s. Track its progress via: %s\n", util.ColorInfo(name), util.ColorInfo("jx get team --pending"))
+		return nil
+	}
+
 	uninstall := &UninstallOptions{
 		CommonOptions: o.CommonOptions,
 		Namespace:     name,
@@ -52,11 +52,13 @@ func NewCmdEdit(f Factory, in terminal.FileReader, out terminal.FileWriter, errO
 	}
 
 	cmd.AddCommand(NewCmdCreateBranchPattern(f, in, out, errOut))
+	cmd.AddCommand(NewCmdEditBranchProtection(f, in, out, errOut))
 	cmd.AddCommand(NewCmdEditAddon(f, in, out, errOut))
 	cmd.AddCommand(NewCmdEditBuildpack(f, in, out, errOut))
 	cmd.AddCommand(NewCmdEditConfig(f, in, out, errOut))
 	cmd.AddCommand(NewCmdEditEnv(f, in, out, errOut))
 	cmd.AddCommand(NewCmdEditHelmBin(f, in, out, errOut))
+	cmd.AddCommand(NewCmdEditProw(f, in, out, errOut))
 	cmd.AddCommand(NewCmdEditUserRole(f, in, out, errOut))
 	addTeamSettingsCommandsFromTags(cmd, in, out, errOut, options)
 	return cmd
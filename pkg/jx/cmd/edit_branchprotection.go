@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+var (
+	editBranchProtectionLong = templates.LongDesc(`
+		Configures the branch protection policy of your team
+
+		The policy is applied to the git repositories created or imported via 'jx import', 'jx create quickstart'
+		and 'jx create env' so that changes have to pass the pipeline before they can be merged.
+`)
+
+	editBranchProtectionExample = templates.Examples(`
+		# Require the pipeline to pass and one approving review before merging into master
+		jx edit branchprotection --context continuous-integration/jenkins/pr-merge --reviews 1
+
+		# Stop protecting the branches of new repositories
+		jx edit branchprotection --disable
+	`)
+)
+
+// EditBranchProtectionOptions the options for the edit branchprotection command
+type EditBranchProtectionOptions struct {
+	EditOptions
+
+	Branch                   string
+	RequiredStatusContexts   []string
+	RequiredApprovingReviews int
+	DismissStaleReviews      bool
+	EnforceAdmins            bool
+	AllowForcePushes         bool
+	RequireUpToDate          bool
+	Disable                  bool
+}
+
+// NewCmdEditBranchProtection creates a command object for the "edit branchprotection" command
+func NewCmdEditBranchProtection(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &EditBranchProtectionOptions{
+		EditOptions: EditOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "branchprotection",
+		Short:   "Configures the branch protection policy of your team",
+		Aliases: []string{"branchprotect"},
+		Long:    editBranchProtectionLong,
+		Example: editBranchProtectionExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Branch, "branch", "b", defaultProtectedBranch, "The branch to protect")
+	cmd.Flags().StringArrayVarP(&options.RequiredStatusContexts, "context", "c", []string{}, "The contexts of the commit statuses which must pass before merging")
+	cmd.Flags().IntVarP(&options.RequiredApprovingReviews, "reviews", "r", 0, "The number of approving reviews required before merging")
+	cmd.Flags().BoolVarP(&options.DismissStaleReviews, "dismiss-stale-reviews", "", false, "Dismisses approving reviews when new commits are pushed")
+	cmd.Flags().BoolVarP(&options.EnforceAdmins, "enforce-admins", "", false, "Applies the protection to administrators too")
+	cmd.Flags().BoolVarP(&options.AllowForcePushes, "allow-force-pushes", "", false, "Allows force pushes to the branch. Protecting the branch fails on the git providers which do not support it")
+	cmd.Flags().BoolVarP(&options.RequireUpToDate, "require-up-to-date", "", false, "Requires branches to be up to date with the protected branch before merging on the git providers which support it")
+	cmd.Flags().BoolVarP(&options.Disable, "disable", "", false, "Disables the branch protection of new repositories")
+
+	options.addCommonFlags(cmd)
+	options.addAuditFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *EditBranchProtectionOptions) Run() error {
+	callback := func(env *v1.Environment) error {
+		if o.Disable {
+			env.Spec.TeamSettings.BranchProtection = nil
+			log.Infof("Disabled the branch protection of new repositories\n")
+			return nil
+		}
+		env.Spec.TeamSettings.BranchProtection = &v1.BranchProtectionPolicy{
+			Branch:                   o.Branch,
+			RequiredStatusContexts:   o.RequiredStatusContexts,
+			RequiredApprovingReviews: o.RequiredApprovingReviews,
+			DismissStaleReviews:      o.DismissStaleReviews,
+			EnforceAdmins:            o.EnforceAdmins,
+			AllowForcePushes:         o.AllowForcePushes,
+			RequireUpToDate:          o.RequireUpToDate,
+		}
+		log.Infof("Protecting branch %s of new repositories requiring contexts [%s] and %d approving reviews\n",
+			util.ColorInfo(o.Branch), util.ColorInfo(strings.Join(o.RequiredStatusContexts, ", ")), o.RequiredApprovingReviews)
+		return nil
+	}
+	return o.ModifyDevEnvironment(callback)
+}
@@ -62,6 +62,7 @@ func NewCmdEditBuildpack(f Factory, in terminal.FileReader, out terminal.FileWri
 	cmd.Flags().StringVarP(&options.BuildPackURL, "url", "u", "", "The URL for the build pack Git repository")
 	cmd.Flags().StringVarP(&options.BuildPackRef, "ref", "r", "", "The Git reference (branch,tag,sha) in the Git repository touse")
 	options.addCommonFlags(cmd)
+	options.addAuditFlags(cmd)
 	return cmd
 }
 
@@ -96,6 +96,7 @@ func NewCmdEditEnv(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 
 	addGitRepoOptionsArguments(cmd, &options.GitRepositoryOptions)
 	options.HelmValuesConfig.AddExposeControllerValues(cmd, false)
+	options.addAuditFlags(cmd)
 	return cmd
 }
 
@@ -155,6 +156,7 @@ func (o *EditEnvOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	before := env.Spec.DeepCopy()
 	o.Options.Spec.PromotionStrategy = v1.PromotionStrategyType(o.PromotionStrategy)
 	gitProvider, err := kube.CreateEnvironmentSurvey(o.BatchMode, authConfigSvc, devEnv, env, &o.Options, o.ForkEnvironmentGitRepo,
 		ns, jxClient, kubeClient, envDir, &o.GitRepositoryOptions, o.HelmValuesConfig, o.Prefix, o.Git(), o.In, o.Out, o.Err)
@@ -166,6 +168,7 @@ func (o *EditEnvOptions) Run() error {
 		return err
 	}
 	log.Infof("Updated environment %s\n", util.ColorInfo(env.Name))
+	o.auditChange(v1.AuditActionUpdate, "Environment", env.Name, before, &env.Spec)
 
 	err = kube.EnsureEnvironmentNamespaceSetup(kubeClient, jxClient, env, ns)
 	if err != nil {
@@ -64,6 +64,7 @@ func NewCmdEditHelmBin(f Factory, in terminal.FileReader, out terminal.FileWrite
 	}
 
 	options.addCommonFlags(cmd)
+	options.addAuditFlags(cmd)
 	return cmd
 }
 
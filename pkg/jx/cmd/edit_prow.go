@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/prow"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	"k8s.io/test-infra/prow/config"
+)
+
+var (
+	editProwLong = templates.LongDesc(`
+		Edits the Prow configuration of a repository: its plugins, triggers, Tide merge policy and presubmit and postsubmit jobs.
+
+		The 'config' and 'plugins' ConfigMaps are validated before they are written so that a mistake cannot stop Prow loading them.
+`)
+
+	editProwExample = templates.Examples(`
+		# Enable the shrug plugin and squash merge the Pull Requests of the current repository
+		jx edit prow --add-plugin shrug --merge-method squash
+
+		# Require the lgtm label before Tide merges the Pull Requests of a repository
+		jx edit prow myorg/myrepo --label lgtm
+
+		# Add or replace a presubmit job defined in a YAML file
+		jx edit prow myorg/myrepo --presubmit-file lint.yaml
+
+		# Remove a repository from Prow
+		jx edit prow myorg/myrepo --remove
+
+		# Lint the Prow ConfigMaps without changing them
+		jx edit prow --validate
+	`)
+)
+
+// EditProwOptions the options for the edit prow command
+type EditProwOptions struct {
+	EditOptions
+
+	Dir                 string
+	AddPlugins          []string
+	RemovePlugins       []string
+	TrustedOrg          string
+	OnlyOrgMembers      bool
+	MergeMethod         string
+	Labels              []string
+	RemoveLabels        []string
+	MissingLabels       []string
+	RemoveMissingLabels []string
+	NoTide              bool
+	PresubmitFiles      []string
+	PostsubmitFiles     []string
+	RemovePresubmits    []string
+	RemovePostsubmits   []string
+	Remove              bool
+	Validate            bool
+}
+
+// NewCmdEditProw creates a command object for the "edit prow" command
+func NewCmdEditProw(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &EditProwOptions{
+		EditOptions: EditOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "prow [owner/name]",
+		Short:   "Edits the Prow plugins, triggers, Tide policy and jobs of a repository",
+		Long:    editProwLong,
+		Example: editProwExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", ".", "The directory of the git repository to edit if no repository is specified")
+	cmd.Flags().StringArrayVarP(&options.AddPlugins, "add-plugin", "p", []string{}, "The plugins to enable")
+	cmd.Flags().StringArrayVarP(&options.RemovePlugins, "remove-plugin", "", []string{}, "The plugins to disable")
+	cmd.Flags().StringVarP(&options.TrustedOrg, "trusted-org", "", "", "The org whose members can trigger the jobs of Pull Requests")
+	cmd.Flags().BoolVarP(&options.OnlyOrgMembers, "only-org-members", "", false, "Only lets members of the trusted org trigger jobs rather than collaborators too")
+	cmd.Flags().StringVarP(&options.MergeMethod, "merge-method", "m", "", "How Tide merges Pull Requests. One of: merge, rebase, squash")
+	cmd.Flags().StringArrayVarP(&options.Labels, "label", "l", []string{}, "The labels a Pull Request needs before Tide merges it")
+	cmd.Flags().StringArrayVarP(&options.RemoveLabels, "remove-label", "", []string{}, "The labels a Pull Request no longer needs before Tide merges it")
+	cmd.Flags().StringArrayVarP(&options.MissingLabels, "missing-label", "", []string{}, "The labels which stop Tide merging a Pull Request")
+	cmd.Flags().StringArrayVarP(&options.RemoveMissingLabels, "remove-missing-label", "", []string{}, "The labels which no longer stop Tide merging a Pull Request")
+	cmd.Flags().BoolVarP(&options.NoTide, "no-tide", "", false, "Stops Tide merging the Pull Requests of the repository")
+	cmd.Flags().StringArrayVarP(&options.PresubmitFiles, "presubmit-file", "", []string{}, "YAML files of presubmit jobs to add or replace by name")
+	cmd.Flags().StringArrayVarP(&options.PostsubmitFiles, "postsubmit-file", "", []string{}, "YAML files of postsubmit jobs to add or replace by name")
+	cmd.Flags().StringArrayVarP(&options.RemovePresubmits, "remove-presubmit", "", []string{}, "The names of the presubmit jobs to remove")
+	cmd.Flags().StringArrayVarP(&options.RemovePostsubmits, "remove-postsubmit", "", []string{}, "The names of the postsubmit jobs to remove")
+	cmd.Flags().BoolVarP(&options.Remove, "remove", "", false, "Removes the repository from Prow")
+	cmd.Flags().BoolVarP(&options.Validate, "validate", "", false, "Only validates the Prow ConfigMaps")
+
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *EditProwOptions) Run() error {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	if o.Validate {
+		err = prow.Validate(kubeClient, ns)
+		if err != nil {
+			return fmt.Errorf("Invalid Prow configuration in namespace %s:\n%s", ns, err)
+		}
+		log.Infof("The Prow configuration in namespace %s is valid\n", util.ColorInfo(ns))
+		return nil
+	}
+
+	repo := ""
+	if len(o.Args) > 0 {
+		repo = o.Args[0]
+	} else {
+		gitInfo, err := o.FindGitInfo(o.Dir)
+		if err != nil {
+			return err
+		}
+		repo = gitInfo.Organisation + "/" + gitInfo.Name
+	}
+
+	if o.Remove {
+		err = prow.RemoveRepository(kubeClient, ns, repo)
+		if err != nil {
+			return err
+		}
+		log.Infof("Removed repository %s from Prow\n", util.ColorInfo(repo))
+		return nil
+	}
+
+	err = prow.UpdateRepositoryConfig(kubeClient, ns, repo, o.applyChanges)
+	if err != nil {
+		return err
+	}
+	log.Infof("Updated the Prow configuration of repository %s\n", util.ColorInfo(repo))
+	return nil
+}
+
+// applyChanges applies the command line options to the Prow configuration of the repository
+func (o *EditProwOptions) applyChanges(rc *prow.RepositoryConfig) error {
+	rc.Plugins = editStrings(rc.Plugins, o.AddPlugins, o.RemovePlugins)
+
+	if o.TrustedOrg != "" {
+		rc.TrustedOrg = o.TrustedOrg
+	}
+	if o.OnlyOrgMembers || (o.Cmd != nil && o.Cmd.Flags().Changed("only-org-members")) {
+		rc.OnlyOrgMembers = o.OnlyOrgMembers
+	}
+
+	if o.NoTide {
+		rc.Tide = false
+	} else if o.MergeMethod != "" || len(o.Labels) > 0 || len(o.RemoveLabels) > 0 || len(o.MissingLabels) > 0 || len(o.RemoveMissingLabels) > 0 {
+		rc.Tide = true
+	}
+	if o.MergeMethod != "" {
+		rc.MergeMethod = o.MergeMethod
+	}
+	rc.Labels = editStrings(rc.Labels, o.Labels, o.RemoveLabels)
+	rc.MissingLabels = editStrings(rc.MissingLabels, o.MissingLabels, o.RemoveMissingLabels)
+
+	for _, name := range o.RemovePresubmits {
+		if !rc.RemovePresubmit(name) {
+			return fmt.Errorf("No presubmit job %s found for repository %s", name, rc.Repo)
+		}
+	}
+	for _, name := range o.RemovePostsubmits {
+		if !rc.RemovePostsubmit(name) {
+			return fmt.Errorf("No postsubmit job %s found for repository %s", name, rc.Repo)
+		}
+	}
+	for _, file := range o.PresubmitFiles {
+		job := config.Presubmit{}
+		err := loadProwJob(file, &job)
+		if err != nil {
+			return err
+		}
+		rc.SetPresubmit(job)
+	}
+	for _, file := range o.PostsubmitFiles {
+		job := config.Postsubmit{}
+		err := loadProwJob(file, &job)
+		if err != nil {
+			return err
+		}
+		rc.SetPostsubmit(job)
+	}
+	return nil
+}
+
+func loadProwJob(file string, job interface{}) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("Failed to load file %s: %s", file, err)
+	}
+	err = yaml.Unmarshal(data, job)
+	if err != nil {
+		return fmt.Errorf("Failed to unmarshal the Prow job in %s: %s", file, err)
+	}
+	return nil
+}
+
+// editStrings returns the values with the additions appended and the removals removed
+func editStrings(values []string, additions []string, removals []string) []string {
+	answer := []string{}
+	for _, v := range values {
+		if util.StringArrayIndex(removals, v) < 0 {
+			answer = append(answer, v)
+		}
+	}
+	for _, v := range additions {
+		if util.StringArrayIndex(answer, v) < 0 {
+			answer = append(answer, v)
+		}
+	}
+	return answer
+}
+
+// removeRepositoriesFromProw removes the 'owner/name' repositories from Prow if the team uses Prow
+func (o *CommonOptions) removeRepositoriesFromProw(repos ...string) error {
+	_, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	_, _, err = o.JXClient()
+	if err != nil {
+		return err
+	}
+	isProw, err := o.isProw()
+	if err != nil || !isProw {
+		return err
+	}
+	for _, repo := range repos {
+		err = prow.RemoveRepository(o.KubeClientCached, o.currentNamespace, repo)
+		if err != nil {
+			return fmt.Errorf("Failed to remove repository %s from Prow: %s", repo, err)
+		}
+		log.Infof("Removed repository %s from Prow\n", util.ColorInfo(repo))
+	}
+	return nil
+}
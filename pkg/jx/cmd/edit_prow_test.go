@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/prow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditProwApplyChanges(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-edit-prow")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	lintFile := filepath.Join(dir, "lint.yaml")
+	err = ioutil.WriteFile(lintFile, []byte(`name: lint
+context: lint
+agent: knative-build
+trigger: "(?m)^/lint,?(\\s+|$)"
+rerun_command: /lint
+`), 0644)
+	require.NoError(t, err)
+
+	rc := &prow.RepositoryConfig{
+		Repo:          "myorg/myrepo",
+		Plugins:       []string{"approve", "cat", "lgtm"},
+		Tide:          true,
+		Labels:        []string{"approved"},
+		MissingLabels: []string{"do-not-merge"},
+	}
+	o := &EditProwOptions{
+		AddPlugins:          []string{"shrug", "lgtm"},
+		RemovePlugins:       []string{"cat"},
+		MergeMethod:         "squash",
+		Labels:              []string{"lgtm"},
+		RemoveMissingLabels: []string{"do-not-merge"},
+		OnlyOrgMembers:      true,
+		PresubmitFiles:      []string{lintFile},
+	}
+	err = o.applyChanges(rc)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"approve", "lgtm", "shrug"}, rc.Plugins)
+	assert.Equal(t, "squash", rc.MergeMethod)
+	assert.Equal(t, []string{"approved", "lgtm"}, rc.Labels)
+	assert.Empty(t, rc.MissingLabels)
+	assert.True(t, rc.OnlyOrgMembers)
+	require.Equal(t, 1, len(rc.Presubmits))
+	assert.Equal(t, "/lint", rc.Presubmits[0].RerunCommand)
+
+	o = &EditProwOptions{
+		NoTide:           true,
+		RemovePresubmits: []string{"lint"},
+	}
+	err = o.applyChanges(rc)
+	require.NoError(t, err)
+	assert.False(t, rc.Tide)
+	assert.Empty(t, rc.Presubmits)
+	assert.True(t, rc.OnlyOrgMembers, "the trigger policy should be unchanged")
+
+	err = o.applyChanges(rc)
+	assert.Error(t, err, "removing a missing job should fail")
+}
@@ -3,8 +3,10 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
@@ -30,6 +32,12 @@ var (
 	`)
 )
 
+// userRolesAudit the roles of a user recorded in the audit trail
+type userRolesAudit struct {
+	Kind  string   `json:"kind"`
+	Roles []string `json:"roles"`
+}
+
 // EditUserRoleOptions the options for the create spring command
 type EditUserRoleOptions struct {
 	EditOptions
@@ -69,6 +77,7 @@ func NewCmdEditUserRole(f Factory, in terminal.FileReader, out terminal.FileWrit
 	cmd.Flags().StringArrayVarP(&options.Roles, "role", "r", []string{}, "The roles to set on a user")
 
 	options.addCommonFlags(cmd)
+	options.addAuditFlags(cmd)
 	return cmd
 }
 
@@ -141,6 +150,11 @@ func (o *EditUserRoleOptions) Run() error {
 		}
 	}
 
+	oldRoles, err := kube.GetUserRoles(jxClient, ns, userKind, name)
+	if err != nil {
+		return err
+	}
+
 	rolesText := strings.Join(userRoles, ", ")
 	log.Infof("updating user %s for roles %s\n", name, rolesText)
 
@@ -149,6 +163,9 @@ func (o *EditUserRoleOptions) Run() error {
 		return errors.Wrapf(err, "Failed to update user roles for user %s kind %s and roles %s", name, userKind, rolesText)
 	}
 	log.Infof("Updated roles for user: %s kind: %s roles: %s\n", util.ColorInfo(name), util.ColorInfo(userKind), util.ColorInfo(rolesText))
+
+	sort.Strings(oldRoles)
+	o.auditChange(v1.AuditActionUpdate, "EnvironmentRoleBinding", name, userRolesAudit{Kind: userKind, Roles: oldRoles}, userRolesAudit{Kind: userKind, Roles: userRoles})
 	return nil
 
 }
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// EnvironmentDrift the resources of an environment whose live state differs from the environment git repository
+type EnvironmentDrift struct {
+	Environment string                `json:"environment"`
+	Namespace   string                `json:"namespace"`
+	Resources   []*kube.ResourceDrift `json:"resources,omitempty"`
+
+	workDir     string
+	dir         string
+	releaseName string
+}
+
+// removeWorkDir removes the clone of the environment git repository
+func (d *EnvironmentDrift) removeWorkDir() {
+	if d.workDir != "" {
+		os.RemoveAll(d.workDir)
+	}
+}
+
+// environmentReleaseName returns the helm release name used to deploy the environment chart into the namespace
+func (o *CommonOptions) environmentReleaseName(ns string) (string, error) {
+	helmBinary, noTiller, helmTemplate, err := o.TeamHelmBin()
+	if err != nil {
+		return "", err
+	}
+	if helmBinary != "helm" || noTiller || helmTemplate {
+		return "jx", nil
+	}
+	return ns, nil
+}
+
+// detectEnvironmentDrift renders the chart of the environment git repository and compares it with the live
+// resources in the environment namespace
+func (o *CommonOptions) detectEnvironmentDrift(env *v1.Environment) (*EnvironmentDrift, error) {
+	gitURL := env.Spec.Source.URL
+	if gitURL == "" {
+		return nil, fmt.Errorf("Environment %s has no git repository", env.Name)
+	}
+	ns := env.Spec.Namespace
+	if ns == "" {
+		return nil, fmt.Errorf("Environment %s has no namespace", env.Name)
+	}
+	dir, err := ioutil.TempDir("", "jx-env-drift-")
+	if err != nil {
+		return nil, err
+	}
+	drift := &EnvironmentDrift{
+		Environment: env.Name,
+		Namespace:   ns,
+		workDir:     dir,
+		dir:         filepath.Join(dir, "env"),
+	}
+	answer, err := o.renderAndCompareEnvironment(env, drift)
+	if err != nil {
+		drift.removeWorkDir()
+	}
+	return answer, err
+}
+
+func (o *CommonOptions) renderAndCompareEnvironment(env *v1.Environment, drift *EnvironmentDrift) (*EnvironmentDrift, error) {
+	gitURL := env.Spec.Source.URL
+	ns := drift.Namespace
+	dir := drift.workDir
+	log.Infof("Cloning environment %s repository %s\n", util.ColorInfo(env.Name), util.ColorInfo(gitURL))
+	err := o.Git().Clone(gitURL, dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to clone the git repository of environment %s", env.Name)
+	}
+	ref := env.Spec.Source.Ref
+	if ref != "" && ref != "master" {
+		err = o.Git().Checkout(dir, ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to checkout %s of the git repository of environment %s", ref, env.Name)
+		}
+	}
+	envDir := drift.dir
+	_, err = o.helmInitDependencyBuild(envDir, o.defaultReleaseCharts())
+	if err != nil {
+		return nil, err
+	}
+	releaseName, err := o.environmentReleaseName(ns)
+	if err != nil {
+		return nil, err
+	}
+	outDir := filepath.Join(dir, "output")
+	err = o.Helm().Template(envDir, releaseName, ns, outDir, true, nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render the chart of environment %s", env.Name)
+	}
+	desired, err := loadDesiredResources(outDir)
+	if err != nil {
+		return nil, err
+	}
+	drift.releaseName = releaseName
+	drift.Resources, err = kube.DetectDrift(desired, ns, o.kubectlResourceGetter())
+	if err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
+
+// kubectlResourceGetter returns the live resources using kubectl so that any kind of resource can be compared
+func (o *CommonOptions) kubectlResourceGetter() kube.LiveResourceGetter {
+	return func(kind string, name string, ns string) (map[string]interface{}, error) {
+		text, err := o.getCommandOutput("", "kubectl", "get", kind+"/"+name, "--namespace", ns, "-o", "json", "--ignore-not-found")
+		if err != nil {
+			return nil, err
+		}
+		if text == "" {
+			return nil, nil
+		}
+		answer := map[string]interface{}{}
+		err = json.Unmarshal([]byte(text), &answer)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the JSON of %s %s", kind, name)
+		}
+		return answer, nil
+	}
+}
+
+// loadDesiredResources loads the resources rendered into the given directory ignoring any helm hooks
+func loadDesiredResources(dir string) ([]map[string]interface{}, error) {
+	answer := []map[string]interface{}{}
+	err := filepath.Walk(dir, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if f.IsDir() || filepath.Ext(path) != ".yaml" {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load file %s", path)
+		}
+		for _, doc := range helm.SplitYamlDocuments(string(data)) {
+			jsonData, err := yaml.YAMLToJSON([]byte(doc))
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse YAML of file %s", path)
+			}
+			resource := map[string]interface{}{}
+			err = json.Unmarshal(jsonData, &resource)
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse YAML of file %s", path)
+			}
+			if len(resource) == 0 {
+				continue
+			}
+			metadata, _ := resource["metadata"].(map[string]interface{})
+			annotations, _ := metadata["annotations"].(map[string]interface{})
+			if annotations["helm.sh/hook"] != nil {
+				continue
+			}
+			answer = append(answer, resource)
+		}
+		return nil
+	})
+	return answer, err
+}
+
+// driftIssueTitle returns the title of the issue raised for drift in the environment
+func driftIssueTitle(envName string) string {
+	return fmt.Sprintf("Drift detected in environment %s", envName)
+}
+
+// driftIssueBody returns the markdown description of the drift in the environment
+func driftIssueBody(drift *EnvironmentDrift) string {
+	lines := []string{
+		fmt.Sprintf("The following resources in namespace `%s` differ from this repository:", drift.Namespace),
+		"",
+		"| Kind | Name | Status | Differences |",
+		"| --- | --- | --- | --- |",
+	}
+	for _, r := range drift.Resources {
+		lines = append(lines, fmt.Sprintf("| %s | %s | %s | %s |", r.Kind, r.Name, r.Status, strings.Join(r.Differences, "<br>")))
+	}
+	lines = append(lines, "", fmt.Sprintf("To restore the desired state run: `jx get env drift %s --reapply`", drift.Environment))
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// createDriftIssue raises an issue on the git repository of the environment unless an open one already exists
+func (o *CommonOptions) createDriftIssue(env *v1.Environment, drift *EnvironmentDrift) error {
+	gitURL := env.Spec.Source.URL
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		return err
+	}
+	provider, err := o.gitProviderForURL(gitURL, "user name to create issues")
+	if err != nil {
+		return err
+	}
+	title := driftIssueTitle(env.Name)
+	issues, err := provider.SearchIssues(gitInfo.Organisation, gitInfo.Name, "")
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		if issue.Title == title && (issue.State == nil || *issue.State == "open") {
+			log.Infof("Drift issue already open for environment %s at %s\n", util.ColorInfo(env.Name), util.ColorInfo(issue.URL))
+			return nil
+		}
+	}
+	issue, err := provider.CreateIssue(gitInfo.Organisation, gitInfo.Name, &gits.GitIssue{
+		Title: title,
+		Body:  driftIssueBody(drift),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the drift issue for environment %s", env.Name)
+	}
+	log.Infof("Created drift issue %s\n", util.ColorInfo(issue.URL))
+	return nil
+}
+
+// reapplyEnvironment reapplies the chart of the environment git repository to restore the desired state
+func (o *CommonOptions) reapplyEnvironment(drift *EnvironmentDrift) error {
+	log.Infof("Reapplying environment %s to namespace %s\n", util.ColorInfo(drift.Environment), util.ColorInfo(drift.Namespace))
+	o.Helm().SetCWD(drift.dir)
+	timeout := 600
+	return o.Helm().UpgradeChart(drift.dir, drift.releaseName, drift.Namespace, nil, true, &timeout, false, true, nil, nil)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDesiredResourcesIgnoresHooks(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-env-drift")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	templatesDir := filepath.Join(dir, "env", "templates")
+	require.NoError(t, os.MkdirAll(templatesDir, DefaultWritePermissions))
+	resources := `---
+apiVersion: v1
+kind: Service
+metadata:
+  name: cheese
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cheese-config
+`
+	hook := `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: cheese-hook
+  annotations:
+    helm.sh/hook: post-install
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(templatesDir, "resources.yaml"), []byte(resources), DefaultWritePermissions))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(templatesDir, "hook.yaml"), []byte(hook), DefaultWritePermissions))
+
+	desired, err := loadDesiredResources(dir)
+	require.NoError(t, err)
+	require.Len(t, desired, 2)
+	assert.Equal(t, "Service", desired[0]["kind"])
+	assert.Equal(t, "ConfigMap", desired[1]["kind"])
+}
+
+func TestDriftIssueBody(t *testing.T) {
+	t.Parallel()
+	drift := &EnvironmentDrift{
+		Environment: "staging",
+		Namespace:   "jx-staging",
+		Resources: []*kube.ResourceDrift{
+			{Kind: "Deployment", Name: "cheese", Status: kube.DriftStatusModified, Differences: []string{"spec.replicas", "metadata.labels.app"}},
+			{Kind: "ConfigMap", Name: "cheese-config", Status: kube.DriftStatusMissing},
+		},
+	}
+	body := driftIssueBody(drift)
+	assert.Contains(t, body, "| Deployment | cheese | Modified | spec.replicas<br>metadata.labels.app |")
+	assert.Contains(t, body, "| ConfigMap | cheese-config | Missing |  |")
+	assert.Contains(t, body, "jx get env drift staging --reapply")
+	assert.Equal(t, "Drift detected in environment staging", driftIssueTitle("staging"))
+}
@@ -122,6 +122,10 @@ func (o *GCPreviewsOptions) Run() error {
 
 			if strings.HasPrefix(lowerState, "clos") || strings.HasPrefix(lowerState, "merged") || strings.HasPrefix(lowerState, "superseded") || strings.HasPrefix(lowerState, "declined") {
 				// lets delete the preview environment
+				err = o.deletePreviewDependencies(&e)
+				if err != nil {
+					log.Warnf("%s\n", err)
+				}
 				deleteOpts := DeleteEnvOptions{
 					DeleteNamespace: true,
 					CommonOptions:   o.CommonOptions,
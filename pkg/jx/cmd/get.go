@@ -68,6 +68,7 @@ func NewCmdGet(f Factory, in terminal.FileReader, out terminal.FileWriter, errOu
 	cmd.AddCommand(NewCmdGetAddon(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetApplications(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetAWSInfo(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGetAudit(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetBranchPattern(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetBuild(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetBuildPack(f, in, out, errOut))
@@ -85,6 +86,7 @@ func NewCmdGet(f Factory, in terminal.FileReader, out terminal.FileWriter, errOu
 	cmd.AddCommand(NewCmdGetPostPreviewJob(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetPreview(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetQuickstartLocation(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGetQuickstarts(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetRelease(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetTeam(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetTeamRole(f, in, out, errOut))
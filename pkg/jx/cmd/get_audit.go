@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"io"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// GetAuditOptions containers the CLI options
+type GetAuditOptions struct {
+	GetOptions
+
+	Filter kube.AuditEventFilter
+	Since  time.Duration
+}
+
+var (
+	getAuditLong = templates.LongDesc(`
+		Display the audit trail of the promotions, approvals and changes to the Environments, roles and team settings
+		of the current team.
+
+		Use '-o yaml' to see the state of the changed resources before and after each change.
+`)
+
+	getAuditExample = templates.Examples(`
+		# Who promoted what to production and why
+		jx get audit --action promote --env production
+
+		# The changes made to Environments in the last day
+		jx get audit --kind Environment --since 24h
+
+		# The changes made by a user including the state before and after each change
+		jx get audit --actor jstrachan -o yaml
+	`)
+)
+
+// NewCmdGetAudit creates the new command for: jx get audit
+func NewCmdGetAudit(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetAuditOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "audit",
+		Short:   "Display the audit trail of promotions, approvals and changes to the team",
+		Aliases: []string{"audits", "auditevents"},
+		Long:    getAuditLong,
+		Example: getAuditExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Filter.Action, "action", "", "", "Only display changes of this kind such as create, update, delete, promote or approve")
+	cmd.Flags().StringVarP(&options.Filter.Kind, "kind", "k", "", "Only display changes to this kind of resource such as Environment, EnvironmentRoleBinding or TeamSettings")
+	cmd.Flags().StringVarP(&options.Filter.Name, "name", "n", "", "Only display changes to the resource with this name")
+	cmd.Flags().StringVarP(&options.Filter.Actor, "actor", "", "", "Only display changes made by this user")
+	cmd.Flags().StringVarP(&options.Filter.Environment, "env", "e", "", "Only display promotions to this Environment")
+	cmd.Flags().StringVarP(&options.Filter.Application, "app", "a", "", "Only display promotions of this application")
+	cmd.Flags().DurationVarP(&options.Since, "since", "s", 0, "Only display changes made within this duration such as 24h")
+
+	options.addGetFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *GetAuditOptions) Run() error {
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterAuditEventCRD(apisClient)
+	if err != nil {
+		return err
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	if o.Since > 0 {
+		o.Filter.Since = time.Now().Add(-o.Since)
+	}
+	events, err := kube.GetAuditEvents(jxClient, ns, &o.Filter)
+	if err != nil {
+		return err
+	}
+	if o.Output != "" {
+		list := &v1.AuditEventList{}
+		for _, event := range events {
+			list.Items = append(list.Items, *event)
+		}
+		return o.renderResult(list, o.Output)
+	}
+	if len(events) == 0 {
+		log.Info("No audit events found\n")
+		return nil
+	}
+
+	table := o.CreateTable()
+	table.AddRow("TIME", "ACTOR", "ACTION", "KIND", "NAME", "ENVIRONMENT", "VERSION", "REASON")
+	for _, event := range events {
+		spec := &event.Spec
+		timestamp := ""
+		if spec.Timestamp != nil {
+			timestamp = spec.Timestamp.Format(time.RFC3339)
+		}
+		table.AddRow(timestamp, spec.Actor, string(spec.Action), spec.Kind, spec.Name, spec.Environment, spec.Version, spec.Reason)
+	}
+	table.Render()
+	return nil
+}
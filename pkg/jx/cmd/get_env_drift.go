@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// GetEnvDriftOptions containers the CLI options
+type GetEnvDriftOptions struct {
+	GetOptions
+
+	CreateIssue bool
+	Reapply     bool
+}
+
+var (
+	getEnvDriftLong = templates.LongDesc(`
+		Displays the resources in the Environment namespaces which differ from the Environment git repositories.
+
+		The chart in each Environment git repository is rendered and compared with the live resources so that any
+		manual changes made via 'kubectl edit' or 'kubectl apply' are reported.
+`)
+
+	getEnvDriftExample = templates.Examples(`
+		# Display the drift of all the permanent environments
+		jx get env drift
+
+		# Display the drift of the staging environment and raise an issue on its git repository if there is any
+		jx get env drift staging --issue
+
+		# Restore the desired state of the staging environment from its git repository
+		jx get env drift staging --reapply
+	`)
+)
+
+// NewCmdGetEnvDrift creates the new command for: jx get env drift
+func NewCmdGetEnvDrift(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetEnvDriftOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "drift [environment]",
+		Short:   "Displays the differences between the Environment git repositories and the cluster",
+		Long:    getEnvDriftLong,
+		Example: getEnvDriftExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addGetFlags(cmd)
+	cmd.Flags().BoolVarP(&options.CreateIssue, "issue", "", false, "Raises an issue on the Environment git repository if any drift is detected")
+	cmd.Flags().BoolVarP(&options.Reapply, "reapply", "", false, "Reapplies the Environment git repository if any drift is detected")
+	return cmd
+}
+
+// Run implements this command
+func (o *GetEnvDriftOptions) Run() error {
+	envs, err := o.driftEnvironments(o.Args)
+	if err != nil {
+		return err
+	}
+	drifts := []*EnvironmentDrift{}
+	for _, env := range envs {
+		drift, err := o.detectEnvironmentDrift(env)
+		if err != nil {
+			return err
+		}
+		defer drift.removeWorkDir()
+		drifts = append(drifts, drift)
+
+		if len(drift.Resources) > 0 {
+			if o.CreateIssue {
+				err = o.createDriftIssue(env, drift)
+				if err != nil {
+					return err
+				}
+			}
+			if o.Reapply {
+				err = o.reapplyEnvironment(drift)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if o.Output != "" {
+		return o.renderResult(drifts, o.Output)
+	}
+	table := o.CreateTable()
+	table.AddRow("ENV", "KIND", "NAME", "STATUS", "DIFFERENCES")
+	count := 0
+	for _, drift := range drifts {
+		for _, r := range drift.Resources {
+			table.AddRow(drift.Environment, r.Kind, r.Name, r.Status, r.DriftSummary())
+			count++
+		}
+	}
+	if count == 0 {
+		log.Infof("No drift detected\n")
+		return nil
+	}
+	table.Render()
+	return nil
+}
+
+// driftEnvironments returns the environments with the given names or all the permanent environments with a git
+// repository if no names are given
+func (o *CommonOptions) driftEnvironments(names []string) ([]*v1.Environment, error) {
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return nil, err
+	}
+	envMap, envNames, err := kube.GetOrderedEnvironments(jxClient, ns)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*v1.Environment{}
+	if len(names) > 0 {
+		for _, name := range names {
+			env := envMap[name]
+			if env == nil {
+				return nil, util.InvalidArg(name, envNames)
+			}
+			answer = append(answer, env)
+		}
+		return answer, nil
+	}
+	for _, name := range envNames {
+		env := envMap[name]
+		if env != nil && env.Spec.Kind == v1.EnvironmentKindTypePermanent && env.Spec.Source.URL != "" {
+			answer = append(answer, env)
+		}
+	}
+	if len(answer) == 0 {
+		return nil, fmt.Errorf("No permanent Environments with a git repository found in namespace %s", ns)
+	}
+	return answer, nil
+}
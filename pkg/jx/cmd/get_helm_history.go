@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// GetHelmHistoryOptions containers the CLI options
+type GetHelmHistoryOptions struct {
+	GetOptions
+
+	Namespace string
+}
+
+var (
+	getHelmHistoryLong = templates.LongDesc(`
+		Display the revisions of a helm release.
+
+		When using helm template mode the revisions are stored in ConfigMaps or Secrets in the namespace of the release
+`)
+
+	getHelmHistoryExample = templates.Examples(`
+		# List the revisions of the jenkins-x release
+		jx get helm history jenkins-x
+
+		# List the revisions of a release in a given namespace as YAML
+		jx get helm history jx-staging -n jx-staging -o yaml
+	`)
+)
+
+// NewCmdGetHelmHistory creates the new command for: jx get helm history
+func NewCmdGetHelmHistory(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetHelmHistoryOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "history [release]",
+		Short:   "Display the revisions of a helm release",
+		Long:    getHelmHistoryLong,
+		Example: getHelmHistoryExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addGetFlags(cmd)
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace of the release or defaults to the current namespace")
+	return cmd
+}
+
+// Run implements this command
+func (o *GetHelmHistoryOptions) Run() error {
+	if len(o.Args) == 0 {
+		return fmt.Errorf("Missing release name argument")
+	}
+	releaseName := o.Args[0]
+	ns := o.Namespace
+	if ns == "" {
+		_, curNs, err := o.KubeClient()
+		if err != nil {
+			return err
+		}
+		ns = curNs
+	}
+	history, err := o.Helm().ReleaseHistory(ns, releaseName)
+	if err != nil {
+		return err
+	}
+	if o.Output != "" {
+		return o.renderResult(history, o.Output)
+	}
+	if len(history) == 0 {
+		log.Infof("No revisions found for release %s in namespace %s\n", util.ColorInfo(releaseName), util.ColorInfo(ns))
+		return nil
+	}
+	table := o.CreateTable()
+	table.AddRow("REVISION", "UPDATED", "STATUS", "CHART", "VERSION", "DESCRIPTION")
+	for _, r := range history {
+		updated := ""
+		if !r.Updated.IsZero() {
+			updated = r.Updated.Format(time.ANSIC)
+		}
+		table.AddRow(strconv.Itoa(r.Revision), updated, r.Status, r.Chart, r.Version, r.Description)
+	}
+	table.Render()
+	return nil
+}
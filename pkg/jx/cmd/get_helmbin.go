@@ -61,6 +61,7 @@ func NewCmdGetHelmBin(f Factory, in terminal.FileReader, out terminal.FileWriter
 	}
 
 	options.addGetFlags(cmd)
+	cmd.AddCommand(NewCmdGetHelmHistory(f, in, out, errOut))
 	return cmd
 }
 
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/quickstarts"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// GetQuickstartsOptions containers the CLI options
+type GetQuickstartsOptions struct {
+	GetOptions
+	QuickstartLoadOptions
+
+	Filter quickstarts.QuickstartFilter
+}
+
+var (
+	getQuickstartsLong = templates.LongDesc(`
+		Display the quickstarts of the current team with the most popular first.
+
+		Any arguments are used to search the names, descriptions, languages, frameworks and tags of the quickstarts.
+
+		The quickstarts are loaded from the catalog of the team created by 'jx step quickstart index' if there is one.
+
+		For more documentation see: [https://jenkins-x.io/developing/create-quickstart/](https://jenkins-x.io/developing/create-quickstart/)
+
+`)
+
+	getQuickstartsExample = templates.Examples(`
+		# List all the quickstarts
+		jx get quickstarts
+
+		# Search for REST quickstarts written in go
+		jx get quickstarts rest --language go
+
+		# List the quickstarts with a tag as JSON
+		jx get quickstarts -t spring -o json
+	`)
+)
+
+// NewCmdGetQuickstarts creates the new command for: jx get quickstarts
+func NewCmdGetQuickstarts(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetQuickstartsOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "quickstarts [text]",
+		Short:   "Display the quickstarts of the team",
+		Aliases: []string{"quickstart", "qs"},
+		Long:    getQuickstartsLong,
+		Example: getQuickstartsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addQuickstartLoadFlags(cmd)
+	cmd.Flags().StringArrayVarP(&options.Filter.Tags, "tag", "t", []string{}, "Only display the quickstarts with these tags")
+	cmd.Flags().StringVarP(&options.Filter.Owner, "owner", "", "", "Only display the quickstarts of this owner")
+	cmd.Flags().StringVarP(&options.Filter.Language, "language", "l", "", "Only display the quickstarts in this language")
+	cmd.Flags().StringVarP(&options.Filter.Framework, "framework", "", "", "Only display the quickstarts using this framework")
+
+	options.addGetFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *GetQuickstartsOptions) Run() error {
+	model, err := o.loadQuickstartModel(&o.QuickstartLoadOptions)
+	if err != nil {
+		return err
+	}
+	o.Filter.Text = strings.Join(o.Args, " ")
+	results := model.Filter(&o.Filter)
+	quickstarts.SortByPopularity(results)
+
+	if o.Output != "" {
+		return o.renderResult(results, o.Output)
+	}
+	if len(results) == 0 {
+		log.Info("No quickstarts found\n")
+		return nil
+	}
+
+	table := o.CreateTable()
+	table.AddRow("NAME", "LANGUAGE", "FRAMEWORK", "STARS", "TAGS", "DESCRIPTION")
+	for _, q := range results {
+		table.AddRow(q.SurveyName(), q.Language, q.Framework, strconv.Itoa(q.Stars), strings.Join(q.Tags, ", "), q.Description)
+	}
+	table.Render()
+	return nil
+}
@@ -119,10 +119,10 @@ There are no pending Teams yet. Try create one via: jx create team --pending
 	}
 
 	table := o.CreateTable()
-	table.AddRow("NAME", "STATUS", "KIND", "MEMBERS")
+	table.AddRow("NAME", "STATUS", "KIND", "MEMBERS", "MESSAGE")
 	for _, team := range teams {
 		spec := &team.Spec
-		table.AddRow(team.Name, string(team.Status.ProvisionStatus), string(spec.Kind), strings.Join(spec.Members, ", "))
+		table.AddRow(team.Name, string(team.Status.ProvisionStatus), string(spec.Kind), strings.Join(spec.Members, ", "), team.Status.Message)
 	}
 	table.Render()
 	return nil
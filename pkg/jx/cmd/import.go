@@ -53,6 +53,9 @@ const (
 
 	minimumMavenDeployVersion = "2.8.2"
 
+	// importDeployablesDepth how deep to look in the folders of a project for deployables
+	importDeployablesDepth = 2
+
 	defaultGitIgnoreFile = `
 .project
 .classpath
@@ -114,6 +117,10 @@ var (
 	    Or you can use '--dir' to specify a directory to import.
 
 	    You can specify the git URL as an argument.
+
+		A single pipeline is created for the repository using the build pack detected for the project. If the project
+		has several deployables, such as a backend and a frontend in different folders, only the deployable of that
+		build pack is built. Use 'jx step buildpack detect' to see the build pack of each deployable.
 	    
 		For more documentation see: [https://jenkins-x.io/developing/import/](https://jenkins-x.io/developing/import/)
 	    
@@ -439,6 +446,56 @@ func (options *ImportOptions) ImportProjectsFromGitHub() error {
 	return nil
 }
 
+// detectPack scores the build packs against the build files of the project returning the directory of the best
+// pack. Falls back to the draft language detection if no pack matches
+func (options *ImportOptions) detectPack(packsDir string, draftHome draftpath.Home, dir string) (string, error) {
+	detector, err := jxdraft.NewPackDetector(packsDir)
+	if err != nil {
+		return "", err
+	}
+	scores, err := detector.Detect(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(scores) == 0 {
+		// pack detection time
+		return jxdraft.DoPackDetection(draftHome, options.Out, dir)
+	}
+	log.Infof("Detected build pack %s\n", scores[0].Explain())
+	for _, score := range scores[1:] {
+		options.Debugf("Also considered build pack %s\n", score.Explain())
+	}
+
+	deployables, err := detector.DetectDeployables(dir, importDeployablesDepth)
+	if err != nil {
+		log.Warnf("Failed to detect the deployables in %s: %s\n", dir, err)
+	} else if len(deployables) > 1 {
+		err = options.confirmMultipleDeployables(dir, scores[0], deployables)
+		if err != nil {
+			return "", err
+		}
+	}
+	return scores[0].Dir, nil
+}
+
+// confirmMultipleDeployables warns that only one pipeline is created for a project with multiple deployables and
+// asks whether to carry on importing it with the chosen build pack unless in batch mode
+func (options *ImportOptions) confirmMultipleDeployables(dir string, pack *jxdraft.PackScore, deployables []*jxdraft.Deployable) error {
+	log.Warnf("Found %d deployables in %s:\n", len(deployables), dir)
+	for _, deployable := range deployables {
+		log.Warnf("  %s uses build pack %s\n", deployable.Dir, deployable.Pack.Explain())
+	}
+	log.Warnf("jx import creates a single pipeline for a repository so only the %s build pack is used. To build the other deployables move them into their own repositories and import those or add them to the generated pipeline\n", util.ColorInfo(pack.Name))
+	if options.BatchMode {
+		return nil
+	}
+	if !util.Confirm(fmt.Sprintf("Do you want to import %s using the %s build pack?", dir, pack.Name), true,
+		"Only the deployable the build pack was detected for will be built by the pipeline", options.In, options.Out, options.Err) {
+		return fmt.Errorf("import of %s cancelled as it has %d deployables. Run %s to see the build pack of each deployable", dir, len(deployables), util.ColorInfo("jx step buildpack detect"))
+	}
+	return nil
+}
+
 // DraftCreate creates a draft
 func (options *ImportOptions) DraftCreate() error {
 	draftDir, err := util.DraftDir()
@@ -467,10 +524,6 @@ func (options *ImportOptions) DraftCreate() error {
 		jenkinsfile = filepath.Join(dir, options.Jenkinsfile)
 		withRename = true
 	}
-	pomName := filepath.Join(dir, "pom.xml")
-	gradleName := filepath.Join(dir, "build.gradle")
-	jenkinsPluginsName := filepath.Join(dir, "plugins.txt")
-	packagerConfigName := filepath.Join(dir, "packager-config.yml")
 	lpack := ""
 	customDraftPack := options.DraftPack
 	if len(customDraftPack) == 0 {
@@ -497,41 +550,9 @@ func (options *ImportOptions) DraftCreate() error {
 	}
 
 	if len(lpack) == 0 {
-		if exists, err := util.FileExists(pomName); err == nil && exists {
-			pack, err := util.PomFlavour(pomName)
-			if err != nil {
-				return err
-			}
-			if len(pack) > 0 {
-				if pack == util.LIBERTY {
-					lpack = filepath.Join(packsDir, "liberty")
-				} else if pack == util.APPSERVER {
-					lpack = filepath.Join(packsDir, "appserver")
-				} else {
-					log.Warn("Do not know how to handle pack: " + pack)
-				}
-			} else {
-				lpack = filepath.Join(packsDir, "maven")
-			}
-
-			exists, _ = util.FileExists(lpack)
-			if !exists {
-				log.Warn("defaulting to maven pack")
-				lpack = filepath.Join(packsDir, "maven")
-			}
-		} else if exists, err := util.FileExists(gradleName); err == nil && exists {
-			lpack = filepath.Join(packsDir, "gradle")
-		} else if exists, err := util.FileExists(jenkinsPluginsName); err == nil && exists {
-			lpack = filepath.Join(packsDir, "jenkins")
-		} else if exists, err := util.FileExists(packagerConfigName); err == nil && exists {
-			lpack = filepath.Join(packsDir, "cwp")
-		} else {
-			// pack detection time
-			lpack, err = jxdraft.DoPackDetection(draftHome, options.Out, dir)
-
-			if err != nil {
-				return err
-			}
+		lpack, err = options.detectPack(packsDir, draftHome, dir)
+		if err != nil {
+			return err
 		}
 	}
 	log.Success("selected pack: " + lpack + "\n")
@@ -748,53 +769,43 @@ func (options *ImportOptions) CreateNewRemoteRepository() error {
 		// Make the invitation
 		err := options.GitProvider.AddCollaborator(options.PipelineUserName, details.Organisation, details.RepoName)
 		if err != nil {
-			return err
-		}
-
-		// If repo is put in an organisation that the pipeline user is not part of an invitation needs to be accepted.
-		// Create a new provider for the pipeline user
-		authConfig := authConfigSvc.Config()
-		if err != nil {
-			return err
-		}
-		pipelineUserAuth := authConfig.FindUserAuth(options.GitServer.URL, options.PipelineUserName)
-		if pipelineUserAuth == nil {
-			log.Warnf("Pipeline Git user credentials not found. %s will need to accept the invitation to collaborate\n"+
-				"on %s if %s is not part of %s.\n\n",
-				options.PipelineUserName, details.RepoName, options.PipelineUserName, details.Organisation)
+			log.Warnf("Failed to add the pipeline user %s as a collaborator to %s: %s\nPlease add %s as a collaborator to %s so that the pipelines can push to it.\n\n",
+				options.PipelineUserName, details.RepoName, err, options.PipelineUserName, details.RepoName)
 		} else {
-			pipelineServerAuth := authConfig.GetServer(authConfig.CurrentServer)
-			pipelineUserProvider, err := gits.CreateProvider(pipelineServerAuth, pipelineUserAuth, options.Git())
+			// If repo is put in an organisation that the pipeline user is not part of an invitation needs to be accepted.
+			// Create a new provider for the pipeline user
+			authConfig := authConfigSvc.Config()
 			if err != nil {
 				return err
 			}
-
-			// Get all invitations for the pipeline user
-			// Wrapped in retry to not immediately fail the quickstart creation if APIs are flaky.
-			f := func() error {
-				invites, _, err := pipelineUserProvider.ListInvitations()
+			pipelineUserAuth := authConfig.FindUserAuth(options.GitServer.URL, options.PipelineUserName)
+			if pipelineUserAuth == nil {
+				log.Warnf("Pipeline Git user credentials not found. %s will need to accept the invitation to collaborate\n"+
+					"on %s if %s is not part of %s.\n\n",
+					options.PipelineUserName, details.RepoName, options.PipelineUserName, details.Organisation)
+			} else {
+				pipelineServerAuth := authConfig.GetServer(authConfig.CurrentServer)
+				pipelineUserProvider, err := gits.CreateProvider(pipelineServerAuth, pipelineUserAuth, options.Git())
 				if err != nil {
 					return err
 				}
-				for _, x := range invites {
-					// Accept all invitations for the pipeline user
-					_, err = pipelineUserProvider.AcceptInvitation(*x.ID)
-					if err != nil {
-						return err
-					}
+
+				// Get all invitations for the pipeline user
+				// Wrapped in retry to not immediately fail the quickstart creation if APIs are flaky.
+				f := func() error {
+					_, err := acceptGitInvitations(pipelineUserProvider)
+					return err
+				}
+				exponentialBackOff := backoff.NewExponentialBackOff()
+				timeout := 20 * time.Second
+				exponentialBackOff.MaxElapsedTime = timeout
+				exponentialBackOff.Reset()
+				err = backoff.Retry(f, exponentialBackOff)
+				if err != nil {
+					return err
 				}
-				return nil
-			}
-			exponentialBackOff := backoff.NewExponentialBackOff()
-			timeout := 20 * time.Second
-			exponentialBackOff.MaxElapsedTime = timeout
-			exponentialBackOff.Reset()
-			err = backoff.Retry(f, exponentialBackOff)
-			if err != nil {
-				return err
 			}
 		}
-
 	}
 
 	return nil
@@ -993,6 +1004,12 @@ func (options *ImportOptions) doImport() error {
 		return err
 	}
 
+	teamSettings, err := options.TeamSettings()
+	if err != nil {
+		return err
+	}
+	options.protectBranch(teamSettings, gitURL, gitProvider)
+
 	isProw, err := options.isProw()
 	if err != nil {
 		return err
@@ -53,6 +53,7 @@ type InitFlags struct {
 	HelmBin                    string
 	RecreateExistingDraftRepos bool
 	NoTiller                   bool
+	HelmReleaseStorage         string
 	RemoteTiller               bool
 	GlobalTiller               bool
 	SkipIngress                bool
@@ -133,6 +134,7 @@ func (options *InitOptions) addInitFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&options.Flags.GlobalTiller, "global-tiller", "", true, "Whether or not to use a cluster global tiller")
 	cmd.Flags().BoolVarP(&options.Flags.RemoteTiller, "remote-tiller", "", true, "If enabled and we are using tiller for helm then run tiller remotely in the kubernetes cluster. Otherwise we run the tiller process locally.")
 	cmd.Flags().BoolVarP(&options.Flags.NoTiller, "no-tiller", "", false, "Whether to disable the use of tiller with helm. If disabled we use 'helm template' to generate the YAML from helm charts then we use 'kubectl apply' to install it to avoid using tiller completely.")
+	cmd.Flags().StringVarP(&options.Flags.HelmReleaseStorage, "helm-release-storage", "", helm.ReleaseStorageConfigMaps, "Where the release history is stored when using --no-tiller: configmaps or secrets. Secrets should be used if the chart values contain credentials")
 	cmd.Flags().BoolVarP(&options.Flags.SkipIngress, "skip-ingress", "", false, "Don't install an ingress controller")
 	cmd.Flags().BoolVarP(&options.Flags.SkipTiller, "skip-tiller", "", false, "Don't install a Helm Tiller service")
 	cmd.Flags().BoolVarP(&options.Flags.Helm3, "helm3", "", false, "Use helm3 to install Jenkins X which does not use Tiller")
@@ -43,8 +43,9 @@ type InstallOptions struct {
 	CreateEnvOptions
 	config.AdminSecretsService
 
-	InitOptions InitOptions
-	Flags       InstallFlags
+	InitOptions  InitOptions
+	Flags        InstallFlags
+	Requirements *config.InstallRequirements
 }
 
 // InstallFlags flags for the install command
@@ -68,6 +69,8 @@ type InstallFlags struct {
 	Version                  string
 	Prow                     bool
 	DisableSetKubeContext    bool
+	RequirementsFile         string
+	ExportFile               string
 }
 
 // Secrets struct for secrets
@@ -116,6 +119,12 @@ var (
 
 		# If you know the cloud provider you can pass this as a CLI argument. E.g. for AWS
 		jx install --provider=aws
+
+		# Install without any prompts from a requirements file
+		jx install --file jx-requirements.yml
+
+		# Export the requirements of the current installation so that it can be reproduced
+		jx install --export jx-requirements.yml
 `)
 )
 
@@ -143,6 +152,7 @@ func NewCmdInstall(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 	options.addInstallFlags(cmd, false)
 
 	cmd.Flags().StringVarP(&options.Flags.Provider, "provider", "", "", "Cloud service providing the Kubernetes cluster.  Supported providers: "+KubernetesProviderOptions())
+	cmd.Flags().StringVarP(&options.Flags.ExportFile, "export", "", "", "Exports the requirements of the current installation to the file, or to the console if the file is '-', rather than installing")
 	return cmd
 }
 
@@ -218,6 +228,7 @@ func (options *InstallOptions) addInstallFlags(cmd *cobra.Command, includesInit
 	cmd.Flags().StringVarP(&flags.ExposeControllerPathMode, "exposecontroller-pathmode", "", "", "The ExposeController path mode for how services should be exposed as URLs. Defaults to using subnets. Use a value of `path` to use relative paths within the domain host such as when using AWS ELB host names")
 	cmd.Flags().StringVarP(&flags.Version, "version", "", "", "The specific platform version to install")
 	cmd.Flags().BoolVarP(&flags.Prow, "prow", "", false, "Enable prow")
+	cmd.Flags().StringVarP(&flags.RequirementsFile, "file", "", "", "The requirements file describing the installation such as "+config.RequirementsConfigFileName+". The installation runs without prompting and the values in the file take precedence over the flags")
 
 	addGitRepoOptionsArguments(cmd, &options.GitRepositoryOptions)
 	options.HelmValuesConfig.AddExposeControllerValues(cmd, true)
@@ -232,6 +243,16 @@ func (flags *InstallFlags) addCloudEnvOptions(cmd *cobra.Command) {
 
 // Run implements this command
 func (options *InstallOptions) Run() error {
+	if options.Flags.ExportFile != "" {
+		return options.exportRequirements()
+	}
+	if options.Flags.RequirementsFile != "" && options.Requirements == nil {
+		err := options.loadRequirementsFile()
+		if err != nil {
+			return err
+		}
+	}
+
 	client, originalNs, err := options.KubeClient()
 	if err != nil {
 		return errors.Wrap(err, "failed to create the kube client")
@@ -239,6 +260,10 @@ func (options *InstallOptions) Run() error {
 	options.KubeClientCached = client
 
 	initOpts := &options.InitOptions
+	err = helm.ValidateReleaseStorage(initOpts.Flags.HelmReleaseStorage)
+	if err != nil {
+		return err
+	}
 	helmBinary := initOpts.HelmBinary()
 
 	// configure the helm binary
@@ -247,7 +272,7 @@ func (options *InstallOptions) Run() error {
 		helmer := options.Helm()
 		helmCli, ok := helmer.(*helm.HelmCLI)
 		if ok && helmCli != nil {
-			options.helm = helm.NewHelmTemplate(helmCli, helmCli.CWD, client)
+			options.helm = helm.NewHelmTemplate(helmCli, helmCli.CWD, client, initOpts.Flags.HelmReleaseStorage)
 		} else {
 			helmTemplate, ok := helmer.(*helm.HelmTemplate)
 			if ok {
@@ -423,9 +448,14 @@ func (options *InstallOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	err = options.registerAuditEventCRD()
+	if err != nil {
+		return err
+	}
 	if initOpts.Flags.NoTiller {
 		callback := func(env *v1.Environment) error {
 			env.Spec.TeamSettings.HelmTemplate = true
+			env.Spec.TeamSettings.HelmReleaseStorage = initOpts.Flags.HelmReleaseStorage
 			log.Info("Enabling helm template mode in the TeamSettings\n")
 			return nil
 		}
@@ -750,14 +780,25 @@ func (options *InstallOptions) Run() error {
 		return errors.Wrap(err, "failed to load the addons configuration")
 	}
 
+	addons := []string{}
 	for _, ac := range addonConfig.Addons {
 		if ac.Enabled {
-			err = options.installAddon(ac.Name)
-			if err != nil {
-				return fmt.Errorf("failed to install addon %s: %s", ac.Name, err)
+			addons = append(addons, ac.Name)
+		}
+	}
+	if options.Requirements != nil {
+		for _, name := range options.Requirements.Addons {
+			if util.StringArrayIndex(addons, name) < 0 {
+				addons = append(addons, name)
 			}
 		}
 	}
+	for _, name := range addons {
+		err = options.installAddon(name)
+		if err != nil {
+			return fmt.Errorf("failed to install addon %s: %s", name, err)
+		}
+	}
 
 	options.logAdminPassword()
 
@@ -797,7 +838,12 @@ func (options *InstallOptions) Run() error {
 				options.Flags.DefaultEnvironmentPrefix = strings.ToLower(randomdata.SillyName())
 			}
 
-			log.Info("Creating default staging and production environments\n")
+			environments := options.requirementsEnvironments()
+			envNames := []string{}
+			for _, env := range environments {
+				envNames = append(envNames, env.Name)
+			}
+			log.Infof("Creating the environments %s\n", util.ColorInfo(strings.Join(envNames, ", ")))
 			// Common CreateEnv Options
 			options.CreateEnvOptions.GitRepositoryOptions = options.GitRepositoryOptions
 			options.CreateEnvOptions.GitRepositoryOptions.Owner = options.Flags.EnvironmentGitOwner
@@ -807,22 +853,25 @@ func (options *InstallOptions) Run() error {
 				options.CreateEnvOptions.BatchMode = options.BatchMode
 			}
 
-			options.CreateEnvOptions.Options.Name = "staging"
-			options.CreateEnvOptions.Options.Spec.Label = "Staging"
-			options.CreateEnvOptions.Options.Spec.Order = 100
-			err = options.CreateEnvOptions.Run()
-			if err != nil {
-				return errors.Wrapf(err, "failed to create staging environment in namespace %s", options.devNamespace)
-			}
-			options.CreateEnvOptions.Options.Name = "production"
-			options.CreateEnvOptions.Options.Spec.Label = "Production"
-			options.CreateEnvOptions.Options.Spec.Order = 200
-			options.CreateEnvOptions.Options.Spec.PromotionStrategy = v1.PromotionStrategyTypeManual
-			options.CreateEnvOptions.PromotionStrategy = string(v1.PromotionStrategyTypeManual)
+			for i, env := range environments {
+				promotionStrategy := env.PromotionStrategy
+				if promotionStrategy == "" {
+					promotionStrategy = string(v1.PromotionStrategyTypeAutomatic)
+				}
+				label := env.Label
+				if label == "" {
+					label = strings.Title(env.Name)
+				}
+				options.CreateEnvOptions.Options.Name = env.Name
+				options.CreateEnvOptions.Options.Spec.Label = label
+				options.CreateEnvOptions.Options.Spec.Order = int32(100 * (i + 1))
+				options.CreateEnvOptions.Options.Spec.PromotionStrategy = v1.PromotionStrategyType(promotionStrategy)
+				options.CreateEnvOptions.PromotionStrategy = promotionStrategy
 
-			err = options.CreateEnvOptions.Run()
-			if err != nil {
-				return errors.Wrapf(err, "failed to create the production environment in namespace %s", options.devNamespace)
+				err = options.CreateEnvOptions.Run()
+				if err != nil {
+					return errors.Wrapf(err, "failed to create the %s environment in namespace %s", env.Name, options.devNamespace)
+				}
 			}
 		}
 	}
@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// loadRequirementsFile loads the requirements file, checks it can be installed and applies it to the install options
+func (options *InstallOptions) loadRequirementsFile() error {
+	requirements, err := config.LoadInstallRequirements(options.Flags.RequirementsFile)
+	if err != nil {
+		return err
+	}
+	err = options.ApplyRequirements(requirements)
+	if err != nil {
+		return fmt.Errorf("invalid requirements file %s: %s", options.Flags.RequirementsFile, err)
+	}
+	err = options.verifyRequirementsGitAuth()
+	if err != nil {
+		return err
+	}
+	log.Infof("Installing Jenkins X from the requirements file %s\n", util.ColorInfo(options.Flags.RequirementsFile))
+	return nil
+}
+
+// ApplyRequirements applies the requirements to the install options so that the install runs without prompting.
+// Values in the requirements take precedence over the command line flags
+func (options *InstallOptions) ApplyRequirements(requirements *config.InstallRequirements) error {
+	provider := requirements.Cluster.Provider
+	if !util.Contains(KUBERNETES_PROVIDERS, provider) {
+		return fmt.Errorf("cluster.provider %s must be one of %s", provider, KubernetesProviderOptions())
+	}
+	if options.Flags.Provider != "" && options.Flags.Provider != provider {
+		return fmt.Errorf("cluster.provider %s does not match the %s cluster being installed into", provider, options.Flags.Provider)
+	}
+	for _, name := range requirements.Addons {
+		if name == kube.DefaultProwReleaseName || name == kube.DefaultKnativeBuildReleaseName {
+			return fmt.Errorf("addon %s is installed by using webhook: %s rather than as an addon", name, config.WebhookProw)
+		}
+		if _, ok := kube.AddonCharts[name]; !ok {
+			return fmt.Errorf("unknown addon %s. Available addons are: %s", name, strings.Join(util.SortedMapKeys(kube.AddonCharts), ", "))
+		}
+	}
+
+	flags := &options.Flags
+	flags.Provider = provider
+	if requirements.Cluster.Namespace != "" {
+		flags.Namespace = requirements.Cluster.Namespace
+	}
+	if requirements.Cluster.DockerRegistry != "" {
+		flags.DockerRegistry = requirements.Cluster.DockerRegistry
+	}
+
+	gitServer := requirements.GitServer
+	if gitServer.URL != "" {
+		options.GitRepositoryOptions.ServerURL = gitServer.URL
+	}
+	if gitServer.Username != "" {
+		options.GitRepositoryOptions.Username = gitServer.Username
+	}
+	if gitServer.Owner != "" {
+		flags.EnvironmentGitOwner = gitServer.Owner
+	}
+	options.GitRepositoryOptions.Private = options.GitRepositoryOptions.Private || gitServer.Private
+
+	flags.NoDefaultEnvironments = requirements.Environments != nil && len(requirements.Environments) == 0
+	if requirements.EnvironmentPrefix != "" {
+		flags.DefaultEnvironmentPrefix = requirements.EnvironmentPrefix
+	}
+
+	ingress := requirements.Ingress
+	if ingress.Domain != "" {
+		flags.Domain = ingress.Domain
+		options.InitOptions.Flags.Domain = ingress.Domain
+	}
+	flags.ExposeControllerPathMode = ingress.PathMode
+	helmConfig := &options.CreateEnvOptions.HelmValuesConfig
+	if helmConfig.ExposeController == nil {
+		helmConfig.ExposeController = &config.ExposeController{}
+	}
+	ecConfig := &helmConfig.ExposeController.Config
+	ecConfig.Domain = ingress.Domain
+	ecConfig.PathMode = ingress.PathMode
+	ecConfig.TLSAcme = strconv.FormatBool(ingress.TLS)
+	ecConfig.HTTP = strconv.FormatBool(!ingress.TLS)
+	if ingress.Exposer != "" {
+		ecConfig.Exposer = ingress.Exposer
+	} else if ecConfig.Exposer == "" {
+		ecConfig.Exposer = "Ingress"
+	}
+
+	initFlags := &options.InitOptions.Flags
+	initFlags.RemoteTiller = true
+	initFlags.NoTiller = false
+	initFlags.Helm3 = false
+	switch requirements.Helm {
+	case config.HelmModeLocalTiller:
+		initFlags.RemoteTiller = false
+	case config.HelmModeTemplate:
+		initFlags.NoTiller = true
+	case config.HelmModeHelm3:
+		initFlags.Helm3 = true
+	}
+
+	flags.Prow = requirements.Webhook == config.WebhookProw
+	if requirements.Version != "" {
+		flags.Version = requirements.Version
+	}
+
+	options.Requirements = requirements
+	options.BatchMode = true
+	options.InitOptions.BatchMode = true
+	return nil
+}
+
+// verifyRequirementsGitAuth fails early if there is no git user and API token to install with as the install cannot
+// prompt for them
+func (options *InstallOptions) verifyRequirementsGitAuth() error {
+	gitOptions := &options.GitRepositoryOptions
+	if gitOptions.ServerURL == "" {
+		gitOptions.ServerURL = gits.GitHubURL
+	}
+	if gitOptions.Username == "" {
+		gitOptions.Username = os.Getenv(JX_GIT_USER)
+	}
+	if gitOptions.ApiToken == "" {
+		gitOptions.ApiToken = os.Getenv(JX_GIT_TOKEN)
+	}
+	if gitOptions.Username != "" && gitOptions.ApiToken != "" {
+		return nil
+	}
+	authConfigSvc, err := options.CreateGitAuthConfigService()
+	if err != nil {
+		return errors.Wrap(err, "failed to load the git auth configuration")
+	}
+	userAuth := authConfigSvc.Config().FindUserAuth(gitOptions.ServerURL, gitOptions.Username)
+	if userAuth == nil || userAuth.IsInvalid() {
+		return fmt.Errorf("no git API token found for %s on %s. Set $%s and $%s, use --git-username and --git-api-token or run 'jx create git token'",
+			util.ColorInfo(gitOptions.Username), gitOptions.ServerURL, JX_GIT_USER, JX_GIT_TOKEN)
+	}
+	gitOptions.Username = userAuth.Username
+	gitOptions.ApiToken = userAuth.ApiToken
+	return nil
+}
+
+// requirementsEnvironments returns the permanent environments to create
+func (options *InstallOptions) requirementsEnvironments() []config.EnvironmentRequirements {
+	if options.Requirements != nil && options.Requirements.Environments != nil {
+		return options.Requirements.Environments
+	}
+	return config.DefaultEnvironments()
+}
+
+// exportRequirements writes the requirements of the current installation to the export file
+func (options *InstallOptions) exportRequirements() error {
+	requirements, err := options.installedRequirements()
+	if err != nil {
+		return err
+	}
+	err = requirements.Validate()
+	if err != nil {
+		log.Warnf("The exported requirements must be edited before they can be installed:\n%s\n", err)
+	}
+	fileName := options.Flags.ExportFile
+	if fileName == "-" {
+		data, err := yaml.Marshal(requirements)
+		if err != nil {
+			return err
+		}
+		_, err = options.Out.Write(data)
+		return err
+	}
+	err = requirements.SaveConfig(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save the requirements to %s", fileName)
+	}
+	log.Infof("Exported the requirements of the installation to %s\n", util.ColorInfo(fileName))
+	log.Infof("To reproduce the installation use: %s\n", util.ColorInfo("jx install --file "+fileName))
+	return nil
+}
+
+// installedRequirements loads the requirements of the installation in the current team
+func (options *InstallOptions) installedRequirements() (*config.InstallRequirements, error) {
+	kubeClient, _, err := options.KubeClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the kube client")
+	}
+	jxClient, ns, err := options.JXClientAndDevNamespace()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the jx client")
+	}
+	envMap, names, err := kube.GetOrderedEnvironments(jxClient, ns)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the environments in namespace %s", ns)
+	}
+	envs := []*v1.Environment{}
+	for _, name := range names {
+		envs = append(envs, envMap[name])
+	}
+	ingressConfig, err := kube.GetIngressConfig(kubeClient, ns)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the ingress configuration in namespace %s. Is Jenkins X installed?", ns)
+	}
+	dockerRegistry := ""
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(kube.ConfigMapJenkinsDockerRegistry, metav1.GetOptions{})
+	if err == nil {
+		dockerRegistry = cm.Data["docker.registry"]
+	}
+	releases, err := options.Helm().StatusReleases(ns)
+	if err != nil {
+		log.Warnf("Failed to find the installed addons: %s\n", err)
+	}
+	return InstalledRequirements(ns, envs, ingressConfig, dockerRegistry, releases)
+}
+
+// InstalledRequirements returns the requirements of an installation from its environments, ingress configuration,
+// Docker registry and helm releases
+func InstalledRequirements(ns string, envs []*v1.Environment, ingressConfig kube.IngressConfig, dockerRegistry string, releases map[string]string) (*config.InstallRequirements, error) {
+	var devEnv *v1.Environment
+	environments := []config.EnvironmentRequirements{}
+	prefix := ""
+	owner := ""
+	for _, env := range envs {
+		if env.Name == kube.LabelValueDevEnvironment || env.Spec.Kind == v1.EnvironmentKindTypeDevelopment {
+			devEnv = env
+			continue
+		}
+		if !env.Spec.Kind.IsPermanent() {
+			continue
+		}
+		environments = append(environments, config.EnvironmentRequirements{
+			Name:              env.Name,
+			Label:             env.Spec.Label,
+			PromotionStrategy: string(env.Spec.PromotionStrategy),
+		})
+		if env.Spec.Source.URL != "" {
+			gitInfo, err := gits.ParseGitURL(env.Spec.Source.URL)
+			if err == nil {
+				owner = gitInfo.Organisation
+				repoPrefix := strings.TrimSuffix(strings.TrimPrefix(gitInfo.Name, "environment-"), "-"+env.Name)
+				if prefix == "" && repoPrefix != gitInfo.Name {
+					prefix = repoPrefix
+				}
+			}
+		}
+	}
+	if devEnv == nil {
+		return nil, fmt.Errorf("no development environment found in namespace %s. Is Jenkins X installed?", ns)
+	}
+	settings := devEnv.Spec.TeamSettings
+	if settings.Organisation != "" {
+		owner = settings.Organisation
+	}
+
+	helmMode := config.HelmModeTiller
+	switch {
+	case settings.HelmBinary == "helm3":
+		helmMode = config.HelmModeHelm3
+	case settings.HelmTemplate:
+		helmMode = config.HelmModeTemplate
+	case settings.NoTiller:
+		helmMode = config.HelmModeLocalTiller
+	}
+	webhook := config.WebhookJenkins
+	if devEnv.Spec.WebHookEngine == v1.WebHookEngineProw {
+		webhook = config.WebhookProw
+	}
+
+	addons := []string{}
+	for name := range kube.AddonCharts {
+		if name == kube.DefaultProwReleaseName || name == kube.DefaultKnativeBuildReleaseName {
+			continue
+		}
+		releaseName := name
+		if name == "gitea" {
+			releaseName = defaultGiteaReleaseName
+		}
+		if _, ok := releases[releaseName]; ok {
+			addons = append(addons, name)
+		}
+	}
+	sort.Strings(addons)
+
+	requirements := &config.InstallRequirements{
+		Cluster: config.ClusterRequirements{
+			Provider:       settings.KubeProvider,
+			Namespace:      ns,
+			DockerRegistry: dockerRegistry,
+		},
+		GitServer: config.GitServerRequirements{
+			URL:      settings.GitServer,
+			Username: settings.PipelineUsername,
+			Owner:    owner,
+			Private:  settings.GitPrivate,
+		},
+		Environments:      environments,
+		EnvironmentPrefix: prefix,
+		Ingress: config.IngressRequirements{
+			Domain:  ingressConfig.Domain,
+			TLS:     ingressConfig.TLS,
+			Exposer: ingressConfig.Exposer,
+		},
+		Helm:    helmMode,
+		Webhook: webhook,
+		Addons:  addons,
+	}
+	return requirements, nil
+}
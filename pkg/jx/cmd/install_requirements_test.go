@@ -0,0 +1,152 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyRequirements(t *testing.T) {
+	t.Parallel()
+	options := cmd.CreateInstallOptions(nil, nil, nil, nil)
+	requirements := &config.InstallRequirements{
+		Cluster: config.ClusterRequirements{
+			Provider:       "gke",
+			Namespace:      "cheese",
+			DockerRegistry: "gcr.io",
+		},
+		GitServer: config.GitServerRequirements{
+			URL:      "https://github.com",
+			Username: "jenkins-x-bot",
+			Owner:    "acme",
+		},
+		Environments:      []config.EnvironmentRequirements{},
+		EnvironmentPrefix: "acme",
+		Ingress: config.IngressRequirements{
+			Domain: "acme.io",
+			TLS:    true,
+		},
+		Helm:    config.HelmModeHelm3,
+		Webhook: config.WebhookProw,
+		Addons:  []string{"gitea"},
+		Version: "1.3.500",
+	}
+
+	err := options.ApplyRequirements(requirements)
+	require.NoError(t, err)
+	assert.True(t, options.BatchMode)
+	assert.Equal(t, "gke", options.Flags.Provider)
+	assert.Equal(t, "cheese", options.Flags.Namespace)
+	assert.Equal(t, "gcr.io", options.Flags.DockerRegistry)
+	assert.Equal(t, "https://github.com", options.GitRepositoryOptions.ServerURL)
+	assert.Equal(t, "jenkins-x-bot", options.GitRepositoryOptions.Username)
+	assert.Equal(t, "acme", options.Flags.EnvironmentGitOwner)
+	assert.True(t, options.Flags.NoDefaultEnvironments)
+	assert.Equal(t, "acme", options.Flags.DefaultEnvironmentPrefix)
+	assert.Equal(t, "acme.io", options.Flags.Domain)
+	assert.Equal(t, "acme.io", options.InitOptions.Flags.Domain)
+	ecConfig := options.CreateEnvOptions.HelmValuesConfig.ExposeController.Config
+	assert.Equal(t, "true", ecConfig.TLSAcme)
+	assert.Equal(t, "false", ecConfig.HTTP)
+	assert.Equal(t, "Ingress", ecConfig.Exposer)
+	assert.True(t, options.InitOptions.Flags.Helm3)
+	assert.Equal(t, "helm3", options.InitOptions.HelmBinary())
+	assert.True(t, options.Flags.Prow)
+	assert.Equal(t, "1.3.500", options.Flags.Version)
+}
+
+func TestApplyRequirementsFails(t *testing.T) {
+	t.Parallel()
+	options := cmd.CreateInstallOptions(nil, nil, nil, nil)
+	err := options.ApplyRequirements(&config.InstallRequirements{
+		Cluster: config.ClusterRequirements{Provider: "digitalocean"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cluster.provider digitalocean must be one of")
+
+	options.Flags.Provider = "eks"
+	err = options.ApplyRequirements(&config.InstallRequirements{
+		Cluster: config.ClusterRequirements{Provider: "gke"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match the eks cluster")
+
+	err = options.ApplyRequirements(&config.InstallRequirements{
+		Cluster: config.ClusterRequirements{Provider: "eks"},
+		Addons:  []string{"cheese"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown addon cheese")
+
+	err = options.ApplyRequirements(&config.InstallRequirements{
+		Cluster: config.ClusterRequirements{Provider: "eks"},
+		Addons:  []string{kube.DefaultProwReleaseName},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "webhook: prow")
+}
+
+func TestInstalledRequirements(t *testing.T) {
+	t.Parallel()
+	dev := kube.NewPermanentEnvironment("dev")
+	dev.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	dev.Spec.WebHookEngine = v1.WebHookEngineProw
+	dev.Spec.TeamSettings = v1.TeamSettings{
+		KubeProvider:     "eks",
+		GitServer:        "https://github.com",
+		PipelineUsername: "jenkins-x-bot",
+		HelmTemplate:     true,
+	}
+	staging := newRequirementsEnvironment("staging", "Staging", v1.PromotionStrategyTypeAutomatic)
+	production := newRequirementsEnvironment("production", "Production", v1.PromotionStrategyTypeManual)
+	preview := newRequirementsEnvironment("acme-pr-1", "PR-1", v1.PromotionStrategyTypeAutomatic)
+	preview.Spec.Kind = v1.EnvironmentKindTypePreview
+
+	requirements, err := cmd.InstalledRequirements("jx", []*v1.Environment{dev, staging, production, preview},
+		kube.IngressConfig{Domain: "acme.io", TLS: true, Exposer: "Ingress"}, "1234.dkr.ecr.eu-west-1.amazonaws.com",
+		map[string]string{"jenkins-x": "DEPLOYED", "gitea": "DEPLOYED", kube.DefaultProwReleaseName: "DEPLOYED"})
+	require.NoError(t, err)
+	require.NoError(t, requirements.Validate())
+
+	assert.Equal(t, "eks", requirements.Cluster.Provider)
+	assert.Equal(t, "jx", requirements.Cluster.Namespace)
+	assert.Equal(t, "1234.dkr.ecr.eu-west-1.amazonaws.com", requirements.Cluster.DockerRegistry)
+	assert.Equal(t, "https://github.com", requirements.GitServer.URL)
+	assert.Equal(t, "jenkins-x-bot", requirements.GitServer.Username)
+	assert.Equal(t, "acme", requirements.GitServer.Owner)
+	assert.Equal(t, "cheese", requirements.EnvironmentPrefix)
+	assert.Equal(t, []config.EnvironmentRequirements{
+		{Name: "staging", Label: "Staging", PromotionStrategy: "Auto"},
+		{Name: "production", Label: "Production", PromotionStrategy: "Manual"},
+	}, requirements.Environments)
+	assert.Equal(t, config.IngressRequirements{Domain: "acme.io", TLS: true, Exposer: "Ingress"}, requirements.Ingress)
+	assert.Equal(t, config.HelmModeTemplate, requirements.Helm)
+	assert.Equal(t, config.WebhookProw, requirements.Webhook)
+	assert.Equal(t, []string{"gitea"}, requirements.Addons)
+
+	_, err = cmd.InstalledRequirements("jx", []*v1.Environment{staging}, kube.IngressConfig{}, "", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no development environment")
+}
+
+func newRequirementsEnvironment(name string, label string, promotionStrategy v1.PromotionStrategyType) *v1.Environment {
+	return &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1.EnvironmentSpec{
+			Label:             label,
+			Kind:              v1.EnvironmentKindTypePermanent,
+			PromotionStrategy: promotionStrategy,
+			Source: v1.EnvironmentRepository{
+				URL: "https://github.com/acme/environment-cheese-" + name + ".git",
+			},
+		},
+	}
+}
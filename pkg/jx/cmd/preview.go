@@ -31,6 +31,8 @@ var (
 
 		For more documentation on Preview Environments see: [https://jenkins-x.io/about/features/#preview-environments](https://jenkins-x.io/about/features/#preview-environments)
 
+		Other charts the application depends on, such as other microservices or databases, can be deployed into the Preview Environment by declaring them in the 'previewEnvironments.dependencies' section of the jenkins-x.yml file. Each dependency can either pin its chart version or use the version currently deployed in an environment (staging by default) and can use a seed Job to populate it with data, such as restoring a database snapshot.
+
 `)
 
 	previewExample = templates.Examples(`
@@ -212,6 +214,8 @@ func (o *PreviewOptions) Run() error {
 	buildStatusUrl := ""
 
 	var pullRequest *gits.GitPullRequest
+	var gitProvider gits.GitProvider
+	commitSha := ""
 
 	if o.GitInfo != nil {
 		gitKind, err := o.GitServerKind(o.GitInfo)
@@ -219,7 +223,7 @@ func (o *PreviewOptions) Run() error {
 			return err
 		}
 
-		gitProvider, err := o.GitInfo.CreateProvider(authConfigSvc, gitKind, o.Git())
+		gitProvider, err = o.GitInfo.CreateProvider(authConfigSvc, gitKind, o.Git())
 		if err != nil {
 			return fmt.Errorf("cannot create Git provider %v", err)
 		}
@@ -270,6 +274,9 @@ func (o *PreviewOptions) Run() error {
 				}
 			}
 
+			if pullRequest != nil {
+				commitSha = pullRequest.LastCommitSha
+			}
 			statuses, err := gitProvider.ListCommitStatus(o.GitInfo.Organisation, o.GitInfo.Name, pullRequest.LastCommitSha)
 
 			if err != nil {
@@ -412,6 +419,11 @@ func (o *PreviewOptions) Run() error {
 		return err
 	}
 
+	err = o.deployPreviewDependencies(jxClient, kubeClient, ns, env)
+	if err != nil {
+		return err
+	}
+
 	if o.ReleaseName == "" {
 		o.ReleaseName = o.Namespace
 	}
@@ -462,8 +474,10 @@ func (o *PreviewOptions) Run() error {
 		return err
 	}
 
+	o.createPreviewCommitStatus(gitProvider, commitSha, "pending", "Deploying the Preview Environment", "")
 	err = o.Helm().UpgradeChart(".", o.ReleaseName, o.Namespace, nil, true, nil, true, true, nil, []string{configFileName})
 	if err != nil {
+		o.createPreviewCommitStatus(gitProvider, commitSha, "failure", "Failed to deploy the Preview Environment", "")
 		return err
 	}
 
@@ -481,6 +495,8 @@ func (o *PreviewOptions) Run() error {
 		log.Warnf("Could not find the service URL in namespace %s for names %s\n", o.Namespace, strings.Join(appNames, ", "))
 	}
 
+	o.createPreviewCommitStatus(gitProvider, commitSha, "success", "The Preview Environment is available", url)
+
 	comment := fmt.Sprintf(":star: PR built and available in a preview environment **%s**", o.Name)
 	if url != "" {
 		comment += fmt.Sprintf(" [here](%s) ", url)
@@ -563,6 +579,23 @@ func (o *PreviewOptions) Run() error {
 	return o.RunPostPreviewSteps(kubeClient, o.Namespace, url, pipeline, build)
 }
 
+// createPreviewCommitStatus creates a status for the preview on the last commit of the Pull Request.
+// Failures are only logged as the status is informational and should not fail the preview
+func (o *PreviewOptions) createPreviewCommitStatus(provider gits.GitProvider, sha string, state string, description string, targetURL string) {
+	if o.NoCommitStatus || provider == nil || sha == "" {
+		return
+	}
+	err := o.createCommitStatus(provider, o.GitInfo.Organisation, o.GitInfo.Name, sha, &gits.GitRepoStatus{
+		State:       state,
+		Context:     commitStatusContextPreview,
+		Description: description,
+		TargetURL:   targetURL,
+	})
+	if err != nil {
+		log.Warnf("%s\n", err)
+	}
+}
+
 // RunPostPreviewSteps lets run any post-preview steps that are configured for all apps in a team
 func (o *PreviewOptions) RunPostPreviewSteps(kubeClient kubernetes.Interface, ns string, url string, pipeline string, build string) error {
 	teamSettings, err := o.TeamSettings()
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultPreviewDependencyEnvironment = "staging"
+	releasesChartRepositoryName         = "releases"
+)
+
+// deployPreviewDependencies deploys the dependencies declared in the 'previewEnvironments' section of the
+// jenkins-x.yml file into the preview environment and runs any seed jobs
+func (o *PreviewOptions) deployPreviewDependencies(jxClient versioned.Interface, kubeClient kubernetes.Interface, devNs string, env *v1.Environment) error {
+	projectConfig, _, err := config.LoadProjectConfig(o.Dir)
+	if err != nil {
+		return err
+	}
+	dependencies := []*config.PreviewDependency{}
+	if projectConfig.PreviewEnvironments != nil {
+		dependencies = projectConfig.PreviewEnvironments.Dependencies
+	}
+	previousReleases := previewDependencyReleases(env)
+	if len(dependencies) == 0 && len(previousReleases) == 0 {
+		return nil
+	}
+
+	releases := []string{}
+	for i, dep := range dependencies {
+		if dep.Name == "" {
+			return fmt.Errorf("Missing name for preview dependency %d in %s", i+1, config.ProjectConfigFileName)
+		}
+		version, err := o.resolvePreviewDependencyVersion(jxClient, devNs, dep)
+		if err != nil {
+			return err
+		}
+		chart, err := o.previewDependencyChart(dep)
+		if err != nil {
+			return err
+		}
+		releaseName := kube.ToValidName(o.Namespace + "-" + dep.ReleaseName())
+		log.Infof("Deploying preview dependency %s version %s as release %s\n", util.ColorInfo(chart), util.ColorInfo(version), util.ColorInfo(releaseName))
+		err = o.installChartOptions(InstallChartOptions{
+			ReleaseName: releaseName,
+			Chart:       chart,
+			Version:     version,
+			Ns:          o.Namespace,
+			HelmUpdate:  i == 0,
+			SetValues:   dep.Values,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to deploy preview dependency %s: %s", dep.Name, err)
+		}
+		releases = append(releases, releaseName)
+
+		// lets save as we go so that a failed deployment is still cleaned up
+		saveReleases := append([]string{}, releases...)
+		for _, release := range previousReleases {
+			if util.StringArrayIndex(saveReleases, release) < 0 {
+				saveReleases = append(saveReleases, release)
+			}
+		}
+		err = o.savePreviewDependencyReleases(jxClient, devNs, env.Name, saveReleases)
+		if err != nil {
+			return err
+		}
+		if dep.Seed != nil {
+			err = o.runPreviewSeedJob(kubeClient, dep.Seed, releaseName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// lets remove any dependencies which are no longer required
+	for _, release := range previousReleases {
+		if util.StringArrayIndex(releases, release) < 0 {
+			log.Infof("Removing preview dependency release %s\n", util.ColorInfo(release))
+			err = o.Helm().DeleteRelease(o.Namespace, release, true)
+			if err != nil {
+				log.Warnf("Failed to delete preview dependency release %s: %s\n", release, err)
+			}
+		}
+	}
+	return o.savePreviewDependencyReleases(jxClient, devNs, env.Name, releases)
+}
+
+// resolvePreviewDependencyVersion returns the pinned version of the dependency or the version currently deployed
+// in the dependency's environment
+func (o *PreviewOptions) resolvePreviewDependencyVersion(jxClient versioned.Interface, devNs string, dep *config.PreviewDependency) (string, error) {
+	if dep.Version != "" {
+		return dep.Version, nil
+	}
+	envName := dep.Environment
+	if envName == "" {
+		envName = defaultPreviewDependencyEnvironment
+	}
+	env, err := jxClient.JenkinsV1().Environments(devNs).Get(envName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("Could not find the Environment %s to find the version of preview dependency %s: %s", envName, dep.Name, err)
+	}
+	releases, err := kube.GetOrderedReleases(jxClient, env.Spec.Namespace, "")
+	if err != nil {
+		return "", err
+	}
+	answer := ""
+	var latest *semver.Version
+	for _, release := range releases {
+		if release.Spec.Name != dep.Name || release.Spec.Version == "" {
+			continue
+		}
+		version := strings.TrimPrefix(release.Spec.Version, "v")
+		sv, err := semver.Parse(version)
+		if err != nil {
+			if answer == "" {
+				answer = version
+			}
+			continue
+		}
+		if latest == nil || sv.GT(*latest) {
+			latest = &sv
+			answer = version
+		}
+	}
+	if answer != "" {
+		return answer, nil
+	}
+	return "", fmt.Errorf("No Release of %s found in the %s Environment. Please specify the version of the preview dependency in %s", dep.Name, envName, config.ProjectConfigFileName)
+}
+
+// previewDependencyChart returns the chart name prefixed by the helm repository, adding the repository if its missing
+func (o *PreviewOptions) previewDependencyChart(dep *config.PreviewDependency) (string, error) {
+	repoURL := dep.Repository
+	repoName := releasesChartRepositoryName
+	if repoURL == "" {
+		repoURL = o.releaseChartMuseumUrl()
+	} else {
+		u, err := url.Parse(repoURL)
+		if err != nil {
+			return "", fmt.Errorf("Invalid chart repository URL %s for preview dependency %s: %s", repoURL, dep.Name, err)
+		}
+		repoName = kube.ToValidName(u.Host + u.Path)
+	}
+	repos, err := o.Helm().ListRepos()
+	if err != nil {
+		return "", err
+	}
+	for name, u := range repos {
+		if strings.TrimSuffix(u, "/") == strings.TrimSuffix(repoURL, "/") {
+			return name + "/" + dep.Name, nil
+		}
+	}
+	err = o.Helm().AddRepo(repoName, repoURL)
+	if err != nil {
+		return "", fmt.Errorf("Failed to add the helm repository %s at %s: %s", repoName, repoURL, err)
+	}
+	return repoName + "/" + dep.Name, nil
+}
+
+// runPreviewSeedJob runs the seed Job for a dependency unless it has already completed successfully
+func (o *PreviewOptions) runPreviewSeedJob(kubeClient kubernetes.Interface, seed *config.PreviewSeed, releaseName string) error {
+	if seed.Image == "" {
+		return fmt.Errorf("Missing image for the seed of preview dependency %s", releaseName)
+	}
+	ns := o.Namespace
+	name := kube.ToValidName(releaseName + "-seed")
+	jobResources := kubeClient.BatchV1().Jobs(ns)
+	existing, err := jobResources.Get(name, metav1.GetOptions{})
+	if err == nil && existing != nil {
+		if kube.IsJobSucceeded(existing) {
+			log.Infof("Preview dependency %s has already been seeded by Job %s\n", util.ColorInfo(releaseName), util.ColorInfo(name))
+			return nil
+		}
+		propagationPolicy := metav1.DeletePropagationForeground
+		err = jobResources.Delete(name, &metav1.DeleteOptions{PropagationPolicy: &propagationPolicy})
+		if err != nil {
+			return err
+		}
+		gone := func() (bool, error) {
+			job, err := jobResources.Get(name, metav1.GetOptions{})
+			return job == nil || err != nil, nil
+		}
+		err = o.retryUntilTrueOrTimeout(time.Minute, time.Second, gone)
+		if err != nil {
+			return fmt.Errorf("Failed waiting for the previous seed Job %s to be deleted: %s", name, err)
+		}
+	}
+
+	env := []corev1.EnvVar{}
+	for _, envVar := range seed.Env {
+		env = append(env, envVar.ToEnvVar())
+	}
+	env = append(env,
+		corev1.EnvVar{Name: "JX_PREVIEW_NAMESPACE", Value: ns},
+		corev1.EnvVar{Name: "JX_DEPENDENCY_RELEASE", Value: releaseName},
+		corev1.EnvVar{Name: "JX_SEED_SNAPSHOT", Value: seed.Snapshot},
+	)
+	backoffLimit := int32(3)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels: map[string]string{
+				kube.LabelJobKind: kube.ValueJobKindPreviewSeed,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "seed",
+							Image:   seed.Image,
+							Command: seed.Command,
+							Args:    seed.Args,
+							Env:     env,
+						},
+					},
+				},
+			},
+		},
+	}
+	log.Infof("Seeding preview dependency %s with Job %s\n", util.ColorInfo(releaseName), util.ColorInfo(name))
+	createdJob, err := jobResources.Create(job)
+	if err != nil {
+		return err
+	}
+	return o.waitForJob(kubeClient, createdJob)
+}
+
+func (o *PreviewOptions) savePreviewDependencyReleases(jxClient versioned.Interface, devNs string, envName string, releases []string) error {
+	environments := jxClient.JenkinsV1().Environments(devNs)
+	env, err := environments.Get(envName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	value := strings.Join(releases, ",")
+	if env.Annotations[kube.AnnotationPreviewDependencies] == value {
+		return nil
+	}
+	if env.Annotations == nil {
+		env.Annotations = map[string]string{}
+	}
+	if value == "" {
+		delete(env.Annotations, kube.AnnotationPreviewDependencies)
+	} else {
+		env.Annotations[kube.AnnotationPreviewDependencies] = value
+	}
+	_, err = environments.Update(env)
+	if err != nil {
+		return fmt.Errorf("Failed to update Environment %s due to %s", envName, err)
+	}
+	return nil
+}
+
+// deletePreviewDependencies deletes the helm releases of the dependencies deployed into a preview environment
+func (o *CommonOptions) deletePreviewDependencies(env *v1.Environment) error {
+	for _, release := range previewDependencyReleases(env) {
+		log.Infof("Deleting preview dependency release %s\n", util.ColorInfo(release))
+		err := o.Helm().DeleteRelease(env.Spec.Namespace, release, true)
+		if err != nil {
+			return fmt.Errorf("Failed to delete preview dependency release %s: %s", release, err)
+		}
+	}
+	return nil
+}
+
+// previewDependencyReleases returns the helm releases of the dependencies deployed into the preview environment
+func previewDependencyReleases(env *v1.Environment) []string {
+	answer := []string{}
+	if env == nil || env.Annotations == nil {
+		return answer
+	}
+	for _, release := range strings.Split(env.Annotations[kube.AnnotationPreviewDependencies], ",") {
+		release = strings.TrimSpace(release)
+		if release != "" {
+			answer = append(answer, release)
+		}
+	}
+	return answer
+}
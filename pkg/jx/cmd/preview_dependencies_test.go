@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResolvePreviewDependencyVersion(t *testing.T) {
+	t.Parallel()
+	jxClient := fake.NewSimpleClientset(
+		&v1.Environment{
+			ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "jx"},
+			Spec:       v1.EnvironmentSpec{Namespace: "jx-staging"},
+		},
+		&v1.Release{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders-1.0.1", Namespace: "jx-staging"},
+			Spec:       v1.ReleaseSpec{Name: "orders", Version: "1.0.1"},
+		},
+		&v1.Release{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders-1.0.2", Namespace: "jx-staging"},
+			Spec:       v1.ReleaseSpec{Name: "orders", Version: "v1.0.2"},
+		},
+	)
+	o := &PreviewOptions{}
+
+	version, err := o.resolvePreviewDependencyVersion(jxClient, "jx", &config.PreviewDependency{Name: "orders"})
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.2", version)
+
+	version, err = o.resolvePreviewDependencyVersion(jxClient, "jx", &config.PreviewDependency{Name: "orders", Version: "0.9.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "0.9.0", version)
+
+	_, err = o.resolvePreviewDependencyVersion(jxClient, "jx", &config.PreviewDependency{Name: "payments"})
+	assert.Error(t, err)
+
+	_, err = o.resolvePreviewDependencyVersion(jxClient, "jx", &config.PreviewDependency{Name: "orders", Environment: "production"})
+	assert.Error(t, err)
+}
+
+func TestPreviewDependencyReleases(t *testing.T) {
+	t.Parallel()
+	env := &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				kube.AnnotationPreviewDependencies: "jx-pr-1-orders, jx-pr-1-postgresql,",
+			},
+		},
+	}
+	assert.Equal(t, []string{"jx-pr-1-orders", "jx-pr-1-postgresql"}, previewDependencyReleases(env))
+	assert.Empty(t, previewDependencyReleases(&v1.Environment{}))
+}
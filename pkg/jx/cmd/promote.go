@@ -56,6 +56,7 @@ type PromoteOptions struct {
 	NoPoll              bool
 	NoWaitAfterMerge    bool
 	IgnoreLocalFiles    bool
+	NoCommitStatus      bool
 	Timeout             string
 	PullRequestPollTime string
 	Filter              string
@@ -142,6 +143,7 @@ func NewCmdPromote(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 	}
 
 	options.addCommonFlags(cmd)
+	options.addAuditFlags(cmd)
 
 	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The Namespace to promote to")
 	cmd.Flags().StringVarP(&options.Environment, optionEnvironment, "e", "", "The Environment to promote to")
@@ -168,6 +170,7 @@ func (options *PromoteOptions) addPromoteOptions(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&options.NoPoll, "no-poll", "", false, "Disables polling for Pull Request or Pipeline status")
 	cmd.Flags().BoolVarP(&options.NoWaitAfterMerge, "no-wait", "", false, "Disables waiting for completing promotion after the Pull request is merged")
 	cmd.Flags().BoolVarP(&options.IgnoreLocalFiles, "ignore-local-file", "", false, "Ignores the local file system when deducing the Git repository")
+	cmd.Flags().BoolVarP(&options.NoCommitStatus, "no-commit-status", "", false, "Disables creating statuses on the git commit being promoted or previewed")
 }
 
 // Run implements this command
@@ -390,6 +393,11 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 		if source.URL != "" && env.Spec.Kind.IsPermanent() {
 			err := o.PromoteViaPullRequest(env, releaseInfo)
 			if err == nil {
+				prURL := ""
+				if pr := releaseInfo.PullRequestInfo; pr != nil && pr.PullRequest != nil {
+					prURL = pr.PullRequest.URL
+				}
+				o.createPromoteCommitStatus(env, promoteKey, "pending", "Waiting for the promotion Pull Request to merge", prURL)
 				startPromotePR := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromotePullRequestStep) error {
 					kube.StartPromotionPullRequest(a, s, ps, p)
 					pr := releaseInfo.PullRequestInfo
@@ -405,6 +413,7 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 				if err != nil {
 					log.Warnf("Failed to update PipelineActivity: %s\n", err)
 				}
+				o.auditPromotion(env, releaseInfo, prURL)
 				// lets sleep a little before we try poll for the PR status
 				time.Sleep(waitAfterPullRequestCreated)
 			}
@@ -436,17 +445,33 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 
 	err = o.Helm().UpgradeChart(fullAppName, releaseName, targetNS, &version, true, nil, false, true, nil, nil)
 	if err == nil {
+		releaseInfo.Version = version
+		o.auditPromotion(env, releaseInfo, "")
+		o.createPromoteCommitStatus(env, promoteKey, "success", fmt.Sprintf("Promoted to namespace %s", targetNS), "")
 		err = o.commentOnIssues(targetNS, env, promoteKey)
 		if err != nil {
 			log.Warnf("Failed to comment on issues for release %s: %s\n", releaseName, err)
 		}
 		err = promoteKey.OnPromoteUpdate(o.Activities, kube.CompletePromotionUpdate)
 	} else {
+		o.createPromoteCommitStatus(env, promoteKey, "failure", fmt.Sprintf("Failed to promote to namespace %s", targetNS), "")
 		err = promoteKey.OnPromoteUpdate(o.Activities, kube.FailedPromotionUpdate)
 	}
 	return releaseInfo, err
 }
 
+// auditPromotion records the promotion in the audit trail
+func (o *PromoteOptions) auditPromotion(env *v1.Environment, releaseInfo *ReleaseInfo, prURL string) {
+	event := kube.NewAuditEvent("", v1.AuditActionPromote, "Application", o.Application)
+	event.Spec.Application = o.Application
+	event.Spec.Version = releaseInfo.Version
+	event.Spec.PullRequestURL = prURL
+	if env != nil {
+		event.Spec.Environment = env.Name
+	}
+	o.audit(event)
+}
+
 func (o *PromoteOptions) PromoteViaPullRequest(env *v1.Environment, releaseInfo *ReleaseInfo) error {
 	version := o.Version
 	versionName := version
@@ -469,6 +494,7 @@ func (o *PromoteOptions) PromoteViaPullRequest(env *v1.Environment, releaseInfo
 			}
 		}
 		requirements.SetAppVersion(app, version, o.HelmRepositoryURL, o.Alias)
+		releaseInfo.Version = version
 		return nil
 	}
 	if o.FakePullRequests != nil {
@@ -549,6 +575,11 @@ func (o *PromoteOptions) WaitForPromotion(ns string, env *v1.Environment, releas
 		if err != nil {
 			// TODO based on if the PR completed or not fail the PR or the Promote?
 			promoteKey.OnPromotePullRequest(o.Activities, kube.FailedPromotionPullRequest)
+			prURL := ""
+			if pullRequestInfo.PullRequest != nil {
+				prURL = pullRequestInfo.PullRequest.URL
+			}
+			o.createPromoteCommitStatus(env, promoteKey, "failure", "The promotion failed", prURL)
 			return err
 		}
 	}
@@ -563,6 +594,7 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 	logHasMergeSha := false
 	logMergeStatusError := false
 	logNoMergeStatuses := false
+	mergedAutomatically := false
 	urlStatusMap := map[string]string{}
 	urlStatusTargetURLMap := map[string]string{}
 
@@ -592,6 +624,7 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 								return nil
 							}
 							promoteKey.OnPromotePullRequest(o.Activities, mergedPR)
+							o.auditPromotionMerge(o.Application, env.Name, releaseInfo.Version, pr.URL, pr.MergedBy, mergedAutomatically)
 
 							if o.NoWaitAfterMerge {
 								log.Infof("Pull requests are merged, No wait on promotion to complete")
@@ -659,6 +692,7 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 								}
 								if succeeded {
 									log.Infoln("Merge status checks all passed so the promotion worked!")
+									o.createPromoteCommitStatus(env, promoteKey, "success", "The promotion succeeded", pr.URL)
 									err = o.commentOnIssues(ns, env, promoteKey)
 									if err == nil {
 										err = promoteKey.OnPromoteUpdate(o.Activities, kube.CompletePromotionUpdate)
@@ -690,6 +724,8 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 										logMergeFailure = true
 										log.Warnf("Failed to merge the Pull Request %s due to %s maybe I don't have karma?\n", pr.URL, err)
 									}
+								} else {
+									mergedAutomatically = true
 								}
 							}
 						} else if status == "error" || status == "failure" {
@@ -768,6 +804,83 @@ func (o *PromoteOptions) verifyHelmConfigured() error {
 	return o.registerLocalHelmRepo(o.LocalHelmRepoName, ns)
 }
 
+// createPromoteCommitStatus creates a status for the promotion to the environment on the commit being promoted.
+// Failures are only logged as the status is informational and should not fail the promotion
+func (o *PromoteOptions) createPromoteCommitStatus(env *v1.Environment, promoteKey *kube.PromoteStepActivityKey, state string, description string, targetURL string) {
+	if o.NoCommitStatus || env == nil {
+		return
+	}
+	gitURL, sha, err := o.promotedCommit(env, promoteKey)
+	if err != nil {
+		log.Warnf("Failed to find the commit to create the promotion status on: %s\n", err)
+		return
+	}
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		log.Warnf("Failed to parse the git URL %s to create the promotion status: %s\n", gitURL, err)
+		return
+	}
+	provider, err := o.gitProviderForURL(gitURL, "user name to create commit statuses")
+	if err != nil {
+		log.Warnf("Failed to create the git provider to create the promotion status: %s\n", err)
+		return
+	}
+	err = o.createCommitStatus(provider, gitInfo.Organisation, gitInfo.Name, sha, &gits.GitRepoStatus{
+		State:       state,
+		Context:     commitStatusContextPromotePrefix + env.Name,
+		Description: description,
+		TargetURL:   targetURL,
+	})
+	if err != nil {
+		log.Warnf("%s\n", err)
+	}
+}
+
+// promotedCommit returns the git URL and sha of the commit of the version being promoted. The commit is taken from
+// the PipelineActivity of the release pipeline or the Release of the version in the environment. The working
+// directory is only used if neither records the commit and the local files are not ignored
+func (o *PromoteOptions) promotedCommit(env *v1.Environment, promoteKey *kube.PromoteStepActivityKey) (string, string, error) {
+	gitURL := ""
+	if o.GitInfo != nil {
+		gitURL = o.GitInfo.URL
+	}
+	if o.Activities != nil && promoteKey != nil && promoteKey.Name != "" {
+		activity, err := o.Activities.Get(promoteKey.Name, metav1.GetOptions{})
+		if err == nil && activity.Spec.LastCommitSHA != "" {
+			if activity.Spec.GitURL != "" {
+				gitURL = activity.Spec.GitURL
+			}
+			if gitURL != "" {
+				return gitURL, activity.Spec.LastCommitSHA, nil
+			}
+		}
+	}
+	release := o.releaseResource
+	if release == nil && o.Application != "" && o.Version != "" && env.Spec.Namespace != "" {
+		jxClient, _, err := o.JXClient()
+		if err == nil {
+			release, err = jxClient.JenkinsV1().Releases(env.Spec.Namespace).Get(kube.ToValidNameWithDots(o.Application+"-"+o.Version), metav1.GetOptions{})
+			if err != nil {
+				release = nil
+			}
+		}
+	}
+	if release != nil && len(release.Spec.Commits) > 0 {
+		if release.Spec.GitHTTPURL != "" {
+			gitURL = release.Spec.GitHTTPURL
+		}
+		if gitURL != "" {
+			// the commits of a release end with the latest commit
+			return gitURL, release.Spec.Commits[len(release.Spec.Commits)-1].SHA, nil
+		}
+	}
+	if o.IgnoreLocalFiles || gitURL == "" {
+		return "", "", fmt.Errorf("no PipelineActivity or Release records the commit of version %s of %s", o.Version, o.Application)
+	}
+	sha, err := o.currentCommitSha("")
+	return gitURL, sha, err
+}
+
 func (o *PromoteOptions) createPromoteKey(env *v1.Environment) *kube.PromoteStepActivityKey {
 	pipeline := o.Pipeline
 	build := o.Build
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	gits_test "github.com/jenkins-x/jx/pkg/gits/mocks"
+	helm_test "github.com/jenkins-x/jx/pkg/helm/mocks"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestPromotedCommitUsesThePipelineActivityOrRelease(t *testing.T) {
+	t.Parallel()
+	activity := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myorg-myapp-master-3",
+			Namespace: "jx",
+		},
+		Spec: v1.PipelineActivitySpec{
+			GitURL:        "https://github.com/myorg/myapp.git",
+			LastCommitSHA: "a1b2c3",
+		},
+	}
+	release := &v1.Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myapp-1.0.3",
+			Namespace: "jx-staging",
+		},
+		Spec: v1.ReleaseSpec{
+			GitHTTPURL: "https://github.com/myorg/myapp",
+			Commits: []v1.CommitSummary{
+				{SHA: "d4e5f6"},
+				{SHA: "0a9b8c"},
+			},
+		},
+	}
+	staging := kube.NewPermanentEnvironment("staging")
+	staging.Spec.Namespace = "jx-staging"
+
+	o := &PromoteOptions{
+		Application:      "myapp",
+		Version:          "1.0.3",
+		IgnoreLocalFiles: true,
+	}
+	ConfigureTestOptionsWithResources(&o.CommonOptions, []runtime.Object{}, []runtime.Object{activity, release},
+		gits_test.NewMockGitter(), helm_test.NewMockHelmer())
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	require.NoError(t, err)
+	o.Activities = jxClient.JenkinsV1().PipelineActivities(ns)
+
+	key := &kube.PromoteStepActivityKey{
+		PipelineActivityKey: kube.PipelineActivityKey{
+			Name: "myorg-myapp-master-3",
+		},
+	}
+	gitURL, sha, err := o.promotedCommit(staging, key)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/myorg/myapp.git", gitURL)
+	assert.Equal(t, "a1b2c3", sha)
+
+	key.Name = "myorg-myapp-master-4"
+	gitURL, sha, err = o.promotedCommit(staging, key)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/myorg/myapp", gitURL)
+	assert.Equal(t, "0a9b8c", sha, "the latest commit of the release")
+
+	o.Version = "1.0.4"
+	_, _, err = o.promotedCommit(staging, key)
+	assert.Error(t, err, "the working directory should not be used when the local files are ignored")
+}
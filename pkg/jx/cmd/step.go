@@ -42,6 +42,7 @@ func NewCmdStep(f Factory, in terminal.FileReader, out terminal.FileWriter, errO
 	}
 
 	cmd.AddCommand(NewCmdStepBlog(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepBuildPack(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepChangelog(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateBuild(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepGit(f, in, out, errOut))
@@ -54,13 +55,16 @@ func NewCmdStep(f Factory, in terminal.FileReader, out terminal.FileWriter, errO
 	cmd.AddCommand(NewCmdStepPre(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepPR(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepPost(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepQuickstart(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepReport(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepRelease(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepSplitMonorepo(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepTag(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepTerraform(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepValidate(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepVerify(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepWaitForArtifact(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepWebhook(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepCollect(f, in, out, errOut))
 
 	return cmd
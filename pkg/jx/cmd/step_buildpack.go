@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepBuildPackOptions contains the command line flags
+type StepBuildPackOptions struct {
+	StepOptions
+}
+
+// NewCmdStepBuildPack creates the command for: jx step buildpack
+func NewCmdStepBuildPack(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepBuildPackOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "buildpack",
+		Short: "build pack step actions",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdStepBuildPackDetect(f, in, out, errOut))
+
+	return cmd
+}
+
+// Run implements this command
+func (o *StepBuildPackOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	jxdraft "github.com/jenkins-x/jx/pkg/draft"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepBuildPackDetectOptions contains the command line flags
+type StepBuildPackDetectOptions struct {
+	StepBuildPackOptions
+
+	Dir      string
+	PacksDir string
+	Depth    int
+	All      bool
+}
+
+var (
+	stepBuildPackDetectLong = templates.LongDesc(`
+		Explains which build pack is detected for each deployable in a project.
+
+		'jx import' creates a single pipeline for a repository using the build pack with the best score for the
+		project so the other deployables are not built by it.
+
+		The build packs are scored on the build files of the project such as pom.xml, build.gradle, go.mod,
+		package.json, Cargo.toml and Dockerfile along with the languages of the source code. A build pack can declare
+		its own rules in a detect.yaml file.
+`)
+
+	stepBuildPackDetectExample = templates.Examples(`
+		# Explain the build pack used for the current folder
+		jx step buildpack detect
+
+		# Show all the build packs which match each deployable of a project
+		jx step buildpack detect --dir ~/projects/shop --all
+`)
+)
+
+// NewCmdStepBuildPackDetect creates the command for: jx step buildpack detect
+func NewCmdStepBuildPackDetect(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepBuildPackDetectOptions{
+		StepBuildPackOptions: StepBuildPackOptions{
+			StepOptions: StepOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "detect",
+		Short:   "Explains which build pack is used for each deployable in a project",
+		Long:    stepBuildPackDetectLong,
+		Example: stepBuildPackDetectExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", ".", "The project directory")
+	cmd.Flags().StringVarP(&options.PacksDir, "packs-dir", "", "", "The directory containing the build packs. Defaults to the build packs of the team")
+	cmd.Flags().IntVarP(&options.Depth, "depth", "", importDeployablesDepth, "How many folders deep to look for deployables")
+	cmd.Flags().BoolVarP(&options.All, "all", "a", false, "Show all the build packs which match each deployable")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *StepBuildPackDetectOptions) Run() error {
+	packsDir := o.PacksDir
+	if packsDir == "" {
+		initOpts := InitOptions{
+			CommonOptions: o.CommonOptions,
+		}
+		var err error
+		packsDir, err = initOpts.initBuildPacks()
+		if err != nil {
+			return err
+		}
+	}
+	detector, err := jxdraft.NewPackDetector(packsDir)
+	if err != nil {
+		return err
+	}
+	deployables, err := detector.DetectDeployables(o.Dir, o.Depth)
+	if err != nil {
+		return err
+	}
+	if len(deployables) == 0 {
+		return fmt.Errorf("no build pack in %s matches %s", packsDir, o.Dir)
+	}
+
+	table := o.CreateTable()
+	table.AddRow("DIR", "PACK", "SCORE", "REASONS")
+	for _, deployable := range deployables {
+		candidates := []*jxdraft.PackScore{deployable.Pack}
+		if deployable.Dir == "." {
+			// the root of the project is also scored on its languages like 'jx import' does
+			candidates, err = detector.Detect(o.Dir)
+			if err != nil {
+				return err
+			}
+		} else if o.All {
+			candidates = deployable.Candidates
+		}
+		if !o.All {
+			candidates = candidates[0:1]
+		}
+		for i, score := range candidates {
+			dir := deployable.Dir
+			if i > 0 {
+				dir = ""
+			}
+			table.AddRow(dir, score.Name, strconv.Itoa(score.Score), strings.Join(score.Reasons, ", "))
+		}
+	}
+	table.Render()
+	if len(deployables) > 1 {
+		log.Infof("\n'jx import' only uses the build pack of the root folder. Import the other deployables separately via: jx import <folder>\n")
+	}
+	return nil
+}
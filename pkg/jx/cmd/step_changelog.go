@@ -16,7 +16,10 @@ import (
 	"github.com/ghodss/yaml"
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io"
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
 	"github.com/jenkins-x/jx/pkg/issues"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
@@ -48,6 +51,16 @@ type StepChangelogOptions struct {
 	Footer              string
 	FooterFile          string
 	OutputMarkdownFile  string
+	OutputFile          string
+	Format              string
+	TemplateFile        string
+	ChangelogFile       string
+	CommitChangelog     bool
+	ChangelogBranch     string
+	ChangelogDirectPush bool
+	Paths               []string
+	Environment         bool
+	RequirementsFile    string
 	OverwriteCRD        bool
 	GenerateCRD         bool
 	GenerateReleaseYaml bool
@@ -114,6 +127,14 @@ e.g. define environment variables GIT_USERNAME and GIT_API_TOKEN
 		This command also generates a Release Custom Resource Definition you can include in your helm chart to give metadata about the changelog of the application along with metadata about the release (git tag, url, commits, issues fixed etc). Including this metadata in a helm charts means we can do things like automatically comment on issues when they hit Staging or Production; or give detailed descriptions of what things have changed when using GitOps to update versions in an environment by referencing the fixed issues in the Pull Request.
 
 		You can opt out of the release YAML generation via the '--generate-yaml=false' option
+
+		The changelog can be rendered in other formats (html, asciidoc or json) via '--format' or using your own go template via '--template-file'. The template is passed the ReleaseSpec, the grouped commits, issues and pull requests. The format, template and how commits are grouped can also be configured in the 'changelog' section of your 'jenkins-x.yml' file.
+
+		You can also add each release to a markdown file in your repository such as CHANGELOG.md via '--changelog-file' and commit it via '--commit-changelog'. The commit is pushed to a 'changelog-<version>' branch and a Pull Request is created to add it to the release branch so that protected branches are respected. Use '--changelog-direct-push' to push it straight to an unprotected release branch instead
+
+		For monorepos you can use '--path' to only include the commits which change files in the given directories.
+
+		For environment repositories use '--env' to generate an aggregated changelog of the applications which were upgraded in the 'env/requirements.yaml' file between the two revisions. The changelog combines the Release resources of each upgraded application version.
 		
 		To update the release notes on GitHub / Gitea this command needs a git API token.
 
@@ -129,6 +150,18 @@ e.g. define environment variables GIT_USERNAME and GIT_API_TOKEN
 		# specify the version and a header template
 		jx step changelog --header-file docs/dev/changelog-header.md --version 1.2.3
 
+		# generate a HTML changelog
+		jx step changelog --version 1.2.3 --format html --output-file changelog.html
+
+		# render the changelog with a custom template and add it to the CHANGELOG.md file
+		jx step changelog --version 1.2.3 --template-file docs/changelog.tmpl --changelog-file CHANGELOG.md --commit-changelog
+
+		# only include commits which change the services/foo directory
+		jx step changelog --version 1.2.3 --path services/foo
+
+		# generate the changelog of the applications upgraded in an environment repository
+		jx step changelog --env
+
 `)
 
 	GitHubIssueRegex = regexp.MustCompile(`(\#\d+)`)
@@ -171,6 +204,16 @@ func NewCmdStepChangelog(f Factory, in terminal.FileReader, out terminal.FileWri
 	cmd.Flags().StringVarP(&options.Build, "build", "", "", "The Build number which is used to update the PipelineActivity. If not specified its defaulted from  the '$BUILD_NUMBER' environment variable")
 	cmd.Flags().StringVarP(&options.Dir, "dir", "", "", "The directory of the Git repository. Defaults to the current working directory")
 	cmd.Flags().StringVarP(&options.OutputMarkdownFile, "output-markdown", "", "", "The file to generate for the changelog output if not updating a Git provider release")
+	cmd.Flags().StringVarP(&options.OutputFile, "output-file", "", "", "The file to generate the changelog into using the output format")
+	cmd.Flags().StringVarP(&options.Format, "format", "", "", fmt.Sprintf("The output format of the changelog. Possible values: %s. Defaults to the 'changelog' configuration in the jenkins-x.yml file or markdown", strings.Join(gits.ChangelogFormats, ", ")))
+	cmd.Flags().StringVarP(&options.TemplateFile, "template-file", "", "", "The file name of a go template used to render the changelog: https://golang.org/pkg/text/template/")
+	cmd.Flags().StringVarP(&options.ChangelogFile, "changelog-file", "", "", "The markdown file in the repository, such as CHANGELOG.md, to add the changelog of this release to")
+	cmd.Flags().BoolVarP(&options.CommitChangelog, "commit-changelog", "", false, "Should we commit the changes to the changelog file and create a Pull Request to add them to the changelog branch")
+	cmd.Flags().StringVarP(&options.ChangelogBranch, "changelog-branch", "", "", "The branch to add the changes to the changelog file to. Defaults to the '$BRANCH_NAME' environment variable or the current branch")
+	cmd.Flags().BoolVarP(&options.ChangelogDirectPush, "changelog-direct-push", "", false, "Push the changes to the changelog file directly to the changelog branch rather than creating a Pull Request. The branch must not be protected and must not have changed since the release was built")
+	cmd.Flags().StringArrayVarP(&options.Paths, "path", "", nil, "Only include commits which change files within the given paths relative to the root of the repository. Can be specified multiple times")
+	cmd.Flags().BoolVarP(&options.Environment, "env", "", false, "Generates an aggregated changelog of the applications upgraded in the requirements of an environment repository")
+	cmd.Flags().StringVarP(&options.RequirementsFile, "requirements-file", "", "env/requirements.yaml", "The requirements file of the environment repository relative to the root of the repository used with '--env'")
 	cmd.Flags().BoolVarP(&options.OverwriteCRD, "overwrite", "o", false, "overwrites the Release CRD YAML file if it exists")
 	cmd.Flags().BoolVarP(&options.GenerateCRD, "crd", "c", false, "Generate the CRD in the chart")
 	cmd.Flags().BoolVarP(&options.GenerateReleaseYaml, "generate-yaml", "y", true, "Generate the Release YAML in the local helm chart")
@@ -304,10 +347,22 @@ func (o *StepChangelogOptions) Run() error {
 	o.State.GitProvider = gitProvider
 	o.State.FoundIssueNames = map[string]bool{}
 
+	changelogConfig, err := o.changelogConfig(dir)
+	if err != nil {
+		return err
+	}
 	commits, err := chgit.FetchCommits(gitDir, previousRev, currentRev)
 	if err != nil {
 		return err
 	}
+	if commits != nil && len(changelogConfig.Paths) > 0 {
+		log.Infof("Only including commits which change %s\n", util.ColorInfo(strings.Join(changelogConfig.Paths, ", ")))
+		filtered, err := gits.FilterCommitsByPaths(*commits, changelogConfig.Paths)
+		if err != nil {
+			return err
+		}
+		commits = &filtered
+	}
 	version := o.Version
 	if version == "" {
 		version = SpecVersion
@@ -344,9 +399,44 @@ func (o *StepChangelogOptions) Run() error {
 			o.addCommit(&release.Spec, &commit)
 		}
 	}
+	if o.Environment {
+		err = o.addUpgradedReleases(&release.Spec, gitDir, previousRev, currentRev, jxClient, devNs)
+		if err != nil {
+			return err
+		}
+	}
+
+	rules := []*gits.CommitGroupRule{}
+	for _, group := range changelogConfig.Groups {
+		rules = append(rules, &gits.CommitGroupRule{
+			Title:   group.Title,
+			Kinds:   group.Kinds,
+			Pattern: group.Pattern,
+		})
+	}
+	changelogData, err := gits.NewChangelogData(&release.Spec, gitInfo, rules)
+	if err != nil {
+		return err
+	}
+	format := changelogConfig.Format
+	templateText := ""
+	if changelogConfig.Template != "" {
+		data, err := ioutil.ReadFile(changelogConfig.Template)
+		if err != nil {
+			return fmt.Errorf("Failed to load changelog template %s: %s", changelogConfig.Template, err)
+		}
+		templateText = string(data)
+	}
 
 	// lets try to update the release
-	markdown, err := gits.GenerateMarkdown(&release.Spec, gitInfo)
+	markdown := ""
+	if templateText != "" && (format == "" || format == gits.ChangelogFormatMarkdown) {
+		markdown, err = gits.RenderChangelog(format, templateText, changelogData)
+	} else if len(rules) > 0 {
+		markdown, err = gits.RenderChangelog(gits.ChangelogFormatMarkdown, "", changelogData)
+	} else {
+		markdown, err = gits.GenerateMarkdown(&release.Spec, gitInfo)
+	}
 	if err != nil {
 		return err
 	}
@@ -385,10 +475,30 @@ func (o *StepChangelogOptions) Run() error {
 			return err
 		}
 		log.Infof("\nGenerated Changelog: %s\n", util.ColorInfo(o.OutputMarkdownFile))
-	} else {
+	} else if o.OutputFile == "" {
 		log.Infof("\nGenerated Changelog:\n")
 		log.Infof("%s\n\n", markdown)
 	}
+	if o.OutputFile != "" {
+		output := markdown
+		if format != "" && format != gits.ChangelogFormatMarkdown {
+			output, err = gits.RenderChangelog(format, templateText, changelogData)
+			if err != nil {
+				return err
+			}
+		}
+		err := ioutil.WriteFile(o.OutputFile, []byte(output), DefaultWritePermissions)
+		if err != nil {
+			return err
+		}
+		log.Infof("\nGenerated Changelog: %s\n", util.ColorInfo(o.OutputFile))
+	}
+	if changelogConfig.File != "" {
+		err = o.updateChangelogFile(dir, changelogConfig.File, version, markdown)
+		if err != nil {
+			return err
+		}
+	}
 
 	o.State.Release = release
 	// now lets marshal the release YAML
@@ -493,6 +603,210 @@ func (o *StepChangelogOptions) Run() error {
 	return nil
 }
 
+// changelogConfig returns the changelog configuration from the project configuration overridden by the command line flags
+func (o *StepChangelogOptions) changelogConfig(dir string) (*config.ChangelogConfig, error) {
+	answer := &config.ChangelogConfig{}
+	projectConfig, _, err := config.LoadProjectConfig(dir)
+	if err != nil {
+		return answer, err
+	}
+	if projectConfig.Changelog != nil {
+		answer = projectConfig.Changelog
+		if answer.Template != "" && !filepath.IsAbs(answer.Template) {
+			answer.Template = filepath.Join(dir, answer.Template)
+		}
+	}
+	if o.Format != "" {
+		answer.Format = o.Format
+	}
+	if o.TemplateFile != "" {
+		answer.Template = o.TemplateFile
+	}
+	if o.ChangelogFile != "" {
+		answer.File = o.ChangelogFile
+	}
+	if len(o.Paths) > 0 {
+		answer.Paths = o.Paths
+	}
+	if answer.Format != "" && util.StringArrayIndex(gits.ChangelogFormats, answer.Format) < 0 {
+		return answer, util.InvalidOption("format", answer.Format, gits.ChangelogFormats)
+	}
+	return answer, nil
+}
+
+// updateChangelogFile adds the changelog for the version to the changelog file in the repository
+// and optionally commits and pushes it
+func (o *StepChangelogOptions) updateChangelogFile(dir string, fileName string, version string, markdown string) error {
+	if version == SpecVersion {
+		log.Warnf("Cannot update the changelog file %s as no version was specified\n", fileName)
+		return nil
+	}
+	if !filepath.IsAbs(fileName) {
+		fileName = filepath.Join(dir, fileName)
+	}
+	err := gits.UpdateChangelogFile(fileName, version, markdown)
+	if err != nil {
+		return fmt.Errorf("Failed to update changelog file %s: %s", fileName, err)
+	}
+	log.Infof("Updated changelog file %s\n", util.ColorInfo(fileName))
+	if !o.CommitChangelog {
+		return nil
+	}
+	branch, err := o.changelogBranch(dir)
+	if err != nil {
+		return err
+	}
+	err = o.Git().Add(dir, fileName)
+	if err != nil {
+		return err
+	}
+	message := fmt.Sprintf("chore: changelog for %s", version)
+	err = o.Git().CommitIfChanges(dir, message)
+	if err != nil {
+		return err
+	}
+	if o.ChangelogDirectPush {
+		// a push which is not a fast forward is rejected so the branch cannot have changed since the release was built
+		err = o.Git().PushBranch(dir, "HEAD", branch)
+		if err != nil {
+			return fmt.Errorf("Failed to push the changelog file %s to the branch %s. The branch may be protected or have changed since the release was built so remove --changelog-direct-push to create a Pull Request: %s", fileName, branch, err)
+		}
+		return nil
+	}
+	return o.createChangelogPullRequest(dir, fileName, version, branch, message)
+}
+
+// createChangelogPullRequest pushes the changelog commit to a branch of the version and creates a Pull Request to
+// merge it into the changelog branch
+func (o *StepChangelogOptions) createChangelogPullRequest(dir string, fileName string, version string, base string, title string) error {
+	provider := o.State.GitProvider
+	gitInfo := o.State.GitInfo
+	if provider == nil || gitInfo == nil {
+		return fmt.Errorf("Cannot create a Pull Request for the changelog file %s as there is no git provider. Use --changelog-direct-push to push it to the branch %s", fileName, base)
+	}
+	head := "changelog-" + version
+	// the branch is only used for this changelog so lets replace it if the release is rebuilt
+	err := o.Git().ForcePushBranch(dir, "HEAD", head)
+	if err != nil {
+		return fmt.Errorf("Failed to push the changelog file %s to the branch %s: %s", fileName, head, err)
+	}
+	rel, err := filepath.Rel(dir, fileName)
+	if err != nil {
+		rel = fileName
+	}
+	pr, err := provider.CreatePullRequest(&gits.GitPullRequestArguments{
+		GitRepositoryInfo: gitInfo,
+		Title:             title,
+		Body:              fmt.Sprintf("Adds the changelog of version %s to %s", version, filepath.ToSlash(rel)),
+		Base:              base,
+		Head:              head,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to create a Pull Request for the changelog file %s: %s", fileName, err)
+	}
+	log.Infof("Created Pull Request %s to add the changelog to the branch %s\n", util.ColorInfo(pr.URL), util.ColorInfo(base))
+	return nil
+}
+
+// changelogBranch returns the branch to add the changelog file to. Release pipelines usually build a detached
+// HEAD so the branch is taken from the flag or $BRANCH_NAME before falling back to the current branch
+func (o *StepChangelogOptions) changelogBranch(dir string) (string, error) {
+	branch := o.ChangelogBranch
+	if branch == "" {
+		branch = os.Getenv("BRANCH_NAME")
+	}
+	if branch == "" {
+		current, err := o.Git().Branch(dir)
+		if err != nil {
+			return "", err
+		}
+		if current != "HEAD" {
+			branch = current
+		}
+	}
+	if branch == "" {
+		return "", fmt.Errorf("Cannot commit the changelog file as the repository in %s has a detached HEAD and no branch was specified via --changelog-branch or $BRANCH_NAME", dir)
+	}
+	return branch, nil
+}
+
+// addUpgradedReleases adds the commits, issues and pull requests of the Release resources of every application
+// upgraded in the environment requirements between the two revisions
+func (o *StepChangelogOptions) addUpgradedReleases(spec *v1.ReleaseSpec, gitDir string, previousRev string, currentRev string, jxClient versioned.Interface, ns string) error {
+	fromRequirements, err := o.loadRequirementsAtRevision(gitDir, previousRev)
+	if err != nil {
+		return err
+	}
+	toRequirements, err := o.loadRequirementsAtRevision(gitDir, currentRev)
+	if err != nil {
+		return err
+	}
+	upgrades := helm.DiffRequirements(fromRequirements, toRequirements)
+	if len(upgrades) == 0 {
+		log.Infof("No applications were upgraded in %s\n", o.RequirementsFile)
+		return nil
+	}
+	foundIssues := map[string]bool{}
+	for _, issue := range spec.Issues {
+		foundIssues[issue.URL] = true
+	}
+	for _, pr := range spec.PullRequests {
+		foundIssues[pr.URL] = true
+	}
+	// the same chart can be upgraded under more than one alias so lets only include its commits once
+	foundCommits := map[string]bool{}
+	for _, commit := range spec.Commits {
+		foundCommits[commit.SHA] = true
+	}
+	for _, upgrade := range upgrades {
+		releases, err := kube.GetReleasesBetweenVersions(jxClient, ns, upgrade.Name, upgrade.FromVersion, upgrade.ToVersion)
+		if err != nil {
+			return err
+		}
+		if len(releases) == 0 {
+			log.Warnf("No Release resources found in namespace %s for %s version %s\n", ns, upgrade.Name, upgrade.ToVersion)
+			continue
+		}
+		log.Infof("Including %d releases of %s upgraded from %s to %s\n", len(releases), util.ColorInfo(upgrade.Name), util.ColorInfo(upgrade.FromVersion), util.ColorInfo(upgrade.ToVersion))
+		for _, release := range releases {
+			for _, commit := range release.Spec.Commits {
+				if commit.SHA == "" || !foundCommits[commit.SHA] {
+					foundCommits[commit.SHA] = true
+					spec.Commits = append(spec.Commits, commit)
+				}
+			}
+			for _, issue := range release.Spec.Issues {
+				if !foundIssues[issue.URL] {
+					foundIssues[issue.URL] = true
+					spec.Issues = append(spec.Issues, issue)
+				}
+			}
+			for _, pr := range release.Spec.PullRequests {
+				if !foundIssues[pr.URL] {
+					foundIssues[pr.URL] = true
+					spec.PullRequests = append(spec.PullRequests, pr)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (o *StepChangelogOptions) loadRequirementsAtRevision(gitDir string, revision string) (*helm.Requirements, error) {
+	data, err := gits.GetFileContentsAtRevision(gitDir, revision, o.RequirementsFile)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return &helm.Requirements{}, nil
+	}
+	requirements, err := helm.LoadRequirements(data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse %s at revision %s: %s", o.RequirementsFile, revision, err)
+	}
+	return requirements, nil
+}
+
 func (o *StepChangelogOptions) addCommit(spec *v1.ReleaseSpec, commit *object.Commit) {
 	// TODO
 	url := ""
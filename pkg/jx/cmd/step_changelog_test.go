@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runTestGit(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=jx", "GIT_AUTHOR_EMAIL=jx@example.com",
+		"GIT_COMMITTER_NAME=jx", "GIT_COMMITTER_EMAIL=jx@example.com")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %s: %s", strings.Join(args, " "), string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func TestUpdateChangelogFileCreatesPullRequest(t *testing.T) {
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		os.Setenv(name, "jx")
+		defer os.Unsetenv(name)
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		os.Setenv(name, "jx@example.com")
+		defer os.Unsetenv(name)
+	}
+	tmpDir, err := ioutil.TempDir("", "test-changelog-file")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	origin := filepath.Join(tmpDir, "origin.git")
+	dir := filepath.Join(tmpDir, "myapp")
+	require.NoError(t, os.MkdirAll(dir, util.DefaultWritePermissions))
+	runTestGit(t, tmpDir, "init", "--bare", origin)
+	runTestGit(t, dir, "init")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("# myapp\n"), util.DefaultWritePermissions))
+	runTestGit(t, dir, "add", "README.md")
+	runTestGit(t, dir, "commit", "-m", "initial import")
+	runTestGit(t, dir, "remote", "add", "origin", origin)
+	runTestGit(t, dir, "push", "origin", "HEAD:master")
+	master := runTestGit(t, origin, "rev-parse", "master")
+
+	provider := gits.NewFakeProvider(gits.NewFakeRepository("myorg", "myapp"))
+	o := &StepChangelogOptions{
+		CommitChangelog: true,
+		ChangelogBranch: "master",
+	}
+	o.GitClient = gits.NewGitCLI()
+	o.State.GitProvider = provider
+	o.State.GitInfo = &gits.GitRepositoryInfo{Host: "github.com", Organisation: "myorg", Name: "myapp"}
+
+	err = o.updateChangelogFile(dir, "CHANGELOG.md", "1.2.3", "## Changes\n\n* fixed a bug\n")
+	require.NoError(t, err)
+
+	assert.Equal(t, master, runTestGit(t, origin, "rev-parse", "master"), "the changelog should not be pushed to the release branch")
+	assert.Contains(t, runTestGit(t, origin, "show", "changelog-1.2.3:CHANGELOG.md"), "fixed a bug")
+
+	pullRequests := provider.Repositories["myorg"][0].PullRequests
+	require.Len(t, pullRequests, 1)
+	for _, pr := range pullRequests {
+		assert.Equal(t, "chore: changelog for 1.2.3", pr.PullRequest.Title)
+		assert.Equal(t, "Adds the changelog of version 1.2.3 to CHANGELOG.md", pr.PullRequest.Body)
+	}
+
+	o.ChangelogDirectPush = true
+	err = o.updateChangelogFile(dir, "CHANGELOG.md", "1.2.4", "## Changes\n\n* fixed another bug\n")
+	require.NoError(t, err)
+	assert.Contains(t, runTestGit(t, origin, "show", "master:CHANGELOG.md"), "fixed another bug")
+}
@@ -43,7 +43,9 @@ func NewCmdStepGit(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 			CheckErr(err)
 		},
 	}
+	cmd.AddCommand(NewCmdStepGitAcceptInvitations(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepGitCredentials(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepGitStatus(f, in, out, errOut))
 	return cmd
 }
 
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepGitAcceptInvitationsOptions contains the command line flags
+type StepGitAcceptInvitationsOptions struct {
+	StepOptions
+
+	GitServer string
+	Username  string
+}
+
+var (
+	stepGitAcceptInvitationsLong = templates.LongDesc(`
+		Accepts all the pending invitations of the pipeline user to collaborate on git repositories.
+
+		Git providers which grant access directly, such as GitLab, Gitea and Bitbucket Server, have no invitations to accept.
+`)
+
+	stepGitAcceptInvitationsExample = templates.Examples(`
+		# Accept the invitations of the pipeline user of the team
+		jx step git accept-invitations
+
+		# Accept the invitations of a specific user on a git server
+		jx step git accept-invitations --git-server https://github.com --username jenkins-x-bot
+	`)
+)
+
+// NewCmdStepGitAcceptInvitations creates a command object for the "step git accept-invitations" command
+func NewCmdStepGitAcceptInvitations(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepGitAcceptInvitationsOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "accept-invitations",
+		Short:   "Accepts the pending invitations of the pipeline user to collaborate on git repositories",
+		Aliases: []string{"accept-invitation"},
+		Long:    stepGitAcceptInvitationsLong,
+		Example: stepGitAcceptInvitationsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.GitServer, "git-server", "", "", "The git server URL. Defaults to the git server of the team")
+	cmd.Flags().StringVarP(&options.Username, "username", "u", "", "The user accepting the invitations. Defaults to the pipeline user of the team")
+
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *StepGitAcceptInvitationsOptions) Run() error {
+	serverURL := o.GitServer
+	username := o.Username
+	if serverURL == "" || username == "" {
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return err
+		}
+		if serverURL == "" {
+			serverURL = teamSettings.GitServer
+		}
+		if username == "" {
+			username = teamSettings.PipelineUsername
+		}
+	}
+	if serverURL == "" {
+		return util.MissingOption("git-server")
+	}
+	authConfigSvc, err := o.CreateGitAuthConfigService()
+	if err != nil {
+		return err
+	}
+	config := authConfigSvc.Config()
+	server := config.GetServer(serverURL)
+	if server == nil {
+		return fmt.Errorf("No git server %s found in the git credentials", serverURL)
+	}
+	userAuth := config.FindUserAuth(serverURL, username)
+	if userAuth == nil {
+		return fmt.Errorf("No git credentials found for user %s on git server %s", username, serverURL)
+	}
+	provider, err := gits.CreateProvider(server, userAuth, o.Git())
+	if err != nil {
+		return err
+	}
+	count, err := acceptGitInvitations(provider)
+	if err != nil {
+		return err
+	}
+	log.Infof("Accepted %d invitations for user %s on %s\n", count, util.ColorInfo(userAuth.Username), util.ColorInfo(serverURL))
+	return nil
+}
+
+// acceptGitInvitations accepts all the pending invitations of the user of the git provider returning how many were accepted
+func acceptGitInvitations(provider gits.GitProvider) (int, error) {
+	invitations, err := provider.ListInvitations()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to list the git invitations: %s", err)
+	}
+	for i, invitation := range invitations {
+		err = provider.AcceptInvitation(invitation.ID)
+		if err != nil {
+			return i, fmt.Errorf("Failed to accept the invitation to %s/%s: %s", invitation.Organisation, invitation.Repository, err)
+		}
+	}
+	return len(invitations), nil
+}
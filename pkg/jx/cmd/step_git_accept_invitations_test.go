@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptGitInvitations(t *testing.T) {
+	t.Parallel()
+	provider := gits.NewFakeProvider(gits.NewFakeRepository("myorg", "myrepo"))
+	provider.Invitations = []*gits.GitInvitation{
+		{ID: "1", Organisation: "myorg", Repository: "myrepo", Permission: gits.GitPermissionWrite},
+		{ID: "2", Organisation: "myorg", Repository: "another", Permission: gits.GitPermissionWrite},
+	}
+
+	count, err := acceptGitInvitations(provider)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Empty(t, provider.Invitations, "all the invitations should have been accepted")
+
+	count, err = acceptGitInvitations(provider)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
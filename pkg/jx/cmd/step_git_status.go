@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const (
+	// PULL_BASE_SHA the environment variable containing the SHA of the base branch being built
+	PULL_BASE_SHA = "PULL_BASE_SHA"
+
+	commitStatusContextPreview       = "jx/preview"
+	commitStatusContextPromotePrefix = "jx/promote-"
+)
+
+var commitStatusStates = []string{"pending", "success", "error", "failure"}
+
+// StepGitStatusOptions contains the command line flags
+type StepGitStatusOptions struct {
+	StepOptions
+
+	Dir         string
+	Owner       string
+	Repository  string
+	SHA         string
+	State       string
+	Context     string
+	Description string
+	TargetURL   string
+}
+
+var (
+	stepGitStatusLong = templates.LongDesc(`
+		Creates a status on a git commit so that the pipeline can report the outcome of its steps to the git provider.
+
+		The commit defaults to the commit being built by the pipeline and the repository defaults to the git repository
+		in the current directory.
+`)
+
+	stepGitStatusExample = templates.Examples(`
+		# Report that the CVE scan of the current commit succeeded
+		jx step git status --context cve-scan --state success --description "No vulnerabilities found"
+
+		# Report that the tests of a specific commit failed linking to the test report
+		jx step git status --context tests --state failure --sha 5c8afc5 --url https://reports.example.com/5c8afc5
+	`)
+)
+
+// NewCmdStepGitStatus creates a command object for the "step git status" command
+func NewCmdStepGitStatus(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepGitStatusOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "status",
+		Short:   "Creates a status on a git commit",
+		Long:    stepGitStatusLong,
+		Example: stepGitStatusExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory of the git repository. Defaults to the current directory")
+	cmd.Flags().StringVarP(&options.Owner, "owner", "o", "", "The git organisation / owner. Defaults to the owner of the git repository in the directory")
+	cmd.Flags().StringVarP(&options.Repository, "repository", "r", "", "The git repository. Defaults to the git repository in the directory")
+	cmd.Flags().StringVarP(&options.SHA, "sha", "", "", "The commit SHA. Defaults to $PULL_PULL_SHA, $PULL_BASE_SHA or the HEAD of the git repository in the directory")
+	cmd.Flags().StringVarP(&options.State, "state", "s", "", "The state of the status. One of: "+strings.Join(commitStatusStates, ", "))
+	cmd.Flags().StringVarP(&options.Context, "context", "c", "", "The context which identifies the status, e.g. the name of the pipeline step")
+	cmd.Flags().StringVarP(&options.Description, "description", "", "", "A short description of the status")
+	cmd.Flags().StringVarP(&options.TargetURL, "url", "u", "", "The URL with the details of the status")
+
+	options.addCommonFlags(cmd)
+
+	return cmd
+}
+
+// Run implements this command
+func (o *StepGitStatusOptions) Run() error {
+	if o.State == "" {
+		return util.MissingOption("state")
+	}
+	if util.StringArrayIndex(commitStatusStates, o.State) < 0 {
+		return util.InvalidOption("state", o.State, commitStatusStates)
+	}
+	if o.Context == "" {
+		return util.MissingOption("context")
+	}
+	gitInfo, err := o.Git().Info(o.Dir)
+	if err != nil {
+		return fmt.Errorf("Failed to find the git repository in directory %s: %s", o.Dir, err)
+	}
+	owner := o.Owner
+	if owner == "" {
+		owner = gitInfo.Organisation
+	}
+	repo := o.Repository
+	if repo == "" {
+		repo = gitInfo.Name
+	}
+	sha := o.SHA
+	if sha == "" {
+		sha, err = o.currentCommitSha(o.Dir)
+		if err != nil {
+			return err
+		}
+	}
+	provider, err := o.gitProviderForURL(gitInfo.URL, "user name to create the commit status")
+	if err != nil {
+		return err
+	}
+	return o.createCommitStatus(provider, owner, repo, sha, &gits.GitRepoStatus{
+		State:       o.State,
+		Context:     o.Context,
+		Description: o.Description,
+		TargetURL:   o.TargetURL,
+	})
+}
+
+// currentCommitSha returns the SHA of the commit being built from $PULL_PULL_SHA or $PULL_BASE_SHA falling back to the
+// HEAD of the git repository in the given directory
+func (o *CommonOptions) currentCommitSha(dir string) (string, error) {
+	for _, name := range []string{PULL_PULL_SHA, PULL_BASE_SHA} {
+		sha := os.Getenv(name)
+		if sha != "" {
+			return sha, nil
+		}
+	}
+	if dir == "" {
+		dir = "."
+	}
+	sha, err := o.getCommandOutput(dir, "git", "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("Failed to find the HEAD commit of the git repository in %s: %s", dir, err)
+	}
+	return strings.TrimSpace(sha), nil
+}
+
+// createCommitStatus creates the status on the commit of the given repository
+func (o *CommonOptions) createCommitStatus(provider gits.GitProvider, owner string, repo string, sha string, status *gits.GitRepoStatus) error {
+	_, err := provider.CreateCommitStatus(owner, repo, sha, status)
+	if err != nil {
+		return fmt.Errorf("Failed to create the %s status %s on commit %s of %s/%s: %s", status.Context, status.State, sha, owner, repo, err)
+	}
+	log.Infof("Created the %s status %s on commit %s of %s/%s\n", util.ColorInfo(status.Context), util.ColorInfo(status.State),
+		util.ColorInfo(sha), owner, repo)
+	return nil
+}
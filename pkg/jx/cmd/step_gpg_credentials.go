@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
@@ -28,6 +29,8 @@ var (
 	StepGpgCredentialsLong = templates.LongDesc(`
 		This pipeline step generates GPG credentials files from the ` + kube.SecretJenkinsReleaseGPG + ` secret
 
+		To sign the commits and tags which jx creates set the $` + gits.SigningKeyEnvVar + ` environment variable of the pipeline to the ID of the key.
+
 `)
 
 	StepGpgCredentialsExample = templates.Examples(`
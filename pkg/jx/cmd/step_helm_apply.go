@@ -95,11 +95,6 @@ func (o *StepHelmApplyOptions) Run() error {
 		return err
 	}
 
-	helmBinary, noTiller, helmTemplate, err := o.TeamHelmBin()
-	if err != nil {
-		return err
-	}
-
 	ns := o.Namespace
 	if ns == "" {
 		ns = os.Getenv("DEPLOY_NAMESPACE")
@@ -110,9 +105,9 @@ func (o *StepHelmApplyOptions) Run() error {
 
 	releaseName := o.ReleaseName
 	if releaseName == "" {
-		releaseName = ns
-		if helmBinary != "helm" || noTiller || helmTemplate {
-			releaseName = "jx"
+		releaseName, err = o.environmentReleaseName(ns)
+		if err != nil {
+			return err
 		}
 	}
 
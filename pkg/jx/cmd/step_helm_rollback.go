@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepHelmRollbackOptions contains the command line flags
+type StepHelmRollbackOptions struct {
+	StepHelmOptions
+
+	Namespace string
+}
+
+var (
+	StepHelmRollbackLong = templates.LongDesc(`
+		Rolls back a helm release to a previous revision.
+
+		If no revision is specified the release is rolled back to the revision before the currently deployed one.
+		Use 'jx get helm history' to view the revisions of a release.
+`)
+
+	StepHelmRollbackExample = templates.Examples(`
+		# rolls back the staging environment release to its previous revision
+		jx step helm rollback jx-staging -n jx-staging
+
+		# rolls back a release to revision 3
+		jx step helm rollback jx-staging 3 -n jx-staging
+`)
+)
+
+// NewCmdStepHelmRollback creates the command for: jx step helm rollback
+func NewCmdStepHelmRollback(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := StepHelmRollbackOptions{
+		StepHelmOptions: StepHelmOptions{
+			StepOptions: StepOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "rollback [release] [revision]",
+		Short:   "Rolls back a helm release to a previous revision",
+		Long:    StepHelmRollbackLong,
+		Example: StepHelmRollbackExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace of the release or defaults to the current namespace")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepHelmRollbackOptions) Run() error {
+	args := o.Args
+	if len(args) == 0 {
+		return fmt.Errorf("Missing release name argument")
+	}
+	releaseName := args[0]
+	ns := o.Namespace
+	if ns == "" {
+		_, curNs, err := o.KubeClient()
+		if err != nil {
+			return err
+		}
+		ns = curNs
+	}
+	revision := 0
+	if len(args) > 1 {
+		var err error
+		revision, err = strconv.Atoi(args[1])
+		if err != nil {
+			return util.InvalidArgf(args[1], "revision should be a number")
+		}
+	} else {
+		history, err := o.Helm().ReleaseHistory(ns, releaseName)
+		if err != nil {
+			return err
+		}
+		revision = previousRevision(history)
+		if revision == 0 {
+			return fmt.Errorf("No previous revision of release %s found in namespace %s", releaseName, ns)
+		}
+	}
+	err := o.Helm().RollbackRelease(ns, releaseName, revision)
+	if err != nil {
+		return err
+	}
+	log.Infof("Rolled back release %s to revision %s\n", util.ColorInfo(releaseName), util.ColorInfo(strconv.Itoa(revision)))
+	return nil
+}
+
+// previousRevision returns the latest successfully deployed revision before the latest deployed revision or 0 if
+// there is none. Like helm rollback it skips revisions which failed or were deleted
+func previousRevision(history []*helm.ReleaseRevision) int {
+	deployed := -1
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Status == helm.ReleaseStatusDeployed {
+			deployed = i
+			break
+		}
+	}
+	if deployed < 0 {
+		deployed = len(history)
+	}
+	for i := deployed - 1; i >= 0; i-- {
+		status := history[i].Status
+		if status == helm.ReleaseStatusSuperseded || status == helm.ReleaseStatusDeployed {
+			return history[i].Revision
+		}
+	}
+	return 0
+}
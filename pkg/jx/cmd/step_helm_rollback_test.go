@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviousRevision(t *testing.T) {
+	t.Parallel()
+	revisions := func(statuses ...string) []*helm.ReleaseRevision {
+		answer := []*helm.ReleaseRevision{}
+		for i, status := range statuses {
+			answer = append(answer, &helm.ReleaseRevision{Revision: i + 1, Status: status})
+		}
+		return answer
+	}
+	assert.Equal(t, 0, previousRevision(nil))
+	assert.Equal(t, 0, previousRevision(revisions(helm.ReleaseStatusDeployed)))
+	assert.Equal(t, 1, previousRevision(revisions(helm.ReleaseStatusSuperseded, helm.ReleaseStatusDeployed)))
+	assert.Equal(t, 1, previousRevision(revisions(helm.ReleaseStatusSuperseded, helm.ReleaseStatusFailed, helm.ReleaseStatusDeployed)))
+	assert.Equal(t, 1, previousRevision(revisions(helm.ReleaseStatusSuperseded, helm.ReleaseStatusDeployed, helm.ReleaseStatusFailed)))
+	assert.Equal(t, 2, previousRevision(revisions(helm.ReleaseStatusSuperseded, helm.ReleaseStatusSuperseded, helm.ReleaseStatusFailed)))
+	assert.Equal(t, 0, previousRevision(revisions(helm.ReleaseStatusFailed, helm.ReleaseStatusDeployed)))
+	assert.Equal(t, 0, previousRevision(revisions(helm.ReleaseStatusFailed, helm.ReleaseStatusFailed)))
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepQuickstartOptions contains the command line flags
+type StepQuickstartOptions struct {
+	StepOptions
+}
+
+// NewCmdStepQuickstart creates the command for: jx step quickstart
+func NewCmdStepQuickstart(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepQuickstartOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "quickstart",
+		Short: "quickstart step actions",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdStepQuickstartIndex(f, in, out, errOut))
+
+	return cmd
+}
+
+// Run implements this command
+func (o *StepQuickstartOptions) Run() error {
+	return o.Cmd.Help()
+}
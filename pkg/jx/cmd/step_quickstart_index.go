@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/quickstarts"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepQuickstartIndexOptions contains the command line flags
+type StepQuickstartIndexOptions struct {
+	StepQuickstartOptions
+	QuickstartLoadOptions
+
+	OutputFile    string
+	SkipManifests bool
+}
+
+var (
+	stepQuickstartIndexLong = templates.LongDesc(`
+		Builds the catalog of the quickstarts in the quickstart locations of the team.
+
+		The catalog is used by 'jx create quickstart' and 'jx get quickstarts' so that they do not have to query
+		the git providers of every quickstart location each time they run. Run this step periodically or whenever
+		the quickstart locations change to keep the catalog up to date.
+
+		Each quickstart is downloaded so that the language, framework, description and tags of its quickstart.yaml
+		manifest are included in the catalog.
+`)
+
+	stepQuickstartIndexExample = templates.Examples(`
+		# Update the quickstart catalog of the team
+		jx step quickstart index
+
+		# Write the quickstart catalog to a file
+		jx step quickstart index --output-file quickstarts.json
+`)
+)
+
+// NewCmdStepQuickstartIndex creates the command for: jx step quickstart index
+func NewCmdStepQuickstartIndex(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepQuickstartIndexOptions{
+		StepQuickstartOptions: StepQuickstartOptions{
+			StepOptions: StepOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "index",
+		Short:   "Builds the catalog of the quickstarts of the team",
+		Long:    stepQuickstartIndexLong,
+		Example: stepQuickstartIndexExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringArrayVarP(&options.GitHubOrganisations, "organisations", "g", []string{}, "The extra GitHub organisations to include in the catalog")
+	cmd.Flags().StringVarP(&options.OutputFile, "output-file", "", "", "The JSON file to write the catalog to rather than the ConfigMap of the team")
+	cmd.Flags().BoolVarP(&options.SkipManifests, "skip-manifests", "", false, "Do not download the quickstarts to include the metadata of their quickstart.yaml manifests")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *StepQuickstartIndexOptions) Run() error {
+	o.Refresh = true
+	model, err := o.loadQuickstartModel(&o.QuickstartLoadOptions)
+	if err != nil {
+		return err
+	}
+	if !o.SkipManifests {
+		o.applyQuickstartManifests(model)
+	}
+	catalog := quickstarts.NewQuickstartCatalog(model)
+	if o.OutputFile != "" {
+		err = catalog.SaveFile(o.OutputFile)
+		if err != nil {
+			return err
+		}
+		log.Infof("Wrote the catalog of %s quickstarts to %s\n", util.ColorInfo(len(catalog.Quickstarts)), util.ColorInfo(o.OutputFile))
+		return nil
+	}
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	err = catalog.SaveConfigMap(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	log.Infof("Saved the catalog of %s quickstarts in ConfigMap %s in namespace %s\n", util.ColorInfo(len(catalog.Quickstarts)), util.ColorInfo(kube.ConfigMapQuickstartCatalog), util.ColorInfo(ns))
+	return nil
+}
+
+// applyQuickstartManifests downloads the quickstarts and applies the metadata of their manifests so that the catalog
+// can be searched using it
+func (o *StepQuickstartIndexOptions) applyQuickstartManifests(model *quickstarts.QuickstartModel) {
+	ids := []string{}
+	for id := range model.Quickstarts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		q := model.Quickstarts[id]
+		dir, err := o.downloadQuickstartSource(q)
+		if err != nil {
+			log.Warnf("Failed to download quickstart %s to load its manifest: %s\n", id, err)
+			continue
+		}
+		manifest, err := quickstarts.LoadQuickstartManifest(dir)
+		if err != nil {
+			log.Warnf("Failed to load the manifest of quickstart %s: %s\n", id, err)
+		} else if manifest != nil {
+			manifest.Apply(q)
+		}
+		err = os.RemoveAll(filepath.Dir(dir))
+		if err != nil {
+			log.Warnf("Failed to remove the download of quickstart %s: %s\n", id, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"archive/zip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/quickstarts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepQuickstartIndexAppliesQuickstartManifests(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archive := zip.NewWriter(w)
+		if r.URL.Path == "/node-http.zip" {
+			f, err := archive.Create("node-http-master/" + quickstarts.ManifestFileName)
+			require.NoError(t, err)
+			_, err = f.Write([]byte("language: javascript\nframework: express\ntags: [http]\n"))
+			require.NoError(t, err)
+		}
+		f, err := archive.Create("node-http-master/README.md")
+		require.NoError(t, err)
+		_, err = f.Write([]byte("# quickstart\n"))
+		require.NoError(t, err)
+		require.NoError(t, archive.Close())
+	}))
+	defer server.Close()
+
+	provider := &gits.FakeProvider{}
+	model := quickstarts.NewQuickstartModel()
+	model.Quickstarts["jenkins-x-quickstarts/node-http"] = &quickstarts.Quickstart{
+		ID:             "jenkins-x-quickstarts/node-http",
+		Name:           "node-http",
+		Language:       "HTML",
+		DownloadZipURL: server.URL + "/node-http.zip",
+		GitProvider:    provider,
+	}
+	model.Quickstarts["jenkins-x-quickstarts/golang-http"] = &quickstarts.Quickstart{
+		ID:             "jenkins-x-quickstarts/golang-http",
+		Name:           "golang-http",
+		Language:       "Go",
+		DownloadZipURL: server.URL + "/golang-http.zip",
+		GitProvider:    provider,
+	}
+
+	o := &StepQuickstartIndexOptions{}
+	o.applyQuickstartManifests(model)
+
+	node := model.Quickstarts["jenkins-x-quickstarts/node-http"]
+	assert.Equal(t, "javascript", node.Language)
+	assert.Equal(t, "express", node.Framework)
+	assert.Equal(t, []string{"http"}, node.Tags)
+
+	golang := model.Quickstarts["jenkins-x-quickstarts/golang-http"]
+	assert.Equal(t, "Go", golang.Language, "a quickstart without a manifest should keep its metadata")
+}
@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/terraform"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// maxPlanCommentLength the most of a plan which is included in a Pull Request comment
+	maxPlanCommentLength = 20000
+
+	// terraformPlanSecretPrefix the prefix of the names of the secrets which store the reviewed plans of the clusters
+	terraformPlanSecretPrefix = "jx-terraform-plan-"
+	// terraformPlanKind the kind label of the secrets which store the reviewed plans
+	terraformPlanKind = "terraform-plan"
+	// terraformPlanKey the key of the plan in the secret of a reviewed plan
+	terraformPlanKey = "plan"
+	// labelTerraformCluster the label of the secret of a reviewed plan with the name of the cluster
+	labelTerraformCluster = "jenkins.io/terraform-cluster"
+
+	// TerraformAppliedConfigMap the config map which records the commit of the organisation repository which was
+	// last applied to each cluster
+	TerraformAppliedConfigMap = "jx-terraform-applied"
+)
+
+// StepTerraformOptions contains the command line flags
+type StepTerraformOptions struct {
+	StepOptions
+}
+
+// StepTerraformClusterOptions the options for choosing the clusters of an organisation repository to plan or apply
+type StepTerraformClusterOptions struct {
+	Dir      string
+	Base     string
+	Clusters []string
+	All      bool
+}
+
+// TerraformClusterPlan the Terraform plan of a cluster in an organisation repository
+type TerraformClusterPlan struct {
+	Cluster Cluster
+	Path    string
+	Plan    string
+	Error   error
+}
+
+// NewCmdStepTerraform creates the command for: jx step terraform
+func NewCmdStepTerraform(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepTerraformOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "terraform",
+		Short: "terraform step actions for the clusters of an organisation repository",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdStepTerraformApply(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepTerraformPlan(f, in, out, errOut))
+
+	return cmd
+}
+
+// Run implements this command
+func (o *StepTerraformOptions) Run() error {
+	return o.Cmd.Help()
+}
+
+func (o *StepTerraformClusterOptions) addTerraformClusterFlags(cmd *cobra.Command, baseDescription string) {
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "The directory of the organisation repository")
+	cmd.Flags().StringVarP(&o.Base, "base", "", "", "The git revision to compare with to find the changed clusters. "+baseDescription)
+	cmd.Flags().StringArrayVarP(&o.Clusters, "cluster", "c", []string{}, "The names of the clusters rather than the changed clusters")
+	cmd.Flags().BoolVarP(&o.All, "all", "a", false, "All the clusters rather than the changed clusters")
+}
+
+// selectTerraformClusters returns the clusters of the organisation repository which were chosen or changed since the
+// base revision
+func (o *CommonOptions) selectTerraformClusters(options *StepTerraformClusterOptions) ([]*TerraformClusterPlan, error) {
+	clusters, err := LoadOrganisationClusters(options.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if options.All {
+		return clusters, nil
+	}
+	if len(options.Clusters) > 0 {
+		answer := []*TerraformClusterPlan{}
+		for _, name := range options.Clusters {
+			found := false
+			for _, c := range clusters {
+				if c.Cluster.Name() == name {
+					answer = append(answer, c)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("no cluster %s in the organisation repository %s", name, options.Dir)
+			}
+		}
+		return answer, nil
+	}
+	output, err := o.getCommandOutput(options.Dir, "git", "diff", "--name-only", options.Base+"...HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the files changed since %s in %s: %s", options.Base, options.Dir, err)
+	}
+	return ChangedClusters(clusters, strings.Split(output, "\n")), nil
+}
+
+// LoadOrganisationClusters loads the clusters from their Terraform variables in the clusters folder of an
+// organisation repository
+func LoadOrganisationClusters(dir string) ([]*TerraformClusterPlan, error) {
+	fileNames, err := filepath.Glob(filepath.Join(dir, Clusters, "*", Terraform, "terraform.tfvars"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(fileNames)
+	answer := []*TerraformClusterPlan{}
+	for _, fileName := range fileNames {
+		path := filepath.Dir(fileName)
+		name := filepath.Base(filepath.Dir(path))
+		provider, err := terraform.ReadValueFromFile(fileName, "provider")
+		if err != nil {
+			return nil, err
+		}
+		c, err := newTerraformCluster(name, provider)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cluster %s from %s: %s", name, fileName, err)
+		}
+		c.ParseTfVarsFile(fileName)
+		answer = append(answer, &TerraformClusterPlan{
+			Cluster: c,
+			Path:    path,
+		})
+	}
+	return answer, nil
+}
+
+// ChangedClusters returns the clusters which have a file in the changed files of the organisation repository
+func ChangedClusters(clusters []*TerraformClusterPlan, changedFiles []string) []*TerraformClusterPlan {
+	answer := []*TerraformClusterPlan{}
+	for _, c := range clusters {
+		prefix := Clusters + "/" + c.Cluster.Name() + "/"
+		for _, file := range changedFiles {
+			if strings.HasPrefix(filepath.ToSlash(strings.TrimSpace(file)), prefix) {
+				answer = append(answer, c)
+				break
+			}
+		}
+	}
+	return answer
+}
+
+// TerraformPlanComment returns the markdown of a Pull Request comment showing the plans of the clusters
+func TerraformPlanComment(plans []*TerraformClusterPlan) string {
+	var buffer bytes.Buffer
+	for _, p := range plans {
+		buffer.WriteString(fmt.Sprintf("### Terraform plan for cluster `%s` on %s\n\n", p.Cluster.Name(), strings.ToUpper(p.Cluster.Provider())))
+		if p.Error != nil {
+			buffer.WriteString(fmt.Sprintf(":x: The plan failed: %s\n\n", p.Error.Error()))
+		} else {
+			summary := planSummary(p.Plan)
+			if summary != "" {
+				buffer.WriteString(fmt.Sprintf("**%s**\n\n", summary))
+			}
+			if strings.Contains(p.Plan, "forces new resource") {
+				buffer.WriteString(":warning: This plan is destructive and replaces resources of the cluster. It is only applied with `--ignore-terraform-warnings`\n\n")
+			}
+		}
+		plan := strings.TrimSpace(p.Plan)
+		if plan != "" {
+			if len(plan) > maxPlanCommentLength {
+				plan = plan[:maxPlanCommentLength] + "\n... truncated"
+			}
+			buffer.WriteString("<details><summary>Show plan</summary>\n\n```\n")
+			buffer.WriteString(plan)
+			buffer.WriteString("\n```\n</details>\n\n")
+		}
+	}
+	buffer.WriteString("These plans are applied by `jx step terraform apply` once this Pull Request is merged. If the state of a cluster changes before then its plan is not applied\n")
+	return buffer.String()
+}
+
+// planSummary returns the line of the plan which summarises the changes
+func planSummary(plan string) string {
+	for _, line := range strings.Split(plan, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Plan:") || strings.HasPrefix(line, "No changes.") {
+			return line
+		}
+	}
+	return ""
+}
+
+func terraformClusterNames(plans []*TerraformClusterPlan) string {
+	names := []string{}
+	for _, p := range plans {
+		names = append(names, util.ColorInfo(p.Cluster.Name()))
+	}
+	return strings.Join(names, ", ")
+}
+
+// terraformTree returns the hash of the tree of the files of the organisation repository which identifies the
+// reviewed plans. A Pull Request which is up to date with its base branch has the same tree once it is merged
+func (o *CommonOptions) terraformTree(dir string) (string, error) {
+	tree, err := o.getCommandOutput(dir, "git", "rev-parse", "HEAD^{tree}")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find the git tree of %s", dir)
+	}
+	return strings.TrimSpace(tree), nil
+}
+
+func terraformPlanSecretName(clusterName string, tree string) string {
+	if len(tree) > 12 {
+		tree = tree[:12]
+	}
+	return terraformPlanSecretPrefix + kube.ToValidName(clusterName) + "-" + tree
+}
+
+// SaveTerraformPlan stores the reviewed plan file of the cluster for the git tree in a secret so that it can be
+// applied once the Pull Request is merged
+func SaveTerraformPlan(kubeClient kubernetes.Interface, ns string, clusterName string, tree string, planFile string) error {
+	data, err := ioutil.ReadFile(planFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the plan %s", planFile)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: terraformPlanSecretName(clusterName, tree),
+			Labels: map[string]string{
+				kube.LabelKind:        terraformPlanKind,
+				labelTerraformCluster: kube.ToValidName(clusterName),
+			},
+		},
+		Data: map[string][]byte{
+			terraformPlanKey: data,
+		},
+	}
+	secrets := kubeClient.CoreV1().Secrets(ns)
+	_, err = secrets.Create(secret)
+	if apierrors.IsAlreadyExists(err) {
+		_, err = secrets.Update(secret)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to save the plan of cluster %s in secret %s", clusterName, secret.Name)
+	}
+	return nil
+}
+
+// LoadTerraformPlan writes the reviewed plan of the cluster for the git tree to the plan file returning false if
+// no plan was reviewed for the tree
+func LoadTerraformPlan(kubeClient kubernetes.Interface, ns string, clusterName string, tree string, planFile string) (bool, error) {
+	name := terraformPlanSecretName(clusterName, tree)
+	secret, err := kubeClient.CoreV1().Secrets(ns).Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to load the plan of cluster %s from secret %s", clusterName, name)
+	}
+	data := secret.Data[terraformPlanKey]
+	if len(data) == 0 {
+		return false, nil
+	}
+	err = ioutil.WriteFile(planFile, data, util.DefaultWritePermissions)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to write the plan %s", planFile)
+	}
+	return true, nil
+}
+
+// DeleteTerraformPlans deletes the reviewed plans of the cluster. Once a plan has been applied the other plans of the
+// cluster are stale and cannot be applied
+func DeleteTerraformPlans(kubeClient kubernetes.Interface, ns string, clusterName string) error {
+	selector := fmt.Sprintf("%s=%s,%s=%s", kube.LabelKind, terraformPlanKind, labelTerraformCluster, kube.ToValidName(clusterName))
+	secrets := kubeClient.CoreV1().Secrets(ns)
+	list, err := secrets.List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the plans of cluster %s", clusterName)
+	}
+	for _, secret := range list.Items {
+		err = secrets.Delete(secret.Name, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete the plan %s of cluster %s", secret.Name, clusterName)
+		}
+	}
+	return nil
+}
+
+// TerraformAppliedCommits returns the commit of the organisation repository which was last applied to each cluster
+// indexed by the name of the cluster
+func TerraformAppliedCommits(kubeClient kubernetes.Interface, ns string) (map[string]string, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(TerraformAppliedConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load config map %s", TerraformAppliedConfigMap)
+	}
+	if cm.Data == nil {
+		return map[string]string{}, nil
+	}
+	return cm.Data, nil
+}
+
+// RecordTerraformAppliedCommit records the commit of the organisation repository which was applied to the cluster
+func RecordTerraformAppliedCommit(kubeClient kubernetes.Interface, ns string, clusterName string, sha string) error {
+	configMaps := kubeClient.CoreV1().ConfigMaps(ns)
+	cm, err := configMaps.Get(TerraformAppliedConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: TerraformAppliedConfigMap,
+			},
+			Data: map[string]string{
+				clusterName: sha,
+			},
+		}
+		_, err = configMaps.Create(cm)
+	} else if err == nil {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[clusterName] = sha
+		_, err = configMaps.Update(cm)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to record the commit applied to cluster %s in config map %s", clusterName, TerraformAppliedConfigMap)
+	}
+	return nil
+}
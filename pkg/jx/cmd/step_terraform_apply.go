@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	"k8s.io/client-go/kubernetes"
+)
+
+// StepTerraformApplyOptions contains the command line flags
+type StepTerraformApplyOptions struct {
+	StepOptions
+	StepTerraformClusterOptions
+
+	IgnoreTerraformWarnings bool
+	ApplyUnreviewed         bool
+}
+
+var (
+	stepTerraformApplyLong = templates.LongDesc(`
+		Applies the Terraform plans of the clusters of an organisation repository which changed since the commit last
+		applied to each cluster.
+
+		This step is run by the release pipeline of an organisation repository created by 'jx create terraform' so that
+		the plans reviewed on a Pull Request are only applied once it is merged. The plan saved by 'jx step terraform plan'
+		for the merged files is applied rather than a new plan so that exactly what was reviewed is applied. If the state
+		of a cluster has changed since its plan was reviewed Terraform refuses to apply the plan; run
+		'jx step terraform plan' in the organisation repository to review a new plan and then apply it. If no plan was
+		reviewed for the merged files, such as after a direct push or when the Pull Request was not up to date with its
+		base branch, the step fails so that unreviewed changes are never applied; push a Pull Request with the changes
+		to review their plan or use --apply-unreviewed to create and apply a new plan.
+
+		Destructive plans are not applied unless --ignore-terraform-warnings is used.
+`)
+
+	stepTerraformApplyExample = templates.Examples(`
+		# Apply the plans of the clusters changed since they were last applied
+		jx step terraform apply
+
+		# Apply the plan of the dev cluster
+		jx step terraform apply -c dev
+	`)
+)
+
+// NewCmdStepTerraformApply creates the command for: jx step terraform apply
+func NewCmdStepTerraformApply(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepTerraformApplyOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "apply",
+		Short:   "Applies the Terraform plans of the changed clusters of an organisation repository",
+		Long:    stepTerraformApplyLong,
+		Example: stepTerraformApplyExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addTerraformClusterFlags(cmd, "Defaults to the commit last applied to each cluster")
+	cmd.Flags().BoolVarP(&options.IgnoreTerraformWarnings, "ignore-terraform-warnings", "", false, "Ignore any warnings about the Terraform plan being potentially destructive")
+	cmd.Flags().BoolVarP(&options.ApplyUnreviewed, "apply-unreviewed", "", false, "Create and apply a new plan for the clusters which have no plan reviewed for the current commit")
+
+	options.addCommonFlags(cmd)
+
+	return cmd
+}
+
+// Run implements this command
+func (o *StepTerraformApplyOptions) Run() error {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	var plans []*TerraformClusterPlan
+	if o.Base == "" && !o.All && len(o.Clusters) == 0 {
+		plans, err = o.clustersChangedSinceApplied(kubeClient, ns)
+	} else {
+		plans, err = o.selectTerraformClusters(&o.StepTerraformClusterOptions)
+	}
+	if err != nil {
+		return err
+	}
+	if len(plans) == 0 {
+		log.Infof("No clusters were changed since they were last applied\n")
+		return nil
+	}
+	log.Infof("Applying the Terraform plans of clusters %s\n", terraformClusterNames(plans))
+
+	sha, err := o.getCommandOutput(o.Dir, "git", "rev-parse", "HEAD")
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the commit of %s", o.Dir)
+	}
+	sha = strings.TrimSpace(sha)
+	tree, err := o.terraformTree(o.Dir)
+	if err != nil {
+		return err
+	}
+
+	createOptions := &CreateTerraformOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: o.CommonOptions,
+		},
+		Flags: Flags{
+			IgnoreTerraformWarnings: o.IgnoreTerraformWarnings,
+		},
+		ApplySavedPlans: true,
+	}
+	// the plans were reviewed on the Pull Request, or --apply-unreviewed was used, so they are applied without asking
+	createOptions.BatchMode = true
+
+	failed := []string{}
+	for _, p := range plans {
+		name := p.Cluster.Name()
+		log.Infof("\nApplying the plan of cluster %s\n", util.ColorInfo(name))
+		err = o.applyTerraformClusterPlan(createOptions, kubeClient, ns, p, tree, sha)
+		if err != nil {
+			log.Warnf("Failed to apply the plan of cluster %s: %s\n", name, err)
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to apply the plans of clusters %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// applyTerraformClusterPlan applies the plan reviewed for the tree to the cluster, or a new plan if none was
+// reviewed and --apply-unreviewed is used, and records the applied commit
+func (o *StepTerraformApplyOptions) applyTerraformClusterPlan(createOptions *CreateTerraformOptions, kubeClient kubernetes.Interface, ns string, p *TerraformClusterPlan, tree string, sha string) error {
+	clusterName := p.Cluster.ClusterName()
+	reviewed, err := LoadTerraformPlan(kubeClient, ns, clusterName, tree, filepath.Join(p.Path, TerraformPlanFile))
+	if err != nil {
+		return err
+	}
+	if !reviewed {
+		if !o.ApplyUnreviewed {
+			return fmt.Errorf("no plan of cluster %s was reviewed for commit %s. Review its plan on a Pull Request which is up to date with its base branch or use --apply-unreviewed", p.Cluster.Name(), sha)
+		}
+		log.Warnf("No plan of cluster %s was reviewed for commit %s so a new plan is applied\n", p.Cluster.Name(), sha)
+	}
+	err = createOptions.applyTerraformCluster(p.Cluster, p.Path)
+	if err != nil {
+		return err
+	}
+	err = DeleteTerraformPlans(kubeClient, ns, clusterName)
+	if err != nil {
+		log.Warnf("%s\n", err)
+	}
+	return RecordTerraformAppliedCommit(kubeClient, ns, clusterName, sha)
+}
+
+// clustersChangedSinceApplied returns the clusters of the organisation repository which changed since the commit last
+// applied to them along with the clusters which have never been applied
+func (o *StepTerraformApplyOptions) clustersChangedSinceApplied(kubeClient kubernetes.Interface, ns string) ([]*TerraformClusterPlan, error) {
+	clusters, err := LoadOrganisationClusters(o.Dir)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := TerraformAppliedCommits(kubeClient, ns)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*TerraformClusterPlan{}
+	for _, c := range clusters {
+		base := applied[c.Cluster.ClusterName()]
+		if base == "" {
+			log.Infof("Cluster %s has not been applied by the pipeline yet\n", util.ColorInfo(c.Cluster.Name()))
+			answer = append(answer, c)
+			continue
+		}
+		output, err := o.getCommandOutput(o.Dir, "git", "diff", "--name-only", base+"...HEAD")
+		if err != nil {
+			log.Warnf("Failed to find the files changed since commit %s was applied to cluster %s so it is applied again: %s\n", base, c.Cluster.Name(), err)
+			answer = append(answer, c)
+			continue
+		}
+		answer = append(answer, ChangedClusters([]*TerraformClusterPlan{c}, strings.Split(output, "\n"))...)
+	}
+	return answer, nil
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestApplyTerraformClusterPlanRequiresReviewedPlan(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-terraform-apply")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cluster := &GKECluster{Organisation: "acme", ProjectID: "acme-project", Zone: "europe-west1-b"}
+	cluster.SetName("dev")
+	cluster.SetProvider("gke")
+	plan := &TerraformClusterPlan{Cluster: cluster, Path: dir}
+	kubeClient := fake.NewSimpleClientset()
+
+	o := &StepTerraformApplyOptions{}
+	err = o.applyTerraformClusterPlan(&CreateTerraformOptions{}, kubeClient, "jx", plan, "tree", "sha")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--apply-unreviewed")
+
+	applied, err := TerraformAppliedCommits(kubeClient, "jx")
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
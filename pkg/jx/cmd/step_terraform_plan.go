@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/terraform"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepTerraformPlanOptions contains the command line flags
+type StepTerraformPlanOptions struct {
+	StepOptions
+	StepTerraformClusterOptions
+
+	PullRequest string
+}
+
+var (
+	stepTerraformPlanLong = templates.LongDesc(`
+		Shows the Terraform plans of the clusters of an organisation repository which were changed by a Pull Request
+		and comments them on the Pull Request so that they can be reviewed before they are applied.
+
+		This step is run by the Pull Request pipeline of an organisation repository created by 'jx create terraform'.
+		The Terraform state is locked while the plans are created so that they are not changed by other pipelines.
+
+		Each plan is saved in a secret in the development namespace so that 'jx step terraform apply' applies exactly
+		the plan which was reviewed once the Pull Request is merged.
+`)
+
+	stepTerraformPlanExample = templates.Examples(`
+		# Comment the plans of the clusters changed by the Pull Request being built
+		jx step terraform plan
+
+		# Comment the plan of the dev cluster on Pull Request 12
+		jx step terraform plan -c dev --pr 12
+	`)
+)
+
+// NewCmdStepTerraformPlan creates the command for: jx step terraform plan
+func NewCmdStepTerraformPlan(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepTerraformPlanOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "plan",
+		Short:   "Comments the Terraform plans of the changed clusters on the Pull Request of an organisation repository",
+		Long:    stepTerraformPlanLong,
+		Example: stepTerraformPlanExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addTerraformClusterFlags(cmd, "Defaults to $PULL_BASE_SHA or origin/master")
+	cmd.Flags().StringVarP(&options.PullRequest, "pr", "", "", "The Pull Request number to comment on. Defaults to the Pull Request being built. If there is no Pull Request the plans are only logged")
+
+	options.addCommonFlags(cmd)
+
+	return cmd
+}
+
+// Run implements this command
+func (o *StepTerraformPlanOptions) Run() error {
+	if o.Base == "" {
+		o.Base = os.Getenv(PULL_BASE_SHA)
+		if o.Base == "" {
+			o.Base = "origin/master"
+		}
+	}
+	plans, err := o.selectTerraformClusters(&o.StepTerraformClusterOptions)
+	if err != nil {
+		return err
+	}
+	if len(plans) == 0 {
+		log.Infof("No clusters were changed since %s\n", util.ColorInfo(o.Base))
+		return nil
+	}
+	log.Infof("Creating the Terraform plans of clusters %s\n", terraformClusterNames(plans))
+
+	tree, err := o.terraformTree(o.Dir)
+	if err != nil {
+		return err
+	}
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+
+	createOptions := &CreateTerraformOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: o.CommonOptions,
+		},
+	}
+	failed := []string{}
+	for _, p := range plans {
+		planFile := filepath.Join(p.Path, TerraformPlanFile)
+		p.Plan, p.Error = createOptions.planTerraformCluster(p.Cluster, p.Path, planFile)
+		if p.Error == nil {
+			// the reviewed plan is saved so that exactly this plan is applied once the Pull Request is merged
+			p.Error = SaveTerraformPlan(kubeClient, ns, p.Cluster.ClusterName(), tree, planFile)
+		}
+		os.Remove(planFile)
+		if p.Error != nil {
+			log.Warnf("Failed to create the plan of cluster %s: %s\n", p.Cluster.Name(), p.Error)
+			failed = append(failed, p.Cluster.Name())
+		}
+	}
+
+	comment := TerraformPlanComment(plans)
+	prNumber, err := o.pullRequestNumber()
+	if err != nil {
+		return err
+	}
+	if prNumber == 0 {
+		log.Infof("No Pull Request to comment on so logging the plans\n\n%s\n", comment)
+	} else {
+		err = o.commentOnPullRequest(prNumber, comment)
+		if err != nil {
+			return err
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to create the plans of clusters %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// planTerraformCluster initialises the Terraform plan of the cluster and returns the plan of its changes which is
+// saved to the plan file
+func (options *CreateTerraformOptions) planTerraformCluster(c Cluster, path string, planFile string) (string, error) {
+	serviceAccountPath, err := options.prepareTerraformCluster(c, path)
+	if err != nil {
+		return "", err
+	}
+	err = terraform.Init(path, serviceAccountPath)
+	if err != nil {
+		return "", err
+	}
+	return terraform.PlanWithoutColor(path, filepath.Join(path, "terraform.tfvars"), serviceAccountPath, planFile)
+}
+
+// pullRequestNumber returns the Pull Request number from the flag or from the Pull Request being built
+func (o *StepTerraformPlanOptions) pullRequestNumber() (int, error) {
+	pr := o.PullRequest
+	if pr == "" {
+		pr = os.Getenv("PULL_NUMBER")
+	}
+	if pr == "" {
+		branch := os.Getenv("BRANCH_NAME")
+		if strings.HasPrefix(branch, "PR-") {
+			pr = strings.TrimPrefix(branch, "PR-")
+		}
+	}
+	if pr == "" {
+		return 0, nil
+	}
+	prNumber, err := strconv.Atoi(pr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Pull Request number %s: %s", pr, err)
+	}
+	return prNumber, nil
+}
+
+func (o *StepTerraformPlanOptions) commentOnPullRequest(prNumber int, comment string) error {
+	gitInfo, err := o.Git().Info(o.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to find the git repository in directory %s: %s", o.Dir, err)
+	}
+	provider, err := o.gitProviderForURL(gitInfo.URL, "user name to comment on the Pull Request")
+	if err != nil {
+		return err
+	}
+	pr := &gits.GitPullRequest{
+		Owner:  gitInfo.Organisation,
+		Repo:   gitInfo.Name,
+		Number: &prNumber,
+	}
+	err = provider.AddPRComment(pr, comment)
+	if err != nil {
+		return fmt.Errorf("failed to comment on Pull Request %d of %s/%s: %s", prNumber, gitInfo.Organisation, gitInfo.Name, err)
+	}
+	log.Infof("Commented the plans on Pull Request %s of %s/%s\n", util.ColorInfo(prNumber), gitInfo.Organisation, gitInfo.Name)
+	return nil
+}
@@ -0,0 +1,143 @@
+package cmd_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLoadOrganisationClusters(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-organisation-clusters")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	dev := &cmd.GKECluster{Organisation: "acme", ProjectID: "acme-project", Zone: "europe-west1-b"}
+	dev.SetName("dev")
+	dev.SetProvider("gke")
+	prod := &cmd.EKSCluster{Organisation: "acme", Region: "eu-west-1"}
+	prod.SetName("prod")
+	prod.SetProvider("eks")
+	for _, c := range []cmd.Cluster{dev, prod} {
+		path := filepath.Join(dir, cmd.Clusters, c.Name(), cmd.Terraform)
+		require.NoError(t, os.MkdirAll(path, cmd.DefaultWritePermissions))
+		require.NoError(t, c.CreateTfVarsFile(filepath.Join(path, "terraform.tfvars")))
+	}
+
+	clusters, err := cmd.LoadOrganisationClusters(dir)
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+	assert.Equal(t, "dev", clusters[0].Cluster.Name())
+	assert.IsType(t, &cmd.GKECluster{}, clusters[0].Cluster)
+	assert.Equal(t, "acme-dev", clusters[0].Cluster.ClusterName())
+	assert.Equal(t, filepath.Join(dir, cmd.Clusters, "dev", cmd.Terraform), clusters[0].Path)
+	assert.Equal(t, "prod", clusters[1].Cluster.Name())
+	assert.IsType(t, &cmd.EKSCluster{}, clusters[1].Cluster)
+	assert.Equal(t, "eks_eu-west-1_acme-prod", clusters[1].Cluster.Context())
+
+	changed := cmd.ChangedClusters(clusters, []string{"README.md", "clusters/prod/terraform/terraform.tfvars", ""})
+	require.Len(t, changed, 1)
+	assert.Equal(t, "prod", changed[0].Cluster.Name())
+
+	assert.Empty(t, cmd.ChangedClusters(clusters, []string{"clusters/production/terraform/main.tf"}))
+}
+
+func TestTerraformPlanComment(t *testing.T) {
+	t.Parallel()
+	dev := &cmd.GKECluster{}
+	dev.SetName("dev")
+	dev.SetProvider("gke")
+	staging := &cmd.AKSCluster{}
+	staging.SetName("staging")
+	staging.SetProvider("aks")
+	prod := &cmd.EKSCluster{}
+	prod.SetName("prod")
+	prod.SetProvider("eks")
+
+	comment := cmd.TerraformPlanComment([]*cmd.TerraformClusterPlan{
+		{
+			Cluster: dev,
+			Plan:    "  ~ google_container_node_pool.jx_node_pool\n      autoscaling.0.max_node_count: \"5\" => \"7\"\n\nPlan: 0 to add, 1 to change, 0 to destroy.\n",
+		},
+		{
+			Cluster: staging,
+			Plan:    "-/+ azurerm_kubernetes_cluster.jx (new resource required)\n      location: \"westeurope\" => \"northeurope\" (forces new resource)\n\nPlan: 1 to add, 0 to change, 1 to destroy.\n",
+		},
+		{
+			Cluster: prod,
+			Plan:    "Error: Error locking state",
+			Error:   errors.New("exit status 1"),
+		},
+	})
+
+	assert.Contains(t, comment, "### Terraform plan for cluster `dev` on GKE")
+	assert.Contains(t, comment, "**Plan: 0 to add, 1 to change, 0 to destroy.**")
+	assert.Contains(t, comment, "autoscaling.0.max_node_count")
+	assert.Contains(t, comment, "### Terraform plan for cluster `staging` on AKS")
+	assert.Contains(t, comment, ":warning: This plan is destructive")
+	assert.Contains(t, comment, "### Terraform plan for cluster `prod` on EKS")
+	assert.Contains(t, comment, ":x: The plan failed: exit status 1")
+	assert.Contains(t, comment, "Error locking state")
+	assert.Equal(t, 1, strings.Count(comment, ":warning:"))
+}
+
+func TestReviewedTerraformPlans(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-reviewed-terraform-plans")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	kubeClient := fake.NewSimpleClientset()
+	ns := "jx"
+	tree := "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+	planFile := filepath.Join(dir, cmd.TerraformPlanFile)
+	require.NoError(t, ioutil.WriteFile(planFile, []byte("reviewed plan"), cmd.DefaultWritePermissions))
+	require.NoError(t, cmd.SaveTerraformPlan(kubeClient, ns, "acme-dev", tree, planFile))
+	require.NoError(t, cmd.SaveTerraformPlan(kubeClient, ns, "acme-dev", "0123456789abcdef", planFile))
+	require.NoError(t, cmd.SaveTerraformPlan(kubeClient, ns, "acme-prod", tree, planFile))
+	require.NoError(t, os.Remove(planFile))
+
+	loaded, err := cmd.LoadTerraformPlan(kubeClient, ns, "acme-dev", "fedcba9876543210", planFile)
+	require.NoError(t, err)
+	assert.False(t, loaded, "no plan was reviewed for the tree")
+
+	loaded, err = cmd.LoadTerraformPlan(kubeClient, ns, "acme-dev", tree, planFile)
+	require.NoError(t, err)
+	require.True(t, loaded)
+	data, err := ioutil.ReadFile(planFile)
+	require.NoError(t, err)
+	assert.Equal(t, "reviewed plan", string(data))
+
+	require.NoError(t, cmd.DeleteTerraformPlans(kubeClient, ns, "acme-dev"))
+	secrets, err := kubeClient.CoreV1().Secrets(ns).List(metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, secrets.Items, 1)
+	assert.Equal(t, "jx-terraform-plan-acme-prod-4b825dc642cb", secrets.Items[0].Name)
+}
+
+func TestTerraformAppliedCommits(t *testing.T) {
+	t.Parallel()
+	kubeClient := fake.NewSimpleClientset()
+	ns := "jx"
+
+	applied, err := cmd.TerraformAppliedCommits(kubeClient, ns)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	require.NoError(t, cmd.RecordTerraformAppliedCommit(kubeClient, ns, "acme-dev", "abc123"))
+	require.NoError(t, cmd.RecordTerraformAppliedCommit(kubeClient, ns, "acme-prod", "def456"))
+	require.NoError(t, cmd.RecordTerraformAppliedCommit(kubeClient, ns, "acme-dev", "789abc"))
+
+	applied, err = cmd.TerraformAppliedCommits(kubeClient, ns)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"acme-dev": "789abc", "acme-prod": "def456"}, applied)
+}
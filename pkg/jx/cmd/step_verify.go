@@ -66,6 +66,8 @@ func NewCmdStepVerify(f Factory, in terminal.FileReader, out terminal.FileWriter
 	cmd.Flags().Int32VarP(&options.Pods, "pods", "p", 1, "Number of expected pods to be running")
 	cmd.Flags().Int32VarP(&options.Restarts, "restarts", "r", 0, "Maximum number of restarts which are acceptable within the given time")
 
+	cmd.AddCommand(NewCmdStepVerifySignatures(f, in, out, errOut))
+
 	return cmd
 }
 
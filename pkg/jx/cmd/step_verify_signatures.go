@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepVerifySignaturesOptions contains the command line flags
+type StepVerifySignaturesOptions struct {
+	StepOptions
+
+	Dir                string
+	Range              string
+	Tags               []string
+	Format             string
+	AllowedSignersFile string
+	AllowUnknown       bool
+}
+
+var (
+	stepVerifySignaturesLong = templates.LongDesc(`
+		Verifies that the commits in a range, and optionally tags, of the git repository are signed with trusted keys.
+
+		GPG signatures are verified with the keys of the GPG keyring, e.g. as generated by 'jx step gpg credentials'. 
+		SSH signatures are verified with an allowed signers file.
+`)
+
+	stepVerifySignaturesExample = templates.Examples(`
+		# Verify the commits of the current branch which are not on master
+		jx step verify signatures
+
+		# Verify the commits since the last release and the release tag
+		jx step verify signatures v1.0.0..HEAD --tag v1.0.1
+
+		# Verify SSH signatures
+		jx step verify signatures --format ssh --allowed-signers ~/.ssh/allowed_signers
+	`)
+)
+
+// NewCmdStepVerifySignatures creates a command object for the "step verify signatures" command
+func NewCmdStepVerifySignatures(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepVerifySignaturesOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "signatures [range]",
+		Short:   "Verifies the signatures of the commits and tags of a git repository",
+		Long:    stepVerifySignaturesLong,
+		Example: stepVerifySignaturesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory of the git repository. Defaults to the current directory")
+	cmd.Flags().StringVarP(&options.Range, "range", "r", "origin/master..HEAD", "The range of commits to verify")
+	cmd.Flags().StringArrayVarP(&options.Tags, "tag", "t", []string{}, "The tags to verify")
+	cmd.Flags().StringVarP(&options.Format, "format", "f", "", fmt.Sprintf("The format of the signatures: %s. Defaults to $%s", strings.Join(gits.SigningFormats, ", "), gits.SigningFormatEnvVar))
+	cmd.Flags().StringVarP(&options.AllowedSignersFile, "allowed-signers", "", "", fmt.Sprintf("The allowed signers file to verify SSH signatures with. Defaults to $%s", gits.AllowedSignersEnvVar))
+	cmd.Flags().BoolVarP(&options.AllowUnknown, "allow-unknown", "", false, "Accepts good signatures made with keys whose validity is unknown, such as GPG keys which are imported but not trusted")
+
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *StepVerifySignaturesOptions) Run() error {
+	revisionRange := o.Range
+	if len(o.Args) > 0 {
+		revisionRange = o.Args[0]
+	}
+	config := gits.SigningConfigFromEnv()
+	if config == nil {
+		config = &gits.SigningConfig{
+			Format:             o.Format,
+			AllowedSignersFile: o.AllowedSignersFile,
+		}
+	}
+	// lets only verify with the key format and allowed signers rather than the key used to sign
+	config.Key = ""
+	if o.Format != "" {
+		config.Format = o.Format
+	}
+	if o.AllowedSignersFile != "" {
+		config.AllowedSignersFile = o.AllowedSignersFile
+	}
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+
+	signatures, err := gits.CommitSignatures(o.Dir, revisionRange, config)
+	if err != nil {
+		return err
+	}
+	invalid := 0
+	table := o.CreateTable()
+	table.AddRow("COMMIT", "SIGNATURE", "SIGNER", "SUBJECT")
+	for _, signature := range signatures {
+		status := util.ColorInfo(signature.Description())
+		if !signature.IsValid(o.AllowUnknown) {
+			status = util.ColorError(signature.Description())
+			invalid++
+		}
+		sha := signature.SHA
+		if len(sha) > 8 {
+			sha = sha[:8]
+		}
+		table.AddRow(sha, status, signature.Signer, signature.Subject)
+	}
+	if len(signatures) > 0 {
+		table.Render()
+	}
+
+	errs := []error{}
+	if invalid > 0 {
+		errs = append(errs, fmt.Errorf("%d of the %d commits in %s are not validly signed", invalid, len(signatures), revisionRange))
+	}
+	for _, tag := range o.Tags {
+		err = gits.VerifyTag(o.Dir, tag, config)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			log.Infof("The signature of tag %s is valid\n", util.ColorInfo(tag))
+		}
+	}
+	if len(errs) == 0 {
+		log.Infof("Verified the signatures of the %d commits in %s\n", len(signatures), util.ColorInfo(revisionRange))
+	}
+	return util.CombineErrors(errs...)
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/jenkins-x/jx/pkg/webhooks"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepWebhookOptions contains the command line flags
+type StepWebhookOptions struct {
+	StepOptions
+}
+
+// WebhookTargetOptions the flags which choose where webhook events are sent
+type WebhookTargetOptions struct {
+	TargetURL string
+	Secret    string
+	GitServer string
+}
+
+// NewCmdStepWebhook creates a command object for the "step webhook" command
+func NewCmdStepWebhook(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepWebhookOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "webhook",
+		Short: "webhook [command]",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.AddCommand(NewCmdStepWebhookReplay(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *StepWebhookOptions) Run() error {
+	return o.Cmd.Help()
+}
+
+func (t *WebhookTargetOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&t.TargetURL, "target-url", "", "", "The URL to send the webhook to. Defaults to the Prow hook or the Jenkins webhook endpoint of the team")
+	cmd.Flags().StringVarP(&t.Secret, "secret", "", "", "The secret to sign the webhook with. Defaults to the Prow HMAC token when sending to Prow")
+	cmd.Flags().StringVarP(&t.GitServer, "git-server", "", "", "The git server of the repository. Defaults to the git server of the team")
+}
+
+// webhookTarget returns the URL of the Prow hook or the Jenkins webhook endpoint for the 'owner/name' repository
+// and the secret to sign the webhooks with
+func (o *CommonOptions) webhookTarget(target *WebhookTargetOptions, repository string) (string, string, error) {
+	if target.TargetURL != "" {
+		return target.TargetURL, target.Secret, nil
+	}
+	_, _, err := o.KubeClient()
+	if err != nil {
+		return "", "", err
+	}
+	_, _, err = o.JXClient()
+	if err != nil {
+		return "", "", err
+	}
+	isProw, err := o.isProw()
+	if err != nil {
+		return "", "", err
+	}
+	if isProw {
+		hookURL, hmacToken, err := o.prowWebhookEndpoint()
+		if err != nil {
+			return "", "", err
+		}
+		secret := target.Secret
+		if secret == "" {
+			secret = hmacToken
+		}
+		return hookURL, secret, nil
+	}
+
+	gitServer := target.GitServer
+	if gitServer == "" {
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return "", "", err
+		}
+		gitServer = teamSettings.GitServer
+	}
+	if gitServer == "" {
+		return "", "", util.MissingOption("git-server")
+	}
+	if repository == "" {
+		return "", "", fmt.Errorf("The webhook has no repository so use --%s to say where to send it", "target-url")
+	}
+	gitURL := util.UrlJoin(gitServer, repository)
+	provider, err := o.gitProviderForURL(gitURL, "user name to find the Jenkins webhook endpoint")
+	if err != nil {
+		return "", "", err
+	}
+	jenkinsURL := o.ExternalJenkinsBaseURL
+	if jenkinsURL == "" {
+		jenk, err := o.JenkinsClient()
+		if err != nil {
+			return "", "", err
+		}
+		jenkinsURL = jenk.BaseURL()
+	}
+	return util.UrlJoin(jenkinsURL, provider.JenkinsWebHookPath(gitURL, target.Secret)), target.Secret, nil
+}
+
+// sendWebhookEvent sends the event to the webhook endpoint of the team failing if it is not accepted
+func (o *CommonOptions) sendWebhookEvent(target *WebhookTargetOptions, event *webhooks.Event) error {
+	targetURL, secret, err := o.webhookTarget(target, event.Repository)
+	if err != nil {
+		return err
+	}
+	resp, err := webhooks.Send(nil, targetURL, event, secret)
+	if err != nil {
+		return fmt.Errorf("Failed to send the %s %s webhook to %s: %s", event.Kind, event.Type, targetURL, err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("The %s %s webhook was rejected by %s with status %s: %s", event.Kind, event.Type, targetURL, resp.Status, string(body))
+	}
+	log.Infof("Sent the %s %s webhook for %s to %s\n", event.Kind, util.ColorInfo(event.Type), util.ColorInfo(event.Repository), util.ColorInfo(targetURL))
+	return nil
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/webhooks"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepWebhookReplayOptions contains the command line flags
+type StepWebhookReplayOptions struct {
+	StepOptions
+	WebhookTargetOptions
+
+	RecorderURL string
+	EventsToken string
+	File        string
+	List        bool
+	Last        bool
+}
+
+var (
+	stepWebhookReplayLong = templates.LongDesc(`
+		Replays a webhook recorded by the webhook recorder addon to the Prow hook or the Jenkins webhook endpoint.
+
+		Use this to find out why a Pull Request or push did not trigger a pipeline. Install the recorder with 'jx create addon webhook-recorder'.
+`)
+
+	stepWebhookReplayExample = templates.Examples(`
+		# List the recorded webhooks
+		jx step webhook replay --list
+
+		# Replay a recorded webhook
+		jx step webhook replay 72d3162e-cc78-11e3-81ab-4c9367dc0958
+
+		# Replay the most recent webhook to a local endpoint
+		jx step webhook replay --last --target-url http://localhost:8888/hook --secret mysecret
+
+		# Replay a webhook saved as JSON
+		jx step webhook replay --file event.json
+	`)
+)
+
+// NewCmdStepWebhookReplay creates a command object for the "step webhook replay" command
+func NewCmdStepWebhookReplay(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepWebhookReplayOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "replay [id]",
+		Short:   "Replays a recorded webhook to the Prow hook or Jenkins",
+		Long:    stepWebhookReplayLong,
+		Example: stepWebhookReplayExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.RecorderURL, "recorder-url", "", "", "The URL of the webhook recorder. Defaults to the URL of the webhook recorder service")
+	cmd.Flags().StringVarP(&options.EventsToken, "events-token", "", "", "The token required to list the events of the webhook recorder. Defaults to the token in the Secret "+kube.SecretWebhookRecorder)
+	cmd.Flags().StringVarP(&options.File, "file", "f", "", "A JSON file of a webhook to replay rather than a recorded one")
+	cmd.Flags().BoolVarP(&options.List, "list", "l", false, "Lists the recorded webhooks")
+	cmd.Flags().BoolVarP(&options.Last, "last", "", false, "Replays the most recent webhook")
+	options.WebhookTargetOptions.addFlags(cmd)
+
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *StepWebhookReplayOptions) Run() error {
+	if o.File != "" {
+		event, err := webhooks.LoadEvent(o.File)
+		if err != nil {
+			return err
+		}
+		return o.sendWebhookEvent(&o.WebhookTargetOptions, event)
+	}
+
+	recorderURL := o.RecorderURL
+	token := o.EventsToken
+	if recorderURL == "" || token == "" {
+		kubeClient, ns, err := o.KubeClientAndDevNamespace()
+		if err != nil {
+			return err
+		}
+		if recorderURL == "" {
+			recorderURL, err = kube.GetServiceURLFromName(kubeClient, kube.ServiceWebhookRecorder, ns)
+			if err != nil {
+				return fmt.Errorf("Failed to find the webhook recorder. Is the webhook-recorder addon installed? %s", err)
+			}
+		}
+		if token == "" {
+			token, err = webhookRecorderEventsToken(kubeClient, ns, false)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var event *webhooks.Event
+	if len(o.Args) > 0 && !o.List {
+		var err error
+		event, err = webhooks.GetRecordedEvent(nil, recorderURL, token, o.Args[0])
+		if err != nil {
+			return err
+		}
+	} else {
+		events, err := webhooks.ListRecordedEvents(nil, recorderURL, token)
+		if err != nil {
+			return err
+		}
+		if o.List || !o.Last {
+			o.renderWebhookEvents(events)
+			return nil
+		}
+		if len(events) == 0 {
+			return fmt.Errorf("No webhooks have been recorded")
+		}
+		event = events[0]
+	}
+	return o.sendWebhookEvent(&o.WebhookTargetOptions, event)
+}
+
+func (o *StepWebhookReplayOptions) renderWebhookEvents(events []*webhooks.Event) {
+	table := o.CreateTable()
+	table.AddRow("ID", "KIND", "TYPE", "REPOSITORY", "RECEIVED")
+	for _, event := range events {
+		table.AddRow(event.ID, event.Kind, event.Type, event.Repository, event.Received.Format("2006-01-02 15:04:05"))
+	}
+	table.Render()
+}
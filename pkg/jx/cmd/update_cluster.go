@@ -54,6 +54,8 @@ func NewCmdUpdateCluster(f Factory, in terminal.FileReader, out terminal.FileWri
 	}
 
 	cmd.AddCommand(NewCmdUpdateClusterGKE(f, in, out, errOut))
+	cmd.AddCommand(NewCmdUpdateClusterEKS(f, in, out, errOut))
+	cmd.AddCommand(NewCmdUpdateClusterAKS(f, in, out, errOut))
 
 	return cmd
 }
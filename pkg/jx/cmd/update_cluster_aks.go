@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// UpdateClusterAKSOptions the options for updating a cluster on AKS
+type UpdateClusterAKSOptions struct {
+	UpdateClusterOptions
+}
+
+var (
+	updateClusterAKSLong = templates.LongDesc(`
+		Updates an existing Kubernetes cluster on AKS which was created by 'jx create terraform'
+
+`)
+
+	updateClusterAKSExample = templates.Examples(`
+
+		jx update cluster aks terraform -n dev
+
+`)
+)
+
+// NewCmdUpdateClusterAKS creates the command for updating a cluster on AKS
+func NewCmdUpdateClusterAKS(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := createUpdateClusterAKSOptions(f, in, out, errOut, AKS)
+
+	cmd := &cobra.Command{
+		Use:     "aks",
+		Short:   "Updates an existing Kubernetes cluster on AKS: Runs on Microsoft Azure",
+		Long:    updateClusterAKSLong,
+		Example: updateClusterAKSExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdUpdateClusterTerraform(f, in, out, errOut, AKS))
+
+	return cmd
+}
+
+func createUpdateClusterAKSOptions(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer, cloudProvider string) UpdateClusterAKSOptions {
+	commonOptions := CommonOptions{
+		Factory: f,
+		In:      in,
+		Out:     out,
+		Err:     errOut,
+	}
+	options := UpdateClusterAKSOptions{
+		UpdateClusterOptions: UpdateClusterOptions{
+			UpdateOptions: UpdateOptions{
+				CommonOptions: commonOptions,
+			},
+			Provider: cloudProvider,
+		},
+	}
+	return options
+}
+
+func (o *UpdateClusterAKSOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// UpdateClusterEKSOptions the options for updating a cluster on EKS
+type UpdateClusterEKSOptions struct {
+	UpdateClusterOptions
+}
+
+var (
+	updateClusterEKSLong = templates.LongDesc(`
+		Updates an existing Kubernetes cluster on EKS which was created by 'jx create terraform'
+
+`)
+
+	updateClusterEKSExample = templates.Examples(`
+
+		jx update cluster eks terraform -n dev
+
+`)
+)
+
+// NewCmdUpdateClusterEKS creates the command for updating a cluster on EKS
+func NewCmdUpdateClusterEKS(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := createUpdateClusterEKSOptions(f, in, out, errOut, EKS)
+
+	cmd := &cobra.Command{
+		Use:     "eks",
+		Short:   "Updates an existing Kubernetes cluster on EKS: Runs on Amazon Web Services",
+		Long:    updateClusterEKSLong,
+		Example: updateClusterEKSExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdUpdateClusterTerraform(f, in, out, errOut, EKS))
+
+	return cmd
+}
+
+func createUpdateClusterEKSOptions(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer, cloudProvider string) UpdateClusterEKSOptions {
+	commonOptions := CommonOptions{
+		Factory: f,
+		In:      in,
+		Out:     out,
+		Err:     errOut,
+	}
+	options := UpdateClusterEKSOptions{
+		UpdateClusterOptions: UpdateClusterOptions{
+			UpdateOptions: UpdateOptions{
+				CommonOptions: commonOptions,
+			},
+			Provider: cloudProvider,
+		},
+	}
+	return options
+}
+
+func (o *UpdateClusterEKSOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// UpdateClusterTerraformOptions the options for re-applying the Terraform plan of a cluster created by jx create terraform
+type UpdateClusterTerraformOptions struct {
+	UpdateClusterOptions
+
+	Flags UpdateClusterTerraformFlags
+}
+
+// UpdateClusterTerraformFlags the flags for updating a cluster with Terraform
+type UpdateClusterTerraformFlags struct {
+	ClusterName             string
+	Dir                     string
+	IgnoreTerraformWarnings bool
+}
+
+var (
+	updateClusterTerraformLong = templates.LongDesc(`
+
+		Command re-applies the Terraform plan of a cluster created by 'jx create terraform' which is in the
+		clusters/<cluster>/terraform folder of an organisation repository in ~/.jx/organisations
+
+`)
+
+	updateClusterTerraformExample = templates.Examples(`
+
+		jx update cluster %s terraform -n dev
+
+		# to use the plan in a specific folder
+		jx update cluster %s terraform -n dev --dir ~/.jx/organisations/organisation-acme/clusters/dev/terraform
+
+`)
+)
+
+// NewCmdUpdateClusterTerraform creates the command to update a cluster of the Kubernetes provider with Terraform
+func NewCmdUpdateClusterTerraform(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer, cloudProvider string) *cobra.Command {
+	options := createUpdateClusterTerraformOptions(f, in, out, errOut, cloudProvider)
+
+	cmd := &cobra.Command{
+		Use:     "terraform",
+		Short:   fmt.Sprintf("Updates an existing Kubernetes cluster on %s using Terraform", strings.ToUpper(cloudProvider)),
+		Long:    updateClusterTerraformLong,
+		Example: fmt.Sprintf(updateClusterTerraformExample, cloudProvider, cloudProvider),
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Flags.ClusterName, optionClusterName, "n", "", "The name of the cluster in the organisation")
+	cmd.Flags().StringVarP(&options.Flags.Dir, "dir", "", "", "The folder of the Terraform plan of the cluster. Defaults to the cluster in the organisation repositories")
+	cmd.Flags().BoolVarP(&options.Flags.IgnoreTerraformWarnings, "ignore-terraform-warnings", "", false, "Ignore any warnings about the Terraform plan being potentially destructive")
+
+	return cmd
+}
+
+func createUpdateClusterTerraformOptions(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer, cloudProvider string) UpdateClusterTerraformOptions {
+	commonOptions := CommonOptions{
+		Factory: f,
+		In:      in,
+		Out:     out,
+		Err:     errOut,
+	}
+	options := UpdateClusterTerraformOptions{
+		UpdateClusterOptions: UpdateClusterOptions{
+			UpdateOptions: UpdateOptions{
+				CommonOptions: commonOptions,
+			},
+			Provider: cloudProvider,
+		},
+	}
+	return options
+}
+
+// Run implements this command
+func (o *UpdateClusterTerraformOptions) Run() error {
+	if o.Flags.ClusterName == "" {
+		return util.MissingOption(optionClusterName)
+	}
+
+	dir, err := o.findClusterTerraformDir()
+	if err != nil {
+		return err
+	}
+
+	c, err := newTerraformCluster(o.Flags.ClusterName, o.Provider)
+	if err != nil {
+		return err
+	}
+	terraformVars := filepath.Join(dir, "terraform.tfvars")
+	log.Infof("Loading config from %s\n", util.ColorInfo(terraformVars))
+	c.ParseTfVarsFile(terraformVars)
+	if c.Provider() != o.Provider {
+		return fmt.Errorf("the cluster in %s is on %s not %s", dir, c.Provider(), o.Provider)
+	}
+
+	err = o.installRequirements("", terraformClusterDependencies([]Cluster{c}, o.InstallOptions.InitOptions.HelmBinary())...)
+	if err != nil {
+		return err
+	}
+
+	if !o.BatchMode {
+		surveyOpts := survey.WithStdio(o.In, o.Out, o.Err)
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Would you like to update the %s cluster %s with Terraform?", strings.ToUpper(o.Provider), c.ClusterName()),
+			Default: true,
+		}
+		survey.AskOne(prompt, &confirm, nil, surveyOpts)
+
+		if !confirm {
+			// exit at this point
+			return nil
+		}
+	}
+
+	createOptions := &CreateTerraformOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: o.CommonOptions,
+		},
+		Flags: Flags{
+			IgnoreTerraformWarnings: o.Flags.IgnoreTerraformWarnings,
+		},
+	}
+	return createOptions.applyTerraformCluster(c, dir)
+}
+
+// findClusterTerraformDir returns the folder of the Terraform plan of the cluster in the organisation repositories
+func (o *UpdateClusterTerraformOptions) findClusterTerraformDir() (string, error) {
+	if o.Flags.Dir != "" {
+		if _, err := os.Stat(o.Flags.Dir); os.IsNotExist(err) {
+			return "", fmt.Errorf("unable to find Terraform plan dir %s", o.Flags.Dir)
+		}
+		return o.Flags.Dir, nil
+	}
+	organisationDir, err := util.OrganisationsDir()
+	if err != nil {
+		return "", err
+	}
+	dirs, err := filepath.Glob(filepath.Join(organisationDir, "*", Clusters, o.Flags.ClusterName, Terraform))
+	if err != nil {
+		return "", err
+	}
+	switch len(dirs) {
+	case 0:
+		return "", fmt.Errorf("unable to find cluster %s in the organisations in %s. Use --dir to specify its Terraform plan", o.Flags.ClusterName, organisationDir)
+	case 1:
+		return dirs[0], nil
+	default:
+		return "", fmt.Errorf("found cluster %s in more than one organisation %v. Use --dir to specify its Terraform plan", o.Flags.ClusterName, dirs)
+	}
+}
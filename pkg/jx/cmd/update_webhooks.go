@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/prow"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const (
+	webHookActionCreate    = "create"
+	webHookActionUpdate    = "update"
+	webHookActionDelete    = "delete"
+	webHookActionUnchanged = "unchanged"
+)
+
+// UpdateWebhooksOptions the options for the update webhooks command
+type UpdateWebhooksOptions struct {
+	UpdateOptions
+
+	GitServer        string
+	Organisations    []string
+	PreviousHookURLs []string
+	DryRun           bool
+}
+
+// WebHookChange the change made to a webhook of a git repository
+type WebHookChange struct {
+	Repository string
+	Action     string
+	ID         string
+	URL        string
+}
+
+var (
+	updateWebhooksLong = templates.LongDesc(`
+		Updates the webhooks of all the git repositories of the team so that they use the current Jenkins or Prow endpoint and secret.
+
+		The repositories are the Environment git repositories and the repositories in the Prow config or the Jenkins multibranch jobs.
+		Any webhooks using the current endpoint or one of the previous endpoints are updated and any duplicates are deleted.
+`)
+
+	updateWebhooksExample = templates.Examples(`
+		# Display the changes which would be made to the webhooks
+		jx update webhooks --dry-run
+
+		# Replace the webhooks which use an old endpoint in the repositories of an organisation
+		jx update webhooks --org myorg --previous-hook-url http://hook.jx.1.2.3.4.nip.io
+	`)
+)
+
+// NewCmdUpdateWebhooks creates a command object for the "update webhooks" command
+func NewCmdUpdateWebhooks(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &UpdateWebhooksOptions{
+		UpdateOptions: UpdateOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "webhooks",
+		Short:   "Updates the webhooks of the git repositories of the team to the current endpoint and secret",
+		Aliases: []string{"webhook"},
+		Long:    updateWebhooksLong,
+		Example: updateWebhooksExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.GitServer, "git-server", "", "", "The git server of the repositories in the Prow config or Jenkins jobs. Defaults to the git server of the team")
+	cmd.Flags().StringArrayVarP(&options.Organisations, "org", "o", []string{}, "Only updates the repositories of these git organisations")
+	cmd.Flags().StringArrayVarP(&options.PreviousHookURLs, "previous-hook-url", "p", []string{}, "The previous endpoints whose webhooks should be replaced")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "Only displays the changes which would be made to the webhooks")
+
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *UpdateWebhooksOptions) Run() error {
+	_, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	_, _, err = o.JXClient()
+	if err != nil {
+		return err
+	}
+	isProw, err := o.isProw()
+	if err != nil {
+		return err
+	}
+	gitURLs, err := o.teamRepositoryURLs(isProw)
+	if err != nil {
+		return err
+	}
+
+	prowURL, prowSecret := "", ""
+	jenkinsURL := ""
+	if isProw {
+		prowURL, prowSecret, err = o.prowWebhookEndpoint()
+		if err != nil {
+			return err
+		}
+	} else {
+		jenkinsURL = o.ExternalJenkinsBaseURL
+		if jenkinsURL == "" {
+			jenk, err := o.JenkinsClient()
+			if err != nil {
+				return err
+			}
+			jenkinsURL = jenk.BaseURL()
+		}
+	}
+
+	providers := map[string]gits.GitProvider{}
+	changes := []*WebHookChange{}
+	for _, gitURL := range gitURLs {
+		gitInfo, err := gits.ParseGitURL(gitURL)
+		if err != nil {
+			log.Warnf("Ignoring git repository %s: %s\n", gitURL, err)
+			continue
+		}
+		provider := providers[gitInfo.Host]
+		if provider == nil {
+			provider, err = o.gitProviderForURL(gitURL, "user name to update webhooks")
+			if err != nil {
+				log.Warnf("Failed to create the git provider for %s: %s\n", gitURL, err)
+				continue
+			}
+			providers[gitInfo.Host] = provider
+		}
+		webhookURL, secret := prowURL, prowSecret
+		if !isProw {
+			webhookURL = util.UrlJoin(jenkinsURL, provider.JenkinsWebHookPath(gitURL, ""))
+		}
+		webhookURL, err = o.webhookEndpoint(provider, webhookURL)
+		if err != nil {
+			log.Warnf("Failed to find the webhook endpoint of %s: %s\n", gitURL, err)
+			continue
+		}
+		repoChanges, err := reconcileWebHooks(provider, gitInfo, webhookURL, secret, o.PreviousHookURLs, o.DryRun)
+		changes = append(changes, repoChanges...)
+		if err != nil {
+			log.Warnf("Failed to update the webhooks of %s: %s\n", gitURL, err)
+		}
+	}
+
+	if len(changes) == 0 {
+		log.Infof("No webhooks found\n")
+		return nil
+	}
+	if o.DryRun {
+		log.Infof("dry-run so the following changes have not been made\n")
+	}
+	table := o.CreateTable()
+	table.AddRow("REPOSITORY", "ACTION", "ID", "URL")
+	for _, change := range changes {
+		table.AddRow(change.Repository, change.Action, change.ID, change.URL)
+	}
+	table.Render()
+	return nil
+}
+
+// teamRepositoryURLs returns the git URLs of the Environment repositories and the repositories in the Prow config
+// or the Jenkins multibranch jobs
+func (o *UpdateWebhooksOptions) teamRepositoryURLs(isProw bool) ([]string, error) {
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return nil, err
+	}
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return nil, err
+	}
+	gitServer := o.GitServer
+	if gitServer == "" {
+		gitServer = teamSettings.GitServer
+	}
+	if gitServer == "" {
+		gitServer = gits.GitHubURL
+	}
+
+	urls := []string{}
+	envMap, _, err := kube.GetEnvironments(jxClient, ns)
+	if err != nil {
+		return nil, err
+	}
+	for _, env := range envMap {
+		if env.Spec.Source.URL != "" {
+			urls = append(urls, env.Spec.Source.URL)
+		}
+	}
+
+	var repos []string
+	if isProw {
+		repos, err = prow.GetRepositories(o.KubeClientCached, ns)
+	} else {
+		repos, err = o.jenkinsJobRepositories()
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, repo := range repos {
+		urls = append(urls, util.UrlJoin(gitServer, repo))
+	}
+	return filterGitURLs(urls, o.Organisations), nil
+}
+
+// jenkinsJobRepositories returns the 'owner/name' of the repositories of the multibranch jobs in the organisation
+// folders of Jenkins
+func (o *UpdateWebhooksOptions) jenkinsJobRepositories() ([]string, error) {
+	jenk, err := o.JenkinsClient()
+	if err != nil {
+		return nil, err
+	}
+	folders, err := jenk.GetJobs()
+	if err != nil {
+		return nil, err
+	}
+	answer := []string{}
+	for _, folder := range folders {
+		job, err := jenk.GetJob(folder.Name)
+		if err != nil {
+			log.Warnf("Failed to find Jenkins job %s: %s\n", folder.Name, err)
+			continue
+		}
+		for _, child := range job.Jobs {
+			answer = append(answer, folder.Name+"/"+child.Name)
+		}
+	}
+	return answer, nil
+}
+
+// filterGitURLs removes duplicate git URLs and the URLs of repositories outside the given organisations
+func filterGitURLs(urls []string, organisations []string) []string {
+	answer := []string{}
+	keys := map[string]bool{}
+	for _, u := range urls {
+		gitInfo, err := gits.ParseGitURL(u)
+		if err != nil {
+			continue
+		}
+		if len(organisations) > 0 && util.StringArrayIndex(organisations, gitInfo.Organisation) < 0 {
+			continue
+		}
+		key := strings.ToLower(gitInfo.HostURL() + "/" + gitInfo.Organisation + "/" + gitInfo.Name)
+		if !keys[key] {
+			keys[key] = true
+			answer = append(answer, u)
+		}
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+// webHookURLMatches returns true if the webhook URL is one of the given URLs or is a URL within one of them
+func webHookURLMatches(hookURL string, urls ...string) bool {
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+		if hookURL == u || strings.HasPrefix(hookURL, strings.TrimSuffix(u, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileWebHooks makes sure the repository has a single webhook for the webhook URL and secret by updating the
+// webhooks which use the webhook URL or one of the previous URLs and deleting any duplicates
+func reconcileWebHooks(provider gits.GitProvider, gitInfo *gits.GitRepositoryInfo, webhookURL string, secret string, previousURLs []string, dryRun bool) ([]*WebHookChange, error) {
+	repository := gitInfo.Organisation + "/" + gitInfo.Name
+	hooks, err := provider.ListWebHooks(gitInfo.Organisation, gitInfo.Name)
+	if err != nil {
+		return nil, err
+	}
+	urls := append([]string{webhookURL}, previousURLs...)
+	changes := []*WebHookChange{}
+	var current *gits.GitWebHookArguments
+	for _, hook := range hooks {
+		if !webHookURLMatches(hook.URL, urls...) {
+			continue
+		}
+		if current == nil {
+			current = hook
+			continue
+		}
+		changes = append(changes, &WebHookChange{Repository: repository, Action: webHookActionDelete, ID: hook.ID, URL: hook.URL})
+		if !dryRun {
+			err = provider.DeleteWebHook(gitInfo.Organisation, gitInfo.Name, hook.ID)
+			if err != nil {
+				return changes, fmt.Errorf("Failed to delete webhook %s: %s", hook.ID, err)
+			}
+		}
+	}
+
+	webhook := &gits.GitWebHookArguments{
+		Owner:  gitInfo.Organisation,
+		Repo:   gitInfo,
+		URL:    webhookURL,
+		Secret: secret,
+	}
+	if current == nil {
+		changes = append(changes, &WebHookChange{Repository: repository, Action: webHookActionCreate, URL: webhookURL})
+		if !dryRun {
+			err = provider.CreateWebHook(webhook)
+		}
+		return changes, err
+	}
+
+	// git providers do not return the secret of a webhook so it is always updated when there is one
+	if secret == "" && webHookURLMatches(current.URL, webhookURL) {
+		changes = append(changes, &WebHookChange{Repository: repository, Action: webHookActionUnchanged, ID: current.ID, URL: current.URL})
+		return changes, nil
+	}
+	webhook.ID = current.ID
+	changes = append(changes, &WebHookChange{Repository: repository, Action: webHookActionUpdate, ID: current.ID, URL: webhookURL})
+	if !dryRun {
+		err = provider.UpdateWebHook(webhook)
+	}
+	return changes, err
+}
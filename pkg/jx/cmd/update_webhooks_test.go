@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testHookURL     = "http://hook.jx.5.6.7.8.nip.io/hook"
+	testOldHookURL  = "http://hook.jx.1.2.3.4.nip.io/hook"
+	testOtherURL    = "https://ci.example.com/notify"
+	testHookSecret  = "new-secret"
+	testWebHookRepo = "https://github.com/myorg/myrepo.git"
+)
+
+func createWebHooks(t *testing.T, provider gits.GitProvider, gitInfo *gits.GitRepositoryInfo, urls ...string) {
+	for _, u := range urls {
+		err := provider.CreateWebHook(&gits.GitWebHookArguments{Owner: gitInfo.Organisation, Repo: gitInfo, URL: u, Secret: "old-secret"})
+		require.NoError(t, err)
+	}
+}
+
+func TestReconcileWebHooksUpdatesStaleHookAndDeletesDuplicates(t *testing.T) {
+	t.Parallel()
+	repo := gits.NewFakeRepository("myorg", "myrepo")
+	provider := gits.NewFakeProvider(repo)
+	gitInfo, err := gits.ParseGitURL(testWebHookRepo)
+	require.NoError(t, err)
+	createWebHooks(t, provider, gitInfo, testOldHookURL, testOtherURL, testOldHookURL, testHookURL)
+
+	changes, err := reconcileWebHooks(provider, gitInfo, testHookURL, testHookSecret, []string{"http://hook.jx.1.2.3.4.nip.io"}, false)
+	require.NoError(t, err)
+
+	actions := []string{}
+	for _, change := range changes {
+		actions = append(actions, change.Action+" "+change.ID)
+	}
+	assert.Equal(t, []string{"delete 3", "delete 4", "update 1"}, actions)
+
+	require.Len(t, repo.WebHooks, 2)
+	assert.Equal(t, "1", repo.WebHooks[0].ID)
+	assert.Equal(t, testHookURL, repo.WebHooks[0].URL)
+	assert.Equal(t, testHookSecret, repo.WebHooks[0].Secret)
+	assert.Equal(t, testOtherURL, repo.WebHooks[1].URL, "webhooks of other endpoints should be left alone")
+}
+
+func TestReconcileWebHooksCreatesMissingHook(t *testing.T) {
+	t.Parallel()
+	repo := gits.NewFakeRepository("myorg", "myrepo")
+	provider := gits.NewFakeProvider(repo)
+	gitInfo, err := gits.ParseGitURL(testWebHookRepo)
+	require.NoError(t, err)
+	createWebHooks(t, provider, gitInfo, testOtherURL)
+
+	changes, err := reconcileWebHooks(provider, gitInfo, testHookURL, testHookSecret, nil, false)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, webHookActionCreate, changes[0].Action)
+
+	require.Len(t, repo.WebHooks, 2)
+	assert.Equal(t, testHookURL, repo.WebHooks[1].URL)
+	assert.Equal(t, testHookSecret, repo.WebHooks[1].Secret)
+}
+
+func TestReconcileWebHooksLeavesMatchingHookWithoutSecret(t *testing.T) {
+	t.Parallel()
+	repo := gits.NewFakeRepository("myorg", "myrepo")
+	provider := gits.NewFakeProvider(repo)
+	gitInfo, err := gits.ParseGitURL(testWebHookRepo)
+	require.NoError(t, err)
+	createWebHooks(t, provider, gitInfo, testHookURL)
+
+	changes, err := reconcileWebHooks(provider, gitInfo, testHookURL, "", nil, false)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, webHookActionUnchanged, changes[0].Action)
+}
+
+func TestReconcileWebHooksDryRun(t *testing.T) {
+	t.Parallel()
+	repo := gits.NewFakeRepository("myorg", "myrepo")
+	provider := gits.NewFakeProvider(repo)
+	gitInfo, err := gits.ParseGitURL(testWebHookRepo)
+	require.NoError(t, err)
+	createWebHooks(t, provider, gitInfo, testOldHookURL, testOldHookURL)
+
+	changes, err := reconcileWebHooks(provider, gitInfo, testHookURL, testHookSecret, []string{testOldHookURL}, true)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, webHookActionDelete, changes[0].Action)
+	assert.Equal(t, webHookActionUpdate, changes[1].Action)
+
+	require.Len(t, repo.WebHooks, 2)
+	assert.Equal(t, testOldHookURL, repo.WebHooks[0].URL)
+	assert.Equal(t, testOldHookURL, repo.WebHooks[1].URL)
+}
+
+func TestFilterGitURLs(t *testing.T) {
+	t.Parallel()
+	urls := []string{
+		"https://github.com/myorg/myrepo.git",
+		"https://github.com/myorg/myrepo",
+		"https://github.com/other/repo",
+		"https://github.com/myorg/another",
+	}
+	assert.Equal(t, []string{"https://github.com/myorg/another", "https://github.com/myorg/myrepo.git"}, filterGitURLs(urls, []string{"myorg"}))
+	assert.Len(t, filterGitURLs(urls, nil), 3)
+}
@@ -91,6 +91,10 @@ func (o *UpgradePlatformOptions) Run() error {
 			return err
 		}
 	}
+	err = o.registerAuditEventCRD()
+	if err != nil {
+		return err
+	}
 	if targetVersion == "" {
 		io := &InstallOptions{}
 		io.CommonOptions = o.CommonOptions
@@ -0,0 +1,130 @@
+package kube
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AuditEventFilter the criteria used to find AuditEvents. Blank values match every event
+type AuditEventFilter struct {
+	Action      string
+	Kind        string
+	Name        string
+	Actor       string
+	Environment string
+	Application string
+	Since       time.Time
+}
+
+// NewAuditEvent creates an AuditEvent for a change to the named resource of the given kind
+func NewAuditEvent(ns string, action v1.AuditActionType, kind string, name string) *v1.AuditEvent {
+	now := metav1.Now()
+	return &v1.AuditEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+		},
+		Spec: v1.AuditEventSpec{
+			Action:    action,
+			Kind:      kind,
+			Name:      name,
+			Timestamp: &now,
+		},
+	}
+}
+
+// CreateAuditEvent writes the AuditEvent giving it a unique name if it does not have one
+func CreateAuditEvent(jxClient versioned.Interface, ns string, event *v1.AuditEvent) (*v1.AuditEvent, error) {
+	if event.Spec.Timestamp == nil {
+		now := metav1.Now()
+		event.Spec.Timestamp = &now
+	}
+	generateName := event.Name == ""
+	for i := 0; ; i++ {
+		if generateName {
+			suffix, err := util.RandStringBytesMaskImprSrc(5)
+			if err != nil {
+				return nil, err
+			}
+			prefix := ToValidName(string(event.Spec.Action) + "-" + event.Spec.Kind + "-" + event.Spec.Name)
+			if len(prefix) > 40 {
+				prefix = strings.TrimSuffix(prefix[:40], "-")
+			}
+			event.Name = prefix + "-" + event.Spec.Timestamp.UTC().Format("20060102150405") + "-" + suffix
+		}
+		answer, err := jxClient.JenkinsV1().AuditEvents(ns).Create(event)
+		if err != nil && generateName && apierrors.IsAlreadyExists(err) && i < 5 {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create the AuditEvent %s in namespace %s", event.Name, ns)
+		}
+		return answer, nil
+	}
+}
+
+// GetAuditEvents returns the AuditEvents in the namespace which match the filter oldest first
+func GetAuditEvents(jxClient versioned.Interface, ns string, filter *AuditEventFilter) ([]*v1.AuditEvent, error) {
+	list, err := jxClient.JenkinsV1().AuditEvents(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the AuditEvents in namespace %s", ns)
+	}
+	answer := []*v1.AuditEvent{}
+	for i := range list.Items {
+		event := &list.Items[i]
+		if filter == nil || filter.Matches(event) {
+			answer = append(answer, event)
+		}
+	}
+	sort.SliceStable(answer, func(i, j int) bool {
+		return auditEventTime(answer[i]).Before(auditEventTime(answer[j]))
+	})
+	return answer, nil
+}
+
+// Matches returns true if the event matches all the criteria of the filter
+func (f *AuditEventFilter) Matches(event *v1.AuditEvent) bool {
+	spec := &event.Spec
+	if !auditFieldMatches(f.Action, string(spec.Action)) || !auditFieldMatches(f.Kind, spec.Kind) ||
+		!auditFieldMatches(f.Name, spec.Name) || !auditFieldMatches(f.Actor, spec.Actor) ||
+		!auditFieldMatches(f.Environment, spec.Environment) || !auditFieldMatches(f.Application, spec.Application) {
+		return false
+	}
+	return f.Since.IsZero() || !auditEventTime(event).Before(f.Since)
+}
+
+func auditFieldMatches(pattern string, value string) bool {
+	return pattern == "" || strings.EqualFold(pattern, value)
+}
+
+func auditEventTime(event *v1.AuditEvent) time.Time {
+	if event.Spec.Timestamp != nil {
+		return event.Spec.Timestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// ToAuditYAML returns the YAML of a resource for recording the state before or after a change
+func ToAuditYAML(resource interface{}) (string, error) {
+	if resource == nil {
+		return "", nil
+	}
+	data, err := yaml.Marshal(resource)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the resource to YAML")
+	}
+	text := string(data)
+	if strings.TrimSpace(text) == "null" {
+		// a nil pointer
+		return "", nil
+	}
+	return text, nil
+}
@@ -0,0 +1,72 @@
+package kube_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	v1fake "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCreateAndGetAuditEvents(t *testing.T) {
+	t.Parallel()
+	ns := "jx"
+	jxClient := v1fake.NewSimpleClientset()
+
+	now := time.Now()
+	events := []*v1.AuditEvent{
+		kube.NewAuditEvent(ns, v1.AuditActionPromote, "Application", "myapp"),
+		kube.NewAuditEvent(ns, v1.AuditActionPromote, "Application", "myapp"),
+		kube.NewAuditEvent(ns, v1.AuditActionUpdate, "Environment", "staging"),
+	}
+	for i, event := range events {
+		timestamp := metav1.NewTime(now.Add(-time.Duration(len(events)-i) * time.Hour))
+		event.Spec.Timestamp = &timestamp
+		event.Spec.Actor = "jstrachan"
+	}
+	events[0].Spec.Environment = "staging"
+	events[1].Spec.Environment = "production"
+	events[1].Spec.Version = "1.2.3"
+	events[2].Spec.Actor = "rawlingsj"
+
+	// lets create them newest first to check they are sorted
+	for i := len(events) - 1; i >= 0; i-- {
+		created, err := kube.CreateAuditEvent(jxClient, ns, events[i])
+		require.NoError(t, err)
+		assert.Regexp(t, "^[a-z0-9-]+$", created.Name)
+	}
+	assert.NotEqual(t, events[0].Name, events[1].Name)
+
+	all, err := kube.GetAuditEvents(jxClient, ns, nil)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "staging", all[0].Spec.Environment)
+	assert.Equal(t, "production", all[1].Spec.Environment)
+	assert.Equal(t, "Environment", all[2].Spec.Kind)
+
+	promotions, err := kube.GetAuditEvents(jxClient, ns, &kube.AuditEventFilter{Action: "promote", Environment: "Production"})
+	require.NoError(t, err)
+	require.Len(t, promotions, 1)
+	assert.Equal(t, "1.2.3", promotions[0].Spec.Version)
+
+	recent, err := kube.GetAuditEvents(jxClient, ns, &kube.AuditEventFilter{Actor: "jstrachan", Since: now.Add(-150 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, recent, 1)
+	assert.Equal(t, "production", recent[0].Spec.Environment)
+}
+
+func TestToAuditYAML(t *testing.T) {
+	t.Parallel()
+	var env *v1.EnvironmentSpec
+	text, err := kube.ToAuditYAML(env)
+	require.NoError(t, err)
+	assert.Empty(t, text)
+
+	text, err = kube.ToAuditYAML(&v1.EnvironmentSpec{Label: "Staging"})
+	require.NoError(t, err)
+	assert.Contains(t, text, "label: Staging")
+}
@@ -31,9 +31,30 @@ const (
 	// ChartKnative the default chart for knative
 	ChartKnativeBuild = "jenkins-x/knative-build"
 
+	// ChartWebhookRecorder the default chart for the webhook recorder which runs 'jx controller webhook-recorder'
+	ChartWebhookRecorder = "jenkins-x/jx-webhook-recorder"
+
 	DefaultProwReleaseName         = "jx-prow"
 	DefaultKnativeBuildReleaseName = "jx-knative-build"
 
+	// ServiceWebhookRecorder the name of the webhook recorder Service
+	ServiceWebhookRecorder = "jx-webhook-recorder"
+
+	// SecretWebhookRecorder the name of the Secret with the token required to list the events of the webhook recorder
+	SecretWebhookRecorder = "jx-webhook-recorder"
+
+	// SecretDataToken the token in a Secret
+	SecretDataToken = "token"
+
+	// ServiceProwHook the name of the Service of the Prow hook which receives the webhooks
+	ServiceProwHook = "hook"
+
+	// SecretProwHMACToken the name of the Secret with the HMAC token the webhooks sent to the Prow hook are signed with
+	SecretProwHMACToken = "hmac-token"
+
+	// SecretDataHMAC the HMAC token in a Secret
+	SecretDataHMAC = "hmac"
+
 	// ServiceJenkins is the name of the Jenkins Service
 	ServiceJenkins = "jenkins"
 
@@ -91,6 +112,9 @@ const (
 	// ConfigMapNameJXInstallConfig is the ConfigMap containing the jx installation's CA and server url. Used by jx login
 	ConfigMapNameJXInstallConfig = "jx-install-config"
 
+	// ConfigMapQuickstartCatalog is the ConfigMap containing the index of the quickstarts of the team
+	ConfigMapQuickstartCatalog = "jx-quickstart-catalog"
+
 	// LocalHelmRepoName is the default name of the local chart repository where CI/CD releases go to
 	LocalHelmRepoName = "releases"
 
@@ -158,6 +182,9 @@ const (
 	// LabelUsername the user name owner of a namespace or resource
 	LabelUsername = "jenkins.io/user"
 
+	// LabelUserDirectory the identity directory a User was imported from
+	LabelUserDirectory = "jenkins.io/user-directory"
+
 	// ValueCreatedByJX for resources created by the Jenkins X CLI
 	ValueCreatedByJX = "jx"
 
@@ -182,6 +209,24 @@ const (
 	// ValueJobKindPostPreview
 	ValueJobKindPostPreview = "post-preview-step"
 
+	// ValueJobKindPreviewSeed the kind of job which seeds a preview dependency with data
+	ValueJobKindPreviewSeed = "preview-seed"
+
+	// AnnotationPreviewDependencies the comma separated helm releases of the dependencies deployed into a preview environment
+	AnnotationPreviewDependencies = "jenkins.io/preview-dependencies"
+
+	// AnnotationTeamMembers the comma separated members of a team who were last given access to the team
+	AnnotationTeamMembers = "jenkins.io/team-members"
+
+	// AnnotationTeamGitMembers the comma separated members of a team who were added to its git organisation by the team controller
+	AnnotationTeamGitMembers = "jenkins.io/team-git-members"
+
+	// AnnotationTeamDeprovisionStep the last step of the teardown of a team which has completed
+	AnnotationTeamDeprovisionStep = "jenkins.io/deprovisioned-step"
+
+	// AnnotationDirectoryRoles the comma separated team/role pairs the groups of an identity directory gave a User which it did not already have
+	AnnotationDirectoryRoles = "jenkins.io/directory-roles"
+
 	// AnnotationURL indicates a service/server's URL
 	AnnotationURL = "jenkins.io/url"
 
@@ -229,13 +274,15 @@ var (
 		"kubeless":                     ChartKubeless,
 		"prometheus":                   "stable/prometheus",
 		"grafana":                      "stable/grafana",
+		"webhook-recorder":             ChartWebhookRecorder,
 		DefaultProwReleaseName:         ChartProw,
 		DefaultKnativeBuildReleaseName: ChartKnativeBuild,
 	}
 
 	AddonServices = map[string]string{
-		"anchore":         "anchore-anchore-engine",
-		"pipeline-events": "jx-pipeline-events-elasticsearch-client",
-		"grafana":         "grafana",
+		"anchore":          "anchore-anchore-engine",
+		"pipeline-events":  "jx-pipeline-events-elasticsearch-client",
+		"grafana":          "grafana",
+		"webhook-recorder": ServiceWebhookRecorder,
 	}
 )
@@ -360,3 +360,48 @@ func CleanCertmanagerResources(c kubernetes.Interface, ns string, config Ingress
 
 	return nil
 }
+
+// RegisterAuditEventCRD ensures that the CRD is registered for AuditEvent
+func RegisterAuditEventCRD(apiClient apiextensionsclientset.Interface) error {
+	name := "auditevents." + jenkinsio.GroupName
+	names := &v1beta1.CustomResourceDefinitionNames{
+		Kind:       "AuditEvent",
+		ListKind:   "AuditEventList",
+		Plural:     "auditevents",
+		Singular:   "auditevent",
+		ShortNames: []string{"audit"},
+	}
+	columns := []v1beta1.CustomResourceColumnDefinition{
+		{
+			Name:        "Action",
+			Type:        "string",
+			Description: "The kind of change",
+			JSONPath:    ".spec.action",
+		},
+		{
+			Name:        "Kind",
+			Type:        "string",
+			Description: "The kind of resource which was changed",
+			JSONPath:    ".spec.kind",
+		},
+		{
+			Name:        "Resource",
+			Type:        "string",
+			Description: "The name of the resource which was changed",
+			JSONPath:    ".spec.name",
+		},
+		{
+			Name:        "Actor",
+			Type:        "string",
+			Description: "Who made the change",
+			JSONPath:    ".spec.actor",
+		},
+		{
+			Name:        "Reason",
+			Type:        "string",
+			Description: "Why the change was made",
+			JSONPath:    ".spec.reason",
+		},
+	}
+	return registerCRD(apiClient, name, names, columns)
+}
@@ -0,0 +1,224 @@
+package kube
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// DriftStatusMissing the resource in the environment git repository does not exist in the cluster
+	DriftStatusMissing = "Missing"
+
+	// DriftStatusModified the resource in the cluster differs from the environment git repository
+	DriftStatusModified = "Modified"
+)
+
+// ResourceDrift represents the difference between the desired state of a resource and its live state
+type ResourceDrift struct {
+	Kind        string   `json:"kind"`
+	Name        string   `json:"name"`
+	Namespace   string   `json:"namespace,omitempty"`
+	Status      string   `json:"status"`
+	Differences []string `json:"differences,omitempty"`
+}
+
+// LiveResourceGetter returns the live state of a resource as generic JSON or nil if it does not exist
+type LiveResourceGetter func(kind string, name string, ns string) (map[string]interface{}, error)
+
+// DetectDrift compares the desired resources with their live state returned by the getter. Resources without a
+// namespace are looked up in the given namespace
+func DetectDrift(desired []map[string]interface{}, ns string, getter LiveResourceGetter) ([]*ResourceDrift, error) {
+	answer := []*ResourceDrift{}
+	for _, d := range desired {
+		kind, _ := d["kind"].(string)
+		metadata, _ := d["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		if kind == "" || name == "" {
+			continue
+		}
+		resourceNs, _ := metadata["namespace"].(string)
+		if resourceNs == "" {
+			resourceNs = ns
+		}
+		live, err := getter(kind, name, resourceNs)
+		if err != nil {
+			return answer, fmt.Errorf("Failed to get %s %s in namespace %s: %s", kind, name, resourceNs, err)
+		}
+		if live == nil {
+			answer = append(answer, &ResourceDrift{
+				Kind:      kind,
+				Name:      name,
+				Namespace: resourceNs,
+				Status:    DriftStatusMissing,
+			})
+			continue
+		}
+		differences := CompareResource(d, live)
+		if len(differences) > 0 {
+			answer = append(answer, &ResourceDrift{
+				Kind:        kind,
+				Name:        name,
+				Namespace:   resourceNs,
+				Status:      DriftStatusModified,
+				Differences: differences,
+			})
+		}
+	}
+	return answer, nil
+}
+
+// CompareResource returns the paths of the fields in the desired resource which have a different value in the live
+// resource. Fields which are only in the live resource such as defaults and the status are ignored unless they
+// are elements of a list in the desired resource. The stringData of Secrets is compared with their base64 encoded
+// data and resource quantities such as 1000m and 1 are compared by their value
+func CompareResource(desired map[string]interface{}, live map[string]interface{}) []string {
+	if desired["kind"] == "Secret" {
+		desired = foldSecretStringData(desired)
+	}
+	answer := []string{}
+	keys := sortedKeys(desired)
+	for _, k := range keys {
+		switch k {
+		case "status":
+			continue
+		case "metadata":
+			dm, _ := desired[k].(map[string]interface{})
+			lm, _ := live[k].(map[string]interface{})
+			for _, field := range []string{"labels", "annotations"} {
+				dv, _ := dm[field].(map[string]interface{})
+				lv, _ := lm[field].(map[string]interface{})
+				answer = append(answer, compareValues("metadata."+field, dv, lv)...)
+			}
+		default:
+			answer = append(answer, compareValues(k, desired[k], live[k])...)
+		}
+	}
+	return answer
+}
+
+func compareValues(path string, desired interface{}, live interface{}) []string {
+	switch d := desired.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			if len(d) == 0 {
+				return nil
+			}
+			return []string{path}
+		}
+		answer := []string{}
+		for _, k := range sortedKeys(d) {
+			answer = append(answer, compareValues(path+"."+k, d[k], l[k])...)
+		}
+		return answer
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			if len(d) == 0 {
+				return nil
+			}
+			return []string{path}
+		}
+		if len(d) != len(l) {
+			return []string{path}
+		}
+		answer := []string{}
+		for i := range d {
+			answer = append(answer, compareValues(fmt.Sprintf("%s[%d]", path, i), d[i], l[i])...)
+		}
+		return answer
+	default:
+		if live == nil || !(scalarEquals(d, live) || (isQuantityPath(path) && quantityEquals(d, live))) {
+			return []string{path}
+		}
+		return nil
+	}
+}
+
+// foldSecretStringData returns a copy of the Secret with its stringData base64 encoded into its data as the API
+// server only returns the data of a Secret
+func foldSecretStringData(secret map[string]interface{}) map[string]interface{} {
+	stringData, _ := secret["stringData"].(map[string]interface{})
+	if len(stringData) == 0 {
+		return secret
+	}
+	answer := map[string]interface{}{}
+	for k, v := range secret {
+		answer[k] = v
+	}
+	delete(answer, "stringData")
+	data := map[string]interface{}{}
+	existing, _ := secret["data"].(map[string]interface{})
+	for k, v := range existing {
+		data[k] = v
+	}
+	for k, v := range stringData {
+		data[k] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%v", v)))
+	}
+	answer["data"] = data
+	return answer
+}
+
+// quantityPathParents the fields whose values are resource quantities, e.g. the limits of a container
+var quantityPathParents = map[string]bool{
+	"limits":               true,
+	"requests":             true,
+	"hard":                 true,
+	"capacity":             true,
+	"min":                  true,
+	"max":                  true,
+	"default":              true,
+	"defaultRequest":       true,
+	"maxLimitRequestRatio": true,
+}
+
+// isQuantityPath returns true if the value at the path is a resource quantity
+func isQuantityPath(path string) bool {
+	elements := strings.Split(path, ".")
+	return len(elements) >= 2 && quantityPathParents[elements[len(elements)-2]]
+}
+
+// quantityEquals compares the values as resource quantities so that 1000m equals 1
+func quantityEquals(a interface{}, b interface{}) bool {
+	qa, err := resource.ParseQuantity(fmt.Sprintf("%v", a))
+	if err != nil {
+		return false
+	}
+	qb, err := resource.ParseQuantity(fmt.Sprintf("%v", b))
+	if err != nil {
+		return false
+	}
+	return qa.Cmp(qb) == 0
+}
+
+// scalarEquals compares scalar values allowing for differences in number types and quoting
+func scalarEquals(a interface{}, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DriftSummary returns a short description of the differences of the resource
+func (d *ResourceDrift) DriftSummary() string {
+	if d.Status == DriftStatusMissing {
+		return "not found in the cluster"
+	}
+	return strings.Join(d.Differences, ", ")
+}
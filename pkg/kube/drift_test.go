@@ -0,0 +1,89 @@
+package kube_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestResource(t *testing.T, text string) map[string]interface{} {
+	answer := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(text), &answer))
+	return answer
+}
+
+func TestDetectDrift(t *testing.T) {
+	t.Parallel()
+
+	desired := []map[string]interface{}{
+		parseTestResource(t, `{"kind": "Deployment", "metadata": {"name": "cheese", "labels": {"app": "cheese"}},
+			"spec": {"replicas": 2, "template": {"spec": {"containers": [{"name": "cheese", "image": "cheese:1.0.0"}]}}}}`),
+		parseTestResource(t, `{"kind": "Service", "metadata": {"name": "cheese"}, "spec": {"ports": [{"port": 80}]}}`),
+		parseTestResource(t, `{"kind": "ConfigMap", "metadata": {"name": "cheese-config", "namespace": "other"}, "data": {"a": "b"}}`),
+	}
+	live := map[string]map[string]interface{}{
+		"jx-staging/Deployment/cheese": parseTestResource(t, `{"kind": "Deployment",
+			"metadata": {"name": "cheese", "uid": "1234", "labels": {"app": "cheese", "extra": "label"}},
+			"spec": {"replicas": 3, "template": {"spec": {"containers": [{"name": "cheese", "image": "cheese:1.0.0", "imagePullPolicy": "IfNotPresent"}]}}},
+			"status": {"replicas": 3}}`),
+		"jx-staging/Service/cheese": parseTestResource(t, `{"kind": "Service", "metadata": {"name": "cheese"},
+			"spec": {"clusterIP": "10.0.0.1", "ports": [{"port": 80, "protocol": "TCP"}]}}`),
+	}
+	getter := func(kind string, name string, ns string) (map[string]interface{}, error) {
+		return live[ns+"/"+kind+"/"+name], nil
+	}
+
+	drifts, err := kube.DetectDrift(desired, "jx-staging", getter)
+	require.NoError(t, err)
+	require.Len(t, drifts, 2)
+
+	assert.Equal(t, "Deployment", drifts[0].Kind)
+	assert.Equal(t, kube.DriftStatusModified, drifts[0].Status)
+	assert.Equal(t, []string{"spec.replicas"}, drifts[0].Differences)
+
+	assert.Equal(t, "ConfigMap", drifts[1].Kind)
+	assert.Equal(t, "other", drifts[1].Namespace)
+	assert.Equal(t, kube.DriftStatusMissing, drifts[1].Status)
+}
+
+func TestCompareResourceLists(t *testing.T) {
+	t.Parallel()
+
+	desired := parseTestResource(t, `{"kind": "Deployment", "metadata": {"name": "cheese", "annotations": {"a": "b"}},
+		"spec": {"template": {"spec": {"containers": [{"name": "cheese", "env": [{"name": "FOO", "value": "1"}]}]}}}}`)
+	live := parseTestResource(t, `{"kind": "Deployment", "metadata": {"name": "cheese"},
+		"spec": {"template": {"spec": {"containers": [{"name": "cheese", "env": [{"name": "FOO", "value": "1"}, {"name": "BAR", "value": "2"}]}]}}}}`)
+
+	differences := kube.CompareResource(desired, live)
+	assert.Equal(t, []string{"metadata.annotations.a", "spec.template.spec.containers[0].env"}, differences)
+}
+
+func TestCompareResourceSecretStringData(t *testing.T) {
+	t.Parallel()
+
+	desired := parseTestResource(t, `{"kind": "Secret", "metadata": {"name": "cheese"},
+		"data": {"a": "Yg=="}, "stringData": {"password": "s3cr3t", "token": "changed"}}`)
+	live := parseTestResource(t, `{"kind": "Secret", "metadata": {"name": "cheese"},
+		"data": {"a": "Yg==", "password": "czNjcjN0", "token": "b2xk"}}`)
+
+	differences := kube.CompareResource(desired, live)
+	assert.Equal(t, []string{"data.token"}, differences)
+	assert.NotNil(t, desired["stringData"], "the desired resource should not be modified")
+}
+
+func TestCompareResourceQuantities(t *testing.T) {
+	t.Parallel()
+
+	desired := parseTestResource(t, `{"kind": "Deployment", "metadata": {"name": "cheese"},
+		"spec": {"template": {"spec": {"containers": [{"name": "cheese",
+			"resources": {"limits": {"cpu": "1000m", "memory": "1Gi"}, "requests": {"cpu": 0.5, "memory": "256Mi"}}}]}}}}`)
+	live := parseTestResource(t, `{"kind": "Deployment", "metadata": {"name": "cheese"},
+		"spec": {"template": {"spec": {"containers": [{"name": "cheese",
+			"resources": {"limits": {"cpu": "1", "memory": "1024Mi"}, "requests": {"cpu": "500m", "memory": "512Mi"}}}]}}}}`)
+
+	differences := kube.CompareResource(desired, live)
+	assert.Equal(t, []string{"spec.template.spec.containers[0].resources.requests.memory"}, differences)
+}
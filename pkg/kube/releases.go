@@ -89,3 +89,35 @@ func GetOrderedReleases(jxClient versioned.Interface, ns string, filter string)
 	SortReleases(answer)
 	return answer, nil
 }
+
+// GetReleasesBetweenVersions returns the releases of the given application which are newer than the from version
+// and no newer than the to version, oldest release first. If there is no from version only the release of the to version is returned
+func GetReleasesBetweenVersions(jxClient versioned.Interface, ns string, appName string, fromVersion string, toVersion string) ([]v1.Release, error) {
+	answer := []v1.Release{}
+	list, err := jxClient.JenkinsV1().Releases(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return answer, err
+	}
+	from, fromErr := semver.Parse(strings.TrimPrefix(fromVersion, "v"))
+	to, toErr := semver.Parse(strings.TrimPrefix(toVersion, "v"))
+	for _, release := range list.Items {
+		if release.Spec.Name != appName {
+			continue
+		}
+		version := strings.TrimPrefix(release.Spec.Version, "v")
+		if version == strings.TrimPrefix(toVersion, "v") {
+			answer = append(answer, release)
+			continue
+		}
+		if fromVersion == "" || fromErr != nil || toErr != nil {
+			continue
+		}
+		sv, err := semver.Parse(version)
+		if err == nil && sv.GT(from) && sv.LTE(to) {
+			answer = append(answer, release)
+		}
+	}
+	// lets return oldest releases first
+	sort.Sort(sort.Reverse(ReleaseOrder(answer)))
+	return answer, nil
+}
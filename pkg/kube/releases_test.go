@@ -0,0 +1,52 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetReleasesBetweenVersions(t *testing.T) {
+	t.Parallel()
+	ns := "jx"
+	jxClient := fake.NewSimpleClientset()
+	for _, r := range []struct {
+		app     string
+		version string
+	}{
+		{"myapp", "1.0.0"},
+		{"myapp", "1.0.1"},
+		{"myapp", "1.0.2"},
+		{"myapp", "1.0.3"},
+		{"other", "1.0.2"},
+	} {
+		_, err := jxClient.JenkinsV1().Releases(ns).Create(&v1.Release{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: kube.ToValidName(r.app + "-" + r.version),
+			},
+			Spec: v1.ReleaseSpec{
+				Name:    r.app,
+				Version: r.version,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	releases, err := kube.GetReleasesBetweenVersions(jxClient, ns, "myapp", "1.0.0", "1.0.2")
+	require.NoError(t, err)
+	versions := []string{}
+	for _, release := range releases {
+		versions = append(versions, release.Spec.Version)
+	}
+	assert.Equal(t, []string{"1.0.1", "1.0.2"}, versions)
+
+	releases, err = kube.GetReleasesBetweenVersions(jxClient, ns, "myapp", "", "v1.0.3")
+	require.NoError(t, err)
+	require.Len(t, releases, 1)
+	assert.Equal(t, "1.0.3", releases[0].Spec.Version)
+}
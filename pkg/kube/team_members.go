@@ -0,0 +1,131 @@
+package kube
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultTeamMemberRole the role members of a team are given in the team's environments unless they have other roles
+const DefaultTeamMemberRole = "viewer"
+
+// ReconciledTeamMembers returns the members of the team who were given access to the team when it was last reconciled
+func ReconciledTeamMembers(team *v1.Team) []string {
+	value := team.Annotations[AnnotationTeamMembers]
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+// SetReconciledTeamMembers records the members of the team who have been given access to the team
+func SetReconciledTeamMembers(team *v1.Team, members []string) {
+	if team.Annotations == nil {
+		team.Annotations = map[string]string{}
+	}
+	sorted := append([]string{}, members...)
+	sort.Strings(sorted)
+	team.Annotations[AnnotationTeamMembers] = strings.Join(sorted, ",")
+}
+
+// TeamGitMembers returns the members of the team who were added to the git organisation of the team
+func TeamGitMembers(team *v1.Team) []string {
+	value := team.Annotations[AnnotationTeamGitMembers]
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+// SetTeamGitMembers records the members of the team who were added to the git organisation of the team
+func SetTeamGitMembers(team *v1.Team, members []string) {
+	if team.Annotations == nil {
+		team.Annotations = map[string]string{}
+	}
+	sorted := append([]string{}, members...)
+	sort.Strings(sorted)
+	team.Annotations[AnnotationTeamGitMembers] = strings.Join(sorted, ",")
+}
+
+// TeamMemberChanges returns the members added to the team and the members removed from it since it was last reconciled
+func TeamMemberChanges(team *v1.Team) ([]string, []string) {
+	removed, added := util.DiffSlices(ReconciledTeamMembers(team), team.Spec.Members)
+	return added, removed
+}
+
+// ReconcileTeamMembers ensures each member of the team has a User in the admin namespace and that members without a
+// role in the team namespace are given the default role, which the role controller applies to the team's environments.
+// The members removed from the team since it was last reconciled are removed from all the roles of the team.
+// Returns the added and removed members so that the caller can record the reconciled members on the team
+func ReconcileTeamMembers(kubeClient kubernetes.Interface, jxClient versioned.Interface, adminNs string, team *v1.Team, defaultRole string) ([]string, []string, error) {
+	added, removed := TeamMemberChanges(team)
+	teamNs := team.Name
+
+	users, _, err := GetUsers(jxClient, adminNs)
+	if err != nil {
+		return added, removed, errors.Wrapf(err, "failed to list the users in namespace %s", adminNs)
+	}
+	roles, _, err := GetTeamRoles(kubeClient, teamNs)
+	if err != nil {
+		return added, removed, errors.Wrapf(err, "failed to list the roles of team %s", teamNs)
+	}
+	if defaultRole != "" && roles[defaultRole] == nil {
+		log.Warnf("The team %s has no role %s so its members will not be given access to its environments\n", teamNs, defaultRole)
+		defaultRole = ""
+	}
+
+	for _, login := range team.Spec.Members {
+		name := ToValidName(login)
+		user := users[name]
+		if user == nil {
+			user, err = jxClient.JenkinsV1().Users(adminNs).Create(CreateUser(adminNs, login, strings.Title(login), ""))
+			if err != nil {
+				return added, removed, errors.Wrapf(err, "failed to create the User %s", login)
+			}
+			users[name] = user
+			log.Infof("Created User %s for team %s\n", util.ColorInfo(login), util.ColorInfo(teamNs))
+		}
+		if defaultRole == "" {
+			continue
+		}
+		// lets leave the roles of members who have been given roles explicitly
+		userRoles, err := GetUserRoles(jxClient, teamNs, user.SubjectKind(), user.Name)
+		if err != nil {
+			return added, removed, err
+		}
+		if len(userRoles) == 0 {
+			err = UpdateUserRoles(kubeClient, jxClient, teamNs, user.SubjectKind(), user.Name, []string{defaultRole}, roles)
+			if err != nil {
+				return added, removed, errors.Wrapf(err, "failed to give User %s the role %s in team %s", login, defaultRole, teamNs)
+			}
+			log.Infof("Gave User %s the role %s in team %s\n", util.ColorInfo(login), util.ColorInfo(defaultRole), util.ColorInfo(teamNs))
+		}
+	}
+
+	for _, login := range removed {
+		name := ToValidName(login)
+		kind := "User"
+		if user := users[name]; user != nil {
+			kind = user.SubjectKind()
+		}
+		userRoles, err := GetUserRoles(jxClient, teamNs, kind, name)
+		if err != nil {
+			return added, removed, err
+		}
+		if len(userRoles) == 0 {
+			continue
+		}
+		err = UpdateUserRoles(kubeClient, jxClient, teamNs, kind, name, []string{}, roles)
+		if err != nil {
+			return added, removed, errors.Wrapf(err, "failed to remove the roles of User %s from team %s", login, teamNs)
+		}
+		log.Infof("Removed User %s from the roles %s of team %s\n", util.ColorInfo(login), util.ColorInfo(strings.Join(userRoles, ", ")), util.ColorInfo(teamNs))
+	}
+	return added, removed, nil
+}
@@ -0,0 +1,92 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	v1fake "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReconcileTeamMembers(t *testing.T) {
+	t.Parallel()
+	adminNs := "jx"
+	teamNs := "myteam"
+	roles := []*rbacv1.Role{}
+	for _, name := range []string{"viewer", "committer"} {
+		roles = append(roles, &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: teamNs,
+				Labels:    map[string]string{kube.LabelKind: kube.ValueKindEnvironmentRole},
+			},
+		})
+	}
+	kubeClient := fake.NewSimpleClientset(roles[0], roles[1])
+	jxClient := v1fake.NewSimpleClientset(kube.CreateUser(adminNs, "carol", "Carol", "carol@example.com"))
+
+	team := kube.CreateTeam(adminNs, teamNs, []string{"alice", "bob"})
+	added, removed, err := kube.ReconcileTeamMembers(kubeClient, jxClient, adminNs, team, "viewer")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, added)
+	assert.Empty(t, removed)
+	kube.SetReconciledTeamMembers(team, team.Spec.Members)
+
+	_, userNames, err := kube.GetUsers(jxClient, adminNs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob", "carol"}, userNames)
+	assertUserRoles(t, jxClient, teamNs, "alice", "viewer")
+	assertUserRoles(t, jxClient, teamNs, "bob", "viewer")
+
+	// roles given explicitly are kept
+	err = kube.UpdateUserRoles(kubeClient, jxClient, teamNs, "User", "bob", []string{"committer"}, map[string]*rbacv1.Role{
+		"viewer":    roles[0],
+		"committer": roles[1],
+	})
+	require.NoError(t, err)
+
+	team.Spec.Members = []string{"bob", "carol"}
+	added, removed, err = kube.ReconcileTeamMembers(kubeClient, jxClient, adminNs, team, "viewer")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"carol"}, added)
+	assert.Equal(t, []string{"alice"}, removed)
+	kube.SetReconciledTeamMembers(team, team.Spec.Members)
+	assert.Equal(t, []string{"bob", "carol"}, kube.ReconciledTeamMembers(team))
+
+	assertUserRoles(t, jxClient, teamNs, "alice")
+	assertUserRoles(t, jxClient, teamNs, "bob", "committer")
+	assertUserRoles(t, jxClient, teamNs, "carol", "viewer")
+}
+
+func assertUserRoles(t *testing.T, jxClient *v1fake.Clientset, ns string, user string, expected ...string) {
+	actual, err := kube.GetUserRoles(jxClient, ns, "User", user)
+	require.NoError(t, err)
+	if len(expected) == 0 {
+		assert.Empty(t, actual, "roles of user %s", user)
+	} else {
+		assert.Equal(t, expected, actual, "roles of user %s", user)
+	}
+}
+
+func TestTeamMemberChanges(t *testing.T) {
+	t.Parallel()
+	team := &v1.Team{
+		Spec: v1.TeamSpec{
+			Members: []string{"a", "c"},
+		},
+	}
+	added, removed := kube.TeamMemberChanges(team)
+	assert.Equal(t, []string{"a", "c"}, added)
+	assert.Empty(t, removed)
+
+	kube.SetReconciledTeamMembers(team, []string{"b", "a"})
+	assert.Equal(t, "a,b", team.Annotations[kube.AnnotationTeamMembers])
+	added, removed = kube.TeamMemberChanges(team)
+	assert.Equal(t, []string{"c"}, added)
+	assert.Equal(t, []string{"b"}, removed)
+}
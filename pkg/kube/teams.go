@@ -6,6 +6,7 @@ import (
 
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/util"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -64,3 +65,35 @@ func DeleteTeam(jxClient versioned.Interface, ns string, teamName string) error
 	}
 	return err
 }
+
+// TeamFinalizer the finalizer which stops a Team being removed until the team controller has removed its resources
+const TeamFinalizer = "jenkins.io/team-controller"
+
+// IsTeamDeleting returns true if the team has been deleted or marked for deletion by 'jx delete team'
+func IsTeamDeleting(team *v1.Team) bool {
+	return team.DeletionTimestamp != nil || team.Status.ProvisionStatus == v1.TeamProvisionStatusDeleting
+}
+
+// HasTeamFinalizer returns true if the team controller removes the resources of the team before it is removed
+func HasTeamFinalizer(team *v1.Team) bool {
+	return util.StringArrayIndex(team.Finalizers, TeamFinalizer) >= 0
+}
+
+// AddTeamFinalizer adds the finalizer of the team controller to the team returning false if it already has it
+func AddTeamFinalizer(team *v1.Team) bool {
+	if HasTeamFinalizer(team) {
+		return false
+	}
+	team.Finalizers = append(team.Finalizers, TeamFinalizer)
+	return true
+}
+
+// RemoveTeamFinalizer removes the finalizer of the team controller from the team returning false if it did not have it
+func RemoveTeamFinalizer(team *v1.Team) bool {
+	idx := util.StringArrayIndex(team.Finalizers, TeamFinalizer)
+	if idx < 0 {
+		return false
+	}
+	team.Finalizers = append(team.Finalizers[:idx], team.Finalizers[idx+1:]...)
+	return true
+}
@@ -0,0 +1,38 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTeamFinalizer(t *testing.T) {
+	t.Parallel()
+	team := kube.CreateTeam("jx", "myteam", nil)
+	team.Finalizers = []string{"other"}
+
+	assert.False(t, kube.HasTeamFinalizer(team))
+	assert.True(t, kube.AddTeamFinalizer(team))
+	assert.False(t, kube.AddTeamFinalizer(team))
+	assert.Equal(t, []string{"other", kube.TeamFinalizer}, team.Finalizers)
+
+	assert.True(t, kube.RemoveTeamFinalizer(team))
+	assert.False(t, kube.RemoveTeamFinalizer(team))
+	assert.Equal(t, []string{"other"}, team.Finalizers)
+}
+
+func TestIsTeamDeleting(t *testing.T) {
+	t.Parallel()
+	team := kube.CreateTeam("jx", "myteam", nil)
+	assert.False(t, kube.IsTeamDeleting(team))
+
+	team.Status.ProvisionStatus = v1.TeamProvisionStatusDeleting
+	assert.True(t, kube.IsTeamDeleting(team))
+
+	team.Status.ProvisionStatus = v1.TeamProvisionStatusComplete
+	team.DeletionTimestamp = &metav1.Time{}
+	assert.True(t, kube.IsTeamDeleting(team))
+}
@@ -2,12 +2,15 @@ package prow
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
 	build "github.com/knative/build/pkg/apis/build/v1alpha1"
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/test-infra/prow/config"
@@ -21,8 +24,20 @@ const (
 
 	Application Kind = "APPLICATION"
 	Environment Kind = "ENVIRONMENT"
+
+	// ConfigMapName the name of the ConfigMap containing the prow config
+	ConfigMapName = "config"
+	// ConfigKey the key of the prow config in its ConfigMap
+	ConfigKey = "config.yaml"
+	// PluginsConfigMapName the name of the ConfigMap containing the prow plugins
+	PluginsConfigMapName = "plugins"
+	// PluginsKey the key of the plugin configuration in its ConfigMap
+	PluginsKey = "plugins.yaml"
 )
 
+// DefaultPlugins the plugins enabled for the repositories added to prow
+var DefaultPlugins = []string{"config-updater", "approve", "assign", "blunderbuss", "help", "hold", "lgtm", "lifecycle", "size", "trigger", "wip", "heart", "cat"}
+
 type Kind string
 
 // Options for prow
@@ -33,6 +48,9 @@ type Options struct {
 	Kind                 Kind
 	DraftPack            string
 	EnvironmentNamespace string
+
+	// Plugins the plugins to enable for the repositories. Defaults to DefaultPlugins for new repositories
+	Plugins []string
 }
 
 func add(kubeClient kubernetes.Interface, repos []string, ns string, kind Kind, draftPack, environmentNamespace string) error {
@@ -65,6 +83,32 @@ func AddApplication(kubeClient kubernetes.Interface, repos []string, ns, draftPa
 	return add(kubeClient, repos, ns, Application, draftPack, "")
 }
 
+// GetRepositories returns the sorted 'owner/name' of the repositories which have jobs in the prow config
+func GetRepositories(kubeClient kubernetes.Interface, ns string) ([]string, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	prowConfig := &config.Config{}
+	err = yaml.Unmarshal([]byte(cm.Data[ConfigKey]), prowConfig)
+	if err != nil {
+		return nil, err
+	}
+	repoMap := map[string]bool{}
+	for repo := range prowConfig.Presubmits {
+		repoMap[repo] = true
+	}
+	for repo := range prowConfig.Postsubmits {
+		repoMap[repo] = true
+	}
+	repos := []string{}
+	for repo := range repoMap {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return repos, nil
+}
+
 // create Git repo?
 // get config and update / overwrite repos?
 // should we get the existing CM and do a diff?
@@ -240,45 +284,32 @@ func (o *Options) createPreSubmitApplication() config.Presubmit {
 	return ps
 }
 
+// addRepoToTideConfig adds the repository to the default tide query of its kind. Repositories which are already in a
+// query, such as those customised with 'jx edit prow', are left alone as tide queries must not overlap
 func (o *Options) addRepoToTideConfig(t *config.Tide, repo string, kind Kind) error {
-	switch o.Kind {
-	case Application:
-		found := false
-		for index, q := range t.Queries {
-			if util.Contains(q.Labels, "approved") {
-				found = true
-				repos := t.Queries[index].Repos
-				if !util.Contains(repos, repo) {
-					repos = append(repos, repo)
-					t.Queries[index].Repos = repos
-				}
-			}
-		}
-
-		if !found {
-			log.Infof("Failed to find 'application' tide config, adding...\n")
-			t.Queries = append(t.Queries, o.createApplicationTideQuery())
+	for _, q := range t.Queries {
+		if util.Contains(q.Repos, repo) {
+			return nil
 		}
+	}
+	var defaultQuery config.TideQuery
+	switch kind {
+	case Application:
+		defaultQuery = o.createApplicationTideQuery()
 	case Environment:
-		found := false
-		for index, q := range t.Queries {
-			if !util.Contains(q.Labels, "approved") {
-				found = true
-				repos := t.Queries[index].Repos
-				if !util.Contains(repos, repo) {
-					repos = append(repos, repo)
-					t.Queries[index].Repos = repos
-				}
-			}
-		}
-
-		if !found {
-			log.Infof("Failed to find 'environment' tide config, adding...\n")
-			t.Queries = append(t.Queries, o.createEnvironmentTideQuery())
-		}
+		defaultQuery = o.createEnvironmentTideQuery()
 	default:
-		return fmt.Errorf("unknown prow config kind %s", o.Kind)
+		return fmt.Errorf("unknown prow config kind %s", kind)
 	}
+	for index, q := range t.Queries {
+		if len(q.Orgs) == 0 && sameStrings(q.Labels, defaultQuery.Labels) && sameStrings(q.MissingLabels, defaultQuery.MissingLabels) {
+			t.Queries[index].Repos = append(q.Repos, repo)
+			return nil
+		}
+	}
+	log.Infof("Failed to find '%s' tide config, adding...\n", strings.ToLower(string(kind)))
+	defaultQuery.Repos = []string{repo}
+	t.Queries = append(t.Queries, defaultQuery)
 	return nil
 }
 
@@ -354,127 +385,162 @@ func (o *Options) AddProwConfig() error {
 		return fmt.Errorf("unknown prow config kind %s", o.Kind)
 	}
 
-	cm, err := o.KubeClient.CoreV1().ConfigMaps(o.NS).Get("config", metav1.GetOptions{})
-	create := true
-	prowConfig := &config.Config{}
-	// config doesn't exist, creating
+	prowConfig, exists, err := loadProwConfig(o.KubeClient, o.NS)
 	if err != nil {
-		prowConfig.Presubmits = make(map[string][]config.Presubmit)
-		prowConfig.Postsubmits = make(map[string][]config.Postsubmit)
+		return err
+	}
+	if !exists {
 		prowConfig.Tide = o.createTide()
-	} else {
-		// config exists, updating
-		create = false
-		err = yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &prowConfig)
-		if err != nil {
-			return err
-		}
-		if len(prowConfig.Presubmits) == 0 {
-			prowConfig.Presubmits = make(map[string][]config.Presubmit)
-		}
-		if len(prowConfig.Postsubmits) == 0 {
-			prowConfig.Postsubmits = make(map[string][]config.Postsubmit)
-		}
 	}
 	prowConfig.PodNamespace = o.NS
 	prowConfig.ProwJobNamespace = o.NS
 
 	for _, r := range o.Repos {
-		o.addRepoToTideConfig(&prowConfig.Tide, r, o.Kind)
-	}
-
-	for _, r := range o.Repos {
-		prowConfig.Presubmits[r] = []config.Presubmit{preSubmit}
-		prowConfig.Postsubmits[r] = []config.Postsubmit{postSubmit}
-	}
-
-	configYAML, err := yaml.Marshal(prowConfig)
-	if err != nil {
-		return err
-	}
-
-	data := make(map[string]string)
-	data["config.yaml"] = string(configYAML)
-	cm = &v1.ConfigMap{
-		Data: data,
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "config",
-		},
-	}
+		err = o.addRepoToTideConfig(&prowConfig.Tide, r, o.Kind)
+		if err != nil {
+			return err
+		}
 
-	if create {
-		_, err = o.KubeClient.CoreV1().ConfigMaps(o.NS).Create(cm)
-	} else {
-		_, err = o.KubeClient.CoreV1().ConfigMaps(o.NS).Update(cm)
+		// lets not lose the jobs of repositories which have been customised with 'jx edit prow'
+		if len(prowConfig.Presubmits[r]) == 0 && len(prowConfig.Postsubmits[r]) == 0 {
+			prowConfig.Presubmits[r] = []config.Presubmit{preSubmit}
+			prowConfig.Postsubmits[r] = []config.Postsubmit{postSubmit}
+		}
 	}
 
-	return err
-
+	return saveProwConfig(o.KubeClient, o.NS, prowConfig, exists)
 }
 
 // AddProwPlugins adds plugins to prow
 func (o *Options) AddProwPlugins() error {
-
-	pluginsList := []string{"config-updater", "approve", "assign", "blunderbuss", "help", "hold", "lgtm", "lifecycle", "size", "trigger", "wip", "heart", "cat"}
-
-	cm, err := o.KubeClient.CoreV1().ConfigMaps(o.NS).Get("plugins", metav1.GetOptions{})
-	create := true
-	pluginConfig := &plugins.Configuration{}
+	pluginConfig, exists, err := loadPluginConfig(o.KubeClient, o.NS)
 	if err != nil {
-		pluginConfig.Plugins = make(map[string][]string)
-		pluginConfig.Approve = []plugins.Approve{}
-
+		return err
+	}
+	if !exists {
 		pluginConfig.ConfigUpdater.Maps = make(map[string]plugins.ConfigMapSpec)
 		pluginConfig.ConfigUpdater.Maps["prow/config.yaml"] = plugins.ConfigMapSpec{Name: "config"}
 		pluginConfig.ConfigUpdater.Maps["prow/plugins.yaml"] = plugins.ConfigMapSpec{Name: "plugins"}
+	}
 
-	} else {
-		create = false
-		err = yaml.Unmarshal([]byte(cm.Data["plugins.yaml"]), &pluginConfig)
-		if err != nil {
-			return err
-		}
-		if pluginConfig == nil {
-			pluginConfig = &plugins.Configuration{}
+	for _, r := range o.Repos {
+		// lets not lose the plugins of repositories which have been customised with 'jx edit prow'
+		if len(o.Plugins) > 0 {
+			pluginConfig.Plugins[r] = o.Plugins
+		} else if len(pluginConfig.Plugins[r]) == 0 {
+			pluginConfig.Plugins[r] = DefaultPlugins
 		}
-		if len(pluginConfig.Plugins) == 0 {
-			pluginConfig.Plugins = make(map[string][]string)
+
+		found := false
+		for _, a := range pluginConfig.Approve {
+			if util.Contains(a.Repos, r) {
+				found = true
+				break
+			}
 		}
-		if len(pluginConfig.Approve) == 0 {
-			pluginConfig.Approve = []plugins.Approve{}
+		if !found {
+			a := plugins.Approve{
+				Repos:               []string{r},
+				ReviewActsAsApprove: true,
+				LgtmActsAsApprove:   true,
+			}
+			pluginConfig.Approve = append(pluginConfig.Approve, a)
 		}
 	}
 
-	for _, r := range o.Repos {
-		pluginConfig.Plugins[r] = pluginsList
+	return savePluginConfig(o.KubeClient, o.NS, pluginConfig, exists)
+}
 
-		a := plugins.Approve{
-			Repos:               []string{r},
-			ReviewActsAsApprove: true,
-			LgtmActsAsApprove:   true,
+// loadProwConfig loads the prow config from the config ConfigMap returning whether the ConfigMap exists
+func loadProwConfig(kubeClient kubernetes.Interface, ns string) (*config.Config, bool, error) {
+	prowConfig := &config.Config{}
+	exists := true
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, false, err
+		}
+		exists = false
+	} else {
+		err = yaml.Unmarshal([]byte(cm.Data[ConfigKey]), prowConfig)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to parse the prow config: %s", err)
 		}
-		pluginConfig.Approve = append(pluginConfig.Approve, a)
+	}
+	if len(prowConfig.Presubmits) == 0 {
+		prowConfig.Presubmits = make(map[string][]config.Presubmit)
+	}
+	if len(prowConfig.Postsubmits) == 0 {
+		prowConfig.Postsubmits = make(map[string][]config.Postsubmit)
+	}
+	return prowConfig, exists, nil
+}
 
+// saveProwConfig validates the prow config and then writes it to the config ConfigMap
+func saveProwConfig(kubeClient kubernetes.Interface, ns string, prowConfig *config.Config, exists bool) error {
+	err := ValidateConfig(prowConfig)
+	if err != nil {
+		return fmt.Errorf("invalid prow config: %s", err)
+	}
+	configYAML, err := yaml.Marshal(prowConfig)
+	if err != nil {
+		return err
+	}
+	return saveConfigMap(kubeClient, ns, ConfigMapName, ConfigKey, string(configYAML), exists)
+}
+
+// loadPluginConfig loads the plugin configuration from the plugins ConfigMap returning whether the ConfigMap exists
+func loadPluginConfig(kubeClient kubernetes.Interface, ns string) (*plugins.Configuration, bool, error) {
+	pluginConfig := &plugins.Configuration{}
+	exists := true
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(PluginsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, false, err
+		}
+		exists = false
+	} else {
+		err = yaml.Unmarshal([]byte(cm.Data[PluginsKey]), pluginConfig)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to parse the prow plugins: %s", err)
+		}
+	}
+	if len(pluginConfig.Plugins) == 0 {
+		pluginConfig.Plugins = make(map[string][]string)
 	}
+	if len(pluginConfig.Approve) == 0 {
+		pluginConfig.Approve = []plugins.Approve{}
+	}
+	return pluginConfig, exists, nil
+}
 
+// savePluginConfig validates the plugin configuration and then writes it to the plugins ConfigMap
+func savePluginConfig(kubeClient kubernetes.Interface, ns string, pluginConfig *plugins.Configuration, exists bool) error {
+	err := ValidatePluginConfig(pluginConfig)
+	if err != nil {
+		return fmt.Errorf("invalid prow plugins: %s", err)
+	}
 	pluginYAML, err := yaml.Marshal(pluginConfig)
 	if err != nil {
 		return err
 	}
+	return saveConfigMap(kubeClient, ns, PluginsConfigMapName, PluginsKey, string(pluginYAML), exists)
+}
 
-	data := make(map[string]string)
-	data["plugins.yaml"] = string(pluginYAML)
-	cm = &v1.ConfigMap{
-		Data: data,
+func saveConfigMap(kubeClient kubernetes.Interface, ns string, name string, key string, value string, exists bool) error {
+	cm := &v1.ConfigMap{
+		Data: map[string]string{
+			key: value,
+		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "plugins",
+			Name: name,
 		},
 	}
-	if create {
-		_, err = o.KubeClient.CoreV1().ConfigMaps(o.NS).Create(cm)
+	var err error
+	if exists {
+		_, err = kubeClient.CoreV1().ConfigMaps(ns).Update(cm)
 	} else {
-		_, err = o.KubeClient.CoreV1().ConfigMaps(o.NS).Update(cm)
+		_, err = kubeClient.CoreV1().ConfigMaps(ns).Create(cm)
 	}
-
 	return err
 }
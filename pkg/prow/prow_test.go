@@ -172,7 +172,7 @@ func TestAddProwConfig(t *testing.T) {
 	assert.NotEmpty(t, prowConfig.Presubmits["test/repo2"])
 }
 
-// make sure that rerunning addProwConfig replaces any modified changes in the configmap
+// make sure that rerunning addProwConfig keeps any modified jobs in the configmap
 func TestReplaceProwConfig(t *testing.T) {
 	t.Parallel()
 	o := TestOptions{}
@@ -194,7 +194,7 @@ func TestReplaceProwConfig(t *testing.T) {
 	assert.Equal(t, 2, len(prowConfig.Tide.Queries[1].Repos))
 
 	p := prowConfig.Presubmits["test/repo"]
-	p[0].Agent = "foo"
+	p[0].RerunCommand = "/test all"
 
 	configYAML, err := yaml.Marshal(&prowConfig)
 	assert.NoError(t, err)
@@ -218,13 +218,13 @@ func TestReplaceProwConfig(t *testing.T) {
 	yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &prowConfig)
 
 	p = prowConfig.Presubmits["test/repo"]
-	assert.Equal(t, "foo", p[0].Agent)
+	assert.Equal(t, "/test all", p[0].RerunCommand)
 
 	// generate the prow config again
 	err = o.AddProwConfig()
 	assert.NoError(t, err)
 
-	// assert value is reset
+	// assert value is kept
 	cm, err = o.KubeClient.CoreV1().ConfigMaps(o.NS).Get("config", metav1.GetOptions{})
 	assert.NoError(t, err)
 
@@ -235,6 +235,7 @@ func TestReplaceProwConfig(t *testing.T) {
 	assert.Equal(t, 2, len(prowConfig.Tide.Queries[1].Repos))
 
 	p = prowConfig.Presubmits["test/repo"]
+	assert.Equal(t, "/test all", p[0].RerunCommand)
 	assert.Equal(t, "knative-build", p[0].Agent)
 
 	// add test/repo2
@@ -269,3 +270,259 @@ func TestReplaceProwConfig(t *testing.T) {
 	assert.Equal(t, 3, len(prowConfig.Tide.Queries[0].Repos))
 	assert.Equal(t, 2, len(prowConfig.Tide.Queries[1].Repos))
 }
+
+func TestGetRepositories(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Application
+	o.Repos = []string{"test/repo2", "test/repo"}
+
+	err := o.AddProwConfig()
+	assert.NoError(t, err)
+
+	o.Kind = prow.Environment
+	o.EnvironmentNamespace = "jx-staging"
+	o.Repos = []string{"test/environment-staging"}
+	err = o.AddProwConfig()
+	assert.NoError(t, err)
+
+	repos, err := prow.GetRepositories(o.KubeClient, o.NS)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test/environment-staging", "test/repo", "test/repo2"}, repos)
+}
+
+func TestAddProwPluginsKeepsCustomisedPlugins(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Application
+
+	err := o.AddProwPlugins()
+	assert.NoError(t, err)
+
+	err = prow.UpdateRepositoryConfig(o.KubeClient, o.NS, "test/repo", func(rc *prow.RepositoryConfig) error {
+		rc.Plugins = []string{"trigger", "lgtm"}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = o.AddProwPlugins()
+	assert.NoError(t, err)
+
+	rc, err := prow.GetRepositoryConfig(o.KubeClient, o.NS, "test/repo")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"trigger", "lgtm"}, rc.Plugins)
+
+	cm, err := o.KubeClient.CoreV1().ConfigMaps(o.NS).Get("plugins", metav1.GetOptions{})
+	assert.NoError(t, err)
+	pluginConfig := &plugins.Configuration{}
+	yaml.Unmarshal([]byte(cm.Data["plugins.yaml"]), &pluginConfig)
+	assert.Equal(t, 1, len(pluginConfig.Approve), "the approve config should not be duplicated")
+}
+
+func TestUpdateRepositoryConfig(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Application
+	o.Repos = []string{"test/repo", "test/repo2"}
+
+	err := o.AddProwConfig()
+	assert.NoError(t, err)
+	err = o.AddProwPlugins()
+	assert.NoError(t, err)
+
+	err = prow.UpdateRepositoryConfig(o.KubeClient, o.NS, "test/repo", func(rc *prow.RepositoryConfig) error {
+		assert.True(t, rc.Tide)
+		assert.Equal(t, prow.DefaultPlugins, rc.Plugins)
+		rc.Plugins = append(rc.Plugins, "shrug")
+		rc.MergeMethod = "squash"
+		rc.Labels = append(rc.Labels, "lgtm")
+		rc.TrustedOrg = "test"
+		rc.SetPresubmit(config.Presubmit{
+			Name:         "lint",
+			Context:      "lint",
+			Agent:        prow.KnativeBuildAgent,
+			BuildSpec:    rc.Presubmits[0].BuildSpec,
+			Trigger:      "(?m)^/lint,?(\\s+|$)",
+			RerunCommand: "/lint",
+		})
+		assert.True(t, rc.RemovePostsubmit("release"))
+		return nil
+	})
+	assert.NoError(t, err)
+
+	rc, err := prow.GetRepositoryConfig(o.KubeClient, o.NS, "test/repo")
+	assert.NoError(t, err)
+	assert.Contains(t, rc.Plugins, "shrug")
+	assert.Equal(t, "squash", rc.MergeMethod)
+	assert.Equal(t, []string{"approved", "lgtm"}, rc.Labels)
+	assert.Equal(t, "test", rc.TrustedOrg)
+	assert.Equal(t, 2, len(rc.Presubmits))
+	assert.Empty(t, rc.Postsubmits)
+
+	// the other repository keeps the shared policy
+	rc2, err := prow.GetRepositoryConfig(o.KubeClient, o.NS, "test/repo2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"approved"}, rc2.Labels)
+	assert.Equal(t, "", rc2.MergeMethod)
+	assert.Equal(t, "", rc2.TrustedOrg)
+
+	err = prow.Validate(o.KubeClient, o.NS)
+	assert.NoError(t, err)
+}
+
+func TestAddProwConfigAfterEditingRepository(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Application
+	o.Repos = []string{"myorg/a"}
+
+	err := o.AddProwConfig()
+	assert.NoError(t, err)
+
+	err = prow.UpdateRepositoryConfig(o.KubeClient, o.NS, "myorg/a", func(rc *prow.RepositoryConfig) error {
+		rc.Labels = append(rc.Labels, "lgtm")
+		rc.SetPresubmit(config.Presubmit{
+			Name:         "lint",
+			Context:      "lint",
+			Agent:        prow.KnativeBuildAgent,
+			BuildSpec:    rc.Presubmits[0].BuildSpec,
+			Trigger:      "(?m)^/lint,?(\\s+|$)",
+			RerunCommand: "/lint",
+		})
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// importing another repository adds it to the default query only
+	o.Repos = []string{"myorg/b"}
+	err = o.AddProwConfig()
+	assert.NoError(t, err)
+
+	// re-importing the edited repository keeps its policy and jobs
+	o.Repos = []string{"myorg/a"}
+	err = o.AddProwConfig()
+	assert.NoError(t, err)
+
+	a, err := prow.GetRepositoryConfig(o.KubeClient, o.NS, "myorg/a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"approved", "lgtm"}, a.Labels)
+	assert.Equal(t, 2, len(a.Presubmits))
+
+	b, err := prow.GetRepositoryConfig(o.KubeClient, o.NS, "myorg/b")
+	assert.NoError(t, err)
+	assert.True(t, b.Tide)
+	assert.Equal(t, []string{"approved"}, b.Labels)
+
+	err = prow.Validate(o.KubeClient, o.NS)
+	assert.NoError(t, err)
+}
+
+func TestUpdateRepositoryConfigRejectsInvalidChanges(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Application
+
+	err := o.AddProwConfig()
+	assert.NoError(t, err)
+
+	err = prow.UpdateRepositoryConfig(o.KubeClient, o.NS, "test/repo", func(rc *prow.RepositoryConfig) error {
+		rc.Labels = append(rc.Labels, "do-not-merge")
+		return nil
+	})
+	assert.Error(t, err, "a label cannot be both required and missing")
+
+	err = prow.UpdateRepositoryConfig(o.KubeClient, o.NS, "test/repo", func(rc *prow.RepositoryConfig) error {
+		rc.Presubmits[0].RerunCommand = "/retest"
+		return nil
+	})
+	assert.Error(t, err, "the rerun command must match the trigger")
+
+	err = prow.UpdateRepositoryConfig(o.KubeClient, o.NS, "test/repo", func(rc *prow.RepositoryConfig) error {
+		rc.MergeMethod = "fast-forward"
+		return nil
+	})
+	assert.Error(t, err)
+
+	rc, err := prow.GetRepositoryConfig(o.KubeClient, o.NS, "test/repo")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"approved"}, rc.Labels, "invalid changes should not be written")
+	assert.Equal(t, "/test this", rc.Presubmits[0].RerunCommand)
+}
+
+func TestRemoveRepository(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Application
+	o.Repos = []string{"test/repo", "test/repo2"}
+
+	err := o.AddProwConfig()
+	assert.NoError(t, err)
+	err = o.AddProwPlugins()
+	assert.NoError(t, err)
+
+	err = prow.RemoveRepository(o.KubeClient, o.NS, "test/repo")
+	assert.NoError(t, err)
+
+	repos, err := prow.GetRepositories(o.KubeClient, o.NS)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test/repo2"}, repos)
+
+	rc, err := prow.GetRepositoryConfig(o.KubeClient, o.NS, "test/repo")
+	assert.NoError(t, err)
+	assert.False(t, rc.Tide)
+	assert.Empty(t, rc.Plugins)
+
+	cm, err := o.KubeClient.CoreV1().ConfigMaps(o.NS).Get("plugins", metav1.GetOptions{})
+	assert.NoError(t, err)
+	pluginConfig := &plugins.Configuration{}
+	yaml.Unmarshal([]byte(cm.Data["plugins.yaml"]), &pluginConfig)
+	assert.Equal(t, 1, len(pluginConfig.Approve))
+	assert.Equal(t, []string{"test/repo2"}, pluginConfig.Approve[0].Repos)
+}
+
+func TestValidateConfig(t *testing.T) {
+	t.Parallel()
+	prowConfig := &config.Config{}
+	prowConfig.Presubmits = map[string][]config.Presubmit{
+		"test": {{Name: "build", Agent: prow.KubernetesAgent}},
+		"test/repo": {
+			{Name: "build", Agent: prow.KubernetesAgent, Spec: &v1.PodSpec{}},
+			{Name: "build", Agent: prow.KubernetesAgent, Spec: &v1.PodSpec{}},
+		},
+	}
+	prowConfig.Tide.Queries = config.TideQueries{
+		{Repos: []string{"test/repo"}},
+		{Repos: []string{"test/repo"}, Labels: []string{"approved"}},
+	}
+	err := prow.ValidateConfig(prowConfig)
+	assert.Error(t, err)
+	message := err.Error()
+	assert.Contains(t, message, `"test" is not of the form owner/name`)
+	assert.Contains(t, message, "presubmit build of test uses the kubernetes agent so must have a spec")
+	assert.Contains(t, message, "duplicate presubmit build for test/repo")
+	assert.Contains(t, message, "repository test/repo is in tide queries 0 and 1")
+}
+
+func TestValidatePluginConfig(t *testing.T) {
+	t.Parallel()
+	pluginConfig := &plugins.Configuration{
+		Plugins: map[string][]string{
+			"test":      {"lgtm"},
+			"test/repo": {"lgtm", "hold", "hold"},
+		},
+	}
+	err := prow.ValidatePluginConfig(pluginConfig)
+	assert.Error(t, err)
+	message := err.Error()
+	assert.Contains(t, message, "plugin hold is listed more than once for test/repo")
+	assert.Contains(t, message, "plugin lgtm is enabled for both test and test/repo")
+
+	pluginConfig.Plugins["test/repo"] = []string{"hold"}
+	assert.NoError(t, prow.ValidatePluginConfig(pluginConfig))
+}
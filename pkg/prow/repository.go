@@ -0,0 +1,289 @@
+package prow
+
+import (
+	"github.com/jenkins-x/jx/pkg/util"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/test-infra/prow/config"
+	"k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/plugins"
+)
+
+// RepositoryConfig the prow configuration of a single 'owner/name' repository gathered from the config and plugins ConfigMaps
+type RepositoryConfig struct {
+	Repo string
+
+	// Plugins the plugins enabled for the repository
+	Plugins []string
+
+	// TrustedOrg the org whose members can trigger the jobs of Pull Requests. Defaults to the owner of the repository
+	TrustedOrg string
+	// OnlyOrgMembers only lets members of the trusted org trigger jobs rather than collaborators too
+	OnlyOrgMembers bool
+
+	// Tide whether tide merges the Pull Requests of the repository
+	Tide bool
+	// MergeMethod how tide merges Pull Requests. Defaults to merge if blank
+	MergeMethod string
+	// Labels the labels a Pull Request needs before tide merges it
+	Labels []string
+	// MissingLabels the labels which stop tide merging a Pull Request
+	MissingLabels []string
+
+	Presubmits  []config.Presubmit
+	Postsubmits []config.Postsubmit
+}
+
+// SetPresubmit adds the presubmit job or replaces the job with the same name
+func (r *RepositoryConfig) SetPresubmit(job config.Presubmit) {
+	for i, p := range r.Presubmits {
+		if p.Name == job.Name {
+			r.Presubmits[i] = job
+			return
+		}
+	}
+	r.Presubmits = append(r.Presubmits, job)
+}
+
+// RemovePresubmit removes the presubmit job with the name returning false if there is no such job
+func (r *RepositoryConfig) RemovePresubmit(name string) bool {
+	for i, p := range r.Presubmits {
+		if p.Name == name {
+			r.Presubmits = append(r.Presubmits[:i], r.Presubmits[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// SetPostsubmit adds the postsubmit job or replaces the job with the same name
+func (r *RepositoryConfig) SetPostsubmit(job config.Postsubmit) {
+	for i, p := range r.Postsubmits {
+		if p.Name == job.Name {
+			r.Postsubmits[i] = job
+			return
+		}
+	}
+	r.Postsubmits = append(r.Postsubmits, job)
+}
+
+// RemovePostsubmit removes the postsubmit job with the name returning false if there is no such job
+func (r *RepositoryConfig) RemovePostsubmit(name string) bool {
+	for i, p := range r.Postsubmits {
+		if p.Name == name {
+			r.Postsubmits = append(r.Postsubmits[:i], r.Postsubmits[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetRepositoryConfig returns the prow configuration of the 'owner/name' repository
+func GetRepositoryConfig(kubeClient kubernetes.Interface, ns string, repo string) (*RepositoryConfig, error) {
+	prowConfig, _, err := loadProwConfig(kubeClient, ns)
+	if err != nil {
+		return nil, err
+	}
+	pluginConfig, _, err := loadPluginConfig(kubeClient, ns)
+	if err != nil {
+		return nil, err
+	}
+	return repositoryConfig(prowConfig, pluginConfig, repo), nil
+}
+
+// UpdateRepositoryConfig lets the function change the prow configuration of the 'owner/name' repository then
+// validates the config and plugins ConfigMaps before writing them
+func UpdateRepositoryConfig(kubeClient kubernetes.Interface, ns string, repo string, fn func(*RepositoryConfig) error) error {
+	err := validateRepoName(repo, false)
+	if err != nil {
+		return err
+	}
+	prowConfig, configExists, err := loadProwConfig(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	pluginConfig, pluginsExist, err := loadPluginConfig(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	rc := repositoryConfig(prowConfig, pluginConfig, repo)
+	err = fn(rc)
+	if err != nil {
+		return err
+	}
+	if rc.MergeMethod != "" && util.StringArrayIndex(MergeMethods, rc.MergeMethod) < 0 {
+		return util.InvalidOption("merge-method", rc.MergeMethod, MergeMethods)
+	}
+	applyRepositoryConfig(prowConfig, pluginConfig, rc)
+
+	// lets validate both before writing either so that we don't leave prow half configured
+	err = util.CombineErrors(ValidateConfig(prowConfig), ValidatePluginConfig(pluginConfig))
+	if err != nil {
+		return err
+	}
+	err = saveProwConfig(kubeClient, ns, prowConfig, configExists)
+	if err != nil {
+		return err
+	}
+	return savePluginConfig(kubeClient, ns, pluginConfig, pluginsExist)
+}
+
+// RemoveRepository removes the jobs, tide policy and plugins of the 'owner/name' repository from prow
+func RemoveRepository(kubeClient kubernetes.Interface, ns string, repo string) error {
+	return UpdateRepositoryConfig(kubeClient, ns, repo, func(rc *RepositoryConfig) error {
+		*rc = RepositoryConfig{Repo: repo}
+		return nil
+	})
+}
+
+func repositoryConfig(prowConfig *config.Config, pluginConfig *plugins.Configuration, repo string) *RepositoryConfig {
+	rc := &RepositoryConfig{
+		Repo:        repo,
+		Plugins:     append([]string{}, pluginConfig.Plugins[repo]...),
+		MergeMethod: string(prowConfig.Tide.MergeType[repo]),
+		Presubmits:  append([]config.Presubmit{}, prowConfig.Presubmits[repo]...),
+		Postsubmits: append([]config.Postsubmit{}, prowConfig.Postsubmits[repo]...),
+	}
+	for _, q := range prowConfig.Tide.Queries {
+		if util.Contains(q.Repos, repo) {
+			rc.Tide = true
+			rc.Labels = append([]string{}, q.Labels...)
+			rc.MissingLabels = append([]string{}, q.MissingLabels...)
+			break
+		}
+	}
+	for _, t := range pluginConfig.Triggers {
+		if util.Contains(t.Repos, repo) {
+			rc.TrustedOrg = t.TrustedOrg
+			rc.OnlyOrgMembers = t.OnlyOrgMembers
+			break
+		}
+	}
+	return rc
+}
+
+func applyRepositoryConfig(prowConfig *config.Config, pluginConfig *plugins.Configuration, rc *RepositoryConfig) {
+	repo := rc.Repo
+	if len(rc.Presubmits) > 0 {
+		prowConfig.Presubmits[repo] = rc.Presubmits
+	} else {
+		delete(prowConfig.Presubmits, repo)
+	}
+	if len(rc.Postsubmits) > 0 {
+		prowConfig.Postsubmits[repo] = rc.Postsubmits
+	} else {
+		delete(prowConfig.Postsubmits, repo)
+	}
+
+	if rc.MergeMethod != "" && rc.Tide {
+		if prowConfig.Tide.MergeType == nil {
+			prowConfig.Tide.MergeType = map[string]github.PullRequestMergeType{}
+		}
+		prowConfig.Tide.MergeType[repo] = github.PullRequestMergeType(rc.MergeMethod)
+	} else {
+		delete(prowConfig.Tide.MergeType, repo)
+	}
+	setTideQuery(&prowConfig.Tide, rc)
+
+	if len(rc.Plugins) > 0 {
+		pluginConfig.Plugins[repo] = rc.Plugins
+	} else {
+		delete(pluginConfig.Plugins, repo)
+		approve := []plugins.Approve{}
+		for _, a := range pluginConfig.Approve {
+			a.Repos = removeString(a.Repos, repo)
+			if len(a.Repos) > 0 {
+				approve = append(approve, a)
+			}
+		}
+		pluginConfig.Approve = approve
+		lgtm := []plugins.Lgtm{}
+		for _, l := range pluginConfig.Lgtm {
+			l.Repos = removeString(l.Repos, repo)
+			if len(l.Repos) > 0 {
+				lgtm = append(lgtm, l)
+			}
+		}
+		pluginConfig.Lgtm = lgtm
+		delete(pluginConfig.ExternalPlugins, repo)
+	}
+
+	triggers := []plugins.Trigger{}
+	for _, t := range pluginConfig.Triggers {
+		t.Repos = removeString(t.Repos, repo)
+		if len(t.Repos) > 0 {
+			triggers = append(triggers, t)
+		}
+	}
+	if rc.TrustedOrg != "" || rc.OnlyOrgMembers {
+		triggers = append(triggers, plugins.Trigger{
+			Repos:          []string{repo},
+			TrustedOrg:     rc.TrustedOrg,
+			OnlyOrgMembers: rc.OnlyOrgMembers,
+		})
+	}
+	pluginConfig.Triggers = triggers
+}
+
+// setTideQuery moves the repository into the tide query with the labels of the repository creating one if need be.
+// A repository is only ever in one query as tide queries must not overlap
+func setTideQuery(t *config.Tide, rc *RepositoryConfig) {
+	queries := config.TideQueries{}
+	for _, q := range t.Queries {
+		if util.Contains(q.Repos, rc.Repo) {
+			q.Repos = removeString(q.Repos, rc.Repo)
+			if len(q.Repos) == 0 && len(q.Orgs) == 0 {
+				// an empty query would match every Pull Request
+				continue
+			}
+		}
+		queries = append(queries, q)
+	}
+	if rc.Tide {
+		found := false
+		for i, q := range queries {
+			if len(q.Orgs) == 0 && len(q.ExcludedBranches) == 0 && len(q.IncludedBranches) == 0 && q.Milestone == "" &&
+				!q.ReviewApprovedRequired && sameStrings(q.Labels, rc.Labels) && sameStrings(q.MissingLabels, rc.MissingLabels) {
+				queries[i].Repos = append(q.Repos, rc.Repo)
+				found = true
+				break
+			}
+		}
+		if !found {
+			queries = append(queries, config.TideQuery{
+				Repos:         []string{rc.Repo},
+				Labels:        append([]string{}, rc.Labels...),
+				MissingLabels: append([]string{}, rc.MissingLabels...),
+			})
+		}
+	}
+	t.Queries = queries
+}
+
+// removeString returns a copy of the values without the value
+func removeString(values []string, value string) []string {
+	answer := []string{}
+	for _, v := range values {
+		if v != value {
+			answer = append(answer, v)
+		}
+	}
+	return answer
+}
+
+// sameStrings returns true if both slices contain the same strings in any order
+func sameStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, s := range a {
+		if !util.Contains(b, s) {
+			return false
+		}
+	}
+	for _, s := range b {
+		if !util.Contains(a, s) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,248 @@
+package prow
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/test-infra/prow/config"
+	"k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/plugins"
+)
+
+const (
+	// KubernetesAgent the agent which runs jobs as pods
+	KubernetesAgent = "kubernetes"
+	// KnativeBuildAgent the agent which runs jobs as knative builds
+	KnativeBuildAgent = "knative-build"
+	// JenkinsAgent the agent which runs jobs on Jenkins
+	JenkinsAgent = "jenkins"
+)
+
+// MergeMethods the merge methods which tide supports
+var MergeMethods = []string{string(github.MergeMerge), string(github.MergeRebase), string(github.MergeSquash)}
+
+// Validate lints the config and plugins ConfigMaps in the namespace
+func Validate(kubeClient kubernetes.Interface, ns string) error {
+	prowConfig, _, err := loadProwConfig(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	pluginConfig, _, err := loadPluginConfig(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	return util.CombineErrors(ValidateConfig(prowConfig), ValidatePluginConfig(pluginConfig))
+}
+
+// ValidateConfig returns an error describing the problems in the prow config which would stop prow from loading it
+func ValidateConfig(prowConfig *config.Config) error {
+	errs := []error{}
+	for _, repo := range sortedKeys(prowConfig.Presubmits) {
+		errs = append(errs, validateRepoName(repo, false))
+		errs = append(errs, validatePresubmits(repo, prowConfig.Presubmits[repo]))
+	}
+	for _, repo := range sortedKeys(prowConfig.Postsubmits) {
+		errs = append(errs, validateRepoName(repo, false))
+		errs = append(errs, validatePostsubmits(repo, prowConfig.Postsubmits[repo]))
+	}
+	errs = append(errs, validateTide(&prowConfig.Tide))
+	return util.CombineErrors(errs...)
+}
+
+func validatePresubmits(repo string, jobs []config.Presubmit) error {
+	errs := []error{}
+	names := map[string]bool{}
+	contexts := map[string]bool{}
+	for _, job := range jobs {
+		if job.Name == "" {
+			errs = append(errs, fmt.Errorf("presubmit of %s has no name", repo))
+			continue
+		}
+		if names[job.Name] {
+			errs = append(errs, fmt.Errorf("duplicate presubmit %s for %s", job.Name, repo))
+		}
+		names[job.Name] = true
+		if job.Context != "" {
+			if contexts[job.Context] {
+				errs = append(errs, fmt.Errorf("presubmit %s of %s reuses the context %s", job.Name, repo, job.Context))
+			}
+			contexts[job.Context] = true
+		}
+		if (job.Trigger == "") != (job.RerunCommand == "") {
+			errs = append(errs, fmt.Errorf("presubmit %s of %s must have both a trigger and a rerun command or neither", job.Name, repo))
+		}
+		errs = append(errs, validateAgent("presubmit "+job.Name+" of "+repo, job.Agent, job.Spec != nil, job.BuildSpec != nil))
+	}
+	// SetPresubmitRegexes compiles the triggers, branches and changes and checks the rerun command matches the trigger
+	copied := append([]config.Presubmit{}, jobs...)
+	for i, job := range copied {
+		if job.Trigger == "" && job.RerunCommand == "" {
+			copied[i].Trigger = config.DefaultTriggerFor(job.Name)
+			copied[i].RerunCommand = config.DefaultRerunCommandFor(job.Name)
+		}
+	}
+	err := config.SetPresubmitRegexes(copied)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid presubmit of %s: %s", repo, err))
+	}
+	return util.CombineErrors(errs...)
+}
+
+func validatePostsubmits(repo string, jobs []config.Postsubmit) error {
+	errs := []error{}
+	names := map[string]bool{}
+	for _, job := range jobs {
+		if job.Name == "" {
+			errs = append(errs, fmt.Errorf("postsubmit of %s has no name", repo))
+			continue
+		}
+		if names[job.Name] {
+			errs = append(errs, fmt.Errorf("duplicate postsubmit %s for %s", job.Name, repo))
+		}
+		names[job.Name] = true
+		errs = append(errs, validateAgent("postsubmit "+job.Name+" of "+repo, job.Agent, job.Spec != nil, job.BuildSpec != nil))
+	}
+	err := config.SetPostsubmitRegexes(append([]config.Postsubmit{}, jobs...))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid postsubmit of %s: %s", repo, err))
+	}
+	return util.CombineErrors(errs...)
+}
+
+func validateAgent(job string, agent string, hasSpec bool, hasBuildSpec bool) error {
+	switch agent {
+	case KubernetesAgent, "":
+		if !hasSpec {
+			return fmt.Errorf("%s uses the %s agent so must have a spec", job, KubernetesAgent)
+		}
+	case KnativeBuildAgent:
+		if !hasBuildSpec {
+			return fmt.Errorf("%s uses the %s agent so must have a build_spec", job, KnativeBuildAgent)
+		}
+	case JenkinsAgent:
+	default:
+		return fmt.Errorf("%s has unknown agent %s", job, agent)
+	}
+	if hasSpec && hasBuildSpec {
+		return fmt.Errorf("%s cannot have both a spec and a build_spec", job)
+	}
+	return nil
+}
+
+func validateTide(t *config.Tide) error {
+	errs := []error{}
+	queried := map[string]int{}
+	for i, q := range t.Queries {
+		err := q.Validate()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tide query %d: %s", i, err))
+		}
+		if len(q.Orgs) == 0 && len(q.Repos) == 0 {
+			errs = append(errs, fmt.Errorf("tide query %d has no orgs or repos so would merge every Pull Request", i))
+		}
+		// queries must not overlap or tide could merge a Pull Request with the wrong policy
+		for _, repo := range q.Repos {
+			if previous, ok := queried[repo]; ok {
+				errs = append(errs, fmt.Errorf("repository %s is in tide queries %d and %d", repo, previous, i))
+			}
+			queried[repo] = i
+		}
+	}
+	for _, repo := range sortedKeys(t.MergeType) {
+		method := string(t.MergeType[repo])
+		if util.StringArrayIndex(MergeMethods, method) < 0 {
+			errs = append(errs, fmt.Errorf("invalid tide merge method %s for %s. Valid methods are %s", method, repo, strings.Join(MergeMethods, ", ")))
+		}
+	}
+	err := t.ContextOptions.TideContextPolicy.Validate()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("tide context options: %s", err))
+	}
+	return util.CombineErrors(errs...)
+}
+
+// ValidatePluginConfig returns an error describing the problems in the plugin configuration which would stop prow from loading it
+func ValidatePluginConfig(pluginConfig *plugins.Configuration) error {
+	errs := []error{}
+	for _, repo := range sortedKeys(pluginConfig.Plugins) {
+		errs = append(errs, validateRepoName(repo, true))
+		seen := map[string]bool{}
+		for _, plugin := range pluginConfig.Plugins[repo] {
+			if plugin == "" {
+				errs = append(errs, fmt.Errorf("blank plugin name for %s", repo))
+			} else if seen[plugin] {
+				errs = append(errs, fmt.Errorf("plugin %s is listed more than once for %s", plugin, repo))
+			}
+			seen[plugin] = true
+		}
+		// a plugin enabled for an org cannot be enabled again for one of its repositories
+		parts := strings.Split(repo, "/")
+		if len(parts) == 2 {
+			for _, plugin := range pluginConfig.Plugins[parts[0]] {
+				if seen[plugin] {
+					errs = append(errs, fmt.Errorf("plugin %s is enabled for both %s and %s", plugin, parts[0], repo))
+				}
+			}
+		}
+	}
+	for _, repo := range sortedKeys(pluginConfig.ExternalPlugins) {
+		errs = append(errs, validateRepoName(repo, true))
+		for _, plugin := range pluginConfig.ExternalPlugins[repo] {
+			if plugin.Name == "" {
+				errs = append(errs, fmt.Errorf("external plugin of %s has no name", repo))
+			}
+		}
+	}
+	for _, a := range pluginConfig.Approve {
+		errs = append(errs, validateRepoNames("approve", a.Repos))
+	}
+	for _, l := range pluginConfig.Lgtm {
+		errs = append(errs, validateRepoNames("lgtm", l.Repos))
+	}
+	for _, t := range pluginConfig.Triggers {
+		errs = append(errs, validateRepoNames("triggers", t.Repos))
+	}
+	for _, file := range sortedKeys(pluginConfig.ConfigUpdater.Maps) {
+		if pluginConfig.ConfigUpdater.Maps[file].Name == "" {
+			errs = append(errs, fmt.Errorf("config_updater has no ConfigMap name for %s", file))
+		}
+	}
+	return util.CombineErrors(errs...)
+}
+
+func validateRepoNames(section string, repos []string) error {
+	errs := []error{}
+	for _, repo := range repos {
+		err := validateRepoName(repo, true)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", section, err))
+		}
+	}
+	return util.CombineErrors(errs...)
+}
+
+// validateRepoName checks the name is of the form 'owner/name' or just 'owner' if orgs are allowed
+func validateRepoName(repo string, allowOrg bool) error {
+	parts := strings.Split(repo, "/")
+	if allowOrg && len(parts) == 1 && parts[0] != "" {
+		return nil
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("%q is not of the form owner/name", repo)
+	}
+	return nil
+}
+
+// sortedKeys returns the sorted keys of a map with string keys
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,149 @@
+package quickstarts
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// CatalogConfigMapKey the key of the catalog JSON in the quickstart catalog ConfigMap
+	CatalogConfigMapKey = "catalog.json"
+)
+
+// QuickstartCatalog an index of the quickstarts in the quickstart locations of a team so that they can be found
+// without querying every git provider
+type QuickstartCatalog struct {
+	Created     time.Time     `json:"created"`
+	Quickstarts []*Quickstart `json:"quickstarts"`
+}
+
+// NewQuickstartCatalog creates a catalog of the quickstarts in the model
+func NewQuickstartCatalog(model *QuickstartModel) *QuickstartCatalog {
+	catalog := &QuickstartCatalog{
+		Created: time.Now().UTC(),
+	}
+	for _, q := range model.Quickstarts {
+		catalog.Quickstarts = append(catalog.Quickstarts, q)
+	}
+	sort.Slice(catalog.Quickstarts, func(i, j int) bool {
+		return catalog.Quickstarts[i].ID < catalog.Quickstarts[j].ID
+	})
+	return catalog
+}
+
+// IsStale returns true if the catalog is older than the maximum age. A zero maximum age never expires
+func (c *QuickstartCatalog) IsStale(maxAge time.Duration) bool {
+	return maxAge > 0 && time.Since(c.Created) > maxAge
+}
+
+// LocationQuickstarts returns the quickstarts in the catalog for the owner on the git server
+func (c *QuickstartCatalog) LocationQuickstarts(gitServerURL string, owner string) []*Quickstart {
+	answer := []*Quickstart{}
+	serverURL := strings.TrimSuffix(gitServerURL, "/")
+	for _, q := range c.Quickstarts {
+		if q.Owner == owner && strings.TrimSuffix(q.GitServerURL, "/") == serverURL {
+			answer = append(answer, q)
+		}
+	}
+	return answer
+}
+
+// LoadQuickstartCatalogFile loads the catalog from a JSON file
+func LoadQuickstartCatalogFile(fileName string) (*QuickstartCatalog, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load file %s", fileName)
+	}
+	return parseCatalog(data, fileName)
+}
+
+// SaveFile saves the catalog as a JSON file
+func (c *QuickstartCatalog) SaveFile(fileName string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the quickstart catalog")
+	}
+	return ioutil.WriteFile(fileName, data, util.DefaultWritePermissions)
+}
+
+// LoadQuickstartCatalogConfigMap loads the catalog from the ConfigMap in the namespace returning nil if there is none
+func LoadQuickstartCatalogConfigMap(kubeClient kubernetes.Interface, ns string) (*QuickstartCatalog, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(kube.ConfigMapQuickstartCatalog, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to load ConfigMap %s in namespace %s", kube.ConfigMapQuickstartCatalog, ns)
+	}
+	text := cm.Data[CatalogConfigMapKey]
+	if text == "" {
+		return nil, nil
+	}
+	return parseCatalog([]byte(text), "ConfigMap "+kube.ConfigMapQuickstartCatalog)
+}
+
+// SaveConfigMap saves the catalog in the ConfigMap in the namespace
+func (c *QuickstartCatalog) SaveConfigMap(kubeClient kubernetes.Interface, ns string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the quickstart catalog")
+	}
+	configMaps := kubeClient.CoreV1().ConfigMaps(ns)
+	cm, err := configMaps.Get(kube.ConfigMapQuickstartCatalog, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to load ConfigMap %s in namespace %s", kube.ConfigMapQuickstartCatalog, ns)
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: kube.ConfigMapQuickstartCatalog,
+			},
+			Data: map[string]string{
+				CatalogConfigMapKey: string(data),
+			},
+		}
+		_, err = configMaps.Create(cm)
+	} else {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[CatalogConfigMapKey] = string(data)
+		_, err = configMaps.Update(cm)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to save ConfigMap %s in namespace %s", kube.ConfigMapQuickstartCatalog, ns)
+	}
+	return nil
+}
+
+func parseCatalog(data []byte, source string) (*QuickstartCatalog, error) {
+	catalog := &QuickstartCatalog{}
+	err := json.Unmarshal(data, catalog)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the quickstart catalog in %s", source)
+	}
+	return catalog, nil
+}
+
+// SortByPopularity sorts the quickstarts with the most stars first then by name
+func SortByPopularity(quickstarts []*Quickstart) {
+	sort.SliceStable(quickstarts, func(i, j int) bool {
+		q1 := quickstarts[i]
+		q2 := quickstarts[j]
+		if q1.Stars != q2.Stars {
+			return q1.Stars > q2.Stars
+		}
+		return q1.ID < q2.ID
+	})
+}
@@ -0,0 +1,131 @@
+package quickstarts_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/quickstarts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func createTestQuickstartProvider() *gits.FakeProvider {
+	provider := gits.NewFakeProvider(
+		&gits.FakeRepository{
+			Owner: "acme-quickstarts",
+			GitRepo: &gits.GitRepository{
+				Name:        "spring-boot-rest",
+				Language:    "Java",
+				Stars:       12,
+				Description: "A Spring Boot REST service",
+				Topics:      []string{"spring", "rest"},
+			},
+		},
+		&gits.FakeRepository{
+			Owner: "acme-quickstarts",
+			GitRepo: &gits.GitRepository{
+				Name:     "golang-http",
+				Language: "Go",
+				Stars:    30,
+				Topics:   []string{"http"},
+			},
+		},
+		&gits.FakeRepository{
+			Owner: "acme-quickstarts",
+			GitRepo: &gits.GitRepository{
+				Name:     "WIP-rust",
+				Language: "Rust",
+			},
+		},
+	)
+	provider.Type = gits.Gitlab
+	provider.Server = auth.AuthServer{URL: "https://gitlab.acme.com"}
+	return provider
+}
+
+func TestQuickstartCatalog(t *testing.T) {
+	t.Parallel()
+	provider := createTestQuickstartProvider()
+
+	model := quickstarts.NewQuickstartModel()
+	err := model.LoadGitQuickstarts(provider, "acme-quickstarts", []string{"*"}, []string{"WIP-*"})
+	require.NoError(t, err)
+	catalog := quickstarts.NewQuickstartCatalog(model)
+	require.Len(t, catalog.Quickstarts, 2)
+	q := catalog.Quickstarts[1]
+	assert.Equal(t, "acme-quickstarts/spring-boot-rest", q.ID)
+	assert.Equal(t, "https://gitlab.acme.com", q.GitServerURL)
+	assert.Equal(t, gits.KindGitlab, q.GitKind)
+	assert.Equal(t, []string{"spring", "rest"}, q.Tags)
+
+	// the git provider is not saved in the catalog
+	expected := []*quickstarts.Quickstart{}
+	for _, q := range catalog.Quickstarts {
+		entry := *q
+		entry.GitProvider = nil
+		expected = append(expected, &entry)
+	}
+
+	dir, err := ioutil.TempDir("", "test-quickstart-catalog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "catalog.json")
+	require.NoError(t, catalog.SaveFile(fileName))
+	loaded, err := quickstarts.LoadQuickstartCatalogFile(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, expected, loaded.Quickstarts)
+
+	kubeClient := fake.NewSimpleClientset()
+	missing, err := quickstarts.LoadQuickstartCatalogConfigMap(kubeClient, "jx")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+	require.NoError(t, catalog.SaveConfigMap(kubeClient, "jx"))
+	require.NoError(t, catalog.SaveConfigMap(kubeClient, "jx"))
+	loaded, err = quickstarts.LoadQuickstartCatalogConfigMap(kubeClient, "jx")
+	require.NoError(t, err)
+	require.NotNil(t, loaded)
+	assert.Equal(t, expected, loaded.Quickstarts)
+	assert.False(t, loaded.IsStale(time.Hour))
+	assert.False(t, loaded.IsStale(0))
+	loaded.Created = time.Now().Add(-2 * time.Hour)
+	assert.True(t, loaded.IsStale(time.Hour))
+
+	// the catalog is used without listing the repositories of the provider
+	provider.Repositories = map[string][]*gits.FakeRepository{}
+	cached := quickstarts.NewQuickstartModel()
+	assert.True(t, cached.LoadCatalogQuickstarts(loaded, provider, "acme-quickstarts", []string{"*"}, []string{"golang-*"}))
+	assert.False(t, cached.LoadCatalogQuickstarts(loaded, provider, "another-owner", []string{"*"}, nil))
+	require.Len(t, cached.Quickstarts, 1)
+	q = cached.Quickstarts["acme-quickstarts/spring-boot-rest"]
+	require.NotNil(t, q)
+	assert.Equal(t, provider, q.GitProvider)
+	assert.Equal(t, 12, q.Stars)
+}
+
+func TestQuickstartModelSearch(t *testing.T) {
+	t.Parallel()
+	model := quickstarts.NewQuickstartModel()
+	err := model.LoadGitQuickstarts(createTestQuickstartProvider(), "acme-quickstarts", []string{"*"}, nil)
+	require.NoError(t, err)
+
+	search := func(filter *quickstarts.QuickstartFilter) []string {
+		results := model.Filter(filter)
+		quickstarts.SortByPopularity(results)
+		names := []string{}
+		for _, q := range results {
+			names = append(names, q.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"golang-http", "spring-boot-rest"}, search(&quickstarts.QuickstartFilter{}))
+	assert.Equal(t, []string{"spring-boot-rest"}, search(&quickstarts.QuickstartFilter{Text: "REST service"}))
+	assert.Equal(t, []string{"spring-boot-rest"}, search(&quickstarts.QuickstartFilter{Text: "java"}))
+	assert.Equal(t, []string{"golang-http"}, search(&quickstarts.QuickstartFilter{Tags: []string{"HTTP"}}))
+	assert.Equal(t, []string{}, search(&quickstarts.QuickstartFilter{Text: "rest", Tags: []string{"http"}}))
+}
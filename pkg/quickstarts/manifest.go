@@ -0,0 +1,461 @@
+package quickstarts
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/blang/semver"
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"gopkg.in/AlecAivazis/survey.v1"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const (
+	// ManifestFileName the name of the file in the root of a quickstart which describes it and its parameters
+	ManifestFileName = "quickstart.yaml"
+
+	// ParameterTypeString a free text parameter
+	ParameterTypeString = "string"
+	// ParameterTypeInt a whole number parameter
+	ParameterTypeInt = "int"
+	// ParameterTypeBool a true or false parameter
+	ParameterTypeBool = "bool"
+	// ParameterTypeEnum a parameter whose value is one of the options
+	ParameterTypeEnum = "enum"
+)
+
+// QuickstartManifest describes a quickstart, the parameters used to generate a project from it and how its files
+// are rendered
+type QuickstartManifest struct {
+	Name         string   `json:"name,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Language     string   `json:"language,omitempty"`
+	Framework    string   `json:"framework,omitempty"`
+	MinJxVersion string   `json:"minJxVersion,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	// Parameters the parameters asked for when creating a project
+	Parameters []QuickstartParameter `json:"parameters,omitempty"`
+	// Templates the glob patterns of the files which are rendered as go templates
+	Templates []string `json:"templates,omitempty"`
+	// Files the files or directories which are only included if their condition is true
+	Files []QuickstartFile `json:"files,omitempty"`
+}
+
+// QuickstartParameter a typed parameter of a quickstart
+type QuickstartParameter struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Options     []string `json:"options,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	// Pattern the regular expression string values must match
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// QuickstartFile a file or directory of a quickstart which is only included in the generated project if the
+// condition template renders as true
+type QuickstartFile struct {
+	Path      string `json:"path"`
+	Condition string `json:"if"`
+}
+
+// LoadQuickstartManifest loads the manifest in the given quickstart directory returning nil if there is none
+func LoadQuickstartManifest(dir string) (*QuickstartManifest, error) {
+	fileName := filepath.Join(dir, ManifestFileName)
+	exists, err := util.FileExists(fileName)
+	if err != nil || !exists {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load file %s", fileName)
+	}
+	manifest := &QuickstartManifest{}
+	err = yaml.Unmarshal(data, manifest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal YAML file %s", fileName)
+	}
+	return manifest, manifest.Validate()
+}
+
+// Apply applies the metadata of the manifest to the quickstart so that it can be found by its language, framework
+// and tags. The quickstart keeps the name of its repository as the name of the manifest is only used in messages
+func (m *QuickstartManifest) Apply(q *Quickstart) {
+	if m.Language != "" {
+		q.Language = m.Language
+	}
+	if m.Framework != "" {
+		q.Framework = m.Framework
+	}
+	if m.Description != "" {
+		q.Description = m.Description
+	}
+	for _, tag := range m.Tags {
+		if util.StringArrayIndex(q.Tags, tag) < 0 {
+			q.Tags = append(q.Tags, tag)
+		}
+	}
+	if m.Name == "" {
+		m.Name = q.Name
+	}
+}
+
+// Validate returns an error if the manifest is invalid
+func (m *QuickstartManifest) Validate() error {
+	if m.MinJxVersion != "" {
+		_, err := semver.ParseTolerant(m.MinJxVersion)
+		if err != nil {
+			return errors.Wrapf(err, "invalid minJxVersion %s", m.MinJxVersion)
+		}
+	}
+	names := map[string]bool{}
+	for _, p := range m.Parameters {
+		if p.Name == "" {
+			return fmt.Errorf("quickstart parameter has no name")
+		}
+		if names[p.Name] {
+			return fmt.Errorf("duplicate quickstart parameter %s", p.Name)
+		}
+		names[p.Name] = true
+		switch p.Type {
+		case "", ParameterTypeString, ParameterTypeInt, ParameterTypeBool:
+		case ParameterTypeEnum:
+			if len(p.Options) == 0 {
+				return fmt.Errorf("quickstart parameter %s of type enum has no options", p.Name)
+			}
+		default:
+			return util.InvalidOption("type", p.Type, []string{ParameterTypeString, ParameterTypeInt, ParameterTypeBool, ParameterTypeEnum})
+		}
+		if p.Pattern != "" {
+			_, err := regexp.Compile(p.Pattern)
+			if err != nil {
+				return errors.Wrapf(err, "invalid pattern for quickstart parameter %s", p.Name)
+			}
+		}
+		if p.Default != "" {
+			err := p.Validate(p.Default)
+			if err != nil {
+				return errors.Wrap(err, "invalid default value")
+			}
+		}
+	}
+	for _, f := range m.Files {
+		if f.Path == "" {
+			return fmt.Errorf("quickstart file has no path")
+		}
+		if !isRelativeQuickstartPath(f.Path) {
+			return fmt.Errorf("quickstart file %s must be a relative path inside the quickstart", f.Path)
+		}
+		_, err := template.New(f.Path).Parse(f.Condition)
+		if err != nil {
+			return errors.Wrapf(err, "invalid condition for quickstart file %s", f.Path)
+		}
+	}
+	return nil
+}
+
+// CheckVersion returns an error if the quickstart requires a newer version of jx than the given version
+func (m *QuickstartManifest) CheckVersion(current semver.Version) error {
+	if m.MinJxVersion == "" {
+		return nil
+	}
+	min, err := semver.ParseTolerant(m.MinJxVersion)
+	if err != nil {
+		return errors.Wrapf(err, "invalid minJxVersion %s", m.MinJxVersion)
+	}
+	if current.LT(min) {
+		return fmt.Errorf("quickstart %s requires jx version %s or later but you are using %s. Please upgrade via: jx upgrade cli", m.Name, min.String(), current.String())
+	}
+	return nil
+}
+
+// Validate returns an error if the value is not valid for this parameter
+func (p *QuickstartParameter) Validate(value string) error {
+	if value == "" {
+		if p.Required {
+			return fmt.Errorf("no value for required quickstart parameter %s", p.Name)
+		}
+		return nil
+	}
+	switch p.Type {
+	case ParameterTypeInt:
+		_, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("value %s of quickstart parameter %s is not a whole number", value, p.Name)
+		}
+	case ParameterTypeBool:
+		_, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("value %s of quickstart parameter %s is not true or false", value, p.Name)
+		}
+	case ParameterTypeEnum:
+		if util.StringArrayIndex(p.Options, value) < 0 {
+			return util.InvalidOption(p.Name, value, p.Options)
+		}
+	}
+	if p.Pattern != "" {
+		matched, err := regexp.MatchString(p.Pattern, value)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			return fmt.Errorf("value %s of quickstart parameter %s does not match the pattern %s", value, p.Name, p.Pattern)
+		}
+	}
+	return nil
+}
+
+// ParseParameterValues parses the key=value expressions of the --set options
+func ParseParameterValues(expressions []string) (map[string]string, error) {
+	answer := map[string]string{}
+	for _, expression := range expressions {
+		idx := strings.Index(expression, "=")
+		if idx <= 0 {
+			return answer, fmt.Errorf("invalid quickstart parameter %s. Expected the format name=value", expression)
+		}
+		answer[strings.TrimSpace(expression[0:idx])] = expression[idx+1:]
+	}
+	return answer, nil
+}
+
+// SurveyParameters returns the value of each parameter taken from the given values or asked for if not in batch mode
+func (m *QuickstartManifest) SurveyParameters(values map[string]string, batchMode bool, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) (map[string]string, error) {
+	surveyOpts := survey.WithStdio(in, out, errOut)
+	answer := map[string]string{}
+	for name := range values {
+		if m.Parameter(name) == nil {
+			return nil, util.InvalidOption("set", name, m.ParameterNames())
+		}
+	}
+	for i := range m.Parameters {
+		p := &m.Parameters[i]
+		value, ok := values[p.Name]
+		if !ok {
+			value = p.Default
+			if !batchMode {
+				var err error
+				value, err = p.ask(value, surveyOpts)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		err := p.Validate(value)
+		if err != nil {
+			return nil, err
+		}
+		answer[p.Name] = value
+	}
+	return answer, nil
+}
+
+func (p *QuickstartParameter) ask(defaultValue string, surveyOpts survey.AskOpt) (string, error) {
+	message := p.Description
+	if message == "" {
+		message = p.Name
+	}
+	answer := defaultValue
+	var err error
+	switch p.Type {
+	case ParameterTypeBool:
+		flag, _ := strconv.ParseBool(defaultValue)
+		prompt := &survey.Confirm{
+			Message: message,
+			Default: flag,
+		}
+		err = survey.AskOne(prompt, &flag, nil, surveyOpts)
+		answer = strconv.FormatBool(flag)
+	case ParameterTypeEnum:
+		prompt := &survey.Select{
+			Message: message,
+			Options: p.Options,
+			Default: defaultValue,
+		}
+		err = survey.AskOne(prompt, &answer, survey.Required, surveyOpts)
+	default:
+		prompt := &survey.Input{
+			Message: message,
+			Default: defaultValue,
+		}
+		validator := func(val interface{}) error {
+			text, _ := val.(string)
+			return p.Validate(text)
+		}
+		err = survey.AskOne(prompt, &answer, validator, surveyOpts)
+	}
+	return answer, err
+}
+
+// Parameter returns the parameter with the given name or nil if there is none
+func (m *QuickstartManifest) Parameter(name string) *QuickstartParameter {
+	for i := range m.Parameters {
+		if m.Parameters[i].Name == name {
+			return &m.Parameters[i]
+		}
+	}
+	return nil
+}
+
+// ParameterNames returns the names of the parameters
+func (m *QuickstartManifest) ParameterNames() []string {
+	answer := []string{}
+	for _, p := range m.Parameters {
+		answer = append(answer, p.Name)
+	}
+	return answer
+}
+
+// TemplateData returns the data available to the templates and conditions of the quickstart files. The parameter
+// values are converted to their types so that bool parameters can be used directly in conditions
+func (m *QuickstartManifest) TemplateData(projectName string, values map[string]string) map[string]interface{} {
+	parameters := map[string]interface{}{}
+	for k, v := range values {
+		parameters[k] = v
+	}
+	for _, p := range m.Parameters {
+		value := values[p.Name]
+		switch p.Type {
+		case ParameterTypeInt:
+			if n, err := strconv.Atoi(value); err == nil {
+				parameters[p.Name] = n
+			}
+		case ParameterTypeBool:
+			flag, _ := strconv.ParseBool(value)
+			parameters[p.Name] = flag
+		}
+	}
+	return map[string]interface{}{
+		"Name":       projectName,
+		"Parameters": parameters,
+	}
+}
+
+// Render removes the files whose condition is false and renders the template files in the given directory. The
+// manifest itself is removed as it is not part of the generated project
+func (m *QuickstartManifest) Render(dir string, data map[string]interface{}) error {
+	for _, f := range m.Files {
+		include, err := renderCondition(f.Path, f.Condition, data)
+		if err != nil {
+			return err
+		}
+		if !include {
+			fileName, err := quickstartFileName(dir, f.Path)
+			if err != nil {
+				return err
+			}
+			err = os.RemoveAll(fileName)
+			if err != nil {
+				return errors.Wrapf(err, "failed to remove quickstart file %s", f.Path)
+			}
+		}
+	}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if !m.isTemplate(filepath.ToSlash(rel)) {
+			return nil
+		}
+		return renderTemplateFile(path, rel, info.Mode(), data)
+	})
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(dir, ManifestFileName))
+}
+
+// isRelativeQuickstartPath returns true if the path is relative and inside the quickstart directory
+func isRelativeQuickstartPath(p string) bool {
+	if path.IsAbs(p) || filepath.IsAbs(p) || filepath.VolumeName(p) != "" {
+		return false
+	}
+	clean := path.Clean(filepath.ToSlash(p))
+	return clean != "." && clean != ".." && !strings.HasPrefix(clean, "../")
+}
+
+// quickstartFileName returns the file name of a path of the quickstart returning an error if it is outside of the
+// quickstart directory
+func quickstartFileName(dir string, p string) (string, error) {
+	if !isRelativeQuickstartPath(p) {
+		return "", fmt.Errorf("quickstart file %s must be a relative path inside the quickstart", p)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	fileName := filepath.Join(absDir, filepath.FromSlash(p))
+	rel, err := filepath.Rel(absDir, fileName)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("quickstart file %s is outside of the quickstart directory %s", p, dir)
+	}
+	return fileName, nil
+}
+
+func (m *QuickstartManifest) isTemplate(path string) bool {
+	for _, pattern := range m.Templates {
+		if matched, _ := filepath.Match(pattern, path); matched {
+			return true
+		}
+		// lets treat a pattern without a directory as matching the file name in any directory
+		if !strings.Contains(pattern, "/") {
+			if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func renderTemplateFile(path string, name string, mode os.FileMode, data map[string]interface{}) error {
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse quickstart template %s", name)
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to render quickstart template %s", name)
+	}
+	return ioutil.WriteFile(path, buffer.Bytes(), mode)
+}
+
+func renderCondition(name string, condition string, data map[string]interface{}) (bool, error) {
+	if strings.TrimSpace(condition) == "" {
+		return true, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(condition)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse the condition of quickstart file %s", name)
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, data)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to evaluate the condition of quickstart file %s", name)
+	}
+	result := strings.TrimSpace(buffer.String())
+	answer, err := strconv.ParseBool(result)
+	if err != nil {
+		return false, fmt.Errorf("the condition of quickstart file %s evaluated to %s rather than true or false", name, result)
+	}
+	return answer, nil
+}
@@ -0,0 +1,198 @@
+package quickstarts_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/jenkins-x/jx/pkg/quickstarts"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `name: spring-boot-rest
+description: a REST service
+language: java
+minJxVersion: 1.3.0
+parameters:
+- name: packageName
+  type: string
+  default: com.example
+  pattern: ^[a-z][a-z0-9_.]*$
+  required: true
+- name: port
+  type: int
+  default: "8080"
+- name: database
+  type: enum
+  options: [none, postgres, mysql]
+  default: none
+- name: metrics
+  type: bool
+templates:
+- "*.java"
+- config/application.yaml
+files:
+- path: db
+  if: '{{ ne .Parameters.database "none" }}'
+- path: config/metrics.yaml
+  if: '{{ .Parameters.metrics }}'
+`
+
+func writeTestFile(t *testing.T, dir string, name string, text string) {
+	fileName := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(fileName), util.DefaultWritePermissions))
+	require.NoError(t, ioutil.WriteFile(fileName, []byte(text), util.DefaultWritePermissions))
+}
+
+func TestQuickstartManifestRender(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-quickstart-manifest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestFile(t, dir, quickstarts.ManifestFileName, testManifest)
+	writeTestFile(t, dir, "src/App.java", "package {{ .Parameters.packageName }};\n")
+	writeTestFile(t, dir, "config/application.yaml", "name: {{ .Name }}\nport: {{ .Parameters.port }}\n")
+	writeTestFile(t, dir, "config/metrics.yaml", "enabled: true\n")
+	writeTestFile(t, dir, "charts/templates/service.yaml", "name: {{ .Values.name }}\n")
+	writeTestFile(t, dir, "db/schema.sql", "create table foo;\n")
+
+	manifest, err := quickstarts.LoadQuickstartManifest(dir)
+	require.NoError(t, err)
+	require.NotNil(t, manifest)
+	assert.Equal(t, []string{"packageName", "port", "database", "metrics"}, manifest.ParameterNames())
+
+	assert.Error(t, manifest.CheckVersion(semver.MustParse("1.2.9")))
+	assert.NoError(t, manifest.CheckVersion(semver.MustParse("1.3.0")))
+
+	values, err := manifest.SurveyParameters(map[string]string{"packageName": "com.acme", "metrics": "true"}, true, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"packageName": "com.acme", "port": "8080", "database": "none", "metrics": "true"}, values)
+
+	err = manifest.Render(dir, manifest.TemplateData("myapp", values))
+	require.NoError(t, err)
+
+	assertFileText(t, dir, "src/App.java", "package com.acme;\n")
+	assertFileText(t, dir, "config/application.yaml", "name: myapp\nport: 8080\n")
+	assertFileText(t, dir, "config/metrics.yaml", "enabled: true\n")
+	assertFileText(t, dir, "charts/templates/service.yaml", "name: {{ .Values.name }}\n")
+	assertFileMissing(t, dir, "db")
+	assertFileMissing(t, dir, quickstarts.ManifestFileName)
+}
+
+func TestQuickstartManifestApply(t *testing.T) {
+	t.Parallel()
+	q := &quickstarts.Quickstart{
+		ID:       "jenkins-x-quickstarts/spring-boot-rest-prometheus",
+		Name:     "spring-boot-rest-prometheus",
+		Language: "Shell",
+		Tags:     []string{"prometheus"},
+	}
+	manifest := &quickstarts.QuickstartManifest{
+		Language:    "java",
+		Framework:   "spring",
+		Description: "a REST service with metrics",
+		Tags:        []string{"rest", "prometheus"},
+	}
+	manifest.Apply(q)
+
+	assert.Equal(t, "spring-boot-rest-prometheus", q.Name)
+	assert.Equal(t, "java", q.Language)
+	assert.Equal(t, "spring", q.Framework)
+	assert.Equal(t, "a REST service with metrics", q.Description)
+	assert.Equal(t, []string{"prometheus", "rest"}, q.Tags)
+	assert.Equal(t, "spring-boot-rest-prometheus", manifest.Name)
+
+	manifest = &quickstarts.QuickstartManifest{Name: "Spring Boot REST"}
+	manifest.Apply(q)
+	assert.Equal(t, "java", q.Language, "the quickstart should keep its metadata if the manifest has none")
+	assert.Equal(t, "Spring Boot REST", manifest.Name)
+}
+
+func TestQuickstartManifestInvalidParameters(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-quickstart-manifest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeTestFile(t, dir, quickstarts.ManifestFileName, testManifest)
+
+	manifest, err := quickstarts.LoadQuickstartManifest(dir)
+	require.NoError(t, err)
+
+	invalid := []map[string]string{
+		{"packageName": "Com.Acme"},
+		{"packageName": ""},
+		{"port": "http"},
+		{"database": "oracle"},
+		{"metrics": "maybe"},
+		{"unknown": "value"},
+	}
+	for _, values := range invalid {
+		_, err = manifest.SurveyParameters(values, true, nil, nil, nil)
+		assert.Error(t, err, "values %#v", values)
+	}
+
+	parsed, err := quickstarts.ParseParameterValues([]string{"packageName=com.acme", "greeting=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"packageName": "com.acme", "greeting": "a=b"}, parsed)
+	_, err = quickstarts.ParseParameterValues([]string{"packageName"})
+	assert.Error(t, err)
+
+	missing, err := quickstarts.LoadQuickstartManifest(filepath.Join(dir, "does-not-exist"))
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestQuickstartManifestPathTraversal(t *testing.T) {
+	t.Parallel()
+	parent, err := ioutil.TempDir("", "test-quickstart-manifest-traversal")
+	require.NoError(t, err)
+	defer os.RemoveAll(parent)
+	dir := filepath.Join(parent, "quickstart")
+	writeTestFile(t, parent, "precious.txt", "keep me\n")
+	writeTestFile(t, dir, "README.md", "hello\n")
+
+	for _, p := range []string{"../precious.txt", "..", ".", "db/../../precious.txt", "/etc/passwd", filepath.Join(parent, "precious.txt")} {
+		manifest := &quickstarts.QuickstartManifest{
+			Files: []quickstarts.QuickstartFile{
+				{
+					Path:      p,
+					Condition: "false",
+				},
+			},
+		}
+		assert.Error(t, manifest.Validate(), "path %s", p)
+		assert.Error(t, manifest.Render(dir, nil), "path %s", p)
+	}
+	assertFileText(t, parent, "precious.txt", "keep me\n")
+	assertFileText(t, dir, "README.md", "hello\n")
+
+	valid := &quickstarts.QuickstartManifest{
+		Files: []quickstarts.QuickstartFile{
+			{
+				Path:      "db/../README.md",
+				Condition: "false",
+			},
+		},
+	}
+	require.NoError(t, valid.Validate())
+	require.NoError(t, valid.Render(dir, nil))
+	assertFileMissing(t, dir, "README.md")
+}
+
+func assertFileText(t *testing.T, dir string, name string, expected string) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected, string(data), "file %s", name)
+	}
+}
+
+func assertFileMissing(t *testing.T, dir string, name string) {
+	exists, err := util.FileExists(filepath.Join(dir, filepath.FromSlash(name)))
+	assert.NoError(t, err)
+	assert.False(t, exists, "file %s should have been removed", name)
+}
@@ -7,7 +7,10 @@ import (
 	"strings"
 
 	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/jenkins-x/jx/pkg/version"
+	"github.com/pkg/errors"
 	"gopkg.in/AlecAivazis/survey.v1"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 )
@@ -28,6 +31,8 @@ func GitQuickstart(provider gits.GitProvider, owner string, repo string, languag
 		Framework:      framework,
 		Tags:           tags,
 		DownloadZipURL: u,
+		GitServerURL:   provider.ServerURL(),
+		GitKind:        provider.Kind(),
 		GitProvider:    provider,
 	}
 }
@@ -36,12 +41,20 @@ func toGitHubQuickstart(provider gits.GitProvider, owner string, repo *gits.GitR
 	language := repo.Language
 	// TODO find this from GitHub???
 	framework := ""
-	tags := []string{}
-	return GitQuickstart(provider, owner, repo.Name, language, framework, tags...)
+	tags := append([]string{}, repo.Topics...)
+	q := GitQuickstart(provider, owner, repo.Name, language, framework, tags...)
+	q.Description = repo.Description
+	q.Stars = repo.Stars
+	return q
 }
 
 // LoadGithubQuickstarts Loads quickstarts from github
 func (model *QuickstartModel) LoadGithubQuickstarts(provider gits.GitProvider, owner string, includes []string, excludes []string) error {
+	return model.LoadGitQuickstarts(provider, owner, includes, excludes)
+}
+
+// LoadGitQuickstarts loads the quickstarts of the owner from any kind of git provider
+func (model *QuickstartModel) LoadGitQuickstarts(provider gits.GitProvider, owner string, includes []string, excludes []string) error {
 	repos, err := provider.ListRepositories(owner)
 	if err != nil {
 		return err
@@ -55,6 +68,26 @@ func (model *QuickstartModel) LoadGithubQuickstarts(provider gits.GitProvider, o
 	return nil
 }
 
+// LoadCatalogQuickstarts adds the quickstarts of the owner from the catalog using the git provider to download them.
+// Returns false if the catalog has no quickstarts for the owner
+func (model *QuickstartModel) LoadCatalogQuickstarts(catalog *QuickstartCatalog, provider gits.GitProvider, owner string, includes []string, excludes []string) bool {
+	entries := catalog.LocationQuickstarts(provider.ServerURL(), owner)
+	if len(entries) == 0 {
+		return false
+	}
+	for _, entry := range entries {
+		if util.StringMatchesAny(entry.Name, includes, excludes) {
+			q := *entry
+			q.GitProvider = provider
+			if q.DownloadZipURL == "" {
+				q.DownloadZipURL = provider.BranchArchiveURL(q.Owner, q.Name, "master")
+			}
+			model.Add(&q)
+		}
+	}
+	return true
+}
+
 // NewQuickstartModel creates a new quickstart model
 func NewQuickstartModel() *QuickstartModel {
 	return &QuickstartModel{
@@ -142,9 +175,47 @@ func (model *QuickstartModel) CreateSurvey(filter *QuickstartFilter, batchMode b
 		Quickstart: q,
 		Name:       name,
 	}
+	err := model.surveyParameters(form, filter, batchMode, in, out, errOut)
+	if err != nil {
+		return nil, err
+	}
 	return form, nil
 }
 
+// surveyParameters loads the manifest of the chosen quickstart and asks for the values of its parameters
+func (model *QuickstartModel) surveyParameters(form *QuickstartForm, filter *QuickstartFilter, batchMode bool, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) error {
+	q := form.Quickstart
+	if q.Manifest == nil && model.LoadManifest != nil {
+		manifest, err := model.LoadManifest(q)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load the manifest of quickstart %s", q.ID)
+		}
+		if manifest != nil {
+			manifest.Apply(q)
+		}
+		q.Manifest = manifest
+	}
+	manifest := q.Manifest
+	if manifest == nil {
+		if len(filter.Parameters) > 0 {
+			return fmt.Errorf("quickstart %s has no parameters", q.ID)
+		}
+		return nil
+	}
+	current, err := version.GetSemverVersion()
+	if err == nil {
+		err = manifest.CheckVersion(current)
+		if err != nil {
+			return err
+		}
+	}
+	if manifest.Description != "" && !batchMode {
+		log.Infof("%s\n", manifest.Description)
+	}
+	form.Parameters, err = manifest.SurveyParameters(filter.Parameters, batchMode, in, out, errOut)
+	return err
+}
+
 // Filter filters all the available quickstarts with the filter and return the matches
 func (model *QuickstartModel) Filter(filter *QuickstartFilter) []*Quickstart {
 	answer := []*Quickstart{}
@@ -16,9 +16,15 @@ func (f *QuickstartFilter) Matches(q *Quickstart) bool {
 	if strings.Contains(q.ID, "WIP-") {
 		return false
 	}
-	text := f.Text
-	if text != "" && !strings.Contains(q.ID, text) {
-		return false
+	for _, term := range strings.Fields(strings.ToLower(f.Text)) {
+		if !q.matchesText(term) {
+			return false
+		}
+	}
+	for _, tag := range f.Tags {
+		if !q.hasTag(tag) {
+			return false
+		}
 	}
 	owner := strings.ToLower(f.Owner)
 	if owner != "" && strings.ToLower(q.Owner) != owner {
@@ -34,3 +40,23 @@ func (f *QuickstartFilter) Matches(q *Quickstart) bool {
 	}
 	return true
 }
+
+// matchesText returns true if the lower case search term is in the ID, description, language, framework or tags
+func (q *Quickstart) matchesText(term string) bool {
+	fields := append([]string{q.ID, q.Description, q.Language, q.Framework}, q.Tags...)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), term) {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *Quickstart) hasTag(tag string) bool {
+	for _, t := range q.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,18 +3,27 @@ package quickstarts
 import "github.com/jenkins-x/jx/pkg/gits"
 
 type Quickstart struct {
-	ID             string
-	Owner          string
-	Name           string
-	Language       string
-	Framework      string
-	Tags           []string
-	DownloadZipURL string
-	GitProvider    gits.GitProvider
+	ID             string   `json:"id"`
+	Owner          string   `json:"owner"`
+	Name           string   `json:"name"`
+	Language       string   `json:"language,omitempty"`
+	Framework      string   `json:"framework,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Stars          int      `json:"stars,omitempty"`
+	DownloadZipURL string   `json:"downloadZipURL,omitempty"`
+	GitServerURL   string   `json:"gitServerURL,omitempty"`
+	GitKind        string   `json:"gitKind,omitempty"`
+
+	GitProvider gits.GitProvider    `json:"-"`
+	Manifest    *QuickstartManifest `json:"-"`
 }
 
 type QuickstartModel struct {
 	Quickstarts map[string]*Quickstart
+
+	// LoadManifest if specified loads the manifest of the chosen quickstart so its parameters can be asked for
+	LoadManifest func(q *Quickstart) (*QuickstartManifest, error)
 }
 
 type QuickstartFilter struct {
@@ -24,9 +33,12 @@ type QuickstartFilter struct {
 	Text        string
 	ProjectName string
 	Tags        []string
+	// Parameters the values of the quickstart parameters specified on the command line
+	Parameters map[string]string
 }
 
 type QuickstartForm struct {
 	Quickstart *Quickstart
 	Name       string
+	Parameters map[string]string
 }
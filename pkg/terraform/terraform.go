@@ -10,9 +10,18 @@ import (
 	"io"
 )
 
+const (
+	// LockTimeout how long to wait for the lock of the Terraform state held by another plan or apply
+	LockTimeout = "5m"
+)
+
+// Init initialises the Terraform plan in the directory. The service account is only used by GKE plans so it is
+// ignored when blank
 func Init(terraformDir string, serviceAccountPath string) error {
 	fmt.Println("Initialising Terraform")
-	os.Setenv("GOOGLE_CREDENTIALS", serviceAccountPath)
+	if serviceAccountPath != "" {
+		os.Setenv("GOOGLE_CREDENTIALS", serviceAccountPath)
+	}
 	cmd := util.Command{
 		Name: "terraform",
 		Args: []string{"init", terraformDir},
@@ -24,15 +33,13 @@ func Init(terraformDir string, serviceAccountPath string) error {
 	return nil
 }
 
-func Plan(terraformDir string, terraformVars string, serviceAccountPath string) (string, error) {
+// Plan shows the Terraform plan for the directory. If the plan file is not blank the plan is saved to it so that
+// exactly the plan which was shown can be applied with ApplyPlan
+func Plan(terraformDir string, terraformVars string, serviceAccountPath string, planFile string) (string, error) {
 	fmt.Println("Showing Terraform Plan")
 	cmd := util.Command{
 		Name: "terraform",
-		Args: []string{"plan",
-			fmt.Sprintf("-var-file=%s", terraformVars),
-			"-var",
-			fmt.Sprintf("credentials=%s", serviceAccountPath),
-			terraformDir},
+		Args: planArgs(outArgs([]string{"plan"}, planFile), terraformDir, terraformVars, serviceAccountPath),
 	}
 	out, err := cmd.RunWithoutRetry()
 	if err != nil {
@@ -41,17 +48,33 @@ func Plan(terraformDir string, terraformVars string, serviceAccountPath string)
 	return out, nil
 }
 
+// PlanWithoutColor returns the Terraform plan for the directory without colours so that it can be shown in a pull
+// request comment. If the plan file is not blank the plan is saved to it
+func PlanWithoutColor(terraformDir string, terraformVars string, serviceAccountPath string, planFile string) (string, error) {
+	cmd := util.Command{
+		Name: "terraform",
+		Args: planArgs(outArgs([]string{"plan", "-no-color", "-input=false"}, planFile), terraformDir, terraformVars, serviceAccountPath),
+	}
+	return cmd.RunWithoutRetry()
+}
+
+// ShowPlan returns the changes of a saved Terraform plan without colours
+func ShowPlan(planFile string) (string, error) {
+	cmd := util.Command{
+		Name: "terraform",
+		Args: []string{"show", "-no-color", planFile},
+	}
+	return cmd.RunWithoutRetry()
+}
+
+// Apply applies the Terraform plan for the directory
 func Apply(terraformDir string, terraformVars string, serviceAccountPath string, stdout io.Writer, stderr io.Writer) error {
 	fmt.Println("Applying Terraform")
 	cmd := util.Command{
 		Name: "terraform",
-		Args: []string{"apply", "-auto-approve",
-			fmt.Sprintf("-var-file=%s", terraformVars),
-			"-var",
-			fmt.Sprintf("credentials=%s", serviceAccountPath),
-			terraformDir},
-		Out: stdout,
-		Err: stderr,
+		Args: planArgs([]string{"apply", "-auto-approve"}, terraformDir, terraformVars, serviceAccountPath),
+		Out:  stdout,
+		Err:  stderr,
 	}
 	_, err := cmd.RunWithoutRetry()
 	if err != nil {
@@ -60,6 +83,35 @@ func Apply(terraformDir string, terraformVars string, serviceAccountPath string,
 	return nil
 }
 
+// ApplyPlan applies a saved Terraform plan. Terraform refuses to apply the plan if the state has changed since it was
+// created
+func ApplyPlan(planFile string, stdout io.Writer, stderr io.Writer) error {
+	fmt.Println("Applying Terraform")
+	cmd := util.Command{
+		Name: "terraform",
+		Args: []string{"apply", fmt.Sprintf("-lock-timeout=%s", LockTimeout), "-input=false", planFile},
+		Out:  stdout,
+		Err:  stderr,
+	}
+	_, err := cmd.RunWithoutRetry()
+	return err
+}
+
+func outArgs(args []string, planFile string) []string {
+	if planFile != "" {
+		args = append(args, fmt.Sprintf("-out=%s", planFile))
+	}
+	return args
+}
+
+func planArgs(args []string, terraformDir string, terraformVars string, serviceAccountPath string) []string {
+	args = append(args, fmt.Sprintf("-lock-timeout=%s", LockTimeout), fmt.Sprintf("-var-file=%s", terraformVars))
+	if serviceAccountPath != "" {
+		args = append(args, "-var", fmt.Sprintf("credentials=%s", serviceAccountPath))
+	}
+	return append(args, terraformDir)
+}
+
 func WriteKeyValueToFileIfNotExists(path string, key string, value string) error {
 	// file exists
 	if _, err := os.Stat(path); err == nil {
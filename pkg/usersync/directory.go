@@ -0,0 +1,42 @@
+package usersync
+
+import (
+	"sort"
+	"strings"
+)
+
+// DirectoryUser a user of an identity directory along with the names of the groups they are a member of
+type DirectoryUser struct {
+	Login  string
+	Name   string
+	Email  string
+	Groups []string
+}
+
+// Directory an identity directory such as an LDAP server or a SCIM endpoint which users are imported from
+type Directory interface {
+	// Name returns the name of the directory which is used to label the users imported from it
+	Name() string
+
+	// ListUsers returns the users of the directory along with their group memberships
+	ListUsers() ([]*DirectoryUser, error)
+}
+
+// addGroup adds the group to the user if they are not already a member of it
+func (u *DirectoryUser) addGroup(group string) {
+	for _, g := range u.Groups {
+		if strings.EqualFold(g, group) {
+			return
+		}
+	}
+	u.Groups = append(u.Groups, group)
+}
+
+func sortUsers(users []*DirectoryUser) {
+	for _, user := range users {
+		sort.Strings(user.Groups)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Login < users[j].Login
+	})
+}
@@ -0,0 +1,288 @@
+package usersync
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/ldap.v2"
+)
+
+const (
+	// DefaultLDAPUserFilter the default filter used to find the users of an LDAP directory
+	DefaultLDAPUserFilter = "(|(objectClass=inetOrgPerson)(objectClass=person)(objectClass=user))"
+
+	// DefaultLDAPGroupFilter the default filter used to find the groups of an LDAP directory
+	DefaultLDAPGroupFilter = "(|(objectClass=groupOfNames)(objectClass=groupOfUniqueNames)(objectClass=posixGroup)(objectClass=group))"
+
+	// DefaultLDAPLoginAttribute the default attribute holding the login of a user
+	DefaultLDAPLoginAttribute = "uid"
+
+	// DefaultLDAPNameAttribute the default attribute holding the display name of a user
+	DefaultLDAPNameAttribute = "cn"
+
+	// DefaultLDAPEmailAttribute the default attribute holding the email address of a user
+	DefaultLDAPEmailAttribute = "mail"
+
+	// DefaultLDAPGroupNameAttribute the default attribute holding the name of a group
+	DefaultLDAPGroupNameAttribute = "cn"
+
+	// DefaultLDAPPageSize the default number of entries requested in each page of search results
+	DefaultLDAPPageSize = 500
+
+	// DefaultLDAPTimeout the default timeout of each LDAP operation
+	DefaultLDAPTimeout = 30 * time.Second
+)
+
+// the attributes used to find the members of a group. member and uniqueMember hold the DNs of the members and
+// memberUid the logins of the members of a posixGroup
+var ldapGroupMemberAttributes = []string{"member", "uniqueMember", "memberUid"}
+
+// LDAPDirectory imports users and their groups from an LDAP server such as OpenLDAP or Active Directory
+type LDAPDirectory struct {
+	URL                string
+	BindDN             string
+	BindPassword       string
+	StartTLS           bool
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+	PageSize           int
+
+	UserBaseDN     string
+	UserFilter     string
+	LoginAttribute string
+	NameAttribute  string
+	EmailAttribute string
+
+	GroupBaseDN        string
+	GroupFilter        string
+	GroupNameAttribute string
+}
+
+// ldapEntry an entry returned by a search with the attribute names in lower case
+type ldapEntry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+func (e *ldapEntry) get(attribute string) string {
+	values := e.Attributes[strings.ToLower(attribute)]
+	if len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Name returns the host of the LDAP server
+func (d *LDAPDirectory) Name() string {
+	u, err := url.Parse(d.URL)
+	if err != nil || u.Hostname() == "" {
+		return "ldap"
+	}
+	return u.Hostname()
+}
+
+// ListUsers returns the users of the directory and the names of the groups they are a member of
+func (d *LDAPDirectory) ListUsers() ([]*DirectoryUser, error) {
+	if d.UserBaseDN == "" {
+		return nil, fmt.Errorf("no base DN specified for the users of LDAP server %s", d.URL)
+	}
+	conn, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	loginAttribute := defaultString(d.LoginAttribute, DefaultLDAPLoginAttribute)
+	nameAttribute := defaultString(d.NameAttribute, DefaultLDAPNameAttribute)
+	emailAttribute := defaultString(d.EmailAttribute, DefaultLDAPEmailAttribute)
+	groupNameAttribute := defaultString(d.GroupNameAttribute, DefaultLDAPGroupNameAttribute)
+
+	userEntries, err := d.search(conn, d.UserBaseDN, defaultString(d.UserFilter, DefaultLDAPUserFilter),
+		[]string{loginAttribute, nameAttribute, emailAttribute, "memberOf"})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to search for users in %s", d.UserBaseDN)
+	}
+	groupEntries, err := d.search(conn, defaultString(d.GroupBaseDN, d.UserBaseDN), defaultString(d.GroupFilter, DefaultLDAPGroupFilter),
+		append([]string{groupNameAttribute}, ldapGroupMemberAttributes...))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to search for groups in %s", defaultString(d.GroupBaseDN, d.UserBaseDN))
+	}
+
+	users := []*DirectoryUser{}
+	usersByDN := map[string]*DirectoryUser{}
+	usersByLogin := map[string]*DirectoryUser{}
+	for _, entry := range userEntries {
+		login := entry.get(loginAttribute)
+		if login == "" {
+			continue
+		}
+		user := &DirectoryUser{
+			Login: login,
+			Name:  defaultString(entry.get(nameAttribute), login),
+			Email: entry.get(emailAttribute),
+		}
+		users = append(users, user)
+		usersByDN[normalizeDN(entry.DN)] = user
+		usersByLogin[strings.ToLower(login)] = user
+	}
+
+	groupNames := map[string]string{}
+	for _, entry := range groupEntries {
+		group := defaultString(entry.get(groupNameAttribute), rdnValue(entry.DN))
+		groupNames[normalizeDN(entry.DN)] = group
+		for _, attribute := range ldapGroupMemberAttributes {
+			for _, member := range entry.Attributes[strings.ToLower(attribute)] {
+				user := usersByDN[normalizeDN(member)]
+				if attribute == "memberUid" {
+					user = usersByLogin[strings.ToLower(member)]
+				}
+				if user != nil {
+					user.addGroup(group)
+				}
+			}
+		}
+	}
+
+	// Active Directory and OpenLDAP with the memberof overlay list the groups on the users too
+	for _, entry := range userEntries {
+		user := usersByDN[normalizeDN(entry.DN)]
+		if user == nil {
+			continue
+		}
+		for _, groupDN := range entry.Attributes["memberof"] {
+			group := groupNames[normalizeDN(groupDN)]
+			if group == "" {
+				group = rdnValue(groupDN)
+			}
+			if group != "" {
+				user.addGroup(group)
+			}
+		}
+	}
+	sortUsers(users)
+	return users, nil
+}
+
+func (d *LDAPDirectory) pageSize() int {
+	if d.PageSize > 0 {
+		return d.PageSize
+	}
+	return DefaultLDAPPageSize
+}
+
+// connect connects to the server, upgrading the connection to TLS if required, and binds as the BindDN
+func (d *LDAPDirectory) connect() (*ldap.Conn, error) {
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse LDAP URL %s", d.URL)
+	}
+	timeout := d.Timeout
+	if timeout <= 0 {
+		timeout = DefaultLDAPTimeout
+	}
+	host := u.Host
+	tlsConfig := &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: d.InsecureSkipVerify,
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	var netConn net.Conn
+	switch u.Scheme {
+	case "ldap":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "389")
+		}
+		netConn, err = dialer.Dial("tcp", host)
+	case "ldaps":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "636")
+		}
+		netConn, err = tls.DialWithDialer(dialer, "tcp", host, tlsConfig)
+	default:
+		return nil, fmt.Errorf("unsupported LDAP URL %s. Use ldap:// or ldaps://", d.URL)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to LDAP server %s", d.URL)
+	}
+	conn := ldap.NewConn(netConn, u.Scheme == "ldaps")
+	conn.Start()
+	conn.SetTimeout(timeout)
+	if d.StartTLS && u.Scheme == "ldap" {
+		err = conn.StartTLS(tlsConfig)
+		if err != nil {
+			conn.Close()
+			return nil, errors.Wrapf(err, "failed to start TLS with LDAP server %s", d.URL)
+		}
+	}
+	if d.BindDN != "" {
+		err = conn.Bind(d.BindDN, d.BindPassword)
+		if err != nil {
+			conn.Close()
+			return nil, errors.Wrapf(err, "failed to bind to LDAP server %s as %s", d.URL, d.BindDN)
+		}
+	}
+	return conn, nil
+}
+
+// search returns all the entries in the subtree of the base DN matching the filter, requesting them a page at a
+// time so that servers with a size limit such as Active Directory return all of them. Referrals are not followed
+func (d *LDAPDirectory) search(conn *ldap.Conn, baseDN string, filter string, attributes []string) ([]*ldapEntry, error) {
+	timeLimit := int(d.Timeout / time.Second)
+	if timeLimit <= 0 {
+		timeLimit = int(DefaultLDAPTimeout / time.Second)
+	}
+	request := ldap.NewSearchRequest(baseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, timeLimit, false,
+		filter, attributes, nil)
+	result, err := conn.SearchWithPaging(request, uint32(d.pageSize()))
+	if err != nil {
+		return nil, err
+	}
+	entries := []*ldapEntry{}
+	for _, e := range result.Entries {
+		entry := &ldapEntry{
+			DN:         e.DN,
+			Attributes: map[string][]string{},
+		}
+		for _, attribute := range e.Attributes {
+			name := strings.ToLower(attribute.Name)
+			entry.Attributes[name] = append(entry.Attributes[name], attribute.Values...)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// normalizeDN returns the DN in lower case without the spaces around its separators so that DNs can be compared
+func normalizeDN(dn string) string {
+	parts := strings.Split(dn, ",")
+	for i, part := range parts {
+		nameValue := strings.SplitN(part, "=", 2)
+		for j, text := range nameValue {
+			nameValue[j] = strings.TrimSpace(text)
+		}
+		parts[i] = strings.Join(nameValue, "=")
+	}
+	return strings.ToLower(strings.Join(parts, ","))
+}
+
+// rdnValue returns the value of the first component of the DN such as 'developers' for 'cn=developers,ou=groups'
+func rdnValue(dn string) string {
+	rdn := strings.SplitN(dn, ",", 2)[0]
+	idx := strings.Index(rdn, "=")
+	if idx < 0 {
+		return ""
+	}
+	return strings.TrimSpace(rdn[idx+1:])
+}
+
+func defaultString(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,195 @@
+package usersync
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/asn1-ber.v1"
+	"gopkg.in/ldap.v2"
+)
+
+// mockLDAPServer a minimal LDAP server which supports simple binds and returns fixed entries a page at a time
+type mockLDAPServer struct {
+	listener net.Listener
+	password string
+	users    []*ldapEntry
+	groups   []*ldapEntry
+	pageSize int
+	binds    []string
+}
+
+func newMockLDAPServer(t *testing.T) *mockLDAPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &mockLDAPServer{
+		listener: listener,
+		password: "secret",
+		pageSize: 1,
+		users: []*ldapEntry{
+			{DN: "uid=jstrachan,ou=people,dc=example,dc=com", Attributes: map[string][]string{
+				"uid": {"jstrachan"}, "cn": {"James Strachan"}, "mail": {"james@example.com"},
+			}},
+			{DN: "uid=rawlingsj, ou=people, dc=example, dc=com", Attributes: map[string][]string{
+				"uid": {"rawlingsj"}, "cn": {"James Rawlings"},
+				"memberOf": {"cn=Admins,ou=groups,dc=example,dc=com"},
+			}},
+			{DN: "cn=no login,ou=people,dc=example,dc=com", Attributes: map[string][]string{
+				"cn": {"no login"},
+			}},
+		},
+		groups: []*ldapEntry{
+			{DN: "cn=developers,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+				"cn":     {"developers"},
+				"member": {"UID=jstrachan,OU=people,DC=example,DC=com", "uid=rawlingsj,ou=people,dc=example,dc=com"},
+			}},
+			{DN: "cn=ops,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+				"cn":        {"ops"},
+				"memberUid": {"rawlingsj"},
+			}},
+		},
+	}
+	go server.serve()
+	return server
+}
+
+func (s *mockLDAPServer) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *mockLDAPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *mockLDAPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		message, err := ber.ReadPacket(conn)
+		if err != nil || len(message.Children) < 2 {
+			return
+		}
+		id := message.Children[0].Value.(int64)
+		op := message.Children[1]
+		reply := func(op *ber.Packet, controls ...ldap.Control) {
+			packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+			packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+			packet.AppendChild(op)
+			if len(controls) > 0 {
+				encoded := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "Controls")
+				for _, control := range controls {
+					encoded.AppendChild(control.Encode())
+				}
+				packet.AppendChild(encoded)
+			}
+			conn.Write(packet.Bytes())
+		}
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			dn := op.Children[1].Value.(string)
+			s.binds = append(s.binds, dn)
+			code := int64(ldap.LDAPResultSuccess)
+			if op.Children[2].Data.String() != s.password {
+				code = ldap.LDAPResultInvalidCredentials
+			}
+			reply(mockLDAPResult(ldap.ApplicationBindResponse, code, "invalid credentials"))
+		case ldap.ApplicationSearchRequest:
+			entries := s.users
+			if strings.Contains(op.Children[0].Value.(string), "ou=groups") {
+				entries = s.groups
+			}
+			start := 0
+			if len(message.Children) > 2 {
+				for _, child := range message.Children[2].Children {
+					if paging, ok := ldap.DecodeControl(child).(*ldap.ControlPaging); ok {
+						start, _ = strconv.Atoi(string(paging.Cookie))
+					}
+				}
+			}
+			end := start + s.pageSize
+			if end > len(entries) {
+				end = len(entries)
+			}
+			for _, entry := range entries[start:end] {
+				result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+				result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "DN"))
+				attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+				for name, values := range entry.Attributes {
+					attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+					attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Name"))
+					set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+					for _, value := range values {
+						set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+					}
+					attribute.AppendChild(set)
+					attributes.AppendChild(attribute)
+				}
+				result.AppendChild(attributes)
+				reply(result)
+			}
+			paging := ldap.NewControlPaging(0)
+			if end < len(entries) {
+				paging.SetCookie([]byte(strconv.Itoa(end)))
+			}
+			reply(mockLDAPResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""), paging)
+		default:
+			return
+		}
+	}
+}
+
+func mockLDAPResult(tag ber.Tag, code int64, message string) *ber.Packet {
+	if code == ldap.LDAPResultSuccess {
+		message = ""
+	}
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "Diagnostic Message"))
+	return result
+}
+
+func TestLDAPDirectoryListUsers(t *testing.T) {
+	server := newMockLDAPServer(t)
+	defer server.listener.Close()
+
+	directory := &LDAPDirectory{
+		URL:          server.url(),
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "secret",
+		UserBaseDN:   "ou=people,dc=example,dc=com",
+		GroupBaseDN:  "ou=groups,dc=example,dc=com",
+	}
+	users, err := directory.ListUsers()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"cn=admin,dc=example,dc=com"}, server.binds)
+	assert.Equal(t, []*DirectoryUser{
+		{Login: "jstrachan", Name: "James Strachan", Email: "james@example.com", Groups: []string{"developers"}},
+		{Login: "rawlingsj", Name: "James Rawlings", Groups: []string{"Admins", "developers", "ops"}},
+	}, users)
+}
+
+func TestLDAPDirectoryInvalidCredentials(t *testing.T) {
+	server := newMockLDAPServer(t)
+	defer server.listener.Close()
+
+	directory := &LDAPDirectory{
+		URL:          server.url(),
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "wrong",
+		UserBaseDN:   "ou=people,dc=example,dc=com",
+	}
+	_, err := directory.ListUsers()
+	require.Error(t, err)
+	assert.True(t, ldap.IsErrorWithCode(errors.Cause(err), ldap.LDAPResultInvalidCredentials), "unexpected error %s", err)
+}
@@ -0,0 +1,211 @@
+package usersync
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultSCIMPageSize the default number of resources requested in each page from a SCIM endpoint
+const DefaultSCIMPageSize = 100
+
+// SCIMDirectory imports users and groups from a SCIM 2.0 endpoint such as the ones offered by Okta, Azure AD or Keycloak
+type SCIMDirectory struct {
+	URL             string
+	Token           string
+	PageSize        int
+	IncludeInactive bool
+	Client          *http.Client
+}
+
+type scimListResponse struct {
+	TotalResults int               `json:"totalResults"`
+	ItemsPerPage int               `json:"itemsPerPage"`
+	StartIndex   int               `json:"startIndex"`
+	Resources    []json.RawMessage `json:"Resources"`
+}
+
+type scimReference struct {
+	Value   string `json:"value"`
+	Display string `json:"display"`
+}
+
+type scimEmail struct {
+	Value   string `json:"value"`
+	Primary bool   `json:"primary"`
+}
+
+type scimUser struct {
+	ID          string `json:"id"`
+	UserName    string `json:"userName"`
+	DisplayName string `json:"displayName"`
+	Name        struct {
+		Formatted  string `json:"formatted"`
+		GivenName  string `json:"givenName"`
+		FamilyName string `json:"familyName"`
+	} `json:"name"`
+	Emails []scimEmail     `json:"emails"`
+	Active *bool           `json:"active"`
+	Groups []scimReference `json:"groups"`
+}
+
+type scimGroup struct {
+	ID          string          `json:"id"`
+	DisplayName string          `json:"displayName"`
+	Members     []scimReference `json:"members"`
+}
+
+// Name returns the host of the SCIM endpoint
+func (d *SCIMDirectory) Name() string {
+	u, err := url.Parse(d.URL)
+	if err != nil || u.Hostname() == "" {
+		return "scim"
+	}
+	return u.Hostname()
+}
+
+// ListUsers returns the active users of the endpoint along with the groups they are a member of
+func (d *SCIMDirectory) ListUsers() ([]*DirectoryUser, error) {
+	users := []*DirectoryUser{}
+	usersByID := map[string]*DirectoryUser{}
+	groupNames := map[string]string{}
+
+	userResources, err := d.list("Users")
+	if err != nil {
+		return nil, err
+	}
+	scimUsers := []*scimUser{}
+	for _, resource := range userResources {
+		u := &scimUser{}
+		err = json.Unmarshal(resource, u)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse a SCIM user")
+		}
+		if u.UserName == "" || (u.Active != nil && !*u.Active && !d.IncludeInactive) {
+			continue
+		}
+		user := &DirectoryUser{
+			Login: u.UserName,
+			Name:  scimDisplayName(u),
+			Email: scimPrimaryEmail(u.Emails),
+		}
+		users = append(users, user)
+		usersByID[u.ID] = user
+		scimUsers = append(scimUsers, u)
+	}
+
+	groupResources, err := d.list("Groups")
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range groupResources {
+		g := &scimGroup{}
+		err = json.Unmarshal(resource, g)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse a SCIM group")
+		}
+		groupNames[g.ID] = g.DisplayName
+		for _, member := range g.Members {
+			user := usersByID[member.Value]
+			if user != nil && g.DisplayName != "" {
+				user.addGroup(g.DisplayName)
+			}
+		}
+	}
+
+	// some endpoints only list the groups on the users
+	for _, u := range scimUsers {
+		user := usersByID[u.ID]
+		for _, ref := range u.Groups {
+			group := defaultString(groupNames[ref.Value], ref.Display)
+			if group != "" {
+				user.addGroup(group)
+			}
+		}
+	}
+	sortUsers(users)
+	return users, nil
+}
+
+// list returns all the resources of the given type a page at a time
+func (d *SCIMDirectory) list(resourceType string) ([]json.RawMessage, error) {
+	pageSize := d.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultSCIMPageSize
+	}
+	answer := []json.RawMessage{}
+	for startIndex := 1; ; {
+		u := fmt.Sprintf("%s/%s?startIndex=%d&count=%d", strings.TrimSuffix(d.URL, "/"), resourceType, startIndex, pageSize)
+		page := &scimListResponse{}
+		err := d.get(u, page)
+		if err != nil {
+			return answer, err
+		}
+		answer = append(answer, page.Resources...)
+		startIndex += len(page.Resources)
+		if len(page.Resources) == 0 || startIndex > page.TotalResults {
+			return answer, nil
+		}
+	}
+}
+
+func (d *SCIMDirectory) get(u string, result interface{}) error {
+	client := d.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/scim+json, application/json")
+	if d.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.Token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to query %s", u)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the response of %s", u)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %s querying %s: %s", resp.Status, u, strings.TrimSpace(string(body)))
+	}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the response of %s", u)
+	}
+	return nil
+}
+
+func scimDisplayName(u *scimUser) string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+	name := strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+	return defaultString(name, u.UserName)
+}
+
+func scimPrimaryEmail(emails []scimEmail) string {
+	for _, email := range emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(emails) > 0 {
+		return emails[0].Value
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package usersync_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/usersync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSCIMDirectoryListUsers(t *testing.T) {
+	t.Parallel()
+	users := []string{
+		`{"id": "1", "userName": "alice", "displayName": "Alice", "emails": [{"value": "a@home.com"}, {"value": "alice@example.com", "primary": true}]}`,
+		`{"id": "2", "userName": "bob", "name": {"givenName": "Bob", "familyName": "Smith"}, "groups": [{"value": "g2", "display": "ops"}]}`,
+		`{"id": "3", "userName": "carol", "active": false}`,
+	}
+	groups := []string{
+		`{"id": "g1", "displayName": "developers", "members": [{"value": "1"}, {"value": "2"}, {"value": "3"}]}`,
+		`{"id": "g2", "displayName": "ops"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer mytoken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		resources := users
+		if r.URL.Path == "/scim/v2/Groups" {
+			resources = groups
+		}
+		// lets return a single resource per page
+		startIndex, _ := strconv.Atoi(r.URL.Query().Get("startIndex"))
+		page := ""
+		if startIndex <= len(resources) {
+			page = resources[startIndex-1]
+		}
+		fmt.Fprintf(w, `{"totalResults": %d, "startIndex": %d, "itemsPerPage": 1, "Resources": [%s]}`, len(resources), startIndex, page)
+	}))
+	defer server.Close()
+
+	directory := &usersync.SCIMDirectory{
+		URL:   server.URL + "/scim/v2/",
+		Token: "mytoken",
+	}
+	actual, err := directory.ListUsers()
+	require.NoError(t, err)
+	assert.Equal(t, []*usersync.DirectoryUser{
+		{Login: "alice", Name: "Alice", Email: "alice@example.com", Groups: []string{"developers"}},
+		{Login: "bob", Name: "Bob Smith", Groups: []string{"developers", "ops"}},
+	}, actual)
+
+	directory.Token = "wrong"
+	_, err = directory.ListUsers()
+	assert.Error(t, err)
+}
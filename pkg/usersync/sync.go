@@ -0,0 +1,302 @@
+package usersync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GroupRole grants the members of a directory group a role in the environments of a team
+type GroupRole struct {
+	Group string
+	Team  string
+	Role  string
+}
+
+// ParseGroupRoles parses group to role mappings of the form 'group=role' or 'group=team/role'.
+// Mappings which do not specify a team grant the role in the default team
+func ParseGroupRoles(mappings []string, defaultTeam string) ([]*GroupRole, error) {
+	answer := []*GroupRole{}
+	for _, mapping := range mappings {
+		idx := strings.LastIndex(mapping, "=")
+		if idx <= 0 || idx == len(mapping)-1 {
+			return answer, fmt.Errorf("invalid group role mapping %s. Expected 'group=role' or 'group=team/role'", mapping)
+		}
+		groupRole := &GroupRole{
+			Group: strings.TrimSpace(mapping[:idx]),
+			Team:  defaultTeam,
+			Role:  strings.TrimSpace(mapping[idx+1:]),
+		}
+		paths := strings.SplitN(groupRole.Role, "/", 2)
+		if len(paths) == 2 {
+			groupRole.Team = paths[0]
+			groupRole.Role = paths[1]
+		}
+		if groupRole.Team == "" || groupRole.Role == "" {
+			return answer, fmt.Errorf("invalid group role mapping %s. Expected 'group=role' or 'group=team/role'", mapping)
+		}
+		answer = append(answer, groupRole)
+	}
+	return answer, nil
+}
+
+// UserSyncer imports the users of a directory into User resources and grants them the team roles of their groups
+type UserSyncer struct {
+	KubeClient     kubernetes.Interface
+	JXClient       versioned.Interface
+	AdminNamespace string
+	GroupRoles     []*GroupRole
+
+	// DeleteUsers deletes the Users imported from the directory when they are removed from it
+	DeleteUsers bool
+
+	// AllowEmpty allows a directory without any users to remove the roles of everyone imported from it. A directory
+	// without users is more likely a broken filter or base DN so the sync fails by default
+	AllowEmpty bool
+
+	teamRoles map[string]map[string]*rbacv1.Role
+}
+
+// SyncResult the changes made by a sync
+type SyncResult struct {
+	Created      []string
+	Updated      []string
+	Deleted      []string
+	RolesChanged []string
+}
+
+// Sync creates or updates a User for each user of the directory and updates the roles they were granted by their groups.
+// The roles granted by a previous sync are recorded on the User so that roles are removed when a user leaves a group
+// without removing the roles given to the user by hand
+func (s *UserSyncer) Sync(directory string, users []*DirectoryUser) (*SyncResult, error) {
+	result := &SyncResult{}
+	if len(users) == 0 && !s.AllowEmpty {
+		return result, fmt.Errorf("the directory %s returned no users so the Users imported from it are left unchanged", directory)
+	}
+	s.teamRoles = map[string]map[string]*rbacv1.Role{}
+	existing, names, err := kube.GetUsers(s.JXClient, s.AdminNamespace)
+	if err != nil {
+		return result, errors.Wrapf(err, "failed to list the users in namespace %s", s.AdminNamespace)
+	}
+	userInterface := s.JXClient.JenkinsV1().Users(s.AdminNamespace)
+	found := map[string]bool{}
+	for _, du := range users {
+		name := kube.ToValidName(du.Login)
+		if name == "" || found[name] {
+			continue
+		}
+		found[name] = true
+		user := existing[name]
+		if user == nil {
+			user = kube.CreateUser(s.AdminNamespace, du.Login, du.Name, du.Email)
+			user.Labels = map[string]string{
+				kube.LabelUserDirectory: directory,
+			}
+			user, err = userInterface.Create(user)
+			if err != nil {
+				return result, errors.Wrapf(err, "failed to create the User %s", du.Login)
+			}
+			result.Created = append(result.Created, du.Login)
+			log.Infof("Created User %s from %s\n", util.ColorInfo(du.Login), util.ColorInfo(directory))
+		} else if user.Labels[kube.LabelUserDirectory] == directory && (user.Spec.Name != du.Name || user.Spec.Email != du.Email) {
+			user.Spec.Name = du.Name
+			user.Spec.Email = du.Email
+			user, err = userInterface.Update(user)
+			if err != nil {
+				return result, errors.Wrapf(err, "failed to update the User %s", du.Login)
+			}
+			result.Updated = append(result.Updated, du.Login)
+		}
+		changed, err := s.syncRoles(user, s.rolesForGroups(du.Groups))
+		if err != nil {
+			return result, err
+		}
+		if changed {
+			result.RolesChanged = append(result.RolesChanged, du.Login)
+		}
+	}
+
+	for _, name := range names {
+		user := existing[name]
+		if found[name] {
+			continue
+		}
+		imported := user.Labels[kube.LabelUserDirectory] == directory
+		if !imported && user.Annotations[kube.AnnotationDirectoryRoles] == "" {
+			continue
+		}
+		changed, err := s.syncRoles(user, []string{})
+		if err != nil {
+			return result, err
+		}
+		if changed {
+			result.RolesChanged = append(result.RolesChanged, user.Spec.Login)
+		}
+		if imported && s.DeleteUsers {
+			err = kube.DeleteUser(s.JXClient, s.AdminNamespace, name)
+			if err != nil {
+				return result, errors.Wrapf(err, "failed to delete the User %s", name)
+			}
+			result.Deleted = append(result.Deleted, user.Spec.Login)
+			log.Infof("Deleted User %s as it was removed from %s\n", util.ColorInfo(user.Spec.Login), util.ColorInfo(directory))
+		}
+	}
+	return result, nil
+}
+
+// rolesForGroups returns the sorted 'team/role' pairs granted to the members of the given groups
+func (s *UserSyncer) rolesForGroups(groups []string) []string {
+	answer := []string{}
+	for _, groupRole := range s.GroupRoles {
+		for _, group := range groups {
+			if strings.EqualFold(group, groupRole.Group) {
+				key := groupRole.Team + "/" + groupRole.Role
+				if util.StringArrayIndex(answer, key) < 0 {
+					answer = append(answer, key)
+				}
+			}
+		}
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+// syncRoles grants the user the given 'team/role' pairs and removes the roles granted by previous syncs which are no
+// longer wanted. Only the roles the sync created are recorded so that a role the user already had, such as one given
+// with 'jx edit userrole', is never removed even if one of their groups grants it too
+func (s *UserSyncer) syncRoles(user *v1.User, desired []string) (bool, error) {
+	previous := DirectoryRoles(user)
+	teams := []string{}
+	for _, key := range append(append([]string{}, previous...), desired...) {
+		team := strings.SplitN(key, "/", 2)[0]
+		if util.StringArrayIndex(teams, team) < 0 {
+			teams = append(teams, team)
+		}
+	}
+	sort.Strings(teams)
+
+	granted := []string{}
+	changed := false
+	for i, team := range teams {
+		teamGranted, teamChanged, err := s.syncTeamRoles(user, team, previous, desired)
+		changed = changed || teamChanged
+		if err != nil {
+			// keep the roles granted in the teams which were not synchronised so that they are removed by a later sync
+			recorded := append([]string{}, granted...)
+			for _, key := range previous {
+				if util.StringArrayIndex(teams[i:], strings.SplitN(key, "/", 2)[0]) >= 0 {
+					recorded = append(recorded, key)
+				}
+			}
+			recordErr := s.recordDirectoryRoles(user, recorded)
+			if recordErr != nil {
+				log.Warnf("%s\n", recordErr)
+			}
+			return changed, err
+		}
+		granted = append(granted, teamGranted...)
+	}
+	return changed, s.recordDirectoryRoles(user, granted)
+}
+
+// syncTeamRoles updates the roles of the user in the team returning the 'team/role' pairs granted by the sync and
+// whether the roles changed
+func (s *UserSyncer) syncTeamRoles(user *v1.User, team string, previous []string, desired []string) ([]string, bool, error) {
+	granted := []string{}
+	roles, err := s.rolesOfTeam(team)
+	if err != nil {
+		return granted, false, err
+	}
+	current, err := kube.GetUserRoles(s.JXClient, team, user.SubjectKind(), user.Name)
+	if err != nil {
+		return granted, false, errors.Wrapf(err, "failed to get the roles of User %s in team %s", user.Name, team)
+	}
+	newRoles := []string{}
+	for _, role := range current {
+		key := team + "/" + role
+		if util.StringArrayIndex(previous, key) >= 0 && util.StringArrayIndex(desired, key) < 0 {
+			continue
+		}
+		newRoles = append(newRoles, role)
+	}
+	for _, key := range desired {
+		role := strings.TrimPrefix(key, team+"/")
+		if role == key {
+			continue
+		}
+		if roles[role] == nil {
+			log.Warnf("Cannot grant User %s the role %s as team %s has no such role\n", user.Name, role, team)
+			continue
+		}
+		if util.StringArrayIndex(current, role) < 0 {
+			newRoles = append(newRoles, role)
+			granted = append(granted, key)
+		} else if util.StringArrayIndex(previous, key) >= 0 {
+			granted = append(granted, key)
+		}
+	}
+	sort.Strings(current)
+	sort.Strings(newRoles)
+	if strings.Join(current, ",") == strings.Join(newRoles, ",") {
+		return granted, false, nil
+	}
+	err = kube.UpdateUserRoles(s.KubeClient, s.JXClient, team, user.SubjectKind(), user.Name, newRoles, roles)
+	if err != nil {
+		return granted, false, errors.Wrapf(err, "failed to update the roles of User %s in team %s", user.Name, team)
+	}
+	log.Infof("Updated the roles of User %s in team %s to %s\n", util.ColorInfo(user.Name), util.ColorInfo(team), util.ColorInfo(strings.Join(newRoles, ", ")))
+	return granted, true, nil
+}
+
+// recordDirectoryRoles records the 'team/role' pairs granted to the user by the sync
+func (s *UserSyncer) recordDirectoryRoles(user *v1.User, granted []string) error {
+	value := strings.Join(granted, ",")
+	if user.Annotations[kube.AnnotationDirectoryRoles] == value {
+		return nil
+	}
+	if user.Annotations == nil {
+		user.Annotations = map[string]string{}
+	}
+	if value == "" {
+		delete(user.Annotations, kube.AnnotationDirectoryRoles)
+	} else {
+		user.Annotations[kube.AnnotationDirectoryRoles] = value
+	}
+	_, err := s.JXClient.JenkinsV1().Users(s.AdminNamespace).Update(user)
+	if err != nil {
+		return errors.Wrapf(err, "failed to record the directory roles of User %s", user.Name)
+	}
+	return nil
+}
+
+// rolesOfTeam returns the roles of the team caching them for the duration of a sync
+func (s *UserSyncer) rolesOfTeam(team string) (map[string]*rbacv1.Role, error) {
+	roles := s.teamRoles[team]
+	if roles != nil {
+		return roles, nil
+	}
+	roles, _, err := kube.GetTeamRoles(s.KubeClient, team)
+	if err != nil {
+		return roles, errors.Wrapf(err, "failed to list the roles of team %s", team)
+	}
+	s.teamRoles[team] = roles
+	return roles, nil
+}
+
+// DirectoryRoles returns the 'team/role' pairs the user was granted by the groups of a directory
+func DirectoryRoles(user *v1.User) []string {
+	value := user.Annotations[kube.AnnotationDirectoryRoles]
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,224 @@
+package usersync_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	v1fake "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/usersync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestParseGroupRoles(t *testing.T) {
+	t.Parallel()
+	groupRoles, err := usersync.ParseGroupRoles([]string{"developers=committer", "ops=production/owner"}, "jx")
+	require.NoError(t, err)
+	assert.Equal(t, []*usersync.GroupRole{
+		{Group: "developers", Team: "jx", Role: "committer"},
+		{Group: "ops", Team: "production", Role: "owner"},
+	}, groupRoles)
+
+	for _, invalid := range []string{"developers", "=viewer", "developers=", "developers=jx/"} {
+		_, err = usersync.ParseGroupRoles([]string{invalid}, "jx")
+		assert.Error(t, err, "mapping %s", invalid)
+	}
+}
+
+func TestUserSyncerSync(t *testing.T) {
+	t.Parallel()
+	ns := "jx"
+	roles := []*rbacv1.Role{}
+	rolesMap := map[string]*rbacv1.Role{}
+	for _, name := range []string{"viewer", "committer", "owner"} {
+		role := &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+				Labels:    map[string]string{kube.LabelKind: kube.ValueKindEnvironmentRole},
+			},
+		}
+		roles = append(roles, role)
+		rolesMap[name] = role
+	}
+	kubeClient := fake.NewSimpleClientset(roles[0], roles[1], roles[2])
+	jxClient := v1fake.NewSimpleClientset(kube.CreateUser(ns, "carol", "Carol", "carol@example.com"),
+		kube.CreateUser(ns, "dave", "Dave", "dave@example.com"))
+
+	// carol and dave were given roles by hand which must survive the sync
+	err := kube.UpdateUserRoles(kubeClient, jxClient, ns, "User", "carol", []string{"viewer"}, rolesMap)
+	require.NoError(t, err)
+	err = kube.UpdateUserRoles(kubeClient, jxClient, ns, "User", "dave", []string{"committer"}, rolesMap)
+	require.NoError(t, err)
+
+	groupRoles, err := usersync.ParseGroupRoles([]string{"developers=committer", "admins=owner", "missing=nosuchrole"}, ns)
+	require.NoError(t, err)
+	syncer := &usersync.UserSyncer{
+		KubeClient:     kubeClient,
+		JXClient:       jxClient,
+		AdminNamespace: ns,
+		GroupRoles:     groupRoles,
+		DeleteUsers:    true,
+	}
+
+	result, err := syncer.Sync("ldap.example.com", []*usersync.DirectoryUser{
+		{Login: "alice", Name: "Alice", Email: "alice@example.com", Groups: []string{"Developers", "admins"}},
+		{Login: "bob", Name: "Bob", Groups: []string{"developers", "missing"}},
+		{Login: "carol", Name: "Carol", Groups: []string{"developers"}},
+		{Login: "dave", Name: "Dave", Groups: []string{"developers"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, result.Created)
+	assert.Equal(t, []string{"alice", "bob", "carol"}, result.RolesChanged)
+	assertRoles(t, jxClient, ns, "alice", "committer", "owner")
+	assertRoles(t, jxClient, ns, "bob", "committer")
+	assertRoles(t, jxClient, ns, "carol", "committer", "viewer")
+	assertRoles(t, jxClient, ns, "dave", "committer")
+
+	alice, err := jxClient.JenkinsV1().Users(ns).Get("alice", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "ldap.example.com", alice.Labels[kube.LabelUserDirectory])
+	assert.Equal(t, []string{"jx/committer", "jx/owner"}, usersync.DirectoryRoles(alice))
+
+	dave, err := jxClient.JenkinsV1().Users(ns).Get("dave", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, usersync.DirectoryRoles(dave), "roles the user already had are not recorded as granted by the sync")
+
+	// alice leaves the admins group, bob leaves the directory and carol and dave leave all their groups
+	result, err = syncer.Sync("ldap.example.com", []*usersync.DirectoryUser{
+		{Login: "alice", Name: "Alice Smith", Email: "alice@example.com", Groups: []string{"developers"}},
+		{Login: "carol", Name: "Carol"},
+		{Login: "dave", Name: "Dave"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Equal(t, []string{"alice"}, result.Updated)
+	assert.Equal(t, []string{"bob"}, result.Deleted)
+	assertRoles(t, jxClient, ns, "alice", "committer")
+	assertRoles(t, jxClient, ns, "bob")
+	assertRoles(t, jxClient, ns, "carol", "viewer")
+	assertRoles(t, jxClient, ns, "dave", "committer")
+
+	_, names, err := kube.GetUsers(jxClient, ns)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "carol", "dave"}, names)
+
+	carol, err := jxClient.JenkinsV1().Users(ns).Get("carol", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, usersync.DirectoryRoles(carol))
+	assert.Empty(t, carol.Labels[kube.LabelUserDirectory], "users created by hand are not labelled")
+
+	// nothing changes on the next sync
+	result, err = syncer.Sync("ldap.example.com", []*usersync.DirectoryUser{
+		{Login: "alice", Name: "Alice Smith", Email: "alice@example.com", Groups: []string{"developers"}},
+		{Login: "carol", Name: "Carol"},
+		{Login: "dave", Name: "Dave"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Empty(t, result.Updated)
+	assert.Empty(t, result.Deleted)
+	assert.Empty(t, result.RolesChanged)
+
+	// a directory without users leaves everyone alone unless that is explicitly allowed
+	_, err = syncer.Sync("ldap.example.com", []*usersync.DirectoryUser{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "returned no users")
+	assertRoles(t, jxClient, ns, "alice", "committer")
+
+	syncer.AllowEmpty = true
+	result, err = syncer.Sync("ldap.example.com", []*usersync.DirectoryUser{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, result.Deleted)
+	assertRoles(t, jxClient, ns, "alice")
+}
+
+func TestUserSyncerSyncRecordsRolesGrantedBeforeAFailure(t *testing.T) {
+	t.Parallel()
+	teams := []string{"alpha", "beta"}
+	objects := []runtime.Object{}
+	for _, team := range teams {
+		objects = append(objects, &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "committer",
+				Namespace: team,
+				Labels:    map[string]string{kube.LabelKind: kube.ValueKindEnvironmentRole},
+			},
+		})
+	}
+	kubeClient := fake.NewSimpleClientset(objects...)
+	jxClient := v1fake.NewSimpleClientset()
+	failBeta := true
+	jxClient.PrependReactor("*", "environmentrolebindings", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		switch action.GetVerb() {
+		case "create", "update", "patch", "delete":
+			if failBeta && action.GetNamespace() == "beta" {
+				return true, nil, fmt.Errorf("the API server is unavailable")
+			}
+		}
+		return false, nil, nil
+	})
+
+	groupRoles, err := usersync.ParseGroupRoles([]string{"developers=alpha/committer", "developers=beta/committer"}, "jx")
+	require.NoError(t, err)
+	syncer := &usersync.UserSyncer{
+		KubeClient:     kubeClient,
+		JXClient:       jxClient,
+		AdminNamespace: "jx",
+		GroupRoles:     groupRoles,
+	}
+	developer := []*usersync.DirectoryUser{{Login: "erin", Name: "Erin", Groups: []string{"developers"}}}
+	assertDirectoryRoles := func(expected ...string) {
+		erin, err := jxClient.JenkinsV1().Users("jx").Get("erin", metav1.GetOptions{})
+		require.NoError(t, err)
+		if len(expected) == 0 {
+			assert.Empty(t, usersync.DirectoryRoles(erin))
+		} else {
+			assert.Equal(t, expected, usersync.DirectoryRoles(erin))
+		}
+	}
+
+	_, err = syncer.Sync("ldap.example.com", developer)
+	require.Error(t, err)
+	assertRoles(t, jxClient, "alpha", "erin", "committer")
+	assertRoles(t, jxClient, "beta", "erin")
+	assertDirectoryRoles("alpha/committer")
+
+	failBeta = false
+	_, err = syncer.Sync("ldap.example.com", developer)
+	require.NoError(t, err)
+	assertRoles(t, jxClient, "beta", "erin", "committer")
+	assertDirectoryRoles("alpha/committer", "beta/committer")
+
+	// erin leaves the group but the roles of beta cannot be removed so they are still recorded
+	failBeta = true
+	_, err = syncer.Sync("ldap.example.com", []*usersync.DirectoryUser{{Login: "erin", Name: "Erin"}})
+	require.Error(t, err)
+	assertRoles(t, jxClient, "alpha", "erin")
+	assertRoles(t, jxClient, "beta", "erin", "committer")
+	assertDirectoryRoles("beta/committer")
+
+	failBeta = false
+	_, err = syncer.Sync("ldap.example.com", []*usersync.DirectoryUser{{Login: "erin", Name: "Erin"}})
+	require.NoError(t, err)
+	assertRoles(t, jxClient, "beta", "erin")
+	assertDirectoryRoles()
+}
+
+func assertRoles(t *testing.T, jxClient *v1fake.Clientset, ns string, user string, expected ...string) {
+	actual, err := kube.GetUserRoles(jxClient, ns, "User", user)
+	require.NoError(t, err)
+	sort.Strings(actual)
+	if len(expected) == 0 {
+		assert.Empty(t, actual, "roles of user %s", user)
+	} else {
+		assert.Equal(t, expected, actual, "roles of user %s", user)
+	}
+}
@@ -38,3 +38,9 @@ func assertStringIndices(t *testing.T, text string, sep string, expected []int)
 	actual := util.StringIndexes(text, sep)
 	assert.Equal(t, expected, actual, "Failed to evaluate StringIndices(%s, %s)", text, sep)
 }
+
+func TestDiffSlices(t *testing.T) {
+	toDelete, toInsert := util.DiffSlices([]string{"a", "b", "c", "d"}, []string{"b", "e", "f"})
+	assert.Equal(t, []string{"a", "c", "d"}, toDelete)
+	assert.Equal(t, []string{"e", "f"}, toInsert)
+}
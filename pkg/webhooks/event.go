@@ -0,0 +1,130 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+)
+
+// Event a webhook payload received from, or synthesised for, a git provider
+type Event struct {
+	// ID the delivery ID of the git provider or a generated one
+	ID string `json:"id"`
+	// Kind the kind of git provider which sent the event such as github or gitea
+	Kind string `json:"kind"`
+	// Type the provider specific name of the event such as push, pull_request or pr:opened
+	Type string `json:"type"`
+	// Repository the 'owner/name' of the repository the event is about if known
+	Repository string `json:"repository,omitempty"`
+	// Headers the HTTP headers of the request without any credentials
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body the JSON payload
+	Body string `json:"body"`
+	// Received when the event was received or synthesised
+	Received time.Time `json:"received"`
+}
+
+// providerHeaders the headers naming the event and delivery of each kind of git provider. Gitea also sends the GitHub
+// headers so it has to be checked first
+var providerHeaders = []struct {
+	kind     string
+	event    string
+	delivery string
+	required string
+}{
+	{kind: gits.KindGitea, event: "X-Gitea-Event", delivery: "X-Gitea-Delivery"},
+	{kind: gits.KindGitHub, event: "X-GitHub-Event", delivery: "X-GitHub-Delivery"},
+	{kind: gits.KindGitlab, event: "X-Gitlab-Event"},
+	{kind: gits.KindBitBucketCloud, event: "X-Event-Key", delivery: "X-Request-UUID", required: "X-Hook-UUID"},
+	{kind: gits.KindBitBucketServer, event: "X-Event-Key", delivery: "X-Request-Id"},
+	{kind: gits.KindCodeCommit, event: "X-Amz-Sns-Message-Type", delivery: "X-Amz-Sns-Message-Id"},
+}
+
+// ignoredHeaders the headers which are not recorded or replayed
+var ignoredHeaders = map[string]bool{
+	"Authorization":     true,
+	"Cookie":            true,
+	"Content-Length":    true,
+	"Accept-Encoding":   true,
+	"Connection":        true,
+	"X-Forwarded-For":   true,
+	"X-Forwarded-Host":  true,
+	"X-Forwarded-Port":  true,
+	"X-Forwarded-Proto": true,
+	"X-Real-Ip":         true,
+}
+
+// NewEvent creates an event from a webhook request and its body
+func NewEvent(req *http.Request, body []byte) *Event {
+	event := &Event{
+		Kind:     gits.KindUnknown,
+		Headers:  map[string]string{},
+		Body:     string(body),
+		Received: time.Now(),
+	}
+	for name, values := range req.Header {
+		name = http.CanonicalHeaderKey(name)
+		if !ignoredHeaders[name] && len(values) > 0 {
+			event.Headers[name] = values[0]
+		}
+	}
+	for _, h := range providerHeaders {
+		eventType := req.Header.Get(h.event)
+		if eventType == "" || (h.required != "" && req.Header.Get(h.required) == "") {
+			continue
+		}
+		event.Kind = h.kind
+		event.Type = eventType
+		if h.delivery != "" {
+			event.ID = req.Header.Get(h.delivery)
+		}
+		break
+	}
+	event.Repository = payloadRepository(body)
+	return event
+}
+
+// payloadRepository returns the 'owner/name' of the repository in the webhook payload of any of the git providers
+func payloadRepository(body []byte) string {
+	payload := map[string]interface{}{}
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		return ""
+	}
+	// GitHub, Gitea and Bitbucket Cloud
+	if name := jsonString(payload, "repository", "full_name"); name != "" {
+		return name
+	}
+	// GitLab
+	if name := jsonString(payload, "project", "path_with_namespace"); name != "" {
+		return name
+	}
+	// CodeCommit repository triggers delivered by SNS
+	if name := snsRepository(payload); name != "" {
+		return name
+	}
+	// Bitbucket Server pushes and Pull Requests
+	for _, path := range [][]string{{"repository"}, {"pullRequest", "toRef", "repository"}} {
+		project := jsonString(payload, append(path, "project", "key")...)
+		slug := jsonString(payload, append(path, "slug")...)
+		if project != "" && slug != "" {
+			return strings.ToLower(project) + "/" + slug
+		}
+	}
+	return ""
+}
+
+func jsonString(value interface{}, path ...string) string {
+	for _, key := range path {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = m[key]
+	}
+	text, _ := value.(string)
+	return text
+}
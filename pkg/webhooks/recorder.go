@@ -0,0 +1,237 @@
+package webhooks
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// EventsPath the path on which the recorder lists the recorded events
+	EventsPath = "/events"
+	// HealthPath the path of the health check of the recorder
+	HealthPath = "/health"
+
+	maxBodySize = 25 * 1024 * 1024
+)
+
+// Recorder is an HTTP handler which records the webhooks it receives and optionally forwards them to the real
+// webhook endpoint so that it can sit in front of Jenkins or the Prow hook. The recorded events are listed as JSON
+// on EventsPath and each event on EventsPath/ID to the clients which present the EventsToken as a bearer token.
+//
+// SNS subscriptions, which CodeCommit webhooks are made of, are confirmed rather than forwarded and the SNS
+// notifications of CodeCommit repository triggers are forwarded as GitHub push events. Both are only trusted if they
+// are signed by SNS and come from one of the TopicArns
+type Recorder struct {
+	Store *FileStore
+
+	// ForwardURL the URL webhooks are forwarded to. Webhooks are only recorded if blank
+	ForwardURL string
+	// ForwardSecret the webhook secret used to sign the events translated from CodeCommit notifications
+	ForwardSecret string
+	// TopicArns the SNS topics whose subscriptions are confirmed and whose notifications are forwarded. A topic
+	// ending with * matches every topic starting with the rest of it
+	TopicArns []string
+	// EventsToken the bearer token required to list the recorded events as they contain the payloads of the
+	// webhooks. The events are not listed if blank
+	EventsToken string
+	Client      *http.Client
+}
+
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	switch {
+	case req.Method == http.MethodGet && path == HealthPath:
+		w.Write([]byte("OK"))
+	case req.Method == http.MethodGet && path == EventsPath:
+		if !r.authorized(w, req) {
+			return
+		}
+		events, err := r.Store.List()
+		r.writeJSON(w, events, err)
+	case req.Method == http.MethodGet && strings.HasPrefix(path, EventsPath+"/"):
+		if !r.authorized(w, req) {
+			return
+		}
+		event, err := r.Store.Get(strings.TrimPrefix(path, EventsPath+"/"))
+		if err == nil && event == nil {
+			http.NotFound(w, req)
+			return
+		}
+		r.writeJSON(w, event, err)
+	case req.Method == http.MethodPost:
+		r.record(w, req)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authorized returns true if the request presents the events token otherwise it writes the error response
+func (r *Recorder) authorized(w http.ResponseWriter, req *http.Request) bool {
+	if r.EventsToken == "" {
+		http.Error(w, "The recorded events are not listed as the recorder has no events token", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(r.EventsToken)) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+func (r *Recorder) record(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read the webhook: %s", err), http.StatusBadRequest)
+		return
+	}
+	event := NewEvent(req, body)
+
+	// lets not expose tokens on the events endpoint
+	recorded := *event
+	recorded.Headers = map[string]string{}
+	for name, value := range event.Headers {
+		if name != gitlabTokenHeader {
+			recorded.Headers[name] = value
+		}
+	}
+	err = r.Store.Save(&recorded)
+	if err != nil {
+		log.Warnf("Failed to record the %s %s webhook: %s\n", event.Kind, event.Type, err)
+	} else {
+		log.Infof("Recorded the %s %s webhook for %s as %s\n", event.Kind, event.Type, event.Repository, recorded.ID)
+	}
+	if event.Kind == gits.KindCodeCommit && (event.Type == SNSSubscriptionConfirmation || event.Type == SNSNotification) {
+		// lets not let anyone who can reach the recorder subscribe it to their topics or forge push events
+		err = VerifySNSMessage(r.Client, body, r.TopicArns)
+		if err != nil {
+			log.Warnf("Rejected the SNS message of webhook %s: %s\n", recorded.ID, err)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+	if event.Kind == gits.KindCodeCommit && event.Type == SNSSubscriptionConfirmation {
+		err = ConfirmSNSSubscription(r.Client, body)
+		if err != nil {
+			log.Warnf("Failed to confirm the SNS subscription of webhook %s: %s\n", recorded.ID, err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Infof("Confirmed the SNS subscription of webhook %s\n", recorded.ID)
+		w.Write([]byte("OK"))
+		return
+	}
+	if r.ForwardURL == "" {
+		w.Write([]byte("OK"))
+		return
+	}
+	if event.Kind == gits.KindCodeCommit && event.Type == SNSNotification {
+		r.forwardCodeCommitNotification(w, body, recorded.ID)
+		return
+	}
+
+	// lets pass on the response of the real endpoint so that the git provider shows the right delivery status
+	forwardURL := r.ForwardURL
+	if req.URL.RawQuery != "" && !strings.Contains(forwardURL, "?") {
+		forwardURL += "?" + req.URL.RawQuery
+	}
+	resp, err := Send(r.Client, forwardURL, event, "")
+	if err != nil {
+		log.Warnf("Failed to forward the webhook %s to %s: %s\n", recorded.ID, r.ForwardURL, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	data, _ := ioutil.ReadAll(resp.Body)
+	w.WriteHeader(resp.StatusCode)
+	w.Write(data)
+}
+
+// forwardCodeCommitNotification forwards the SNS notification of a CodeCommit repository trigger as GitHub push
+// events. SNS retries the notification unless all of them are accepted
+func (r *Recorder) forwardCodeCommitNotification(w http.ResponseWriter, body []byte, id string) {
+	events, err := CodeCommitPushEvents(body)
+	if err != nil {
+		log.Warnf("Failed to translate the CodeCommit notification %s: %s\n", id, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, event := range events {
+		resp, err := Send(r.Client, r.ForwardURL, event, r.ForwardSecret)
+		if err != nil {
+			log.Warnf("Failed to forward the push event of CodeCommit notification %s to %s: %s\n", id, r.ForwardURL, err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			log.Warnf("Failed to forward the push event of CodeCommit notification %s to %s: %s\n", id, r.ForwardURL, resp.Status)
+			http.Error(w, resp.Status, http.StatusBadGateway)
+			return
+		}
+	}
+	log.Infof("Forwarded CodeCommit notification %s as %d push events\n", id, len(events))
+	w.Write([]byte("OK"))
+}
+
+func (r *Recorder) writeJSON(w http.ResponseWriter, value interface{}, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// ListRecordedEvents returns the events recorded by the recorder at the URL most recent first using the events token
+// of the recorder
+func ListRecordedEvents(client *http.Client, recorderURL string, token string) ([]*Event, error) {
+	events := []*Event{}
+	err := getJSON(client, util.UrlJoin(recorderURL, EventsPath), token, &events)
+	return events, err
+}
+
+// GetRecordedEvent returns the event with the ID recorded by the recorder at the URL using the events token of the
+// recorder
+func GetRecordedEvent(client *http.Client, recorderURL string, token string, id string) (*Event, error) {
+	event := &Event{}
+	err := getJSON(client, util.UrlJoin(recorderURL, EventsPath, url.PathEscape(id)), token, event)
+	return event, err
+}
+
+func getJSON(client *http.Client, u string, token string, value interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("no webhook event found at %s", u)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get %s: %s", u, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(value)
+}
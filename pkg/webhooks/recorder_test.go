@@ -0,0 +1,431 @@
+package webhooks_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pushPayload = `{"ref":"refs/heads/master","repository":{"name":"myrepo","full_name":"myorg/myrepo"}}`
+	snsPayload  = `{"Type":"Notification","TopicArn":"arn:aws:sns:us-east-1:123456789012:jx-codecommit-myrepo",` +
+		`"Message":"{\"Records\":[{\"eventSourceARN\":\"arn:aws:codecommit:us-east-1:123456789012:myrepo\"}]}"}`
+)
+
+func createStore(t *testing.T, maxEvents int) (*webhooks.FileStore, func()) {
+	dir, err := ioutil.TempDir("", "test-webhooks")
+	require.NoError(t, err)
+	store, err := webhooks.NewFileStore(dir, maxEvents)
+	require.NoError(t, err)
+	return store, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func postWebhook(t *testing.T, url string, headers map[string]string, body string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestRecorderRecordsAndForwardsWebhooks(t *testing.T) {
+	t.Parallel()
+	store, cleanup := createStore(t, 0)
+	defer cleanup()
+
+	forwarded := []*http.Request{}
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = append(forwarded, r)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer hook.Close()
+
+	recorder := httptest.NewServer(&webhooks.Recorder{Store: store, ForwardURL: hook.URL + "/hook", EventsToken: "token"})
+	defer recorder.Close()
+
+	resp := postWebhook(t, recorder.URL+"/hook", map[string]string{
+		"X-GitHub-Event":    "push",
+		"X-GitHub-Delivery": "abc-123",
+		"X-Hub-Signature":   "sha1=1234",
+		"Authorization":     "Bearer secret",
+	}, pushPayload)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode, "the status of the real endpoint should be returned")
+	require.Equal(t, 1, len(forwarded))
+	assert.Equal(t, "/hook", forwarded[0].URL.Path)
+	assert.Equal(t, "push", forwarded[0].Header.Get("X-GitHub-Event"))
+	assert.Equal(t, "sha1=1234", forwarded[0].Header.Get("X-Hub-Signature"))
+	assert.Equal(t, "", forwarded[0].Header.Get("Authorization"))
+
+	events, err := webhooks.ListRecordedEvents(nil, recorder.URL, "token")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(events))
+	event := events[0]
+	assert.Equal(t, "abc-123", event.ID)
+	assert.Equal(t, gits.KindGitHub, event.Kind)
+	assert.Equal(t, "push", event.Type)
+	assert.Equal(t, "myorg/myrepo", event.Repository)
+	assert.Equal(t, pushPayload, event.Body)
+	assert.Equal(t, "", event.Headers["Authorization"])
+
+	event, err = webhooks.GetRecordedEvent(nil, recorder.URL, "token", "abc-123")
+	require.NoError(t, err)
+	assert.Equal(t, "abc-123", event.ID)
+
+	_, err = webhooks.GetRecordedEvent(nil, recorder.URL, "token", "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no webhook event found")
+
+	_, err = webhooks.ListRecordedEvents(nil, recorder.URL, "wrong")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+
+	resp, err = http.Get(recorder.URL + webhooks.EventsPath + "/abc-123")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRecorderWithoutEventsTokenDoesNotListEvents(t *testing.T) {
+	t.Parallel()
+	store, cleanup := createStore(t, 0)
+	defer cleanup()
+
+	recorder := httptest.NewServer(&webhooks.Recorder{Store: store})
+	defer recorder.Close()
+
+	resp := postWebhook(t, recorder.URL+"/hook", map[string]string{"X-GitHub-Event": "push"}, pushPayload)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	for _, path := range []string{webhooks.EventsPath, webhooks.EventsPath + "/abc-123"} {
+		req, err := http.NewRequest(http.MethodGet, recorder.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer ")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, "GET %s", path)
+	}
+}
+
+func TestNewEventDetectsProviders(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		headers map[string]string
+		body    string
+		kind    string
+		repo    string
+	}{
+		{map[string]string{"X-Gitea-Event": "push", "X-GitHub-Event": "push"}, pushPayload, gits.KindGitea, "myorg/myrepo"},
+		{map[string]string{"X-Gitlab-Event": "Push Hook"}, `{"project":{"path_with_namespace":"mygroup/myrepo"}}`, gits.KindGitlab, "mygroup/myrepo"},
+		{map[string]string{"X-Event-Key": "repo:push", "X-Hook-UUID": "1234"}, pushPayload, gits.KindBitBucketCloud, "myorg/myrepo"},
+		{map[string]string{"X-Event-Key": "pr:opened"}, `{"pullRequest":{"toRef":{"repository":{"slug":"myrepo","project":{"key":"PROJ"}}}}}`, gits.KindBitBucketServer, "proj/myrepo"},
+		{map[string]string{"X-Amz-Sns-Message-Type": "Notification"}, snsPayload, gits.KindCodeCommit, "us-east-1/myrepo"},
+		{map[string]string{}, `not json`, gits.KindUnknown, ""},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+		event := webhooks.NewEvent(req, []byte(tc.body))
+		assert.Equal(t, tc.kind, event.Kind)
+		assert.Equal(t, tc.repo, event.Repository)
+	}
+}
+
+func TestConfirmSNSSubscriptionOnlyCallsSNS(t *testing.T) {
+	t.Parallel()
+	body := `{"Type":"SubscriptionConfirmation","TopicArn":"arn:aws:sns:us-east-1:123456789012:mytopic","SubscribeURL":"https://example.com/confirm"}`
+	err := webhooks.ConfirmSNSSubscription(nil, []byte(body))
+	assert.Error(t, err)
+
+	err = webhooks.ConfirmSNSSubscription(nil, []byte(snsPayload))
+	assert.Error(t, err)
+}
+
+const (
+	codeCommitTopic   = "arn:aws:sns:us-east-1:123456789012:jx-codecommit-myrepo"
+	codeCommitMessage = `{"Records":[{"eventSourceARN":"arn:aws:codecommit:us-east-1:123456789012:myrepo",` +
+		`"userIdentityARN":"arn:aws:iam::123456789012:user/alice","codecommit":{"references":[` +
+		`{"commit":"1234567890abcdef1234567890abcdef12345678","ref":"refs/heads/master"},` +
+		`{"commit":"abcdef1234567890abcdef1234567890abcdef12","ref":"refs/heads/feature","deleted":true}]}}]}`
+)
+
+var codeCommitNotification = `{"Type":"Notification","MessageId":"msg-1","TopicArn":"` + codeCommitTopic + `","Message":` +
+	strconv.Quote(codeCommitMessage) + `}`
+
+var snsCertificateCount int32
+
+// snsSigner signs SNS messages the way SNS does with a certificate served on its own SNS certificate URL
+type snsSigner struct {
+	key     *rsa.PrivateKey
+	certPEM []byte
+	certURL string
+}
+
+func newSNSSigner(t *testing.T) *snsSigner {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return &snsSigner{
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		certURL: fmt.Sprintf("https://sns.us-east-1.amazonaws.com/SimpleNotificationService-%d.pem", atomic.AddInt32(&snsCertificateCount, 1)),
+	}
+}
+
+// sign returns the JSON of the SNS message with the fields signed with the key of the signer
+func (s *snsSigner) sign(t *testing.T, fields map[string]string) string {
+	message := map[string]string{
+		"MessageId":        "msg-1",
+		"Timestamp":        time.Now().UTC().Format(time.RFC3339),
+		"SignatureVersion": "1",
+		"SigningCertURL":   s.certURL,
+	}
+	for k, v := range fields {
+		message[k] = v
+	}
+	names := []string{"Message", "MessageId", "Subject", "Timestamp", "TopicArn", "Type"}
+	if message["Type"] == webhooks.SNSSubscriptionConfirmation {
+		names = []string{"Message", "MessageId", "SubscribeURL", "Timestamp", "Token", "TopicArn", "Type"}
+	}
+	text := ""
+	for _, name := range names {
+		if value, ok := message[name]; ok {
+			text += name + "\n" + value + "\n"
+		}
+	}
+	hash := sha1.Sum([]byte(text))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA1, hash[:])
+	require.NoError(t, err)
+	message["Signature"] = base64.StdEncoding.EncodeToString(signature)
+	data, err := json.Marshal(message)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// client returns a client which downloads the certificate of the signer from SNS and confirms subscriptions
+func (s *snsSigner) client() *http.Client {
+	return &http.Client{Transport: roundTripper(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host != "sns.us-east-1.amazonaws.com" {
+			return http.DefaultTransport.RoundTrip(req)
+		}
+		body := "<ConfirmSubscriptionResponse/>"
+		if req.URL.String() == s.certURL {
+			body = string(s.certPEM)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body)), Request: req}, nil
+	})}
+}
+
+type roundTripper func(req *http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestCodeCommitPushEvents(t *testing.T) {
+	t.Parallel()
+	events, err := webhooks.CodeCommitPushEvents([]byte(codeCommitNotification))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	event := events[0]
+	assert.Equal(t, gits.KindGitHub, event.Kind)
+	assert.Equal(t, "push", event.Type)
+	assert.Equal(t, "msg-1-0", event.ID)
+	assert.Equal(t, "us-east-1/myrepo", event.Repository)
+	assert.Equal(t, "push", event.Headers["X-GitHub-Event"])
+	payload := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(event.Body), &payload))
+	assert.Equal(t, "refs/heads/master", payload["ref"])
+	assert.Equal(t, "1234567890abcdef1234567890abcdef12345678", payload["after"])
+	repository := payload["repository"].(map[string]interface{})
+	assert.Equal(t, "us-east-1/myrepo", repository["full_name"])
+	assert.Equal(t, "https://git-codecommit.us-east-1.amazonaws.com/v1/repos/myrepo", repository["clone_url"])
+	assert.Equal(t, "alice", payload["sender"].(map[string]interface{})["login"])
+
+	deleted := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(events[1].Body), &deleted))
+	assert.Equal(t, "refs/heads/feature", deleted["ref"])
+	assert.Equal(t, true, deleted["deleted"])
+	assert.Equal(t, "0000000000000000000000000000000000000000", deleted["after"])
+
+	_, err = webhooks.CodeCommitPushEvents([]byte(`{"Type":"SubscriptionConfirmation"}`))
+	assert.Error(t, err)
+	_, err = webhooks.CodeCommitPushEvents([]byte(`{"Type":"Notification","Message":"{\"Records\":[{\"eventSourceARN\":\"arn:aws:s3:::bucket\"}]}"}`))
+	assert.Error(t, err)
+}
+
+func TestRecorderForwardsCodeCommitNotificationsAsPushEvents(t *testing.T) {
+	t.Parallel()
+	store, cleanup := createStore(t, 0)
+	defer cleanup()
+
+	forwarded := []*http.Request{}
+	bodies := []string{}
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		forwarded = append(forwarded, r)
+		bodies = append(bodies, string(data))
+	}))
+	defer hook.Close()
+
+	signer := newSNSSigner(t)
+	recorder := httptest.NewServer(&webhooks.Recorder{
+		Store:         store,
+		ForwardURL:    hook.URL + "/hook",
+		ForwardSecret: "secret",
+		TopicArns:     []string{"arn:aws:sns:us-east-1:123456789012:jx-codecommit-*"},
+		Client:        signer.client(),
+	})
+	defer recorder.Close()
+
+	notification := signer.sign(t, map[string]string{"Type": "Notification", "TopicArn": codeCommitTopic, "Message": codeCommitMessage})
+	resp := postWebhook(t, recorder.URL+"/hook", map[string]string{
+		"X-Amz-Sns-Message-Type": "Notification",
+		"X-Amz-Sns-Message-Id":   "msg-1",
+	}, notification)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, forwarded, 2)
+	assert.Equal(t, "push", forwarded[0].Header.Get("X-GitHub-Event"))
+	assert.Equal(t, "", forwarded[0].Header.Get("X-Amz-Sns-Message-Type"))
+	assert.True(t, strings.HasPrefix(forwarded[0].Header.Get("X-Hub-Signature"), "sha1="))
+	assert.Contains(t, bodies[0], `"ref":"refs/heads/master"`)
+
+	events, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, gits.KindCodeCommit, events[0].Kind)
+	assert.Equal(t, notification, events[0].Body)
+
+	confirmation := signer.sign(t, map[string]string{
+		"Type":         "SubscriptionConfirmation",
+		"TopicArn":     codeCommitTopic,
+		"Message":      "You have chosen to subscribe to the topic",
+		"Token":        "token",
+		"SubscribeURL": "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription&Token=token",
+	})
+	resp = postWebhook(t, recorder.URL+"/hook", map[string]string{"X-Amz-Sns-Message-Type": "SubscriptionConfirmation"}, confirmation)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRecorderRejectsForgedAndUnsignedSNSMessages(t *testing.T) {
+	t.Parallel()
+	store, cleanup := createStore(t, 0)
+	defer cleanup()
+
+	forwarded := 0
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded++
+	}))
+	defer hook.Close()
+
+	signer := newSNSSigner(t)
+	recorder := httptest.NewServer(&webhooks.Recorder{
+		Store:         store,
+		ForwardURL:    hook.URL + "/hook",
+		ForwardSecret: "secret",
+		TopicArns:     []string{codeCommitTopic},
+		Client:        signer.client(),
+	})
+	defer recorder.Close()
+
+	fields := map[string]string{"Type": "Notification", "TopicArn": codeCommitTopic, "Message": codeCommitMessage}
+	forger := newSNSSigner(t)
+	forged := forger.sign(t, fields)
+	// the forger signs with their own key but can only point at the certificate SNS serves
+	forged = strings.Replace(forged, forger.certURL, signer.certURL, 1)
+	tampered := strings.Replace(signer.sign(t, fields), "refs/heads/master", "refs/heads/release", 1)
+	otherTopic := strings.Replace(codeCommitTopic, "myrepo", "other", 1)
+	notAllowed := signer.sign(t, map[string]string{"Type": "Notification", "TopicArn": otherTopic, "Message": codeCommitMessage})
+	offSNS := strings.Replace(signer.sign(t, fields), "sns.us-east-1.amazonaws.com", "sns.example.com", 1)
+	oldFields := map[string]string{"Timestamp": time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)}
+	for k, v := range fields {
+		oldFields[k] = v
+	}
+	replayed := signer.sign(t, oldFields)
+	subscription := forger.sign(t, map[string]string{
+		"Type":         "SubscriptionConfirmation",
+		"TopicArn":     "arn:aws:sns:us-east-1:999999999999:jx-codecommit-myrepo",
+		"SubscribeURL": "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription&Token=token",
+	})
+
+	for name, body := range map[string]string{
+		"unsigned":     codeCommitNotification,
+		"forged":       forged,
+		"tampered":     tampered,
+		"not allowed":  notAllowed,
+		"off SNS":      offSNS,
+		"replayed":     replayed,
+		"subscription": subscription,
+	} {
+		messageType := "Notification"
+		if name == "subscription" {
+			messageType = "SubscriptionConfirmation"
+		}
+		resp := postWebhook(t, recorder.URL+"/hook", map[string]string{"X-Amz-Sns-Message-Type": messageType}, body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, name)
+	}
+	assert.Equal(t, 0, forwarded)
+}
+
+func TestFileStoreRemovesOldestEvents(t *testing.T) {
+	t.Parallel()
+	store, cleanup := createStore(t, 2)
+	defer cleanup()
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		err := store.Save(&webhooks.Event{Kind: gits.KindGitHub, Body: pushPayload, Received: now.Add(time.Duration(i) * time.Second)})
+		require.NoError(t, err)
+	}
+	events, err := store.List()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(events))
+	assert.Equal(t, now.Add(2*time.Second).Unix(), events[0].Received.Unix(), "the most recent event should be first")
+	assert.Equal(t, now.Add(time.Second).Unix(), events[1].Received.Unix())
+
+	event, err := store.Get("../../etc/passwd")
+	assert.NoError(t, err)
+	assert.Nil(t, event)
+}
@@ -0,0 +1,74 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+)
+
+const (
+	gitHubSignatureHeader = "X-Hub-Signature"
+	giteaSignatureHeader  = "X-Gitea-Signature"
+	gitlabTokenHeader     = "X-Gitlab-Token"
+)
+
+// Send posts the event to the webhook endpoint with its original headers. If a secret is given the event is signed
+// with it the way its git provider would so that the endpoint accepts it, otherwise the original signature is sent
+func Send(client *http.Client, url string, event *Event, secret string) (*http.Response, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	body := []byte(event.Body)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range event.Headers {
+		if !ignoredHeaders[http.CanonicalHeaderKey(name)] {
+			req.Header.Set(name, value)
+		}
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if secret != "" {
+		err = Sign(req, event.Kind, body, secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return client.Do(req)
+}
+
+// Sign adds the signature or token headers which the kind of git provider uses to authenticate webhooks
+func Sign(req *http.Request, kind string, body []byte, secret string) error {
+	switch kind {
+	case gits.KindGitHub:
+		req.Header.Set(gitHubSignatureHeader, "sha1="+hmacHex(sha1.New, body, secret))
+	case gits.KindGitea:
+		req.Header.Set(giteaSignatureHeader, hmacHex(sha256.New, body, secret))
+		// lets also sign the GitHub way as gitea sends the GitHub headers too
+		req.Header.Set(gitHubSignatureHeader, "sha1="+hmacHex(sha1.New, body, secret))
+	case gits.KindBitBucketServer:
+		req.Header.Set(gitHubSignatureHeader, "sha256="+hmacHex(sha256.New, body, secret))
+	case gits.KindGitlab:
+		req.Header.Set(gitlabTokenHeader, secret)
+	default:
+		return fmt.Errorf("signing %s webhooks is not supported", kind)
+	}
+	return nil
+}
+
+func hmacHex(h func() hash.Hash, body []byte, secret string) string {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,338 @@
+package webhooks
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+)
+
+const (
+	// SNSSubscriptionConfirmation the type of the message SNS sends to confirm a subscription
+	SNSSubscriptionConfirmation = "SubscriptionConfirmation"
+	// SNSNotification the type of the messages SNS delivers to confirmed subscriptions
+	SNSNotification = "Notification"
+
+	// snsMaxAge how old an SNS message can be before it is rejected as a replay
+	snsMaxAge = time.Hour
+)
+
+var (
+	snsHostRegex = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+	snsCertificates     = map[string]*x509.Certificate{}
+	snsCertificatesLock sync.Mutex
+)
+
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	Token            string `json:"Token"`
+	SubscribeURL     string `json:"SubscribeURL"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+}
+
+// codeCommitRecord a record of the message a CodeCommit repository trigger publishes when references change
+type codeCommitRecord struct {
+	EventSourceARN  string `json:"eventSourceARN"`
+	UserIdentityARN string `json:"userIdentityARN"`
+	CodeCommit      struct {
+		References []struct {
+			Commit  string `json:"commit"`
+			Ref     string `json:"ref"`
+			Created bool   `json:"created"`
+			Deleted bool   `json:"deleted"`
+		} `json:"references"`
+	} `json:"codecommit"`
+}
+
+// VerifySNSMessage returns an error unless the SNS message was published to one of the topics and is signed by SNS.
+// A topic ending with * matches the topics starting with the rest of it such as
+// arn:aws:sns:us-east-1:123456789012:jx-codecommit-*
+func VerifySNSMessage(client *http.Client, body []byte, topicArns []string) error {
+	message := snsMessage{}
+	err := json.Unmarshal(body, &message)
+	if err != nil {
+		return fmt.Errorf("failed to parse the SNS message: %s", err)
+	}
+	if !snsTopicAllowed(message.TopicArn, topicArns) {
+		return fmt.Errorf("the SNS topic %s is not one of the allowed topics", message.TopicArn)
+	}
+	timestamp, err := time.Parse(time.RFC3339, message.Timestamp)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %s of the SNS message: %s", message.Timestamp, err)
+	}
+	if time.Since(timestamp) > snsMaxAge {
+		return fmt.Errorf("the SNS message was sent at %s which is more than %s ago", message.Timestamp, snsMaxAge)
+	}
+	var algorithm x509.SignatureAlgorithm
+	switch message.SignatureVersion {
+	case "1":
+		algorithm = x509.SHA1WithRSA
+	case "2":
+		algorithm = x509.SHA256WithRSA
+	default:
+		return fmt.Errorf("unsupported SNS signature version %s", message.SignatureVersion)
+	}
+	signature, err := base64.StdEncoding.DecodeString(message.Signature)
+	if err != nil || len(signature) == 0 {
+		return fmt.Errorf("the SNS message has no valid signature")
+	}
+	text, err := snsStringToSign(&message)
+	if err != nil {
+		return err
+	}
+	cert, err := snsSigningCertificate(client, &message)
+	if err != nil {
+		return err
+	}
+	err = cert.CheckSignature(algorithm, []byte(text), signature)
+	if err != nil {
+		return fmt.Errorf("the signature of the SNS message is invalid: %s", err)
+	}
+	return nil
+}
+
+// snsTopicAllowed returns true if the topic matches one of the allowed topics
+func snsTopicAllowed(topicArn string, allowed []string) bool {
+	if topicArn == "" {
+		return false
+	}
+	for _, a := range allowed {
+		if strings.HasSuffix(a, "*") {
+			if strings.HasPrefix(topicArn, strings.TrimSuffix(a, "*")) {
+				return true
+			}
+		} else if topicArn == a {
+			return true
+		}
+	}
+	return false
+}
+
+// snsStringToSign returns the text SNS signs which is made of the name and value of some of the fields of the message
+func snsStringToSign(message *snsMessage) (string, error) {
+	var fields [][]string
+	switch message.Type {
+	case SNSNotification:
+		fields = [][]string{{"Message", message.Message}, {"MessageId", message.MessageID}}
+		if message.Subject != "" {
+			fields = append(fields, []string{"Subject", message.Subject})
+		}
+		fields = append(fields, []string{"Timestamp", message.Timestamp}, []string{"TopicArn", message.TopicArn}, []string{"Type", message.Type})
+	case SNSSubscriptionConfirmation:
+		fields = [][]string{{"Message", message.Message}, {"MessageId", message.MessageID}, {"SubscribeURL", message.SubscribeURL},
+			{"Timestamp", message.Timestamp}, {"Token", message.Token}, {"TopicArn", message.TopicArn}, {"Type", message.Type}}
+	default:
+		return "", fmt.Errorf("unsupported SNS message type %s", message.Type)
+	}
+	text := ""
+	for _, field := range fields {
+		text += field[0] + "\n" + field[1] + "\n"
+	}
+	return text, nil
+}
+
+// snsSigningCertificate returns the certificate the message was signed with which must be downloaded from SNS in the
+// region of the topic
+func snsSigningCertificate(client *http.Client, message *snsMessage) (*x509.Certificate, error) {
+	// e.g. arn:aws:sns:us-east-1:123456789012:mytopic
+	arn := strings.Split(message.TopicArn, ":")
+	if len(arn) != 6 || arn[2] != "sns" {
+		return nil, fmt.Errorf("the topic %s is not an SNS topic", message.TopicArn)
+	}
+	host := "sns." + arn[3] + ".amazonaws.com"
+	if strings.HasPrefix(arn[3], "cn-") {
+		host += ".cn"
+	}
+	u, err := url.Parse(message.SigningCertURL)
+	if err != nil || u.Scheme != "https" || u.Host != host || !strings.HasSuffix(u.Path, ".pem") {
+		return nil, fmt.Errorf("the SigningCertURL %s is not an SNS certificate of region %s", message.SigningCertURL, arn[3])
+	}
+	certURL := u.String()
+
+	snsCertificatesLock.Lock()
+	defer snsCertificatesLock.Unlock()
+	cert := snsCertificates[certURL]
+	if cert != nil {
+		return cert, nil
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(certURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the SNS certificate %s: %s", certURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download the SNS certificate %s: %s", certURL, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("the SNS certificate %s is not PEM encoded", certURL)
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the SNS certificate %s: %s", certURL, err)
+	}
+	snsCertificates[certURL] = cert
+	return cert, nil
+}
+
+// ConfirmSNSSubscription confirms the SNS subscription of a SubscriptionConfirmation message so that SNS starts
+// delivering the notifications of the topic, such as the events of CodeCommit repository triggers
+func ConfirmSNSSubscription(client *http.Client, body []byte) error {
+	message := snsMessage{}
+	err := json.Unmarshal(body, &message)
+	if err != nil {
+		return fmt.Errorf("failed to parse the SNS message: %s", err)
+	}
+	if message.Type != SNSSubscriptionConfirmation {
+		return fmt.Errorf("the SNS message is a %s rather than a %s", message.Type, SNSSubscriptionConfirmation)
+	}
+	// lets only call back SNS so that the recorder cannot be used to make arbitrary requests
+	u, err := url.Parse(message.SubscribeURL)
+	if err != nil || u.Scheme != "https" || !snsHostRegex.MatchString(u.Host) {
+		return fmt.Errorf("the SubscribeURL %s is not an SNS URL", message.SubscribeURL)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to confirm the subscription to %s: %s", message.TopicArn, resp.Status)
+	}
+	return nil
+}
+
+// CodeCommitPushEvents translates the SNS notification of a CodeCommit repository trigger into the GitHub push
+// events which Prow and Jenkins understand, one for each changed reference. Repository triggers are only published
+// for pushes so there are no Pull Request events
+func CodeCommitPushEvents(body []byte) ([]*Event, error) {
+	notification := snsMessage{}
+	err := json.Unmarshal(body, &notification)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the SNS message: %s", err)
+	}
+	if notification.Type != SNSNotification {
+		return nil, fmt.Errorf("the SNS message is a %s rather than a %s", notification.Type, SNSNotification)
+	}
+	message := struct {
+		Records []codeCommitRecord `json:"Records"`
+	}{}
+	err = json.Unmarshal([]byte(notification.Message), &message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the CodeCommit records of the SNS message: %s", err)
+	}
+	events := []*Event{}
+	for _, record := range message.Records {
+		// e.g. arn:aws:codecommit:us-east-1:123456789012:myrepo
+		arn := strings.Split(record.EventSourceARN, ":")
+		if len(arn) != 6 || arn[2] != "codecommit" {
+			return nil, fmt.Errorf("the event source %s is not a CodeCommit repository", record.EventSourceARN)
+		}
+		region, name := arn[3], arn[5]
+		sender := record.UserIdentityARN[strings.LastIndex(record.UserIdentityARN, "/")+1:]
+		if sender == "" {
+			sender = region
+		}
+		host := "git-codecommit." + region + ".amazonaws.com"
+		if strings.HasPrefix(region, "cn-") {
+			host += ".cn"
+		}
+		repository := map[string]interface{}{
+			"name":      name,
+			"full_name": region + "/" + name,
+			"owner":     map[string]interface{}{"login": region, "username": region},
+			"html_url":  fmt.Sprintf("https://console.aws.amazon.com/codesuite/codecommit/repositories/%s/browse?region=%s", name, region),
+			"clone_url": fmt.Sprintf("https://%s/v1/repos/%s", host, name),
+		}
+		for i, ref := range record.CodeCommit.References {
+			after := ref.Commit
+			if ref.Deleted {
+				after = emptySha
+			}
+			payload := map[string]interface{}{
+				"ref":        ref.Ref,
+				"before":     emptySha,
+				"after":      after,
+				"created":    ref.Created,
+				"deleted":    ref.Deleted,
+				"repository": repository,
+				"pusher":     map[string]interface{}{"name": sender},
+				"sender":     map[string]interface{}{"login": sender, "username": sender},
+			}
+			if !ref.Deleted {
+				commit := map[string]interface{}{"id": ref.Commit, "message": ""}
+				payload["head_commit"] = commit
+				payload["commits"] = []interface{}{commit}
+			}
+			data, err := json.Marshal(payload)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, &Event{
+				ID:         fmt.Sprintf("%s-%d", notification.MessageID, i),
+				Kind:       gits.KindGitHub,
+				Type:       "push",
+				Repository: region + "/" + name,
+				Headers: map[string]string{
+					"Content-Type":      "application/json",
+					"X-GitHub-Event":    "push",
+					"X-GitHub-Delivery": fmt.Sprintf("%s-%d", notification.MessageID, i),
+				},
+				Body:     string(data),
+				Received: time.Now(),
+			})
+		}
+	}
+	return events, nil
+}
+
+// snsRepository returns the 'region/name' of the CodeCommit repository of the records of an SNS notification
+func snsRepository(payload map[string]interface{}) string {
+	text := jsonString(payload, "Message")
+	if text == "" {
+		return ""
+	}
+	message := struct {
+		Records []struct {
+			EventSourceARN string `json:"eventSourceARN"`
+		} `json:"Records"`
+	}{}
+	err := json.Unmarshal([]byte(text), &message)
+	if err != nil || len(message.Records) == 0 {
+		return ""
+	}
+	// e.g. arn:aws:codecommit:us-east-1:123456789012:myrepo
+	arn := strings.Split(message.Records[0].EventSourceARN, ":")
+	if len(arn) != 6 || arn[2] != "codecommit" {
+		return ""
+	}
+	return arn[3] + "/" + arn[5]
+}
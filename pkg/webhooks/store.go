@@ -0,0 +1,136 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// DefaultMaxEvents the number of events a store keeps by default
+const DefaultMaxEvents = 200
+
+var unsafeIDCharacters = regexp.MustCompile("[^a-zA-Z0-9_.-]")
+
+// FileStore stores webhook events as JSON files in a directory, removing the oldest events once it holds MaxEvents
+type FileStore struct {
+	Dir       string
+	MaxEvents int
+
+	lock sync.Mutex
+}
+
+// NewFileStore creates a store of webhook events in the directory
+func NewFileStore(dir string, maxEvents int) (*FileStore, error) {
+	if maxEvents <= 0 {
+		maxEvents = DefaultMaxEvents
+	}
+	err := os.MkdirAll(dir, util.DefaultWritePermissions)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the webhook event directory %s: %s", dir, err)
+	}
+	return &FileStore{
+		Dir:       dir,
+		MaxEvents: maxEvents,
+	}, nil
+}
+
+// Save stores the event generating an ID if it has none
+func (s *FileStore) Save(event *Event) error {
+	if event.ID == "" {
+		event.ID = event.Received.UTC().Format("20060102-150405.000000000")
+	}
+	event.ID = unsafeIDCharacters.ReplaceAllString(event.ID, "-")
+	data, err := json.MarshalIndent(event, "", "  ")
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err = ioutil.WriteFile(s.fileName(event.ID), data, util.DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	return s.prune()
+}
+
+// Get returns the event with the ID or nil if there is no such event
+func (s *FileStore) Get(id string) (*Event, error) {
+	if unsafeIDCharacters.MatchString(id) {
+		return nil, nil
+	}
+	exists, err := util.FileExists(s.fileName(id))
+	if err != nil || !exists {
+		return nil, err
+	}
+	return LoadEvent(s.fileName(id))
+}
+
+// List returns the events most recent first
+func (s *FileStore) List() ([]*Event, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.list()
+}
+
+func (s *FileStore) list() ([]*Event, error) {
+	files, err := ioutil.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	events := []*Event{}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		event, err := LoadEvent(filepath.Join(s.Dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Received.After(events[j].Received)
+	})
+	return events, nil
+}
+
+// prune removes the oldest events beyond MaxEvents
+func (s *FileStore) prune() error {
+	events, err := s.list()
+	if err != nil {
+		return err
+	}
+	for i := s.MaxEvents; i < len(events); i++ {
+		err = os.Remove(s.fileName(events[i].ID))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *FileStore) fileName(id string) string {
+	return filepath.Join(s.Dir, id+".json")
+}
+
+// LoadEvent loads a webhook event from a JSON file
+func LoadEvent(fileName string) (*Event, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load file %s: %s", fileName, err)
+	}
+	event := &Event{}
+	err = json.Unmarshal(data, event)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal the webhook event in %s: %s", fileName, err)
+	}
+	return event, nil
+}
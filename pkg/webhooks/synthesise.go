@@ -0,0 +1,273 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+)
+
+const (
+	// PushEvent a push of commits to a branch
+	PushEvent = "push"
+	// PullRequestEvent a Pull Request being opened
+	PullRequestEvent = "pull-request"
+	// CommentEvent a comment on a Pull Request such as '/test this'
+	CommentEvent = "comment"
+
+	emptySha = "0000000000000000000000000000000000000000"
+)
+
+// EventTypes the types of event which can be synthesised
+var EventTypes = []string{PushEvent, PullRequestEvent, CommentEvent}
+
+// EventArguments describes an event to synthesise
+type EventArguments struct {
+	// Kind the kind of git provider to synthesise the event of
+	Kind string
+	// Type one of EventTypes
+	Type       string
+	Repository *gits.GitRepositoryInfo
+	// Branch the branch pushed to or the source branch of the Pull Request
+	Branch string
+	// BaseBranch the target branch of the Pull Request
+	BaseBranch string
+	Sha        string
+	// Number the Pull Request number
+	Number  int
+	Title   string
+	Comment string
+	Sender  string
+}
+
+// Synthesise creates an event in the format the kind of git provider would send it
+func Synthesise(args *EventArguments) (*Event, error) {
+	if args.Repository == nil {
+		return nil, fmt.Errorf("no repository given for the %s event", args.Type)
+	}
+	if args.Branch == "" {
+		args.Branch = "master"
+	}
+	if args.BaseBranch == "" {
+		args.BaseBranch = "master"
+	}
+	if args.Sender == "" {
+		args.Sender = args.Repository.Organisation
+	}
+	if args.Title == "" {
+		args.Title = fmt.Sprintf("Synthesised %s event", args.Type)
+	}
+	if args.Number <= 0 && args.Type != PushEvent {
+		return nil, fmt.Errorf("no Pull Request number given for the %s event", args.Type)
+	}
+
+	var eventType string
+	var payload map[string]interface{}
+	var err error
+	headers := map[string]string{}
+	switch args.Kind {
+	case gits.KindGitHub, gits.KindGitea:
+		eventType, payload, err = gitHubPayload(args)
+		headers["X-GitHub-Event"] = eventType
+		if args.Kind == gits.KindGitea {
+			headers["X-Gitea-Event"] = eventType
+		}
+	case gits.KindGitlab:
+		eventType, payload, err = gitlabPayload(args)
+		headers["X-Gitlab-Event"] = eventType
+	case gits.KindBitBucketServer:
+		eventType, payload, err = bitbucketServerPayload(args)
+		headers["X-Event-Key"] = eventType
+	default:
+		return nil, fmt.Errorf("synthesising %s webhook events is not supported", args.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	headers["Content-Type"] = "application/json"
+	return &Event{
+		Kind:       args.Kind,
+		Type:       eventType,
+		Repository: args.Repository.Organisation + "/" + args.Repository.Name,
+		Headers:    headers,
+		Body:       string(body),
+		Received:   time.Now(),
+	}, nil
+}
+
+func unknownEventType(eventType string) error {
+	return fmt.Errorf("unknown event type %s. Valid types are %s", eventType, strings.Join(EventTypes, ", "))
+}
+
+func gitHubPayload(args *EventArguments) (string, map[string]interface{}, error) {
+	info := args.Repository
+	sender := map[string]interface{}{"login": args.Sender, "username": args.Sender}
+	repository := map[string]interface{}{
+		"name":      info.Name,
+		"full_name": info.Organisation + "/" + info.Name,
+		"owner":     map[string]interface{}{"login": info.Organisation, "username": info.Organisation},
+		"html_url":  info.HttpsURL(),
+		"clone_url": info.HttpCloneURL(),
+	}
+	pullRequest := map[string]interface{}{
+		"number":   args.Number,
+		"state":    "open",
+		"title":    args.Title,
+		"html_url": info.HttpsURL() + "/pull/" + fmt.Sprint(args.Number),
+		"user":     sender,
+		"head":     map[string]interface{}{"ref": args.Branch, "sha": args.Sha, "repo": repository},
+		"base":     map[string]interface{}{"ref": args.BaseBranch, "repo": repository},
+	}
+	switch args.Type {
+	case PushEvent:
+		return "push", map[string]interface{}{
+			"ref":         "refs/heads/" + args.Branch,
+			"before":      emptySha,
+			"after":       args.Sha,
+			"repository":  repository,
+			"pusher":      map[string]interface{}{"name": args.Sender},
+			"sender":      sender,
+			"head_commit": map[string]interface{}{"id": args.Sha, "message": args.Title},
+			"commits":     []interface{}{map[string]interface{}{"id": args.Sha, "message": args.Title}},
+		}, nil
+	case PullRequestEvent:
+		return "pull_request", map[string]interface{}{
+			"action":       "opened",
+			"number":       args.Number,
+			"pull_request": pullRequest,
+			"repository":   repository,
+			"sender":       sender,
+		}, nil
+	case CommentEvent:
+		return "issue_comment", map[string]interface{}{
+			"action": "created",
+			"issue": map[string]interface{}{
+				"number":       args.Number,
+				"state":        "open",
+				"title":        args.Title,
+				"user":         sender,
+				"pull_request": map[string]interface{}{"html_url": pullRequest["html_url"]},
+			},
+			"is_pull":    true,
+			"comment":    map[string]interface{}{"body": args.Comment, "user": sender},
+			"repository": repository,
+			"sender":     sender,
+		}, nil
+	}
+	return "", nil, unknownEventType(args.Type)
+}
+
+func gitlabPayload(args *EventArguments) (string, map[string]interface{}, error) {
+	info := args.Repository
+	project := map[string]interface{}{
+		"name":                info.Name,
+		"namespace":           info.Organisation,
+		"path_with_namespace": info.Organisation + "/" + info.Name,
+		"web_url":             info.HttpsURL(),
+		"git_http_url":        info.HttpCloneURL(),
+	}
+	user := map[string]interface{}{"username": args.Sender}
+	mergeRequest := map[string]interface{}{
+		"iid":           args.Number,
+		"title":         args.Title,
+		"state":         "opened",
+		"source_branch": args.Branch,
+		"target_branch": args.BaseBranch,
+		"last_commit":   map[string]interface{}{"id": args.Sha},
+	}
+	switch args.Type {
+	case PushEvent:
+		return "Push Hook", map[string]interface{}{
+			"object_kind":   "push",
+			"ref":           "refs/heads/" + args.Branch,
+			"before":        emptySha,
+			"after":         args.Sha,
+			"checkout_sha":  args.Sha,
+			"user_username": args.Sender,
+			"project":       project,
+		}, nil
+	case PullRequestEvent:
+		mergeRequest["action"] = "open"
+		return "Merge Request Hook", map[string]interface{}{
+			"object_kind":       "merge_request",
+			"user":              user,
+			"project":           project,
+			"object_attributes": mergeRequest,
+		}, nil
+	case CommentEvent:
+		return "Note Hook", map[string]interface{}{
+			"object_kind":       "note",
+			"user":              user,
+			"project":           project,
+			"object_attributes": map[string]interface{}{"note": args.Comment, "noteable_type": "MergeRequest"},
+			"merge_request":     mergeRequest,
+		}, nil
+	}
+	return "", nil, unknownEventType(args.Type)
+}
+
+func bitbucketServerPayload(args *EventArguments) (string, map[string]interface{}, error) {
+	info := args.Repository
+	repository := map[string]interface{}{
+		"slug":    info.Name,
+		"name":    info.Name,
+		"project": map[string]interface{}{"key": strings.ToUpper(info.Organisation)},
+	}
+	actor := map[string]interface{}{"name": args.Sender}
+	date := time.Now().Format("2006-01-02T15:04:05-0700")
+	pullRequest := map[string]interface{}{
+		"id":     args.Number,
+		"title":  args.Title,
+		"state":  "OPEN",
+		"author": map[string]interface{}{"user": actor},
+		"fromRef": map[string]interface{}{
+			"id":           "refs/heads/" + args.Branch,
+			"displayId":    args.Branch,
+			"latestCommit": args.Sha,
+			"repository":   repository,
+		},
+		"toRef": map[string]interface{}{
+			"id":         "refs/heads/" + args.BaseBranch,
+			"displayId":  args.BaseBranch,
+			"repository": repository,
+		},
+	}
+	switch args.Type {
+	case PushEvent:
+		return "repo:refs_changed", map[string]interface{}{
+			"eventKey":   "repo:refs_changed",
+			"date":       date,
+			"actor":      actor,
+			"repository": repository,
+			"changes": []interface{}{map[string]interface{}{
+				"ref":      map[string]interface{}{"id": "refs/heads/" + args.Branch, "displayId": args.Branch, "type": "BRANCH"},
+				"refId":    "refs/heads/" + args.Branch,
+				"fromHash": emptySha,
+				"toHash":   args.Sha,
+				"type":     "UPDATE",
+			}},
+		}, nil
+	case PullRequestEvent:
+		return "pr:opened", map[string]interface{}{
+			"eventKey":    "pr:opened",
+			"date":        date,
+			"actor":       actor,
+			"pullRequest": pullRequest,
+		}, nil
+	case CommentEvent:
+		return "pr:comment:added", map[string]interface{}{
+			"eventKey":    "pr:comment:added",
+			"date":        date,
+			"actor":       actor,
+			"pullRequest": pullRequest,
+			"comment":     map[string]interface{}{"text": args.Comment, "author": actor},
+		}, nil
+	}
+	return "", nil, unknownEventType(args.Type)
+}
@@ -0,0 +1,93 @@
+package webhooks_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynthesiseEvents(t *testing.T) {
+	t.Parallel()
+	info := &gits.GitRepositoryInfo{Host: "github.com", Organisation: "myorg", Name: "myrepo"}
+	testCases := []struct {
+		kind      string
+		eventType string
+		expected  string
+	}{
+		{gits.KindGitHub, webhooks.PushEvent, "push"},
+		{gits.KindGitHub, webhooks.PullRequestEvent, "pull_request"},
+		{gits.KindGitea, webhooks.CommentEvent, "issue_comment"},
+		{gits.KindGitlab, webhooks.PullRequestEvent, "Merge Request Hook"},
+		{gits.KindBitBucketServer, webhooks.CommentEvent, "pr:comment:added"},
+	}
+	for _, tc := range testCases {
+		event, err := webhooks.Synthesise(&webhooks.EventArguments{
+			Kind:       tc.kind,
+			Type:       tc.eventType,
+			Repository: info,
+			Sha:        "0123456789abcdef",
+			Number:     7,
+			Comment:    "/test this",
+		})
+		require.NoError(t, err, "%s %s", tc.kind, tc.eventType)
+		assert.Equal(t, tc.expected, event.Type)
+		assert.Equal(t, "myorg/myrepo", event.Repository)
+
+		// the recorder should recognise the events it could have received
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		for k, v := range event.Headers {
+			req.Header.Set(k, v)
+		}
+		recorded := webhooks.NewEvent(req, []byte(event.Body))
+		assert.Equal(t, tc.kind, recorded.Kind)
+		assert.Equal(t, tc.expected, recorded.Type)
+	}
+
+	_, err := webhooks.Synthesise(&webhooks.EventArguments{Kind: gits.KindGitHub, Type: webhooks.CommentEvent, Repository: info})
+	assert.Error(t, err, "comments need a Pull Request number")
+	_, err = webhooks.Synthesise(&webhooks.EventArguments{Kind: gits.KindBitBucketCloud, Type: webhooks.PushEvent, Repository: info})
+	assert.Error(t, err)
+}
+
+func TestSendSignsGitHubEvents(t *testing.T) {
+	t.Parallel()
+	secret := "my-hmac-secret"
+	var body []byte
+	var signature string
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		signature = r.Header.Get("X-Hub-Signature")
+	}))
+	defer hook.Close()
+
+	event, err := webhooks.Synthesise(&webhooks.EventArguments{
+		Kind:       gits.KindGitHub,
+		Type:       webhooks.PushEvent,
+		Repository: &gits.GitRepositoryInfo{Host: "github.com", Organisation: "myorg", Name: "myrepo"},
+		Branch:     "feature",
+		Sha:        "0123456789abcdef",
+	})
+	require.NoError(t, err)
+	resp, err := webhooks.Send(nil, hook.URL, event, secret)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	assert.Equal(t, "sha1="+hex.EncodeToString(mac.Sum(nil)), signature)
+
+	payload := map[string]interface{}{}
+	err = json.Unmarshal(body, &payload)
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/feature", payload["ref"])
+}